@@ -0,0 +1,809 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/chienchuanw/asset-manager/internal/api"
+	"github.com/chienchuanw/asset-manager/internal/cache"
+	"github.com/chienchuanw/asset-manager/internal/client"
+	"github.com/chienchuanw/asset-manager/internal/db"
+	discordbot "github.com/chienchuanw/asset-manager/internal/discord"
+	"github.com/chienchuanw/asset-manager/internal/external"
+	"github.com/chienchuanw/asset-manager/internal/i18n"
+	"github.com/chienchuanw/asset-manager/internal/middleware"
+	"github.com/chienchuanw/asset-manager/internal/repository"
+	"github.com/chienchuanw/asset-manager/internal/scheduler"
+	"github.com/chienchuanw/asset-manager/internal/service"
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+	"github.com/joho/godotenv"
+	"github.com/redis/go-redis/v9"
+	swaggerFiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
+
+	_ "github.com/chienchuanw/asset-manager/docs" // swagger docs
+)
+
+// @title Asset Manager API
+// @version 1.0
+// @description Personal finance system API for tracking investment portfolios, cash flows, subscriptions, and financial analytics.
+// @host localhost:8080
+// @BasePath /
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+func main() {
+	// 載入環境變數
+	if err := godotenv.Load(".env.local"); err != nil {
+		log.Printf("Warning: .env.local file not found: %v", err)
+	}
+
+	// 初始化 i18n
+	if err := i18n.Init(); err != nil {
+		log.Fatalf("Failed to initialize i18n: %v", err)
+	}
+	log.Println("i18n initialized successfully")
+
+	// 初始化資料庫連線
+	database, err := db.InitDB()
+	if err != nil {
+		log.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer database.Close()
+
+	// 建立可取消的 context，供 Discord bot 清理 goroutine 使用
+	botCtx, botCancel := context.WithCancel(context.Background())
+	defer botCancel()
+
+	// 初始化 Repository
+	transactionRepo := repository.NewTransactionRepository(database)
+	exchangeRateRepo := repository.NewExchangeRateRepository(database)
+	realizedProfitRepo := repository.NewRealizedProfitRepository(database)
+	assetSnapshotRepo := repository.NewAssetSnapshotRepository(database)
+	settingsRepo := repository.NewSettingsRepository(database)
+	cashFlowRepo := repository.NewCashFlowRepository(database)
+	categoryRepo := repository.NewCategoryRepository(database)
+	subscriptionRepo := repository.NewSubscriptionRepository(database)
+	installmentRepo := repository.NewInstallmentRepository(database)
+	bankAccountRepo := repository.NewBankAccountRepository(database)
+	creditCardRepo := repository.NewCreditCardRepository(database)
+	creditCardGroupRepo := repository.NewCreditCardGroupRepository(database)
+	loanRepo := repository.NewLoanRepository(database)
+	recurringTemplateRepo := repository.NewRecurringTemplateRepository(database)
+	manualAssetRepo := repository.NewManualAssetRepository(database)
+	reconciliationRepo := repository.NewBankAccountReconciliationRepository(database)
+	creditCardStatementRepo := repository.NewCreditCardStatementRepository(database)
+	creditCardRewardRuleRepo := repository.NewCreditCardRewardRuleRepository(database)
+	billingLedgerRepo := repository.NewBillingLedgerRepository(database)
+	brokerageAccountRepo := repository.NewBrokerageAccountRepository(database)
+	custodyAccountRepo := repository.NewCustodyAccountRepository(database)
+	optionContractRepo := repository.NewOptionContractRepository(database)
+	lastKnownPriceRepo := repository.NewLastKnownPriceRepository(database)
+	instrumentRepo := repository.NewInstrumentRepository(database)
+	feeProfileRepo := repository.NewFeeProfileRepository(database)
+	dataArchiveRepo := repository.NewDataArchiveRepository(database)
+
+	// PerformanceSnapshotRepository 需要 sqlx.DB
+	dbx := sqlx.NewDb(database, "postgres")
+	performanceSnapshotRepo := repository.NewPerformanceSnapshotRepository(dbx)
+	schedulerLogRepo := repository.NewSchedulerLogRepository(dbx)
+	cashFlowReportLogRepo := repository.NewCashFlowReportLogRepository(database)
+
+	authService := service.NewAuthService()
+
+	// 初始化 Price Provider（真實 API 或 Mock），Redis 是否可用都共用同一組 provider 與速率限制器
+	var basePriceService service.PriceService
+	var priceProviderRegistry service.PriceProviderRegistry
+
+	finmindAPIKey := os.Getenv("FINMIND_API_KEY")
+	coingeckoAPIKey := os.Getenv("COINGECKO_API_KEY")
+	alphaVantageAPIKey := os.Getenv("ALPHA_VANTAGE_API_KEY")
+
+	if finmindAPIKey != "" && coingeckoAPIKey != "" && alphaVantageAPIKey != "" {
+		// 使用真實 API
+		priceProviderRegistry = service.NewDefaultPriceProviderRegistry(finmindAPIKey, coingeckoAPIKey, alphaVantageAPIKey, lastKnownPriceRepo, instrumentRepo)
+		basePriceService = service.NewProviderChainPriceService(priceProviderRegistry)
+		log.Println("Using real price API (FinMind + Yahoo Finance + Alpha Vantage + CoinGecko)")
+	} else {
+		// 使用 Mock Service
+		basePriceService = service.NewMockPriceService()
+		log.Println("Warning: API keys not found. Using mock price service.")
+	}
+
+	// 初始化 Redis Cache
+	redisAddr := os.Getenv("REDIS_ADDR")
+	if redisAddr == "" {
+		redisAddr = "localhost:6379"
+	}
+	redisPassword := os.Getenv("REDIS_PASSWORD")
+	redisDB, _ := strconv.Atoi(os.Getenv("REDIS_DB"))
+
+	// 啟用排程器時預設由背景排程更新價格，持倉查詢只讀取快取（PRICE_REFRESH_ENABLED=false 可改回即時查詢）
+	schedulerEnabled := os.Getenv("SNAPSHOT_SCHEDULER_ENABLED") == "true"
+	backgroundPriceRefresh := false
+	priceRefreshConfig := service.PriceRefreshConfigFromEnv()
+
+	priceService := basePriceService
+	var redisClient *redis.Client
+
+	redisCache, err := cache.NewRedisCache(redisAddr, redisPassword, redisDB)
+	if err != nil {
+		// 如果 Redis 連線失敗，使用不帶快取的 Price Service 並停用排程器
+		log.Printf("Warning: Failed to connect to Redis: %v. Using price service without cache.", err)
+		log.Println("Warning: Scheduler is disabled (Redis not available)")
+		schedulerEnabled = false
+		redisCache = nil
+	} else {
+		defer redisCache.Close()
+		redisClient = redisCache.GetClient()
+
+		// 外部 API 速率限制改由 Redis 協調，讓多個程序共用每日額度
+		external.UseRedisRateLimiting(redisClient)
+
+		// 解析快取過期時間
+		cacheExpiration := 5 * time.Minute
+		if expStr := os.Getenv("PRICE_CACHE_EXPIRATION"); expStr != "" {
+			if duration, err := time.ParseDuration(expStr); err == nil {
+				cacheExpiration = duration
+			}
+		}
+
+		// 加上 Redis 快取層
+		backgroundPriceRefresh = schedulerEnabled && os.Getenv("PRICE_REFRESH_ENABLED") != "false"
+		if backgroundPriceRefresh {
+			priceService = service.NewCacheOnlyPriceService(redisCache, basePriceService, lastKnownPriceRepo, priceRefreshConfig)
+			log.Println("Redis cache enabled: prices are refreshed in the background, holdings read from cache")
+		} else {
+			priceService = service.NewCachedPriceService(redisCache, basePriceService, cacheExpiration, lastKnownPriceRepo)
+			log.Printf("Redis cache enabled: default=%v, US stocks=1h (to avoid Alpha Vantage API limits)", cacheExpiration)
+		}
+	}
+
+	// 初始化匯率服務（Redis 可用時帶快取）
+	exchangeRateClient := client.NewExchangeRateAPIClient()
+	exchangeRateService := service.NewExchangeRateService(exchangeRateRepo, exchangeRateClient, redisClient)
+
+	// 初始化 FIFO Calculator（需要 exchangeRateService）
+	fifoCalculator := service.NewFIFOCalculator(exchangeRateService)
+
+	// 初始化 Holding Service
+	holdingService := service.NewHoldingService(transactionRepo, fifoCalculator, priceService, exchangeRateService, optionContractRepo)
+
+	// 初始化背景價格更新服務（依台股、美股交易時段與加密貨幣間隔更新持倉價格）
+	var priceRefreshService service.PriceRefreshService
+	if backgroundPriceRefresh {
+		priceRefreshService = service.NewPriceRefreshService(transactionRepo, fifoCalculator, priceService, optionContractRepo, priceRefreshConfig)
+	}
+
+	// 信用卡現金流異動時同步重新產生帳單，讀取帳單時不再重新產生
+	creditCardStatementService := service.NewCreditCardStatementService(creditCardStatementRepo, creditCardRepo, cashFlowRepo)
+	cashFlowService := service.NewStatementSyncingCashFlowService(service.NewCashFlowService(cashFlowRepo, categoryRepo, bankAccountRepo, creditCardRepo), creditCardStatementService)
+	brokerageAccountService := service.NewBrokerageAccountService(brokerageAccountRepo, bankAccountRepo, categoryRepo, cashFlowService, holdingService)
+	custodyAccountService := service.NewCustodyAccountService(custodyAccountRepo, transactionRepo, holdingService)
+	optionContractService := service.NewOptionContractService(optionContractRepo)
+	instrumentService := service.NewInstrumentService(instrumentRepo)
+	feeProfileService := service.NewFeeProfileService(feeProfileRepo, instrumentRepo)
+
+	// 初始化 TransactionService（需要 brokerageAccountService 產生交割現金異動）
+	transactionService := service.NewTransactionService(transactionRepo, realizedProfitRepo, fifoCalculator, exchangeRateService, brokerageAccountService, optionContractRepo, instrumentRepo, feeProfileService)
+	manualAssetService := service.NewManualAssetService(manualAssetRepo, exchangeRateService)
+
+	// 初始化 Analytics Service
+	analyticsService := service.NewAnalyticsService(realizedProfitRepo)
+	unrealizedAnalyticsService := service.NewUnrealizedAnalyticsService(holdingService)
+	allocationService := service.NewAllocationService(holdingService, manualAssetService)
+	performanceTrendService := service.NewPerformanceTrendService(performanceSnapshotRepo, unrealizedAnalyticsService, analyticsService)
+	settingsService := service.NewSettingsService(settingsRepo)
+	discordService := service.NewDiscordService()
+	rebalanceService := service.NewRebalanceService(settingsService, holdingService)
+	categoryService := service.NewCategoryService(categoryRepo)
+	subscriptionService := service.NewSubscriptionService(subscriptionRepo, categoryRepo, exchangeRateService)
+	installmentService := service.NewInstallmentService(installmentRepo, categoryRepo, cashFlowService, exchangeRateService)
+	loanService := service.NewLoanService(loanRepo, categoryRepo)
+	recurringTemplateService := service.NewRecurringTemplateService(recurringTemplateRepo, categoryRepo)
+	billingService := service.NewBillingService(subscriptionRepo, installmentRepo, loanRepo, recurringTemplateRepo, billingLedgerRepo, bankAccountRepo, cashFlowService, exchangeRateService)
+	cashFlowForecastService := service.NewCashFlowForecastService(bankAccountRepo, creditCardRepo, creditCardStatementRepo, subscriptionRepo, installmentRepo, loanRepo, recurringTemplateRepo, cashFlowRepo, exchangeRateService)
+	bankAccountService := service.NewBankAccountService(bankAccountRepo)
+	bankAccountLedgerService := service.NewBankAccountLedgerService(bankAccountRepo, cashFlowRepo, categoryRepo, reconciliationRepo, cashFlowService)
+	creditCardService := service.NewCreditCardService(creditCardRepo)
+	creditCardRewardService := service.NewCreditCardRewardService(creditCardRewardRuleRepo, creditCardRepo, cashFlowRepo, categoryRepo)
+	creditCardGroupService := service.NewCreditCardGroupService(creditCardGroupRepo, creditCardRepo)
+	netWorthService := service.NewNetWorthService(holdingService, bankAccountRepo, creditCardRepo, installmentRepo, loanService, manualAssetService, exchangeRateService)
+
+	// 初始化 Asset Snapshot Service（包含依賴）
+	assetSnapshotService := service.NewAssetSnapshotServiceWithDeps(assetSnapshotRepo, holdingService, manualAssetService)
+
+	// 初始化 CSV Import Service
+	csvImportService := service.NewCSVImportService(feeProfileService)
+	brokerStatementService := service.NewBrokerStatementService(transactionRepo, feeProfileService)
+
+	// 初始化 Handler
+	handlers := serverHandlers{
+		auth:                api.NewAuthHandler(authService),
+		transaction:         api.NewTransactionHandler(transactionService, csvImportService),
+		brokerStatement:     api.NewBrokerStatementHandler(brokerStatementService),
+		holding:             api.NewHoldingHandler(holdingService),
+		analytics:           api.NewAnalyticsHandler(analyticsService),
+		unrealizedAnalytics: api.NewUnrealizedAnalyticsHandler(unrealizedAnalyticsService),
+		allocation:          api.NewAllocationHandler(allocationService),
+		performanceTrend:    api.NewPerformanceTrendHandler(performanceTrendService),
+		settings:            api.NewSettingsHandler(settingsService),
+		assetSnapshot:       api.NewAssetSnapshotHandler(assetSnapshotService),
+		discord:             api.NewDiscordHandler(discordService, settingsService, holdingService, rebalanceService),
+		rebalance:           api.NewRebalanceHandler(rebalanceService),
+		cashFlow:            api.NewCashFlowHandler(cashFlowService),
+		cashFlowForecast:    api.NewCashFlowForecastHandler(cashFlowForecastService),
+		category:            api.NewCategoryHandler(categoryService),
+		subscription:        api.NewSubscriptionHandler(subscriptionService),
+		installment:         api.NewInstallmentHandler(installmentService),
+		billing:             api.NewBillingHandler(billingService),
+		bankAccount:         api.NewBankAccountHandler(bankAccountService),
+		brokerageAccount:    api.NewBrokerageAccountHandler(brokerageAccountService),
+		custodyAccount:      api.NewCustodyAccountHandler(custodyAccountService),
+		optionContract:      api.NewOptionContractHandler(optionContractService),
+		instrument:          api.NewInstrumentHandler(instrumentService),
+		feeProfile:          api.NewFeeProfileHandler(feeProfileService),
+		dataArchive:         api.NewDataArchiveHandler(service.NewDataArchiveService(dataArchiveRepo)),
+		export:              api.NewExportHandler(service.NewExportService(cashFlowService, transactionService, realizedProfitRepo, bankAccountRepo, creditCardRepo, brokerageAccountRepo, custodyAccountRepo)),
+		priceProvider:       api.NewPriceProviderHandler(priceProviderRegistry),
+		bankAccountLedger:   api.NewBankAccountLedgerHandler(bankAccountLedgerService),
+		creditCard:          api.NewCreditCardHandler(creditCardService),
+		creditCardStatement: api.NewCreditCardStatementHandler(creditCardStatementService),
+		creditCardReward:    api.NewCreditCardRewardHandler(creditCardRewardService),
+		creditCardGroup:     api.NewCreditCardGroupHandler(creditCardGroupService),
+		exchangeRate:        api.NewExchangeRateHandler(exchangeRateService),
+		loan:                api.NewLoanHandler(loanService),
+		recurringTemplate:   api.NewRecurringTemplateHandler(recurringTemplateService),
+		netWorth:            api.NewNetWorthHandler(netWorthService),
+		manualAsset:         api.NewManualAssetHandler(manualAssetService),
+	}
+	handlers.cashFlow.SetDiscordService(discordService) // 設定 Discord service 用於發送報告
+
+	// 初始化並啟動排程器管理器（Redis 不可用時停用，也不記錄執行日誌）
+	schedulerManagerConfig := scheduler.SchedulerManagerConfig{
+		Enabled:           schedulerEnabled,
+		DailySnapshotTime: getEnvOrDefault("SCHEDULER_SNAPSHOT_TIME", "23:59"),
+	}
+	if redisCache == nil {
+		schedulerLogRepo = nil
+		cashFlowReportLogRepo = nil
+	}
+	schedulerManager := scheduler.NewSchedulerManager(
+		assetSnapshotService,
+		discordService,
+		settingsService,
+		holdingService,
+		rebalanceService,
+		billingService,
+		exchangeRateService,
+		creditCardService,
+		creditCardStatementService,
+		cashFlowService,
+		cashFlowForecastService,
+		priceRefreshService,
+		schedulerLogRepo,
+		cashFlowReportLogRepo,
+		schedulerManagerConfig,
+	)
+	if err := schedulerManager.Start(); err != nil {
+		log.Printf("Warning: Failed to start scheduler manager: %v", err)
+	}
+
+	// 初始化排程器 Handler
+	handlers.scheduler = api.NewSchedulerHandler(schedulerManager)
+
+	// 啟動伺服器（會在內部處理 graceful shutdown）
+	bot := startDiscordBot(botCtx, cashFlowService, creditCardStatementService, categoryRepo, bankAccountRepo, creditCardRepo)
+	startServer(handlers, schedulerManager, bot)
+}
+
+// getEnvOrDefault 取得環境變數，如果不存在則使用預設值
+func getEnvOrDefault(key, defaultValue string) string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
+
+func startDiscordBot(ctx context.Context, cashFlowSvc service.CashFlowService, statementSvc service.CreditCardStatementService, categoryRepo repository.CategoryRepository, bankAccountRepo repository.BankAccountRepository, creditCardRepo repository.CreditCardRepository) *discordbot.Bot {
+	cfg := discordbot.LoadConfig()
+	if !cfg.Enabled {
+		log.Println("Discord bot disabled")
+		return nil
+	}
+
+	bot, err := discordbot.NewBot(cfg)
+	if err != nil {
+		log.Printf("Warning: Failed to create Discord bot: %v", err)
+		return nil
+	}
+
+	parser := discordbot.NewGeminiParser(cfg.GeminiKey)
+	creator := discordbot.NewCashFlowServiceAdapter(cashFlowSvc)
+	catLoader := discordbot.NewCategoryRepoAdapter(categoryRepo)
+	acctLoader := discordbot.NewAccountRepoAdapter(bankAccountRepo, creditCardRepo)
+	cfQuerier := discordbot.NewCashFlowQueryAdapter(cashFlowSvc)
+	acctBalQuerier := discordbot.NewAccountBalanceQueryAdapter(bankAccountRepo, creditCardRepo)
+	ccPaymentAdapter := discordbot.NewCreditCardPaymentAdapter(cashFlowSvc, creditCardRepo, categoryRepo, statementSvc)
+	handler := discordbot.NewHandler(ctx, parser, creator, catLoader, acctLoader, cfg.Lang,
+		discordbot.WithCashFlowQuerier(cfQuerier),
+		discordbot.WithAccountBalanceQuerier(acctBalQuerier),
+		discordbot.WithCCPaymentCreator(ccPaymentAdapter),
+	)
+	bot.SetHandler(handler)
+
+	if err := bot.Start(); err != nil {
+		log.Printf("Warning: Failed to start Discord bot: %v", err)
+		return nil
+	}
+
+	return bot
+}
+
+// serverHandlers API 伺服器註冊路由使用的 handler
+type serverHandlers struct {
+	auth                *api.AuthHandler
+	transaction         *api.TransactionHandler
+	brokerStatement     *api.BrokerStatementHandler
+	holding             *api.HoldingHandler
+	analytics           *api.AnalyticsHandler
+	unrealizedAnalytics *api.UnrealizedAnalyticsHandler
+	allocation          *api.AllocationHandler
+	performanceTrend    *api.PerformanceTrendHandler
+	settings            *api.SettingsHandler
+	assetSnapshot       *api.AssetSnapshotHandler
+	discord             *api.DiscordHandler
+	scheduler           *api.SchedulerHandler
+	rebalance           *api.RebalanceHandler
+	cashFlow            *api.CashFlowHandler
+	cashFlowForecast    *api.CashFlowForecastHandler
+	category            *api.CategoryHandler
+	subscription        *api.SubscriptionHandler
+	installment         *api.InstallmentHandler
+	billing             *api.BillingHandler
+	bankAccount         *api.BankAccountHandler
+	bankAccountLedger   *api.BankAccountLedgerHandler
+	brokerageAccount    *api.BrokerageAccountHandler
+	custodyAccount      *api.CustodyAccountHandler
+	optionContract      *api.OptionContractHandler
+	instrument          *api.InstrumentHandler
+	feeProfile          *api.FeeProfileHandler
+	priceProvider       *api.PriceProviderHandler
+	creditCard          *api.CreditCardHandler
+	creditCardStatement *api.CreditCardStatementHandler
+	creditCardReward    *api.CreditCardRewardHandler
+	creditCardGroup     *api.CreditCardGroupHandler
+	exchangeRate        *api.ExchangeRateHandler
+	loan                *api.LoanHandler
+	recurringTemplate   *api.RecurringTemplateHandler
+	netWorth            *api.NetWorthHandler
+	manualAsset         *api.ManualAssetHandler
+	dataArchive         *api.DataArchiveHandler
+	export              *api.ExportHandler
+}
+
+func startServer(handlers serverHandlers, schedulerManager *scheduler.SchedulerManager, discordBot *discordbot.Bot) {
+	// 建立 Gin router
+	router := gin.Default()
+
+	// 設定 CORS
+	// 從環境變數讀取允許的來源，預設為 localhost:3000
+	allowedOrigins := []string{"http://localhost:3000"}
+	if origins := os.Getenv("CORS_ALLOWED_ORIGINS"); origins != "" {
+		allowedOrigins = strings.Split(origins, ",")
+	}
+
+	router.Use(cors.New(cors.Config{
+		AllowOrigins:     allowedOrigins,
+		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "Accept-Language"},
+		ExposeHeaders:    []string{"Content-Length"},
+		AllowCredentials: true, // 重要：允許發送 cookies
+		MaxAge:           12 * 3600,
+	}))
+
+	// 添加 i18n middleware
+	router.Use(middleware.I18nMiddleware())
+
+	// Swagger UI
+	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+
+	// Health check endpoint (不需要驗證)
+	router.GET("/health", func(c *gin.Context) {
+		c.JSON(200, gin.H{
+			"status":  "OK",
+			"message": "Asset Manager API Server is running.",
+		})
+	})
+
+	// Auth routes (不需要驗證)
+	authGroup := router.Group("/api/auth")
+	{
+		authGroup.POST("/login", handlers.auth.Login)
+		authGroup.POST("/logout", handlers.auth.Logout)
+		authGroup.GET("/me", middleware.AuthMiddleware(), handlers.auth.GetCurrentUser)
+	}
+
+	// API routes (需要驗證)
+	apiGroup := router.Group("/api")
+	apiGroup.Use(middleware.AuthMiddleware())
+	{
+		// Transactions 路由
+		transactions := apiGroup.Group("/transactions")
+		{
+			transactions.POST("", handlers.transaction.CreateTransaction)
+			transactions.POST("/batch", handlers.transaction.CreateTransactionsBatch)
+			transactions.POST("/swaps", handlers.transaction.CreateSwap)
+			transactions.GET("", handlers.transaction.ListTransactions)
+			transactions.GET("/export", handlers.export.ExportTransactions)
+			transactions.GET("/realized-profits/export", handlers.export.ExportRealizedProfits)
+			transactions.GET("/:id", handlers.transaction.GetTransaction)
+			transactions.PUT("/:id", handlers.transaction.UpdateTransaction)
+			transactions.DELETE("/:id", handlers.transaction.DeleteTransaction)
+			transactions.GET("/template", handlers.transaction.DownloadCSVTemplate)
+			transactions.POST("/parse-csv", handlers.transaction.ParseCSV)
+			transactions.GET("/import/formats", handlers.brokerStatement.ListFormats)
+			transactions.POST("/import/preview", handlers.brokerStatement.PreviewStatement)
+		}
+
+		// Holdings 路由
+		holdings := apiGroup.Group("/holdings")
+		{
+			holdings.GET("", handlers.holding.GetAllHoldings)
+			holdings.GET("/:symbol", handlers.holding.GetHoldingBySymbol)
+			holdings.POST("/fix-insufficient-quantity", handlers.holding.FixInsufficientQuantity)
+		}
+
+		// Analytics 路由
+		analytics := apiGroup.Group("/analytics")
+		{
+			analytics.GET("/summary", handlers.analytics.GetSummary)
+			analytics.GET("/performance", handlers.analytics.GetPerformance)
+			analytics.GET("/top-assets", handlers.analytics.GetTopAssets)
+
+			// Unrealized Analytics 路由
+			unrealized := analytics.Group("/unrealized")
+			{
+				unrealized.GET("/summary", handlers.unrealizedAnalytics.GetSummary)
+				unrealized.GET("/performance", handlers.unrealizedAnalytics.GetPerformance)
+				unrealized.GET("/top-assets", handlers.unrealizedAnalytics.GetTopAssets)
+			}
+		}
+
+		// Allocation 路由
+		allocation := apiGroup.Group("/allocation")
+		{
+			allocation.GET("/current", handlers.allocation.GetCurrentAllocation)
+			allocation.GET("/by-type", handlers.allocation.GetAllocationByType)
+			allocation.GET("/by-asset", handlers.allocation.GetAllocationByAsset)
+		}
+
+		// Performance Trends 路由
+		performanceTrends := apiGroup.Group("/performance-trends")
+		{
+			performanceTrends.POST("/snapshot", handlers.performanceTrend.CreateDailySnapshot)
+			performanceTrends.GET("/range", handlers.performanceTrend.GetTrendByDateRange)
+			performanceTrends.GET("/latest", handlers.performanceTrend.GetLatestTrend)
+		}
+
+		// Settings 路由
+		settings := apiGroup.Group("/settings")
+		{
+			settings.GET("", handlers.settings.GetSettings)
+			settings.PUT("", handlers.settings.UpdateSettings)
+		}
+
+		// Discord 路由
+		discord := apiGroup.Group("/discord")
+		{
+			discord.POST("/test", handlers.discord.TestDiscord)
+			discord.POST("/daily-report", handlers.discord.SendDailyReport)
+		}
+
+		// Scheduler 路由
+		schedulerGroup := apiGroup.Group("/scheduler")
+		{
+			schedulerGroup.GET("/status", handlers.scheduler.GetStatus)
+			schedulerGroup.GET("/summaries", handlers.scheduler.GetTaskSummaries)
+			schedulerGroup.POST("/trigger/snapshot", handlers.scheduler.TriggerSnapshot)
+			schedulerGroup.POST("/trigger/discord-report", handlers.scheduler.TriggerDiscordReport)
+			schedulerGroup.POST("/reload/discord", handlers.scheduler.ReloadDiscordSchedule)
+		}
+
+		// Rebalance 路由
+		rebalance := apiGroup.Group("/rebalance")
+		{
+			rebalance.GET("/check", handlers.rebalance.CheckRebalance)
+		}
+
+		// Asset Snapshots 路由
+		snapshots := apiGroup.Group("/snapshots")
+		{
+			snapshots.POST("", handlers.assetSnapshot.CreateSnapshot)
+			snapshots.POST("/trigger", handlers.assetSnapshot.TriggerDailySnapshots) // 手動觸發每日快照
+			snapshots.GET("/trend", handlers.assetSnapshot.GetAssetTrend)
+			snapshots.GET("/latest", handlers.assetSnapshot.GetLatestSnapshot)
+			snapshots.PUT("", handlers.assetSnapshot.UpdateSnapshot)
+			snapshots.DELETE("", handlers.assetSnapshot.DeleteSnapshot)
+		}
+
+		// Cash Flows 路由
+		cashFlows := apiGroup.Group("/cash-flows")
+		{
+			cashFlows.POST("", handlers.cashFlow.CreateCashFlow)
+			cashFlows.GET("", handlers.cashFlow.ListCashFlows)
+			cashFlows.GET("/export", handlers.export.ExportCashFlows)
+			cashFlows.GET("/export/monthly", handlers.export.ExportMonthlySummary)
+			cashFlows.GET("/summary", handlers.cashFlow.GetSummary)
+			cashFlows.GET("/monthly-summary", handlers.cashFlow.GetMonthlySummary)
+			cashFlows.GET("/yearly-summary", handlers.cashFlow.GetYearlySummary)
+			cashFlows.POST("/send-monthly-report", handlers.cashFlow.SendMonthlyReport)
+			cashFlows.POST("/send-yearly-report", handlers.cashFlow.SendYearlyReport)
+			cashFlows.GET("/forecast", handlers.cashFlowForecast.GetForecast)
+			cashFlows.GET("/:id", handlers.cashFlow.GetCashFlow)
+			cashFlows.PUT("/:id", handlers.cashFlow.UpdateCashFlow)
+			cashFlows.DELETE("/:id", handlers.cashFlow.DeleteCashFlow)
+		}
+
+		// Categories 路由
+		categories := apiGroup.Group("/categories")
+		{
+			categories.POST("", handlers.category.CreateCategory)
+			categories.GET("", handlers.category.ListCategories)
+			categories.PUT("/reorder", handlers.category.ReorderCategories)
+			categories.GET("/:id", handlers.category.GetCategory)
+			categories.PUT("/:id", handlers.category.UpdateCategory)
+			categories.DELETE("/:id", handlers.category.DeleteCategory)
+		}
+
+		// Subscriptions 路由
+		subscriptions := apiGroup.Group("/subscriptions")
+		{
+			subscriptions.POST("", handlers.subscription.CreateSubscription)
+			subscriptions.GET("", handlers.subscription.ListSubscriptions)
+			subscriptions.GET("/:id", handlers.subscription.GetSubscription)
+			subscriptions.PUT("/:id", handlers.subscription.UpdateSubscription)
+			subscriptions.DELETE("/:id", handlers.subscription.DeleteSubscription)
+			subscriptions.GET("/analytics", handlers.subscription.GetCostAnalytics)
+			subscriptions.POST("/:id/cancel", handlers.subscription.CancelSubscription)
+			subscriptions.POST("/:id/price-changes", handlers.subscription.AddPriceChange)
+			subscriptions.GET("/:id/price-changes", handlers.subscription.GetPriceHistory)
+			subscriptions.POST("/:id/usage-notes", handlers.subscription.AddUsageNote)
+			subscriptions.GET("/:id/usage-notes", handlers.subscription.ListUsageNotes)
+		}
+
+		// Installments 路由
+		installments := apiGroup.Group("/installments")
+		{
+			installments.POST("", handlers.installment.CreateInstallment)
+			installments.GET("", handlers.installment.ListInstallments)
+			installments.GET("/completing-soon", handlers.installment.GetCompletingSoon)
+			installments.GET("/:id", handlers.installment.GetInstallment)
+			installments.PUT("/:id", handlers.installment.UpdateInstallment)
+			installments.DELETE("/:id", handlers.installment.DeleteInstallment)
+			installments.POST("/:id/prepayments", handlers.installment.AddPrepayment)
+			installments.POST("/:id/payoff", handlers.installment.PayOff)
+		}
+
+		// Billing 路由
+		billing := apiGroup.Group("/billing")
+		{
+			billing.POST("/process-daily", handlers.billing.ProcessDailyBilling)
+			billing.POST("/process-subscriptions", handlers.billing.ProcessSubscriptionBilling)
+			billing.POST("/process-installments", handlers.billing.ProcessInstallmentBilling)
+			billing.POST("/process-loans", handlers.billing.ProcessLoanBilling)
+			billing.POST("/catch-up", handlers.billing.CatchUpBilling)
+			billing.GET("/runs", handlers.billing.ListBillingRuns)
+		}
+
+		// Loans 路由
+		loans := apiGroup.Group("/loans")
+		{
+			loans.POST("", handlers.loan.CreateLoan)
+			loans.GET("", handlers.loan.ListLoans)
+			loans.GET("/:id", handlers.loan.GetLoan)
+			loans.PUT("/:id", handlers.loan.UpdateLoan)
+			loans.DELETE("/:id", handlers.loan.DeleteLoan)
+			loans.GET("/:id/schedule", handlers.loan.GetAmortizationSchedule)
+			loans.GET("/:id/payments", handlers.loan.GetPayments)
+			loans.POST("/:id/rate-changes", handlers.loan.AddRateChange)
+			loans.POST("/:id/prepayments", handlers.loan.AddPrepayment)
+		}
+
+		// Recurring Templates 路由
+		recurringTemplates := apiGroup.Group("/recurring-templates")
+		{
+			recurringTemplates.POST("", handlers.recurringTemplate.CreateTemplate)
+			recurringTemplates.GET("", handlers.recurringTemplate.ListTemplates)
+			recurringTemplates.GET("/:id", handlers.recurringTemplate.GetTemplate)
+			recurringTemplates.PUT("/:id", handlers.recurringTemplate.UpdateTemplate)
+			recurringTemplates.DELETE("/:id", handlers.recurringTemplate.DeleteTemplate)
+			recurringTemplates.GET("/:id/occurrences", handlers.recurringTemplate.GetOccurrences)
+			recurringTemplates.POST("/:id/amount-changes", handlers.recurringTemplate.AddAmountChange)
+			recurringTemplates.DELETE("/:id/amount-changes/:change_id", handlers.recurringTemplate.DeleteAmountChange)
+		}
+
+		// Manual Assets 路由
+		manualAssets := apiGroup.Group("/manual-assets")
+		{
+			manualAssets.POST("", handlers.manualAsset.CreateManualAsset)
+			manualAssets.GET("", handlers.manualAsset.ListManualAssets)
+			manualAssets.GET("/:id", handlers.manualAsset.GetManualAsset)
+			manualAssets.PUT("/:id", handlers.manualAsset.UpdateManualAsset)
+			manualAssets.DELETE("/:id", handlers.manualAsset.DeleteManualAsset)
+			manualAssets.GET("/:id/valuations", handlers.manualAsset.GetValuations)
+			manualAssets.POST("/:id/valuations", handlers.manualAsset.AddValuation)
+			manualAssets.DELETE("/:id/valuations/:valuation_id", handlers.manualAsset.DeleteValuation)
+		}
+
+		// Net Worth 路由
+		apiGroup.GET("/net-worth", handlers.netWorth.GetNetWorth)
+
+		// Bank Accounts 路由
+		bankAccounts := apiGroup.Group("/bank-accounts")
+		{
+			bankAccounts.POST("", handlers.bankAccount.CreateBankAccount)
+			bankAccounts.GET("", handlers.bankAccount.ListBankAccounts)
+			bankAccounts.GET("/:id", handlers.bankAccount.GetBankAccount)
+			bankAccounts.PUT("/:id", handlers.bankAccount.UpdateBankAccount)
+			bankAccounts.DELETE("/:id", handlers.bankAccount.DeleteBankAccount)
+			bankAccounts.GET("/:id/ledger", handlers.bankAccountLedger.GetLedger)
+			bankAccounts.GET("/:id/reconciliations", handlers.bankAccountLedger.ListReconciliations)
+			bankAccounts.POST("/:id/reconciliations", handlers.bankAccountLedger.Reconcile)
+		}
+
+		// Brokerage Accounts 路由
+		brokerageAccounts := apiGroup.Group("/brokerage-accounts")
+		{
+			brokerageAccounts.POST("", handlers.brokerageAccount.CreateBrokerageAccount)
+			brokerageAccounts.GET("", handlers.brokerageAccount.ListBrokerageAccounts)
+			brokerageAccounts.GET("/:id", handlers.brokerageAccount.GetBrokerageAccount)
+			brokerageAccounts.PUT("/:id", handlers.brokerageAccount.UpdateBrokerageAccount)
+			brokerageAccounts.DELETE("/:id", handlers.brokerageAccount.DeleteBrokerageAccount)
+			brokerageAccounts.GET("/:id/movements", handlers.brokerageAccount.GetMovements)
+			brokerageAccounts.GET("/:id/holdings", handlers.brokerageAccount.GetHoldings)
+		}
+
+		// Custody Accounts 路由（加密貨幣保管帳戶）
+		custodyAccounts := apiGroup.Group("/custody-accounts")
+		{
+			custodyAccounts.POST("", handlers.custodyAccount.CreateCustodyAccount)
+			custodyAccounts.GET("", handlers.custodyAccount.ListCustodyAccounts)
+			custodyAccounts.GET("/holdings", handlers.custodyAccount.GetHoldingsByCustody)
+			custodyAccounts.GET("/:id", handlers.custodyAccount.GetCustodyAccount)
+			custodyAccounts.PUT("/:id", handlers.custodyAccount.UpdateCustodyAccount)
+			custodyAccounts.DELETE("/:id", handlers.custodyAccount.DeleteCustodyAccount)
+		}
+
+		// Option Contracts 路由（選擇權合約規格）
+		optionContracts := apiGroup.Group("/option-contracts")
+		{
+			optionContracts.POST("", handlers.optionContract.CreateOptionContract)
+			optionContracts.GET("", handlers.optionContract.ListOptionContracts)
+			optionContracts.GET("/:id", handlers.optionContract.GetOptionContract)
+			optionContracts.DELETE("/:id", handlers.optionContract.DeleteOptionContract)
+		}
+
+		// Instruments 路由（商品主檔搜尋與清單匯入）
+		instruments := apiGroup.Group("/instruments")
+		{
+			instruments.GET("/search", handlers.instrument.SearchInstruments)
+			instruments.POST("/import", handlers.instrument.ImportInstruments)
+		}
+
+		// Fee Profiles 路由（券商手續費與交易稅設定）
+		feeProfiles := apiGroup.Group("/fee-profiles")
+		{
+			feeProfiles.POST("", handlers.feeProfile.CreateFeeProfile)
+			feeProfiles.GET("", handlers.feeProfile.ListFeeProfiles)
+			feeProfiles.GET("/:id", handlers.feeProfile.GetFeeProfile)
+			feeProfiles.PUT("/:id", handlers.feeProfile.UpdateFeeProfile)
+			feeProfiles.DELETE("/:id", handlers.feeProfile.DeleteFeeProfile)
+		}
+
+		// Backup 路由（完整資料備份與還原）
+		apiGroup.GET("/export", handlers.dataArchive.ExportData)
+		apiGroup.POST("/import", handlers.dataArchive.ImportData)
+
+		// Prices 路由（價格來源順序與健康狀態）
+		prices := apiGroup.Group("/prices")
+		{
+			prices.GET("/providers", handlers.priceProvider.GetProviderStatus)
+		}
+
+		// Credit Cards 路由
+		creditCards := apiGroup.Group("/credit-cards")
+		{
+			creditCards.POST("", handlers.creditCard.CreateCreditCard)
+			creditCards.GET("", handlers.creditCard.ListCreditCards)
+			creditCards.GET("/upcoming-billing", handlers.creditCard.GetUpcomingBilling)
+			creditCards.GET("/upcoming-payment", handlers.creditCard.GetUpcomingPayment)
+			creditCards.GET("/:id", handlers.creditCard.GetCreditCard)
+			creditCards.PUT("/:id", handlers.creditCard.UpdateCreditCard)
+			creditCards.DELETE("/:id", handlers.creditCard.DeleteCreditCard)
+			creditCards.GET("/:id/statements", handlers.creditCardStatement.ListStatements)
+			creditCards.GET("/:id/statements/current", handlers.creditCardStatement.GetCurrentStatement)
+			creditCards.GET("/:id/statements/:statement_id", handlers.creditCardStatement.GetStatement)
+			creditCards.GET("/:id/reward-rules", handlers.creditCardReward.ListRules)
+			creditCards.POST("/:id/reward-rules", handlers.creditCardReward.CreateRule)
+			creditCards.PUT("/:id/reward-rules/:rule_id", handlers.creditCardReward.UpdateRule)
+			creditCards.DELETE("/:id/reward-rules/:rule_id", handlers.creditCardReward.DeleteRule)
+			creditCards.GET("/:id/rewards", handlers.creditCardReward.GetRewardReport)
+		}
+
+		// Credit Card Rewards 路由
+		creditCardRewards := apiGroup.Group("/credit-card-rewards")
+		{
+			creditCardRewards.GET("/recommendation", handlers.creditCardReward.RecommendCard)
+		}
+
+		// Credit Card Groups 路由
+		creditCardGroups := apiGroup.Group("/credit-card-groups")
+		{
+			creditCardGroups.POST("", handlers.creditCardGroup.CreateCreditCardGroup)
+			creditCardGroups.GET("", handlers.creditCardGroup.ListCreditCardGroups)
+			creditCardGroups.GET("/:id", handlers.creditCardGroup.GetCreditCardGroup)
+			creditCardGroups.PUT("/:id", handlers.creditCardGroup.UpdateCreditCardGroup)
+			creditCardGroups.DELETE("/:id", handlers.creditCardGroup.DeleteCreditCardGroup)
+			creditCardGroups.POST("/:id/cards", handlers.creditCardGroup.AddCardsToGroup)
+			creditCardGroups.DELETE("/:id/cards", handlers.creditCardGroup.RemoveCardsFromGroup)
+		}
+
+		// Exchange Rates 路由
+		exchangeRates := apiGroup.Group("/exchange-rates")
+		{
+			exchangeRates.POST("/refresh", handlers.exchangeRate.RefreshExchangeRate)
+		}
+	}
+
+	// 建立 HTTP 伺服器
+	srv := &http.Server{
+		Addr:    ":8080",
+		Handler: router,
+	}
+
+	// 在 goroutine 中啟動伺服器
+	go func() {
+		log.Println("Starting server on :8080...")
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("Failed to start server: %v", err)
+		}
+	}()
+
+	// 等待中斷信號
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+	log.Println("Shutting down server gracefully...")
+
+	if discordBot != nil {
+		log.Println("Stopping Discord bot...")
+		_ = discordBot.Stop()
+	}
+
+	if schedulerManager != nil {
+		log.Println("Stopping scheduler...")
+		schedulerManager.Stop()
+		time.Sleep(5 * time.Second)
+	}
+
+	// 設定 5 秒的超時時間來關閉伺服器
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Fatalf("Server forced to shutdown: %v", err)
+	}
+
+	log.Println("Server exited")
+}
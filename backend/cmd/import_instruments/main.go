@@ -0,0 +1,53 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/chienchuanw/asset-manager/internal/db"
+	"github.com/chienchuanw/asset-manager/internal/models"
+	"github.com/chienchuanw/asset-manager/internal/repository"
+	"github.com/chienchuanw/asset-manager/internal/service"
+	"github.com/joho/godotenv"
+)
+
+// 匯入商品主檔清單
+// 用法：go run ./cmd/import_instruments -source twse -file t187ap03_L.csv
+// source：twse、tpex、emerging（CSV）、us_tickers（nasdaqlisted.txt / otherlisted.txt）、coingecko（/coins/list 或 /coins/markets JSON）
+func main() {
+	source := flag.String("source", "", "instrument list source (twse, tpex, emerging, us_tickers, coingecko)")
+	path := flag.String("file", "", "path to the instrument list file")
+	flag.Parse()
+
+	if !models.InstrumentSource(*source).Validate() || *path == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	// 載入環境變數
+	if err := godotenv.Load(".env.local"); err != nil {
+		log.Printf("Warning: .env.local file not found, using environment variables")
+	}
+
+	// 連接資料庫
+	database, err := db.InitDB()
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer database.Close()
+
+	file, err := os.Open(*path)
+	if err != nil {
+		log.Fatalf("Failed to open %s: %v", *path, err)
+	}
+	defer file.Close()
+
+	instrumentService := service.NewInstrumentService(repository.NewInstrumentRepository(database))
+	result, err := instrumentService.Import(models.InstrumentSource(*source), file)
+	if err != nil {
+		log.Fatalf("Failed to import instruments: %v", err)
+	}
+
+	log.Printf("✓ Imported %d %s instruments (parsed %d, skipped %d)", result.Imported, result.Source, result.Parsed, result.Skipped)
+}
@@ -33,6 +33,10 @@ func main() {
 	exchangeRateRepo := repository.NewExchangeRateRepository(database)
 	assetSnapshotRepo := repository.NewAssetSnapshotRepository(database)
 	realizedProfitRepo := repository.NewRealizedProfitRepository(database)
+	manualAssetRepo := repository.NewManualAssetRepository(database)
+	optionContractRepo := repository.NewOptionContractRepository(database)
+	lastKnownPriceRepo := repository.NewLastKnownPriceRepository(database)
+	instrumentRepo := repository.NewInstrumentRepository(database)
 
 	// PerformanceSnapshotRepository 需要 sqlx.DB
 	dbx := sqlx.NewDb(database, "postgres")
@@ -45,8 +49,9 @@ func main() {
 	alphaVantageAPIKey := os.Getenv("ALPHA_VANTAGE_API_KEY")
 
 	if finmindAPIKey != "" && coingeckoAPIKey != "" && alphaVantageAPIKey != "" {
-		priceService = service.NewRealPriceService(finmindAPIKey, coingeckoAPIKey, alphaVantageAPIKey)
-		log.Println("✓ Using real price API (FinMind + CoinGecko + Alpha Vantage)")
+		priceProviderRegistry := service.NewDefaultPriceProviderRegistry(finmindAPIKey, coingeckoAPIKey, alphaVantageAPIKey, lastKnownPriceRepo, instrumentRepo)
+		priceService = service.NewProviderChainPriceService(priceProviderRegistry)
+		log.Println("✓ Using real price API (FinMind + Yahoo Finance + Alpha Vantage + CoinGecko)")
 	} else {
 		priceService = service.NewMockPriceService()
 		log.Println("⚠️  Using mock price service (API keys not configured)")
@@ -60,10 +65,13 @@ func main() {
 	fifoCalculator := service.NewFIFOCalculator(exchangeRateService)
 
 	// 初始化 HoldingService
-	holdingService := service.NewHoldingService(transactionRepo, fifoCalculator, priceService, exchangeRateService)
+	holdingService := service.NewHoldingService(transactionRepo, fifoCalculator, priceService, exchangeRateService, optionContractRepo)
+
+	// 初始化 ManualAssetService
+	manualAssetService := service.NewManualAssetService(manualAssetRepo, exchangeRateService)
 
 	// 初始化 AssetSnapshotService
-	assetSnapshotService := service.NewAssetSnapshotServiceWithDeps(assetSnapshotRepo, holdingService)
+	assetSnapshotService := service.NewAssetSnapshotServiceWithDeps(assetSnapshotRepo, holdingService, manualAssetService)
 
 	// 初始化 Analytics Services
 	unrealizedAnalyticsService := service.NewUnrealizedAnalyticsService(holdingService)
@@ -45,6 +45,7 @@ func main() {
 	// 初始化 repositories
 	transactionRepo := repository.NewTransactionRepository(db)
 	exchangeRateRepo := repository.NewExchangeRateRepository(db)
+	optionContractRepo := repository.NewOptionContractRepository(db)
 
 	// 初始化 services
 	exchangeRateAPIClient := client.NewExchangeRateAPIClient()
@@ -55,7 +56,7 @@ func main() {
 	priceService := service.NewMockPriceService()
 
 	// 初始化 Holding Service
-	holdingService := service.NewHoldingService(transactionRepo, fifoCalculator, priceService, exchangeRateService)
+	holdingService := service.NewHoldingService(transactionRepo, fifoCalculator, priceService, exchangeRateService, optionContractRepo)
 
 	// 測試取得所有持倉
 	fmt.Println("\n=== 測試 GetAllHoldings API ===")
@@ -0,0 +1,176 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/chienchuanw/asset-manager/internal/models"
+	"github.com/chienchuanw/asset-manager/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// BankAccountLedgerHandler 銀行帳戶明細帳與對帳 API handler
+type BankAccountLedgerHandler struct {
+	service service.BankAccountLedgerService
+}
+
+// NewBankAccountLedgerHandler 建立新的銀行帳戶明細帳 handler
+func NewBankAccountLedgerHandler(service service.BankAccountLedgerService) *BankAccountLedgerHandler {
+	return &BankAccountLedgerHandler{service: service}
+}
+
+// parseBankAccountID 解析路徑中的銀行帳戶 ID，失敗時回傳 400
+func parseBankAccountID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Error: &APIError{
+				Code:    "INVALID_ID",
+				Message: "Invalid bank account ID format",
+			},
+		})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// GetLedger 取得銀行帳戶明細帳
+// @Summary 取得銀行帳戶明細帳
+// @Description 列出影響帳戶餘額的所有現金流（含作為轉帳目標），並附上逐筆累計餘額
+// @Tags bank-accounts
+// @Produce json
+// @Param id path string true "銀行帳戶 ID"
+// @Param start_date query string false "開始日期 (YYYY-MM-DD)"
+// @Param end_date query string false "結束日期 (YYYY-MM-DD)"
+// @Success 200 {object} APIResponse{data=models.BankAccountLedger}
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 500 {object} APIResponse{error=APIError}
+// @Router /api/bank-accounts/{id}/ledger [get]
+func (h *BankAccountLedgerHandler) GetLedger(c *gin.Context) {
+	id, ok := parseBankAccountID(c)
+	if !ok {
+		return
+	}
+
+	var startDate, endDate *time.Time
+	if startDateStr := c.Query("start_date"); startDateStr != "" {
+		parsed, err := time.Parse("2006-01-02", startDateStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, APIResponse{
+				Error: &APIError{
+					Code:    "INVALID_START_DATE",
+					Message: "Invalid start date format, use YYYY-MM-DD",
+				},
+			})
+			return
+		}
+		startDate = &parsed
+	}
+
+	if endDateStr := c.Query("end_date"); endDateStr != "" {
+		parsed, err := time.Parse("2006-01-02", endDateStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, APIResponse{
+				Error: &APIError{
+					Code:    "INVALID_END_DATE",
+					Message: "Invalid end date format, use YYYY-MM-DD",
+				},
+			})
+			return
+		}
+		endDate = &parsed
+	}
+
+	ledger, err := h.service.GetLedger(id, startDate, endDate)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Error: &APIError{
+				Code:    "GET_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Data: ledger,
+	})
+}
+
+// Reconcile 與對帳單餘額對帳
+// @Summary 銀行帳戶對帳
+// @Description 比對指定日期的帳上餘額與對帳單餘額，有差額時建立「帳戶調整」記錄
+// @Tags bank-accounts
+// @Accept json
+// @Produce json
+// @Param id path string true "銀行帳戶 ID"
+// @Param reconciliation body models.ReconcileBankAccountInput true "對帳資料"
+// @Success 201 {object} APIResponse{data=models.BankAccountReconciliation}
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 500 {object} APIResponse{error=APIError}
+// @Router /api/bank-accounts/{id}/reconciliations [post]
+func (h *BankAccountLedgerHandler) Reconcile(c *gin.Context) {
+	id, ok := parseBankAccountID(c)
+	if !ok {
+		return
+	}
+
+	var input models.ReconcileBankAccountInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Error: &APIError{
+				Code:    "INVALID_INPUT",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	reconciliation, err := h.service.Reconcile(id, &input)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Error: &APIError{
+				Code:    "PROCESS_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, APIResponse{
+		Data: reconciliation,
+	})
+}
+
+// ListReconciliations 取得對帳記錄
+// @Summary 取得對帳記錄
+// @Description 取得銀行帳戶的對帳記錄（由新到舊）
+// @Tags bank-accounts
+// @Produce json
+// @Param id path string true "銀行帳戶 ID"
+// @Success 200 {object} APIResponse{data=[]models.BankAccountReconciliation}
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 500 {object} APIResponse{error=APIError}
+// @Router /api/bank-accounts/{id}/reconciliations [get]
+func (h *BankAccountLedgerHandler) ListReconciliations(c *gin.Context) {
+	id, ok := parseBankAccountID(c)
+	if !ok {
+		return
+	}
+
+	reconciliations, err := h.service.ListReconciliations(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Error: &APIError{
+				Code:    "LIST_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Data: reconciliations,
+	})
+}
@@ -0,0 +1,260 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/chienchuanw/asset-manager/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// BillingHandler 扣款 API handler
+type BillingHandler struct {
+	billingService service.BillingService
+}
+
+// NewBillingHandler 建立新的扣款 handler
+func NewBillingHandler(billingService service.BillingService) *BillingHandler {
+	return &BillingHandler{
+		billingService: billingService,
+	}
+}
+
+// ProcessDailyBilling 處理每日扣款（手動觸發）
+// @Summary 處理每日扣款
+// @Description 手動觸發每日扣款處理（訂閱 + 分期 + 貸款）
+// @Tags billing
+// @Accept json
+// @Produce json
+// @Param input body ProcessDailyBillingInput false "扣款日期（選填，預設為今天）"
+// @Success 200 {object} APIResponse{data=service.DailyBillingResult}
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 500 {object} APIResponse{error=APIError}
+// @Router /api/billing/process-daily [post]
+func (h *BillingHandler) ProcessDailyBilling(c *gin.Context) {
+	var input ProcessDailyBillingInput
+
+	// 綁定請求資料（選填）
+	if err := c.ShouldBindJSON(&input); err != nil {
+		// 如果沒有提供日期，使用今天
+		input.Date = time.Now()
+	}
+
+	// 如果日期為零值，使用今天
+	if input.Date.IsZero() {
+		input.Date = time.Now()
+	}
+
+	// 呼叫 service 處理每日扣款
+	result, err := h.billingService.ProcessDailyBilling(input.Date)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Error: &APIError{
+				Code:    "PROCESS_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Data: result,
+	})
+}
+
+// ProcessSubscriptionBilling 處理訂閱扣款（手動觸發）
+// @Summary 處理訂閱扣款
+// @Description 手動觸發訂閱扣款處理
+// @Tags billing
+// @Accept json
+// @Produce json
+// @Param input body ProcessBillingInput false "扣款日期（選填，預設為今天）"
+// @Success 200 {object} APIResponse{data=service.BillingResult}
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 500 {object} APIResponse{error=APIError}
+// @Router /api/billing/process-subscriptions [post]
+func (h *BillingHandler) ProcessSubscriptionBilling(c *gin.Context) {
+	var input ProcessBillingInput
+
+	// 綁定請求資料（選填）
+	if err := c.ShouldBindJSON(&input); err != nil {
+		// 如果沒有提供日期，使用今天
+		input.Date = time.Now()
+	}
+
+	// 如果日期為零值，使用今天
+	if input.Date.IsZero() {
+		input.Date = time.Now()
+	}
+
+	// 呼叫 service 處理訂閱扣款
+	result, err := h.billingService.ProcessSubscriptionBilling(input.Date)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Error: &APIError{
+				Code:    "PROCESS_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Data: result,
+	})
+}
+
+// ProcessInstallmentBilling 處理分期扣款（手動觸發）
+// @Summary 處理分期扣款
+// @Description 手動觸發分期扣款處理
+// @Tags billing
+// @Accept json
+// @Produce json
+// @Param input body ProcessBillingInput false "扣款日期（選填，預設為今天）"
+// @Success 200 {object} APIResponse{data=service.BillingResult}
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 500 {object} APIResponse{error=APIError}
+// @Router /api/billing/process-installments [post]
+func (h *BillingHandler) ProcessInstallmentBilling(c *gin.Context) {
+	var input ProcessBillingInput
+
+	// 綁定請求資料（選填）
+	if err := c.ShouldBindJSON(&input); err != nil {
+		// 如果沒有提供日期，使用今天
+		input.Date = time.Now()
+	}
+
+	// 如果日期為零值，使用今天
+	if input.Date.IsZero() {
+		input.Date = time.Now()
+	}
+
+	// 呼叫 service 處理分期扣款
+	result, err := h.billingService.ProcessInstallmentBilling(input.Date)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Error: &APIError{
+				Code:    "PROCESS_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Data: result,
+	})
+}
+
+// ProcessLoanBilling 處理貸款扣款（手動觸發）
+// @Summary 處理貸款扣款
+// @Description 手動觸發貸款扣款處理（本金與利息分開建立現金流）
+// @Tags billing
+// @Accept json
+// @Produce json
+// @Param input body ProcessBillingInput false "扣款日期（選填，預設為今天）"
+// @Success 200 {object} APIResponse{data=service.BillingResult}
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 500 {object} APIResponse{error=APIError}
+// @Router /api/billing/process-loans [post]
+func (h *BillingHandler) ProcessLoanBilling(c *gin.Context) {
+	var input ProcessBillingInput
+
+	// 綁定請求資料（選填）
+	if err := c.ShouldBindJSON(&input); err != nil {
+		// 如果沒有提供日期，使用今天
+		input.Date = time.Now()
+	}
+
+	// 如果日期為零值，使用今天
+	if input.Date.IsZero() {
+		input.Date = time.Now()
+	}
+
+	// 呼叫 service 處理貸款扣款
+	result, err := h.billingService.ProcessLoanBilling(input.Date)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Error: &APIError{
+				Code:    "PROCESS_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Data: result,
+	})
+}
+
+// CatchUpBilling 補跑漏掉的每日扣款（手動觸發）
+// @Summary 補跑每日扣款
+// @Description 從最近一次成功執行的隔天開始逐日處理到今天，已扣款的項目不會重複扣款
+// @Tags billing
+// @Produce json
+// @Success 200 {object} APIResponse{data=[]service.DailyBillingResult}
+// @Failure 500 {object} APIResponse{error=APIError}
+// @Router /api/billing/catch-up [post]
+func (h *BillingHandler) CatchUpBilling(c *gin.Context) {
+	results, err := h.billingService.CatchUpBilling(time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Error: &APIError{
+				Code:    "PROCESS_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Data: results,
+	})
+}
+
+// ListBillingRuns 取得每日扣款執行記錄
+// @Summary 取得每日扣款執行記錄
+// @Description 取得最近的每日扣款執行記錄（依扣款日由新到舊）
+// @Tags billing
+// @Produce json
+// @Param limit query int false "回傳數量限制" default(30)
+// @Success 200 {object} APIResponse{data=[]models.BillingRun}
+// @Failure 500 {object} APIResponse{error=APIError}
+// @Router /api/billing/runs [get]
+func (h *BillingHandler) ListBillingRuns(c *gin.Context) {
+	// 取得 limit 參數，預設為 30
+	limit := 30
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 {
+			limit = parsedLimit
+		}
+	}
+
+	runs, err := h.billingService.ListBillingRuns(limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Error: &APIError{
+				Code:    "LIST_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Data: runs,
+	})
+}
+
+// ProcessBillingInput 處理扣款的輸入
+type ProcessBillingInput struct {
+	Date time.Time `json:"date"`
+}
+
+// ProcessDailyBillingInput 處理每日扣款的輸入
+type ProcessDailyBillingInput struct {
+	Date time.Time `json:"date"`
+}
+
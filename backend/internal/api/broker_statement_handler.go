@@ -0,0 +1,114 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/chienchuanw/asset-manager/internal/models"
+	"github.com/chienchuanw/asset-manager/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// BrokerStatementHandler 券商對帳單匯入 API handler
+type BrokerStatementHandler struct {
+	service service.BrokerStatementService
+}
+
+// NewBrokerStatementHandler 建立新的券商對帳單匯入 handler
+func NewBrokerStatementHandler(service service.BrokerStatementService) *BrokerStatementHandler {
+	return &BrokerStatementHandler{service: service}
+}
+
+// ListFormats 列出支援的對帳單格式
+// @Summary 列出支援的對帳單格式
+// @Description 列出可匯入的券商對帳單格式（元大、富邦、永豐金、Interactive Brokers、Firstrade、Binance）
+// @Tags transactions
+// @Produce json
+// @Success 200 {object} APIResponse{data=[]models.BrokerStatementFormatInfo}
+// @Router /api/transactions/import/formats [get]
+func (h *BrokerStatementHandler) ListFormats(c *gin.Context) {
+	c.JSON(http.StatusOK, APIResponse{
+		Data: h.service.Formats(),
+	})
+}
+
+// PreviewStatement 預覽券商對帳單匯入結果
+// @Summary 預覽券商對帳單
+// @Description 解析券商對帳單為交易資料並標示與既有交易重複的記錄（不寫入資料庫，確認後以批次建立交易 API 匯入）
+// @Tags transactions
+// @Accept multipart/form-data
+// @Produce json
+// @Param format formData string true "對帳單格式 (yuanta, fubon, sinopac, ibkr, firstrade, binance)"
+// @Param brokerage_account_id formData string false "套用至所有交易的券商帳戶 ID"
+// @Param file formData file true "對帳單檔案"
+// @Success 200 {object} APIResponse{data=models.BrokerStatementPreview}
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 500 {object} APIResponse{error=APIError}
+// @Router /api/transactions/import/preview [post]
+func (h *BrokerStatementHandler) PreviewStatement(c *gin.Context) {
+	format := models.BrokerStatementFormat(c.PostForm("format"))
+	if !format.Validate() {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Error: &APIError{
+				Code:    "INVALID_FORMAT",
+				Message: "format 應為 yuanta, fubon, sinopac, ibkr, firstrade 或 binance",
+			},
+		})
+		return
+	}
+
+	var brokerageAccountID *uuid.UUID
+	if value := c.PostForm("brokerage_account_id"); value != "" {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, APIResponse{
+				Error: &APIError{
+					Code:    "INVALID_ID",
+					Message: "Invalid brokerage account ID format",
+				},
+			})
+			return
+		}
+		brokerageAccountID = &id
+	}
+
+	// 取得上傳的檔案
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Error: &APIError{
+				Code:    "INVALID_FILE",
+				Message: "無法讀取上傳的檔案",
+			},
+		})
+		return
+	}
+
+	// 開啟檔案
+	f, err := file.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Error: &APIError{
+				Code:    "FILE_OPEN_ERROR",
+				Message: "無法開啟檔案",
+			},
+		})
+		return
+	}
+	defer f.Close()
+
+	preview, err := h.service.Preview(format, f, brokerageAccountID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Error: &APIError{
+				Code:    "PARSE_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Data: preview,
+	})
+}
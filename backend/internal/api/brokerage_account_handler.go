@@ -0,0 +1,276 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/chienchuanw/asset-manager/internal/models"
+	"github.com/chienchuanw/asset-manager/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// BrokerageAccountHandler 券商帳戶 API handler
+type BrokerageAccountHandler struct {
+	service service.BrokerageAccountService
+}
+
+// NewBrokerageAccountHandler 建立新的券商帳戶 handler
+func NewBrokerageAccountHandler(service service.BrokerageAccountService) *BrokerageAccountHandler {
+	return &BrokerageAccountHandler{service: service}
+}
+
+// CreateBrokerageAccount 建立新的券商帳戶
+// @Summary 建立券商帳戶
+// @Description 建立新的券商帳戶，可選擇性設定交割銀行帳戶
+// @Tags brokerage-accounts
+// @Accept json
+// @Produce json
+// @Param account body models.CreateBrokerageAccountInput true "券商帳戶資料"
+// @Success 201 {object} APIResponse{data=models.BrokerageAccount}
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 500 {object} APIResponse{error=APIError}
+// @Router /api/brokerage-accounts [post]
+func (h *BrokerageAccountHandler) CreateBrokerageAccount(c *gin.Context) {
+	var input models.CreateBrokerageAccountInput
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Error: &APIError{
+				Code:    "INVALID_INPUT",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	account, err := h.service.CreateBrokerageAccount(&input)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Error: &APIError{
+				Code:    "CREATE_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, APIResponse{
+		Data: account,
+	})
+}
+
+// GetBrokerageAccount 取得單筆券商帳戶
+// @Summary 取得券商帳戶
+// @Description 根據 ID 取得單筆券商帳戶
+// @Tags brokerage-accounts
+// @Produce json
+// @Param id path string true "券商帳戶 ID"
+// @Success 200 {object} APIResponse{data=models.BrokerageAccount}
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 404 {object} APIResponse{error=APIError}
+// @Router /api/brokerage-accounts/{id} [get]
+func (h *BrokerageAccountHandler) GetBrokerageAccount(c *gin.Context) {
+	id, ok := parseBrokerageAccountID(c)
+	if !ok {
+		return
+	}
+
+	account, err := h.service.GetBrokerageAccount(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, APIResponse{
+			Error: &APIError{
+				Code:    "NOT_FOUND",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Data: account,
+	})
+}
+
+// ListBrokerageAccounts 列出所有券商帳戶
+// @Summary 列出券商帳戶
+// @Description 列出所有券商帳戶
+// @Tags brokerage-accounts
+// @Produce json
+// @Success 200 {object} APIResponse{data=[]models.BrokerageAccount}
+// @Failure 500 {object} APIResponse{error=APIError}
+// @Router /api/brokerage-accounts [get]
+func (h *BrokerageAccountHandler) ListBrokerageAccounts(c *gin.Context) {
+	accounts, err := h.service.ListBrokerageAccounts()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Error: &APIError{
+				Code:    "LIST_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Data: accounts,
+	})
+}
+
+// UpdateBrokerageAccount 更新券商帳戶
+// @Summary 更新券商帳戶
+// @Description 更新券商帳戶資料
+// @Tags brokerage-accounts
+// @Accept json
+// @Produce json
+// @Param id path string true "券商帳戶 ID"
+// @Param account body models.UpdateBrokerageAccountInput true "更新資料"
+// @Success 200 {object} APIResponse{data=models.BrokerageAccount}
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 500 {object} APIResponse{error=APIError}
+// @Router /api/brokerage-accounts/{id} [put]
+func (h *BrokerageAccountHandler) UpdateBrokerageAccount(c *gin.Context) {
+	id, ok := parseBrokerageAccountID(c)
+	if !ok {
+		return
+	}
+
+	var input models.UpdateBrokerageAccountInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Error: &APIError{
+				Code:    "INVALID_INPUT",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	account, err := h.service.UpdateBrokerageAccount(id, &input)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Error: &APIError{
+				Code:    "UPDATE_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Data: account,
+	})
+}
+
+// DeleteBrokerageAccount 刪除券商帳戶
+// @Summary 刪除券商帳戶
+// @Description 刪除券商帳戶（交易記錄保留，僅解除關聯）
+// @Tags brokerage-accounts
+// @Produce json
+// @Param id path string true "券商帳戶 ID"
+// @Success 200 {object} APIResponse{data=map[string]string}
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 500 {object} APIResponse{error=APIError}
+// @Router /api/brokerage-accounts/{id} [delete]
+func (h *BrokerageAccountHandler) DeleteBrokerageAccount(c *gin.Context) {
+	id, ok := parseBrokerageAccountID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteBrokerageAccount(id); err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Error: &APIError{
+				Code:    "DELETE_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Data: map[string]string{
+			"message": "Brokerage account deleted successfully",
+		},
+	})
+}
+
+// GetMovements 取得券商帳戶的交割現金異動
+// @Summary 取得交割現金異動
+// @Description 取得券商帳戶由買賣、股息產生的交割現金異動
+// @Tags brokerage-accounts
+// @Produce json
+// @Param id path string true "券商帳戶 ID"
+// @Success 200 {object} APIResponse{data=[]models.BrokerageCashMovement}
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 500 {object} APIResponse{error=APIError}
+// @Router /api/brokerage-accounts/{id}/movements [get]
+func (h *BrokerageAccountHandler) GetMovements(c *gin.Context) {
+	id, ok := parseBrokerageAccountID(c)
+	if !ok {
+		return
+	}
+
+	movements, err := h.service.GetMovements(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Error: &APIError{
+				Code:    "GET_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Data: movements,
+	})
+}
+
+// GetHoldings 取得券商帳戶的持倉
+// @Summary 取得券商帳戶持倉
+// @Description 僅計算關聯此券商帳戶的交易
+// @Tags brokerage-accounts
+// @Produce json
+// @Param id path string true "券商帳戶 ID"
+// @Success 200 {object} APIResponse{data=[]models.Holding}
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 500 {object} APIResponse{error=APIError}
+// @Router /api/brokerage-accounts/{id}/holdings [get]
+func (h *BrokerageAccountHandler) GetHoldings(c *gin.Context) {
+	id, ok := parseBrokerageAccountID(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.service.GetHoldings(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Error: &APIError{
+				Code:    "GET_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":     result.Holdings,
+		"warnings": result.Warnings,
+		"error":    nil,
+	})
+}
+
+// parseBrokerageAccountID 解析路徑中的券商帳戶 ID，失敗時直接回應 400
+func parseBrokerageAccountID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Error: &APIError{
+				Code:    "INVALID_ID",
+				Message: "Invalid brokerage account ID format",
+			},
+		})
+		return uuid.Nil, false
+	}
+	return id, true
+}
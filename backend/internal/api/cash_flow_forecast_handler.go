@@ -0,0 +1,75 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/chienchuanw/asset-manager/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// CashFlowForecastHandler 現金流預測 API handler
+type CashFlowForecastHandler struct {
+	service service.CashFlowForecastService
+}
+
+// NewCashFlowForecastHandler 建立新的現金流預測 handler
+func NewCashFlowForecastHandler(service service.CashFlowForecastService) *CashFlowForecastHandler {
+	return &CashFlowForecastHandler{service: service}
+}
+
+// GetForecast 取得現金流預測
+// @Summary 取得現金流預測
+// @Description 依訂閱、分期、貸款、定期收支與信用卡繳款，預測未來各銀行帳戶的每日餘額與低餘額警示
+// @Tags cash-flows
+// @Produce json
+// @Param months query int false "預測月數（1-12，預設 3）"
+// @Param threshold query number false "低餘額警示門檻（TWD）"
+// @Success 200 {object} APIResponse{data=models.CashFlowForecast}
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 500 {object} APIResponse{error=APIError}
+// @Router /api/cash-flows/forecast [get]
+func (h *CashFlowForecastHandler) GetForecast(c *gin.Context) {
+	months := 0
+	if monthsStr := c.Query("months"); monthsStr != "" {
+		m, err := strconv.Atoi(monthsStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, APIResponse{
+				Error: &APIError{
+					Code:    "INVALID_INPUT",
+					Message: "Invalid months",
+				},
+			})
+			return
+		}
+		months = m
+	}
+
+	threshold := 0.0
+	if thresholdStr := c.Query("threshold"); thresholdStr != "" {
+		t, err := strconv.ParseFloat(thresholdStr, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, APIResponse{
+				Error: &APIError{
+					Code:    "INVALID_INPUT",
+					Message: "Invalid threshold",
+				},
+			})
+			return
+		}
+		threshold = t
+	}
+
+	forecast, err := h.service.GetForecast(months, threshold)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Error: &APIError{
+				Code:    "GET_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{Data: forecast})
+}
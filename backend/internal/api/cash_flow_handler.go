@@ -0,0 +1,733 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chienchuanw/asset-manager/internal/models"
+	"github.com/chienchuanw/asset-manager/internal/repository"
+	"github.com/chienchuanw/asset-manager/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// CashFlowHandler 現金流記錄 API handler
+type CashFlowHandler struct {
+	service        service.CashFlowService
+	discordService service.DiscordService
+}
+
+// NewCashFlowHandler 建立新的現金流記錄 handler
+func NewCashFlowHandler(service service.CashFlowService) *CashFlowHandler {
+	return &CashFlowHandler{
+		service:        service,
+		discordService: nil, // 預設為 nil，需要時再設定
+	}
+}
+
+// SetDiscordService 設定 Discord service（用於發送報告）
+func (h *CashFlowHandler) SetDiscordService(discordService service.DiscordService) {
+	h.discordService = discordService
+}
+
+// CreateCashFlow 建立新的現金流記錄
+// @Summary 建立現金流記錄
+// @Description 建立新的現金流記錄
+// @Tags cash-flows
+// @Accept json
+// @Produce json
+// @Param cash_flow body models.CreateCashFlowInput true "現金流記錄資料"
+// @Success 201 {object} APIResponse{data=models.CashFlow}
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 500 {object} APIResponse{error=APIError}
+// @Router /api/cash-flows [post]
+func (h *CashFlowHandler) CreateCashFlow(c *gin.Context) {
+	var input models.CreateCashFlowInput
+
+	// 綁定並驗證請求資料
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Error: &APIError{
+				Code:    "INVALID_INPUT",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	cashFlow, err := h.service.CreateCashFlow(&input)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		errorCode := "CREATE_FAILED"
+
+		if strings.Contains(err.Error(), "insufficient_balance") {
+			statusCode = http.StatusBadRequest
+			errorCode = "INSUFFICIENT_BALANCE"
+		} else if strings.Contains(err.Error(), "insufficient_credit") {
+			statusCode = http.StatusBadRequest
+			errorCode = "INSUFFICIENT_CREDIT"
+		}
+
+		c.JSON(statusCode, APIResponse{
+			Error: &APIError{
+				Code:    errorCode,
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, APIResponse{
+		Data: cashFlow,
+	})
+}
+
+// GetCashFlow 取得單筆現金流記錄
+// @Summary 取得現金流記錄
+// @Description 根據 ID 取得單筆現金流記錄
+// @Tags cash-flows
+// @Produce json
+// @Param id path string true "現金流記錄 ID"
+// @Success 200 {object} APIResponse{data=models.CashFlow}
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 404 {object} APIResponse{error=APIError}
+// @Router /api/cash-flows/{id} [get]
+func (h *CashFlowHandler) GetCashFlow(c *gin.Context) {
+	// 解析 ID
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Error: &APIError{
+				Code:    "INVALID_ID",
+				Message: "Invalid cash flow ID format",
+			},
+		})
+		return
+	}
+
+	// 呼叫 service 取得現金流記錄
+	cashFlow, err := h.service.GetCashFlow(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, APIResponse{
+			Error: &APIError{
+				Code:    "NOT_FOUND",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Data: cashFlow,
+	})
+}
+
+// ListCashFlows 取得現金流記錄列表
+// @Summary 取得現金流記錄列表
+// @Description 取得所有現金流記錄，支援篩選
+// @Tags cash-flows
+// @Produce json
+// @Param type query string false "現金流類型 (income/expense)"
+// @Param category_id query string false "分類 ID"
+// @Param start_date query string false "開始日期 (YYYY-MM-DD)"
+// @Param end_date query string false "結束日期 (YYYY-MM-DD)"
+// @Param limit query int false "每頁筆數"
+// @Param offset query int false "偏移量"
+// @Success 200 {object} APIResponse{data=[]models.CashFlow}
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Router /api/cash-flows [get]
+func (h *CashFlowHandler) ListCashFlows(c *gin.Context) {
+	filters, apiErr := parseCashFlowFilters(c)
+	if apiErr != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{Error: apiErr})
+		return
+	}
+
+	// 呼叫 service 取得現金流記錄列表
+	cashFlows, err := h.service.ListCashFlows(filters)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Error: &APIError{
+				Code:    "LIST_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Data: cashFlows,
+	})
+}
+
+// UpdateCashFlow 更新現金流記錄
+// @Summary 更新現金流記錄
+// @Description 更新現金流記錄
+// @Tags cash-flows
+// @Accept json
+// @Produce json
+// @Param id path string true "現金流記錄 ID"
+// @Param cash_flow body models.UpdateCashFlowInput true "更新資料"
+// @Success 200 {object} APIResponse{data=models.CashFlow}
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 404 {object} APIResponse{error=APIError}
+// @Router /api/cash-flows/{id} [put]
+func (h *CashFlowHandler) UpdateCashFlow(c *gin.Context) {
+	// 解析 ID
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Error: &APIError{
+				Code:    "INVALID_ID",
+				Message: "Invalid cash flow ID format",
+			},
+		})
+		return
+	}
+
+	var input models.UpdateCashFlowInput
+
+	// 綁定並驗證請求資料
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Error: &APIError{
+				Code:    "INVALID_INPUT",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	cashFlow, err := h.service.UpdateCashFlow(id, &input)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		errorCode := "UPDATE_FAILED"
+
+		if strings.Contains(err.Error(), "insufficient_balance") {
+			statusCode = http.StatusBadRequest
+			errorCode = "INSUFFICIENT_BALANCE"
+		} else if strings.Contains(err.Error(), "insufficient_credit") {
+			statusCode = http.StatusBadRequest
+			errorCode = "INSUFFICIENT_CREDIT"
+		}
+
+		c.JSON(statusCode, APIResponse{
+			Error: &APIError{
+				Code:    errorCode,
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Data: cashFlow,
+	})
+}
+
+// DeleteCashFlow 刪除現金流記錄
+// @Summary 刪除現金流記錄
+// @Description 刪除現金流記錄
+// @Tags cash-flows
+// @Produce json
+// @Param id path string true "現金流記錄 ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 404 {object} APIResponse{error=APIError}
+// @Router /api/cash-flows/{id} [delete]
+func (h *CashFlowHandler) DeleteCashFlow(c *gin.Context) {
+	// 解析 ID
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Error: &APIError{
+				Code:    "INVALID_ID",
+				Message: "Invalid cash flow ID format",
+			},
+		})
+		return
+	}
+
+	// 呼叫 service 刪除現金流記錄
+	if err := h.service.DeleteCashFlow(id); err != nil {
+		c.JSON(http.StatusNotFound, APIResponse{
+			Error: &APIError{
+				Code:    "DELETE_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetSummary 取得現金流摘要
+// @Summary 取得現金流摘要
+// @Description 取得指定日期範圍的現金流摘要統計
+// @Tags cash-flows
+// @Produce json
+// @Param start_date query string true "開始日期 (YYYY-MM-DD)"
+// @Param end_date query string true "結束日期 (YYYY-MM-DD)"
+// @Success 200 {object} APIResponse{data=repository.CashFlowSummary}
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Router /api/cash-flows/summary [get]
+func (h *CashFlowHandler) GetSummary(c *gin.Context) {
+	// 解析日期參數
+	startDateStr := c.Query("start_date")
+	endDateStr := c.Query("end_date")
+
+	if startDateStr == "" || endDateStr == "" {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Error: &APIError{
+				Code:    "MISSING_PARAMETERS",
+				Message: "start_date and end_date are required",
+			},
+		})
+		return
+	}
+
+	startDate, err := time.Parse("2006-01-02", startDateStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Error: &APIError{
+				Code:    "INVALID_START_DATE",
+				Message: "Invalid start date format, use YYYY-MM-DD",
+			},
+		})
+		return
+	}
+
+	endDate, err := time.Parse("2006-01-02", endDateStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Error: &APIError{
+				Code:    "INVALID_END_DATE",
+				Message: "Invalid end date format, use YYYY-MM-DD",
+			},
+		})
+		return
+	}
+
+	// 呼叫 service 取得摘要
+	summary, err := h.service.GetSummary(startDate, endDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Error: &APIError{
+				Code:    "SUMMARY_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Data: summary,
+	})
+}
+
+// GetMonthlySummary 取得月度現金流摘要（包含比較）
+// @Summary 取得月度現金流摘要
+// @Description 取得指定月份的現金流摘要，包含與前一個月的比較
+// @Tags cash-flows
+// @Accept json
+// @Produce json
+// @Param year query int true "年份"
+// @Param month query int true "月份 (1-12)"
+// @Success 200 {object} APIResponse{data=models.MonthlyCashFlowSummary}
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 500 {object} APIResponse{error=APIError}
+// @Router /api/cash-flows/monthly-summary [get]
+func (h *CashFlowHandler) GetMonthlySummary(c *gin.Context) {
+	// 解析年份參數
+	yearStr := c.Query("year")
+	if yearStr == "" {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Error: &APIError{
+				Code:    "MISSING_YEAR",
+				Message: "year parameter is required",
+			},
+		})
+		return
+	}
+
+	year, err := strconv.Atoi(yearStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Error: &APIError{
+				Code:    "INVALID_YEAR",
+				Message: "year must be a valid integer",
+			},
+		})
+		return
+	}
+
+	// 解析月份參數
+	monthStr := c.Query("month")
+	if monthStr == "" {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Error: &APIError{
+				Code:    "MISSING_MONTH",
+				Message: "month parameter is required",
+			},
+		})
+		return
+	}
+
+	month, err := strconv.Atoi(monthStr)
+	if err != nil || month < 1 || month > 12 {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Error: &APIError{
+				Code:    "INVALID_MONTH",
+				Message: "month must be between 1 and 12",
+			},
+		})
+		return
+	}
+
+	// 呼叫 service 取得月度摘要
+	summary, err := h.service.GetMonthlySummaryWithComparison(year, month)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Error: &APIError{
+				Code:    "MONTHLY_SUMMARY_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Data: summary,
+	})
+}
+
+// GetYearlySummary 取得年度現金流摘要（包含比較）
+// @Summary 取得年度現金流摘要
+// @Description 取得指定年度的現金流摘要，包含與前一年的比較
+// @Tags cash-flows
+// @Accept json
+// @Produce json
+// @Param year query int true "年份"
+// @Success 200 {object} APIResponse{data=models.YearlyCashFlowSummary}
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 500 {object} APIResponse{error=APIError}
+// @Router /api/cash-flows/yearly-summary [get]
+func (h *CashFlowHandler) GetYearlySummary(c *gin.Context) {
+	// 解析年份參數
+	yearStr := c.Query("year")
+	if yearStr == "" {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Error: &APIError{
+				Code:    "MISSING_YEAR",
+				Message: "year parameter is required",
+			},
+		})
+		return
+	}
+
+	year, err := strconv.Atoi(yearStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Error: &APIError{
+				Code:    "INVALID_YEAR",
+				Message: "year must be a valid integer",
+			},
+		})
+		return
+	}
+
+	// 呼叫 service 取得年度摘要
+	summary, err := h.service.GetYearlySummaryWithComparison(year)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Error: &APIError{
+				Code:    "YEARLY_SUMMARY_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Data: summary,
+	})
+}
+
+// SendMonthlyReport 手動發送月度現金流報告到 Discord
+// @Summary 發送月度現金流報告
+// @Description 手動觸發發送指定月份的現金流報告到 Discord
+// @Tags cash-flows
+// @Accept json
+// @Produce json
+// @Param year query int true "年份"
+// @Param month query int true "月份 (1-12)"
+// @Success 200 {object} APIResponse{data=string}
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 500 {object} APIResponse{error=APIError}
+// @Router /api/cash-flows/send-monthly-report [post]
+func (h *CashFlowHandler) SendMonthlyReport(c *gin.Context) {
+	if h.discordService == nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Error: &APIError{
+				Code:    "DISCORD_SERVICE_NOT_CONFIGURED",
+				Message: "Discord service is not configured",
+			},
+		})
+		return
+	}
+
+	// 解析年份參數
+	yearStr := c.Query("year")
+	if yearStr == "" {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Error: &APIError{
+				Code:    "MISSING_YEAR",
+				Message: "year parameter is required",
+			},
+		})
+		return
+	}
+
+	year, err := strconv.Atoi(yearStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Error: &APIError{
+				Code:    "INVALID_YEAR",
+				Message: "year must be a valid integer",
+			},
+		})
+		return
+	}
+
+	// 解析月份參數
+	monthStr := c.Query("month")
+	if monthStr == "" {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Error: &APIError{
+				Code:    "MISSING_MONTH",
+				Message: "month parameter is required",
+			},
+		})
+		return
+	}
+
+	month, err := strconv.Atoi(monthStr)
+	if err != nil || month < 1 || month > 12 {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Error: &APIError{
+				Code:    "INVALID_MONTH",
+				Message: "month must be between 1 and 12",
+			},
+		})
+		return
+	}
+
+	// 取得月度摘要
+	summary, err := h.service.GetMonthlySummaryWithComparison(year, month)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Error: &APIError{
+				Code:    "MONTHLY_SUMMARY_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	// 格式化 Discord 訊息
+	message := h.discordService.FormatMonthlyCashFlowReport(summary)
+
+	// 取得設定（需要 webhook URL）
+	// 這裡簡化處理，實際應該從 settings service 取得
+	// 暫時使用環境變數或預設值
+	webhookURL := c.Query("webhook_url")
+	if webhookURL == "" {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Error: &APIError{
+				Code:    "MISSING_WEBHOOK_URL",
+				Message: "webhook_url parameter is required",
+			},
+		})
+		return
+	}
+
+	// 發送訊息
+	if err := h.discordService.SendMessage(webhookURL, message); err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Error: &APIError{
+				Code:    "SEND_MESSAGE_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Data: "Monthly report sent successfully",
+	})
+}
+
+// SendYearlyReport 手動發送年度現金流報告到 Discord
+// @Summary 發送年度現金流報告
+// @Description 手動觸發發送指定年度的現金流報告到 Discord
+// @Tags cash-flows
+// @Accept json
+// @Produce json
+// @Param year query int true "年份"
+// @Success 200 {object} APIResponse{data=string}
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 500 {object} APIResponse{error=APIError}
+// @Router /api/cash-flows/send-yearly-report [post]
+func (h *CashFlowHandler) SendYearlyReport(c *gin.Context) {
+	if h.discordService == nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Error: &APIError{
+				Code:    "DISCORD_SERVICE_NOT_CONFIGURED",
+				Message: "Discord service is not configured",
+			},
+		})
+		return
+	}
+
+	// 解析年份參數
+	yearStr := c.Query("year")
+	if yearStr == "" {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Error: &APIError{
+				Code:    "MISSING_YEAR",
+				Message: "year parameter is required",
+			},
+		})
+		return
+	}
+
+	year, err := strconv.Atoi(yearStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Error: &APIError{
+				Code:    "INVALID_YEAR",
+				Message: "year must be a valid integer",
+			},
+		})
+		return
+	}
+
+	// 取得年度摘要
+	summary, err := h.service.GetYearlySummaryWithComparison(year)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Error: &APIError{
+				Code:    "YEARLY_SUMMARY_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	// 格式化 Discord 訊息
+	message := h.discordService.FormatYearlyCashFlowReport(summary)
+
+	// 取得設定（需要 webhook URL）
+	webhookURL := c.Query("webhook_url")
+	if webhookURL == "" {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Error: &APIError{
+				Code:    "MISSING_WEBHOOK_URL",
+				Message: "webhook_url parameter is required",
+			},
+		})
+		return
+	}
+
+	// 發送訊息
+	if err := h.discordService.SendMessage(webhookURL, message); err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Error: &APIError{
+				Code:    "SEND_MESSAGE_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Data: "Yearly report sent successfully",
+	})
+}
+
+// parseCashFlowFilters 解析現金流列表的篩選條件（列表與匯出共用）
+func parseCashFlowFilters(c *gin.Context) (repository.CashFlowFilters, *APIError) {
+	filters := repository.CashFlowFilters{}
+
+	// 類型篩選
+	if typeStr := c.Query("type"); typeStr != "" {
+		flowType := models.CashFlowType(typeStr)
+		filters.Type = &flowType
+	}
+
+	// 分類篩選
+	if categoryIDStr := c.Query("category_id"); categoryIDStr != "" {
+		categoryID, err := uuid.Parse(categoryIDStr)
+		if err != nil {
+			return filters, &APIError{
+				Code:    "INVALID_CATEGORY_ID",
+				Message: "Invalid category ID format",
+			}
+		}
+		filters.CategoryID = &categoryID
+	}
+
+	// 日期範圍篩選
+	if startDateStr := c.Query("start_date"); startDateStr != "" {
+		startDate, err := time.Parse("2006-01-02", startDateStr)
+		if err != nil {
+			return filters, &APIError{
+				Code:    "INVALID_START_DATE",
+				Message: "Invalid start date format, use YYYY-MM-DD",
+			}
+		}
+		filters.StartDate = &startDate
+	}
+
+	if endDateStr := c.Query("end_date"); endDateStr != "" {
+		endDate, err := time.Parse("2006-01-02", endDateStr)
+		if err != nil {
+			return filters, &APIError{
+				Code:    "INVALID_END_DATE",
+				Message: "Invalid end date format, use YYYY-MM-DD",
+			}
+		}
+		filters.EndDate = &endDate
+	}
+
+	// 分頁參數
+	if limitStr := c.Query("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 0 {
+			return filters, &APIError{
+				Code:    "INVALID_LIMIT",
+				Message: "Invalid limit parameter",
+			}
+		}
+		filters.Limit = limit
+	}
+
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		offset, err := strconv.Atoi(offsetStr)
+		if err != nil || offset < 0 {
+			return filters, &APIError{
+				Code:    "INVALID_OFFSET",
+				Message: "Invalid offset parameter",
+			}
+		}
+		filters.Offset = offset
+	}
+
+	return filters, nil
+}
@@ -0,0 +1,322 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/chienchuanw/asset-manager/internal/models"
+	"github.com/chienchuanw/asset-manager/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// CreditCardRewardHandler 信用卡回饋 API handler
+type CreditCardRewardHandler struct {
+	service service.CreditCardRewardService
+}
+
+// NewCreditCardRewardHandler 建立新的信用卡回饋 handler
+func NewCreditCardRewardHandler(service service.CreditCardRewardService) *CreditCardRewardHandler {
+	return &CreditCardRewardHandler{service: service}
+}
+
+// parseRewardRuleID 解析路徑中的回饋規則 ID，失敗時回傳 400
+func parseRewardRuleID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("rule_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Error: &APIError{
+				Code:    "INVALID_ID",
+				Message: "Invalid reward rule ID format",
+			},
+		})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// CreateRule 建立回饋規則
+// @Summary 建立信用卡回饋規則
+// @Description 建立一般回饋、指定分類或指定商家的加碼回饋規則，可設定每期回饋上限
+// @Tags credit-cards
+// @Accept json
+// @Produce json
+// @Param id path string true "信用卡 ID"
+// @Param rule body models.CreateCreditCardRewardRuleInput true "回饋規則"
+// @Success 201 {object} APIResponse{data=models.CreditCardRewardRule}
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 500 {object} APIResponse{error=APIError}
+// @Router /api/credit-cards/{id}/reward-rules [post]
+func (h *CreditCardRewardHandler) CreateRule(c *gin.Context) {
+	id, ok := parseCreditCardID(c)
+	if !ok {
+		return
+	}
+
+	var input models.CreateCreditCardRewardRuleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Error: &APIError{
+				Code:    "INVALID_INPUT",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	rule, err := h.service.CreateRule(id, &input)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Error: &APIError{
+				Code:    "CREATE_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, APIResponse{
+		Data: rule,
+	})
+}
+
+// ListRules 取得回饋規則列表
+// @Summary 取得信用卡回饋規則
+// @Description 取得信用卡的所有回饋規則
+// @Tags credit-cards
+// @Produce json
+// @Param id path string true "信用卡 ID"
+// @Success 200 {object} APIResponse{data=[]models.CreditCardRewardRule}
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 500 {object} APIResponse{error=APIError}
+// @Router /api/credit-cards/{id}/reward-rules [get]
+func (h *CreditCardRewardHandler) ListRules(c *gin.Context) {
+	id, ok := parseCreditCardID(c)
+	if !ok {
+		return
+	}
+
+	rules, err := h.service.ListRules(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Error: &APIError{
+				Code:    "LIST_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Data: rules,
+	})
+}
+
+// UpdateRule 更新回饋規則
+// @Summary 更新信用卡回饋規則
+// @Description 更新回饋比例、上限或停用規則
+// @Tags credit-cards
+// @Accept json
+// @Produce json
+// @Param id path string true "信用卡 ID"
+// @Param rule_id path string true "回饋規則 ID"
+// @Param rule body models.UpdateCreditCardRewardRuleInput true "更新資料"
+// @Success 200 {object} APIResponse{data=models.CreditCardRewardRule}
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 500 {object} APIResponse{error=APIError}
+// @Router /api/credit-cards/{id}/reward-rules/{rule_id} [put]
+func (h *CreditCardRewardHandler) UpdateRule(c *gin.Context) {
+	id, ok := parseCreditCardID(c)
+	if !ok {
+		return
+	}
+
+	ruleID, ok := parseRewardRuleID(c)
+	if !ok {
+		return
+	}
+
+	var input models.UpdateCreditCardRewardRuleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Error: &APIError{
+				Code:    "INVALID_INPUT",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	rule, err := h.service.UpdateRule(id, ruleID, &input)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Error: &APIError{
+				Code:    "UPDATE_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Data: rule,
+	})
+}
+
+// DeleteRule 刪除回饋規則
+// @Summary 刪除信用卡回饋規則
+// @Description 刪除信用卡的回饋規則
+// @Tags credit-cards
+// @Produce json
+// @Param id path string true "信用卡 ID"
+// @Param rule_id path string true "回饋規則 ID"
+// @Success 200 {object} APIResponse{data=string}
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 500 {object} APIResponse{error=APIError}
+// @Router /api/credit-cards/{id}/reward-rules/{rule_id} [delete]
+func (h *CreditCardRewardHandler) DeleteRule(c *gin.Context) {
+	id, ok := parseCreditCardID(c)
+	if !ok {
+		return
+	}
+
+	ruleID, ok := parseRewardRuleID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteRule(id, ruleID); err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Error: &APIError{
+				Code:    "DELETE_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Data: "Reward rule deleted successfully",
+	})
+}
+
+// GetRewardReport 取得回饋報表
+// @Summary 取得信用卡回饋報表
+// @Description 依結帳週期統計每筆刷卡消費的預估回饋、實得回饋與超過上限的回饋（預設為今年至今）
+// @Tags credit-cards
+// @Produce json
+// @Param id path string true "信用卡 ID"
+// @Param start_date query string false "開始日期 (YYYY-MM-DD)"
+// @Param end_date query string false "結束日期 (YYYY-MM-DD)"
+// @Success 200 {object} APIResponse{data=models.CreditCardRewardReport}
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 500 {object} APIResponse{error=APIError}
+// @Router /api/credit-cards/{id}/rewards [get]
+func (h *CreditCardRewardHandler) GetRewardReport(c *gin.Context) {
+	id, ok := parseCreditCardID(c)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	startDate := time.Date(now.Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	if startDateStr := c.Query("start_date"); startDateStr != "" {
+		parsed, err := time.Parse("2006-01-02", startDateStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, APIResponse{
+				Error: &APIError{
+					Code:    "INVALID_START_DATE",
+					Message: "Invalid start date format, use YYYY-MM-DD",
+				},
+			})
+			return
+		}
+		startDate = parsed
+	}
+
+	if endDateStr := c.Query("end_date"); endDateStr != "" {
+		parsed, err := time.Parse("2006-01-02", endDateStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, APIResponse{
+				Error: &APIError{
+					Code:    "INVALID_END_DATE",
+					Message: "Invalid end date format, use YYYY-MM-DD",
+				},
+			})
+			return
+		}
+		endDate = parsed
+	}
+
+	report, err := h.service.GetRewardReport(id, startDate, endDate)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Error: &APIError{
+				Code:    "GET_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Data: report,
+	})
+}
+
+// RecommendCard 推薦回饋最高的信用卡
+// @Summary 最佳刷卡推薦
+// @Description 依支出分類（與商家）試算各信用卡回饋，考慮本期剩餘回饋上限後推薦回饋最高的卡片
+// @Tags credit-cards
+// @Produce json
+// @Param category_id query string true "支出分類 ID"
+// @Param merchant query string false "商家名稱"
+// @Param amount query number false "消費金額（預設 1000）"
+// @Success 200 {object} APIResponse{data=models.CreditCardRewardRecommendation}
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 500 {object} APIResponse{error=APIError}
+// @Router /api/credit-card-rewards/recommendation [get]
+func (h *CreditCardRewardHandler) RecommendCard(c *gin.Context) {
+	categoryID, err := uuid.Parse(c.Query("category_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Error: &APIError{
+				Code:    "INVALID_CATEGORY_ID",
+				Message: "Invalid category ID format",
+			},
+		})
+		return
+	}
+
+	amount := 0.0
+	if amountStr := c.Query("amount"); amountStr != "" {
+		amount, err = strconv.ParseFloat(amountStr, 64)
+		if err != nil || amount < 0 {
+			c.JSON(http.StatusBadRequest, APIResponse{
+				Error: &APIError{
+					Code:    "INVALID_INPUT",
+					Message: "amount must be a valid positive number",
+				},
+			})
+			return
+		}
+	}
+
+	recommendation, err := h.service.RecommendCard(categoryID, c.Query("merchant"), amount, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Error: &APIError{
+				Code:    "GET_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Data: recommendation,
+	})
+}
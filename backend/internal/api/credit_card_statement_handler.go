@@ -0,0 +1,153 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/chienchuanw/asset-manager/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// CreditCardStatementHandler 信用卡帳單 API handler
+type CreditCardStatementHandler struct {
+	service service.CreditCardStatementService
+}
+
+// NewCreditCardStatementHandler 建立新的信用卡帳單 handler
+func NewCreditCardStatementHandler(service service.CreditCardStatementService) *CreditCardStatementHandler {
+	return &CreditCardStatementHandler{service: service}
+}
+
+// parseCreditCardID 解析路徑中的信用卡 ID，失敗時回傳 400
+func parseCreditCardID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Error: &APIError{
+				Code:    "INVALID_ID",
+				Message: "Invalid credit card ID format",
+			},
+		})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// ListStatements 取得信用卡帳單列表
+// @Summary 取得信用卡帳單列表
+// @Description 依帳單日切分結帳週期，產生並取得所有已結帳的帳單（由新到舊）
+// @Tags credit-cards
+// @Produce json
+// @Param id path string true "信用卡 ID"
+// @Success 200 {object} APIResponse{data=[]models.CreditCardStatement}
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 500 {object} APIResponse{error=APIError}
+// @Router /api/credit-cards/{id}/statements [get]
+func (h *CreditCardStatementHandler) ListStatements(c *gin.Context) {
+	id, ok := parseCreditCardID(c)
+	if !ok {
+		return
+	}
+
+	statements, err := h.service.ListStatements(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Error: &APIError{
+				Code:    "LIST_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Data: statements,
+	})
+}
+
+// GetCurrentStatement 取得最近一期帳單
+// @Summary 取得最近一期信用卡帳單
+// @Description 取得最近一期已結帳的帳單（應繳總額、最低應繳與繳款狀態）
+// @Tags credit-cards
+// @Produce json
+// @Param id path string true "信用卡 ID"
+// @Success 200 {object} APIResponse{data=models.CreditCardStatement}
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 404 {object} APIResponse{error=APIError}
+// @Failure 500 {object} APIResponse{error=APIError}
+// @Router /api/credit-cards/{id}/statements/current [get]
+func (h *CreditCardStatementHandler) GetCurrentStatement(c *gin.Context) {
+	id, ok := parseCreditCardID(c)
+	if !ok {
+		return
+	}
+
+	statement, err := h.service.GetCurrentStatement(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Error: &APIError{
+				Code:    "GET_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	if statement == nil {
+		c.JSON(http.StatusNotFound, APIResponse{
+			Error: &APIError{
+				Code:    "NOT_FOUND",
+				Message: "No closed statement yet",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Data: statement,
+	})
+}
+
+// GetStatement 取得單筆帳單
+// @Summary 取得信用卡帳單
+// @Description 取得單筆帳單與週期內的交易明細
+// @Tags credit-cards
+// @Produce json
+// @Param id path string true "信用卡 ID"
+// @Param statement_id path string true "帳單 ID"
+// @Success 200 {object} APIResponse{data=models.CreditCardStatement}
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 404 {object} APIResponse{error=APIError}
+// @Router /api/credit-cards/{id}/statements/{statement_id} [get]
+func (h *CreditCardStatementHandler) GetStatement(c *gin.Context) {
+	id, ok := parseCreditCardID(c)
+	if !ok {
+		return
+	}
+
+	statementID, err := uuid.Parse(c.Param("statement_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Error: &APIError{
+				Code:    "INVALID_ID",
+				Message: "Invalid statement ID format",
+			},
+		})
+		return
+	}
+
+	statement, err := h.service.GetStatement(id, statementID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, APIResponse{
+			Error: &APIError{
+				Code:    "NOT_FOUND",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Data: statement,
+	})
+}
@@ -0,0 +1,235 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/chienchuanw/asset-manager/internal/models"
+	"github.com/chienchuanw/asset-manager/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// CustodyAccountHandler 保管帳戶 API handler
+type CustodyAccountHandler struct {
+	service service.CustodyAccountService
+}
+
+// NewCustodyAccountHandler 建立新的保管帳戶 handler
+func NewCustodyAccountHandler(service service.CustodyAccountService) *CustodyAccountHandler {
+	return &CustodyAccountHandler{service: service}
+}
+
+// CreateCustodyAccount 建立新的保管帳戶
+// @Summary 建立保管帳戶
+// @Description 建立新的加密貨幣保管帳戶（交易所、錢包或質押）
+// @Tags custody-accounts
+// @Accept json
+// @Produce json
+// @Param account body models.CreateCustodyAccountInput true "保管帳戶資料"
+// @Success 201 {object} APIResponse{data=models.CustodyAccount}
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 500 {object} APIResponse{error=APIError}
+// @Router /api/custody-accounts [post]
+func (h *CustodyAccountHandler) CreateCustodyAccount(c *gin.Context) {
+	var input models.CreateCustodyAccountInput
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Error: &APIError{
+				Code:    "INVALID_INPUT",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	account, err := h.service.CreateCustodyAccount(&input)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Error: &APIError{
+				Code:    "CREATE_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, APIResponse{
+		Data: account,
+	})
+}
+
+// GetCustodyAccount 取得單筆保管帳戶
+// @Summary 取得保管帳戶
+// @Description 根據 ID 取得單筆保管帳戶
+// @Tags custody-accounts
+// @Produce json
+// @Param id path string true "保管帳戶 ID"
+// @Success 200 {object} APIResponse{data=models.CustodyAccount}
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 404 {object} APIResponse{error=APIError}
+// @Router /api/custody-accounts/{id} [get]
+func (h *CustodyAccountHandler) GetCustodyAccount(c *gin.Context) {
+	id, ok := parseCustodyAccountID(c)
+	if !ok {
+		return
+	}
+
+	account, err := h.service.GetCustodyAccount(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, APIResponse{
+			Error: &APIError{
+				Code:    "NOT_FOUND",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Data: account,
+	})
+}
+
+// ListCustodyAccounts 列出所有保管帳戶
+// @Summary 列出保管帳戶
+// @Description 列出所有保管帳戶
+// @Tags custody-accounts
+// @Produce json
+// @Success 200 {object} APIResponse{data=[]models.CustodyAccount}
+// @Failure 500 {object} APIResponse{error=APIError}
+// @Router /api/custody-accounts [get]
+func (h *CustodyAccountHandler) ListCustodyAccounts(c *gin.Context) {
+	accounts, err := h.service.ListCustodyAccounts()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Error: &APIError{
+				Code:    "LIST_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Data: accounts,
+	})
+}
+
+// UpdateCustodyAccount 更新保管帳戶
+// @Summary 更新保管帳戶
+// @Description 更新保管帳戶資料
+// @Tags custody-accounts
+// @Accept json
+// @Produce json
+// @Param id path string true "保管帳戶 ID"
+// @Param account body models.UpdateCustodyAccountInput true "更新資料"
+// @Success 200 {object} APIResponse{data=models.CustodyAccount}
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 500 {object} APIResponse{error=APIError}
+// @Router /api/custody-accounts/{id} [put]
+func (h *CustodyAccountHandler) UpdateCustodyAccount(c *gin.Context) {
+	id, ok := parseCustodyAccountID(c)
+	if !ok {
+		return
+	}
+
+	var input models.UpdateCustodyAccountInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Error: &APIError{
+				Code:    "INVALID_INPUT",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	account, err := h.service.UpdateCustodyAccount(id, &input)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Error: &APIError{
+				Code:    "UPDATE_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Data: account,
+	})
+}
+
+// DeleteCustodyAccount 刪除保管帳戶
+// @Summary 刪除保管帳戶
+// @Description 刪除保管帳戶（交易記錄保留，僅解除關聯）
+// @Tags custody-accounts
+// @Produce json
+// @Param id path string true "保管帳戶 ID"
+// @Success 200 {object} APIResponse{data=map[string]string}
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 500 {object} APIResponse{error=APIError}
+// @Router /api/custody-accounts/{id} [delete]
+func (h *CustodyAccountHandler) DeleteCustodyAccount(c *gin.Context) {
+	id, ok := parseCustodyAccountID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteCustodyAccount(id); err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Error: &APIError{
+				Code:    "DELETE_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Data: map[string]string{
+			"message": "Custody account deleted successfully",
+		},
+	})
+}
+
+// GetHoldingsByCustody 依保管位置取得加密貨幣持倉
+// @Summary 依保管位置取得持倉
+// @Description 列出各交易所、錢包與質押位置的加密貨幣持倉，成本依整體 FIFO 持倉按數量分攤
+// @Tags custody-accounts
+// @Produce json
+// @Success 200 {object} APIResponse{data=[]models.CustodyHolding}
+// @Failure 500 {object} APIResponse{error=APIError}
+// @Router /api/custody-accounts/holdings [get]
+func (h *CustodyAccountHandler) GetHoldingsByCustody(c *gin.Context) {
+	holdings, err := h.service.GetHoldingsByCustody()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Error: &APIError{
+				Code:    "GET_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Data: holdings,
+	})
+}
+
+// parseCustodyAccountID 解析路徑中的保管帳戶 ID，失敗時直接回應 400
+func parseCustodyAccountID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Error: &APIError{
+				Code:    "INVALID_ID",
+				Message: "Invalid custody account ID format",
+			},
+		})
+		return uuid.Nil, false
+	}
+	return id, true
+}
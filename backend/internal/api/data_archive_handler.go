@@ -0,0 +1,175 @@
+package api
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/chienchuanw/asset-manager/internal/models"
+	"github.com/chienchuanw/asset-manager/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// dataArchiveFormOverhead 上傳備份檔時 multipart 表單標頭等額外內容的大小上限
+const dataArchiveFormOverhead int64 = 1 << 20
+
+// DataArchiveHandler 完整資料備份與還原 API handler
+type DataArchiveHandler struct {
+	service       service.DataArchiveService
+	maxUploadSize int64
+}
+
+// NewDataArchiveHandler 建立新的資料備份 handler
+func NewDataArchiveHandler(archiveService service.DataArchiveService) *DataArchiveHandler {
+	return &DataArchiveHandler{service: archiveService, maxUploadSize: service.MaxDataArchiveSize + dataArchiveFormOverhead}
+}
+
+// ExportData 匯出完整資料備份
+// @Summary 匯出完整資料備份
+// @Description 匯出所有資料（交易、現金流、分類、帳戶、信用卡、訂閱、分期、設定、快照、匯率等）為含版本資訊的 JSON 或 ZIP 備份檔
+// @Tags backup
+// @Produce application/json,application/zip
+// @Param format query string false "備份檔格式 (json, zip)，預設 json"
+// @Success 200 {file} file "備份檔"
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 500 {object} APIResponse{error=APIError}
+// @Router /api/export [get]
+func (h *DataArchiveHandler) ExportData(c *gin.Context) {
+	format := models.DataArchiveFormat(c.DefaultQuery("format", string(models.DataArchiveFormatJSON)))
+	if !format.Validate() {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Error: &APIError{
+				Code:    "INVALID_FORMAT",
+				Message: "format 應為 json 或 zip",
+			},
+		})
+		return
+	}
+
+	archive, err := h.service.Export()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Error: &APIError{
+				Code:    "EXPORT_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	// 先寫入緩衝區，失敗時仍可回傳 JSON 錯誤
+	var buf bytes.Buffer
+	if err := h.service.WriteArchive(&buf, archive, format); err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Error: &APIError{
+				Code:    "EXPORT_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	contentType := "application/json"
+	if format == models.DataArchiveFormatZIP {
+		contentType = "application/zip"
+	}
+	filename := fmt.Sprintf("asset-manager-backup-%s.%s", archive.Manifest.CreatedAt.Format("20060102-150405"), format)
+	c.Header("Content-Disposition", "attachment; filename="+filename)
+	c.Data(http.StatusOK, contentType, buf.Bytes())
+}
+
+// ImportData 還原完整資料備份
+// @Summary 還原完整資料備份
+// @Description 將 JSON 或 ZIP 備份檔還原至空的資料庫（重新產生所有 ID 並改寫關聯；備份檔的 schema 版本必須與資料庫相同）
+// @Tags backup
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "備份檔"
+// @Success 200 {object} APIResponse{data=models.DataRestoreResult}
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 409 {object} APIResponse{error=APIError}
+// @Failure 413 {object} APIResponse{error=APIError}
+// @Failure 500 {object} APIResponse{error=APIError}
+// @Router /api/import [post]
+func (h *DataArchiveHandler) ImportData(c *gin.Context) {
+	// 限制請求大小（保留 multipart 標頭的空間）
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.maxUploadSize)
+
+	// 取得上傳的檔案
+	file, err := c.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			respondDataArchiveTooLarge(c)
+			return
+		}
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Error: &APIError{
+				Code:    "INVALID_FILE",
+				Message: "無法讀取上傳的檔案",
+			},
+		})
+		return
+	}
+
+	// 開啟檔案
+	f, err := file.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Error: &APIError{
+				Code:    "FILE_OPEN_ERROR",
+				Message: "無法開啟檔案",
+			},
+		})
+		return
+	}
+	defer f.Close()
+
+	archive, err := h.service.ReadArchive(f)
+	if err != nil {
+		if errors.Is(err, service.ErrDataArchiveTooLarge) {
+			respondDataArchiveTooLarge(c)
+			return
+		}
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Error: &APIError{
+				Code:    "INVALID_ARCHIVE",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	result, err := h.service.Restore(archive)
+	if err != nil {
+		status, code := http.StatusInternalServerError, "RESTORE_FAILED"
+		switch {
+		case errors.Is(err, service.ErrDataArchiveIncompatible):
+			status, code = http.StatusBadRequest, "INCOMPATIBLE_ARCHIVE"
+		case errors.Is(err, service.ErrDataRestoreNotEmpty):
+			status, code = http.StatusConflict, "DATABASE_NOT_EMPTY"
+		}
+		c.JSON(status, APIResponse{
+			Error: &APIError{
+				Code:    code,
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Data: result,
+	})
+}
+
+// respondDataArchiveTooLarge 回傳備份檔超過大小上限的錯誤
+func respondDataArchiveTooLarge(c *gin.Context) {
+	c.JSON(http.StatusRequestEntityTooLarge, APIResponse{
+		Error: &APIError{
+			Code:    "FILE_TOO_LARGE",
+			Message: fmt.Sprintf("備份檔不得超過 %d MB", service.MaxDataArchiveSize>>20),
+		},
+	})
+}
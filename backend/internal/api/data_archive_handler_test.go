@@ -0,0 +1,113 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/chienchuanw/asset-manager/internal/models"
+	"github.com/chienchuanw/asset-manager/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockDataArchiveService 是 DataArchiveService 的 mock 實作
+type MockDataArchiveService struct {
+	mock.Mock
+}
+
+func (m *MockDataArchiveService) Export() (*models.DataArchive, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DataArchive), args.Error(1)
+}
+
+func (m *MockDataArchiveService) WriteArchive(w io.Writer, archive *models.DataArchive, format models.DataArchiveFormat) error {
+	args := m.Called(w, archive, format)
+	return args.Error(0)
+}
+
+func (m *MockDataArchiveService) ReadArchive(r io.Reader) (*models.DataArchive, error) {
+	args := m.Called(r)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DataArchive), args.Error(1)
+}
+
+func (m *MockDataArchiveService) Restore(archive *models.DataArchive) (*models.DataRestoreResult, error) {
+	args := m.Called(archive)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DataRestoreResult), args.Error(1)
+}
+
+// newDataArchiveImportRequest 建立上傳備份檔的 multipart 請求
+func newDataArchiveImportRequest(t *testing.T, content []byte) *http.Request {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("file", "backup.json")
+	require.NoError(t, err)
+	_, err = part.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	req, _ := http.NewRequest("POST", "/api/import", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+// TestImportData_RequestTooLarge 測試上傳超過大小上限的請求
+func TestImportData_RequestTooLarge(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockDataArchiveService)
+	handler := &DataArchiveHandler{service: mockService, maxUploadSize: 1 << 10}
+	router := gin.New()
+	router.POST("/api/import", handler.ImportData)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, newDataArchiveImportRequest(t, bytes.Repeat([]byte("x"), 4<<10)))
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+
+	var response APIResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.NotNil(t, response.Error)
+	assert.Equal(t, "FILE_TOO_LARGE", response.Error.Code)
+	mockService.AssertNotCalled(t, "ReadArchive", mock.Anything)
+}
+
+// TestImportData_ArchiveTooLarge 測試備份檔（或解壓縮後的內容）超過大小上限
+func TestImportData_ArchiveTooLarge(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockDataArchiveService)
+	mockService.On("ReadArchive", mock.Anything).Return(nil, fmt.Errorf("%w: manifest.json exceeds 10 bytes", service.ErrDataArchiveTooLarge))
+
+	handler := NewDataArchiveHandler(mockService)
+	router := gin.New()
+	router.POST("/api/import", handler.ImportData)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, newDataArchiveImportRequest(t, []byte("PK\x03\x04")))
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+
+	var response APIResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.NotNil(t, response.Error)
+	assert.Equal(t, "FILE_TOO_LARGE", response.Error.Code)
+	mockService.AssertNotCalled(t, "Restore", mock.Anything)
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,204 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/chienchuanw/asset-manager/internal/middleware"
+	"github.com/chienchuanw/asset-manager/internal/models"
+	"github.com/chienchuanw/asset-manager/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// ExportHandler 報表匯出 API handler（CSV / XLSX）
+type ExportHandler struct {
+	service service.ExportService
+}
+
+// NewExportHandler 建立新的報表匯出 handler
+func NewExportHandler(service service.ExportService) *ExportHandler {
+	return &ExportHandler{service: service}
+}
+
+// ExportCashFlows 匯出現金流記錄
+// @Summary 匯出現金流記錄
+// @Description 以 CSV 或 XLSX 匯出現金流記錄（篩選條件與現金流列表相同，標題依語系翻譯）
+// @Tags cash-flows
+// @Produce text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param format query string false "檔案格式 (csv, xlsx)，預設 csv"
+// @Param type query string false "現金流類型 (income, expense, transfer_in, transfer_out)"
+// @Param category_id query string false "分類 ID"
+// @Param start_date query string false "開始日期 (YYYY-MM-DD)"
+// @Param end_date query string false "結束日期 (YYYY-MM-DD)"
+// @Success 200 {file} file "匯出檔案"
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 500 {object} APIResponse{error=APIError}
+// @Router /api/cash-flows/export [get]
+func (h *ExportHandler) ExportCashFlows(c *gin.Context) {
+	format, ok := exportFormat(c)
+	if !ok {
+		return
+	}
+
+	filters, apiErr := parseCashFlowFilters(c)
+	if apiErr != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{Error: apiErr})
+		return
+	}
+
+	sheet, err := h.service.ExportCashFlows(filters, middleware.GetLocale(c))
+	h.respondSheet(c, "cash-flows", sheet, format, err)
+}
+
+// ExportMonthlySummary 匯出現金流月度彙總
+// @Summary 匯出現金流月度彙總
+// @Description 以 CSV 或 XLSX 匯出依月份與幣別彙總的收入、支出與轉帳金額（篩選條件與現金流列表相同）
+// @Tags cash-flows
+// @Produce text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param format query string false "檔案格式 (csv, xlsx)，預設 csv"
+// @Param type query string false "現金流類型 (income, expense, transfer_in, transfer_out)"
+// @Param category_id query string false "分類 ID"
+// @Param start_date query string false "開始日期 (YYYY-MM-DD)"
+// @Param end_date query string false "結束日期 (YYYY-MM-DD)"
+// @Success 200 {file} file "匯出檔案"
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 500 {object} APIResponse{error=APIError}
+// @Router /api/cash-flows/export/monthly [get]
+func (h *ExportHandler) ExportMonthlySummary(c *gin.Context) {
+	format, ok := exportFormat(c)
+	if !ok {
+		return
+	}
+
+	filters, apiErr := parseCashFlowFilters(c)
+	if apiErr != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{Error: apiErr})
+		return
+	}
+
+	sheet, err := h.service.ExportMonthlySummary(filters, middleware.GetLocale(c))
+	h.respondSheet(c, "monthly-summary", sheet, format, err)
+}
+
+// ExportTransactions 匯出交易記錄
+// @Summary 匯出交易記錄
+// @Description 以 CSV 或 XLSX 匯出交易記錄（篩選條件與交易列表相同，標題依語系翻譯）
+// @Tags transactions
+// @Produce text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param format query string false "檔案格式 (csv, xlsx)，預設 csv"
+// @Param asset_type query string false "資產類型"
+// @Param transaction_type query string false "交易類型"
+// @Param symbol query string false "代碼"
+// @Param brokerage_account_id query string false "券商帳戶 ID"
+// @Param custody_account_id query string false "保管帳戶 ID"
+// @Param start_date query string false "開始日期 (YYYY-MM-DD)"
+// @Param end_date query string false "結束日期 (YYYY-MM-DD)"
+// @Success 200 {file} file "匯出檔案"
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 500 {object} APIResponse{error=APIError}
+// @Router /api/transactions/export [get]
+func (h *ExportHandler) ExportTransactions(c *gin.Context) {
+	format, ok := exportFormat(c)
+	if !ok {
+		return
+	}
+
+	filters, apiErr := parseTransactionFilters(c)
+	if apiErr != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{Error: apiErr})
+		return
+	}
+
+	sheet, err := h.service.ExportTransactions(filters, middleware.GetLocale(c))
+	h.respondSheet(c, "transactions", sheet, format, err)
+}
+
+// ExportRealizedProfits 匯出已實現損益
+// @Summary 匯出已實現損益
+// @Description 以 CSV 或 XLSX 匯出已實現損益明細
+// @Tags transactions
+// @Produce text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param format query string false "檔案格式 (csv, xlsx)，預設 csv"
+// @Param asset_type query string false "資產類型"
+// @Param symbol query string false "代碼"
+// @Param start_date query string false "賣出開始日期 (YYYY-MM-DD)"
+// @Param end_date query string false "賣出結束日期 (YYYY-MM-DD)"
+// @Success 200 {file} file "匯出檔案"
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 500 {object} APIResponse{error=APIError}
+// @Router /api/transactions/realized-profits/export [get]
+func (h *ExportHandler) ExportRealizedProfits(c *gin.Context) {
+	format, ok := exportFormat(c)
+	if !ok {
+		return
+	}
+
+	filters := models.RealizedProfitFilters{}
+	if assetTypeStr := c.Query("asset_type"); assetTypeStr != "" {
+		assetType := models.AssetType(assetTypeStr)
+		filters.AssetType = &assetType
+	}
+	if symbol := c.Query("symbol"); symbol != "" {
+		filters.Symbol = &symbol
+	}
+	if startDateStr := c.Query("start_date"); startDateStr != "" {
+		startDate, err := time.Parse("2006-01-02", startDateStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, APIResponse{
+				Error: &APIError{
+					Code:    "INVALID_DATE",
+					Message: "Invalid start_date format, expected YYYY-MM-DD",
+				},
+			})
+			return
+		}
+		filters.StartDate = &startDate
+	}
+	if endDateStr := c.Query("end_date"); endDateStr != "" {
+		endDate, err := time.Parse("2006-01-02", endDateStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, APIResponse{
+				Error: &APIError{
+					Code:    "INVALID_DATE",
+					Message: "Invalid end_date format, expected YYYY-MM-DD",
+				},
+			})
+			return
+		}
+		filters.EndDate = &endDate
+	}
+
+	sheet, err := h.service.ExportRealizedProfits(filters, middleware.GetLocale(c))
+	h.respondSheet(c, "realized-profits", sheet, format, err)
+}
+
+// exportFormat 解析 format 查詢參數（預設 csv），無效時回應 400
+func exportFormat(c *gin.Context) (models.ExportFormat, bool) {
+	format := models.ExportFormat(c.DefaultQuery("format", string(models.ExportFormatCSV)))
+	if !format.Validate() {
+		RespondError(c, http.StatusBadRequest, "EXPORT_INVALID_FORMAT", "format must be csv or xlsx")
+		return "", false
+	}
+	return format, true
+}
+
+// respondSheet 將表格寫成檔案並以附件回傳
+func (h *ExportHandler) respondSheet(c *gin.Context, name string, sheet *models.ExportSheet, format models.ExportFormat, err error) {
+	if err != nil {
+		RespondErrorWithDetails(c, http.StatusInternalServerError, "EXPORT_FAILED", err.Error())
+		return
+	}
+
+	// 先寫入緩衝區，失敗時仍可回傳 JSON 錯誤
+	var buf bytes.Buffer
+	if err := h.service.WriteSheet(&buf, sheet, format); err != nil {
+		RespondErrorWithDetails(c, http.StatusInternalServerError, "EXPORT_FAILED", err.Error())
+		return
+	}
+
+	filename := fmt.Sprintf("%s-%s.%s", name, time.Now().Format("20060102"), format)
+	c.Header("Content-Disposition", "attachment; filename="+filename)
+	c.Data(http.StatusOK, format.ContentType(), buf.Bytes())
+}
@@ -0,0 +1,210 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/chienchuanw/asset-manager/internal/models"
+	"github.com/chienchuanw/asset-manager/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// FeeProfileHandler 券商手續費設定 API handler
+type FeeProfileHandler struct {
+	service service.FeeProfileService
+}
+
+// NewFeeProfileHandler 建立新的手續費設定 handler
+func NewFeeProfileHandler(service service.FeeProfileService) *FeeProfileHandler {
+	return &FeeProfileHandler{service: service}
+}
+
+// CreateFeeProfile 建立新的手續費設定
+// @Summary 建立手續費設定
+// @Description 建立券商手續費設定（費率、折扣、最低手續費與各商品類別的賣出交易稅率），可指定券商帳戶或設為資產類型的預設設定
+// @Tags fee-profiles
+// @Accept json
+// @Produce json
+// @Param profile body models.CreateFeeProfileInput true "手續費設定資料"
+// @Success 201 {object} APIResponse{data=models.FeeProfile}
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 500 {object} APIResponse{error=APIError}
+// @Router /api/fee-profiles [post]
+func (h *FeeProfileHandler) CreateFeeProfile(c *gin.Context) {
+	var input models.CreateFeeProfileInput
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Error: &APIError{
+				Code:    "INVALID_INPUT",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	profile, err := h.service.CreateProfile(&input)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Error: &APIError{
+				Code:    "CREATE_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, APIResponse{
+		Data: profile,
+	})
+}
+
+// GetFeeProfile 取得單筆手續費設定
+// @Summary 取得手續費設定
+// @Description 根據 ID 取得單筆手續費設定
+// @Tags fee-profiles
+// @Produce json
+// @Param id path string true "手續費設定 ID"
+// @Success 200 {object} APIResponse{data=models.FeeProfile}
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 404 {object} APIResponse{error=APIError}
+// @Router /api/fee-profiles/{id} [get]
+func (h *FeeProfileHandler) GetFeeProfile(c *gin.Context) {
+	id, ok := parseFeeProfileID(c)
+	if !ok {
+		return
+	}
+
+	profile, err := h.service.GetProfile(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, APIResponse{
+			Error: &APIError{
+				Code:    "NOT_FOUND",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Data: profile,
+	})
+}
+
+// ListFeeProfiles 列出所有手續費設定
+// @Summary 列出手續費設定
+// @Description 列出所有手續費設定
+// @Tags fee-profiles
+// @Produce json
+// @Success 200 {object} APIResponse{data=[]models.FeeProfile}
+// @Failure 500 {object} APIResponse{error=APIError}
+// @Router /api/fee-profiles [get]
+func (h *FeeProfileHandler) ListFeeProfiles(c *gin.Context) {
+	profiles, err := h.service.ListProfiles()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Error: &APIError{
+				Code:    "LIST_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Data: profiles,
+	})
+}
+
+// UpdateFeeProfile 更新手續費設定
+// @Summary 更新手續費設定
+// @Description 更新手續費設定（設為預設時會取消同資產類型原有的預設設定）
+// @Tags fee-profiles
+// @Accept json
+// @Produce json
+// @Param id path string true "手續費設定 ID"
+// @Param profile body models.UpdateFeeProfileInput true "更新資料"
+// @Success 200 {object} APIResponse{data=models.FeeProfile}
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 500 {object} APIResponse{error=APIError}
+// @Router /api/fee-profiles/{id} [put]
+func (h *FeeProfileHandler) UpdateFeeProfile(c *gin.Context) {
+	id, ok := parseFeeProfileID(c)
+	if !ok {
+		return
+	}
+
+	var input models.UpdateFeeProfileInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Error: &APIError{
+				Code:    "INVALID_INPUT",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	profile, err := h.service.UpdateProfile(id, &input)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Error: &APIError{
+				Code:    "UPDATE_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Data: profile,
+	})
+}
+
+// DeleteFeeProfile 刪除手續費設定
+// @Summary 刪除手續費設定
+// @Description 刪除手續費設定（已建立的交易不受影響）
+// @Tags fee-profiles
+// @Produce json
+// @Param id path string true "手續費設定 ID"
+// @Success 200 {object} APIResponse{data=map[string]string}
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 500 {object} APIResponse{error=APIError}
+// @Router /api/fee-profiles/{id} [delete]
+func (h *FeeProfileHandler) DeleteFeeProfile(c *gin.Context) {
+	id, ok := parseFeeProfileID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteProfile(id); err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Error: &APIError{
+				Code:    "DELETE_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Data: map[string]string{
+			"message": "Fee profile deleted successfully",
+		},
+	})
+}
+
+// parseFeeProfileID 解析路徑中的手續費設定 ID，失敗時直接回應 400
+func parseFeeProfileID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Error: &APIError{
+				Code:    "INVALID_ID",
+				Message: "Invalid fee profile ID format",
+			},
+		})
+		return uuid.Nil, false
+	}
+	return id, true
+}
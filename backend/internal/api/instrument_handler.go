@@ -0,0 +1,126 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/chienchuanw/asset-manager/internal/models"
+	"github.com/chienchuanw/asset-manager/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// InstrumentHandler 商品主檔 API handler
+type InstrumentHandler struct {
+	service service.InstrumentService
+}
+
+// NewInstrumentHandler 建立新的商品主檔 handler
+func NewInstrumentHandler(service service.InstrumentService) *InstrumentHandler {
+	return &InstrumentHandler{service: service}
+}
+
+// SearchInstruments 搜尋商品
+// @Summary 搜尋商品
+// @Description 依代碼前綴或名稱（中文、英文）搜尋商品主檔，代碼完全相符者優先
+// @Tags instruments
+// @Produce json
+// @Param q query string true "搜尋關鍵字"
+// @Param asset_type query string false "資產類型 (tw-stock, us-stock, crypto)"
+// @Param limit query int false "回傳筆數（預設 20，最多 50）"
+// @Success 200 {object} APIResponse{data=[]models.Instrument}
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Router /api/instruments/search [get]
+func (h *InstrumentHandler) SearchInstruments(c *gin.Context) {
+	var assetType *models.AssetType
+	if value := c.Query("asset_type"); value != "" {
+		at := models.AssetType(value)
+		assetType = &at
+	}
+
+	limit := 0
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 {
+			limit = parsedLimit
+		}
+	}
+
+	instruments, err := h.service.Search(c.Query("q"), assetType, limit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Error: &APIError{
+				Code:    "SEARCH_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Data: instruments,
+	})
+}
+
+// ImportInstruments 匯入商品清單
+// @Summary 匯入商品清單
+// @Description 上傳證交所／櫃買中心上市、上櫃、興櫃清單（CSV）、美股代碼清單（nasdaqlisted.txt / otherlisted.txt）或 CoinGecko 幣種清單（JSON），新增或更新商品主檔
+// @Tags instruments
+// @Accept multipart/form-data
+// @Produce json
+// @Param source formData string true "來源 (twse, tpex, emerging, us_tickers, coingecko)"
+// @Param file formData file true "清單檔案"
+// @Success 200 {object} APIResponse{data=models.InstrumentImportResult}
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 500 {object} APIResponse{error=APIError}
+// @Router /api/instruments/import [post]
+func (h *InstrumentHandler) ImportInstruments(c *gin.Context) {
+	source := models.InstrumentSource(c.PostForm("source"))
+	if !source.Validate() {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Error: &APIError{
+				Code:    "INVALID_SOURCE",
+				Message: "source 應為 twse, tpex, emerging, us_tickers 或 coingecko",
+			},
+		})
+		return
+	}
+
+	// 取得上傳的檔案
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Error: &APIError{
+				Code:    "INVALID_FILE",
+				Message: "無法讀取上傳的檔案",
+			},
+		})
+		return
+	}
+
+	// 開啟檔案
+	f, err := file.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Error: &APIError{
+				Code:    "FILE_OPEN_ERROR",
+				Message: "無法開啟檔案",
+			},
+		})
+		return
+	}
+	defer f.Close()
+
+	result, err := h.service.Import(source, f)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Error: &APIError{
+				Code:    "IMPORT_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Data: result,
+	})
+}
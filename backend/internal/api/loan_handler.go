@@ -0,0 +1,421 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/chienchuanw/asset-manager/internal/models"
+	"github.com/chienchuanw/asset-manager/internal/repository"
+	"github.com/chienchuanw/asset-manager/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// LoanHandler 貸款 API handler
+type LoanHandler struct {
+	service service.LoanService
+}
+
+// NewLoanHandler 建立新的貸款 handler
+func NewLoanHandler(service service.LoanService) *LoanHandler {
+	return &LoanHandler{service: service}
+}
+
+// parseLoanID 解析路徑中的貸款 ID，失敗時回傳 400
+func parseLoanID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Error: &APIError{
+				Code:    "INVALID_ID",
+				Message: "Invalid loan ID format",
+			},
+		})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// CreateLoan 建立新的貸款
+// @Summary 建立貸款
+// @Description 建立新的貸款（房貸、車貸、信貸等）
+// @Tags loans
+// @Accept json
+// @Produce json
+// @Param loan body models.CreateLoanInput true "貸款資料"
+// @Success 201 {object} APIResponse{data=models.Loan}
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 500 {object} APIResponse{error=APIError}
+// @Router /api/loans [post]
+func (h *LoanHandler) CreateLoan(c *gin.Context) {
+	var input models.CreateLoanInput
+
+	// 綁定並驗證請求資料
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Error: &APIError{
+				Code:    "INVALID_INPUT",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	// 呼叫 service 建立貸款
+	loan, err := h.service.CreateLoan(&input)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Error: &APIError{
+				Code:    "CREATE_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, APIResponse{
+		Data: loan,
+	})
+}
+
+// GetLoan 取得單筆貸款
+// @Summary 取得貸款
+// @Description 根據 ID 取得單筆貸款（含利率調整與提前還本記錄）
+// @Tags loans
+// @Produce json
+// @Param id path string true "貸款 ID"
+// @Success 200 {object} APIResponse{data=models.Loan}
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 404 {object} APIResponse{error=APIError}
+// @Router /api/loans/{id} [get]
+func (h *LoanHandler) GetLoan(c *gin.Context) {
+	id, ok := parseLoanID(c)
+	if !ok {
+		return
+	}
+
+	// 呼叫 service 取得貸款
+	loan, err := h.service.GetLoan(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, APIResponse{
+			Error: &APIError{
+				Code:    "NOT_FOUND",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Data: loan,
+	})
+}
+
+// ListLoans 取得貸款列表
+// @Summary 取得貸款列表
+// @Description 取得貸款列表，支援篩選和分頁
+// @Tags loans
+// @Produce json
+// @Param status query string false "狀態篩選 (active, paid_off, cancelled)"
+// @Param loan_type query string false "類型篩選 (mortgage, car, personal, other)"
+// @Param limit query int false "每頁筆數" default(100)
+// @Param offset query int false "略過筆數" default(0)
+// @Success 200 {object} APIResponse{data=[]models.Loan}
+// @Failure 500 {object} APIResponse{error=APIError}
+// @Router /api/loans [get]
+func (h *LoanHandler) ListLoans(c *gin.Context) {
+	// 解析查詢參數
+	filters := repository.LoanFilters{}
+
+	// 狀態篩選
+	if statusStr := c.Query("status"); statusStr != "" {
+		status := models.LoanStatus(statusStr)
+		filters.Status = &status
+	}
+
+	// 類型篩選
+	if typeStr := c.Query("loan_type"); typeStr != "" {
+		loanType := models.LoanType(typeStr)
+		filters.LoanType = &loanType
+	}
+
+	// 分頁參數
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if limit, err := strconv.Atoi(limitStr); err == nil {
+			filters.Limit = limit
+		}
+	}
+
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		if offset, err := strconv.Atoi(offsetStr); err == nil {
+			filters.Offset = offset
+		}
+	}
+
+	// 呼叫 service 取得貸款列表
+	loans, err := h.service.ListLoans(filters)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Error: &APIError{
+				Code:    "LIST_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Data: loans,
+	})
+}
+
+// UpdateLoan 更新貸款
+// @Summary 更新貸款
+// @Description 更新貸款資料
+// @Tags loans
+// @Accept json
+// @Produce json
+// @Param id path string true "貸款 ID"
+// @Param loan body models.UpdateLoanInput true "更新資料"
+// @Success 200 {object} APIResponse{data=models.Loan}
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 500 {object} APIResponse{error=APIError}
+// @Router /api/loans/{id} [put]
+func (h *LoanHandler) UpdateLoan(c *gin.Context) {
+	id, ok := parseLoanID(c)
+	if !ok {
+		return
+	}
+
+	var input models.UpdateLoanInput
+
+	// 綁定並驗證請求資料
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Error: &APIError{
+				Code:    "INVALID_INPUT",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	// 呼叫 service 更新貸款
+	loan, err := h.service.UpdateLoan(id, &input)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Error: &APIError{
+				Code:    "UPDATE_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Data: loan,
+	})
+}
+
+// DeleteLoan 刪除貸款
+// @Summary 刪除貸款
+// @Description 刪除貸款及其利率調整、提前還本與還款記錄
+// @Tags loans
+// @Produce json
+// @Param id path string true "貸款 ID"
+// @Success 200 {object} APIResponse{data=string}
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 500 {object} APIResponse{error=APIError}
+// @Router /api/loans/{id} [delete]
+func (h *LoanHandler) DeleteLoan(c *gin.Context) {
+	id, ok := parseLoanID(c)
+	if !ok {
+		return
+	}
+
+	// 呼叫 service 刪除貸款
+	if err := h.service.DeleteLoan(id); err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Error: &APIError{
+				Code:    "DELETE_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Data: "Loan deleted successfully",
+	})
+}
+
+// GetAmortizationSchedule 取得貸款攤還表
+// @Summary 取得貸款攤還表
+// @Description 依利率調整與提前還本記錄計算每期本金、利息與剩餘本金
+// @Tags loans
+// @Produce json
+// @Param id path string true "貸款 ID"
+// @Param as_of query string false "剩餘本金基準日 (YYYY-MM-DD)，預設今日"
+// @Success 200 {object} APIResponse{data=models.AmortizationSchedule}
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 404 {object} APIResponse{error=APIError}
+// @Router /api/loans/{id}/schedule [get]
+func (h *LoanHandler) GetAmortizationSchedule(c *gin.Context) {
+	id, ok := parseLoanID(c)
+	if !ok {
+		return
+	}
+
+	asOf := time.Now()
+	if asOfStr := c.Query("as_of"); asOfStr != "" {
+		parsed, err := time.Parse("2006-01-02", asOfStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, APIResponse{
+				Error: &APIError{
+					Code:    "INVALID_DATE",
+					Message: "Invalid as_of format, expected YYYY-MM-DD",
+				},
+			})
+			return
+		}
+		asOf = parsed
+	}
+
+	schedule, err := h.service.GetAmortizationSchedule(id, asOf)
+	if err != nil {
+		c.JSON(http.StatusNotFound, APIResponse{
+			Error: &APIError{
+				Code:    "NOT_FOUND",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Data: schedule,
+	})
+}
+
+// GetPayments 取得貸款還款記錄
+// @Summary 取得貸款還款記錄
+// @Description 取得扣款排程已建立的每期還款記錄
+// @Tags loans
+// @Produce json
+// @Param id path string true "貸款 ID"
+// @Success 200 {object} APIResponse{data=[]models.LoanPayment}
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 500 {object} APIResponse{error=APIError}
+// @Router /api/loans/{id}/payments [get]
+func (h *LoanHandler) GetPayments(c *gin.Context) {
+	id, ok := parseLoanID(c)
+	if !ok {
+		return
+	}
+
+	payments, err := h.service.GetPayments(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Error: &APIError{
+				Code:    "GET_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Data: payments,
+	})
+}
+
+// AddRateChange 新增利率調整
+// @Summary 新增利率調整
+// @Description 新增貸款利率調整，自生效日起的攤還表將重新計算
+// @Tags loans
+// @Accept json
+// @Produce json
+// @Param id path string true "貸款 ID"
+// @Param rate_change body models.CreateLoanRateChangeInput true "利率調整資料"
+// @Success 201 {object} APIResponse{data=models.LoanRateChange}
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 500 {object} APIResponse{error=APIError}
+// @Router /api/loans/{id}/rate-changes [post]
+func (h *LoanHandler) AddRateChange(c *gin.Context) {
+	id, ok := parseLoanID(c)
+	if !ok {
+		return
+	}
+
+	var input models.CreateLoanRateChangeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Error: &APIError{
+				Code:    "INVALID_INPUT",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	change, err := h.service.AddRateChange(id, &input)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Error: &APIError{
+				Code:    "CREATE_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, APIResponse{
+		Data: change,
+	})
+}
+
+// AddPrepayment 新增提前還本
+// @Summary 新增提前還本
+// @Description 新增貸款提前還本，之後各期月付金將依剩餘本金重新攤提
+// @Tags loans
+// @Accept json
+// @Produce json
+// @Param id path string true "貸款 ID"
+// @Param prepayment body models.CreateLoanPrepaymentInput true "提前還本資料"
+// @Success 201 {object} APIResponse{data=models.LoanPrepayment}
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 500 {object} APIResponse{error=APIError}
+// @Router /api/loans/{id}/prepayments [post]
+func (h *LoanHandler) AddPrepayment(c *gin.Context) {
+	id, ok := parseLoanID(c)
+	if !ok {
+		return
+	}
+
+	var input models.CreateLoanPrepaymentInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Error: &APIError{
+				Code:    "INVALID_INPUT",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	prepayment, err := h.service.AddPrepayment(id, &input)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Error: &APIError{
+				Code:    "CREATE_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, APIResponse{
+		Data: prepayment,
+	})
+}
@@ -0,0 +1,346 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/chienchuanw/asset-manager/internal/models"
+	"github.com/chienchuanw/asset-manager/internal/repository"
+	"github.com/chienchuanw/asset-manager/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ManualAssetHandler 手動估值資產 API handler
+type ManualAssetHandler struct {
+	service service.ManualAssetService
+}
+
+// NewManualAssetHandler 建立新的手動估值資產 handler
+func NewManualAssetHandler(service service.ManualAssetService) *ManualAssetHandler {
+	return &ManualAssetHandler{service: service}
+}
+
+// parseManualAssetID 解析路徑中的資產 ID，失敗時回傳 400
+func parseManualAssetID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Error: &APIError{
+				Code:    "INVALID_ID",
+				Message: "Invalid manual asset ID format",
+			},
+		})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// CreateManualAsset 建立手動估值資產
+// @Summary 建立手動估值資產
+// @Description 建立不動產、車輛、保單價值等無市場報價的資產
+// @Tags manual-assets
+// @Accept json
+// @Produce json
+// @Param asset body models.CreateManualAssetInput true "資產資料"
+// @Success 201 {object} APIResponse{data=models.ManualAsset}
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 500 {object} APIResponse{error=APIError}
+// @Router /api/manual-assets [post]
+func (h *ManualAssetHandler) CreateManualAsset(c *gin.Context) {
+	var input models.CreateManualAssetInput
+
+	// 綁定並驗證請求資料
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Error: &APIError{
+				Code:    "INVALID_INPUT",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	asset, err := h.service.CreateManualAsset(&input)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Error: &APIError{
+				Code:    "CREATE_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, APIResponse{
+		Data: asset,
+	})
+}
+
+// GetManualAsset 取得單筆手動估值資產
+// @Summary 取得手動估值資產
+// @Description 根據 ID 取得手動估值資產（含估值歷史與目前估值）
+// @Tags manual-assets
+// @Produce json
+// @Param id path string true "資產 ID"
+// @Success 200 {object} APIResponse{data=models.ManualAsset}
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 404 {object} APIResponse{error=APIError}
+// @Router /api/manual-assets/{id} [get]
+func (h *ManualAssetHandler) GetManualAsset(c *gin.Context) {
+	id, ok := parseManualAssetID(c)
+	if !ok {
+		return
+	}
+
+	asset, err := h.service.GetManualAsset(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, APIResponse{
+			Error: &APIError{
+				Code:    "NOT_FOUND",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Data: asset,
+	})
+}
+
+// ListManualAssets 取得手動估值資產列表
+// @Summary 取得手動估值資產列表
+// @Description 取得手動估值資產列表（含估值歷史與目前估值）
+// @Tags manual-assets
+// @Produce json
+// @Param status query string false "狀態篩選 (active, disposed)"
+// @Param category query string false "分類篩選 (real_estate, vehicle, insurance, private_equity, collectible, other)"
+// @Success 200 {object} APIResponse{data=[]models.ManualAsset}
+// @Failure 500 {object} APIResponse{error=APIError}
+// @Router /api/manual-assets [get]
+func (h *ManualAssetHandler) ListManualAssets(c *gin.Context) {
+	filters := repository.ManualAssetFilters{}
+
+	// 狀態篩選
+	if statusStr := c.Query("status"); statusStr != "" {
+		status := models.ManualAssetStatus(statusStr)
+		filters.Status = &status
+	}
+
+	// 分類篩選
+	if categoryStr := c.Query("category"); categoryStr != "" {
+		category := models.ManualAssetCategory(categoryStr)
+		filters.Category = &category
+	}
+
+	assets, err := h.service.ListManualAssets(filters)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Error: &APIError{
+				Code:    "LIST_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Data: assets,
+	})
+}
+
+// UpdateManualAsset 更新手動估值資產
+// @Summary 更新手動估值資產
+// @Description 更新手動估值資產設定（含折舊/增值方式）
+// @Tags manual-assets
+// @Accept json
+// @Produce json
+// @Param id path string true "資產 ID"
+// @Param asset body models.UpdateManualAssetInput true "更新資料"
+// @Success 200 {object} APIResponse{data=models.ManualAsset}
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 500 {object} APIResponse{error=APIError}
+// @Router /api/manual-assets/{id} [put]
+func (h *ManualAssetHandler) UpdateManualAsset(c *gin.Context) {
+	id, ok := parseManualAssetID(c)
+	if !ok {
+		return
+	}
+
+	var input models.UpdateManualAssetInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Error: &APIError{
+				Code:    "INVALID_INPUT",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	asset, err := h.service.UpdateManualAsset(id, &input)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Error: &APIError{
+				Code:    "UPDATE_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Data: asset,
+	})
+}
+
+// DeleteManualAsset 刪除手動估值資產
+// @Summary 刪除手動估值資產
+// @Description 刪除手動估值資產及其估值記錄
+// @Tags manual-assets
+// @Produce json
+// @Param id path string true "資產 ID"
+// @Success 200 {object} APIResponse{data=string}
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 500 {object} APIResponse{error=APIError}
+// @Router /api/manual-assets/{id} [delete]
+func (h *ManualAssetHandler) DeleteManualAsset(c *gin.Context) {
+	id, ok := parseManualAssetID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteManualAsset(id); err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Error: &APIError{
+				Code:    "DELETE_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Data: "Manual asset deleted successfully",
+	})
+}
+
+// GetValuations 取得估值歷史
+// @Summary 取得估值歷史
+// @Description 取得手動估值資產的估值記錄（由新到舊）
+// @Tags manual-assets
+// @Produce json
+// @Param id path string true "資產 ID"
+// @Success 200 {object} APIResponse{data=[]models.ManualAssetValuation}
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 404 {object} APIResponse{error=APIError}
+// @Router /api/manual-assets/{id}/valuations [get]
+func (h *ManualAssetHandler) GetValuations(c *gin.Context) {
+	id, ok := parseManualAssetID(c)
+	if !ok {
+		return
+	}
+
+	valuations, err := h.service.GetValuations(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, APIResponse{
+			Error: &APIError{
+				Code:    "NOT_FOUND",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Data: valuations,
+	})
+}
+
+// AddValuation 新增估值記錄
+// @Summary 新增估值記錄
+// @Description 新增手動估值，之後的折舊/增值以此估值為基準計算
+// @Tags manual-assets
+// @Accept json
+// @Produce json
+// @Param id path string true "資產 ID"
+// @Param valuation body models.CreateManualAssetValuationInput true "估值資料"
+// @Success 201 {object} APIResponse{data=models.ManualAssetValuation}
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 500 {object} APIResponse{error=APIError}
+// @Router /api/manual-assets/{id}/valuations [post]
+func (h *ManualAssetHandler) AddValuation(c *gin.Context) {
+	id, ok := parseManualAssetID(c)
+	if !ok {
+		return
+	}
+
+	var input models.CreateManualAssetValuationInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Error: &APIError{
+				Code:    "INVALID_INPUT",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	valuation, err := h.service.AddValuation(id, &input)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Error: &APIError{
+				Code:    "CREATE_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, APIResponse{
+		Data: valuation,
+	})
+}
+
+// DeleteValuation 刪除估值記錄
+// @Summary 刪除估值記錄
+// @Description 刪除單筆手動估值記錄
+// @Tags manual-assets
+// @Produce json
+// @Param id path string true "資產 ID"
+// @Param valuation_id path string true "估值記錄 ID"
+// @Success 200 {object} APIResponse{data=string}
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 500 {object} APIResponse{error=APIError}
+// @Router /api/manual-assets/{id}/valuations/{valuation_id} [delete]
+func (h *ManualAssetHandler) DeleteValuation(c *gin.Context) {
+	id, ok := parseManualAssetID(c)
+	if !ok {
+		return
+	}
+
+	valuationID, err := uuid.Parse(c.Param("valuation_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Error: &APIError{
+				Code:    "INVALID_ID",
+				Message: "Invalid valuation ID format",
+			},
+		})
+		return
+	}
+
+	if err := h.service.DeleteValuation(id, valuationID); err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Error: &APIError{
+				Code:    "DELETE_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Data: "Valuation deleted successfully",
+	})
+}
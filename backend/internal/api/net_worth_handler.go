@@ -0,0 +1,43 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/chienchuanw/asset-manager/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// NetWorthHandler 淨值 API handler
+type NetWorthHandler struct {
+	service service.NetWorthService
+}
+
+// NewNetWorthHandler 建立新的淨值 handler
+func NewNetWorthHandler(service service.NetWorthService) *NetWorthHandler {
+	return &NetWorthHandler{service: service}
+}
+
+// GetNetWorth 取得目前淨值
+// @Summary 取得淨值
+// @Description 計算目前淨值（投資市值 + 銀行餘額 + 手動估值資產 - 信用卡、分期與貸款負債）
+// @Tags net-worth
+// @Produce json
+// @Success 200 {object} APIResponse{data=models.NetWorth}
+// @Failure 500 {object} APIResponse{error=APIError}
+// @Router /api/net-worth [get]
+func (h *NetWorthHandler) GetNetWorth(c *gin.Context) {
+	netWorth, err := h.service.GetNetWorth()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Error: &APIError{
+				Code:    "GET_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Data: netWorth,
+	})
+}
@@ -0,0 +1,165 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/chienchuanw/asset-manager/internal/models"
+	"github.com/chienchuanw/asset-manager/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// OptionContractHandler 選擇權合約 API handler
+type OptionContractHandler struct {
+	service service.OptionContractService
+}
+
+// NewOptionContractHandler 建立新的選擇權合約 handler
+func NewOptionContractHandler(service service.OptionContractService) *OptionContractHandler {
+	return &OptionContractHandler{service: service}
+}
+
+// CreateOptionContract 建立新的選擇權合約
+// @Summary 建立選擇權合約
+// @Description 建立選擇權合約規格（標的、買權/賣權、履約價、到期日、乘數），交易記錄以合約代碼對應
+// @Tags option-contracts
+// @Accept json
+// @Produce json
+// @Param contract body models.CreateOptionContractInput true "選擇權合約資料"
+// @Success 201 {object} APIResponse{data=models.OptionContract}
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 500 {object} APIResponse{error=APIError}
+// @Router /api/option-contracts [post]
+func (h *OptionContractHandler) CreateOptionContract(c *gin.Context) {
+	var input models.CreateOptionContractInput
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Error: &APIError{
+				Code:    "INVALID_INPUT",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	contract, err := h.service.CreateOptionContract(&input)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Error: &APIError{
+				Code:    "CREATE_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, APIResponse{
+		Data: contract,
+	})
+}
+
+// GetOptionContract 取得單筆選擇權合約
+// @Summary 取得選擇權合約
+// @Description 根據 ID 取得單筆選擇權合約
+// @Tags option-contracts
+// @Produce json
+// @Param id path string true "選擇權合約 ID"
+// @Success 200 {object} APIResponse{data=models.OptionContract}
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 404 {object} APIResponse{error=APIError}
+// @Router /api/option-contracts/{id} [get]
+func (h *OptionContractHandler) GetOptionContract(c *gin.Context) {
+	id, ok := parseOptionContractID(c)
+	if !ok {
+		return
+	}
+
+	contract, err := h.service.GetOptionContract(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, APIResponse{
+			Error: &APIError{
+				Code:    "NOT_FOUND",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Data: contract,
+	})
+}
+
+// ListOptionContracts 列出所有選擇權合約
+// @Summary 列出選擇權合約
+// @Description 列出所有選擇權合約（依到期日排序）
+// @Tags option-contracts
+// @Produce json
+// @Success 200 {object} APIResponse{data=[]models.OptionContract}
+// @Failure 500 {object} APIResponse{error=APIError}
+// @Router /api/option-contracts [get]
+func (h *OptionContractHandler) ListOptionContracts(c *gin.Context) {
+	contracts, err := h.service.ListOptionContracts()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Error: &APIError{
+				Code:    "LIST_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Data: contracts,
+	})
+}
+
+// DeleteOptionContract 刪除選擇權合約
+// @Summary 刪除選擇權合約
+// @Description 刪除選擇權合約規格
+// @Tags option-contracts
+// @Produce json
+// @Param id path string true "選擇權合約 ID"
+// @Success 200 {object} APIResponse{data=map[string]string}
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 500 {object} APIResponse{error=APIError}
+// @Router /api/option-contracts/{id} [delete]
+func (h *OptionContractHandler) DeleteOptionContract(c *gin.Context) {
+	id, ok := parseOptionContractID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteOptionContract(id); err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Error: &APIError{
+				Code:    "DELETE_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Data: map[string]string{
+			"message": "Option contract deleted successfully",
+		},
+	})
+}
+
+// parseOptionContractID 解析路徑中的選擇權合約 ID，失敗時直接回應 400
+func parseOptionContractID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Error: &APIError{
+				Code:    "INVALID_ID",
+				Message: "Invalid option contract ID format",
+			},
+		})
+		return uuid.Nil, false
+	}
+	return id, true
+}
@@ -0,0 +1,42 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/chienchuanw/asset-manager/internal/models"
+	"github.com/chienchuanw/asset-manager/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// PriceProviderHandler 價格來源狀態 API handler
+type PriceProviderHandler struct {
+	registry service.PriceProviderRegistry
+}
+
+// NewPriceProviderHandler 建立新的價格來源狀態 handler（registry 為 nil 表示使用 Mock 價格服務）
+func NewPriceProviderHandler(registry service.PriceProviderRegistry) *PriceProviderHandler {
+	return &PriceProviderHandler{registry: registry}
+}
+
+// GetProviderStatus 取得價格來源順序與健康狀態
+// @Summary 取得價格來源狀態
+// @Description 取得各資產類型的價格來源順序，以及各來源的呼叫次數、錯誤統計與平均回應時間
+// @Tags prices
+// @Produce json
+// @Success 200 {object} APIResponse{data=models.PriceProvidersOverview}
+// @Router /api/prices/providers [get]
+func (h *PriceProviderHandler) GetProviderStatus(c *gin.Context) {
+	if h.registry == nil {
+		c.JSON(http.StatusOK, APIResponse{
+			Data: &models.PriceProvidersOverview{
+				Chains:    []*models.PriceProviderChain{},
+				Providers: []*models.PriceProviderStatus{},
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Data: h.registry.Status(),
+	})
+}
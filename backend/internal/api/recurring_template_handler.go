@@ -0,0 +1,413 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/chienchuanw/asset-manager/internal/models"
+	"github.com/chienchuanw/asset-manager/internal/repository"
+	"github.com/chienchuanw/asset-manager/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RecurringTemplateHandler 定期收支範本 API handler
+type RecurringTemplateHandler struct {
+	service service.RecurringTemplateService
+}
+
+// NewRecurringTemplateHandler 建立新的定期收支範本 handler
+func NewRecurringTemplateHandler(service service.RecurringTemplateService) *RecurringTemplateHandler {
+	return &RecurringTemplateHandler{service: service}
+}
+
+// parseRecurringTemplateID 解析路徑中的範本 ID，失敗時回傳 400
+func parseRecurringTemplateID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Error: &APIError{
+				Code:    "INVALID_ID",
+				Message: "Invalid recurring template ID format",
+			},
+		})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// CreateTemplate 建立新的定期收支範本
+// @Summary 建立定期收支範本
+// @Description 建立定期收入、支出或轉帳範本（例如薪資、房租收入、半年繳保險費）
+// @Tags recurring-templates
+// @Accept json
+// @Produce json
+// @Param template body models.CreateRecurringTemplateInput true "範本資料"
+// @Success 201 {object} APIResponse{data=models.RecurringTemplate}
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 500 {object} APIResponse{error=APIError}
+// @Router /api/recurring-templates [post]
+func (h *RecurringTemplateHandler) CreateTemplate(c *gin.Context) {
+	var input models.CreateRecurringTemplateInput
+
+	// 綁定並驗證請求資料
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Error: &APIError{
+				Code:    "INVALID_INPUT",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	// 呼叫 service 建立範本
+	template, err := h.service.CreateTemplate(&input)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Error: &APIError{
+				Code:    "CREATE_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, APIResponse{
+		Data: template,
+	})
+}
+
+// GetTemplate 取得單筆定期收支範本
+// @Summary 取得定期收支範本
+// @Description 根據 ID 取得單筆定期收支範本（含金額調整記錄）
+// @Tags recurring-templates
+// @Produce json
+// @Param id path string true "範本 ID"
+// @Success 200 {object} APIResponse{data=models.RecurringTemplate}
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 404 {object} APIResponse{error=APIError}
+// @Router /api/recurring-templates/{id} [get]
+func (h *RecurringTemplateHandler) GetTemplate(c *gin.Context) {
+	id, ok := parseRecurringTemplateID(c)
+	if !ok {
+		return
+	}
+
+	// 呼叫 service 取得範本
+	template, err := h.service.GetTemplate(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, APIResponse{
+			Error: &APIError{
+				Code:    "NOT_FOUND",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Data: template,
+	})
+}
+
+// ListTemplates 取得定期收支範本列表
+// @Summary 取得定期收支範本列表
+// @Description 取得定期收支範本列表，支援篩選和分頁
+// @Tags recurring-templates
+// @Produce json
+// @Param status query string false "狀態篩選 (active, paused, ended)"
+// @Param type query string false "類型篩選 (income, expense, transfer_in, transfer_out)"
+// @Param limit query int false "每頁筆數" default(100)
+// @Param offset query int false "略過筆數" default(0)
+// @Success 200 {object} APIResponse{data=[]models.RecurringTemplate}
+// @Failure 500 {object} APIResponse{error=APIError}
+// @Router /api/recurring-templates [get]
+func (h *RecurringTemplateHandler) ListTemplates(c *gin.Context) {
+	// 解析查詢參數
+	filters := repository.RecurringTemplateFilters{}
+
+	// 狀態篩選
+	if statusStr := c.Query("status"); statusStr != "" {
+		status := models.RecurringTemplateStatus(statusStr)
+		filters.Status = &status
+	}
+
+	// 類型篩選
+	if typeStr := c.Query("type"); typeStr != "" {
+		cashFlowType := models.CashFlowType(typeStr)
+		filters.Type = &cashFlowType
+	}
+
+	// 分頁參數
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if limit, err := strconv.Atoi(limitStr); err == nil {
+			filters.Limit = limit
+		}
+	}
+
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		if offset, err := strconv.Atoi(offsetStr); err == nil {
+			filters.Offset = offset
+		}
+	}
+
+	// 呼叫 service 取得範本列表
+	templates, err := h.service.ListTemplates(filters)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Error: &APIError{
+				Code:    "LIST_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Data: templates,
+	})
+}
+
+// UpdateTemplate 更新定期收支範本
+// @Summary 更新定期收支範本
+// @Description 更新定期收支範本的排程、金額或付款設定
+// @Tags recurring-templates
+// @Accept json
+// @Produce json
+// @Param id path string true "範本 ID"
+// @Param template body models.UpdateRecurringTemplateInput true "更新資料"
+// @Success 200 {object} APIResponse{data=models.RecurringTemplate}
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 500 {object} APIResponse{error=APIError}
+// @Router /api/recurring-templates/{id} [put]
+func (h *RecurringTemplateHandler) UpdateTemplate(c *gin.Context) {
+	id, ok := parseRecurringTemplateID(c)
+	if !ok {
+		return
+	}
+
+	var input models.UpdateRecurringTemplateInput
+
+	// 綁定並驗證請求資料
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Error: &APIError{
+				Code:    "INVALID_INPUT",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	// 呼叫 service 更新範本
+	template, err := h.service.UpdateTemplate(id, &input)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Error: &APIError{
+				Code:    "UPDATE_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Data: template,
+	})
+}
+
+// DeleteTemplate 刪除定期收支範本
+// @Summary 刪除定期收支範本
+// @Description 刪除定期收支範本及其金額調整記錄（已產生的現金流不受影響）
+// @Tags recurring-templates
+// @Produce json
+// @Param id path string true "範本 ID"
+// @Success 200 {object} APIResponse{data=string}
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 500 {object} APIResponse{error=APIError}
+// @Router /api/recurring-templates/{id} [delete]
+func (h *RecurringTemplateHandler) DeleteTemplate(c *gin.Context) {
+	id, ok := parseRecurringTemplateID(c)
+	if !ok {
+		return
+	}
+
+	// 呼叫 service 刪除範本
+	if err := h.service.DeleteTemplate(id); err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Error: &APIError{
+				Code:    "DELETE_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Data: "Recurring template deleted successfully",
+	})
+}
+
+// GetOccurrences 預覽定期收支的發生日期
+// @Summary 預覽定期收支發生日期
+// @Description 依排程規則與金額調整，列出期間內的發生日期與金額（預設為今天起三個月）
+// @Tags recurring-templates
+// @Produce json
+// @Param id path string true "範本 ID"
+// @Param start_date query string false "開始日期 (YYYY-MM-DD)"
+// @Param end_date query string false "結束日期 (YYYY-MM-DD)"
+// @Success 200 {object} APIResponse{data=[]models.RecurringOccurrence}
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 404 {object} APIResponse{error=APIError}
+// @Router /api/recurring-templates/{id}/occurrences [get]
+func (h *RecurringTemplateHandler) GetOccurrences(c *gin.Context) {
+	id, ok := parseRecurringTemplateID(c)
+	if !ok {
+		return
+	}
+
+	startDate := time.Now()
+	if startDateStr := c.Query("start_date"); startDateStr != "" {
+		parsed, err := time.Parse("2006-01-02", startDateStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, APIResponse{
+				Error: &APIError{
+					Code:    "INVALID_START_DATE",
+					Message: "Invalid start date format, use YYYY-MM-DD",
+				},
+			})
+			return
+		}
+		startDate = parsed
+	}
+
+	endDate := startDate.AddDate(0, 3, 0)
+	if endDateStr := c.Query("end_date"); endDateStr != "" {
+		parsed, err := time.Parse("2006-01-02", endDateStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, APIResponse{
+				Error: &APIError{
+					Code:    "INVALID_END_DATE",
+					Message: "Invalid end date format, use YYYY-MM-DD",
+				},
+			})
+			return
+		}
+		endDate = parsed
+	}
+
+	if endDate.Before(startDate) {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Error: &APIError{
+				Code:    "INVALID_INPUT",
+				Message: "end_date must be on or after start_date",
+			},
+		})
+		return
+	}
+
+	occurrences, err := h.service.GetOccurrences(id, startDate, endDate)
+	if err != nil {
+		c.JSON(http.StatusNotFound, APIResponse{
+			Error: &APIError{
+				Code:    "NOT_FOUND",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Data: occurrences,
+	})
+}
+
+// AddAmountChange 新增金額調整
+// @Summary 新增金額調整
+// @Description 新增定期收支金額調整（例如調薪、租金調漲），自生效日起套用新金額
+// @Tags recurring-templates
+// @Accept json
+// @Produce json
+// @Param id path string true "範本 ID"
+// @Param amount_change body models.CreateRecurringAmountChangeInput true "金額調整資料"
+// @Success 201 {object} APIResponse{data=models.RecurringAmountChange}
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 500 {object} APIResponse{error=APIError}
+// @Router /api/recurring-templates/{id}/amount-changes [post]
+func (h *RecurringTemplateHandler) AddAmountChange(c *gin.Context) {
+	id, ok := parseRecurringTemplateID(c)
+	if !ok {
+		return
+	}
+
+	var input models.CreateRecurringAmountChangeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Error: &APIError{
+				Code:    "INVALID_INPUT",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	change, err := h.service.AddAmountChange(id, &input)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Error: &APIError{
+				Code:    "CREATE_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, APIResponse{
+		Data: change,
+	})
+}
+
+// DeleteAmountChange 刪除金額調整
+// @Summary 刪除金額調整
+// @Description 刪除定期收支的金額調整記錄
+// @Tags recurring-templates
+// @Produce json
+// @Param id path string true "範本 ID"
+// @Param change_id path string true "金額調整 ID"
+// @Success 200 {object} APIResponse{data=string}
+// @Failure 400 {object} APIResponse{error=APIError}
+// @Failure 500 {object} APIResponse{error=APIError}
+// @Router /api/recurring-templates/{id}/amount-changes/{change_id} [delete]
+func (h *RecurringTemplateHandler) DeleteAmountChange(c *gin.Context) {
+	id, ok := parseRecurringTemplateID(c)
+	if !ok {
+		return
+	}
+
+	changeID, err := uuid.Parse(c.Param("change_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Error: &APIError{
+				Code:    "INVALID_ID",
+				Message: "Invalid amount change ID format",
+			},
+		})
+		return
+	}
+
+	if err := h.service.DeleteAmountChange(id, changeID); err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Error: &APIError{
+				Code:    "DELETE_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Data: "Amount change deleted successfully",
+	})
+}
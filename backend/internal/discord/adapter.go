@@ -29,10 +29,16 @@ type CreditCardPaymentCreator interface {
 	CreatePaymentFromBot(input *BotCCPaymentInput) (string, float64, error)
 }
 
+// CreditCardStatementProvider provides the latest closed statement of a credit card.
+type CreditCardStatementProvider interface {
+	GetCurrentStatement(creditCardID uuid.UUID) (*models.CreditCardStatement, error)
+}
+
 type CreditCardPaymentAdapter struct {
-	svc      service.CashFlowService
-	cardRepo repository.CreditCardRepository
-	catRepo  repository.CategoryRepository
+	svc          service.CashFlowService
+	cardRepo     repository.CreditCardRepository
+	catRepo      repository.CategoryRepository
+	statementSvc CreditCardStatementProvider
 }
 
 // NewCashFlowServiceAdapter wraps a CashFlowService for bot usage.
@@ -40,8 +46,10 @@ func NewCashFlowServiceAdapter(svc service.CashFlowService) *CashFlowServiceAdap
 	return &CashFlowServiceAdapter{svc: svc}
 }
 
-func NewCreditCardPaymentAdapter(svc service.CashFlowService, cardRepo repository.CreditCardRepository, catRepo repository.CategoryRepository) *CreditCardPaymentAdapter {
-	return &CreditCardPaymentAdapter{svc: svc, cardRepo: cardRepo, catRepo: catRepo}
+// NewCreditCardPaymentAdapter wraps the services used by the cc_payment flow.
+// statementSvc may be nil, in which case full payments fall back to the card's used credit.
+func NewCreditCardPaymentAdapter(svc service.CashFlowService, cardRepo repository.CreditCardRepository, catRepo repository.CategoryRepository, statementSvc CreditCardStatementProvider) *CreditCardPaymentAdapter {
+	return &CreditCardPaymentAdapter{svc: svc, cardRepo: cardRepo, catRepo: catRepo, statementSvc: statementSvc}
 }
 
 func (a *CashFlowServiceAdapter) CreateCashFlowFromBot(input *BotCashFlowInput) (string, error) {
@@ -103,13 +111,9 @@ func (a *CreditCardPaymentAdapter) CreatePaymentFromBot(input *BotCCPaymentInput
 		return "", 0, err
 	}
 
-	amount := input.Amount
-	if input.PaymentType == "full" {
-		card, err := a.cardRepo.GetByID(creditCardID)
-		if err != nil {
-			return "", 0, err
-		}
-		amount = card.UsedCredit
+	amount, err := a.resolvePaymentAmount(creditCardID, input)
+	if err != nil {
+		return "", 0, err
 	}
 
 	sourceType := models.SourceTypeBankAccount
@@ -134,6 +138,42 @@ func (a *CreditCardPaymentAdapter) CreatePaymentFromBot(input *BotCCPaymentInput
 	return record.ID.String(), amount, nil
 }
 
+// resolvePaymentAmount determines the amount to pay for full and minimum payments.
+// The latest statement's outstanding amount is used when available; without a
+// statement, full payments use the card's used credit and minimum payments keep
+// the amount the user typed.
+func (a *CreditCardPaymentAdapter) resolvePaymentAmount(creditCardID uuid.UUID, input *BotCCPaymentInput) (float64, error) {
+	if input.PaymentType != "full" && input.PaymentType != "minimum" {
+		return input.Amount, nil
+	}
+	if input.PaymentType == "minimum" && input.Amount > 0 {
+		return input.Amount, nil
+	}
+
+	if a.statementSvc != nil {
+		statement, err := a.statementSvc.GetCurrentStatement(creditCardID)
+		if err != nil {
+			return 0, err
+		}
+		if statement != nil {
+			if input.PaymentType == "minimum" {
+				return statement.RemainingMinimumPayment(), nil
+			}
+			return statement.OutstandingAmount(), nil
+		}
+	}
+
+	if input.PaymentType == "minimum" {
+		return input.Amount, nil
+	}
+
+	card, err := a.cardRepo.GetByID(creditCardID)
+	if err != nil {
+		return 0, err
+	}
+	return card.UsedCredit, nil
+}
+
 // CategoryRepoAdapter bridges repository.CategoryRepository to CategoryLoader.
 type CategoryRepoAdapter struct {
 	repo repository.CategoryRepository
@@ -1,6 +1,7 @@
 package discord
 
 import (
+	"database/sql"
 	"errors"
 	"testing"
 	"time"
@@ -22,6 +23,14 @@ func (m *mockCashFlowQueryService) CreateCashFlow(input *models.CreateCashFlowIn
 	panic("unexpected call to CreateCashFlow")
 }
 
+func (m *mockCashFlowQueryService) CreateCashFlowTx(tx *sql.Tx, input *models.CreateCashFlowInput) (*models.CashFlow, error) {
+	panic("unexpected call to CreateCashFlowTx")
+}
+
+func (m *mockCashFlowQueryService) DeleteCashFlowTx(tx *sql.Tx, id uuid.UUID) error {
+	panic("unexpected call to DeleteCashFlowTx")
+}
+
 func (m *mockCashFlowQueryService) GetCashFlow(id uuid.UUID) (*models.CashFlow, error) {
 	panic("unexpected call to GetCashFlow")
 }
@@ -65,6 +74,10 @@ func (m *mockBankAccountQueryRepo) GetByID(id uuid.UUID) (*models.BankAccount, e
 	panic("unexpected call to GetByID")
 }
 
+func (m *mockBankAccountQueryRepo) GetByIDForUpdateTx(tx *sql.Tx, id uuid.UUID) (*models.BankAccount, error) {
+	panic("unexpected call to GetByIDForUpdateTx")
+}
+
 func (m *mockBankAccountQueryRepo) GetAll(currency *models.Currency) ([]*models.BankAccount, error) {
 	return m.accounts, m.err
 }
@@ -77,6 +90,10 @@ func (m *mockBankAccountQueryRepo) UpdateBalance(id uuid.UUID, amount float64) (
 	panic("unexpected call to UpdateBalance")
 }
 
+func (m *mockBankAccountQueryRepo) UpdateBalanceTx(tx *sql.Tx, id uuid.UUID, amount float64) (*models.BankAccount, error) {
+	panic("unexpected call to UpdateBalanceTx")
+}
+
 func (m *mockBankAccountQueryRepo) Delete(id uuid.UUID) error {
 	panic("unexpected call to Delete")
 }
@@ -97,6 +114,14 @@ func (m *mockCCPaymentCashFlowService) CreateCashFlow(input *models.CreateCashFl
 	return m.createResult, m.err
 }
 
+func (m *mockCCPaymentCashFlowService) CreateCashFlowTx(tx *sql.Tx, input *models.CreateCashFlowInput) (*models.CashFlow, error) {
+	panic("unexpected call to CreateCashFlowTx")
+}
+
+func (m *mockCCPaymentCashFlowService) DeleteCashFlowTx(tx *sql.Tx, id uuid.UUID) error {
+	panic("unexpected call to DeleteCashFlowTx")
+}
+
 func (m *mockCCPaymentCashFlowService) GetCashFlow(id uuid.UUID) (*models.CashFlow, error) {
 	panic("unexpected call to GetCashFlow")
 }
@@ -125,6 +150,15 @@ func (m *mockCCPaymentCashFlowService) GetYearlySummaryWithComparison(year int)
 	panic("unexpected call to GetYearlySummaryWithComparison")
 }
 
+type mockCCPaymentStatementProvider struct {
+	statement *models.CreditCardStatement
+	err       error
+}
+
+func (m *mockCCPaymentStatementProvider) GetCurrentStatement(creditCardID uuid.UUID) (*models.CreditCardStatement, error) {
+	return m.statement, m.err
+}
+
 type mockCCPaymentCreditCardRepo struct {
 	card     *models.CreditCard
 	err      error
@@ -140,6 +174,10 @@ func (m *mockCCPaymentCreditCardRepo) GetByID(id uuid.UUID) (*models.CreditCard,
 	return m.card, m.err
 }
 
+func (m *mockCCPaymentCreditCardRepo) GetByIDForUpdateTx(tx *sql.Tx, id uuid.UUID) (*models.CreditCard, error) {
+	panic("unexpected call to GetByIDForUpdateTx")
+}
+
 func (m *mockCCPaymentCreditCardRepo) GetAll() ([]*models.CreditCard, error) {
 	panic("unexpected call to GetAll")
 }
@@ -168,6 +206,10 @@ func (m *mockCCPaymentCreditCardRepo) UpdateUsedCredit(id uuid.UUID, amount floa
 	panic("unexpected call to UpdateUsedCredit")
 }
 
+func (m *mockCCPaymentCreditCardRepo) UpdateUsedCreditTx(tx *sql.Tx, id uuid.UUID, amount float64) (*models.CreditCard, error) {
+	panic("unexpected call to UpdateUsedCreditTx")
+}
+
 func (m *mockCCPaymentCreditCardRepo) Delete(id uuid.UUID) error {
 	panic("unexpected call to Delete")
 }
@@ -208,6 +250,10 @@ func (m *mockCreditCardQueryRepo) GetByID(id uuid.UUID) (*models.CreditCard, err
 	panic("unexpected call to GetByID")
 }
 
+func (m *mockCreditCardQueryRepo) GetByIDForUpdateTx(tx *sql.Tx, id uuid.UUID) (*models.CreditCard, error) {
+	panic("unexpected call to GetByIDForUpdateTx")
+}
+
 func (m *mockCreditCardQueryRepo) GetAll() ([]*models.CreditCard, error) {
 	return m.cards, m.err
 }
@@ -236,6 +282,10 @@ func (m *mockCreditCardQueryRepo) UpdateUsedCredit(id uuid.UUID, amount float64)
 	panic("unexpected call to UpdateUsedCredit")
 }
 
+func (m *mockCreditCardQueryRepo) UpdateUsedCreditTx(tx *sql.Tx, id uuid.UUID, amount float64) (*models.CreditCard, error) {
+	panic("unexpected call to UpdateUsedCreditTx")
+}
+
 func (m *mockCreditCardQueryRepo) Delete(id uuid.UUID) error {
 	panic("unexpected call to Delete")
 }
@@ -383,7 +433,7 @@ func TestCCPaymentAdapter_CustomAmount(t *testing.T) {
 	recordID := uuid.New()
 	svc := &mockCCPaymentCashFlowService{createResult: &models.CashFlow{ID: recordID}}
 	catRepo := &mockCCPaymentCategoryRepo{categories: []*models.CashFlowCategory{{ID: transferCatID, Name: "移轉", Type: models.CashFlowTypeTransferOut}}}
-	adapter := NewCreditCardPaymentAdapter(svc, &mockCCPaymentCreditCardRepo{}, catRepo)
+	adapter := NewCreditCardPaymentAdapter(svc, &mockCCPaymentCreditCardRepo{}, catRepo, nil)
 
 	id, actualAmount, err := adapter.CreatePaymentFromBot(&BotCCPaymentInput{
 		CreditCardID:  creditCardID.String(),
@@ -419,7 +469,7 @@ func TestCCPaymentAdapter_FullPayment(t *testing.T) {
 	svc := &mockCCPaymentCashFlowService{createResult: &models.CashFlow{ID: recordID}}
 	cardRepo := &mockCCPaymentCreditCardRepo{card: &models.CreditCard{ID: creditCardID, UsedCredit: 23500}}
 	catRepo := &mockCCPaymentCategoryRepo{categories: []*models.CashFlowCategory{{ID: transferCatID, Type: models.CashFlowTypeTransferOut}}}
-	adapter := NewCreditCardPaymentAdapter(svc, cardRepo, catRepo)
+	adapter := NewCreditCardPaymentAdapter(svc, cardRepo, catRepo, nil)
 
 	id, actualAmount, err := adapter.CreatePaymentFromBot(&BotCCPaymentInput{
 		CreditCardID:  creditCardID.String(),
@@ -444,7 +494,7 @@ func TestCCPaymentAdapter_MinimumPayment(t *testing.T) {
 	transferCatID := uuid.New()
 	svc := &mockCCPaymentCashFlowService{createResult: &models.CashFlow{ID: recordID}}
 	catRepo := &mockCCPaymentCategoryRepo{categories: []*models.CashFlowCategory{{ID: transferCatID, Type: models.CashFlowTypeTransferOut}}}
-	adapter := NewCreditCardPaymentAdapter(svc, &mockCCPaymentCreditCardRepo{}, catRepo)
+	adapter := NewCreditCardPaymentAdapter(svc, &mockCCPaymentCreditCardRepo{}, catRepo, nil)
 
 	id, actualAmount, err := adapter.CreatePaymentFromBot(&BotCCPaymentInput{
 		CreditCardID:  creditCardID.String(),
@@ -461,13 +511,58 @@ func TestCCPaymentAdapter_MinimumPayment(t *testing.T) {
 	require.Equal(t, 3000.0, svc.createInput.Amount)
 }
 
+func TestCCPaymentAdapter_FullPayment_UsesStatement(t *testing.T) {
+	bankAccountID := uuid.New()
+	creditCardID := uuid.New()
+	recordID := uuid.New()
+	transferCatID := uuid.New()
+	svc := &mockCCPaymentCashFlowService{createResult: &models.CashFlow{ID: recordID}}
+	cardRepo := &mockCCPaymentCreditCardRepo{card: &models.CreditCard{ID: creditCardID, UsedCredit: 23500}}
+	catRepo := &mockCCPaymentCategoryRepo{categories: []*models.CashFlowCategory{{ID: transferCatID, Type: models.CashFlowTypeTransferOut}}}
+	statements := &mockCCPaymentStatementProvider{statement: &models.CreditCardStatement{ClosingBalance: 18000, MinimumPayment: 1800, PaidAmount: 3000}}
+	adapter := NewCreditCardPaymentAdapter(svc, cardRepo, catRepo, statements)
+
+	_, actualAmount, err := adapter.CreatePaymentFromBot(&BotCCPaymentInput{
+		CreditCardID:  creditCardID.String(),
+		BankAccountID: bankAccountID.String(),
+		Date:          "2026-04-08",
+		PaymentType:   "full",
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, 15000.0, actualAmount)
+	require.Equal(t, 15000.0, svc.createInput.Amount)
+}
+
+func TestCCPaymentAdapter_MinimumPayment_UsesStatement(t *testing.T) {
+	bankAccountID := uuid.New()
+	creditCardID := uuid.New()
+	recordID := uuid.New()
+	transferCatID := uuid.New()
+	svc := &mockCCPaymentCashFlowService{createResult: &models.CashFlow{ID: recordID}}
+	catRepo := &mockCCPaymentCategoryRepo{categories: []*models.CashFlowCategory{{ID: transferCatID, Type: models.CashFlowTypeTransferOut}}}
+	statements := &mockCCPaymentStatementProvider{statement: &models.CreditCardStatement{ClosingBalance: 18000, MinimumPayment: 1800, PaidAmount: 500}}
+	adapter := NewCreditCardPaymentAdapter(svc, &mockCCPaymentCreditCardRepo{}, catRepo, statements)
+
+	_, actualAmount, err := adapter.CreatePaymentFromBot(&BotCCPaymentInput{
+		CreditCardID:  creditCardID.String(),
+		BankAccountID: bankAccountID.String(),
+		Date:          "2026-04-08",
+		PaymentType:   "minimum",
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, 1300.0, actualAmount)
+	require.Equal(t, 1300.0, svc.createInput.Amount)
+}
+
 func TestCCPaymentAdapter_Failure(t *testing.T) {
 	bankAccountID := uuid.New()
 	creditCardID := uuid.New()
 	transferCatID := uuid.New()
 	svc := &mockCCPaymentCashFlowService{err: errors.New("create failed")}
 	catRepo := &mockCCPaymentCategoryRepo{categories: []*models.CashFlowCategory{{ID: transferCatID, Type: models.CashFlowTypeTransferOut}}}
-	adapter := NewCreditCardPaymentAdapter(svc, &mockCCPaymentCreditCardRepo{}, catRepo)
+	adapter := NewCreditCardPaymentAdapter(svc, &mockCCPaymentCreditCardRepo{}, catRepo, nil)
 
 	id, actualAmount, err := adapter.CreatePaymentFromBot(&BotCCPaymentInput{
 		CreditCardID:  creditCardID.String(),
@@ -490,7 +585,7 @@ func TestCCPaymentAdapter_FullPayment_ZeroUsedCredit(t *testing.T) {
 	svc := &mockCCPaymentCashFlowService{createResult: &models.CashFlow{ID: recordID}}
 	cardRepo := &mockCCPaymentCreditCardRepo{card: &models.CreditCard{ID: creditCardID, UsedCredit: 0}}
 	catRepo := &mockCCPaymentCategoryRepo{categories: []*models.CashFlowCategory{{ID: transferCatID, Type: models.CashFlowTypeTransferOut}}}
-	adapter := NewCreditCardPaymentAdapter(svc, cardRepo, catRepo)
+	adapter := NewCreditCardPaymentAdapter(svc, cardRepo, catRepo, nil)
 
 	id, actualAmount, err := adapter.CreatePaymentFromBot(&BotCCPaymentInput{
 		CreditCardID:  creditCardID.String(),
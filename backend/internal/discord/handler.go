@@ -400,7 +400,7 @@ func (h *Handler) handleInteraction(s discordSession, i *discordgo.InteractionCr
 }
 
 func (h *Handler) handleCCPayment(s discordSession, channelID string, result *ParseResult, authorID string) {
-	if result.PaymentType != "full" && hasMissingField(result.MissingFields, "amount") {
+	if result.PaymentType != "full" && result.PaymentType != "minimum" && hasMissingField(result.MissingFields, "amount") {
 		h.sendText(s, channelID, GetMessage(h.lang, MsgCCPaymentMissingAmount)+"\n"+GetMessage(h.lang, MsgCCPaymentUsageExamples))
 		return
 	}
@@ -2,6 +2,7 @@ package external
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -14,13 +15,15 @@ type AlphaVantageClient struct {
 	apiKey     string
 	baseURL    string
 	httpClient *http.Client
+	budget     *requestBudget
 }
 
 // AlphaVantageGlobalQuoteResponse Alpha Vantage Global Quote 回應
 type AlphaVantageGlobalQuoteResponse struct {
-	GlobalQuote AlphaVantageQuote `json:"Global Quote"`
-	Note        string            `json:"Note"`        // API 速率限制訊息
-	Information string            `json:"Information"` // API 錯誤訊息
+	GlobalQuote  AlphaVantageQuote `json:"Global Quote"`
+	Note         string            `json:"Note"`          // 每分鐘呼叫頻率限制訊息
+	Information  string            `json:"Information"`   // 每日額度用完或付費功能限制訊息
+	ErrorMessage string            `json:"Error Message"` // 無效請求（例如查無標的）
 }
 
 // AlphaVantageQuote Alpha Vantage 報價資料
@@ -45,12 +48,24 @@ func NewAlphaVantageClient(apiKey string) *AlphaVantageClient {
 		httpClient: &http.Client{
 			Timeout: 10 * time.Second,
 		},
+		budget: newRequestBudget(ProviderAlphaVantage),
 	}
 }
 
-// GetStockPrice 取得美股即時價格
+// GetStockPrice 取得美股即時價格（受速率限制並自動重試暫時性錯誤）
 // symbol: 股票代碼（例如：AAPL, GOOGL）
 func (c *AlphaVantageClient) GetStockPrice(symbol string) (float64, error) {
+	var price float64
+	err := c.budget.Do(func() error {
+		var err error
+		price, err = c.fetchStockPrice(symbol)
+		return err
+	})
+	return price, err
+}
+
+// fetchStockPrice 發送單次 GLOBAL_QUOTE 請求
+func (c *AlphaVantageClient) fetchStockPrice(symbol string) (float64, error) {
 	// Alpha Vantage API 端點：GLOBAL_QUOTE
 	url := fmt.Sprintf("%s?function=GLOBAL_QUOTE&symbol=%s&apikey=%s",
 		c.baseURL,
@@ -68,7 +83,7 @@ func (c *AlphaVantageClient) GetStockPrice(symbol string) (float64, error) {
 	// 檢查 HTTP 狀態碼
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return 0, fmt.Errorf("Alpha Vantage API error: status=%d, body=%s", resp.StatusCode, string(body))
+		return 0, &APIError{Provider: "Alpha Vantage", StatusCode: resp.StatusCode, Body: string(body)}
 	}
 
 	// 解析 JSON 回應
@@ -77,19 +92,21 @@ func (c *AlphaVantageClient) GetStockPrice(symbol string) (float64, error) {
 		return 0, fmt.Errorf("failed to decode response: %w", err)
 	}
 
-	// 檢查 API 速率限制
+	// 依回應欄位對應錯誤類型（Alpha Vantage 不以 HTTP 狀態碼表示限制）
 	if result.Note != "" {
-		return 0, fmt.Errorf("Alpha Vantage API rate limit: %s", result.Note)
+		return 0, fmt.Errorf("Alpha Vantage API rate limit: %s: %w", result.Note, ErrRateLimited)
 	}
-
-	// 檢查 API 錯誤訊息
 	if result.Information != "" {
-		return 0, fmt.Errorf("Alpha Vantage API error: %s", result.Information)
+		// 每分鐘頻率已由本地限制器控制，Information 代表此 API key 今日無法再取得報價
+		return 0, fmt.Errorf("Alpha Vantage API limit: %s: %w", result.Information, ErrQuotaExhausted)
+	}
+	if result.ErrorMessage != "" {
+		return 0, fmt.Errorf("Alpha Vantage API error for symbol %s: %s: %w", symbol, result.ErrorMessage, ErrSymbolNotFound)
 	}
 
 	// 檢查是否有資料
 	if result.GlobalQuote.Symbol == "" {
-		return 0, fmt.Errorf("no price data found for symbol: %s", symbol)
+		return 0, fmt.Errorf("no price data found for symbol %s: %w", symbol, ErrSymbolNotFound)
 	}
 
 	// 解析價格字串為 float64
@@ -103,18 +120,19 @@ func (c *AlphaVantageClient) GetStockPrice(symbol string) (float64, error) {
 
 // GetMultipleStockPrices 批次取得多個美股價格
 // 注意：Alpha Vantage 免費版不支援批次查詢，需要逐一查詢
-// 免費版限制：每分鐘 5 次請求，每天 100 次請求
+// 請求間隔由共用的速率限制器控制（免費版每分鐘 5 次、每日 25 次）
 func (c *AlphaVantageClient) GetMultipleStockPrices(symbols []string) (map[string]float64, error) {
 	prices := make(map[string]float64)
+	var lastErr error
 
-	for i, symbol := range symbols {
-		// 為了避免超過速率限制，每次請求之間延遲 12 秒（免費版每分鐘 5 次）
-		if i > 0 {
-			time.Sleep(12 * time.Second)
-		}
-
+	for _, symbol := range symbols {
 		price, err := c.GetStockPrice(symbol)
 		if err != nil {
+			lastErr = err
+			// 已達速率限制時後續請求也會失敗，直接停止
+			if errors.Is(err, ErrRateLimited) {
+				break
+			}
 			// 如果單一股票查詢失敗，記錄錯誤但繼續處理其他股票
 			fmt.Printf("Warning: failed to get price for %s: %v\n", symbol, err)
 			continue
@@ -123,6 +141,11 @@ func (c *AlphaVantageClient) GetMultipleStockPrices(symbols []string) (map[strin
 		prices[symbol] = price
 	}
 
+	// 完全沒有取得價格時回傳錯誤，讓呼叫端得知失敗原因
+	if len(prices) == 0 && lastErr != nil {
+		return nil, lastErr
+	}
+
 	return prices, nil
 }
 
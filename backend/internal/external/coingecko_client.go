@@ -14,6 +14,9 @@ type CoinGeckoClient struct {
 	apiKey     string
 	baseURL    string
 	httpClient *http.Client
+	budget     *requestBudget
+
+	coinIDResolver func(symbol string) (string, bool) // 可為 nil
 }
 
 // CoinGeckoPriceResponse CoinGecko 價格回應
@@ -30,28 +33,47 @@ func NewCoinGeckoClient(apiKey string) *CoinGeckoClient {
 		httpClient: &http.Client{
 			Timeout: 10 * time.Second,
 		},
+		budget: newRequestBudget(ProviderCoinGecko),
 	}
 }
 
-// symbolToCoinID 將常見的加密貨幣代碼轉換為 CoinGecko ID
+// defaultCoinGeckoIDs 常見加密貨幣代碼對應的 CoinGecko ID（商品主檔未匯入時使用，匯入時同代碼以此為準）
+var defaultCoinGeckoIDs = map[string]string{
+	"BTC":   "bitcoin",
+	"ETH":   "ethereum",
+	"USDT":  "tether",
+	"USDC":  "usd-coin",
+	"BNB":   "binancecoin",
+	"XRP":   "ripple",
+	"ADA":   "cardano",
+	"DOGE":  "dogecoin",
+	"SOL":   "solana",
+	"MATIC": "matic-network",
+	"DOT":   "polkadot",
+	"AVAX":  "avalanche-2",
+}
+
+// DefaultCoinGeckoID 取得內建的 CoinGecko ID
+func DefaultCoinGeckoID(symbol string) (string, bool) {
+	coinID, exists := defaultCoinGeckoIDs[strings.ToUpper(symbol)]
+	return coinID, exists
+}
+
+// SetCoinIDResolver 設定代碼轉 CoinGecko ID 的查詢方式（例如商品主檔），查無時改用內建對應
+func (c *CoinGeckoClient) SetCoinIDResolver(resolver func(symbol string) (string, bool)) {
+	c.coinIDResolver = resolver
+}
+
+// symbolToCoinID 將加密貨幣代碼轉換為 CoinGecko ID
 func (c *CoinGeckoClient) symbolToCoinID(symbol string) string {
-	// CoinGecko 使用小寫的完整名稱作為 ID
-	symbolMap := map[string]string{
-		"BTC":  "bitcoin",
-		"ETH":  "ethereum",
-		"USDT": "tether",
-		"USDC": "usd-coin",
-		"BNB":  "binancecoin",
-		"XRP":  "ripple",
-		"ADA":  "cardano",
-		"DOGE": "dogecoin",
-		"SOL":  "solana",
-		"MATIC": "matic-network",
-		"DOT":  "polkadot",
-		"AVAX": "avalanche-2",
+	if c.coinIDResolver != nil {
+		if coinID, exists := c.coinIDResolver(symbol); exists {
+			return coinID
+		}
 	}
 
-	if coinID, exists := symbolMap[strings.ToUpper(symbol)]; exists {
+	// CoinGecko 使用小寫的完整名稱作為 ID
+	if coinID, exists := DefaultCoinGeckoID(symbol); exists {
 		return coinID
 	}
 
@@ -74,35 +96,15 @@ func (c *CoinGeckoClient) GetCryptoPrice(symbol string, currency string) (float6
 		c.apiKey,
 	)
 
-	// 發送 HTTP 請求
-	resp, err := c.httpClient.Get(url)
+	result, err := c.fetchSimplePrice(url)
 	if err != nil {
 		return 0, fmt.Errorf("failed to fetch crypto price: %w", err)
 	}
-	defer resp.Body.Close()
-
-	// 檢查 HTTP 狀態碼
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return 0, fmt.Errorf("CoinGecko API error: status=%d, body=%s", resp.StatusCode, string(body))
-	}
-
-	// 解析 JSON 回應
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return 0, fmt.Errorf("failed to read response: %w", err)
-	}
-
-	// 解析為 map[string]map[string]float64
-	var result map[string]map[string]float64
-	if err := json.Unmarshal(body, &result); err != nil {
-		return 0, fmt.Errorf("failed to decode response: %w", err)
-	}
 
 	// 檢查是否有資料
 	priceData, exists := result[coinID]
 	if !exists {
-		return 0, fmt.Errorf("no price data found for coin: %s", symbol)
+		return 0, fmt.Errorf("no price data found for coin %s: %w", symbol, ErrSymbolNotFound)
 	}
 
 	price, exists := priceData[currency]
@@ -141,29 +143,10 @@ func (c *CoinGeckoClient) GetMultipleCryptoPrices(symbols []string, currency str
 		c.apiKey,
 	)
 
-	// 發送 HTTP 請求
-	resp, err := c.httpClient.Get(url)
+	result, err := c.fetchSimplePrice(url)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch crypto prices: %w", err)
 	}
-	defer resp.Body.Close()
-
-	// 檢查 HTTP 狀態碼
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("CoinGecko API error: status=%d, body=%s", resp.StatusCode, string(body))
-	}
-
-	// 解析 JSON 回應
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
-	}
-
-	var result map[string]map[string]float64
-	if err := json.Unmarshal(body, &result); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
-	}
 
 	// 將結果轉換回原始 symbol
 	prices := make(map[string]float64)
@@ -177,3 +160,37 @@ func (c *CoinGeckoClient) GetMultipleCryptoPrices(symbols []string, currency str
 	return prices, nil
 }
 
+// fetchSimplePrice 在速率限制下發送 simple/price 請求（自動重試暫時性錯誤）
+// 回傳格式：{ "bitcoin": { "usd": 43210.12 } }
+func (c *CoinGeckoClient) fetchSimplePrice(url string) (map[string]map[string]float64, error) {
+	var result map[string]map[string]float64
+
+	err := c.budget.Do(func() error {
+		// 發送 HTTP 請求
+		resp, err := c.httpClient.Get(url)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		// 檢查 HTTP 狀態碼（429 表示超過速率限制）
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			return &APIError{Provider: "CoinGecko", StatusCode: resp.StatusCode, Body: string(body)}
+		}
+
+		// 解析 JSON 回應
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("failed to read response: %w", err)
+		}
+
+		result = nil
+		if err := json.Unmarshal(body, &result); err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+		return nil
+	})
+
+	return result, err
+}
@@ -0,0 +1,57 @@
+package external
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+var (
+	// ErrRateLimited 超過外部 API 的速率限制（本地限制器拒絕或 API 回應速率限制）
+	ErrRateLimited = errors.New("rate limited")
+
+	// ErrQuotaExhausted 每日請求額度已用完（同時符合 ErrRateLimited，但不應重試）
+	ErrQuotaExhausted = fmt.Errorf("daily quota exhausted: %w", ErrRateLimited)
+
+	// ErrSymbolNotFound 外部 API 查無標的價格資料
+	ErrSymbolNotFound = errors.New("symbol not found")
+)
+
+// APIError 外部 API 回應非預期的 HTTP 狀態碼
+type APIError struct {
+	Provider   string // 來源顯示名稱（例如 CoinGecko）
+	StatusCode int
+	Body       string
+}
+
+// Error 實作 error 介面
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s API error: status=%d, body=%s", e.Provider, e.StatusCode, e.Body)
+}
+
+// Unwrap HTTP 429 視為速率限制
+func (e *APIError) Unwrap() error {
+	if e.StatusCode == http.StatusTooManyRequests {
+		return ErrRateLimited
+	}
+	return nil
+}
+
+// isRetryable 判斷錯誤是否為暫時性錯誤（速率限制、伺服器錯誤、網路錯誤）
+func isRetryable(err error) bool {
+	if errors.Is(err, ErrQuotaExhausted) || errors.Is(err, ErrSymbolNotFound) {
+		return false
+	}
+	if errors.Is(err, ErrRateLimited) {
+		return true
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= http.StatusInternalServerError
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
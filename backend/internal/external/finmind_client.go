@@ -2,6 +2,7 @@ package external
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -13,6 +14,7 @@ type FinMindClient struct {
 	apiKey     string
 	baseURL    string
 	httpClient *http.Client
+	budget     *requestBudget
 }
 
 // FinMindResponse FinMind API 回應結構
@@ -44,12 +46,24 @@ func NewFinMindClient(apiKey string) *FinMindClient {
 		httpClient: &http.Client{
 			Timeout: 10 * time.Second,
 		},
+		budget: newRequestBudget(ProviderFinMind),
 	}
 }
 
-// GetStockPrice 取得台股即時價格
+// GetStockPrice 取得台股即時價格（受速率限制並自動重試暫時性錯誤）
 // symbol: 股票代碼（例如：2330）
 func (c *FinMindClient) GetStockPrice(symbol string) (float64, error) {
+	var price float64
+	err := c.budget.Do(func() error {
+		var err error
+		price, err = c.fetchStockPrice(symbol)
+		return err
+	})
+	return price, err
+}
+
+// fetchStockPrice 發送單次 TaiwanStockPrice 請求
+func (c *FinMindClient) fetchStockPrice(symbol string) (float64, error) {
 	// FinMind API 端點：取得最新收盤價
 	url := fmt.Sprintf("%s/data?dataset=TaiwanStockPrice&data_id=%s&start_date=%s&token=%s",
 		c.baseURL,
@@ -68,7 +82,7 @@ func (c *FinMindClient) GetStockPrice(symbol string) (float64, error) {
 	// 檢查 HTTP 狀態碼
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return 0, fmt.Errorf("FinMind API error: status=%d, body=%s", resp.StatusCode, string(body))
+		return 0, &APIError{Provider: "FinMind", StatusCode: resp.StatusCode, Body: string(body)}
 	}
 
 	// 解析 JSON 回應
@@ -77,14 +91,17 @@ func (c *FinMindClient) GetStockPrice(symbol string) (float64, error) {
 		return 0, fmt.Errorf("failed to decode response: %w", err)
 	}
 
-	// 檢查 API 回應狀態
+	// 檢查 API 回應狀態（402 表示已達每小時請求上限）
+	if result.Status == http.StatusPaymentRequired {
+		return 0, fmt.Errorf("FinMind API rate limit: %s: %w", result.Msg, ErrRateLimited)
+	}
 	if result.Status != 200 {
 		return 0, fmt.Errorf("FinMind API error: status=%d, msg=%s", result.Status, result.Msg)
 	}
 
 	// 檢查是否有資料
 	if len(result.Data) == 0 {
-		return 0, fmt.Errorf("no price data found for symbol: %s", symbol)
+		return 0, fmt.Errorf("no price data found for symbol %s: %w", symbol, ErrSymbolNotFound)
 	}
 
 	// 返回最新的收盤價（最後一筆資料）
@@ -103,17 +120,28 @@ func (c *FinMindClient) getRecentDate() string {
 // GetMultipleStockPrices 批次取得多個台股價格
 func (c *FinMindClient) GetMultipleStockPrices(symbols []string) (map[string]float64, error) {
 	prices := make(map[string]float64)
-	
+	var lastErr error
+
 	for _, symbol := range symbols {
 		price, err := c.GetStockPrice(symbol)
 		if err != nil {
+			lastErr = err
+			// 已達速率限制時後續請求也會失敗，直接停止
+			if errors.Is(err, ErrRateLimited) {
+				break
+			}
 			// 如果單一股票查詢失敗，記錄錯誤但繼續處理其他股票
 			fmt.Printf("Warning: failed to get price for %s: %v\n", symbol, err)
 			continue
 		}
 		prices[symbol] = price
 	}
-	
+
+	// 完全沒有取得價格時回傳錯誤，讓呼叫端得知失敗原因
+	if len(prices) == 0 && lastErr != nil {
+		return nil, lastErr
+	}
+
 	return prices, nil
 }
 
@@ -0,0 +1,354 @@
+package external
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// 外部 API 來源名稱（速率限制器以此區分額度）
+const (
+	ProviderFinMind      = "finmind"
+	ProviderYahoo        = "yahoo"
+	ProviderAlphaVantage = "alpha_vantage"
+	ProviderCoinGecko    = "coingecko"
+)
+
+// RateLimit 外部 API 速率限制設定
+type RateLimit struct {
+	RequestsPerMinute float64       // 令牌補充速率
+	Burst             int           // 令牌桶容量（可連續發送的請求數）
+	DailyQuota        int           // 每日請求上限（UTC 日期，0 表示不限制）
+	MaxWait           time.Duration // 等待令牌的最長時間，超過即回傳 ErrRateLimited 讓呼叫端改用其他來源
+}
+
+// defaultRateLimits 各來源預設速率限制（依免費方案）
+var defaultRateLimits = map[string]RateLimit{
+	// FinMind 免費版每小時 600 次
+	ProviderFinMind: {RequestsPerMinute: 10, Burst: 10, MaxWait: 10 * time.Second},
+	// Yahoo Finance 非官方 API，保守限制避免被封鎖
+	ProviderYahoo: {RequestsPerMinute: 60, Burst: 5, MaxWait: 10 * time.Second},
+	// Alpha Vantage 免費版每分鐘 5 次、每日 25 次
+	ProviderAlphaVantage: {RequestsPerMinute: 5, Burst: 1, DailyQuota: 25, MaxWait: 15 * time.Second},
+	// CoinGecko Demo 方案每分鐘約 30 次
+	ProviderCoinGecko: {RequestsPerMinute: 30, Burst: 5, MaxWait: 10 * time.Second},
+}
+
+// RateLimiter 外部 API 請求速率限制器
+type RateLimiter interface {
+	// Acquire 取得一次請求額度，必要時等待令牌補充
+	// 每日額度用完時回傳 ErrQuotaExhausted，需等待超過 MaxWait 時回傳 ErrRateLimited
+	Acquire() error
+
+	// Usage 取得今日已使用的請求次數與每日上限（0 表示不限制）
+	Usage() (used int, quota int)
+}
+
+var (
+	limitersMu   sync.Mutex
+	limiters     = make(map[string]RateLimiter)
+	limiterRedis *redis.Client
+)
+
+// UseRedisRateLimiting 改由 Redis 協調所有來源的速率限制，讓多個程序共用同一份額度
+// 應在建立價格服務前呼叫；傳入 nil 則改回程序內的限制器
+func UseRedisRateLimiting(client *redis.Client) {
+	limitersMu.Lock()
+	defer limitersMu.Unlock()
+
+	limiterRedis = client
+	limiters = make(map[string]RateLimiter)
+}
+
+// ProviderRateLimiter 取得來源共用的速率限制器（同一程序內所有客戶端與 goroutine 共用）
+func ProviderRateLimiter(provider string) RateLimiter {
+	limitersMu.Lock()
+	defer limitersMu.Unlock()
+
+	if limiter, exists := limiters[provider]; exists {
+		return limiter
+	}
+
+	limit, exists := defaultRateLimits[provider]
+	if !exists {
+		limit = RateLimit{RequestsPerMinute: 60, Burst: 5, MaxWait: 10 * time.Second}
+	}
+
+	var limiter RateLimiter = newTokenBucketLimiter(limit)
+	if limiterRedis != nil {
+		limiter = newRedisRateLimiter(limiterRedis, provider, limit)
+	}
+	limiters[provider] = limiter
+	return limiter
+}
+
+// ==================== 程序內令牌桶 ====================
+
+// tokenBucketLimiter 程序內的令牌桶限制器
+type tokenBucketLimiter struct {
+	mu     sync.Mutex
+	limit  RateLimit
+	tokens float64 // 可用令牌數（負值代表已被預約、正在等待的請求）
+	last   time.Time
+	day    string
+	used   int
+
+	now   func() time.Time
+	sleep func(time.Duration)
+}
+
+// newTokenBucketLimiter 建立程序內令牌桶限制器（初始為滿桶）
+func newTokenBucketLimiter(limit RateLimit) *tokenBucketLimiter {
+	return &tokenBucketLimiter{
+		limit:  limit,
+		tokens: float64(limit.Burst),
+		now:    time.Now,
+		sleep:  time.Sleep,
+	}
+}
+
+// Acquire 取得一次請求額度
+func (l *tokenBucketLimiter) Acquire() error {
+	l.mu.Lock()
+
+	now := l.now()
+	l.resetDay(now)
+	if l.limit.DailyQuota > 0 && l.used >= l.limit.DailyQuota {
+		l.mu.Unlock()
+		return ErrQuotaExhausted
+	}
+
+	// 補充令牌
+	rate := l.limit.RequestsPerMinute / float64(time.Minute)
+	if !l.last.IsZero() && now.After(l.last) {
+		l.tokens = math.Min(float64(l.limit.Burst), l.tokens+float64(now.Sub(l.last))*rate)
+	}
+	l.last = now
+
+	var wait time.Duration
+	if l.tokens < 1 {
+		wait = time.Duration(math.Ceil((1 - l.tokens) / rate))
+	}
+	if wait > l.limit.MaxWait {
+		l.mu.Unlock()
+		return fmt.Errorf("request would wait %v: %w", wait.Round(time.Second), ErrRateLimited)
+	}
+
+	// 預約令牌後再等待，讓並行的請求依序排隊
+	l.tokens--
+	l.used++
+	l.mu.Unlock()
+
+	if wait > 0 {
+		l.sleep(wait)
+	}
+	return nil
+}
+
+// Usage 取得今日已使用的請求次數與每日上限
+func (l *tokenBucketLimiter) Usage() (int, int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.resetDay(l.now())
+	return l.used, l.limit.DailyQuota
+}
+
+// resetDay 跨日時重設每日用量
+func (l *tokenBucketLimiter) resetDay(now time.Time) {
+	day := now.UTC().Format("2006-01-02")
+	if l.day != day {
+		l.day = day
+		l.used = 0
+	}
+}
+
+// ==================== Redis 令牌桶 ====================
+
+// redisTokenBucketScript 以 Redis 伺服器時間計算令牌桶，回傳需等待的毫秒數（超過最長等待時間則回傳 -1 且不預約）
+var redisTokenBucketScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local max_wait = tonumber(ARGV[3])
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if tokens == nil or ts == nil then
+  tokens = burst
+  ts = now
+end
+if now > ts then
+  tokens = math.min(burst, tokens + (now - ts) * rate)
+  ts = now
+end
+
+local wait = 0
+if tokens < 1 then
+  wait = math.ceil((1 - tokens) / rate)
+end
+if wait > max_wait then
+  return -1
+end
+
+tokens = tokens - 1
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', tostring(ts))
+redis.call('PEXPIRE', KEYS[1], math.ceil(burst / rate) + wait + 1000)
+return wait
+`)
+
+// redisRateLimiter 透過 Redis 協調的限制器（多個程序共用額度），Redis 無法使用時改用程序內限制器
+type redisRateLimiter struct {
+	client   *redis.Client
+	provider string
+	limit    RateLimit
+	local    *tokenBucketLimiter
+}
+
+// newRedisRateLimiter 建立 Redis 限制器
+func newRedisRateLimiter(client *redis.Client, provider string, limit RateLimit) *redisRateLimiter {
+	return &redisRateLimiter{
+		client:   client,
+		provider: provider,
+		limit:    limit,
+		local:    newTokenBucketLimiter(limit),
+	}
+}
+
+// Acquire 取得一次請求額度
+func (l *redisRateLimiter) Acquire() error {
+	ctx := context.Background()
+
+	dailyKey := l.dailyKey(time.Now())
+	if l.limit.DailyQuota > 0 {
+		used, err := l.client.Incr(ctx, dailyKey).Result()
+		if err != nil {
+			log.Printf("Warning: redis rate limiter unavailable for %s, using local limiter: %v", l.provider, err)
+			return l.local.Acquire()
+		}
+		if used == 1 {
+			l.client.Expire(ctx, dailyKey, 48*time.Hour)
+		}
+		if used > int64(l.limit.DailyQuota) {
+			return ErrQuotaExhausted
+		}
+	}
+
+	rate := l.limit.RequestsPerMinute / float64(time.Minute/time.Millisecond)
+	wait, err := redisTokenBucketScript.Run(ctx, l.client, []string{l.bucketKey()},
+		rate, l.limit.Burst, l.limit.MaxWait.Milliseconds()).Int64()
+	if err != nil {
+		log.Printf("Warning: redis rate limiter unavailable for %s, using local limiter: %v", l.provider, err)
+		return l.local.Acquire()
+	}
+
+	if wait < 0 {
+		// 未送出請求，退回已計入的每日用量
+		if l.limit.DailyQuota > 0 {
+			l.client.Decr(ctx, dailyKey)
+		}
+		return fmt.Errorf("request would wait more than %v: %w", l.limit.MaxWait, ErrRateLimited)
+	}
+
+	if l.limit.DailyQuota == 0 {
+		l.client.Incr(ctx, dailyKey)
+		l.client.Expire(ctx, dailyKey, 48*time.Hour)
+	}
+
+	if wait > 0 {
+		time.Sleep(time.Duration(wait) * time.Millisecond)
+	}
+	return nil
+}
+
+// Usage 取得今日已使用的請求次數與每日上限
+func (l *redisRateLimiter) Usage() (int, int) {
+	used, err := l.client.Get(context.Background(), l.dailyKey(time.Now())).Int()
+	if err != nil && err != redis.Nil {
+		return l.local.Usage()
+	}
+	if l.limit.DailyQuota > 0 && used > l.limit.DailyQuota {
+		// 超過上限後被拒絕的請求也會計入，顯示時以上限為準
+		used = l.limit.DailyQuota
+	}
+	return used, l.limit.DailyQuota
+}
+
+// bucketKey 令牌桶的 Redis key
+func (l *redisRateLimiter) bucketKey() string {
+	return fmt.Sprintf("ratelimit:%s:bucket", l.provider)
+}
+
+// dailyKey 每日用量的 Redis key（UTC 日期）
+func (l *redisRateLimiter) dailyKey(now time.Time) string {
+	return fmt.Sprintf("ratelimit:%s:daily:%s", l.provider, now.UTC().Format("2006-01-02"))
+}
+
+// ==================== 重試 ====================
+
+// RetryPolicy 外部 API 重試設定（指數退避）
+type RetryPolicy struct {
+	MaxRetries int
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+}
+
+// defaultRetryPolicy 預設重試設定：最多重試 2 次，間隔 1 秒、2 秒
+var defaultRetryPolicy = RetryPolicy{
+	MaxRetries: 2,
+	BaseDelay:  1 * time.Second,
+	MaxDelay:   10 * time.Second,
+}
+
+// backoff 第 attempt 次重試前的等待時間
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay << attempt
+	if delay > p.MaxDelay || delay <= 0 {
+		return p.MaxDelay
+	}
+	return delay
+}
+
+// requestBudget 外部 API 請求預算：每次請求前取得速率限制額度，暫時性錯誤以指數退避重試
+type requestBudget struct {
+	provider string
+	limiter  RateLimiter // 為 nil 時使用來源共用的限制器
+	retry    RetryPolicy
+	sleep    func(time.Duration)
+}
+
+// newRequestBudget 建立使用共用限制器與預設重試設定的請求預算
+func newRequestBudget(provider string) *requestBudget {
+	return &requestBudget{
+		provider: provider,
+		retry:    defaultRetryPolicy,
+		sleep:    time.Sleep,
+	}
+}
+
+// Do 在速率限制下執行請求
+func (b *requestBudget) Do(request func() error) error {
+	limiter := b.limiter
+	if limiter == nil {
+		limiter = ProviderRateLimiter(b.provider)
+	}
+
+	for attempt := 0; ; attempt++ {
+		if err := limiter.Acquire(); err != nil {
+			return err
+		}
+
+		err := request()
+		if err == nil || !isRetryable(err) || attempt >= b.retry.MaxRetries {
+			return err
+		}
+		b.sleep(b.retry.backoff(attempt))
+	}
+}
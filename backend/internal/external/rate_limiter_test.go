@@ -0,0 +1,269 @@
+package external
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock 測試用時鐘，sleep 直接推進時間
+type fakeClock struct {
+	now   time.Time
+	slept []time.Duration
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func (c *fakeClock) Sleep(d time.Duration) {
+	c.slept = append(c.slept, d)
+	c.now = c.now.Add(d)
+}
+
+func newTestLimiter(limit RateLimit, clock *fakeClock) *tokenBucketLimiter {
+	limiter := newTokenBucketLimiter(limit)
+	limiter.now = clock.Now
+	limiter.sleep = clock.Sleep
+	return limiter
+}
+
+// unlimitedLimiter 測試用不限制的限制器
+type unlimitedLimiter struct{ acquired int }
+
+func (l *unlimitedLimiter) Acquire() error { l.acquired++; return nil }
+
+func (l *unlimitedLimiter) Usage() (int, int) { return l.acquired, 0 }
+
+func newTestBudget(limiter RateLimiter) *requestBudget {
+	return &requestBudget{
+		limiter: limiter,
+		retry:   defaultRetryPolicy,
+		sleep:   func(time.Duration) {},
+	}
+}
+
+func TestTokenBucketLimiter_WaitsForRefill(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)}
+	limiter := newTestLimiter(RateLimit{RequestsPerMinute: 5, Burst: 1, MaxWait: 15 * time.Second}, clock)
+
+	// 第一個請求使用滿桶的令牌，不需等待
+	require.NoError(t, limiter.Acquire())
+	assert.Empty(t, clock.slept)
+
+	// 第二個請求需等待令牌補充（每分鐘 5 次 = 12 秒一個）
+	require.NoError(t, limiter.Acquire())
+	require.Len(t, clock.slept, 1)
+	assert.Equal(t, 12*time.Second, clock.slept[0])
+}
+
+func TestTokenBucketLimiter_RejectsWhenWaitExceedsMax(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)}
+	limiter := newTestLimiter(RateLimit{RequestsPerMinute: 5, Burst: 1, MaxWait: 5 * time.Second}, clock)
+
+	require.NoError(t, limiter.Acquire())
+
+	err := limiter.Acquire()
+	assert.ErrorIs(t, err, ErrRateLimited)
+	assert.NotErrorIs(t, err, ErrQuotaExhausted)
+
+	// 被拒絕的請求不計入每日用量
+	used, _ := limiter.Usage()
+	assert.Equal(t, 1, used)
+}
+
+func TestTokenBucketLimiter_DailyQuota(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2026, 3, 2, 23, 0, 0, 0, time.UTC)}
+	limiter := newTestLimiter(RateLimit{RequestsPerMinute: 60, Burst: 10, DailyQuota: 2, MaxWait: time.Second}, clock)
+
+	require.NoError(t, limiter.Acquire())
+	require.NoError(t, limiter.Acquire())
+
+	err := limiter.Acquire()
+	assert.ErrorIs(t, err, ErrQuotaExhausted)
+	assert.ErrorIs(t, err, ErrRateLimited)
+
+	used, quota := limiter.Usage()
+	assert.Equal(t, 2, used)
+	assert.Equal(t, 2, quota)
+
+	// 跨日（UTC）後重設額度
+	clock.now = clock.now.Add(2 * time.Hour)
+	require.NoError(t, limiter.Acquire())
+	used, _ = limiter.Usage()
+	assert.Equal(t, 1, used)
+}
+
+func TestRequestBudget_RetriesTransientErrors(t *testing.T) {
+	limiter := &unlimitedLimiter{}
+	budget := newTestBudget(limiter)
+
+	calls := 0
+	err := budget.Do(func() error {
+		calls++
+		if calls < 3 {
+			return &APIError{Provider: "Test", StatusCode: http.StatusTooManyRequests}
+		}
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, calls)
+	// 每次重試都重新取得速率限制額度
+	assert.Equal(t, 3, limiter.acquired)
+}
+
+func TestRequestBudget_DoesNotRetryPermanentErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"symbol not found", fmt.Errorf("no price data found for symbol XYZ: %w", ErrSymbolNotFound)},
+		{"quota exhausted", ErrQuotaExhausted},
+		{"bad request", &APIError{Provider: "Test", StatusCode: http.StatusBadRequest}},
+		{"decode error", errors.New("failed to decode response")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			budget := newTestBudget(&unlimitedLimiter{})
+
+			calls := 0
+			err := budget.Do(func() error {
+				calls++
+				return tt.err
+			})
+
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, 1, calls)
+		})
+	}
+}
+
+func TestRequestBudget_GivesUpAfterMaxRetries(t *testing.T) {
+	budget := newTestBudget(&unlimitedLimiter{})
+
+	calls := 0
+	err := budget.Do(func() error {
+		calls++
+		return &APIError{Provider: "Test", StatusCode: http.StatusServiceUnavailable}
+	})
+
+	require.Error(t, err)
+	assert.Equal(t, defaultRetryPolicy.MaxRetries+1, calls)
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{MaxRetries: 5, BaseDelay: time.Second, MaxDelay: 5 * time.Second}
+
+	assert.Equal(t, 1*time.Second, policy.backoff(0))
+	assert.Equal(t, 2*time.Second, policy.backoff(1))
+	assert.Equal(t, 4*time.Second, policy.backoff(2))
+	assert.Equal(t, 5*time.Second, policy.backoff(3))
+}
+
+func TestClients_TypedErrors(t *testing.T) {
+	t.Run("alpha vantage note is rate limited", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{"Note": "Thank you for using Alpha Vantage! Our standard API call frequency is 5 calls per minute."}`)
+		}))
+		defer server.Close()
+
+		client := &AlphaVantageClient{baseURL: server.URL, httpClient: server.Client(), budget: newTestBudget(&unlimitedLimiter{})}
+		_, err := client.GetStockPrice("AAPL")
+
+		assert.ErrorIs(t, err, ErrRateLimited)
+	})
+
+	t.Run("alpha vantage daily limit is quota exhausted", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{"Information": "Our standard API rate limit is 25 requests per day."}`)
+		}))
+		defer server.Close()
+
+		limiter := &unlimitedLimiter{}
+		client := &AlphaVantageClient{baseURL: server.URL, httpClient: server.Client(), budget: newTestBudget(limiter)}
+		_, err := client.GetMultipleStockPrices([]string{"AAPL", "MSFT"})
+
+		assert.ErrorIs(t, err, ErrQuotaExhausted)
+		// 額度用完後不再重試，也不繼續查詢其他標的
+		assert.Equal(t, 1, limiter.acquired)
+	})
+
+	t.Run("alpha vantage information is quota exhausted", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{"Information": "Thank you for using Alpha Vantage! This is a premium endpoint."}`)
+		}))
+		defer server.Close()
+
+		client := &AlphaVantageClient{baseURL: server.URL, httpClient: server.Client(), budget: newTestBudget(&unlimitedLimiter{})}
+		_, err := client.GetStockPrice("AAPL")
+
+		assert.ErrorIs(t, err, ErrQuotaExhausted)
+	})
+
+	t.Run("alpha vantage error message is symbol not found", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{"Error Message": "Invalid API call. Please retry or visit the documentation for GLOBAL_QUOTE."}`)
+		}))
+		defer server.Close()
+
+		limiter := &unlimitedLimiter{}
+		client := &AlphaVantageClient{baseURL: server.URL, httpClient: server.Client(), budget: newTestBudget(limiter)}
+		_, err := client.GetStockPrice("ZZZZ")
+
+		assert.ErrorIs(t, err, ErrSymbolNotFound)
+		assert.Equal(t, 1, limiter.acquired)
+	})
+
+	t.Run("finmind empty data is symbol not found", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{"msg": "success", "status": 200, "data": []}`)
+		}))
+		defer server.Close()
+
+		client := &FinMindClient{baseURL: server.URL, httpClient: server.Client(), budget: newTestBudget(&unlimitedLimiter{})}
+		_, err := client.GetStockPrice("9999")
+
+		assert.ErrorIs(t, err, ErrSymbolNotFound)
+	})
+
+	t.Run("coingecko 429 retries then succeeds", func(t *testing.T) {
+		requests := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			if requests == 1 {
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			fmt.Fprint(w, `{"bitcoin": {"usd": 65000}}`)
+		}))
+		defer server.Close()
+
+		client := &CoinGeckoClient{baseURL: server.URL, httpClient: server.Client(), budget: newTestBudget(&unlimitedLimiter{})}
+		prices, err := client.GetMultipleCryptoPrices([]string{"BTC"}, "usd")
+
+		require.NoError(t, err)
+		assert.Equal(t, 65000.0, prices["BTC"])
+		assert.Equal(t, 2, requests)
+	})
+
+	t.Run("yahoo 429 is rate limited after retries", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer server.Close()
+
+		client := &YahooFinanceClient{baseURL: server.URL, httpClient: server.Client(), budget: newTestBudget(&unlimitedLimiter{})}
+		_, err := client.GetMultipleStockPrices([]string{"AAPL"})
+
+		assert.ErrorIs(t, err, ErrRateLimited)
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusTooManyRequests, apiErr.StatusCode)
+	})
+}
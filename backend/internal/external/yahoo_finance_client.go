@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"time"
 )
 
@@ -13,6 +14,7 @@ import (
 type YahooFinanceClient struct {
 	baseURL    string
 	httpClient *http.Client
+	budget     *requestBudget
 }
 
 // YahooQuoteResponse Yahoo Finance Quote 回應
@@ -44,56 +46,25 @@ func NewYahooFinanceClient() *YahooFinanceClient {
 		httpClient: &http.Client{
 			Timeout: 10 * time.Second,
 		},
+		budget: newRequestBudget(ProviderYahoo),
 	}
 }
 
 // GetStockPrice 取得美股即時價格
 // symbol: 股票代碼（例如：AAPL, GOOGL）
 func (c *YahooFinanceClient) GetStockPrice(symbol string) (float64, error) {
-	// Yahoo Finance API 端點：quote
-	url := fmt.Sprintf("%s/quote?symbols=%s", c.baseURL, symbol)
-
-	// 建立 HTTP 請求
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return 0, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	// 設定 User-Agent（Yahoo Finance 需要）
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36")
-
-	// 發送 HTTP 請求
-	resp, err := c.httpClient.Do(req)
+	quotes, err := c.fetchQuotes([]string{symbol})
 	if err != nil {
 		return 0, fmt.Errorf("failed to fetch stock price: %w", err)
 	}
-	defer resp.Body.Close()
-
-	// 檢查 HTTP 狀態碼
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return 0, fmt.Errorf("Yahoo Finance API error: status=%d, body=%s", resp.StatusCode, string(body))
-	}
-
-	// 解析 JSON 回應
-	var result YahooQuoteResponse
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return 0, fmt.Errorf("failed to decode response: %w", err)
-	}
-
-	// 檢查是否有錯誤
-	if result.QuoteResponse.Error != nil {
-		return 0, fmt.Errorf("Yahoo Finance API error: %v", result.QuoteResponse.Error)
-	}
 
 	// 檢查是否有資料
-	if len(result.QuoteResponse.Result) == 0 {
-		return 0, fmt.Errorf("no price data found for symbol: %s", symbol)
+	if len(quotes) == 0 {
+		return 0, fmt.Errorf("no price data found for symbol %s: %w", symbol, ErrSymbolNotFound)
 	}
 
 	// 返回即時市場價格
-	quote := result.QuoteResponse.Result[0]
-	return quote.RegularMarketPrice, nil
+	return quotes[0].RegularMarketPrice, nil
 }
 
 // GetMultipleStockPrices 批次取得多個美股價格
@@ -103,56 +74,62 @@ func (c *YahooFinanceClient) GetMultipleStockPrices(symbols []string) (map[strin
 		return make(map[string]float64), nil
 	}
 
-	// Yahoo Finance 支援批次查詢（用逗號分隔）
-	symbolsStr := ""
-	for i, symbol := range symbols {
-		if i > 0 {
-			symbolsStr += ","
-		}
-		symbolsStr += symbol
-	}
-
-	url := fmt.Sprintf("%s/quote?symbols=%s", c.baseURL, symbolsStr)
-
-	// 建立 HTTP 請求
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	// 設定 User-Agent
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36")
-
-	// 發送 HTTP 請求
-	resp, err := c.httpClient.Do(req)
+	quotes, err := c.fetchQuotes(symbols)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch stock prices: %w", err)
 	}
-	defer resp.Body.Close()
-
-	// 檢查 HTTP 狀態碼
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Yahoo Finance API error: status=%d, body=%s", resp.StatusCode, string(body))
-	}
-
-	// 解析 JSON 回應
-	var result YahooQuoteResponse
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
-	}
-
-	// 檢查是否有錯誤
-	if result.QuoteResponse.Error != nil {
-		return nil, fmt.Errorf("Yahoo Finance API error: %v", result.QuoteResponse.Error)
-	}
 
 	// 將結果轉換為 map
 	prices := make(map[string]float64)
-	for _, quote := range result.QuoteResponse.Result {
+	for _, quote := range quotes {
 		prices[quote.Symbol] = quote.RegularMarketPrice
 	}
 
 	return prices, nil
 }
 
+// fetchQuotes 在速率限制下發送 quote 請求（Yahoo Finance 支援以逗號分隔的批次查詢，自動重試暫時性錯誤）
+func (c *YahooFinanceClient) fetchQuotes(symbols []string) ([]YahooQuote, error) {
+	url := fmt.Sprintf("%s/quote?symbols=%s", c.baseURL, strings.Join(symbols, ","))
+
+	var quotes []YahooQuote
+	err := c.budget.Do(func() error {
+		// 建立 HTTP 請求
+		req, err := http.NewRequest("GET", url, nil)
+		if err != nil {
+			return fmt.Errorf("failed to create request: %w", err)
+		}
+
+		// 設定 User-Agent（Yahoo Finance 需要）
+		req.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36")
+
+		// 發送 HTTP 請求
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		// 檢查 HTTP 狀態碼（429 表示超過速率限制）
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			return &APIError{Provider: "Yahoo Finance", StatusCode: resp.StatusCode, Body: string(body)}
+		}
+
+		// 解析 JSON 回應
+		var result YahooQuoteResponse
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+
+		// 檢查是否有錯誤
+		if result.QuoteResponse.Error != nil {
+			return fmt.Errorf("Yahoo Finance API error: %v", result.QuoteResponse.Error)
+		}
+
+		quotes = result.QuoteResponse.Result
+		return nil
+	})
+
+	return quotes, err
+}
@@ -14,6 +14,7 @@ const (
 	SnapshotAssetTypeTWStock SnapshotAssetType = "tw-stock"
 	SnapshotAssetTypeUSStock SnapshotAssetType = "us-stock"
 	SnapshotAssetTypeCrypto  SnapshotAssetType = "crypto"
+	SnapshotAssetTypeManual  SnapshotAssetType = "manual"
 	SnapshotAssetTypeTotal   SnapshotAssetType = "total"
 )
 
@@ -56,6 +57,7 @@ func (input *CreateAssetSnapshotInput) Validate() error {
 		SnapshotAssetTypeTWStock: true,
 		SnapshotAssetTypeUSStock: true,
 		SnapshotAssetTypeCrypto:  true,
+		SnapshotAssetTypeManual:  true,
 		SnapshotAssetTypeTotal:   true,
 	}
 	if !validTypes[input.AssetType] {
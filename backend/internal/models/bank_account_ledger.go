@@ -0,0 +1,69 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BalanceAdjustmentCategoryName 對帳差額調整使用的系統分類名稱（income 與 expense 各一）
+const BalanceAdjustmentCategoryName = "帳戶調整"
+
+// BankAccountLedgerEntry 銀行帳戶明細帳的單筆記錄
+type BankAccountLedgerEntry struct {
+	CashFlow       *CashFlow `json:"cash_flow"`
+	Change         float64   `json:"change"`          // 對帳戶餘額的影響（正數為存入，負數為支出）
+	RunningBalance float64   `json:"running_balance"` // 該筆記錄後的帳戶餘額
+}
+
+// BankAccountLedger 銀行帳戶明細帳
+type BankAccountLedger struct {
+	Account        *BankAccount              `json:"account"`
+	StartDate      *time.Time                `json:"start_date,omitempty"`
+	EndDate        *time.Time                `json:"end_date,omitempty"`
+	OpeningBalance float64                   `json:"opening_balance"` // 區間開始前的餘額
+	ClosingBalance float64                   `json:"closing_balance"` // 區間結束時的餘額
+	Entries        []*BankAccountLedgerEntry `json:"entries"`         // 依日期由舊到新排序
+}
+
+// BankAccountReconciliation 銀行帳戶對帳記錄
+type BankAccountReconciliation struct {
+	ID                   uuid.UUID  `json:"id" db:"id"`
+	BankAccountID        uuid.UUID  `json:"bank_account_id" db:"bank_account_id"`
+	StatementDate        time.Time  `json:"statement_date" db:"statement_date"`
+	StatementBalance     float64    `json:"statement_balance" db:"statement_balance"`
+	LedgerBalance        float64    `json:"ledger_balance" db:"ledger_balance"` // 調整前帳上餘額
+	Difference           float64    `json:"difference" db:"difference"`         // 對帳單餘額 - 帳上餘額
+	AdjustmentCashFlowID *uuid.UUID `json:"adjustment_cash_flow_id,omitempty" db:"adjustment_cash_flow_id"`
+	Note                 *string    `json:"note,omitempty" db:"note"`
+	CreatedAt            time.Time  `json:"created_at" db:"created_at"`
+}
+
+// ReconcileBankAccountInput 對帳的輸入資料
+type ReconcileBankAccountInput struct {
+	StatementDate    time.Time `json:"statement_date" binding:"required"`
+	StatementBalance float64   `json:"statement_balance"`
+	Note             *string   `json:"note,omitempty" binding:"omitempty,max=1000"`
+}
+
+// BankAccountBalanceChange 計算現金流對指定銀行帳戶餘額的影響
+// 帳戶為付款來源時：收入與轉入增加、支出與轉出減少；帳戶為轉帳目標時：增加
+func (c *CashFlow) BankAccountBalanceChange(accountID uuid.UUID) float64 {
+	change := 0.0
+
+	if c.SourceType != nil && *c.SourceType == SourceTypeBankAccount && c.SourceID != nil && *c.SourceID == accountID {
+		switch c.Type {
+		case CashFlowTypeIncome, CashFlowTypeTransferIn:
+			change += c.Amount
+		case CashFlowTypeExpense, CashFlowTypeTransferOut:
+			change -= c.Amount
+		}
+	}
+
+	if c.Type == CashFlowTypeTransferOut && c.TargetType != nil && *c.TargetType == SourceTypeBankAccount &&
+		c.TargetID != nil && *c.TargetID == accountID {
+		change += c.Amount
+	}
+
+	return change
+}
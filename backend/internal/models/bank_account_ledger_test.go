@@ -0,0 +1,72 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCashFlow_BankAccountBalanceChange 測試現金流對銀行帳戶餘額的影響
+func TestCashFlow_BankAccountBalanceChange(t *testing.T) {
+	accountID := uuid.New()
+	otherID := uuid.New()
+	bankAccount := SourceTypeBankAccount
+	creditCard := SourceTypeCreditCard
+
+	tests := []struct {
+		name     string
+		cashFlow *CashFlow
+		want     float64
+	}{
+		{
+			name:     "income into account",
+			cashFlow: &CashFlow{Type: CashFlowTypeIncome, Amount: 1000, SourceType: &bankAccount, SourceID: &accountID},
+			want:     1000,
+		},
+		{
+			name:     "expense from account",
+			cashFlow: &CashFlow{Type: CashFlowTypeExpense, Amount: 300, SourceType: &bankAccount, SourceID: &accountID},
+			want:     -300,
+		},
+		{
+			name:     "transfer in",
+			cashFlow: &CashFlow{Type: CashFlowTypeTransferIn, Amount: 500, SourceType: &bankAccount, SourceID: &accountID},
+			want:     500,
+		},
+		{
+			name: "transfer out to credit card",
+			cashFlow: &CashFlow{
+				Type: CashFlowTypeTransferOut, Amount: 800,
+				SourceType: &bankAccount, SourceID: &accountID,
+				TargetType: &creditCard, TargetID: &otherID,
+			},
+			want: -800,
+		},
+		{
+			name: "transfer target from another account",
+			cashFlow: &CashFlow{
+				Type: CashFlowTypeTransferOut, Amount: 2000,
+				SourceType: &bankAccount, SourceID: &otherID,
+				TargetType: &bankAccount, TargetID: &accountID,
+			},
+			want: 2000,
+		},
+		{
+			name:     "other account",
+			cashFlow: &CashFlow{Type: CashFlowTypeExpense, Amount: 100, SourceType: &bankAccount, SourceID: &otherID},
+			want:     0,
+		},
+		{
+			name:     "credit card expense",
+			cashFlow: &CashFlow{Type: CashFlowTypeExpense, Amount: 100, SourceType: &creditCard, SourceID: &accountID},
+			want:     0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.cashFlow.BankAccountBalanceChange(accountID))
+		})
+	}
+}
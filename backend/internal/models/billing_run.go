@@ -0,0 +1,65 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BillingSourceType 扣款來源類型
+type BillingSourceType string
+
+const (
+	BillingSourceSubscription BillingSourceType = "subscription" // 訂閱
+	BillingSourceInstallment  BillingSourceType = "installment"  // 分期
+	BillingSourceLoan         BillingSourceType = "loan"         // 貸款
+	BillingSourceRecurring    BillingSourceType = "recurring"    // 定期收支
+)
+
+// BillingRunStatus 每日扣款執行狀態
+type BillingRunStatus string
+
+const (
+	BillingRunStatusSuccess BillingRunStatus = "success" // 成功
+	BillingRunStatusFailed  BillingRunStatus = "failed"  // 失敗
+)
+
+// BillingRecord 扣款記錄（同一來源同一期只會有一筆）
+type BillingRecord struct {
+	ID          uuid.UUID         `json:"id" db:"id"`
+	SourceType  BillingSourceType `json:"source_type" db:"source_type"`
+	SourceID    uuid.UUID         `json:"source_id" db:"source_id"`
+	Period      string            `json:"period" db:"period"` // 扣款期別（YYYY-MM；定期收支為 YYYY-MM-DD）
+	BillingDate time.Time         `json:"billing_date" db:"billing_date"`
+	Amount      float64           `json:"amount" db:"amount"`
+	CreatedAt   time.Time         `json:"created_at" db:"created_at"`
+}
+
+// BillingRun 每日扣款執行記錄
+type BillingRun struct {
+	ID             uuid.UUID        `json:"id" db:"id"`
+	BillingDate    time.Time        `json:"billing_date" db:"billing_date"`
+	Status         BillingRunStatus `json:"status" db:"status"`
+	ProcessedCount int              `json:"processed_count" db:"processed_count"`
+	SkippedCount   int              `json:"skipped_count" db:"skipped_count"`
+	FailedCount    int              `json:"failed_count" db:"failed_count"`
+	TotalAmount    float64          `json:"total_amount" db:"total_amount"`
+	ErrorMessage   *string          `json:"error_message,omitempty" db:"error_message"`
+	CreatedAt      time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time        `json:"updated_at" db:"updated_at"`
+}
+
+// BillingPeriod 取得扣款日期所屬的期別（訂閱、分期與貸款皆為每月扣款一次）
+func BillingPeriod(date time.Time) string {
+	return date.Format("2006-01")
+}
+
+// RecurringBillingPeriod 取得定期收支的期別（可能每日或每週發生，以發生日為期別）
+func RecurringBillingPeriod(date time.Time) string {
+	return date.Format("2006-01-02")
+}
+
+// BillingDate 將時間截斷為扣款日期（去除時分秒）
+func BillingDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
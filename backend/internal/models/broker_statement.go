@@ -0,0 +1,50 @@
+package models
+
+import "github.com/google/uuid"
+
+// BrokerStatementFormat 券商對帳單格式
+type BrokerStatementFormat string
+
+const (
+	BrokerStatementFormatYuanta    BrokerStatementFormat = "yuanta"    // 元大證券成交明細（CSV，UTF-8 或 Big5）
+	BrokerStatementFormatFubon     BrokerStatementFormat = "fubon"     // 富邦證券成交明細（CSV，UTF-8 或 Big5）
+	BrokerStatementFormatSinoPac   BrokerStatementFormat = "sinopac"   // 永豐金證券成交明細（CSV，UTF-8 或 Big5）
+	BrokerStatementFormatIBKR      BrokerStatementFormat = "ibkr"      // Interactive Brokers Flex Query（XML 或 CSV）
+	BrokerStatementFormatFirstrade BrokerStatementFormat = "firstrade" // Firstrade 帳戶歷史（CSV）
+	BrokerStatementFormatBinance   BrokerStatementFormat = "binance"   // Binance 現貨交易歷史（CSV）
+)
+
+// Validate 驗證 BrokerStatementFormat 是否有效
+func (f BrokerStatementFormat) Validate() bool {
+	switch f {
+	case BrokerStatementFormatYuanta, BrokerStatementFormatFubon, BrokerStatementFormatSinoPac,
+		BrokerStatementFormatIBKR, BrokerStatementFormatFirstrade, BrokerStatementFormatBinance:
+		return true
+	}
+	return false
+}
+
+// BrokerStatementFormatInfo 支援的對帳單格式說明
+type BrokerStatementFormatInfo struct {
+	Format      BrokerStatementFormat `json:"format"`
+	Name        string                `json:"name"`
+	AssetType   AssetType             `json:"asset_type"`
+	Description string                `json:"description"`
+}
+
+// BrokerStatementEntry 對帳單解析出的單筆交易
+type BrokerStatementEntry struct {
+	Row                    int                     `json:"row"`                                // 對帳單中的行號（XML 為第幾筆記錄，從 1 開始）
+	Transaction            *CreateTransactionInput `json:"transaction"`                        // 可直接送出批次建立的交易資料
+	Duplicate              bool                    `json:"duplicate"`                          // 是否與既有交易重複
+	DuplicateTransactionID *uuid.UUID              `json:"duplicate_transaction_id,omitempty"` // 重複的既有交易
+}
+
+// BrokerStatementPreview 對帳單匯入預覽（尚未寫入資料庫）
+type BrokerStatementPreview struct {
+	Format         BrokerStatementFormat   `json:"format"`
+	Entries        []*BrokerStatementEntry `json:"entries"`
+	Errors         []CSVValidationError    `json:"errors,omitempty"`   // 無法解析的行（不會出現在 entries）
+	Warnings       []CSVValidationError    `json:"warnings,omitempty"` // 不影響匯入的提醒（例如手續費與設定不符、略過的記錄）
+	DuplicateCount int                     `json:"duplicate_count"`
+}
@@ -0,0 +1,103 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SettlementCategoryName 交割銀行帳戶現金流使用的系統分類名稱（transfer_in 與 transfer_out 各一）
+const SettlementCategoryName = "證券交割"
+
+// BrokerageAccount 券商帳戶模型
+// 持有證券與交割現金；設定交割銀行帳戶時，買賣與股息的現金直接由該銀行帳戶收付
+type BrokerageAccount struct {
+	ID                  uuid.UUID  `json:"id" db:"id"`
+	Name                string     `json:"name" db:"name"`
+	Broker              string     `json:"broker" db:"broker"`
+	Currency            Currency   `json:"currency" db:"currency"`
+	CashBalance         float64    `json:"cash_balance" db:"cash_balance"`                             // 券商端現金餘額（未設定交割帳戶時使用）
+	SettlementAccountID *uuid.UUID `json:"settlement_account_id,omitempty" db:"settlement_account_id"` // 交割銀行帳戶
+	Note                *string    `json:"note,omitempty" db:"note"`
+	CreatedAt           time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// BrokerageCashMovement 券商帳戶的交割現金異動（由交易產生）
+type BrokerageCashMovement struct {
+	ID                 uuid.UUID       `json:"id" db:"id"`
+	BrokerageAccountID uuid.UUID       `json:"brokerage_account_id" db:"brokerage_account_id"`
+	TransactionID      uuid.UUID       `json:"transaction_id" db:"transaction_id"`
+	Type               TransactionType `json:"type" db:"type"`
+	Amount             float64         `json:"amount" db:"amount"` // 正數為流入、負數為流出（含手續費與交易稅）
+	TradeDate          time.Time       `json:"trade_date" db:"trade_date"`
+	SettlementDate     time.Time       `json:"settlement_date" db:"settlement_date"`
+	BankAccountID      *uuid.UUID      `json:"bank_account_id,omitempty" db:"bank_account_id"` // 由交割銀行帳戶收付時的帳戶
+	CashFlowID         *uuid.UUID      `json:"cash_flow_id,omitempty" db:"cash_flow_id"`       // 交割銀行帳戶的現金流記錄
+	CreatedAt          time.Time       `json:"created_at" db:"created_at"`
+}
+
+// CreateBrokerageAccountInput 建立券商帳戶的輸入資料
+type CreateBrokerageAccountInput struct {
+	Name                string     `json:"name" binding:"required,max=255"`
+	Broker              string     `json:"broker" binding:"required,max=255"`
+	Currency            Currency   `json:"currency" binding:"required,oneof=TWD USD"`
+	CashBalance         float64    `json:"cash_balance" binding:"gte=0"`
+	SettlementAccountID *uuid.UUID `json:"settlement_account_id,omitempty"`
+	Note                *string    `json:"note,omitempty" binding:"omitempty,max=1000"`
+}
+
+// UpdateBrokerageAccountInput 更新券商帳戶的輸入資料
+type UpdateBrokerageAccountInput struct {
+	Name                *string    `json:"name,omitempty" binding:"omitempty,max=255"`
+	Broker              *string    `json:"broker,omitempty" binding:"omitempty,max=255"`
+	CashBalance         *float64   `json:"cash_balance,omitempty" binding:"omitempty,gte=0"`
+	SettlementAccountID *uuid.UUID `json:"settlement_account_id,omitempty"`
+	Note                *string    `json:"note,omitempty" binding:"omitempty,max=1000"`
+}
+
+// SettlementDate 計算交易的交割日
+// 台股為 T+2、美股與美股選擇權為 T+1（以營業日計算，未考慮國定假日），其餘資產於交易日當天交割
+func SettlementDate(assetType AssetType, tradeDate time.Time) time.Time {
+	switch assetType {
+	case AssetTypeTWStock:
+		return addBusinessDays(tradeDate, 2)
+	case AssetTypeUSStock, AssetTypeUSOption:
+		return addBusinessDays(tradeDate, 1)
+	}
+	return tradeDate
+}
+
+// addBusinessDays 往後推算指定的營業日數（略過週六、週日）
+func addBusinessDays(date time.Time, days int) time.Time {
+	for days > 0 {
+		date = date.AddDate(0, 0, 1)
+		if date.Weekday() != time.Saturday && date.Weekday() != time.Sunday {
+			days--
+		}
+	}
+	return date
+}
+
+// CashAmount 交易對交割現金的淨影響（正數為流入、負數為流出）
+// 買進與回補為成交金額加手續費與交易稅；賣出、放空與股息為金額扣除手續費與交易稅
+func (t *Transaction) CashAmount() float64 {
+	fee := 0.0
+	if t.Fee != nil {
+		fee = *t.Fee
+	}
+	tax := 0.0
+	if t.Tax != nil {
+		tax = *t.Tax
+	}
+
+	switch t.TransactionType {
+	case TransactionTypeBuy, TransactionTypeCover:
+		return -(t.Amount + fee + tax)
+	case TransactionTypeSell, TransactionTypeShort, TransactionTypeDividend:
+		return t.Amount - fee - tax
+	case TransactionTypeFee:
+		return -t.Amount
+	}
+	return 0
+}
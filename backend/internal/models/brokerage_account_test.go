@@ -0,0 +1,55 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSettlementDate 測試交割日計算
+func TestSettlementDate(t *testing.T) {
+	thursday := time.Date(2025, 10, 16, 0, 0, 0, 0, time.UTC)
+	friday := time.Date(2025, 10, 17, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		assetType AssetType
+		tradeDate time.Time
+		want      time.Time
+	}{
+		{name: "台股 T+2 跨週末", assetType: AssetTypeTWStock, tradeDate: thursday, want: time.Date(2025, 10, 20, 0, 0, 0, 0, time.UTC)},
+		{name: "台股週五成交於下週二交割", assetType: AssetTypeTWStock, tradeDate: friday, want: time.Date(2025, 10, 21, 0, 0, 0, 0, time.UTC)},
+		{name: "美股 T+1", assetType: AssetTypeUSStock, tradeDate: friday, want: time.Date(2025, 10, 20, 0, 0, 0, 0, time.UTC)},
+		{name: "加密貨幣當日交割", assetType: AssetTypeCrypto, tradeDate: friday, want: friday},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, SettlementDate(tt.assetType, tt.tradeDate))
+		})
+	}
+}
+
+// TestTransaction_CashAmount 測試交易的交割現金淨額
+func TestTransaction_CashAmount(t *testing.T) {
+	fee := 142.0
+	tax := 300.0
+
+	tests := []struct {
+		name        string
+		transaction Transaction
+		want        float64
+	}{
+		{name: "買進含手續費", transaction: Transaction{TransactionType: TransactionTypeBuy, Amount: 100000, Fee: &fee}, want: -100142},
+		{name: "賣出扣除手續費與交易稅", transaction: Transaction{TransactionType: TransactionTypeSell, Amount: 100000, Fee: &fee, Tax: &tax}, want: 99558},
+		{name: "股息", transaction: Transaction{TransactionType: TransactionTypeDividend, Amount: 5000}, want: 5000},
+		{name: "手續費", transaction: Transaction{TransactionType: TransactionTypeFee, Amount: 20}, want: -20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.transaction.CashAmount())
+		})
+	}
+}
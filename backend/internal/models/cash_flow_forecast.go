@@ -0,0 +1,104 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ForecastEventSource 預測事件來源
+type ForecastEventSource string
+
+const (
+	ForecastSourceSubscription      ForecastEventSource = "subscription"        // 訂閱扣款
+	ForecastSourceInstallment       ForecastEventSource = "installment"         // 分期扣款
+	ForecastSourceLoan              ForecastEventSource = "loan"                // 貸款還款
+	ForecastSourceRecurring         ForecastEventSource = "recurring"           // 定期收支
+	ForecastSourceCreditCardPayment ForecastEventSource = "credit_card_payment" // 信用卡繳款
+)
+
+const (
+	// DefaultForecastMonths 預設預測月數
+	DefaultForecastMonths = 3
+	// MaxForecastMonths 最多可預測的月數
+	MaxForecastMonths = 12
+	// DefaultForecastLookbackMonths 計算歷史平均支出時預設回溯的月數
+	DefaultForecastLookbackMonths = 3
+)
+
+// ForecastEvent 預測期間內的單筆排程現金流（金額皆換算為 TWD）
+type ForecastEvent struct {
+	Date         time.Time           `json:"date"`
+	Source       ForecastEventSource `json:"source"`
+	SourceID     uuid.UUID           `json:"source_id"`
+	Name         string              `json:"name"`
+	Amount       float64             `json:"amount"`                   // 正數為流入、負數為流出
+	AccountID    *uuid.UUID          `json:"account_id,omitempty"`     // 影響的銀行帳戶
+	CreditCardID *uuid.UUID          `json:"credit_card_id,omitempty"` // 以信用卡支付時的信用卡（於繳款日才影響銀行帳戶）
+}
+
+// ForecastBalancePoint 單日預測餘額
+type ForecastBalancePoint struct {
+	Date    time.Time `json:"date"`
+	Balance float64   `json:"balance"`
+}
+
+// AccountForecast 單一銀行帳戶的每日預測餘額（TWD）
+type AccountForecast struct {
+	AccountID          uuid.UUID               `json:"account_id"`
+	BankName           string                  `json:"bank_name"`
+	AccountNumberLast4 string                  `json:"account_number_last4"`
+	Currency           Currency                `json:"currency"`
+	StartingBalance    float64                 `json:"starting_balance"`
+	EndingBalance      float64                 `json:"ending_balance"`
+	LowestBalance      float64                 `json:"lowest_balance"`
+	LowestBalanceDate  time.Time               `json:"lowest_balance_date"`
+	AverageDailySpend  float64                 `json:"average_daily_spend"` // 依歷史平均推估的每日非排程支出
+	DailyBalances      []*ForecastBalancePoint `json:"daily_balances"`
+}
+
+// ForecastCategorySpending 依歷史平均推估的分類支出（排除已有排程的分類）
+type ForecastCategorySpending struct {
+	CategoryID     uuid.UUID `json:"category_id"`
+	CategoryName   string    `json:"category_name"`
+	MonthlyAverage float64   `json:"monthly_average"`
+}
+
+// ForecastWarning 帳戶預測餘額低於門檻的警示
+type ForecastWarning struct {
+	AccountID         uuid.UUID `json:"account_id"`
+	AccountName       string    `json:"account_name"`
+	Date              time.Time `json:"date"`                // 第一次低於門檻的日期
+	LowestBalance     float64   `json:"lowest_balance"`      // 預測期間最低餘額
+	LowestBalanceDate time.Time `json:"lowest_balance_date"` // 最低餘額日期
+	Threshold         float64   `json:"threshold"`
+}
+
+// CashFlowForecast 現金流預測結果
+// 總流動性為所有銀行帳戶餘額加總，另含找不到繳款帳戶的信用卡繳款
+type CashFlowForecast struct {
+	StartDate           time.Time                   `json:"start_date"`
+	EndDate             time.Time                   `json:"end_date"`
+	Months              int                         `json:"months"`
+	Threshold           float64                     `json:"threshold"`
+	StartingLiquidity   float64                     `json:"starting_liquidity"`
+	EndingLiquidity     float64                     `json:"ending_liquidity"`
+	LowestLiquidity     float64                     `json:"lowest_liquidity"`
+	LowestLiquidityDate time.Time                   `json:"lowest_liquidity_date"`
+	ScheduledInflow     float64                     `json:"scheduled_inflow"`  // 預測期間排程流入銀行帳戶的總額
+	ScheduledOutflow    float64                     `json:"scheduled_outflow"` // 預測期間排程自銀行帳戶流出的總額（含信用卡繳款）
+	AverageMonthlySpend float64                     `json:"average_monthly_spend"`
+	Accounts            []*AccountForecast          `json:"accounts"`
+	Liquidity           []*ForecastBalancePoint     `json:"liquidity"`
+	Events              []*ForecastEvent            `json:"events"`
+	CategorySpending    []*ForecastCategorySpending `json:"category_spending"`
+	Warnings            []*ForecastWarning          `json:"warnings"`
+}
+
+// AccountName 取得銀行帳戶的顯示名稱
+func (a *AccountForecast) AccountName() string {
+	if a.AccountNumberLast4 == "" {
+		return a.BankName
+	}
+	return a.BankName + " (" + a.AccountNumberLast4 + ")"
+}
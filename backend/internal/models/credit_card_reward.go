@@ -0,0 +1,207 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CreditCardRewardRule 信用卡回饋規則
+// 未指定分類與商家的規則為一般回饋；指定分類或商家關鍵字的規則為加碼回饋
+type CreditCardRewardRule struct {
+	ID              uuid.UUID  `json:"id" db:"id"`
+	CreditCardID    uuid.UUID  `json:"credit_card_id" db:"credit_card_id"`
+	Name            string     `json:"name" db:"name"`
+	CategoryID      *uuid.UUID `json:"category_id,omitempty" db:"category_id"`           // 適用分類（nil 表示不限）
+	MerchantKeyword *string    `json:"merchant_keyword,omitempty" db:"merchant_keyword"` // 加碼商家關鍵字（比對交易描述）
+	Rate            float64    `json:"rate" db:"rate"`                                   // 回饋比例（%）
+	CycleCap        *float64   `json:"cycle_cap,omitempty" db:"cycle_cap"`               // 每期回饋上限
+	IsActive        bool       `json:"is_active" db:"is_active"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// CreateCreditCardRewardRuleInput 建立回饋規則的輸入資料
+type CreateCreditCardRewardRuleInput struct {
+	Name            string     `json:"name" binding:"required,max=255"`
+	CategoryID      *uuid.UUID `json:"category_id,omitempty"`
+	MerchantKeyword *string    `json:"merchant_keyword,omitempty" binding:"omitempty,max=255"`
+	Rate            float64    `json:"rate" binding:"gte=0,lte=100"`
+	CycleCap        *float64   `json:"cycle_cap,omitempty" binding:"omitempty,gt=0"`
+}
+
+// UpdateCreditCardRewardRuleInput 更新回饋規則的輸入資料
+type UpdateCreditCardRewardRuleInput struct {
+	Name            *string    `json:"name,omitempty" binding:"omitempty,max=255"`
+	CategoryID      *uuid.UUID `json:"category_id,omitempty"`
+	MerchantKeyword *string    `json:"merchant_keyword,omitempty" binding:"omitempty,max=255"`
+	Rate            *float64   `json:"rate,omitempty" binding:"omitempty,gte=0,lte=100"`
+	CycleCap        *float64   `json:"cycle_cap,omitempty" binding:"omitempty,gt=0"`
+	IsActive        *bool      `json:"is_active,omitempty"`
+}
+
+// CreditCardRewardAccrual 單筆消費的回饋估算
+type CreditCardRewardAccrual struct {
+	CashFlowID      uuid.UUID  `json:"cash_flow_id"`
+	Date            time.Time  `json:"date"`
+	Description     string     `json:"description"`
+	CategoryID      uuid.UUID  `json:"category_id"`
+	Amount          float64    `json:"amount"`
+	RuleID          *uuid.UUID `json:"rule_id,omitempty"`
+	RuleName        string     `json:"rule_name,omitempty"`
+	Rate            float64    `json:"rate"`
+	EstimatedReward float64    `json:"estimated_reward"` // 未計上限的回饋
+	EarnedReward    float64    `json:"earned_reward"`    // 計入上限後實際獲得的回饋
+	CappedReward    float64    `json:"capped_reward"`    // 因上限而損失的回饋
+}
+
+// CreditCardRewardCycle 單一結帳週期的回饋統計
+type CreditCardRewardCycle struct {
+	PeriodStart     time.Time                  `json:"period_start"`
+	ClosingDate     time.Time                  `json:"closing_date"`
+	Spending        float64                    `json:"spending"`
+	EstimatedReward float64                    `json:"estimated_reward"`
+	EarnedReward    float64                    `json:"earned_reward"`
+	CappedReward    float64                    `json:"capped_reward"`
+	Accruals        []*CreditCardRewardAccrual `json:"accruals"`
+}
+
+// CreditCardRewardReport 信用卡回饋報表（已獲得與超過上限的回饋）
+type CreditCardRewardReport struct {
+	CreditCardID    uuid.UUID                `json:"credit_card_id"`
+	StartDate       time.Time                `json:"start_date"`
+	EndDate         time.Time                `json:"end_date"`
+	TotalSpending   float64                  `json:"total_spending"`
+	EstimatedReward float64                  `json:"estimated_reward"`
+	EarnedReward    float64                  `json:"earned_reward"`
+	CappedReward    float64                  `json:"capped_reward"`
+	EffectiveRate   float64                  `json:"effective_rate"` // 實際回饋率（%）
+	Cycles          []*CreditCardRewardCycle `json:"cycles"`
+}
+
+// CreditCardRewardOption 單張信用卡對某筆消費的回饋試算
+type CreditCardRewardOption struct {
+	CreditCardID    uuid.UUID  `json:"credit_card_id"`
+	IssuingBank     string     `json:"issuing_bank"`
+	CardName        string     `json:"card_name"`
+	RuleID          *uuid.UUID `json:"rule_id,omitempty"`
+	RuleName        string     `json:"rule_name,omitempty"`
+	Rate            float64    `json:"rate"`
+	RemainingCap    *float64   `json:"remaining_cap,omitempty"` // 本期剩餘回饋上限
+	EstimatedReward float64    `json:"estimated_reward"`
+}
+
+// CreditCardRewardRecommendation 指定分類的最佳刷卡建議
+type CreditCardRewardRecommendation struct {
+	CategoryID uuid.UUID                 `json:"category_id"`
+	Merchant   string                    `json:"merchant,omitempty"`
+	Amount     float64                   `json:"amount"`
+	Best       *CreditCardRewardOption   `json:"best,omitempty"`
+	Options    []*CreditCardRewardOption `json:"options"` // 依回饋由高到低排序
+}
+
+// Validate 驗證建立回饋規則的輸入資料
+func (input *CreateCreditCardRewardRuleInput) Validate() error {
+	if strings.TrimSpace(input.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+
+	if input.Rate < 0 || input.Rate > 100 {
+		return fmt.Errorf("rate must be between 0 and 100")
+	}
+
+	if input.CycleCap != nil && *input.CycleCap <= 0 {
+		return fmt.Errorf("cycle_cap must be greater than 0")
+	}
+
+	if input.MerchantKeyword != nil && strings.TrimSpace(*input.MerchantKeyword) == "" {
+		return fmt.Errorf("merchant_keyword cannot be blank")
+	}
+
+	return nil
+}
+
+// Validate 驗證更新回饋規則的輸入資料
+func (input *UpdateCreditCardRewardRuleInput) Validate() error {
+	if input.Name != nil && strings.TrimSpace(*input.Name) == "" {
+		return fmt.Errorf("name cannot be blank")
+	}
+
+	if input.Rate != nil && (*input.Rate < 0 || *input.Rate > 100) {
+		return fmt.Errorf("rate must be between 0 and 100")
+	}
+
+	if input.CycleCap != nil && *input.CycleCap <= 0 {
+		return fmt.Errorf("cycle_cap must be greater than 0")
+	}
+
+	if input.MerchantKeyword != nil && strings.TrimSpace(*input.MerchantKeyword) == "" {
+		return fmt.Errorf("merchant_keyword cannot be blank")
+	}
+
+	return nil
+}
+
+// Matches 檢查規則是否適用於指定分類與交易描述
+func (r *CreditCardRewardRule) Matches(categoryID uuid.UUID, description string) bool {
+	if !r.IsActive {
+		return false
+	}
+
+	if r.CategoryID != nil && *r.CategoryID != categoryID {
+		return false
+	}
+
+	if r.MerchantKeyword != nil &&
+		!strings.Contains(strings.ToLower(description), strings.ToLower(strings.TrimSpace(*r.MerchantKeyword))) {
+		return false
+	}
+
+	return true
+}
+
+// Specificity 規則的明確程度（商家加碼 > 分類加碼 > 一般回饋）
+func (r *CreditCardRewardRule) Specificity() int {
+	specificity := 0
+	if r.MerchantKeyword != nil {
+		specificity += 2
+	}
+	if r.CategoryID != nil {
+		specificity++
+	}
+	return specificity
+}
+
+// MatchCreditCardRewardRule 找出適用於消費的回饋規則
+// 多條規則同時適用時取回饋比例最高者，比例相同時取較明確的規則
+func MatchCreditCardRewardRule(rules []*CreditCardRewardRule, categoryID uuid.UUID, description string) *CreditCardRewardRule {
+	var best *CreditCardRewardRule
+	for _, rule := range rules {
+		if !rule.Matches(categoryID, description) {
+			continue
+		}
+		if best == nil || rule.Rate > best.Rate ||
+			(rule.Rate == best.Rate && rule.Specificity() > best.Specificity()) {
+			best = rule
+		}
+	}
+	return best
+}
+
+// CreditCardReward 計算消費金額依回饋比例的回饋（四捨五入至分）
+func CreditCardReward(amount, rate float64) float64 {
+	return math.Round(amount*rate) / 100
+}
+
+// CycleClosingDate 取得指定日期所屬結帳週期的結帳日
+func (c *CreditCard) CycleClosingDate(date time.Time) time.Time {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	closing := c.LastClosingDate(day)
+	if closing.Before(day) {
+		closing = c.NextClosingDate(closing)
+	}
+	return closing
+}
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestMatchCreditCardRewardRule 測試回饋規則比對
+func TestMatchCreditCardRewardRule(t *testing.T) {
+	diningID := uuid.New()
+	transportID := uuid.New()
+	keyword := "Uber Eats"
+
+	base := &CreditCardRewardRule{ID: uuid.New(), Name: "一般消費", Rate: 1, IsActive: true}
+	dining := &CreditCardRewardRule{ID: uuid.New(), Name: "餐飲加碼", CategoryID: &diningID, Rate: 3, IsActive: true}
+	merchant := &CreditCardRewardRule{ID: uuid.New(), Name: "外送加碼", MerchantKeyword: &keyword, Rate: 5, IsActive: true}
+	inactive := &CreditCardRewardRule{ID: uuid.New(), Name: "停用", Rate: 10, IsActive: false}
+	rules := []*CreditCardRewardRule{base, dining, merchant, inactive}
+
+	assert.Equal(t, merchant, MatchCreditCardRewardRule(rules, diningID, "uber eats 晚餐"))
+	assert.Equal(t, dining, MatchCreditCardRewardRule(rules, diningID, "午餐"))
+	assert.Equal(t, base, MatchCreditCardRewardRule(rules, transportID, "捷運"))
+	assert.Nil(t, MatchCreditCardRewardRule([]*CreditCardRewardRule{inactive}, transportID, "捷運"))
+}
+
+// TestMatchCreditCardRewardRule_PrefersSpecificOnTie 測試回饋比例相同時優先使用較明確的規則
+func TestMatchCreditCardRewardRule_PrefersSpecificOnTie(t *testing.T) {
+	diningID := uuid.New()
+	base := &CreditCardRewardRule{ID: uuid.New(), Rate: 2, IsActive: true}
+	dining := &CreditCardRewardRule{ID: uuid.New(), CategoryID: &diningID, Rate: 2, IsActive: true}
+
+	assert.Equal(t, dining, MatchCreditCardRewardRule([]*CreditCardRewardRule{base, dining}, diningID, ""))
+}
+
+// TestCreditCardReward 測試回饋金額計算
+func TestCreditCardReward(t *testing.T) {
+	assert.Equal(t, 30.0, CreditCardReward(1000, 3))
+	assert.Equal(t, 1.23, CreditCardReward(123, 1))
+	assert.Equal(t, 0.0, CreditCardReward(500, 0))
+}
+
+// TestCreditCard_CycleClosingDate 測試消費所屬結帳週期
+func TestCreditCard_CycleClosingDate(t *testing.T) {
+	card := &CreditCard{BillingDay: 5}
+
+	assert.Equal(t, time.Date(2025, 2, 5, 0, 0, 0, 0, time.UTC), card.CycleClosingDate(time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC), card.CycleClosingDate(time.Date(2025, 1, 5, 15, 30, 0, 0, time.UTC)))
+}
+
+// TestCreateCreditCardRewardRuleInput_Validate 測試建立回饋規則的輸入驗證
+func TestCreateCreditCardRewardRuleInput_Validate(t *testing.T) {
+	blank := "  "
+	zero := 0.0
+
+	assert.NoError(t, (&CreateCreditCardRewardRuleInput{Name: "一般消費", Rate: 1}).Validate())
+	assert.Error(t, (&CreateCreditCardRewardRuleInput{Name: "", Rate: 1}).Validate())
+	assert.Error(t, (&CreateCreditCardRewardRuleInput{Name: "超額", Rate: 120}).Validate())
+	assert.Error(t, (&CreateCreditCardRewardRuleInput{Name: "上限", Rate: 1, CycleCap: &zero}).Validate())
+	assert.Error(t, (&CreateCreditCardRewardRuleInput{Name: "商家", Rate: 1, MerchantKeyword: &blank}).Validate())
+}
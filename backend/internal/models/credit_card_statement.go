@@ -0,0 +1,152 @@
+package models
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CreditCardStatementStatus 信用卡帳單繳款狀態
+type CreditCardStatementStatus string
+
+const (
+	CreditCardStatementStatusUnpaid        CreditCardStatementStatus = "unpaid"         // 未繳
+	CreditCardStatementStatusPartiallyPaid CreditCardStatementStatus = "partially_paid" // 部分繳款
+	CreditCardStatementStatusPaid          CreditCardStatementStatus = "paid"           // 已繳清
+)
+
+const (
+	// CreditCardMinimumPaymentRate 最低應繳比例（本期應繳總額的 10%）
+	CreditCardMinimumPaymentRate = 0.1
+	// CreditCardMinimumPaymentFloor 最低應繳金額下限
+	CreditCardMinimumPaymentFloor = 1000.0
+)
+
+// CreditCardStatement 信用卡帳單（每個結帳週期一筆）
+type CreditCardStatement struct {
+	ID                 uuid.UUID                 `json:"id" db:"id"`
+	CreditCardID       uuid.UUID                 `json:"credit_card_id" db:"credit_card_id"`
+	PeriodStart        time.Time                 `json:"period_start" db:"period_start"` // 週期起日（上期結帳日隔天）
+	ClosingDate        time.Time                 `json:"closing_date" db:"closing_date"` // 結帳日
+	DueDate            time.Time                 `json:"due_date" db:"due_date"`         // 繳款截止日
+	PreviousBalance    float64                   `json:"previous_balance" db:"previous_balance"`
+	NewCharges         float64                   `json:"new_charges" db:"new_charges"`                   // 本期新增消費
+	PaymentsAndCredits float64                   `json:"payments_and_credits" db:"payments_and_credits"` // 本期繳款與退款
+	ClosingBalance     float64                   `json:"closing_balance" db:"closing_balance"`           // 本期應繳總額
+	MinimumPayment     float64                   `json:"minimum_payment" db:"minimum_payment"`           // 最低應繳金額
+	PaidAmount         float64                   `json:"paid_amount" db:"paid_amount"`                   // 結帳後已繳金額
+	Status             CreditCardStatementStatus `json:"status" db:"status"`
+	CreatedAt          time.Time                 `json:"created_at" db:"created_at"`
+	UpdatedAt          time.Time                 `json:"updated_at" db:"updated_at"`
+
+	// 關聯資料（查詢時填入）
+	CreditCard   *CreditCard `json:"credit_card,omitempty" db:"-"`
+	Transactions []*CashFlow `json:"transactions,omitempty" db:"-"`
+}
+
+// Validate 驗證 CreditCardStatementStatus 是否有效
+func (s CreditCardStatementStatus) Validate() bool {
+	switch s {
+	case CreditCardStatementStatusUnpaid, CreditCardStatementStatusPartiallyPaid, CreditCardStatementStatusPaid:
+		return true
+	}
+	return false
+}
+
+// OutstandingAmount 尚未繳清的金額
+func (s *CreditCardStatement) OutstandingAmount() float64 {
+	return math.Max(0, math.Round((s.ClosingBalance-s.PaidAmount)*100)/100)
+}
+
+// RemainingMinimumPayment 尚需繳納的最低應繳金額
+func (s *CreditCardStatement) RemainingMinimumPayment() float64 {
+	return math.Max(0, math.Round((s.MinimumPayment-s.PaidAmount)*100)/100)
+}
+
+// UpdateStatus 依已繳金額更新繳款狀態
+func (s *CreditCardStatement) UpdateStatus() {
+	switch {
+	case s.ClosingBalance <= 0 || s.PaidAmount >= s.ClosingBalance:
+		s.Status = CreditCardStatementStatusPaid
+	case s.PaidAmount > 0:
+		s.Status = CreditCardStatementStatusPartiallyPaid
+	default:
+		s.Status = CreditCardStatementStatusUnpaid
+	}
+}
+
+// CreditCardMinimumPayment 計算最低應繳金額：應繳總額的 10%，不低於下限且不超過應繳總額
+func CreditCardMinimumPayment(closingBalance float64) float64 {
+	if closingBalance <= 0 {
+		return 0
+	}
+	minimum := math.Max(closingBalance*CreditCardMinimumPaymentRate, CreditCardMinimumPaymentFloor)
+	return math.Round(math.Min(minimum, closingBalance)*100) / 100
+}
+
+// ClosingDateIn 計算指定年月的結帳日（帳單日超過當月天數時取月底）
+func (c *CreditCard) ClosingDateIn(year int, month time.Month) time.Time {
+	return dayInMonth(year, month, c.BillingDay)
+}
+
+// LastClosingDate 取得指定日期（含）之前最近一次的結帳日
+func (c *CreditCard) LastClosingDate(date time.Time) time.Time {
+	closing := c.ClosingDateIn(date.Year(), date.Month())
+	if closing.After(date) {
+		prev := date.AddDate(0, 0, -date.Day()+1).AddDate(0, -1, 0)
+		closing = c.ClosingDateIn(prev.Year(), prev.Month())
+	}
+	return closing
+}
+
+// NextClosingDate 取得指定結帳日的下一次結帳日
+func (c *CreditCard) NextClosingDate(closingDate time.Time) time.Time {
+	next := time.Date(closingDate.Year(), closingDate.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+	return c.ClosingDateIn(next.Year(), next.Month())
+}
+
+// DueDateFor 計算結帳日對應的繳款截止日（結帳日之後第一個繳款日）
+func (c *CreditCard) DueDateFor(closingDate time.Time) time.Time {
+	due := dayInMonth(closingDate.Year(), closingDate.Month(), c.PaymentDueDay)
+	if !due.After(closingDate) {
+		next := time.Date(closingDate.Year(), closingDate.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+		due = dayInMonth(next.Year(), next.Month(), c.PaymentDueDay)
+	}
+	return due
+}
+
+// CreditCardBalanceChange 計算現金流對指定信用卡已使用額度的影響
+// 信用卡為付款來源時：收入（退款）減少、其他增加；信用卡為轉帳目標（繳款）時：減少
+func (cf *CashFlow) CreditCardBalanceChange(cardID uuid.UUID) float64 {
+	change := 0.0
+
+	if cf.SourceType != nil && *cf.SourceType == SourceTypeCreditCard && cf.SourceID != nil && *cf.SourceID == cardID {
+		if cf.Type == CashFlowTypeIncome {
+			change -= cf.Amount
+		} else {
+			change += cf.Amount
+		}
+	}
+
+	if cf.IsCreditCardPayment(cardID) {
+		change -= cf.Amount
+	}
+
+	return change
+}
+
+// IsCreditCardPayment 檢查現金流是否為指定信用卡的繳款
+func (cf *CashFlow) IsCreditCardPayment(cardID uuid.UUID) bool {
+	return cf.Type == CashFlowTypeTransferOut && cf.TargetType != nil && *cf.TargetType == SourceTypeCreditCard &&
+		cf.TargetID != nil && *cf.TargetID == cardID
+}
+
+// dayInMonth 取得指定年月的第 N 日，超過當月天數時取月底
+func dayInMonth(year int, month time.Month, day int) time.Time {
+	date := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	if date.Month() != month {
+		date = time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC)
+	}
+	return date
+}
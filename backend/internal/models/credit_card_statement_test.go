@@ -0,0 +1,97 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// utcDate 建立 UTC 日期
+func utcDate(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// TestCreditCard_ClosingDates 測試結帳日與繳款截止日計算
+func TestCreditCard_ClosingDates(t *testing.T) {
+	t.Run("billing day clamped to month end", func(t *testing.T) {
+		card := &CreditCard{BillingDay: 31}
+		assert.Equal(t, utcDate(2025, 2, 28), card.ClosingDateIn(2025, time.February))
+		assert.Equal(t, utcDate(2025, 3, 31), card.NextClosingDate(utcDate(2025, 2, 28)))
+	})
+
+	t.Run("last closing date", func(t *testing.T) {
+		card := &CreditCard{BillingDay: 15}
+		assert.Equal(t, utcDate(2025, 2, 15), card.LastClosingDate(utcDate(2025, 3, 10)))
+		assert.Equal(t, utcDate(2025, 3, 15), card.LastClosingDate(utcDate(2025, 3, 15)))
+		assert.Equal(t, utcDate(2024, 12, 15), card.LastClosingDate(utcDate(2025, 1, 1)))
+	})
+
+	t.Run("due date in same month", func(t *testing.T) {
+		card := &CreditCard{BillingDay: 5, PaymentDueDay: 25}
+		assert.Equal(t, utcDate(2025, 1, 25), card.DueDateFor(utcDate(2025, 1, 5)))
+	})
+
+	t.Run("due date in next month", func(t *testing.T) {
+		card := &CreditCard{BillingDay: 25, PaymentDueDay: 10}
+		assert.Equal(t, utcDate(2025, 2, 10), card.DueDateFor(utcDate(2025, 1, 25)))
+	})
+}
+
+// TestCreditCardMinimumPayment 測試最低應繳金額計算
+func TestCreditCardMinimumPayment(t *testing.T) {
+	assert.Equal(t, 0.0, CreditCardMinimumPayment(0))
+	assert.Equal(t, 0.0, CreditCardMinimumPayment(-200))
+	assert.Equal(t, 500.0, CreditCardMinimumPayment(500))
+	assert.Equal(t, 1000.0, CreditCardMinimumPayment(5000))
+	assert.Equal(t, 2000.0, CreditCardMinimumPayment(20000))
+}
+
+// TestCreditCardStatement_UpdateStatus 測試帳單繳款狀態
+func TestCreditCardStatement_UpdateStatus(t *testing.T) {
+	statement := &CreditCardStatement{ClosingBalance: 5000, MinimumPayment: 1000}
+
+	statement.UpdateStatus()
+	assert.Equal(t, CreditCardStatementStatusUnpaid, statement.Status)
+	assert.Equal(t, 5000.0, statement.OutstandingAmount())
+	assert.Equal(t, 1000.0, statement.RemainingMinimumPayment())
+
+	statement.PaidAmount = 1500
+	statement.UpdateStatus()
+	assert.Equal(t, CreditCardStatementStatusPartiallyPaid, statement.Status)
+	assert.Equal(t, 3500.0, statement.OutstandingAmount())
+	assert.Equal(t, 0.0, statement.RemainingMinimumPayment())
+
+	statement.PaidAmount = 5000
+	statement.UpdateStatus()
+	assert.Equal(t, CreditCardStatementStatusPaid, statement.Status)
+
+	empty := &CreditCardStatement{}
+	empty.UpdateStatus()
+	assert.Equal(t, CreditCardStatementStatusPaid, empty.Status)
+}
+
+// TestCashFlow_CreditCardBalanceChange 測試現金流對信用卡已使用額度的影響
+func TestCashFlow_CreditCardBalanceChange(t *testing.T) {
+	cardID := uuid.New()
+	accountID := uuid.New()
+	bankAccount := SourceTypeBankAccount
+	creditCard := SourceTypeCreditCard
+
+	charge := &CashFlow{Type: CashFlowTypeExpense, Amount: 1200, SourceType: &creditCard, SourceID: &cardID}
+	refund := &CashFlow{Type: CashFlowTypeIncome, Amount: 300, SourceType: &creditCard, SourceID: &cardID}
+	payment := &CashFlow{
+		Type: CashFlowTypeTransferOut, Amount: 5000,
+		SourceType: &bankAccount, SourceID: &accountID,
+		TargetType: &creditCard, TargetID: &cardID,
+	}
+	otherCard := &CashFlow{Type: CashFlowTypeExpense, Amount: 100, SourceType: &creditCard, SourceID: &accountID}
+
+	assert.Equal(t, 1200.0, charge.CreditCardBalanceChange(cardID))
+	assert.Equal(t, -300.0, refund.CreditCardBalanceChange(cardID))
+	assert.Equal(t, -5000.0, payment.CreditCardBalanceChange(cardID))
+	assert.True(t, payment.IsCreditCardPayment(cardID))
+	assert.False(t, charge.IsCreditCardPayment(cardID))
+	assert.Equal(t, 0.0, otherCard.CreditCardBalanceChange(cardID))
+}
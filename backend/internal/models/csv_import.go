@@ -20,6 +20,7 @@ type CSVImportResult struct {
 	Success      bool                      `json:"success"`
 	Transactions []*CreateTransactionInput `json:"transactions,omitempty"`
 	Errors       []CSVValidationError      `json:"errors,omitempty"`
+	Warnings     []CSVValidationError      `json:"warnings,omitempty"` // 不影響匯入的提醒（例如手續費與手續費設定不符）
 }
 
 // CSVValidationError CSV 驗證錯誤
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CustodyType 加密貨幣保管類型
+type CustodyType string
+
+const (
+	CustodyTypeExchange CustodyType = "exchange" // 交易所
+	CustodyTypeWallet   CustodyType = "wallet"   // 自管錢包（含硬體錢包）
+	CustodyTypeStaking  CustodyType = "staking"  // 質押
+)
+
+// Validate 驗證 CustodyType 是否有效
+func (c CustodyType) Validate() bool {
+	switch c {
+	case CustodyTypeExchange, CustodyTypeWallet, CustodyTypeStaking:
+		return true
+	}
+	return false
+}
+
+// CustodyAccount 加密貨幣保管帳戶模型
+type CustodyAccount struct {
+	ID          uuid.UUID   `json:"id" db:"id"`
+	Name        string      `json:"name" db:"name"`
+	CustodyType CustodyType `json:"custody_type" db:"custody_type"`
+	Provider    *string     `json:"provider,omitempty" db:"provider"` // 交易所或錢包供應商，例如 Binance、Ledger
+	Note        *string     `json:"note,omitempty" db:"note"`
+	CreatedAt   time.Time   `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at" db:"updated_at"`
+}
+
+// CreateCustodyAccountInput 建立保管帳戶的輸入資料
+type CreateCustodyAccountInput struct {
+	Name        string      `json:"name" binding:"required,max=255"`
+	CustodyType CustodyType `json:"custody_type" binding:"required,oneof=exchange wallet staking"`
+	Provider    *string     `json:"provider,omitempty" binding:"omitempty,max=255"`
+	Note        *string     `json:"note,omitempty" binding:"omitempty,max=1000"`
+}
+
+// UpdateCustodyAccountInput 更新保管帳戶的輸入資料
+type UpdateCustodyAccountInput struct {
+	Name        *string      `json:"name,omitempty" binding:"omitempty,max=255"`
+	CustodyType *CustodyType `json:"custody_type,omitempty" binding:"omitempty,oneof=exchange wallet staking"`
+	Provider    *string      `json:"provider,omitempty" binding:"omitempty,max=255"`
+	Note        *string      `json:"note,omitempty" binding:"omitempty,max=1000"`
+}
+
+// CustodyHolding 單一保管位置的加密貨幣持倉
+// 成本與市值依整體 FIFO 持倉的平均成本與現價按數量比例分攤，各位置加總等於整體持倉
+type CustodyHolding struct {
+	CustodyAccountID   *uuid.UUID `json:"custody_account_id,omitempty"` // 未指定保管帳戶時為 nil
+	CustodyAccountName string     `json:"custody_account_name"`
+	Symbol             string     `json:"symbol"`
+	Name               string     `json:"name"`
+	Quantity           float64    `json:"quantity"`
+	AvgCost            float64    `json:"avg_cost"`     // 整體 FIFO 平均成本（TWD）
+	TotalCost          float64    `json:"total_cost"`   // 分攤後的成本（TWD）
+	MarketValue        float64    `json:"market_value"` // 分攤後的市值（TWD）
+	UnrealizedPL       float64    `json:"unrealized_pl"`
+}
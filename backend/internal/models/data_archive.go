@@ -0,0 +1,65 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// DataArchiveFormatVersion 目前的備份檔格式版本（檔案結構改變時遞增）
+const DataArchiveFormatVersion = 1
+
+// DataArchiveApplication 備份檔的應用程式識別
+const DataArchiveApplication = "asset-manager"
+
+// DataArchiveFormat 備份檔封裝格式
+type DataArchiveFormat string
+
+const (
+	DataArchiveFormatJSON DataArchiveFormat = "json" // 單一 JSON 檔（manifest 與所有資料表）
+	DataArchiveFormatZIP  DataArchiveFormat = "zip"  // ZIP 檔（manifest.json 與每個資料表一個 JSON 檔）
+)
+
+// Validate 驗證 DataArchiveFormat 是否有效
+func (f DataArchiveFormat) Validate() bool {
+	switch f {
+	case DataArchiveFormatJSON, DataArchiveFormatZIP:
+		return true
+	}
+	return false
+}
+
+// DataArchiveTableInfo 備份檔中單一資料表的說明
+type DataArchiveTableInfo struct {
+	Name string `json:"name"`
+	Rows int    `json:"rows"`
+}
+
+// DataArchiveManifest 備份檔的自我描述資訊
+type DataArchiveManifest struct {
+	Application   string                 `json:"application"`
+	FormatVersion int                    `json:"format_version"` // 備份檔格式版本
+	SchemaVersion int64                  `json:"schema_version"` // 匯出時的資料庫 migration 版本
+	CreatedAt     time.Time              `json:"created_at"`
+	Tables        []DataArchiveTableInfo `json:"tables"` // 依還原順序排列
+}
+
+// DataArchive 完整資料備份（每筆資料為資料表欄位名稱對應值的 JSON 物件）
+type DataArchive struct {
+	Manifest DataArchiveManifest          `json:"manifest"`
+	Tables   map[string][]json.RawMessage `json:"tables"`
+}
+
+// DataRestoreTableResult 單一資料表的還原結果
+type DataRestoreTableResult struct {
+	Name     string `json:"name"`
+	Inserted int    `json:"inserted"` // 新增的筆數
+	Merged   int    `json:"merged"`   // 與既有資料（例如 migration 建立的預設分類、設定）合併的筆數
+}
+
+// DataRestoreResult 資料還原結果
+type DataRestoreResult struct {
+	SchemaVersion int64                    `json:"schema_version"`
+	Tables        []DataRestoreTableResult `json:"tables"`
+	Inserted      int                      `json:"inserted"`
+	Merged        int                      `json:"merged"`
+}
@@ -0,0 +1,34 @@
+package models
+
+// ExportFormat 報表匯出檔案格式
+type ExportFormat string
+
+const (
+	ExportFormatCSV  ExportFormat = "csv"  // CSV（UTF-8 含 BOM，Excel 可直接開啟中文）
+	ExportFormatXLSX ExportFormat = "xlsx" // Excel 活頁簿
+)
+
+// Validate 驗證 ExportFormat 是否有效
+func (f ExportFormat) Validate() bool {
+	switch f {
+	case ExportFormatCSV, ExportFormatXLSX:
+		return true
+	}
+	return false
+}
+
+// ContentType 匯出檔案的 MIME 類型
+func (f ExportFormat) ContentType() string {
+	if f == ExportFormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// ExportSheet 匯出的表格（標題與代碼值已依語系翻譯）
+// 儲存格值為 string、float64、int、time.Time（日期）或 nil（空白）
+type ExportSheet struct {
+	Name    string
+	Headers []string
+	Rows    [][]interface{}
+}
@@ -0,0 +1,90 @@
+package models
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FeeProfile 券商手續費與交易稅設定
+// 手續費 = 成交金額 × 費率 × 折扣 + 股數 × 每股手續費，再套用最低／最高手續費；
+// 賣出（含放空）時依商品類別的稅率課徵交易稅，未設定的台股類別使用法定稅率
+type FeeProfile struct {
+	ID                 uuid.UUID                      `json:"id" db:"id"`
+	Name               string                         `json:"name" db:"name"`
+	AssetType          AssetType                      `json:"asset_type" db:"asset_type"`                               // tw-stock 或 us-stock
+	BrokerageAccountID *uuid.UUID                     `json:"brokerage_account_id,omitempty" db:"brokerage_account_id"` // 僅套用於指定券商帳戶（nil 為通用設定）
+	IsDefault          bool                           `json:"is_default" db:"is_default"`                               // 未指定券商帳戶或帳戶無設定時使用
+	FeeRate            float64                        `json:"fee_rate" db:"fee_rate"`                                   // 手續費率（台股 0.001425）
+	FeeDiscount        float64                        `json:"fee_discount" db:"fee_discount"`                           // 手續費折扣（0.28 表示 2.8 折，1 表示無折扣）
+	PerShareFee        float64                        `json:"per_share_fee" db:"per_share_fee"`                         // 每股手續費（美股，例如 0.005）
+	MinFee             float64                        `json:"min_fee" db:"min_fee"`                                     // 最低手續費（台股 20 元）
+	MaxFee             *float64                       `json:"max_fee,omitempty" db:"max_fee"`                           // 最高手續費（nil 表示無上限）
+	SellTaxRates       map[InstrumentCategory]float64 `json:"sell_tax_rates" db:"sell_tax_rates"`                       // 賣出時各商品類別的交易稅（規費）率
+	Note               *string                        `json:"note,omitempty" db:"note"`
+	CreatedAt          time.Time                      `json:"created_at" db:"created_at"`
+	UpdatedAt          time.Time                      `json:"updated_at" db:"updated_at"`
+}
+
+// CreateFeeProfileInput 建立手續費設定的輸入資料
+type CreateFeeProfileInput struct {
+	Name               string                         `json:"name" binding:"required,max=255"`
+	AssetType          AssetType                      `json:"asset_type" binding:"required,oneof=tw-stock us-stock"`
+	BrokerageAccountID *uuid.UUID                     `json:"brokerage_account_id,omitempty"`
+	IsDefault          bool                           `json:"is_default"`
+	FeeRate            float64                        `json:"fee_rate" binding:"gte=0"`
+	FeeDiscount        *float64                       `json:"fee_discount,omitempty" binding:"omitempty,gt=0,lte=1"` // 未填寫時為 1（無折扣）
+	PerShareFee        float64                        `json:"per_share_fee" binding:"gte=0"`
+	MinFee             float64                        `json:"min_fee" binding:"gte=0"`
+	MaxFee             *float64                       `json:"max_fee,omitempty" binding:"omitempty,gte=0"`
+	SellTaxRates       map[InstrumentCategory]float64 `json:"sell_tax_rates,omitempty"`
+	Note               *string                        `json:"note,omitempty" binding:"omitempty,max=1000"`
+}
+
+// UpdateFeeProfileInput 更新手續費設定的輸入資料
+type UpdateFeeProfileInput struct {
+	Name               *string                        `json:"name,omitempty" binding:"omitempty,max=255"`
+	BrokerageAccountID *uuid.UUID                     `json:"brokerage_account_id,omitempty"`
+	IsDefault          *bool                          `json:"is_default,omitempty"`
+	FeeRate            *float64                       `json:"fee_rate,omitempty" binding:"omitempty,gte=0"`
+	FeeDiscount        *float64                       `json:"fee_discount,omitempty" binding:"omitempty,gt=0,lte=1"`
+	PerShareFee        *float64                       `json:"per_share_fee,omitempty" binding:"omitempty,gte=0"`
+	MinFee             *float64                       `json:"min_fee,omitempty" binding:"omitempty,gte=0"`
+	MaxFee             *float64                       `json:"max_fee,omitempty" binding:"omitempty,gte=0"`
+	SellTaxRates       map[InstrumentCategory]float64 `json:"sell_tax_rates,omitempty"`
+	Note               *string                        `json:"note,omitempty" binding:"omitempty,max=1000"`
+}
+
+// CalculateFee 依設定計算手續費（台股無條件捨去至整數，美股四捨五入至分）
+func (p *FeeProfile) CalculateFee(amount, quantity float64) float64 {
+	fee := amount*p.FeeRate*p.FeeDiscount + quantity*p.PerShareFee
+	if p.MaxFee != nil && fee > *p.MaxFee {
+		fee = *p.MaxFee
+	}
+	if fee < p.MinFee {
+		fee = p.MinFee
+	}
+	return p.roundCost(fee)
+}
+
+// CalculateSellTax 以指定稅率計算賣出交易稅（台股無條件捨去至整數，美股四捨五入至分）
+func (p *FeeProfile) CalculateSellTax(amount, rate float64) float64 {
+	return p.roundCost(amount * rate)
+}
+
+// CostTolerance 判斷手動填寫的手續費或交易稅是否與設定不符時允許的差額（台股 1 元、美股 1 分）
+func (p *FeeProfile) CostTolerance() float64 {
+	if p.AssetType == AssetTypeTWStock {
+		return 1
+	}
+	return 0.01
+}
+
+// roundCost 依市場慣例進位手續費與交易稅
+func (p *FeeProfile) roundCost(value float64) float64 {
+	if p.AssetType == AssetTypeTWStock {
+		return math.Floor(value)
+	}
+	return math.Round(value*100) / 100
+}
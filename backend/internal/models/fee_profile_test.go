@@ -0,0 +1,64 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestFeeProfile_CalculateFee 測試手續費計算與各市場的進位方式
+func TestFeeProfile_CalculateFee(t *testing.T) {
+	maxFee := 5.0
+	tests := []struct {
+		name     string
+		profile  FeeProfile
+		amount   float64
+		quantity float64
+		want     float64
+	}{
+		{
+			name:     "台股折扣後無條件捨去",
+			profile:  FeeProfile{AssetType: AssetTypeTWStock, FeeRate: 0.001425, FeeDiscount: 0.28, MinFee: 20},
+			amount:   1000000,
+			quantity: 1000,
+			want:     399,
+		},
+		{
+			name:     "台股低於最低手續費",
+			profile:  FeeProfile{AssetType: AssetTypeTWStock, FeeRate: 0.001425, FeeDiscount: 0.28, MinFee: 20},
+			amount:   10000,
+			quantity: 10,
+			want:     20,
+		},
+		{
+			name:     "美股每股手續費四捨五入至分",
+			profile:  FeeProfile{AssetType: AssetTypeUSStock, FeeDiscount: 1, PerShareFee: 0.005, MinFee: 1, MaxFee: &maxFee},
+			amount:   60000,
+			quantity: 333,
+			want:     1.67,
+		},
+		{
+			name:     "美股超過最高手續費",
+			profile:  FeeProfile{AssetType: AssetTypeUSStock, FeeDiscount: 1, PerShareFee: 0.005, MinFee: 1, MaxFee: &maxFee},
+			amount:   1000000,
+			quantity: 5000,
+			want:     5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.profile.CalculateFee(tt.amount, tt.quantity))
+		})
+	}
+}
+
+// TestFeeProfile_CalculateSellTax 測試賣出交易稅計算
+func TestFeeProfile_CalculateSellTax(t *testing.T) {
+	tw := FeeProfile{AssetType: AssetTypeTWStock}
+	us := FeeProfile{AssetType: AssetTypeUSStock}
+
+	assert.Equal(t, 3000.0, tw.CalculateSellTax(1000000, 0.003))
+	assert.Equal(t, 9.0, tw.CalculateSellTax(3333, 0.003))
+	assert.Equal(t, 1.67, us.CalculateSellTax(60000, 0.0000278))
+}
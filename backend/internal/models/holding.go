@@ -2,6 +2,8 @@ package models
 
 import (
 	"time"
+
+	"github.com/google/uuid"
 )
 
 // Holding 持倉資料
@@ -22,14 +24,25 @@ type Holding struct {
 	UnrealizedPL     float64   `json:"unrealized_pl"`                // 未實現損益 = MarketValue - TotalCost（TWD）
 	UnrealizedPLPct  float64   `json:"unrealized_pl_pct"`            // 未實現損益百分比
 	LastUpdated      time.Time `json:"last_updated"`                 // 最後更新時間
-	PriceSource      string    `json:"price_source,omitempty"`       // 價格來源（cache, api, stale-cache）
+	PriceSource      string    `json:"price_source,omitempty"`       // 價格來源（cache, yahoo, last_known 等）
 	IsPriceStale     bool      `json:"is_price_stale,omitempty"`     // 價格是否過期
 	PriceStaleReason string    `json:"price_stale_reason,omitempty"` // 價格過期原因
+
+	// 價格實際取得時間與年齡（秒），用於判斷過期價格的新舊程度
+	PriceUpdatedAt  *time.Time `json:"price_updated_at,omitempty"`
+	PriceAgeSeconds int64      `json:"price_age_seconds,omitempty"`
+
+	// 空頭部位的數量、成本與市值皆為負數（成本為放空所得），未實現損益公式與多頭一致
+	IsShort bool `json:"is_short,omitempty"`
+
+	// 選擇權持倉：市值 = 數量 * 每股價格 * 合約乘數；無報價時以標的價格計算內含價值
+	Multiplier     float64         `json:"multiplier,omitempty"`
+	OptionContract *OptionContract `json:"option_contract,omitempty"`
 }
 
 // CostBatch FIFO 成本批次
 // 用於追蹤每一批買入的成本，實作 FIFO 計算
-// 成本統一以 TWD 計價
+// 成本統一以 TWD 計價；空頭批次的數量為負數，單位成本為每單位放空所得（扣除手續費）
 type CostBatch struct {
 	Date             time.Time `json:"date"`               // 買入日期
 	Quantity         float64   `json:"quantity"`           // 該批次剩餘數量
@@ -42,8 +55,9 @@ type CostBatch struct {
 
 // HoldingFilters 持倉篩選條件
 type HoldingFilters struct {
-	AssetType *AssetType `json:"asset_type,omitempty"` // 按資產類型篩選
-	Symbol    *string    `json:"symbol,omitempty"`     // 按標的代碼篩選
+	AssetType          *AssetType `json:"asset_type,omitempty"`           // 按資產類型篩選
+	Symbol             *string    `json:"symbol,omitempty"`               // 按標的代碼篩選
+	BrokerageAccountID *uuid.UUID `json:"brokerage_account_id,omitempty"` // 按券商帳戶篩選
 }
 
 // FixInsufficientQuantityInput 修復持倉數量不足的輸入
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"math"
 	"time"
 
 	"github.com/google/uuid"
@@ -38,6 +39,61 @@ type Installment struct {
 
 	// 關聯資料（Join 時使用）
 	Category *CashFlowCategory `json:"category,omitempty" db:"-"`
+
+	// 關聯資料（查詢詳細資料時載入）
+	Prepayments []*InstallmentPrepayment `json:"prepayments,omitempty" db:"-"`
+	Schedule    *InstallmentSchedule     `json:"schedule,omitempty" db:"-"`
+}
+
+// InstallmentPrepayment 分期提前還款記錄（部分提前還本或提前清償）
+// AfterPeriod 為記錄時的已付期數，提前還款於該期扣款後套用
+type InstallmentPrepayment struct {
+	ID            uuid.UUID  `json:"id" db:"id"`
+	InstallmentID uuid.UUID  `json:"installment_id" db:"installment_id"`
+	Date          time.Time  `json:"date" db:"date"`
+	Amount        float64    `json:"amount" db:"amount"`
+	AfterPeriod   int        `json:"after_period" db:"after_period"`
+	IsPayoff      bool       `json:"is_payoff" db:"is_payoff"`
+	CashFlowID    *uuid.UUID `json:"cash_flow_id,omitempty" db:"cash_flow_id"`
+	Note          *string    `json:"note,omitempty" db:"note"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+}
+
+// InstallmentPayment 分期每期扣款記錄（由扣款排程產生）
+type InstallmentPayment struct {
+	ID              uuid.UUID  `json:"id" db:"id"`
+	InstallmentID   uuid.UUID  `json:"installment_id" db:"installment_id"`
+	Period          int        `json:"period" db:"period"`
+	PaymentDate     time.Time  `json:"payment_date" db:"payment_date"`
+	PrincipalAmount float64    `json:"principal_amount" db:"principal_amount"`
+	InterestAmount  float64    `json:"interest_amount" db:"interest_amount"`
+	CashFlowID      *uuid.UUID `json:"cash_flow_id,omitempty" db:"cash_flow_id"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+}
+
+// InstallmentScheduleEntry 分期付款計畫中的單期資料
+type InstallmentScheduleEntry struct {
+	Period           int         `json:"period"`            // 期數（從 1 開始）
+	BillingDate      time.Time   `json:"billing_date"`      // 扣款日期
+	Payment          float64     `json:"payment"`           // 當期應繳金額（本金 + 利息）
+	Principal        float64     `json:"principal"`         // 當期本金
+	Interest         float64     `json:"interest"`          // 當期利息
+	Prepayment       float64     `json:"prepayment"`        // 當期扣款後的提前還款金額
+	RemainingBalance float64     `json:"remaining_balance"` // 當期結束後剩餘本金
+	IsPaid           bool        `json:"is_paid"`           // 是否已扣款
+	CashFlowIDs      []uuid.UUID `json:"cash_flow_ids"`     // 關聯的現金流（當期扣款與提前還款）
+}
+
+// InstallmentSchedule 分期付款計畫
+type InstallmentSchedule struct {
+	InstallmentID      uuid.UUID                  `json:"installment_id"`
+	PeriodicRate       float64                    `json:"periodic_rate"` // 由總利息推算的每期實際利率
+	Entries            []InstallmentScheduleEntry `json:"entries"`
+	TotalPayment       float64                    `json:"total_payment"`       // 總付款金額（含提前還款）
+	TotalInterest      float64                    `json:"total_interest"`      // 實際總利息
+	InterestSaved      float64                    `json:"interest_saved"`      // 因提前還款而省下的利息
+	RemainingPrincipal float64                    `json:"remaining_principal"` // 目前剩餘本金（提前清償金額）
+	RemainingPayment   float64                    `json:"remaining_payment"`   // 未扣款期數的應繳總額
 }
 
 // CreateInstallmentInput 建立分期的輸入資料
@@ -67,6 +123,19 @@ type UpdateInstallmentInput struct {
 	Note          *string            `json:"note,omitempty"`
 }
 
+// CreateInstallmentPrepaymentInput 新增提前還款的輸入資料
+type CreateInstallmentPrepaymentInput struct {
+	Date   time.Time `json:"date" binding:"required"`
+	Amount float64   `json:"amount" binding:"required,gt=0"`
+	Note   *string   `json:"note,omitempty"`
+}
+
+// InstallmentPayoffInput 提前清償的輸入資料（金額為目前剩餘本金）
+type InstallmentPayoffInput struct {
+	Date time.Time `json:"date" binding:"required"`
+	Note *string   `json:"note,omitempty"`
+}
+
 // Validate 驗證 InstallmentStatus 是否有效
 func (s InstallmentStatus) Validate() bool {
 	switch s {
@@ -87,7 +156,12 @@ func (i *Installment) CalculateInterest() {
 }
 
 // RemainingAmount 計算剩餘金額
+// 有提前還款記錄時，依重新攤提後的付款計畫計算
 func (i *Installment) RemainingAmount() float64 {
+	if len(i.Prepayments) > 0 {
+		return i.GenerateSchedule().RemainingPayment
+	}
+
 	totalWithInterest := i.TotalAmount + i.TotalInterest
 	paidAmount := float64(i.PaidCount) * i.InstallmentAmount
 	return totalWithInterest - paidAmount
@@ -101,12 +175,17 @@ func (i *Installment) RemainingCount() int {
 // NextBillingDate 計算下次扣款日期
 // fromDate: 從哪個日期開始計算（通常是今天）
 func (i *Installment) NextBillingDate(fromDate time.Time) time.Time {
-	// 從開始日期計算下次扣款的月份
+	return i.BillingDate(i.PaidCount)
+}
+
+// BillingDate 計算指定期別的扣款日期（期別從 0 開始，第 0 期為開始月份）
+func (i *Installment) BillingDate(period int) time.Time {
+	// 從開始日期計算扣款的月份
 	startYear := i.StartDate.Year()
 	startMonth := i.StartDate.Month()
 
-	// 計算目標月份（開始月份 + 已付期數）
-	targetMonth := int(startMonth) + i.PaidCount
+	// 計算目標月份（開始月份 + 期別）
+	targetMonth := int(startMonth) + period
 	targetYear := startYear
 
 	// 處理跨年的情況
@@ -127,6 +206,115 @@ func (i *Installment) NextBillingDate(fromDate time.Time) time.Time {
 	return billingDate
 }
 
+// PeriodicRate 由總利息推算每期的實際利率
+// 分期的總利息以本金 × 利率一次計算（每期金額固定），
+// 此處求出使本息平均攤還的每期金額等於 InstallmentAmount 的利率，用於拆分每期本金與利息
+func (i *Installment) PeriodicRate() float64 {
+	if i.TotalInterest <= 0 || i.TotalAmount <= 0 || i.InstallmentCount <= 0 {
+		return 0
+	}
+
+	target := (i.TotalAmount + i.TotalInterest) / float64(i.InstallmentCount)
+	low, high := 0.0, 1.0
+	for iteration := 0; iteration < 100; iteration++ {
+		mid := (low + high) / 2
+		if annuityPayment(i.TotalAmount, mid, i.InstallmentCount) > target {
+			high = mid
+		} else {
+			low = mid
+		}
+	}
+	return (low + high) / 2
+}
+
+// GenerateSchedule 產生分期付款計畫
+// 未提前還款時每期金額為 InstallmentAmount；提前還款後依剩餘本金與剩餘期數重新攤提，
+// 剩餘本金為零時（提前清償）付款計畫提早結束
+func (i *Installment) GenerateSchedule() *InstallmentSchedule {
+	schedule := &InstallmentSchedule{
+		InstallmentID: i.ID,
+		PeriodicRate:  i.PeriodicRate(),
+		Entries:       []InstallmentScheduleEntry{},
+	}
+
+	// 依已付期數彙總提前還款
+	prepaid := map[int]float64{}
+	for _, prepayment := range i.Prepayments {
+		prepaid[prepayment.AfterPeriod] += prepayment.Amount
+	}
+
+	balance := i.TotalAmount
+	payment := roundToCents(i.InstallmentAmount)
+	applyPrepayment := func(afterPeriod int) float64 {
+		amount := math.Min(prepaid[afterPeriod], balance)
+		if amount > 0 {
+			balance = roundToCents(balance - amount)
+			// 提前還款後以剩餘期數重新攤提每期金額
+			payment = roundToCents(annuityPayment(balance, schedule.PeriodicRate, i.InstallmentCount-afterPeriod))
+		}
+		return amount
+	}
+
+	// 第一期扣款前的提前還款
+	initialPrepayment := applyPrepayment(0)
+	schedule.TotalPayment += initialPrepayment
+
+	for period := 1; period <= i.InstallmentCount && balance > 0.005; period++ {
+		interest := roundToCents(balance * schedule.PeriodicRate)
+		principal := roundToCents(payment - interest)
+		entryPayment := payment
+
+		// 最後一期或本金不足時，一次付清剩餘本金
+		if period == i.InstallmentCount || principal > balance {
+			principal = balance
+			entryPayment = roundToCents(principal + interest)
+		}
+		balance = roundToCents(balance - principal)
+
+		prepayment := applyPrepayment(period)
+
+		schedule.Entries = append(schedule.Entries, InstallmentScheduleEntry{
+			Period:           period,
+			BillingDate:      i.BillingDate(period - 1),
+			Payment:          entryPayment,
+			Principal:        principal,
+			Interest:         interest,
+			Prepayment:       prepayment,
+			RemainingBalance: balance,
+			IsPaid:           period <= i.PaidCount,
+			CashFlowIDs:      []uuid.UUID{},
+		})
+
+		schedule.TotalPayment += entryPayment + prepayment
+		schedule.TotalInterest += interest
+		if period > i.PaidCount {
+			schedule.RemainingPayment += entryPayment
+		}
+	}
+
+	// 目前剩餘本金：已付期數與已記錄的提前還款之後的本金
+	schedule.RemainingPrincipal = roundToCents(i.TotalAmount - initialPrepayment)
+	for _, entry := range schedule.Entries {
+		if entry.Period > i.PaidCount {
+			break
+		}
+		schedule.RemainingPrincipal = entry.RemainingBalance
+	}
+	if i.Status == InstallmentStatusCompleted || i.Status == InstallmentStatusCancelled {
+		schedule.RemainingPrincipal = 0
+		schedule.RemainingPayment = 0
+	}
+
+	schedule.TotalPayment = roundToCents(schedule.TotalPayment)
+	schedule.TotalInterest = roundToCents(schedule.TotalInterest)
+	schedule.RemainingPayment = roundToCents(schedule.RemainingPayment)
+	if len(i.Prepayments) > 0 {
+		schedule.InterestSaved = math.Max(0, roundToCents(i.TotalInterest-schedule.TotalInterest))
+	}
+
+	return schedule
+}
+
 // IsActive 檢查分期是否進行中
 func (i *Installment) IsActive() bool {
 	// 狀態必須是 active
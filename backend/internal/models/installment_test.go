@@ -389,3 +389,93 @@ func TestInstallment_PaymentMethodValidation(t *testing.T) {
 		})
 	}
 }
+
+// TestInstallment_GenerateSchedule 測試分期付款計畫的本息拆分
+func TestInstallment_GenerateSchedule(t *testing.T) {
+	inst := &Installment{
+		TotalAmount:      12000,
+		InstallmentCount: 12,
+		InterestRate:     6,
+		BillingDay:       10,
+		StartDate:        time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC),
+		PaidCount:        3,
+		Status:           InstallmentStatusActive,
+	}
+	inst.CalculateInterest()
+
+	schedule := inst.GenerateSchedule()
+
+	assert.Len(t, schedule.Entries, 12)
+	assert.Greater(t, schedule.PeriodicRate, 0.0)
+	assert.InDelta(t, inst.TotalInterest, schedule.TotalInterest, 0.1, "interest split should add up to total interest")
+	assert.InDelta(t, 12720, schedule.TotalPayment, 0.1)
+	assert.Equal(t, 0.0, schedule.InterestSaved)
+
+	// 每期金額固定，利息逐期遞減、本金逐期遞增
+	for _, entry := range schedule.Entries[:11] {
+		assert.InDelta(t, 1060, entry.Payment, 0.001)
+	}
+	assert.Greater(t, schedule.Entries[0].Interest, schedule.Entries[11].Interest)
+	assert.Less(t, schedule.Entries[0].Principal, schedule.Entries[11].Principal)
+	assert.Equal(t, 0.0, schedule.Entries[11].RemainingBalance)
+
+	// 已付期數與剩餘金額
+	assert.True(t, schedule.Entries[2].IsPaid)
+	assert.False(t, schedule.Entries[3].IsPaid)
+	assert.Equal(t, schedule.Entries[2].RemainingBalance, schedule.RemainingPrincipal)
+	assert.InDelta(t, 1060*9, schedule.RemainingPayment, 0.1)
+	assert.Equal(t, time.Date(2025, 4, 10, 0, 0, 0, 0, time.UTC), schedule.Entries[3].BillingDate)
+}
+
+// TestInstallment_GenerateSchedule_Prepayment 測試提前還款後重新攤提
+func TestInstallment_GenerateSchedule_Prepayment(t *testing.T) {
+	inst := &Installment{
+		TotalAmount:      12000,
+		InstallmentCount: 12,
+		InterestRate:     6,
+		BillingDay:       10,
+		StartDate:        time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC),
+		PaidCount:        6,
+		Status:           InstallmentStatusActive,
+	}
+	inst.CalculateInterest()
+	original := inst.GenerateSchedule()
+
+	inst.Prepayments = []*InstallmentPrepayment{
+		{Amount: 3000, AfterPeriod: 6},
+	}
+	schedule := inst.GenerateSchedule()
+
+	assert.Len(t, schedule.Entries, 12)
+	assert.Equal(t, 3000.0, schedule.Entries[5].Prepayment)
+	assert.InDelta(t, original.Entries[5].RemainingBalance-3000, schedule.RemainingPrincipal, 0.001)
+	assert.Less(t, schedule.Entries[6].Payment, original.Entries[6].Payment, "payment should be re-amortized after prepayment")
+	assert.Greater(t, schedule.InterestSaved, 0.0)
+	assert.Equal(t, 0.0, schedule.Entries[11].RemainingBalance)
+	assert.InDelta(t, schedule.RemainingPayment, inst.RemainingAmount(), 0.001)
+}
+
+// TestInstallment_GenerateSchedule_Payoff 測試提前清償後付款計畫提早結束
+func TestInstallment_GenerateSchedule_Payoff(t *testing.T) {
+	inst := &Installment{
+		TotalAmount:       36000,
+		InstallmentCount:  12,
+		InstallmentAmount: 3000,
+		BillingDay:        15,
+		StartDate:         time.Date(2025, 5, 15, 0, 0, 0, 0, time.UTC),
+		PaidCount:         4,
+		Status:            InstallmentStatusActive,
+		Prepayments: []*InstallmentPrepayment{
+			{Amount: 24000, AfterPeriod: 4, IsPayoff: true},
+		},
+	}
+
+	schedule := inst.GenerateSchedule()
+
+	assert.Len(t, schedule.Entries, 4)
+	assert.Equal(t, 0.0, schedule.PeriodicRate)
+	assert.Equal(t, 24000.0, schedule.Entries[3].Prepayment)
+	assert.Equal(t, 0.0, schedule.RemainingPrincipal)
+	assert.Equal(t, 0.0, schedule.RemainingPayment)
+	assert.Equal(t, 36000.0, schedule.TotalPayment)
+}
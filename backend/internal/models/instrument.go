@@ -0,0 +1,120 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// InstrumentSource 商品主檔匯入來源
+type InstrumentSource string
+
+const (
+	InstrumentSourceTWSE      InstrumentSource = "twse"       // 證交所上市公司／證券清單（CSV）
+	InstrumentSourceTPEx      InstrumentSource = "tpex"       // 櫃買中心上櫃公司／證券清單（CSV）
+	InstrumentSourceEmerging  InstrumentSource = "emerging"   // 櫃買中心興櫃公司／證券清單（CSV）
+	InstrumentSourceUSTickers InstrumentSource = "us_tickers" // 美股代碼清單（Nasdaq Trader nasdaqlisted.txt / otherlisted.txt）
+	InstrumentSourceCoinGecko InstrumentSource = "coingecko"  // CoinGecko /coins/list 或 /coins/markets（JSON）
+)
+
+// Validate 驗證 InstrumentSource 是否有效
+func (s InstrumentSource) Validate() bool {
+	switch s {
+	case InstrumentSourceTWSE, InstrumentSourceTPEx, InstrumentSourceEmerging, InstrumentSourceUSTickers, InstrumentSourceCoinGecko:
+		return true
+	}
+	return false
+}
+
+// 交易所代碼
+const (
+	ExchangeTWSE     = "TWSE"
+	ExchangeTPEx     = "TPEx"
+	ExchangeEmerging = "ESB" // 興櫃股票市場（Emerging Stock Board）
+	ExchangeNASDAQ   = "NASDAQ"
+	ExchangeCrypto   = "CRYPTO"
+)
+
+// InstrumentCategory 台股商品類別（決定證券交易稅率）
+type InstrumentCategory string
+
+const (
+	InstrumentCategoryStock        InstrumentCategory = "stock"         // 股票（含興櫃、特別股、TDR）
+	InstrumentCategoryETF          InstrumentCategory = "etf"           // 一般 ETF（含期貨、外幣計價 ETF）
+	InstrumentCategoryBondETF      InstrumentCategory = "bond_etf"      // 債券 ETF（代號以 B 結尾）
+	InstrumentCategoryLeveragedETF InstrumentCategory = "leveraged_etf" // 槓桿／反向 ETF（代號以 L、R 結尾）
+	InstrumentCategoryETN          InstrumentCategory = "etn"           // 指數投資證券（代號 02 開頭的六碼）
+)
+
+// Validate 驗證 InstrumentCategory 是否有效
+func (c InstrumentCategory) Validate() bool {
+	switch c {
+	case InstrumentCategoryStock, InstrumentCategoryETF, InstrumentCategoryBondETF, InstrumentCategoryLeveragedETF, InstrumentCategoryETN:
+		return true
+	}
+	return false
+}
+
+// IsExchangeTraded 是否為受益憑證類商品（ETF、ETN，證券交易稅率為千分之一）
+func (c InstrumentCategory) IsExchangeTraded() bool {
+	return c == InstrumentCategoryETF || c == InstrumentCategoryBondETF || c == InstrumentCategoryLeveragedETF || c == InstrumentCategoryETN
+}
+
+// ClassifyTWInstrument 依台股代號編碼規則判斷商品類別
+// 00 開頭為 ETF（B 結尾為債券 ETF，L、R 結尾為槓桿／反向 ETF）、02 開頭的六碼為 ETN，其餘視為股票
+func ClassifyTWInstrument(symbol string) InstrumentCategory {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+
+	switch {
+	case strings.HasPrefix(symbol, "00") && len(symbol) >= 4:
+		switch symbol[len(symbol)-1] {
+		case 'B':
+			return InstrumentCategoryBondETF
+		case 'L', 'R':
+			return InstrumentCategoryLeveragedETF
+		}
+		return InstrumentCategoryETF
+	case strings.HasPrefix(symbol, "02") && len(symbol) == 6:
+		return InstrumentCategoryETN
+	}
+	return InstrumentCategoryStock
+}
+
+// Instrument 商品主檔（可交易標的的代碼、名稱與報價來源代碼）
+type Instrument struct {
+	ID          uuid.UUID          `json:"id" db:"id"`
+	Symbol      string             `json:"symbol" db:"symbol"`
+	Exchange    string             `json:"exchange" db:"exchange"` // 台股為上市（TWSE）、上櫃（TPEx）或興櫃（ESB）
+	NameZhTW    string             `json:"name_zh_tw" db:"name_zh_tw"`
+	NameEn      string             `json:"name_en" db:"name_en"`
+	Currency    Currency           `json:"currency" db:"currency"`
+	AssetType   AssetType          `json:"asset_type" db:"asset_type"`
+	Category    InstrumentCategory `json:"category,omitempty" db:"category"` // 台股商品類別（其他資產類型為空）
+	LotSize     float64            `json:"lot_size" db:"lot_size"`           // 每交易單位數量（台股一張 1000 股）
+	ProviderIDs map[string]string  `json:"provider_ids" db:"provider_ids"`   // 各價格來源的代碼，例如 {"coingecko": "bitcoin"}
+	CreatedAt   time.Time          `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at" db:"updated_at"`
+}
+
+// DisplayName 取得顯示名稱（台股優先使用中文名稱，其餘優先使用英文名稱）
+func (i *Instrument) DisplayName() string {
+	if i.AssetType == AssetTypeTWStock && i.NameZhTW != "" {
+		return i.NameZhTW
+	}
+	if i.NameEn != "" {
+		return i.NameEn
+	}
+	if i.NameZhTW != "" {
+		return i.NameZhTW
+	}
+	return i.Symbol
+}
+
+// InstrumentImportResult 商品主檔匯入結果
+type InstrumentImportResult struct {
+	Source   InstrumentSource `json:"source"`
+	Parsed   int              `json:"parsed"`   // 檔案中解析出的商品數
+	Imported int              `json:"imported"` // 新增或更新的商品數
+	Skipped  int              `json:"skipped"`  // 格式不符、測試代碼或重複而略過的筆數
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestClassifyTWInstrument 測試依台股代號判斷商品類別
+func TestClassifyTWInstrument(t *testing.T) {
+	tests := []struct {
+		name   string
+		symbol string
+		want   InstrumentCategory
+	}{
+		{name: "上市股票", symbol: "2330", want: InstrumentCategoryStock},
+		{name: "興櫃股票", symbol: "6869", want: InstrumentCategoryStock},
+		{name: "四碼 ETF", symbol: "0050", want: InstrumentCategoryETF},
+		{name: "六碼 ETF", symbol: "006208", want: InstrumentCategoryETF},
+		{name: "期貨 ETF", symbol: "00635U", want: InstrumentCategoryETF},
+		{name: "債券 ETF", symbol: "00679B", want: InstrumentCategoryBondETF},
+		{name: "槓桿 ETF", symbol: "00631L", want: InstrumentCategoryLeveragedETF},
+		{name: "反向 ETF（小寫）", symbol: "00632r", want: InstrumentCategoryLeveragedETF},
+		{name: "ETN", symbol: "020020", want: InstrumentCategoryETN},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ClassifyTWInstrument(tt.symbol))
+		})
+	}
+}
@@ -0,0 +1,356 @@
+package models
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LoanType 貸款類型
+type LoanType string
+
+const (
+	LoanTypeMortgage LoanType = "mortgage" // 房貸
+	LoanTypeCar      LoanType = "car"      // 車貸
+	LoanTypePersonal LoanType = "personal" // 信貸
+	LoanTypeOther    LoanType = "other"    // 其他
+)
+
+// LoanStatus 貸款狀態
+type LoanStatus string
+
+const (
+	LoanStatusActive    LoanStatus = "active"    // 還款中
+	LoanStatusPaidOff   LoanStatus = "paid_off"  // 已清償
+	LoanStatusCancelled LoanStatus = "cancelled" // 已取消
+)
+
+// Loan 貸款模型
+type Loan struct {
+	ID                 uuid.UUID     `json:"id" db:"id"`
+	Name               string        `json:"name" db:"name"`
+	LoanType           LoanType      `json:"loan_type" db:"loan_type"`
+	Principal          float64       `json:"principal" db:"principal"`
+	Currency           Currency      `json:"currency" db:"currency"`
+	AnnualRate         float64       `json:"annual_rate" db:"annual_rate"` // 初始年利率（%）
+	TermMonths         int           `json:"term_months" db:"term_months"`
+	PaymentDay         int           `json:"payment_day" db:"payment_day"`
+	PaidCount          int           `json:"paid_count" db:"paid_count"`
+	StartDate          time.Time     `json:"start_date" db:"start_date"` // 撥款日期
+	CategoryID         uuid.UUID     `json:"category_id" db:"category_id"`
+	InterestCategoryID *uuid.UUID    `json:"interest_category_id,omitempty" db:"interest_category_id"`
+	PaymentMethod      PaymentMethod `json:"payment_method" db:"payment_method"`
+	AccountID          *uuid.UUID    `json:"account_id,omitempty" db:"account_id"`
+	Status             LoanStatus    `json:"status" db:"status"`
+	Note               *string       `json:"note,omitempty" db:"note"`
+	CreatedAt          time.Time     `json:"created_at" db:"created_at"`
+	UpdatedAt          time.Time     `json:"updated_at" db:"updated_at"`
+
+	// 關聯資料（查詢詳細資料時載入）
+	RateChanges []*LoanRateChange `json:"rate_changes,omitempty" db:"-"`
+	Prepayments []*LoanPrepayment `json:"prepayments,omitempty" db:"-"`
+	Category    *CashFlowCategory `json:"category,omitempty" db:"-"`
+}
+
+// LoanRateChange 貸款利率調整記錄
+type LoanRateChange struct {
+	ID            uuid.UUID `json:"id" db:"id"`
+	LoanID        uuid.UUID `json:"loan_id" db:"loan_id"`
+	EffectiveDate time.Time `json:"effective_date" db:"effective_date"`
+	AnnualRate    float64   `json:"annual_rate" db:"annual_rate"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+}
+
+// LoanPrepayment 貸款提前還本記錄
+type LoanPrepayment struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	LoanID    uuid.UUID `json:"loan_id" db:"loan_id"`
+	Date      time.Time `json:"date" db:"date"`
+	Amount    float64   `json:"amount" db:"amount"`
+	Note      *string   `json:"note,omitempty" db:"note"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// LoanPayment 貸款每期還款記錄（由扣款排程產生）
+type LoanPayment struct {
+	ID                  uuid.UUID  `json:"id" db:"id"`
+	LoanID              uuid.UUID  `json:"loan_id" db:"loan_id"`
+	Period              int        `json:"period" db:"period"`
+	PaymentDate         time.Time  `json:"payment_date" db:"payment_date"`
+	PrincipalAmount     float64    `json:"principal_amount" db:"principal_amount"`
+	InterestAmount      float64    `json:"interest_amount" db:"interest_amount"`
+	PrincipalCashFlowID *uuid.UUID `json:"principal_cash_flow_id,omitempty" db:"principal_cash_flow_id"`
+	InterestCashFlowID  *uuid.UUID `json:"interest_cash_flow_id,omitempty" db:"interest_cash_flow_id"`
+	CreatedAt           time.Time  `json:"created_at" db:"created_at"`
+}
+
+// AmortizationEntry 攤還表中的單期資料
+type AmortizationEntry struct {
+	Period           int       `json:"period"`            // 期數（從 1 開始）
+	PaymentDate      time.Time `json:"payment_date"`      // 還款日期
+	AnnualRate       float64   `json:"annual_rate"`       // 當期適用年利率（%）
+	Payment          float64   `json:"payment"`           // 當期應繳金額（本金 + 利息）
+	Principal        float64   `json:"principal"`         // 當期本金
+	Interest         float64   `json:"interest"`          // 當期利息
+	Prepayment       float64   `json:"prepayment"`        // 當期提前還本金額
+	RemainingBalance float64   `json:"remaining_balance"` // 當期結束後剩餘本金
+	IsPaid           bool      `json:"is_paid"`           // 是否已扣款
+}
+
+// AmortizationSchedule 貸款攤還表
+type AmortizationSchedule struct {
+	LoanID           uuid.UUID           `json:"loan_id"`
+	Entries          []AmortizationEntry `json:"entries"`
+	TotalPayment     float64             `json:"total_payment"`     // 總還款金額（含提前還本）
+	TotalInterest    float64             `json:"total_interest"`    // 總利息
+	RemainingBalance float64             `json:"remaining_balance"` // 目前剩餘本金
+}
+
+// LoanBalance 貸款剩餘本金摘要（供淨值計算使用）
+type LoanBalance struct {
+	LoanID           uuid.UUID `json:"loan_id"`
+	Name             string    `json:"name"`
+	LoanType         LoanType  `json:"loan_type"`
+	RemainingBalance float64   `json:"remaining_balance"`
+}
+
+// CreateLoanInput 建立貸款的輸入資料
+type CreateLoanInput struct {
+	Name               string        `json:"name" binding:"required,max=255"`
+	LoanType           LoanType      `json:"loan_type" binding:"required"`
+	Principal          float64       `json:"principal" binding:"required,gt=0"`
+	AnnualRate         float64       `json:"annual_rate" binding:"gte=0"`
+	TermMonths         int           `json:"term_months" binding:"required,gt=0"`
+	PaymentDay         int           `json:"payment_day" binding:"required,min=1,max=31"`
+	StartDate          time.Time     `json:"start_date" binding:"required"`
+	CategoryID         uuid.UUID     `json:"category_id" binding:"required"`
+	InterestCategoryID *uuid.UUID    `json:"interest_category_id,omitempty"`
+	PaymentMethod      PaymentMethod `json:"payment_method" binding:"required"`
+	AccountID          *uuid.UUID    `json:"account_id,omitempty"`
+	Note               *string       `json:"note,omitempty"`
+}
+
+// UpdateLoanInput 更新貸款的輸入資料
+type UpdateLoanInput struct {
+	Name               *string        `json:"name,omitempty" binding:"omitempty,max=255"`
+	PaymentDay         *int           `json:"payment_day,omitempty" binding:"omitempty,min=1,max=31"`
+	CategoryID         *uuid.UUID     `json:"category_id,omitempty"`
+	InterestCategoryID *uuid.UUID     `json:"interest_category_id,omitempty"`
+	PaymentMethod      *PaymentMethod `json:"payment_method,omitempty"`
+	AccountID          *uuid.UUID     `json:"account_id,omitempty"`
+	PaidCount          *int           `json:"paid_count,omitempty" binding:"omitempty,gte=0"`
+	Status             *LoanStatus    `json:"status,omitempty"`
+	Note               *string        `json:"note,omitempty"`
+}
+
+// CreateLoanRateChangeInput 新增利率調整的輸入資料
+type CreateLoanRateChangeInput struct {
+	EffectiveDate time.Time `json:"effective_date" binding:"required"`
+	AnnualRate    float64   `json:"annual_rate" binding:"gte=0"`
+}
+
+// CreateLoanPrepaymentInput 新增提前還本的輸入資料
+type CreateLoanPrepaymentInput struct {
+	Date   time.Time `json:"date" binding:"required"`
+	Amount float64   `json:"amount" binding:"required,gt=0"`
+	Note   *string   `json:"note,omitempty"`
+}
+
+// Validate 驗證 LoanType 是否有效
+func (t LoanType) Validate() bool {
+	switch t {
+	case LoanTypeMortgage, LoanTypeCar, LoanTypePersonal, LoanTypeOther:
+		return true
+	}
+	return false
+}
+
+// Validate 驗證 LoanStatus 是否有效
+func (s LoanStatus) Validate() bool {
+	switch s {
+	case LoanStatusActive, LoanStatusPaidOff, LoanStatusCancelled:
+		return true
+	}
+	return false
+}
+
+// PaymentDate 計算第 N 期的還款日期
+// 第一期為撥款日的次月還款日
+func (l *Loan) PaymentDate(period int) time.Time {
+	year := l.StartDate.Year()
+	month := int(l.StartDate.Month()) + period
+
+	// 處理跨年的情況
+	year += (month - 1) / 12
+	month = (month-1)%12 + 1
+
+	paymentDate := time.Date(year, time.Month(month), l.PaymentDay, 0, 0, 0, 0, time.UTC)
+
+	// 處理月份天數不足的情況（例如 2 月沒有 31 日）
+	if paymentDate.Month() != time.Month(month) {
+		paymentDate = time.Date(year, time.Month(month)+1, 0, 0, 0, 0, 0, time.UTC)
+	}
+
+	return paymentDate
+}
+
+// RateOn 取得指定日期適用的年利率（%）
+func (l *Loan) RateOn(date time.Time) float64 {
+	rate := l.AnnualRate
+	var latest time.Time
+	for _, change := range l.RateChanges {
+		if change.EffectiveDate.After(date) {
+			continue
+		}
+		if latest.IsZero() || !change.EffectiveDate.Before(latest) {
+			latest = change.EffectiveDate
+			rate = change.AnnualRate
+		}
+	}
+	return rate
+}
+
+// GenerateAmortizationSchedule 產生本息平均攤還表
+// 每期依剩餘本金、當期利率與剩餘期數重新計算應繳金額，
+// 因此利率調整或提前還本後，月付金會自動重新攤提；剩餘本金以 asOf 為基準日計算
+func (l *Loan) GenerateAmortizationSchedule(asOf time.Time) *AmortizationSchedule {
+	schedule := &AmortizationSchedule{
+		LoanID:  l.ID,
+		Entries: []AmortizationEntry{},
+	}
+
+	// 依日期排序提前還本記錄
+	prepayments := make([]*LoanPrepayment, len(l.Prepayments))
+	copy(prepayments, l.Prepayments)
+	sort.Slice(prepayments, func(i, j int) bool {
+		return prepayments[i].Date.Before(prepayments[j].Date)
+	})
+
+	balance := l.Principal
+	previousDate := l.StartDate
+	prepaymentIndex := 0
+
+	for period := 1; period <= l.TermMonths && balance > 0.005; period++ {
+		paymentDate := l.PaymentDate(period)
+		annualRate := l.RateOn(paymentDate)
+		monthlyRate := annualRate / 100 / 12
+		remainingPeriods := l.TermMonths - period + 1
+
+		interest := roundToCents(balance * monthlyRate)
+		payment := roundToCents(annuityPayment(balance, monthlyRate, remainingPeriods))
+		principal := roundToCents(payment - interest)
+
+		// 最後一期或本金不足時，一次還清剩餘本金
+		if period == l.TermMonths || principal > balance {
+			principal = balance
+			payment = roundToCents(principal + interest)
+		}
+		balance = roundToCents(balance - principal)
+
+		// 套用本期區間內（上期還款日之後至本期還款日）的提前還本
+		prepaid := 0.0
+		for prepaymentIndex < len(prepayments) && !prepayments[prepaymentIndex].Date.After(paymentDate) {
+			if prepayments[prepaymentIndex].Date.After(previousDate) || period == 1 {
+				prepaid += prepayments[prepaymentIndex].Amount
+			}
+			prepaymentIndex++
+		}
+		if prepaid > balance {
+			prepaid = balance
+		}
+		balance = roundToCents(balance - prepaid)
+
+		schedule.Entries = append(schedule.Entries, AmortizationEntry{
+			Period:           period,
+			PaymentDate:      paymentDate,
+			AnnualRate:       annualRate,
+			Payment:          payment,
+			Principal:        principal,
+			Interest:         interest,
+			Prepayment:       prepaid,
+			RemainingBalance: balance,
+			IsPaid:           period <= l.PaidCount,
+		})
+
+		schedule.TotalPayment += payment + prepaid
+		schedule.TotalInterest += interest
+		previousDate = paymentDate
+	}
+
+	schedule.TotalPayment = roundToCents(schedule.TotalPayment)
+	schedule.TotalInterest = roundToCents(schedule.TotalInterest)
+	schedule.RemainingBalance = l.RemainingBalance(schedule, asOf)
+
+	return schedule
+}
+
+// RemainingBalance 根據攤還表計算 asOf 當日的剩餘本金
+// 以已扣款期數為準，並扣除 asOf 當日（含）以前已發生的提前還本
+func (l *Loan) RemainingBalance(schedule *AmortizationSchedule, asOf time.Time) float64 {
+	if l.Status == LoanStatusPaidOff || l.Status == LoanStatusCancelled {
+		return 0
+	}
+
+	balance := l.Principal
+	for _, entry := range schedule.Entries {
+		if entry.Period > l.PaidCount {
+			break
+		}
+		balance -= entry.Principal + entry.Prepayment
+	}
+
+	// 扣除尚未併入已付期數的提前還本（發生於最後一次扣款之後）
+	if l.PaidCount < len(schedule.Entries) {
+		lastPaidDate := l.StartDate
+		if l.PaidCount > 0 {
+			lastPaidDate = schedule.Entries[l.PaidCount-1].PaymentDate
+		}
+		for _, prepayment := range l.Prepayments {
+			if prepayment.Date.After(lastPaidDate) && !prepayment.Date.After(asOf) {
+				balance -= prepayment.Amount
+			}
+		}
+	}
+
+	if balance < 0 {
+		return 0
+	}
+	return roundToCents(balance)
+}
+
+// IsActive 檢查貸款是否還款中
+func (l *Loan) IsActive() bool {
+	return l.Status == LoanStatusActive && l.PaidCount < l.TermMonths
+}
+
+// ValidatePaymentMethod 驗證付款方式設定是否正確
+func (l *Loan) ValidatePaymentMethod() bool {
+	if !l.PaymentMethod.Validate() {
+		return false
+	}
+
+	if l.PaymentMethod.RequiresAccountID() {
+		return l.AccountID != nil
+	}
+
+	return true
+}
+
+// annuityPayment 本息平均攤還的每期應繳金額
+func annuityPayment(balance, monthlyRate float64, periods int) float64 {
+	if periods <= 0 {
+		return balance
+	}
+	if monthlyRate == 0 {
+		return balance / float64(periods)
+	}
+	factor := math.Pow(1+monthlyRate, float64(periods))
+	return balance * monthlyRate * factor / (factor - 1)
+}
+
+// roundToCents 四捨五入到小數點後兩位
+func roundToCents(value float64) float64 {
+	return math.Round(value*100) / 100
+}
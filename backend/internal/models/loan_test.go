@@ -0,0 +1,228 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestLoan 建立測試用貸款：12 萬、年利率 12%、12 期
+func newTestLoan() *Loan {
+	return &Loan{
+		Name:       "信貸",
+		LoanType:   LoanTypePersonal,
+		Principal:  120000,
+		AnnualRate: 12,
+		TermMonths: 12,
+		PaymentDay: 10,
+		StartDate:  time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC),
+		Status:     LoanStatusActive,
+	}
+}
+
+// TestLoanType_Validate 測試 LoanType 驗證
+func TestLoanType_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		loanType LoanType
+		want     bool
+	}{
+		{name: "mortgage", loanType: LoanTypeMortgage, want: true},
+		{name: "car", loanType: LoanTypeCar, want: true},
+		{name: "personal", loanType: LoanTypePersonal, want: true},
+		{name: "other", loanType: LoanTypeOther, want: true},
+		{name: "invalid", loanType: LoanType("student"), want: false},
+		{name: "empty", loanType: LoanType(""), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.loanType.Validate())
+		})
+	}
+}
+
+// TestLoanStatus_Validate 測試 LoanStatus 驗證
+func TestLoanStatus_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		status LoanStatus
+		want   bool
+	}{
+		{name: "active", status: LoanStatusActive, want: true},
+		{name: "paid off", status: LoanStatusPaidOff, want: true},
+		{name: "cancelled", status: LoanStatusCancelled, want: true},
+		{name: "invalid", status: LoanStatus("invalid"), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.status.Validate())
+		})
+	}
+}
+
+// TestLoan_PaymentDate 測試還款日期計算
+func TestLoan_PaymentDate(t *testing.T) {
+	loan := newTestLoan()
+	loan.PaymentDay = 31
+	loan.StartDate = time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		period int
+		want   time.Time
+	}{
+		{name: "first period crosses year", period: 1, want: time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)},
+		{name: "february clamps to month end", period: 2, want: time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC)},
+		{name: "april clamps to 30th", period: 4, want: time.Date(2025, 4, 30, 0, 0, 0, 0, time.UTC)},
+		{name: "thirteenth period", period: 13, want: time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, loan.PaymentDate(tt.period))
+		})
+	}
+}
+
+// TestLoan_RateOn 測試利率調整生效日
+func TestLoan_RateOn(t *testing.T) {
+	loan := newTestLoan()
+	loan.RateChanges = []*LoanRateChange{
+		{EffectiveDate: time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC), AnnualRate: 6},
+		{EffectiveDate: time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC), AnnualRate: 9},
+	}
+
+	assert.Equal(t, 12.0, loan.RateOn(time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 9.0, loan.RateOn(time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 9.0, loan.RateOn(time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 6.0, loan.RateOn(time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)))
+}
+
+// TestLoan_GenerateAmortizationSchedule 測試本息平均攤還表
+func TestLoan_GenerateAmortizationSchedule(t *testing.T) {
+	t.Run("fixed rate", func(t *testing.T) {
+		loan := newTestLoan()
+		schedule := loan.GenerateAmortizationSchedule(time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC))
+
+		assert.Len(t, schedule.Entries, 12)
+
+		first := schedule.Entries[0]
+		assert.Equal(t, 10661.85, first.Payment)
+		assert.Equal(t, 1200.0, first.Interest)
+		assert.Equal(t, 9461.85, first.Principal)
+		assert.Equal(t, 110538.15, first.RemainingBalance)
+
+		last := schedule.Entries[11]
+		assert.Equal(t, 0.0, last.RemainingBalance)
+		assert.InDelta(t, 10661.85, last.Payment, 0.05)
+		assert.InDelta(t, 7942.2, schedule.TotalInterest, 0.1)
+		assert.Equal(t, 120000.0, schedule.RemainingBalance)
+	})
+
+	t.Run("zero interest", func(t *testing.T) {
+		loan := newTestLoan()
+		loan.AnnualRate = 0
+		schedule := loan.GenerateAmortizationSchedule(time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC))
+
+		assert.Len(t, schedule.Entries, 12)
+		for _, entry := range schedule.Entries {
+			assert.Equal(t, 10000.0, entry.Payment)
+			assert.Equal(t, 0.0, entry.Interest)
+		}
+		assert.Equal(t, 0.0, schedule.TotalInterest)
+	})
+
+	t.Run("rate change re-amortizes remaining periods", func(t *testing.T) {
+		loan := newTestLoan()
+		loan.RateChanges = []*LoanRateChange{
+			{EffectiveDate: time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC), AnnualRate: 0},
+		}
+		schedule := loan.GenerateAmortizationSchedule(time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC))
+
+		// 第 6 期（2025-07-10）起改為零利率，剩餘本金平均攤還
+		assert.Equal(t, 12.0, schedule.Entries[4].AnnualRate)
+		entry := schedule.Entries[5]
+		assert.Equal(t, 0.0, entry.AnnualRate)
+		assert.Equal(t, 0.0, entry.Interest)
+		assert.InDelta(t, schedule.Entries[4].RemainingBalance/7, entry.Principal, 0.01)
+		assert.Equal(t, 0.0, schedule.Entries[11].RemainingBalance)
+	})
+
+	t.Run("prepayment shortens balance and lowers payments", func(t *testing.T) {
+		loan := newTestLoan()
+		base := loan.GenerateAmortizationSchedule(time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC))
+
+		loan.Prepayments = []*LoanPrepayment{
+			{Date: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), Amount: 50000},
+		}
+		schedule := loan.GenerateAmortizationSchedule(time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC))
+
+		// 提前還本套用在第 2 期（2025-03-10）
+		assert.Equal(t, 0.0, schedule.Entries[0].Prepayment)
+		assert.Equal(t, 50000.0, schedule.Entries[1].Prepayment)
+		assert.Less(t, schedule.Entries[2].Payment, base.Entries[2].Payment)
+		assert.Less(t, schedule.TotalInterest, base.TotalInterest)
+		assert.Equal(t, 0.0, schedule.Entries[len(schedule.Entries)-1].RemainingBalance)
+	})
+
+	t.Run("prepayment clears loan early", func(t *testing.T) {
+		loan := newTestLoan()
+		loan.Prepayments = []*LoanPrepayment{
+			{Date: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), Amount: 200000},
+		}
+		schedule := loan.GenerateAmortizationSchedule(time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC))
+
+		assert.Len(t, schedule.Entries, 1)
+		assert.Equal(t, 110538.15, schedule.Entries[0].Prepayment)
+		assert.Equal(t, 0.0, schedule.Entries[0].RemainingBalance)
+	})
+}
+
+// TestLoan_RemainingBalance 測試剩餘本金計算
+func TestLoan_RemainingBalance(t *testing.T) {
+	t.Run("after paid periods", func(t *testing.T) {
+		loan := newTestLoan()
+		loan.PaidCount = 1
+		asOf := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+		schedule := loan.GenerateAmortizationSchedule(asOf)
+
+		assert.Equal(t, 110538.15, loan.RemainingBalance(schedule, asOf))
+	})
+
+	t.Run("includes prepayment made after last payment", func(t *testing.T) {
+		loan := newTestLoan()
+		loan.PaidCount = 1
+		loan.Prepayments = []*LoanPrepayment{
+			{Date: time.Date(2025, 2, 20, 0, 0, 0, 0, time.UTC), Amount: 10000},
+		}
+		asOf := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+		schedule := loan.GenerateAmortizationSchedule(asOf)
+
+		assert.Equal(t, 100538.15, loan.RemainingBalance(schedule, asOf))
+	})
+
+	t.Run("ignores prepayment after as-of date", func(t *testing.T) {
+		loan := newTestLoan()
+		loan.PaidCount = 1
+		loan.Prepayments = []*LoanPrepayment{
+			{Date: time.Date(2025, 2, 20, 0, 0, 0, 0, time.UTC), Amount: 10000},
+		}
+		asOf := time.Date(2025, 2, 15, 0, 0, 0, 0, time.UTC)
+		schedule := loan.GenerateAmortizationSchedule(asOf)
+
+		assert.Equal(t, 110538.15, loan.RemainingBalance(schedule, asOf))
+		assert.Equal(t, 110538.15, schedule.RemainingBalance)
+	})
+
+	t.Run("paid off loan has no balance", func(t *testing.T) {
+		loan := newTestLoan()
+		loan.Status = LoanStatusPaidOff
+		asOf := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+		schedule := loan.GenerateAmortizationSchedule(asOf)
+
+		assert.Equal(t, 0.0, loan.RemainingBalance(schedule, asOf))
+	})
+}
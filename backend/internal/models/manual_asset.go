@@ -0,0 +1,202 @@
+package models
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ManualAssetCategory 手動估值資產分類
+type ManualAssetCategory string
+
+const (
+	ManualAssetCategoryRealEstate    ManualAssetCategory = "real_estate"    // 不動產
+	ManualAssetCategoryVehicle       ManualAssetCategory = "vehicle"        // 車輛
+	ManualAssetCategoryInsurance     ManualAssetCategory = "insurance"      // 保單價值準備金
+	ManualAssetCategoryPrivateEquity ManualAssetCategory = "private_equity" // 未上市股權
+	ManualAssetCategoryCollectible   ManualAssetCategory = "collectible"    // 收藏品
+	ManualAssetCategoryOther         ManualAssetCategory = "other"          // 其他
+)
+
+// ValuationScheduleType 估值調整方式
+type ValuationScheduleType string
+
+const (
+	ValuationScheduleNone         ValuationScheduleType = "none"          // 僅使用手動估值
+	ValuationScheduleStraightLine ValuationScheduleType = "straight_line" // 直線折舊至殘值
+	ValuationScheduleCompound     ValuationScheduleType = "compound"      // 依年利率複合增值（負值為折舊）
+)
+
+// ManualAssetStatus 手動估值資產狀態
+type ManualAssetStatus string
+
+const (
+	ManualAssetStatusActive   ManualAssetStatus = "active"   // 持有中
+	ManualAssetStatusDisposed ManualAssetStatus = "disposed" // 已處分
+)
+
+// ManualAsset 手動估值資產模型（不動產、車輛等無市場報價的資產）
+type ManualAsset struct {
+	ID               uuid.UUID             `json:"id" db:"id"`
+	Name             string                `json:"name" db:"name"`
+	Category         ManualAssetCategory   `json:"category" db:"category"`
+	Currency         Currency              `json:"currency" db:"currency"`
+	AcquisitionDate  time.Time             `json:"acquisition_date" db:"acquisition_date"`
+	AcquisitionCost  float64               `json:"acquisition_cost" db:"acquisition_cost"`
+	ScheduleType     ValuationScheduleType `json:"schedule_type" db:"schedule_type"`
+	AnnualRate       *float64              `json:"annual_rate,omitempty" db:"annual_rate"`               // 年增減值率（%）
+	UsefulLifeMonths *int                  `json:"useful_life_months,omitempty" db:"useful_life_months"` // 耐用月數
+	SalvageValue     *float64              `json:"salvage_value,omitempty" db:"salvage_value"`           // 殘值
+	Status           ManualAssetStatus     `json:"status" db:"status"`
+	Note             *string               `json:"note,omitempty" db:"note"`
+	CreatedAt        time.Time             `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time             `json:"updated_at" db:"updated_at"`
+
+	// 計算欄位（查詢時填入）
+	Valuations      []*ManualAssetValuation `json:"valuations,omitempty" db:"-"`
+	CurrentValue    float64                 `json:"current_value" db:"-"`     // 目前估值（原幣）
+	CurrentValueTWD float64                 `json:"current_value_twd" db:"-"` // 目前估值（TWD）
+}
+
+// ManualAssetValuation 手動估值記錄
+type ManualAssetValuation struct {
+	ID            uuid.UUID `json:"id" db:"id"`
+	AssetID       uuid.UUID `json:"asset_id" db:"asset_id"`
+	ValuationDate time.Time `json:"valuation_date" db:"valuation_date"`
+	Value         float64   `json:"value" db:"value"`
+	Note          *string   `json:"note,omitempty" db:"note"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+}
+
+// CreateManualAssetInput 建立手動估值資產的輸入資料
+type CreateManualAssetInput struct {
+	Name             string                `json:"name" binding:"required,max=255"`
+	Category         ManualAssetCategory   `json:"category" binding:"required"`
+	Currency         Currency              `json:"currency" binding:"required,oneof=TWD USD"`
+	AcquisitionDate  time.Time             `json:"acquisition_date" binding:"required"`
+	AcquisitionCost  float64               `json:"acquisition_cost" binding:"gte=0"`
+	ScheduleType     ValuationScheduleType `json:"schedule_type" binding:"required"`
+	AnnualRate       *float64              `json:"annual_rate,omitempty"`
+	UsefulLifeMonths *int                  `json:"useful_life_months,omitempty" binding:"omitempty,gt=0"`
+	SalvageValue     *float64              `json:"salvage_value,omitempty" binding:"omitempty,gte=0"`
+	Note             *string               `json:"note,omitempty"`
+}
+
+// UpdateManualAssetInput 更新手動估值資產的輸入資料
+type UpdateManualAssetInput struct {
+	Name             *string                `json:"name,omitempty" binding:"omitempty,max=255"`
+	Category         *ManualAssetCategory   `json:"category,omitempty"`
+	ScheduleType     *ValuationScheduleType `json:"schedule_type,omitempty"`
+	AnnualRate       *float64               `json:"annual_rate,omitempty"`
+	UsefulLifeMonths *int                   `json:"useful_life_months,omitempty" binding:"omitempty,gt=0"`
+	SalvageValue     *float64               `json:"salvage_value,omitempty" binding:"omitempty,gte=0"`
+	Status           *ManualAssetStatus     `json:"status,omitempty"`
+	Note             *string                `json:"note,omitempty"`
+}
+
+// CreateManualAssetValuationInput 新增估值記錄的輸入資料
+type CreateManualAssetValuationInput struct {
+	ValuationDate time.Time `json:"valuation_date" binding:"required"`
+	Value         float64   `json:"value" binding:"gte=0"`
+	Note          *string   `json:"note,omitempty"`
+}
+
+// Validate 驗證 ManualAssetCategory 是否有效
+func (c ManualAssetCategory) Validate() bool {
+	switch c {
+	case ManualAssetCategoryRealEstate, ManualAssetCategoryVehicle, ManualAssetCategoryInsurance,
+		ManualAssetCategoryPrivateEquity, ManualAssetCategoryCollectible, ManualAssetCategoryOther:
+		return true
+	}
+	return false
+}
+
+// Validate 驗證 ValuationScheduleType 是否有效
+func (t ValuationScheduleType) Validate() bool {
+	switch t {
+	case ValuationScheduleNone, ValuationScheduleStraightLine, ValuationScheduleCompound:
+		return true
+	}
+	return false
+}
+
+// Validate 驗證 ManualAssetStatus 是否有效
+func (s ManualAssetStatus) Validate() bool {
+	switch s {
+	case ManualAssetStatusActive, ManualAssetStatusDisposed:
+		return true
+	}
+	return false
+}
+
+// LatestValuation 取得指定日期（含）之前最新的估值記錄
+func (a *ManualAsset) LatestValuation(date time.Time) *ManualAssetValuation {
+	var latest *ManualAssetValuation
+	for _, valuation := range a.Valuations {
+		if valuation.ValuationDate.After(date) {
+			continue
+		}
+		if latest == nil || valuation.ValuationDate.After(latest.ValuationDate) {
+			latest = valuation
+		}
+	}
+	return latest
+}
+
+// ValueOn 計算指定日期的估值（原幣）
+// 以最新的手動估值（無估值時使用取得成本）為基準，再依估值調整方式推算
+func (a *ManualAsset) ValueOn(date time.Time) float64 {
+	if date.Before(a.AcquisitionDate) {
+		return 0
+	}
+
+	baseValue := a.AcquisitionCost
+	baseDate := a.AcquisitionDate
+	if valuation := a.LatestValuation(date); valuation != nil {
+		baseValue = valuation.Value
+		baseDate = valuation.ValuationDate
+	}
+
+	switch a.ScheduleType {
+	case ValuationScheduleStraightLine:
+		return roundToCents(a.straightLineValue(baseValue, baseDate, date))
+	case ValuationScheduleCompound:
+		if a.AnnualRate == nil {
+			return baseValue
+		}
+		years := date.Sub(baseDate).Hours() / 24 / 365.25
+		return roundToCents(baseValue * math.Pow(1+*a.AnnualRate/100, years))
+	default:
+		return baseValue
+	}
+}
+
+// straightLineValue 直線折舊：自基準日起，在剩餘耐用期間內線性遞減至殘值
+func (a *ManualAsset) straightLineValue(baseValue float64, baseDate, date time.Time) float64 {
+	if a.UsefulLifeMonths == nil {
+		return baseValue
+	}
+
+	salvage := 0.0
+	if a.SalvageValue != nil {
+		salvage = *a.SalvageValue
+	}
+	if baseValue <= salvage {
+		return baseValue
+	}
+
+	endOfLife := a.AcquisitionDate.AddDate(0, *a.UsefulLifeMonths, 0)
+	if !date.Before(endOfLife) || !baseDate.Before(endOfLife) {
+		return salvage
+	}
+
+	remaining := endOfLife.Sub(baseDate).Hours()
+	elapsed := date.Sub(baseDate).Hours()
+	return baseValue - (baseValue-salvage)*elapsed/remaining
+}
+
+// IsActive 檢查資產是否持有中
+func (a *ManualAsset) IsActive() bool {
+	return a.Status == ManualAssetStatusActive
+}
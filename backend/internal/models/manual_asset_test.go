@@ -0,0 +1,116 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestManualAsset 建立測試用手動估值資產：2024-01-01 以 120 萬取得
+func newTestManualAsset(scheduleType ValuationScheduleType) *ManualAsset {
+	return &ManualAsset{
+		Name:            "自用車",
+		Category:        ManualAssetCategoryVehicle,
+		Currency:        CurrencyTWD,
+		AcquisitionDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		AcquisitionCost: 1200000,
+		ScheduleType:    scheduleType,
+		Status:          ManualAssetStatusActive,
+	}
+}
+
+// TestManualAssetCategory_Validate 測試 ManualAssetCategory 驗證
+func TestManualAssetCategory_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		category ManualAssetCategory
+		want     bool
+	}{
+		{name: "real estate", category: ManualAssetCategoryRealEstate, want: true},
+		{name: "vehicle", category: ManualAssetCategoryVehicle, want: true},
+		{name: "insurance", category: ManualAssetCategoryInsurance, want: true},
+		{name: "private equity", category: ManualAssetCategoryPrivateEquity, want: true},
+		{name: "collectible", category: ManualAssetCategoryCollectible, want: true},
+		{name: "other", category: ManualAssetCategoryOther, want: true},
+		{name: "invalid", category: ManualAssetCategory("stock"), want: false},
+		{name: "empty", category: ManualAssetCategory(""), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.category.Validate())
+		})
+	}
+}
+
+// TestValuationScheduleType_Validate 測試 ValuationScheduleType 驗證
+func TestValuationScheduleType_Validate(t *testing.T) {
+	assert.True(t, ValuationScheduleNone.Validate())
+	assert.True(t, ValuationScheduleStraightLine.Validate())
+	assert.True(t, ValuationScheduleCompound.Validate())
+	assert.False(t, ValuationScheduleType("declining").Validate())
+}
+
+// TestManualAsset_ValueOn 測試估值推算
+func TestManualAsset_ValueOn(t *testing.T) {
+	t.Run("before acquisition", func(t *testing.T) {
+		asset := newTestManualAsset(ValuationScheduleNone)
+
+		assert.Equal(t, 0.0, asset.ValueOn(time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC)))
+	})
+
+	t.Run("none uses latest valuation", func(t *testing.T) {
+		asset := newTestManualAsset(ValuationScheduleNone)
+		asset.Valuations = []*ManualAssetValuation{
+			{ValuationDate: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), Value: 900000},
+			{ValuationDate: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), Value: 1000000},
+		}
+
+		assert.Equal(t, 1200000.0, asset.ValueOn(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)))
+		assert.Equal(t, 1000000.0, asset.ValueOn(time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC)))
+		assert.Equal(t, 900000.0, asset.ValueOn(time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)))
+	})
+
+	t.Run("straight line depreciates to salvage", func(t *testing.T) {
+		asset := newTestManualAsset(ValuationScheduleStraightLine)
+		usefulLife := 48
+		salvage := 200000.0
+		asset.UsefulLifeMonths = &usefulLife
+		asset.SalvageValue = &salvage
+
+		assert.Equal(t, 1200000.0, asset.ValueOn(asset.AcquisitionDate))
+		assert.InDelta(t, 700000, asset.ValueOn(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)), 1000)
+		assert.Equal(t, 200000.0, asset.ValueOn(time.Date(2028, 1, 1, 0, 0, 0, 0, time.UTC)))
+		assert.Equal(t, 200000.0, asset.ValueOn(time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)))
+	})
+
+	t.Run("straight line rebases on manual valuation", func(t *testing.T) {
+		asset := newTestManualAsset(ValuationScheduleStraightLine)
+		usefulLife := 48
+		asset.UsefulLifeMonths = &usefulLife
+		asset.Valuations = []*ManualAssetValuation{
+			{ValuationDate: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), Value: 400000},
+		}
+
+		// 剩餘兩年自 40 萬線性折舊至 0
+		assert.Equal(t, 400000.0, asset.ValueOn(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)))
+		assert.InDelta(t, 200000, asset.ValueOn(time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)), 1000)
+	})
+
+	t.Run("compound appreciation", func(t *testing.T) {
+		asset := newTestManualAsset(ValuationScheduleCompound)
+		rate := 10.0
+		asset.AnnualRate = &rate
+
+		assert.InDelta(t, 1452000, asset.ValueOn(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)), 500)
+	})
+
+	t.Run("compound depreciation", func(t *testing.T) {
+		asset := newTestManualAsset(ValuationScheduleCompound)
+		rate := -20.0
+		asset.AnnualRate = &rate
+
+		assert.InDelta(t, 960000, asset.ValueOn(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)), 500)
+	})
+}
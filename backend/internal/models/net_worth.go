@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// NetWorth 淨值摘要（所有金額皆為 TWD）
+type NetWorth struct {
+	Date time.Time `json:"date"`
+
+	// 資產
+	InvestmentValue  float64 `json:"investment_value"`   // 投資持倉市值
+	CashValue        float64 `json:"cash_value"`         // 銀行帳戶餘額
+	ManualAssetValue float64 `json:"manual_asset_value"` // 手動估值資產（不動產、車輛等）
+	TotalAssets      float64 `json:"total_assets"`       // 資產總額
+
+	// 負債
+	CreditCardDebt   float64 `json:"credit_card_debt"`  // 信用卡已使用額度
+	InstallmentDebt  float64 `json:"installment_debt"`  // 分期剩餘應付金額
+	LoanDebt         float64 `json:"loan_debt"`         // 貸款剩餘本金
+	TotalLiabilities float64 `json:"total_liabilities"` // 負債總額
+
+	NetWorth float64 `json:"net_worth"` // 淨值 = 資產總額 - 負債總額
+
+	// 明細
+	ManualAssets []*ManualAsset `json:"manual_assets"`
+	Loans        []*LoanBalance `json:"loans"`
+}
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OptionType 選擇權類型
+type OptionType string
+
+const (
+	OptionTypeCall OptionType = "call" // 買權
+	OptionTypePut  OptionType = "put"  // 賣權
+)
+
+// DefaultOptionMultiplier 美股選擇權每口合約的預設標的股數
+const DefaultOptionMultiplier = 100.0
+
+// Validate 驗證 OptionType 是否有效
+func (o OptionType) Validate() bool {
+	switch o {
+	case OptionTypeCall, OptionTypePut:
+		return true
+	}
+	return false
+}
+
+// OptionContract 選擇權合約規格
+// 交易記錄以合約代碼（Symbol）對應，數量以口數計、價格為每股權利金
+type OptionContract struct {
+	ID               uuid.UUID  `json:"id" db:"id"`
+	Symbol           string     `json:"symbol" db:"symbol"`                       // 合約代碼，例如 AAPL251219C00200000
+	UnderlyingSymbol string     `json:"underlying_symbol" db:"underlying_symbol"` // 標的股票代碼
+	OptionType       OptionType `json:"option_type" db:"option_type"`
+	StrikePrice      float64    `json:"strike_price" db:"strike_price"`
+	ExpiryDate       time.Time  `json:"expiry_date" db:"expiry_date"`
+	Multiplier       float64    `json:"multiplier" db:"multiplier"` // 每口合約代表的標的股數
+	CreatedAt        time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// CreateOptionContractInput 建立選擇權合約的輸入資料
+type CreateOptionContractInput struct {
+	Symbol           string     `json:"symbol" binding:"required,max=50"`
+	UnderlyingSymbol string     `json:"underlying_symbol" binding:"required,max=20"`
+	OptionType       OptionType `json:"option_type" binding:"required,oneof=call put"`
+	StrikePrice      float64    `json:"strike_price" binding:"required,gt=0"`
+	ExpiryDate       time.Time  `json:"expiry_date" binding:"required"`
+	Multiplier       *float64   `json:"multiplier,omitempty" binding:"omitempty,gt=0"` // 未提供時使用 DefaultOptionMultiplier
+}
+
+// IntrinsicValue 依標的價格計算每股內含價值
+// 買權為 max(標的價格 - 履約價, 0)；賣權為 max(履約價 - 標的價格, 0)
+func (o *OptionContract) IntrinsicValue(underlyingPrice float64) float64 {
+	if o.OptionType == OptionTypeCall {
+		return math.Max(underlyingPrice-o.StrikePrice, 0)
+	}
+	return math.Max(o.StrikePrice-underlyingPrice, 0)
+}
+
+// IsExpired 合約於指定日期是否已過到期日
+func (o *OptionContract) IsExpired(date time.Time) bool {
+	return !date.Before(o.ExpiryDate.AddDate(0, 0, 1))
+}
+
+// AssignmentTransactionType 履約時標的股票的交易方向
+// 持有買權履約或賣出賣權被指派為買進標的；持有賣權履約或賣出買權被指派為賣出標的
+func (o *OptionContract) AssignmentTransactionType(shortPosition bool) TransactionType {
+	buysUnderlying := o.OptionType == OptionTypeCall
+	if shortPosition {
+		buysUnderlying = !buysUnderlying
+	}
+	if buysUnderlying {
+		return TransactionTypeBuy
+	}
+	return TransactionTypeSell
+}
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestOptionContract_IntrinsicValue 測試選擇權內含價值計算
+func TestOptionContract_IntrinsicValue(t *testing.T) {
+	call := &OptionContract{OptionType: OptionTypeCall, StrikePrice: 200}
+	put := &OptionContract{OptionType: OptionTypePut, StrikePrice: 200}
+
+	assert.Equal(t, 15.0, call.IntrinsicValue(215))
+	assert.Equal(t, 0.0, call.IntrinsicValue(190))
+	assert.Equal(t, 10.0, put.IntrinsicValue(190))
+	assert.Equal(t, 0.0, put.IntrinsicValue(215))
+}
+
+// TestOptionContract_AssignmentTransactionType 測試履約時標的股票的交易方向
+func TestOptionContract_AssignmentTransactionType(t *testing.T) {
+	call := &OptionContract{OptionType: OptionTypeCall}
+	put := &OptionContract{OptionType: OptionTypePut}
+
+	assert.Equal(t, TransactionTypeBuy, call.AssignmentTransactionType(false)) // 持有買權履約
+	assert.Equal(t, TransactionTypeSell, call.AssignmentTransactionType(true)) // 賣出買權被指派
+	assert.Equal(t, TransactionTypeSell, put.AssignmentTransactionType(false)) // 持有賣權履約
+	assert.Equal(t, TransactionTypeBuy, put.AssignmentTransactionType(true))   // 賣出賣權被指派
+}
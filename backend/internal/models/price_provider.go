@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// PriceProviderStatus 價格來源的健康狀態與呼叫統計
+type PriceProviderStatus struct {
+	Name                string      `json:"name"`
+	AssetTypes          []AssetType `json:"asset_types"`          // 支援的資產類型
+	Healthy             bool        `json:"healthy"`              // 連續失敗次數未達門檻
+	TotalRequests       int64       `json:"total_requests"`       // 總呼叫次數
+	SuccessCount        int64       `json:"success_count"`        // 至少取得一筆價格的呼叫次數
+	FailureCount        int64       `json:"failure_count"`        // 發生錯誤或未取得任何價格的呼叫次數
+	ConsecutiveFailures int         `json:"consecutive_failures"` // 連續失敗次數
+	AvgLatencyMs        float64     `json:"avg_latency_ms"`       // 平均回應時間（毫秒）
+	LastSuccessAt       *time.Time  `json:"last_success_at,omitempty"`
+	LastFailureAt       *time.Time  `json:"last_failure_at,omitempty"`
+	LastError           string      `json:"last_error,omitempty"`
+	DailyRequests       int         `json:"daily_requests"`        // 今日已使用的請求次數（速率限制器統計）
+	DailyQuota          int         `json:"daily_quota,omitempty"` // 每日請求上限（0 表示不限制）
+}
+
+// PriceProviderChain 單一資產類型的價格來源順序（依序嘗試，前者失敗時改用下一個）
+type PriceProviderChain struct {
+	AssetType AssetType `json:"asset_type"`
+	Providers []string  `json:"providers"`
+}
+
+// PriceProvidersOverview 價格來源設定與狀態總覽
+type PriceProvidersOverview struct {
+	Chains    []*PriceProviderChain  `json:"chains"`
+	Providers []*PriceProviderStatus `json:"providers"`
+}
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// PriceRefreshGroup 單一資產類型的背景價格更新結果
+type PriceRefreshGroup struct {
+	AssetType  AssetType `json:"asset_type"`
+	MarketOpen bool      `json:"market_open"`     // 更新時是否在交易時段內
+	Symbols    int       `json:"symbols"`         // 需要更新的標的數
+	Refreshed  int       `json:"refreshed"`       // 成功取得最新價格的標的數
+	Error      string    `json:"error,omitempty"` // 更新失敗原因
+}
+
+// PriceRefreshResult 背景價格更新結果（只包含本次到期並實際更新的資產類型）
+type PriceRefreshResult struct {
+	RunAt  time.Time            `json:"run_at"`
+	Groups []*PriceRefreshGroup `json:"groups"`
+}
+
+// FailedGroups 取得更新失敗的資產類型
+func (r *PriceRefreshResult) FailedGroups() []*PriceRefreshGroup {
+	failed := []*PriceRefreshGroup{}
+	for _, group := range r.Groups {
+		if group.Error != "" {
+			failed = append(failed, group)
+		}
+	}
+	return failed
+}
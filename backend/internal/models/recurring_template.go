@@ -0,0 +1,399 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RecurrenceFrequency 定期收支的重複頻率
+type RecurrenceFrequency string
+
+const (
+	RecurrenceDaily   RecurrenceFrequency = "daily"   // 每日
+	RecurrenceWeekly  RecurrenceFrequency = "weekly"  // 每週
+	RecurrenceMonthly RecurrenceFrequency = "monthly" // 每月
+	RecurrenceYearly  RecurrenceFrequency = "yearly"  // 每年
+)
+
+// BusinessDayAdjustment 發生日遇週末時的調整方式
+type BusinessDayAdjustment string
+
+const (
+	BusinessDayAdjustmentNone     BusinessDayAdjustment = "none"     // 不調整
+	BusinessDayAdjustmentPrevious BusinessDayAdjustment = "previous" // 提前至前一個營業日
+	BusinessDayAdjustmentNext     BusinessDayAdjustment = "next"     // 延後至下一個營業日
+)
+
+// RecurringTemplateStatus 定期收支範本狀態
+type RecurringTemplateStatus string
+
+const (
+	RecurringTemplateStatusActive RecurringTemplateStatus = "active" // 啟用中
+	RecurringTemplateStatusPaused RecurringTemplateStatus = "paused" // 暫停
+	RecurringTemplateStatusEnded  RecurringTemplateStatus = "ended"  // 已結束
+)
+
+// LastDayOfMonth 以 by_month_day = -1 表示每月最後一天
+const LastDayOfMonth = -1
+
+// businessDayWindow 營業日調整最多移動的天數（週六、週日）
+const businessDayWindow = 2
+
+// RecurringTemplate 定期收支範本模型
+// 排程規則類似 iCalendar RRULE：frequency + interval 決定週期，
+// by_weekdays / by_month_day / by_months 進一步限定發生日，
+// 例如「每兩週五」、「每月最後一個營業日」、「每年 3 月與 9 月 15 日」
+type RecurringTemplate struct {
+	ID                    uuid.UUID               `json:"id" db:"id"`
+	Name                  string                  `json:"name" db:"name"`
+	Type                  CashFlowType            `json:"type" db:"type"`
+	CategoryID            uuid.UUID               `json:"category_id" db:"category_id"`
+	Amount                float64                 `json:"amount" db:"amount"` // 初始金額
+	Currency              Currency                `json:"currency" db:"currency"`
+	PaymentMethod         PaymentMethod           `json:"payment_method" db:"payment_method"`
+	AccountID             *uuid.UUID              `json:"account_id,omitempty" db:"account_id"`
+	TargetType            *SourceType             `json:"target_type,omitempty" db:"target_type"` // 轉帳目標類型（僅 transfer_out）
+	TargetID              *uuid.UUID              `json:"target_id,omitempty" db:"target_id"`
+	Frequency             RecurrenceFrequency     `json:"frequency" db:"frequency"`
+	Interval              int                     `json:"interval" db:"interval_count"`
+	ByWeekdays            []int                   `json:"by_weekdays" db:"by_weekdays"`             // 0: 週日 ~ 6: 週六（每日、每週適用）
+	ByMonthDay            *int                    `json:"by_month_day,omitempty" db:"by_month_day"` // -1 表示月底（每月、每年適用）
+	ByMonths              []int                   `json:"by_months" db:"by_months"`                 // 1 ~ 12
+	BusinessDayAdjustment BusinessDayAdjustment   `json:"business_day_adjustment" db:"business_day_adjustment"`
+	StartDate             time.Time               `json:"start_date" db:"start_date"`
+	EndDate               *time.Time              `json:"end_date,omitempty" db:"end_date"`
+	Status                RecurringTemplateStatus `json:"status" db:"status"`
+	Note                  *string                 `json:"note,omitempty" db:"note"`
+	CreatedAt             time.Time               `json:"created_at" db:"created_at"`
+	UpdatedAt             time.Time               `json:"updated_at" db:"updated_at"`
+
+	// 關聯資料（查詢詳細資料時載入）
+	AmountChanges []*RecurringAmountChange `json:"amount_changes,omitempty" db:"-"`
+	Category      *CashFlowCategory        `json:"category,omitempty" db:"-"`
+}
+
+// RecurringAmountChange 定期收支金額調整記錄
+type RecurringAmountChange struct {
+	ID            uuid.UUID `json:"id" db:"id"`
+	TemplateID    uuid.UUID `json:"template_id" db:"template_id"`
+	EffectiveDate time.Time `json:"effective_date" db:"effective_date"`
+	Amount        float64   `json:"amount" db:"amount"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+}
+
+// RecurringOccurrence 定期收支的單次發生
+type RecurringOccurrence struct {
+	Date     time.Time `json:"date"`
+	Amount   float64   `json:"amount"`
+	Currency Currency  `json:"currency"`
+}
+
+// CreateRecurringTemplateInput 建立定期收支範本的輸入資料
+type CreateRecurringTemplateInput struct {
+	Name                  string                `json:"name" binding:"required,max=255"`
+	Type                  CashFlowType          `json:"type" binding:"required"`
+	CategoryID            uuid.UUID             `json:"category_id" binding:"required"`
+	Amount                float64               `json:"amount" binding:"required,gt=0"`
+	Currency              Currency              `json:"currency,omitempty" binding:"omitempty,oneof=TWD USD"`
+	PaymentMethod         PaymentMethod         `json:"payment_method" binding:"required"`
+	AccountID             *uuid.UUID            `json:"account_id,omitempty"`
+	TargetType            *SourceType           `json:"target_type,omitempty"`
+	TargetID              *uuid.UUID            `json:"target_id,omitempty"`
+	Frequency             RecurrenceFrequency   `json:"frequency" binding:"required"`
+	Interval              int                   `json:"interval,omitempty" binding:"omitempty,gt=0"`
+	ByWeekdays            []int                 `json:"by_weekdays,omitempty"`
+	ByMonthDay            *int                  `json:"by_month_day,omitempty"`
+	ByMonths              []int                 `json:"by_months,omitempty"`
+	BusinessDayAdjustment BusinessDayAdjustment `json:"business_day_adjustment,omitempty"`
+	StartDate             time.Time             `json:"start_date" binding:"required"`
+	EndDate               *time.Time            `json:"end_date,omitempty"`
+	Note                  *string               `json:"note,omitempty"`
+}
+
+// UpdateRecurringTemplateInput 更新定期收支範本的輸入資料
+type UpdateRecurringTemplateInput struct {
+	Name                  *string                  `json:"name,omitempty" binding:"omitempty,max=255"`
+	CategoryID            *uuid.UUID               `json:"category_id,omitempty"`
+	Amount                *float64                 `json:"amount,omitempty" binding:"omitempty,gt=0"`
+	PaymentMethod         *PaymentMethod           `json:"payment_method,omitempty"`
+	AccountID             *uuid.UUID               `json:"account_id,omitempty"`
+	TargetType            *SourceType              `json:"target_type,omitempty"`
+	TargetID              *uuid.UUID               `json:"target_id,omitempty"`
+	Frequency             *RecurrenceFrequency     `json:"frequency,omitempty"`
+	Interval              *int                     `json:"interval,omitempty" binding:"omitempty,gt=0"`
+	ByWeekdays            *[]int                   `json:"by_weekdays,omitempty"`
+	ByMonthDay            *int                     `json:"by_month_day,omitempty"`
+	ByMonths              *[]int                   `json:"by_months,omitempty"`
+	BusinessDayAdjustment *BusinessDayAdjustment   `json:"business_day_adjustment,omitempty"`
+	EndDate               *time.Time               `json:"end_date,omitempty"`
+	Status                *RecurringTemplateStatus `json:"status,omitempty"`
+	Note                  *string                  `json:"note,omitempty"`
+}
+
+// CreateRecurringAmountChangeInput 新增金額調整的輸入資料
+type CreateRecurringAmountChangeInput struct {
+	EffectiveDate time.Time `json:"effective_date" binding:"required"`
+	Amount        float64   `json:"amount" binding:"required,gt=0"`
+}
+
+// Validate 驗證 RecurrenceFrequency 是否有效
+func (f RecurrenceFrequency) Validate() bool {
+	switch f {
+	case RecurrenceDaily, RecurrenceWeekly, RecurrenceMonthly, RecurrenceYearly:
+		return true
+	}
+	return false
+}
+
+// Validate 驗證 BusinessDayAdjustment 是否有效
+func (a BusinessDayAdjustment) Validate() bool {
+	switch a {
+	case BusinessDayAdjustmentNone, BusinessDayAdjustmentPrevious, BusinessDayAdjustmentNext:
+		return true
+	}
+	return false
+}
+
+// Adjust 將落在週末的日期調整為營業日
+func (a BusinessDayAdjustment) Adjust(date time.Time) time.Time {
+	switch a {
+	case BusinessDayAdjustmentPrevious:
+		switch date.Weekday() {
+		case time.Saturday:
+			return date.AddDate(0, 0, -1)
+		case time.Sunday:
+			return date.AddDate(0, 0, -2)
+		}
+	case BusinessDayAdjustmentNext:
+		switch date.Weekday() {
+		case time.Saturday:
+			return date.AddDate(0, 0, 2)
+		case time.Sunday:
+			return date.AddDate(0, 0, 1)
+		}
+	}
+	return date
+}
+
+// Validate 驗證 RecurringTemplateStatus 是否有效
+func (s RecurringTemplateStatus) Validate() bool {
+	switch s {
+	case RecurringTemplateStatusActive, RecurringTemplateStatusPaused, RecurringTemplateStatusEnded:
+		return true
+	}
+	return false
+}
+
+// ValidateSchedule 驗證排程規則
+func (t *RecurringTemplate) ValidateSchedule() error {
+	if !t.Frequency.Validate() {
+		return fmt.Errorf("invalid frequency: %s", t.Frequency)
+	}
+
+	if t.Interval < 1 {
+		return fmt.Errorf("interval must be at least 1")
+	}
+
+	for _, weekday := range t.ByWeekdays {
+		if weekday < 0 || weekday > 6 {
+			return fmt.Errorf("by_weekdays must be between 0 (Sunday) and 6 (Saturday)")
+		}
+	}
+
+	if len(t.ByWeekdays) > 0 && t.Frequency != RecurrenceDaily && t.Frequency != RecurrenceWeekly {
+		return fmt.Errorf("by_weekdays is only supported for daily or weekly frequency")
+	}
+
+	if t.ByMonthDay != nil {
+		if *t.ByMonthDay != LastDayOfMonth && (*t.ByMonthDay < 1 || *t.ByMonthDay > 31) {
+			return fmt.Errorf("by_month_day must be between 1 and 31, or -1 for the last day of month")
+		}
+		if t.Frequency != RecurrenceMonthly && t.Frequency != RecurrenceYearly {
+			return fmt.Errorf("by_month_day is only supported for monthly or yearly frequency")
+		}
+	}
+
+	for _, month := range t.ByMonths {
+		if month < 1 || month > 12 {
+			return fmt.Errorf("by_months must be between 1 and 12")
+		}
+	}
+
+	if !t.BusinessDayAdjustment.Validate() {
+		return fmt.Errorf("invalid business day adjustment: %s", t.BusinessDayAdjustment)
+	}
+
+	if t.EndDate != nil && t.EndDate.Before(t.StartDate) {
+		return fmt.Errorf("end date must be on or after start date")
+	}
+
+	return nil
+}
+
+// AmountOn 取得指定日期適用的金額（套用生效日最晚且不晚於該日的金額調整）
+func (t *RecurringTemplate) AmountOn(date time.Time) float64 {
+	amount := t.Amount
+	var latest time.Time
+	for _, change := range t.AmountChanges {
+		if change.EffectiveDate.After(date) {
+			continue
+		}
+		if latest.IsZero() || !change.EffectiveDate.Before(latest) {
+			latest = change.EffectiveDate
+			amount = change.Amount
+		}
+	}
+	return amount
+}
+
+// Occurrences 取得指定期間（含首尾）內的發生日期與金額
+// 發生日先依排程規則計算，再依營業日調整方式移動，
+// 因此原本落在期間外的週末發生日也可能被調整進來
+func (t *RecurringTemplate) Occurrences(from, to time.Time) []*RecurringOccurrence {
+	from = BillingDate(from)
+	to = BillingDate(to)
+
+	occurrences := []*RecurringOccurrence{}
+	var last time.Time
+	end := to.AddDate(0, 0, businessDayWindow)
+	for date := from.AddDate(0, 0, -businessDayWindow); !date.After(end); date = date.AddDate(0, 0, 1) {
+		if !t.matchesSchedule(date) {
+			continue
+		}
+
+		// 調整後的日期隨原日期遞增，只需與前一筆比較即可去除重複
+		adjusted := t.BusinessDayAdjustment.Adjust(date)
+		if adjusted.Before(from) || adjusted.After(to) || adjusted.Equal(last) {
+			continue
+		}
+		last = adjusted
+
+		occurrences = append(occurrences, &RecurringOccurrence{
+			Date:     adjusted,
+			Amount:   t.AmountOn(adjusted),
+			Currency: t.Currency,
+		})
+	}
+
+	return occurrences
+}
+
+// OccursOn 判斷指定日期是否為發生日
+func (t *RecurringTemplate) OccursOn(date time.Time) bool {
+	return len(t.Occurrences(date, date)) > 0
+}
+
+// NextOccurrence 取得指定日期（含）之後的下一次發生，沒有時回傳 nil
+func (t *RecurringTemplate) NextOccurrence(from time.Time) *RecurringOccurrence {
+	from = BillingDate(from)
+
+	// 最長的週期為 interval 年，多搜尋一年涵蓋指定月份與營業日調整
+	interval := t.Interval
+	if interval < 1 {
+		interval = 1
+	}
+	to := from.AddDate(interval+1, 0, 0)
+	if t.EndDate != nil && BillingDate(*t.EndDate).AddDate(0, 0, businessDayWindow).Before(to) {
+		to = BillingDate(*t.EndDate).AddDate(0, 0, businessDayWindow)
+	}
+
+	occurrences := t.Occurrences(from, to)
+	if len(occurrences) == 0 {
+		return nil
+	}
+	return occurrences[0]
+}
+
+// matchesSchedule 判斷日期是否符合排程規則（營業日調整前）
+func (t *RecurringTemplate) matchesSchedule(date time.Time) bool {
+	start := BillingDate(t.StartDate)
+	if date.Before(start) {
+		return false
+	}
+	if t.EndDate != nil && date.After(BillingDate(*t.EndDate)) {
+		return false
+	}
+
+	if len(t.ByMonths) > 0 && !containsInt(t.ByMonths, int(date.Month())) {
+		return false
+	}
+
+	interval := t.Interval
+	if interval < 1 {
+		interval = 1
+	}
+
+	switch t.Frequency {
+	case RecurrenceDaily:
+		if daysBetween(start, date)%interval != 0 {
+			return false
+		}
+		return len(t.ByWeekdays) == 0 || containsInt(t.ByWeekdays, int(date.Weekday()))
+
+	case RecurrenceWeekly:
+		// 以開始日所在週（週日起算）為第 0 週
+		weeks := daysBetween(weekStart(start), weekStart(date)) / 7
+		if weeks%interval != 0 {
+			return false
+		}
+		weekdays := t.ByWeekdays
+		if len(weekdays) == 0 {
+			weekdays = []int{int(start.Weekday())}
+		}
+		return containsInt(weekdays, int(date.Weekday()))
+
+	case RecurrenceMonthly:
+		months := (date.Year()-start.Year())*12 + int(date.Month()) - int(start.Month())
+		if months%interval != 0 {
+			return false
+		}
+		return date.Day() == t.dayInMonth(date.Year(), date.Month())
+
+	case RecurrenceYearly:
+		if (date.Year()-start.Year())%interval != 0 {
+			return false
+		}
+		if len(t.ByMonths) == 0 && date.Month() != start.Month() {
+			return false
+		}
+		return date.Day() == t.dayInMonth(date.Year(), date.Month())
+	}
+
+	return false
+}
+
+// dayInMonth 取得指定月份的發生日（未指定時沿用開始日，超過月底時以月底計）
+func (t *RecurringTemplate) dayInMonth(year int, month time.Month) int {
+	lastDay := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+
+	day := t.StartDate.Day()
+	if t.ByMonthDay != nil {
+		day = *t.ByMonthDay
+	}
+	if day == LastDayOfMonth || day > lastDay {
+		return lastDay
+	}
+	return day
+}
+
+// weekStart 取得日期所在週的週日
+func weekStart(date time.Time) time.Time {
+	return date.AddDate(0, 0, -int(date.Weekday()))
+}
+
+// daysBetween 計算兩個日期相差的天數
+func daysBetween(from, to time.Time) int {
+	return int(BillingDate(to).Sub(BillingDate(from)).Hours() / 24)
+}
+
+// containsInt 判斷整數切片是否包含指定值
+func containsInt(values []int, target int) bool {
+	for _, v := range values {
+		if v == target {
+			return true
+		}
+	}
+	return false
+}
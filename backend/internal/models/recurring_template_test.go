@@ -0,0 +1,221 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recurringDate 建立 UTC 日期（測試輔助）
+func recurringDate(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// occurrenceDates 取出發生日期字串
+func occurrenceDates(occurrences []*RecurringOccurrence) []string {
+	dates := []string{}
+	for _, o := range occurrences {
+		dates = append(dates, o.Date.Format("2006-01-02"))
+	}
+	return dates
+}
+
+// TestRecurringTemplate_Occurrences_BiWeekly 測試每兩週發生一次（零用錢每兩週五）
+func TestRecurringTemplate_Occurrences_BiWeekly(t *testing.T) {
+	template := &RecurringTemplate{
+		Amount:                500,
+		Frequency:             RecurrenceWeekly,
+		Interval:              2,
+		ByWeekdays:            []int{int(time.Friday)},
+		BusinessDayAdjustment: BusinessDayAdjustmentNone,
+		StartDate:             recurringDate(2025, 1, 3), // 週五
+	}
+
+	occurrences := template.Occurrences(recurringDate(2025, 1, 1), recurringDate(2025, 2, 28))
+
+	assert.Equal(t, []string{"2025-01-03", "2025-01-17", "2025-01-31", "2025-02-14", "2025-02-28"}, occurrenceDates(occurrences))
+}
+
+// TestRecurringTemplate_Occurrences_LastBusinessDay 測試每月最後一個營業日（薪資）
+func TestRecurringTemplate_Occurrences_LastBusinessDay(t *testing.T) {
+	lastDay := LastDayOfMonth
+	template := &RecurringTemplate{
+		Amount:                60000,
+		Frequency:             RecurrenceMonthly,
+		Interval:              1,
+		ByMonthDay:            &lastDay,
+		BusinessDayAdjustment: BusinessDayAdjustmentPrevious,
+		StartDate:             recurringDate(2025, 1, 1),
+	}
+
+	occurrences := template.Occurrences(recurringDate(2025, 1, 1), recurringDate(2025, 6, 30))
+
+	// 2025-05-31 是週六，提前至 5/30
+	assert.Equal(t, []string{"2025-01-31", "2025-02-28", "2025-03-31", "2025-04-30", "2025-05-30", "2025-06-30"}, occurrenceDates(occurrences))
+}
+
+// TestRecurringTemplate_Occurrences_SpecificMonths 測試指定月份（保險費每年 3 月與 9 月）
+func TestRecurringTemplate_Occurrences_SpecificMonths(t *testing.T) {
+	day := 15
+	template := &RecurringTemplate{
+		Amount:                12000,
+		Frequency:             RecurrenceYearly,
+		Interval:              1,
+		ByMonthDay:            &day,
+		ByMonths:              []int{3, 9},
+		BusinessDayAdjustment: BusinessDayAdjustmentNext,
+		StartDate:             recurringDate(2025, 1, 1),
+	}
+
+	occurrences := template.Occurrences(recurringDate(2025, 1, 1), recurringDate(2026, 12, 31))
+
+	// 2025-03-15 是週六，延後至 3/17；2026-03-15 是週日，延後至 3/16
+	assert.Equal(t, []string{"2025-03-17", "2025-09-15", "2026-03-16", "2026-09-15"}, occurrenceDates(occurrences))
+}
+
+// TestRecurringTemplate_Occurrences_EveryNMonths 測試每 N 個月（每半年）並以月底計算短月
+func TestRecurringTemplate_Occurrences_EveryNMonths(t *testing.T) {
+	template := &RecurringTemplate{
+		Amount:                3000,
+		Frequency:             RecurrenceMonthly,
+		Interval:              6,
+		BusinessDayAdjustment: BusinessDayAdjustmentNone,
+		StartDate:             recurringDate(2025, 8, 31),
+	}
+
+	occurrences := template.Occurrences(recurringDate(2025, 1, 1), recurringDate(2026, 12, 31))
+
+	assert.Equal(t, []string{"2025-08-31", "2026-02-28", "2026-08-31"}, occurrenceDates(occurrences))
+}
+
+// TestRecurringTemplate_Occurrences_EndDate 測試結束日之後不再發生
+func TestRecurringTemplate_Occurrences_EndDate(t *testing.T) {
+	endDate := recurringDate(2025, 1, 10)
+	template := &RecurringTemplate{
+		Amount:                100,
+		Frequency:             RecurrenceDaily,
+		Interval:              3,
+		BusinessDayAdjustment: BusinessDayAdjustmentNone,
+		StartDate:             recurringDate(2025, 1, 1),
+		EndDate:               &endDate,
+	}
+
+	occurrences := template.Occurrences(recurringDate(2025, 1, 1), recurringDate(2025, 1, 31))
+
+	assert.Equal(t, []string{"2025-01-01", "2025-01-04", "2025-01-07", "2025-01-10"}, occurrenceDates(occurrences))
+	assert.True(t, template.OccursOn(recurringDate(2025, 1, 7)))
+	assert.False(t, template.OccursOn(recurringDate(2025, 1, 13)))
+}
+
+// TestRecurringTemplate_AmountOn 測試金額調整（調薪）
+func TestRecurringTemplate_AmountOn(t *testing.T) {
+	template := &RecurringTemplate{
+		Amount:                50000,
+		Currency:              CurrencyTWD,
+		Frequency:             RecurrenceMonthly,
+		Interval:              1,
+		BusinessDayAdjustment: BusinessDayAdjustmentNone,
+		StartDate:             recurringDate(2025, 1, 5),
+		AmountChanges: []*RecurringAmountChange{
+			{EffectiveDate: recurringDate(2025, 7, 1), Amount: 55000},
+			{EffectiveDate: recurringDate(2025, 3, 1), Amount: 52000},
+		},
+	}
+
+	assert.Equal(t, 50000.0, template.AmountOn(recurringDate(2025, 2, 5)))
+	assert.Equal(t, 52000.0, template.AmountOn(recurringDate(2025, 3, 1)))
+	assert.Equal(t, 55000.0, template.AmountOn(recurringDate(2025, 12, 5)))
+
+	occurrences := template.Occurrences(recurringDate(2025, 6, 1), recurringDate(2025, 7, 31))
+	require.Len(t, occurrences, 2)
+	assert.Equal(t, 52000.0, occurrences[0].Amount)
+	assert.Equal(t, 55000.0, occurrences[1].Amount)
+}
+
+// TestRecurringTemplate_NextOccurrence 測試下一次發生日
+func TestRecurringTemplate_NextOccurrence(t *testing.T) {
+	template := &RecurringTemplate{
+		Amount:                1000,
+		Frequency:             RecurrenceYearly,
+		Interval:              2,
+		BusinessDayAdjustment: BusinessDayAdjustmentNone,
+		StartDate:             recurringDate(2024, 4, 10),
+	}
+
+	next := template.NextOccurrence(recurringDate(2024, 4, 11))
+	require.NotNil(t, next)
+	assert.Equal(t, "2026-04-10", next.Date.Format("2006-01-02"))
+
+	endDate := recurringDate(2025, 12, 31)
+	template.EndDate = &endDate
+	assert.Nil(t, template.NextOccurrence(recurringDate(2024, 4, 11)))
+}
+
+// TestRecurringTemplate_ValidateSchedule 測試排程規則驗證
+func TestRecurringTemplate_ValidateSchedule(t *testing.T) {
+	lastDay := LastDayOfMonth
+	invalidDay := 0
+
+	tests := []struct {
+		name     string
+		template RecurringTemplate
+		wantErr  bool
+	}{
+		{
+			name:     "valid weekly",
+			template: RecurringTemplate{Frequency: RecurrenceWeekly, Interval: 2, ByWeekdays: []int{1, 5}, BusinessDayAdjustment: BusinessDayAdjustmentNone},
+		},
+		{
+			name:     "valid last day of month",
+			template: RecurringTemplate{Frequency: RecurrenceMonthly, Interval: 1, ByMonthDay: &lastDay, BusinessDayAdjustment: BusinessDayAdjustmentPrevious},
+		},
+		{
+			name:     "invalid frequency",
+			template: RecurringTemplate{Frequency: "hourly", Interval: 1, BusinessDayAdjustment: BusinessDayAdjustmentNone},
+			wantErr:  true,
+		},
+		{
+			name:     "zero interval",
+			template: RecurringTemplate{Frequency: RecurrenceDaily, Interval: 0, BusinessDayAdjustment: BusinessDayAdjustmentNone},
+			wantErr:  true,
+		},
+		{
+			name:     "invalid weekday",
+			template: RecurringTemplate{Frequency: RecurrenceWeekly, Interval: 1, ByWeekdays: []int{7}, BusinessDayAdjustment: BusinessDayAdjustmentNone},
+			wantErr:  true,
+		},
+		{
+			name:     "weekdays with monthly frequency",
+			template: RecurringTemplate{Frequency: RecurrenceMonthly, Interval: 1, ByWeekdays: []int{1}, BusinessDayAdjustment: BusinessDayAdjustmentNone},
+			wantErr:  true,
+		},
+		{
+			name:     "invalid month day",
+			template: RecurringTemplate{Frequency: RecurrenceMonthly, Interval: 1, ByMonthDay: &invalidDay, BusinessDayAdjustment: BusinessDayAdjustmentNone},
+			wantErr:  true,
+		},
+		{
+			name:     "invalid month",
+			template: RecurringTemplate{Frequency: RecurrenceYearly, Interval: 1, ByMonths: []int{13}, BusinessDayAdjustment: BusinessDayAdjustmentNone},
+			wantErr:  true,
+		},
+		{
+			name:     "invalid business day adjustment",
+			template: RecurringTemplate{Frequency: RecurrenceDaily, Interval: 1, BusinessDayAdjustment: "nearest"},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.template.ValidateSchedule()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
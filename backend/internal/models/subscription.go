@@ -44,6 +44,28 @@ type Subscription struct {
 
 	// 關聯資料（Join 時使用）
 	Category *CashFlowCategory `json:"category,omitempty" db:"-"`
+
+	// 價格調整記錄（查詢詳細資料時載入）
+	PriceChanges []*SubscriptionPriceChange `json:"price_changes,omitempty" db:"-"`
+}
+
+// SubscriptionPriceChange 訂閱價格調整記錄
+type SubscriptionPriceChange struct {
+	ID             uuid.UUID `json:"id" db:"id"`
+	SubscriptionID uuid.UUID `json:"subscription_id" db:"subscription_id"`
+	EffectiveDate  time.Time `json:"effective_date" db:"effective_date"`
+	Amount         float64   `json:"amount" db:"amount"`
+	Note           *string   `json:"note,omitempty" db:"note"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+}
+
+// SubscriptionUsageNote 訂閱使用記錄
+type SubscriptionUsageNote struct {
+	ID             uuid.UUID `json:"id" db:"id"`
+	SubscriptionID uuid.UUID `json:"subscription_id" db:"subscription_id"`
+	UsedOn         time.Time `json:"used_on" db:"used_on"`
+	Note           *string   `json:"note,omitempty" db:"note"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
 }
 
 // CreateSubscriptionInput 建立訂閱的輸入資料
@@ -76,6 +98,19 @@ type UpdateSubscriptionInput struct {
 	Note          *string             `json:"note,omitempty"`
 }
 
+// CreateSubscriptionPriceChangeInput 新增價格調整的輸入資料
+type CreateSubscriptionPriceChangeInput struct {
+	EffectiveDate time.Time `json:"effective_date" binding:"required"`
+	Amount        float64   `json:"amount" binding:"required,gt=0"`
+	Note          *string   `json:"note,omitempty"`
+}
+
+// CreateSubscriptionUsageNoteInput 新增使用記錄的輸入資料
+type CreateSubscriptionUsageNoteInput struct {
+	UsedOn time.Time `json:"used_on" binding:"required"`
+	Note   *string   `json:"note,omitempty"`
+}
+
 // Validate 驗證 BillingCycle 是否有效
 func (b BillingCycle) Validate() bool {
 	switch b {
@@ -94,6 +129,54 @@ func (s SubscriptionStatus) Validate() bool {
 	return false
 }
 
+// CyclesPerYear 取得每年的扣款次數
+func (b BillingCycle) CyclesPerYear() int {
+	switch b {
+	case BillingCycleMonthly:
+		return 12
+	case BillingCycleQuarterly:
+		return 4
+	case BillingCycleYearly:
+		return 1
+	}
+	return 0
+}
+
+// PriceOn 取得指定日期適用的價格（套用生效日最晚且不晚於該日的價格調整，沒有時為目前金額）
+func (s *Subscription) PriceOn(date time.Time) float64 {
+	price := s.Amount
+	var latest time.Time
+	for _, change := range s.PriceChanges {
+		if change.EffectiveDate.After(date) {
+			continue
+		}
+		if latest.IsZero() || !change.EffectiveDate.Before(latest) {
+			latest = change.EffectiveDate
+			price = change.Amount
+		}
+	}
+	return price
+}
+
+// AnnualizedCostOn 取得指定日期適用價格換算的年化費用（原幣）
+func (s *Subscription) AnnualizedCostOn(date time.Time) float64 {
+	return s.PriceOn(date) * float64(s.BillingCycle.CyclesPerYear())
+}
+
+// IsActiveOn 檢查訂閱在指定日期是否有效（已開始且尚未結束）
+func (s *Subscription) IsActiveOn(date time.Time) bool {
+	if date.Before(s.StartDate) {
+		return false
+	}
+
+	if s.EndDate != nil {
+		return !date.After(*s.EndDate)
+	}
+
+	// 已取消但未設定結束日期時視為無效
+	return s.Status == SubscriptionStatusActive
+}
+
 // NextBillingDate 計算下次扣款日期
 // fromDate: 從哪個日期開始計算（通常是今天）
 func (s *Subscription) NextBillingDate(fromDate time.Time) time.Time {
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SubscriptionCostAnalytics 訂閱年化費用分析（金額皆換算為 TWD）
+type SubscriptionCostAnalytics struct {
+	AsOf                time.Time                   `json:"as_of"`
+	ActiveCount         int                         `json:"active_count"`
+	TotalAnnualCost     float64                     `json:"total_annual_cost"`    // 年化總費用
+	TotalMonthlyCost    float64                     `json:"total_monthly_cost"`   // 平均每月費用
+	ByCategory          []*SubscriptionCategoryCost `json:"by_category"`          // 依分類彙總（由高到低）
+	ByCurrency          []*SubscriptionCurrencyCost `json:"by_currency"`          // 依幣別彙總
+	MonthlyTrend        []*SubscriptionMonthlyCost  `json:"monthly_trend"`        // 每月費用與月增減
+	UnusedSubscriptions []*UnusedSubscription       `json:"unused_subscriptions"` // 近期沒有使用記錄的訂閱
+}
+
+// SubscriptionCategoryCost 分類年化費用
+type SubscriptionCategoryCost struct {
+	CategoryID   uuid.UUID `json:"category_id"`
+	CategoryName string    `json:"category_name"`
+	Count        int       `json:"count"`
+	AnnualCost   float64   `json:"annual_cost"` // TWD
+}
+
+// SubscriptionCurrencyCost 幣別年化費用
+type SubscriptionCurrencyCost struct {
+	Currency      Currency `json:"currency"`
+	Count         int      `json:"count"`
+	AnnualCost    float64  `json:"annual_cost"`     // 原幣
+	AnnualCostTWD float64  `json:"annual_cost_twd"` // 換算為 TWD
+}
+
+// SubscriptionMonthlyCost 每月訂閱費用（以月底有效的訂閱與價格計算）
+type SubscriptionMonthlyCost struct {
+	Month       string  `json:"month"`        // YYYY-MM
+	MonthlyCost float64 `json:"monthly_cost"` // 年化費用 / 12
+	Change      float64 `json:"change"`       // 與上月相比的變化金額
+	ChangePct   float64 `json:"change_pct"`   // 與上月相比的變化百分比
+	Count       int     `json:"count"`        // 有效訂閱數
+}
+
+// UnusedSubscription 近期沒有使用記錄的訂閱
+type UnusedSubscription struct {
+	SubscriptionID uuid.UUID  `json:"subscription_id"`
+	Name           string     `json:"name"`
+	LastUsedOn     *time.Time `json:"last_used_on,omitempty"` // 最近一次使用日期，從未記錄時為空
+	AnnualCost     float64    `json:"annual_cost"`            // TWD
+}
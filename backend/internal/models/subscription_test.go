@@ -297,3 +297,65 @@ func TestSubscription_IsActive(t *testing.T) {
 	}
 }
 
+
+func TestSubscription_PriceOn(t *testing.T) {
+	sub := &Subscription{
+		Amount:       199,
+		BillingCycle: BillingCycleMonthly,
+		StartDate:    time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC),
+		PriceChanges: []*SubscriptionPriceChange{
+			{EffectiveDate: time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC), Amount: 149},
+			{EffectiveDate: time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC), Amount: 199},
+			{EffectiveDate: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), Amount: 169},
+		},
+	}
+
+	tests := []struct {
+		name string
+		date time.Time
+		want float64
+	}{
+		{"before first change uses current amount", time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), 199},
+		{"original price", time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC), 149},
+		{"on effective date", time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), 169},
+		{"unsorted changes", time.Date(2025, 10, 31, 0, 0, 0, 0, time.UTC), 169},
+		{"latest change", time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), 199},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, sub.PriceOn(tt.date))
+		})
+	}
+
+	// 年化費用依計費週期換算
+	assert.Equal(t, 169.0*12, sub.AnnualizedCostOn(time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)))
+	sub.BillingCycle = BillingCycleQuarterly
+	assert.Equal(t, 169.0*4, sub.AnnualizedCostOn(time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestSubscription_IsActiveOn(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		status  SubscriptionStatus
+		endDate *time.Time
+		date    time.Time
+		want    bool
+	}{
+		{"before start", SubscriptionStatusActive, nil, start.AddDate(0, 0, -1), false},
+		{"on start", SubscriptionStatusActive, nil, start, true},
+		{"cancelled before end date", SubscriptionStatusCancelled, &end, end, true},
+		{"cancelled after end date", SubscriptionStatusCancelled, &end, end.AddDate(0, 0, 1), false},
+		{"cancelled without end date", SubscriptionStatusCancelled, nil, start.AddDate(0, 1, 0), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub := &Subscription{Status: tt.status, StartDate: start, EndDate: tt.endDate}
+			assert.Equal(t, tt.want, sub.IsActiveOn(tt.date))
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// stablecoins 視為準貨幣的美元穩定幣（以 1 USD 計價）
+var stablecoins = map[string]bool{
+	"USDT": true,
+	"USDC": true,
+}
+
+// IsStablecoin 判斷標的是否為美元穩定幣
+func IsStablecoin(symbol string) bool {
+	return stablecoins[strings.ToUpper(symbol)]
+}
+
+// CreateSwapInput 建立加密貨幣互換（例如 BTC→ETH、ETH→USDT）的輸入資料
+// 互換會在同一個資料庫交易中建立處分端的賣出與取得端的買入兩筆交易
+type CreateSwapInput struct {
+	Date             time.Time  `json:"date" binding:"required"`
+	FromSymbol       string     `json:"from_symbol" binding:"required"`
+	FromName         string     `json:"from_name"` // 未填寫時以商品主檔的名稱補上
+	FromQuantity     float64    `json:"from_quantity" binding:"required,gt=0"`
+	ToSymbol         string     `json:"to_symbol" binding:"required"`
+	ToName           string     `json:"to_name"`
+	ToQuantity       float64    `json:"to_quantity" binding:"required,gt=0"`
+	Value            *float64   `json:"value,omitempty" binding:"omitempty,gt=0"` // 成交當下的市值（以 Currency 計價）；任一端為穩定幣且以 USD 計價時可省略
+	Currency         Currency   `json:"currency" binding:"required"`
+	Fee              *float64   `json:"fee,omitempty" binding:"omitempty,gte=0"` // 手續費（以 Currency 計價），自處分所得扣除
+	CustodyAccountID *uuid.UUID `json:"custody_account_id,omitempty"`
+	Note             *string    `json:"note,omitempty"`
+}
+
+// MarketValue 取得互換的成交市值
+// 未提供市值時，以穩定幣一端的數量作為 USD 市值（優先採用處分端）
+func (in *CreateSwapInput) MarketValue() (float64, error) {
+	if in.Value != nil {
+		return *in.Value, nil
+	}
+
+	if in.Currency == CurrencyUSD {
+		if IsStablecoin(in.FromSymbol) {
+			return in.FromQuantity, nil
+		}
+		if IsStablecoin(in.ToSymbol) {
+			return in.ToQuantity, nil
+		}
+	}
+
+	return 0, fmt.Errorf("value is required unless one side is a stablecoin priced in USD")
+}
+
+// Swap 加密貨幣互換結果
+type Swap struct {
+	SwapID      uuid.UUID    `json:"swap_id"`
+	Disposal    *Transaction `json:"disposal"`    // 處分端（賣出）
+	Acquisition *Transaction `json:"acquisition"` // 取得端（買入）
+}
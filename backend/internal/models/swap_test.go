@@ -0,0 +1,37 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCreateSwapInput_MarketValue 測試互換成交市值推算
+func TestCreateSwapInput_MarketValue(t *testing.T) {
+	value := 5000.0
+
+	tests := []struct {
+		name    string
+		input   CreateSwapInput
+		want    float64
+		wantErr bool
+	}{
+		{name: "明確提供市值", input: CreateSwapInput{FromSymbol: "BTC", FromQuantity: 0.1, ToSymbol: "ETH", ToQuantity: 2, Value: &value, Currency: CurrencyTWD}, want: 5000},
+		{name: "以穩定幣取得端推算", input: CreateSwapInput{FromSymbol: "ETH", FromQuantity: 1, ToSymbol: "USDT", ToQuantity: 3000, Currency: CurrencyUSD}, want: 3000},
+		{name: "以穩定幣處分端推算", input: CreateSwapInput{FromSymbol: "usdc", FromQuantity: 2500, ToSymbol: "SOL", ToQuantity: 15, Currency: CurrencyUSD}, want: 2500},
+		{name: "非 USD 計價不可推算", input: CreateSwapInput{FromSymbol: "ETH", FromQuantity: 1, ToSymbol: "USDT", ToQuantity: 3000, Currency: CurrencyTWD}, wantErr: true},
+		{name: "無穩定幣需提供市值", input: CreateSwapInput{FromSymbol: "BTC", FromQuantity: 0.1, ToSymbol: "ETH", ToQuantity: 2, Currency: CurrencyUSD}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.input.MarketValue()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -14,6 +14,8 @@ const (
 	AssetTypeTWStock  AssetType = "tw-stock"
 	AssetTypeUSStock  AssetType = "us-stock"
 	AssetTypeCrypto   AssetType = "crypto"
+	AssetTypeManual   AssetType = "manual"    // 手動估值資產（僅用於資產配置，不可用於交易）
+	AssetTypeUSOption AssetType = "us-option" // 美股選擇權（合約規格見 OptionContract）
 )
 
 // Currency 幣別
@@ -28,10 +30,17 @@ const (
 type TransactionType string
 
 const (
-	TransactionTypeBuy      TransactionType = "buy"
-	TransactionTypeSell     TransactionType = "sell"
-	TransactionTypeDividend TransactionType = "dividend"
-	TransactionTypeFee      TransactionType = "fee"
+	TransactionTypeBuy        TransactionType = "buy"
+	TransactionTypeSell       TransactionType = "sell"
+	TransactionTypeDividend   TransactionType = "dividend"
+	TransactionTypeFee        TransactionType = "fee"
+	TransactionTypeTransfer   TransactionType = "transfer"   // 保管帳戶間的內部轉移（非應稅事件）
+	TransactionTypeStaking    TransactionType = "staking"    // 質押收入
+	TransactionTypeAirdrop    TransactionType = "airdrop"    // 空投收入
+	TransactionTypeShort      TransactionType = "short"      // 放空（賣出開倉，建立空頭批次）
+	TransactionTypeCover      TransactionType = "cover"      // 回補（買進平倉，依 FIFO 沖銷空頭批次）
+	TransactionTypeExpiration TransactionType = "expiration" // 選擇權到期失效（以零價值了結剩餘部位）
+	TransactionTypeAssignment TransactionType = "assignment" // 選擇權履約（被指派或主動履約，並產生標的股票交易）
 )
 
 // Transaction 交易記錄模型
@@ -52,6 +61,20 @@ type Transaction struct {
 	Note            *string         `json:"note,omitempty" db:"note"`
 	CreatedAt       time.Time       `json:"created_at" db:"created_at"`
 	UpdatedAt       time.Time       `json:"updated_at" db:"updated_at"`
+
+	// 所屬券商帳戶（未指定時不產生交割現金流）
+	BrokerageAccountID *uuid.UUID `json:"brokerage_account_id,omitempty" db:"brokerage_account_id"`
+
+	// 加密貨幣保管帳戶（轉移交易為轉出帳戶），轉移交易另記錄轉入帳戶與網路手續費（以幣數計）
+	CustodyAccountID   *uuid.UUID `json:"custody_account_id,omitempty" db:"custody_account_id"`
+	ToCustodyAccountID *uuid.UUID `json:"to_custody_account_id,omitempty" db:"to_custody_account_id"`
+	NetworkFee         *float64   `json:"network_fee,omitempty" db:"network_fee"`
+
+	// 加密貨幣互換識別碼（同一次互換的處分與取得兩筆交易共用）
+	SwapID *uuid.UUID `json:"swap_id,omitempty" db:"swap_id"`
+
+	// 建立交易時的警告（例如手續費與手續費設定不符），不儲存於資料庫
+	Warnings []*Warning `json:"warnings,omitempty" db:"-"`
 }
 
 // CreateTransactionInput 建立交易的輸入資料
@@ -59,7 +82,7 @@ type CreateTransactionInput struct {
 	Date            time.Time       `json:"date" binding:"required"`
 	AssetType       AssetType       `json:"asset_type" binding:"required"`
 	Symbol          string          `json:"symbol" binding:"required"`
-	Name            string          `json:"name" binding:"required"`
+	Name            string          `json:"name"` // 未填寫時以商品主檔的名稱補上
 	TransactionType TransactionType `json:"type" binding:"required"`
 	Quantity        float64         `json:"quantity" binding:"required,gte=0"`
 	Price           float64         `json:"price" binding:"required,gte=0"`
@@ -68,6 +91,12 @@ type CreateTransactionInput struct {
 	Tax             *float64        `json:"tax,omitempty" binding:"omitempty,gte=0"`
 	Currency        Currency        `json:"currency" binding:"required"`
 	Note            *string         `json:"note,omitempty"`
+
+	BrokerageAccountID *uuid.UUID `json:"brokerage_account_id,omitempty"`                  // 所屬券商帳戶
+	CustodyAccountID   *uuid.UUID `json:"custody_account_id,omitempty"`                    // 保管帳戶（轉移交易為轉出帳戶）
+	ToCustodyAccountID *uuid.UUID `json:"to_custody_account_id,omitempty"`                 // 轉移交易的轉入帳戶
+	NetworkFee         *float64   `json:"network_fee,omitempty" binding:"omitempty,gte=0"` // 轉移交易的網路手續費（以幣數計）
+	SwapID             *uuid.UUID `json:"-"`                                               // 互換交易識別碼（由 CreateSwap 設定）
 }
 
 // BatchCreateTransactionsInput 批次建立交易的輸入資料
@@ -89,12 +118,17 @@ type UpdateTransactionInput struct {
 	Tax             *float64         `json:"tax,omitempty" binding:"omitempty,gte=0"`
 	Currency        *Currency        `json:"currency,omitempty"`
 	Note            *string          `json:"note,omitempty"`
+
+	BrokerageAccountID *uuid.UUID `json:"brokerage_account_id,omitempty"`                  // 所屬券商帳戶
+	CustodyAccountID   *uuid.UUID `json:"custody_account_id,omitempty"`                    // 保管帳戶（轉移交易為轉出帳戶）
+	ToCustodyAccountID *uuid.UUID `json:"to_custody_account_id,omitempty"`                 // 轉移交易的轉入帳戶
+	NetworkFee         *float64   `json:"network_fee,omitempty" binding:"omitempty,gte=0"` // 轉移交易的網路手續費（以幣數計）
 }
 
 // Validate 驗證 AssetType 是否有效
 func (a AssetType) Validate() bool {
 	switch a {
-	case AssetTypeCash, AssetTypeTWStock, AssetTypeUSStock, AssetTypeCrypto:
+	case AssetTypeCash, AssetTypeTWStock, AssetTypeUSStock, AssetTypeCrypto, AssetTypeUSOption:
 		return true
 	}
 	return false
@@ -103,7 +137,9 @@ func (a AssetType) Validate() bool {
 // Validate 驗證 TransactionType 是否有效
 func (t TransactionType) Validate() bool {
 	switch t {
-	case TransactionTypeBuy, TransactionTypeSell, TransactionTypeDividend, TransactionTypeFee:
+	case TransactionTypeBuy, TransactionTypeSell, TransactionTypeDividend, TransactionTypeFee,
+		TransactionTypeTransfer, TransactionTypeStaking, TransactionTypeAirdrop,
+		TransactionTypeShort, TransactionTypeCover, TransactionTypeExpiration, TransactionTypeAssignment:
 		return true
 	}
 	return false
@@ -118,3 +154,37 @@ func (c Currency) Validate() bool {
 	return false
 }
 
+// IsCryptoOnly 是否為僅適用於加密貨幣的交易類型（內部轉移、質押、空投）
+func (t TransactionType) IsCryptoOnly() bool {
+	switch t {
+	case TransactionTypeTransfer, TransactionTypeStaking, TransactionTypeAirdrop:
+		return true
+	}
+	return false
+}
+
+// ReceivedQuantity 轉移交易實際轉入的數量（轉出數量扣除網路手續費）
+func (t *Transaction) ReceivedQuantity() float64 {
+	if t.NetworkFee == nil {
+		return t.Quantity
+	}
+	return t.Quantity - *t.NetworkFee
+}
+
+// IsOptionEvent 是否為選擇權事件（到期失效、履約）
+func (t TransactionType) IsOptionEvent() bool {
+	switch t {
+	case TransactionTypeExpiration, TransactionTypeAssignment:
+		return true
+	}
+	return false
+}
+
+// IsClosing 是否為了結部位並產生已實現損益的交易類型（賣出、回補、選擇權到期與履約）
+func (t TransactionType) IsClosing() bool {
+	switch t {
+	case TransactionTypeSell, TransactionTypeCover, TransactionTypeExpiration, TransactionTypeAssignment:
+		return true
+	}
+	return false
+}
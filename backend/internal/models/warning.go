@@ -6,6 +6,15 @@ type WarningCode string
 const (
 	// WarningCodeInsufficientQuantity 數量不足警告
 	WarningCodeInsufficientQuantity WarningCode = "INSUFFICIENT_QUANTITY"
+
+	// WarningCodePositionConflict 交易方向與部位衝突警告（例如持有空頭部位時買進）
+	WarningCodePositionConflict WarningCode = "POSITION_CONFLICT"
+
+	// WarningCodeFeeDeviation 手續費與手續費設定計算結果不符警告
+	WarningCodeFeeDeviation WarningCode = "FEE_DEVIATION"
+
+	// WarningCodeTaxDeviation 交易稅與手續費設定計算結果不符警告
+	WarningCodeTaxDeviation WarningCode = "TAX_DEVIATION"
 )
 
 // Warning API 警告訊息
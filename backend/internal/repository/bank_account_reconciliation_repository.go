@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/chienchuanw/asset-manager/internal/models"
+	"github.com/google/uuid"
+)
+
+// BankAccountReconciliationRepository 銀行帳戶對帳記錄資料存取介面
+type BankAccountReconciliationRepository interface {
+	// CreateTx 在指定的資料庫交易中建立對帳記錄（與調整記錄一併提交）
+	CreateTx(tx *sql.Tx, reconciliation *models.BankAccountReconciliation) (*models.BankAccountReconciliation, error)
+	GetByAccountID(accountID uuid.UUID) ([]*models.BankAccountReconciliation, error)
+	DB() *sql.DB
+}
+
+// bankAccountReconciliationRepository 銀行帳戶對帳記錄資料存取實作
+type bankAccountReconciliationRepository struct {
+	db *sql.DB
+}
+
+// NewBankAccountReconciliationRepository 建立新的銀行帳戶對帳記錄 repository
+func NewBankAccountReconciliationRepository(db *sql.DB) BankAccountReconciliationRepository {
+	return &bankAccountReconciliationRepository{db: db}
+}
+
+// CreateTx 在指定的資料庫交易中建立對帳記錄
+func (r *bankAccountReconciliationRepository) CreateTx(tx *sql.Tx, reconciliation *models.BankAccountReconciliation) (*models.BankAccountReconciliation, error) {
+	query := `
+		INSERT INTO bank_account_reconciliations (
+			bank_account_id, statement_date, statement_balance, ledger_balance,
+			difference, adjustment_cash_flow_id, note
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, bank_account_id, statement_date, statement_balance, ledger_balance,
+			difference, adjustment_cash_flow_id, note, created_at
+	`
+
+	created := &models.BankAccountReconciliation{}
+	err := tx.QueryRow(
+		query,
+		reconciliation.BankAccountID,
+		reconciliation.StatementDate,
+		reconciliation.StatementBalance,
+		reconciliation.LedgerBalance,
+		reconciliation.Difference,
+		reconciliation.AdjustmentCashFlowID,
+		reconciliation.Note,
+	).Scan(
+		&created.ID,
+		&created.BankAccountID,
+		&created.StatementDate,
+		&created.StatementBalance,
+		&created.LedgerBalance,
+		&created.Difference,
+		&created.AdjustmentCashFlowID,
+		&created.Note,
+		&created.CreatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create bank account reconciliation: %w", err)
+	}
+
+	return created, nil
+}
+
+// GetByAccountID 取得銀行帳戶的對帳記錄（由新到舊）
+func (r *bankAccountReconciliationRepository) GetByAccountID(accountID uuid.UUID) ([]*models.BankAccountReconciliation, error) {
+	query := `
+		SELECT id, bank_account_id, statement_date, statement_balance, ledger_balance,
+			difference, adjustment_cash_flow_id, note, created_at
+		FROM bank_account_reconciliations
+		WHERE bank_account_id = $1
+		ORDER BY statement_date DESC, created_at DESC
+	`
+
+	rows, err := r.db.Query(query, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bank account reconciliations: %w", err)
+	}
+	defer rows.Close()
+
+	reconciliations := []*models.BankAccountReconciliation{}
+	for rows.Next() {
+		reconciliation := &models.BankAccountReconciliation{}
+		if err := rows.Scan(
+			&reconciliation.ID,
+			&reconciliation.BankAccountID,
+			&reconciliation.StatementDate,
+			&reconciliation.StatementBalance,
+			&reconciliation.LedgerBalance,
+			&reconciliation.Difference,
+			&reconciliation.AdjustmentCashFlowID,
+			&reconciliation.Note,
+			&reconciliation.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan bank account reconciliation: %w", err)
+		}
+		reconciliations = append(reconciliations, reconciliation)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating bank account reconciliations: %w", err)
+	}
+
+	return reconciliations, nil
+}
+
+// DB 回傳底層的 *sql.DB，供 service 層開啟交易使用
+func (r *bankAccountReconciliationRepository) DB() *sql.DB {
+	return r.db
+}
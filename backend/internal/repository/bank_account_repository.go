@@ -13,9 +13,12 @@ import (
 type BankAccountRepository interface {
 	Create(input *models.CreateBankAccountInput) (*models.BankAccount, error)
 	GetByID(id uuid.UUID) (*models.BankAccount, error)
+	// GetByIDForUpdateTx 在指定的資料庫交易中取得銀行帳戶並鎖定該筆資料（SELECT ... FOR UPDATE）
+	GetByIDForUpdateTx(tx *sql.Tx, id uuid.UUID) (*models.BankAccount, error)
 	GetAll(currency *models.Currency) ([]*models.BankAccount, error)
 	Update(id uuid.UUID, input *models.UpdateBankAccountInput) (*models.BankAccount, error)
 	UpdateBalance(id uuid.UUID, amount float64) (*models.BankAccount, error)
+	UpdateBalanceTx(tx *sql.Tx, id uuid.UUID, amount float64) (*models.BankAccount, error)
 	Delete(id uuid.UUID) error
 }
 
@@ -67,14 +70,25 @@ func (r *bankAccountRepository) Create(input *models.CreateBankAccountInput) (*m
 
 // GetByID 根據 ID 取得銀行帳戶
 func (r *bankAccountRepository) GetByID(id uuid.UUID) (*models.BankAccount, error) {
+	return getBankAccountByID(r.db, id, "")
+}
+
+// GetByIDForUpdateTx 在指定的資料庫交易中取得銀行帳戶並鎖定該筆資料，
+// 交易結束前其他交易無法修改餘額，確保餘額檢查與更新之間不會被並行寫入影響
+func (r *bankAccountRepository) GetByIDForUpdateTx(tx *sql.Tx, id uuid.UUID) (*models.BankAccount, error) {
+	return getBankAccountByID(tx, id, "FOR UPDATE")
+}
+
+// getBankAccountByID 根據 ID 取得銀行帳戶（GetByID 與 GetByIDForUpdateTx 共用）
+func getBankAccountByID(q queryRower, id uuid.UUID, lockClause string) (*models.BankAccount, error) {
 	query := `
 		SELECT id, bank_name, account_type, account_number_last4, currency, balance, note, created_at, updated_at
 		FROM bank_accounts
 		WHERE id = $1
-	`
+	` + lockClause
 
 	account := &models.BankAccount{}
-	err := r.db.QueryRow(query, id).Scan(
+	err := q.QueryRow(query, id).Scan(
 		&account.ID,
 		&account.BankName,
 		&account.AccountType,
@@ -231,6 +245,16 @@ func (r *bankAccountRepository) Update(id uuid.UUID, input *models.UpdateBankAcc
 
 // UpdateBalance 更新銀行帳戶餘額（增加或減少指定金額）
 func (r *bankAccountRepository) UpdateBalance(id uuid.UUID, amount float64) (*models.BankAccount, error) {
+	return updateBankAccountBalance(r.db, id, amount)
+}
+
+// UpdateBalanceTx 在指定的資料庫交易中更新銀行帳戶餘額
+func (r *bankAccountRepository) UpdateBalanceTx(tx *sql.Tx, id uuid.UUID, amount float64) (*models.BankAccount, error) {
+	return updateBankAccountBalance(tx, id, amount)
+}
+
+// updateBankAccountBalance 更新銀行帳戶餘額（UpdateBalance 與 UpdateBalanceTx 共用）
+func updateBankAccountBalance(q queryRower, id uuid.UUID, amount float64) (*models.BankAccount, error) {
 	query := `
 		UPDATE bank_accounts
 		SET balance = balance + $1, updated_at = CURRENT_TIMESTAMP
@@ -239,7 +263,7 @@ func (r *bankAccountRepository) UpdateBalance(id uuid.UUID, amount float64) (*mo
 	`
 
 	account := &models.BankAccount{}
-	err := r.db.QueryRow(query, amount, id).Scan(
+	err := q.QueryRow(query, amount, id).Scan(
 		&account.ID,
 		&account.BankName,
 		&account.AccountType,
@@ -0,0 +1,197 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/chienchuanw/asset-manager/internal/models"
+)
+
+// BillingLedgerRepository 扣款記錄與每日扣款執行記錄的資料存取介面
+type BillingLedgerRepository interface {
+	// ClaimRecordTx 在指定的資料庫交易中登記扣款記錄，同一來源同一期已登記時回傳 false
+	// 扣款失敗時交易回滾會一併移除登記，下次執行即可重試
+	ClaimRecordTx(tx *sql.Tx, record *models.BillingRecord) (bool, error)
+	// UpsertRun 建立或更新指定扣款日的執行記錄
+	UpsertRun(run *models.BillingRun) (*models.BillingRun, error)
+	// GetLastSuccessfulRun 取得最近一次成功的執行記錄，尚無記錄時回傳 nil
+	GetLastSuccessfulRun() (*models.BillingRun, error)
+	// ListRunsSince 取得指定日期（含）之後的執行記錄（依扣款日由舊到新）
+	ListRunsSince(since time.Time) ([]*models.BillingRun, error)
+	ListRuns(limit int) ([]*models.BillingRun, error)
+	DB() *sql.DB
+}
+
+// billingLedgerRepository 扣款記錄資料存取實作
+type billingLedgerRepository struct {
+	db *sql.DB
+}
+
+// NewBillingLedgerRepository 建立新的扣款記錄 repository
+func NewBillingLedgerRepository(db *sql.DB) BillingLedgerRepository {
+	return &billingLedgerRepository{db: db}
+}
+
+// billingRunColumns 執行記錄查詢欄位（與 scanBillingRun 的順序一致）
+const billingRunColumns = `
+	id, billing_date, status, processed_count, skipped_count, failed_count,
+	total_amount, error_message, created_at, updated_at`
+
+// scanBillingRun 掃描執行記錄資料（輔助函式）
+func scanBillingRun(scanner rowScanner) (*models.BillingRun, error) {
+	run := &models.BillingRun{}
+	err := scanner.Scan(
+		&run.ID,
+		&run.BillingDate,
+		&run.Status,
+		&run.ProcessedCount,
+		&run.SkippedCount,
+		&run.FailedCount,
+		&run.TotalAmount,
+		&run.ErrorMessage,
+		&run.CreatedAt,
+		&run.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+// DB 回傳底層的 *sql.DB，供 service 層開啟交易使用
+func (r *billingLedgerRepository) DB() *sql.DB {
+	return r.db
+}
+
+// ClaimRecordTx 在指定的資料庫交易中登記扣款記錄
+func (r *billingLedgerRepository) ClaimRecordTx(tx *sql.Tx, record *models.BillingRecord) (bool, error) {
+	query := `
+		INSERT INTO billing_records (source_type, source_id, period, billing_date, amount)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (source_type, source_id, period) DO NOTHING
+		RETURNING id, created_at
+	`
+
+	err := tx.QueryRow(
+		query,
+		record.SourceType,
+		record.SourceID,
+		record.Period,
+		record.BillingDate,
+		record.Amount,
+	).Scan(&record.ID, &record.CreatedAt)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to claim billing record: %w", err)
+	}
+
+	return true, nil
+}
+
+// UpsertRun 建立或更新執行記錄
+func (r *billingLedgerRepository) UpsertRun(run *models.BillingRun) (*models.BillingRun, error) {
+	query := `
+		INSERT INTO billing_runs (
+			billing_date, status, processed_count, skipped_count, failed_count, total_amount, error_message
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (billing_date) DO UPDATE SET
+			status = EXCLUDED.status,
+			processed_count = billing_runs.processed_count + EXCLUDED.processed_count,
+			skipped_count = EXCLUDED.skipped_count,
+			failed_count = EXCLUDED.failed_count,
+			total_amount = billing_runs.total_amount + EXCLUDED.total_amount,
+			error_message = EXCLUDED.error_message
+		RETURNING ` + billingRunColumns
+
+	saved, err := scanBillingRun(r.db.QueryRow(
+		query,
+		run.BillingDate,
+		run.Status,
+		run.ProcessedCount,
+		run.SkippedCount,
+		run.FailedCount,
+		run.TotalAmount,
+		run.ErrorMessage,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to upsert billing run: %w", err)
+	}
+
+	return saved, nil
+}
+
+// GetLastSuccessfulRun 取得最近一次成功的執行記錄
+func (r *billingLedgerRepository) GetLastSuccessfulRun() (*models.BillingRun, error) {
+	query := `SELECT ` + billingRunColumns + `
+		FROM billing_runs
+		WHERE status = $1
+		ORDER BY billing_date DESC
+		LIMIT 1
+	`
+
+	run, err := scanBillingRun(r.db.QueryRow(query, models.BillingRunStatusSuccess))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get last successful billing run: %w", err)
+	}
+
+	return run, nil
+}
+
+// ListRunsSince 取得指定日期（含）之後的執行記錄（依扣款日由舊到新）
+func (r *billingLedgerRepository) ListRunsSince(since time.Time) ([]*models.BillingRun, error) {
+	query := `SELECT ` + billingRunColumns + `
+		FROM billing_runs
+		WHERE billing_date >= $1
+		ORDER BY billing_date ASC
+	`
+
+	rows, err := r.db.Query(query, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list billing runs: %w", err)
+	}
+	defer rows.Close()
+
+	return scanBillingRuns(rows)
+}
+
+// ListRuns 取得最近的執行記錄（依扣款日由新到舊）
+func (r *billingLedgerRepository) ListRuns(limit int) ([]*models.BillingRun, error) {
+	query := `SELECT ` + billingRunColumns + `
+		FROM billing_runs
+		ORDER BY billing_date DESC
+		LIMIT $1
+	`
+
+	rows, err := r.db.Query(query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list billing runs: %w", err)
+	}
+	defer rows.Close()
+
+	return scanBillingRuns(rows)
+}
+
+// scanBillingRuns 掃描多筆執行記錄（輔助函式）
+func scanBillingRuns(rows *sql.Rows) ([]*models.BillingRun, error) {
+	runs := []*models.BillingRun{}
+	for rows.Next() {
+		run, err := scanBillingRun(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan billing run: %w", err)
+		}
+		runs = append(runs, run)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating billing runs: %w", err)
+	}
+
+	return runs, nil
+}
@@ -0,0 +1,344 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/chienchuanw/asset-manager/internal/models"
+	"github.com/google/uuid"
+)
+
+// BrokerageAccountRepository 券商帳戶資料存取介面
+type BrokerageAccountRepository interface {
+	DB() *sql.DB
+	Create(input *models.CreateBrokerageAccountInput) (*models.BrokerageAccount, error)
+	GetByID(id uuid.UUID) (*models.BrokerageAccount, error)
+	GetAll() ([]*models.BrokerageAccount, error)
+	Update(id uuid.UUID, input *models.UpdateBrokerageAccountInput) (*models.BrokerageAccount, error)
+	Delete(id uuid.UUID) error
+	UpdateCashBalance(id uuid.UUID, amount float64) (*models.BrokerageAccount, error)
+	UpdateCashBalanceTx(tx *sql.Tx, id uuid.UUID, amount float64) (*models.BrokerageAccount, error)
+	CreateMovementTx(tx *sql.Tx, movement *models.BrokerageCashMovement) (*models.BrokerageCashMovement, error)
+	GetMovements(accountID uuid.UUID) ([]*models.BrokerageCashMovement, error)
+	GetMovementsByTransactionID(transactionID uuid.UUID) ([]*models.BrokerageCashMovement, error)
+	DeleteMovementTx(tx *sql.Tx, id uuid.UUID) error
+}
+
+// brokerageAccountRepository 券商帳戶資料存取實作
+type brokerageAccountRepository struct {
+	db *sql.DB
+}
+
+// NewBrokerageAccountRepository 建立新的券商帳戶 repository
+func NewBrokerageAccountRepository(db *sql.DB) BrokerageAccountRepository {
+	return &brokerageAccountRepository{db: db}
+}
+
+// brokerageAccountColumns 券商帳戶查詢欄位
+const brokerageAccountColumns = `id, name, broker, currency, cash_balance, settlement_account_id, note, created_at, updated_at`
+
+// brokerageCashMovementColumns 交割現金異動查詢欄位
+const brokerageCashMovementColumns = `id, brokerage_account_id, transaction_id, type, amount,
+	trade_date, settlement_date, bank_account_id, cash_flow_id, created_at`
+
+// scanBrokerageAccount 掃描券商帳戶資料（輔助函式）
+func scanBrokerageAccount(scanner rowScanner) (*models.BrokerageAccount, error) {
+	account := &models.BrokerageAccount{}
+	err := scanner.Scan(
+		&account.ID,
+		&account.Name,
+		&account.Broker,
+		&account.Currency,
+		&account.CashBalance,
+		&account.SettlementAccountID,
+		&account.Note,
+		&account.CreatedAt,
+		&account.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+// scanBrokerageCashMovement 掃描交割現金異動資料（輔助函式）
+func scanBrokerageCashMovement(scanner rowScanner) (*models.BrokerageCashMovement, error) {
+	movement := &models.BrokerageCashMovement{}
+	err := scanner.Scan(
+		&movement.ID,
+		&movement.BrokerageAccountID,
+		&movement.TransactionID,
+		&movement.Type,
+		&movement.Amount,
+		&movement.TradeDate,
+		&movement.SettlementDate,
+		&movement.BankAccountID,
+		&movement.CashFlowID,
+		&movement.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return movement, nil
+}
+
+// DB 取得資料庫連線（供 service 開啟交易使用）
+func (r *brokerageAccountRepository) DB() *sql.DB {
+	return r.db
+}
+
+// Create 建立新的券商帳戶
+func (r *brokerageAccountRepository) Create(input *models.CreateBrokerageAccountInput) (*models.BrokerageAccount, error) {
+	query := `
+		INSERT INTO brokerage_accounts (name, broker, currency, cash_balance, settlement_account_id, note)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING ` + brokerageAccountColumns
+
+	account, err := scanBrokerageAccount(r.db.QueryRow(
+		query,
+		input.Name,
+		input.Broker,
+		input.Currency,
+		input.CashBalance,
+		input.SettlementAccountID,
+		input.Note,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create brokerage account: %w", err)
+	}
+
+	return account, nil
+}
+
+// GetByID 根據 ID 取得券商帳戶
+func (r *brokerageAccountRepository) GetByID(id uuid.UUID) (*models.BrokerageAccount, error) {
+	query := `SELECT ` + brokerageAccountColumns + ` FROM brokerage_accounts WHERE id = $1`
+
+	account, err := scanBrokerageAccount(r.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("brokerage account not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get brokerage account: %w", err)
+	}
+
+	return account, nil
+}
+
+// GetAll 取得所有券商帳戶
+func (r *brokerageAccountRepository) GetAll() ([]*models.BrokerageAccount, error) {
+	query := `SELECT ` + brokerageAccountColumns + ` FROM brokerage_accounts ORDER BY created_at DESC`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get brokerage accounts: %w", err)
+	}
+	defer rows.Close()
+
+	accounts := []*models.BrokerageAccount{}
+	for rows.Next() {
+		account, err := scanBrokerageAccount(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan brokerage account: %w", err)
+		}
+		accounts = append(accounts, account)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating brokerage accounts: %w", err)
+	}
+
+	return accounts, nil
+}
+
+// Update 更新券商帳戶
+func (r *brokerageAccountRepository) Update(id uuid.UUID, input *models.UpdateBrokerageAccountInput) (*models.BrokerageAccount, error) {
+	// 動態建立 UPDATE 語句
+	var setClauses []string
+	var args []interface{}
+	argPosition := 1
+
+	if input.Name != nil {
+		setClauses = append(setClauses, fmt.Sprintf("name = $%d", argPosition))
+		args = append(args, *input.Name)
+		argPosition++
+	}
+	if input.Broker != nil {
+		setClauses = append(setClauses, fmt.Sprintf("broker = $%d", argPosition))
+		args = append(args, *input.Broker)
+		argPosition++
+	}
+	if input.CashBalance != nil {
+		setClauses = append(setClauses, fmt.Sprintf("cash_balance = $%d", argPosition))
+		args = append(args, *input.CashBalance)
+		argPosition++
+	}
+	if input.SettlementAccountID != nil {
+		setClauses = append(setClauses, fmt.Sprintf("settlement_account_id = $%d", argPosition))
+		args = append(args, *input.SettlementAccountID)
+		argPosition++
+	}
+	if input.Note != nil {
+		setClauses = append(setClauses, fmt.Sprintf("note = $%d", argPosition))
+		args = append(args, *input.Note)
+		argPosition++
+	}
+
+	// 如果沒有任何欄位需要更新，直接返回現有資料
+	if len(setClauses) == 0 {
+		return r.GetByID(id)
+	}
+
+	args = append(args, id)
+
+	query := fmt.Sprintf(`
+		UPDATE brokerage_accounts
+		SET %s
+		WHERE id = $%d
+		RETURNING %s
+	`, strings.Join(setClauses, ", "), argPosition, brokerageAccountColumns)
+
+	account, err := scanBrokerageAccount(r.db.QueryRow(query, args...))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("brokerage account not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update brokerage account: %w", err)
+	}
+
+	return account, nil
+}
+
+// Delete 刪除券商帳戶
+func (r *brokerageAccountRepository) Delete(id uuid.UUID) error {
+	result, err := r.db.Exec(`DELETE FROM brokerage_accounts WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete brokerage account: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("brokerage account not found")
+	}
+
+	return nil
+}
+
+// UpdateCashBalance 更新券商現金餘額（增加或減少指定金額）
+func (r *brokerageAccountRepository) UpdateCashBalance(id uuid.UUID, amount float64) (*models.BrokerageAccount, error) {
+	return updateBrokerageCashBalance(r.db, id, amount)
+}
+
+// UpdateCashBalanceTx 在指定的資料庫交易中更新券商現金餘額
+func (r *brokerageAccountRepository) UpdateCashBalanceTx(tx *sql.Tx, id uuid.UUID, amount float64) (*models.BrokerageAccount, error) {
+	return updateBrokerageCashBalance(tx, id, amount)
+}
+
+// updateBrokerageCashBalance 更新券商現金餘額（UpdateCashBalance 與 UpdateCashBalanceTx 共用）
+func updateBrokerageCashBalance(q queryRower, id uuid.UUID, amount float64) (*models.BrokerageAccount, error) {
+	query := `
+		UPDATE brokerage_accounts
+		SET cash_balance = cash_balance + $1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $2
+		RETURNING ` + brokerageAccountColumns
+
+	account, err := scanBrokerageAccount(q.QueryRow(query, amount, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("brokerage account not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update brokerage cash balance: %w", err)
+	}
+
+	return account, nil
+}
+
+// CreateMovementTx 在指定的資料庫交易中建立交割現金異動
+func (r *brokerageAccountRepository) CreateMovementTx(tx *sql.Tx, movement *models.BrokerageCashMovement) (*models.BrokerageCashMovement, error) {
+	query := `
+		INSERT INTO brokerage_cash_movements (
+			brokerage_account_id, transaction_id, type, amount,
+			trade_date, settlement_date, bank_account_id, cash_flow_id
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at
+	`
+
+	created := *movement
+	err := tx.QueryRow(
+		query,
+		movement.BrokerageAccountID,
+		movement.TransactionID,
+		movement.Type,
+		movement.Amount,
+		movement.TradeDate,
+		movement.SettlementDate,
+		movement.BankAccountID,
+		movement.CashFlowID,
+	).Scan(&created.ID, &created.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create brokerage cash movement: %w", err)
+	}
+
+	return &created, nil
+}
+
+// GetMovements 取得券商帳戶的交割現金異動（依交割日降序）
+func (r *brokerageAccountRepository) GetMovements(accountID uuid.UUID) ([]*models.BrokerageCashMovement, error) {
+	query := `
+		SELECT ` + brokerageCashMovementColumns + `
+		FROM brokerage_cash_movements
+		WHERE brokerage_account_id = $1
+		ORDER BY settlement_date DESC, created_at DESC
+	`
+	return r.queryMovements(query, accountID)
+}
+
+// GetMovementsByTransactionID 取得交易產生的交割現金異動
+func (r *brokerageAccountRepository) GetMovementsByTransactionID(transactionID uuid.UUID) ([]*models.BrokerageCashMovement, error) {
+	query := `
+		SELECT ` + brokerageCashMovementColumns + `
+		FROM brokerage_cash_movements
+		WHERE transaction_id = $1
+		ORDER BY created_at
+	`
+	return r.queryMovements(query, transactionID)
+}
+
+// queryMovements 查詢交割現金異動（輔助函式）
+func (r *brokerageAccountRepository) queryMovements(query string, args ...interface{}) ([]*models.BrokerageCashMovement, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get brokerage cash movements: %w", err)
+	}
+	defer rows.Close()
+
+	movements := []*models.BrokerageCashMovement{}
+	for rows.Next() {
+		movement, err := scanBrokerageCashMovement(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan brokerage cash movement: %w", err)
+		}
+		movements = append(movements, movement)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating brokerage cash movements: %w", err)
+	}
+
+	return movements, nil
+}
+
+// DeleteMovementTx 在指定的資料庫交易中刪除交割現金異動
+func (r *brokerageAccountRepository) DeleteMovementTx(tx *sql.Tx, id uuid.UUID) error {
+	_, err := tx.Exec(`DELETE FROM brokerage_cash_movements WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete brokerage cash movement: %w", err)
+	}
+	return nil
+}
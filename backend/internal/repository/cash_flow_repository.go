@@ -13,10 +13,12 @@ import (
 // CashFlowRepository 現金流記錄資料存取介面
 type CashFlowRepository interface {
 	Create(input *models.CreateCashFlowInput) (*models.CashFlow, error)
+	CreateTx(tx *sql.Tx, input *models.CreateCashFlowInput) (*models.CashFlow, error)
 	GetByID(id uuid.UUID) (*models.CashFlow, error)
 	GetAll(filters CashFlowFilters) ([]*models.CashFlow, error)
 	Update(id uuid.UUID, input *models.UpdateCashFlowInput) (*models.CashFlow, error)
 	Delete(id uuid.UUID) error
+	DeleteTx(tx *sql.Tx, id uuid.UUID) error
 	GetSummary(startDate, endDate time.Time) (*CashFlowSummary, error)
 	GetMonthlySummary(year, month int) (*models.MonthlyCashFlowSummary, error)
 	GetYearlySummary(year int) (*models.YearlyCashFlowSummary, error)
@@ -26,12 +28,14 @@ type CashFlowRepository interface {
 
 // CashFlowFilters 現金流查詢篩選條件
 type CashFlowFilters struct {
-	Type       *models.CashFlowType `json:"type,omitempty"`
-	CategoryID *uuid.UUID           `json:"category_id,omitempty"`
-	StartDate  *time.Time           `json:"start_date,omitempty"`
-	EndDate    *time.Time           `json:"end_date,omitempty"`
-	Limit      int                  `json:"limit,omitempty"`
-	Offset     int                  `json:"offset,omitempty"`
+	Type          *models.CashFlowType `json:"type,omitempty"`
+	CategoryID    *uuid.UUID           `json:"category_id,omitempty"`
+	StartDate     *time.Time           `json:"start_date,omitempty"`
+	EndDate       *time.Time           `json:"end_date,omitempty"`
+	BankAccountID *uuid.UUID           `json:"bank_account_id,omitempty"` // 影響指定銀行帳戶的記錄（付款來源或轉帳目標）
+	CreditCardID  *uuid.UUID           `json:"credit_card_id,omitempty"`  // 影響指定信用卡的記錄（付款來源或繳款目標）
+	Limit         int                  `json:"limit,omitempty"`
+	Offset        int                  `json:"offset,omitempty"`
 }
 
 // CashFlowSummary 現金流摘要
@@ -53,6 +57,16 @@ func NewCashFlowRepository(db *sql.DB) CashFlowRepository {
 
 // Create 建立新的現金流記錄
 func (r *cashFlowRepository) Create(input *models.CreateCashFlowInput) (*models.CashFlow, error) {
+	return createCashFlow(r.db, input)
+}
+
+// CreateTx 在指定的資料庫交易中建立新的現金流記錄
+func (r *cashFlowRepository) CreateTx(tx *sql.Tx, input *models.CreateCashFlowInput) (*models.CashFlow, error) {
+	return createCashFlow(tx, input)
+}
+
+// createCashFlow 建立現金流記錄（Create 與 CreateTx 共用）
+func createCashFlow(q queryRower, input *models.CreateCashFlowInput) (*models.CashFlow, error) {
 	query := `
 		INSERT INTO cash_flows (date, type, category_id, amount, currency, description, note, source_type, source_id, target_type, target_id)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
@@ -60,7 +74,7 @@ func (r *cashFlowRepository) Create(input *models.CreateCashFlowInput) (*models.
 	`
 
 	cashFlow := &models.CashFlow{}
-	err := r.db.QueryRow(
+	err := q.QueryRow(
 		query,
 		input.Date,
 		input.Type,
@@ -186,6 +200,18 @@ func (r *cashFlowRepository) GetAll(filters CashFlowFilters) ([]*models.CashFlow
 		argCount++
 	}
 
+	if filters.BankAccountID != nil {
+		query += fmt.Sprintf(" AND ((cf.source_type = 'bank_account' AND cf.source_id = $%d) OR (cf.target_type = 'bank_account' AND cf.target_id = $%d))", argCount, argCount)
+		args = append(args, *filters.BankAccountID)
+		argCount++
+	}
+
+	if filters.CreditCardID != nil {
+		query += fmt.Sprintf(" AND ((cf.source_type = 'credit_card' AND cf.source_id = $%d) OR (cf.target_type = 'credit_card' AND cf.target_id = $%d))", argCount, argCount)
+		args = append(args, *filters.CreditCardID)
+		argCount++
+	}
+
 	// 排序
 	query += " ORDER BY cf.date DESC, cf.created_at DESC"
 
@@ -339,9 +365,24 @@ func (r *cashFlowRepository) Update(id uuid.UUID, input *models.UpdateCashFlowIn
 
 // Delete 刪除現金流記錄
 func (r *cashFlowRepository) Delete(id uuid.UUID) error {
+	return deleteCashFlow(r.db, id)
+}
+
+// DeleteTx 在指定的資料庫交易中刪除現金流記錄
+func (r *cashFlowRepository) DeleteTx(tx *sql.Tx, id uuid.UUID) error {
+	return deleteCashFlow(tx, id)
+}
+
+// execer 可執行 SQL 的 *sql.DB 或 *sql.Tx
+type execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// deleteCashFlow 刪除現金流記錄（Delete 與 DeleteTx 共用）
+func deleteCashFlow(e execer, id uuid.UUID) error {
 	query := `DELETE FROM cash_flows WHERE id = $1`
 
-	result, err := r.db.Exec(query, id)
+	result, err := e.Exec(query, id)
 	if err != nil {
 		return fmt.Errorf("failed to delete cash flow: %w", err)
 	}
@@ -14,6 +14,8 @@ import (
 type CreditCardRepository interface {
 	Create(input *models.CreateCreditCardInput) (*models.CreditCard, error)
 	GetByID(id uuid.UUID) (*models.CreditCard, error)
+	// GetByIDForUpdateTx 在指定的資料庫交易中取得信用卡並鎖定該筆資料（SELECT ... FOR UPDATE）
+	GetByIDForUpdateTx(tx *sql.Tx, id uuid.UUID) (*models.CreditCard, error)
 	GetAll() ([]*models.CreditCard, error)
 	GetByBillingDay(day int) ([]*models.CreditCard, error)
 	GetByPaymentDueDay(day int) ([]*models.CreditCard, error)
@@ -21,6 +23,7 @@ type CreditCardRepository interface {
 	GetUpcomingPayment(daysAhead int) ([]*models.CreditCard, error)
 	Update(id uuid.UUID, input *models.UpdateCreditCardInput) (*models.CreditCard, error)
 	UpdateUsedCredit(id uuid.UUID, amount float64) (*models.CreditCard, error)
+	UpdateUsedCreditTx(tx *sql.Tx, id uuid.UUID, amount float64) (*models.CreditCard, error)
 	Delete(id uuid.UUID) error
 }
 
@@ -77,14 +80,25 @@ func (r *creditCardRepository) Create(input *models.CreateCreditCardInput) (*mod
 
 // GetByID 根據 ID 取得信用卡
 func (r *creditCardRepository) GetByID(id uuid.UUID) (*models.CreditCard, error) {
+	return getCreditCardByID(r.db, id, "")
+}
+
+// GetByIDForUpdateTx 在指定的資料庫交易中取得信用卡並鎖定該筆資料，
+// 交易結束前其他交易無法修改已使用額度，確保額度檢查與更新之間不會被並行寫入影響
+func (r *creditCardRepository) GetByIDForUpdateTx(tx *sql.Tx, id uuid.UUID) (*models.CreditCard, error) {
+	return getCreditCardByID(tx, id, "FOR UPDATE")
+}
+
+// getCreditCardByID 根據 ID 取得信用卡（GetByID 與 GetByIDForUpdateTx 共用）
+func getCreditCardByID(q queryRower, id uuid.UUID, lockClause string) (*models.CreditCard, error) {
 	query := `
 		SELECT id, issuing_bank, card_name, card_number_last4, billing_day, payment_due_day, credit_limit, used_credit, group_id, note, created_at, updated_at
 		FROM credit_cards
 		WHERE id = $1
-	`
+	` + lockClause
 
 	card := &models.CreditCard{}
-	err := r.db.QueryRow(query, id).Scan(
+	err := q.QueryRow(query, id).Scan(
 		&card.ID,
 		&card.IssuingBank,
 		&card.CardName,
@@ -468,6 +482,16 @@ func (r *creditCardRepository) Update(id uuid.UUID, input *models.UpdateCreditCa
 
 // UpdateUsedCredit 更新信用卡已使用額度（增加或減少指定金額）
 func (r *creditCardRepository) UpdateUsedCredit(id uuid.UUID, amount float64) (*models.CreditCard, error) {
+	return updateCreditCardUsedCredit(r.db, id, amount)
+}
+
+// UpdateUsedCreditTx 在指定的資料庫交易中更新信用卡已使用額度
+func (r *creditCardRepository) UpdateUsedCreditTx(tx *sql.Tx, id uuid.UUID, amount float64) (*models.CreditCard, error) {
+	return updateCreditCardUsedCredit(tx, id, amount)
+}
+
+// updateCreditCardUsedCredit 更新信用卡已使用額度（UpdateUsedCredit 與 UpdateUsedCreditTx 共用）
+func updateCreditCardUsedCredit(q queryRower, id uuid.UUID, amount float64) (*models.CreditCard, error) {
 	query := `
 		UPDATE credit_cards
 		SET used_credit = used_credit + $1, updated_at = CURRENT_TIMESTAMP
@@ -476,7 +500,7 @@ func (r *creditCardRepository) UpdateUsedCredit(id uuid.UUID, amount float64) (*
 	`
 
 	card := &models.CreditCard{}
-	err := r.db.QueryRow(query, amount, id).Scan(
+	err := q.QueryRow(query, amount, id).Scan(
 		&card.ID,
 		&card.IssuingBank,
 		&card.CardName,
@@ -0,0 +1,224 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/chienchuanw/asset-manager/internal/models"
+	"github.com/google/uuid"
+)
+
+// CreditCardRewardRuleRepository 信用卡回饋規則資料存取介面
+type CreditCardRewardRuleRepository interface {
+	Create(creditCardID uuid.UUID, input *models.CreateCreditCardRewardRuleInput) (*models.CreditCardRewardRule, error)
+	GetByID(id uuid.UUID) (*models.CreditCardRewardRule, error)
+	GetByCreditCardID(creditCardID uuid.UUID) ([]*models.CreditCardRewardRule, error)
+	GetAll() ([]*models.CreditCardRewardRule, error)
+	Update(id uuid.UUID, input *models.UpdateCreditCardRewardRuleInput) (*models.CreditCardRewardRule, error)
+	Delete(id uuid.UUID) error
+}
+
+// creditCardRewardRuleRepository 信用卡回饋規則資料存取實作
+type creditCardRewardRuleRepository struct {
+	db *sql.DB
+}
+
+// NewCreditCardRewardRuleRepository 建立新的信用卡回饋規則 repository
+func NewCreditCardRewardRuleRepository(db *sql.DB) CreditCardRewardRuleRepository {
+	return &creditCardRewardRuleRepository{db: db}
+}
+
+// creditCardRewardRuleColumns 回饋規則查詢欄位（與 scanCreditCardRewardRule 的順序一致）
+const creditCardRewardRuleColumns = `
+	id, credit_card_id, name, category_id, merchant_keyword, rate, cycle_cap,
+	is_active, created_at, updated_at`
+
+// scanCreditCardRewardRule 掃描回饋規則資料（輔助函式）
+func scanCreditCardRewardRule(scanner rowScanner) (*models.CreditCardRewardRule, error) {
+	rule := &models.CreditCardRewardRule{}
+	err := scanner.Scan(
+		&rule.ID,
+		&rule.CreditCardID,
+		&rule.Name,
+		&rule.CategoryID,
+		&rule.MerchantKeyword,
+		&rule.Rate,
+		&rule.CycleCap,
+		&rule.IsActive,
+		&rule.CreatedAt,
+		&rule.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// Create 建立新的回饋規則
+func (r *creditCardRewardRuleRepository) Create(creditCardID uuid.UUID, input *models.CreateCreditCardRewardRuleInput) (*models.CreditCardRewardRule, error) {
+	query := `
+		INSERT INTO credit_card_reward_rules (
+			credit_card_id, name, category_id, merchant_keyword, rate, cycle_cap
+		)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING ` + creditCardRewardRuleColumns
+
+	rule, err := scanCreditCardRewardRule(r.db.QueryRow(
+		query,
+		creditCardID,
+		input.Name,
+		input.CategoryID,
+		input.MerchantKeyword,
+		input.Rate,
+		input.CycleCap,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create credit card reward rule: %w", err)
+	}
+
+	return rule, nil
+}
+
+// GetByID 根據 ID 取得回饋規則
+func (r *creditCardRewardRuleRepository) GetByID(id uuid.UUID) (*models.CreditCardRewardRule, error) {
+	query := `SELECT ` + creditCardRewardRuleColumns + ` FROM credit_card_reward_rules WHERE id = $1`
+
+	rule, err := scanCreditCardRewardRule(r.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("credit card reward rule not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get credit card reward rule: %w", err)
+	}
+
+	return rule, nil
+}
+
+// GetByCreditCardID 取得信用卡的所有回饋規則
+func (r *creditCardRewardRuleRepository) GetByCreditCardID(creditCardID uuid.UUID) ([]*models.CreditCardRewardRule, error) {
+	query := `SELECT ` + creditCardRewardRuleColumns + `
+		FROM credit_card_reward_rules
+		WHERE credit_card_id = $1
+		ORDER BY rate DESC, name
+	`
+
+	return r.queryRules(query, creditCardID)
+}
+
+// GetAll 取得所有信用卡的回饋規則
+func (r *creditCardRewardRuleRepository) GetAll() ([]*models.CreditCardRewardRule, error) {
+	query := `SELECT ` + creditCardRewardRuleColumns + `
+		FROM credit_card_reward_rules
+		ORDER BY credit_card_id, rate DESC, name
+	`
+
+	return r.queryRules(query)
+}
+
+// queryRules 執行查詢並掃描回饋規則列表
+func (r *creditCardRewardRuleRepository) queryRules(query string, args ...interface{}) ([]*models.CreditCardRewardRule, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get credit card reward rules: %w", err)
+	}
+	defer rows.Close()
+
+	rules := []*models.CreditCardRewardRule{}
+	for rows.Next() {
+		rule, err := scanCreditCardRewardRule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan credit card reward rule: %w", err)
+		}
+		rules = append(rules, rule)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating credit card reward rules: %w", err)
+	}
+
+	return rules, nil
+}
+
+// Update 更新回饋規則
+func (r *creditCardRewardRuleRepository) Update(id uuid.UUID, input *models.UpdateCreditCardRewardRuleInput) (*models.CreditCardRewardRule, error) {
+	updates := []string{}
+	args := []interface{}{}
+	argCount := 1
+
+	if input.Name != nil {
+		updates = append(updates, fmt.Sprintf("name = $%d", argCount))
+		args = append(args, *input.Name)
+		argCount++
+	}
+
+	if input.CategoryID != nil {
+		updates = append(updates, fmt.Sprintf("category_id = $%d", argCount))
+		args = append(args, *input.CategoryID)
+		argCount++
+	}
+
+	if input.MerchantKeyword != nil {
+		updates = append(updates, fmt.Sprintf("merchant_keyword = $%d", argCount))
+		args = append(args, *input.MerchantKeyword)
+		argCount++
+	}
+
+	if input.Rate != nil {
+		updates = append(updates, fmt.Sprintf("rate = $%d", argCount))
+		args = append(args, *input.Rate)
+		argCount++
+	}
+
+	if input.CycleCap != nil {
+		updates = append(updates, fmt.Sprintf("cycle_cap = $%d", argCount))
+		args = append(args, *input.CycleCap)
+		argCount++
+	}
+
+	if input.IsActive != nil {
+		updates = append(updates, fmt.Sprintf("is_active = $%d", argCount))
+		args = append(args, *input.IsActive)
+		argCount++
+	}
+
+	if len(updates) == 0 {
+		return r.GetByID(id)
+	}
+
+	args = append(args, id)
+
+	query := fmt.Sprintf(`
+		UPDATE credit_card_reward_rules
+		SET %s, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $%d
+		RETURNING %s
+	`, strings.Join(updates, ", "), argCount, creditCardRewardRuleColumns)
+
+	rule, err := scanCreditCardRewardRule(r.db.QueryRow(query, args...))
+	if err != nil {
+		return nil, fmt.Errorf("failed to update credit card reward rule: %w", err)
+	}
+
+	return rule, nil
+}
+
+// Delete 刪除回饋規則
+func (r *creditCardRewardRuleRepository) Delete(id uuid.UUID) error {
+	query := `DELETE FROM credit_card_reward_rules WHERE id = $1`
+	result, err := r.db.Exec(query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete credit card reward rule: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("credit card reward rule not found")
+	}
+
+	return nil
+}
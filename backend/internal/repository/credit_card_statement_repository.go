@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/chienchuanw/asset-manager/internal/models"
+	"github.com/google/uuid"
+)
+
+// CreditCardStatementRepository 信用卡帳單資料存取介面
+type CreditCardStatementRepository interface {
+	// Upsert 建立或更新帳單（以信用卡與結帳日為唯一鍵）
+	Upsert(statement *models.CreditCardStatement) (*models.CreditCardStatement, error)
+	GetByID(id uuid.UUID) (*models.CreditCardStatement, error)
+	GetByCreditCardID(creditCardID uuid.UUID) ([]*models.CreditCardStatement, error)
+}
+
+// creditCardStatementRepository 信用卡帳單資料存取實作
+type creditCardStatementRepository struct {
+	db *sql.DB
+}
+
+// NewCreditCardStatementRepository 建立新的信用卡帳單 repository
+func NewCreditCardStatementRepository(db *sql.DB) CreditCardStatementRepository {
+	return &creditCardStatementRepository{db: db}
+}
+
+// creditCardStatementColumns 查詢帳單時使用的欄位
+const creditCardStatementColumns = `
+	id, credit_card_id, period_start, closing_date, due_date,
+	previous_balance, new_charges, payments_and_credits, closing_balance,
+	minimum_payment, paid_amount, status, created_at, updated_at
+`
+
+// scanCreditCardStatement 掃描單筆帳單資料
+func scanCreditCardStatement(row rowScanner) (*models.CreditCardStatement, error) {
+	statement := &models.CreditCardStatement{}
+	err := row.Scan(
+		&statement.ID,
+		&statement.CreditCardID,
+		&statement.PeriodStart,
+		&statement.ClosingDate,
+		&statement.DueDate,
+		&statement.PreviousBalance,
+		&statement.NewCharges,
+		&statement.PaymentsAndCredits,
+		&statement.ClosingBalance,
+		&statement.MinimumPayment,
+		&statement.PaidAmount,
+		&statement.Status,
+		&statement.CreatedAt,
+		&statement.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return statement, nil
+}
+
+// Upsert 建立或更新帳單
+func (r *creditCardStatementRepository) Upsert(statement *models.CreditCardStatement) (*models.CreditCardStatement, error) {
+	query := `
+		INSERT INTO credit_card_statements (
+			credit_card_id, period_start, closing_date, due_date,
+			previous_balance, new_charges, payments_and_credits, closing_balance,
+			minimum_payment, paid_amount, status
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (credit_card_id, closing_date) DO UPDATE SET
+			period_start = EXCLUDED.period_start,
+			due_date = EXCLUDED.due_date,
+			previous_balance = EXCLUDED.previous_balance,
+			new_charges = EXCLUDED.new_charges,
+			payments_and_credits = EXCLUDED.payments_and_credits,
+			closing_balance = EXCLUDED.closing_balance,
+			minimum_payment = EXCLUDED.minimum_payment,
+			paid_amount = EXCLUDED.paid_amount,
+			status = EXCLUDED.status
+		RETURNING ` + creditCardStatementColumns
+
+	saved, err := scanCreditCardStatement(r.db.QueryRow(
+		query,
+		statement.CreditCardID,
+		statement.PeriodStart,
+		statement.ClosingDate,
+		statement.DueDate,
+		statement.PreviousBalance,
+		statement.NewCharges,
+		statement.PaymentsAndCredits,
+		statement.ClosingBalance,
+		statement.MinimumPayment,
+		statement.PaidAmount,
+		statement.Status,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to upsert credit card statement: %w", err)
+	}
+
+	return saved, nil
+}
+
+// GetByID 根據 ID 取得帳單
+func (r *creditCardStatementRepository) GetByID(id uuid.UUID) (*models.CreditCardStatement, error) {
+	query := `SELECT ` + creditCardStatementColumns + ` FROM credit_card_statements WHERE id = $1`
+
+	statement, err := scanCreditCardStatement(r.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("credit card statement not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get credit card statement: %w", err)
+	}
+
+	return statement, nil
+}
+
+// GetByCreditCardID 取得信用卡的所有帳單（依結帳日由新到舊）
+func (r *creditCardStatementRepository) GetByCreditCardID(creditCardID uuid.UUID) ([]*models.CreditCardStatement, error) {
+	query := `SELECT ` + creditCardStatementColumns + `
+		FROM credit_card_statements
+		WHERE credit_card_id = $1
+		ORDER BY closing_date DESC
+	`
+
+	rows, err := r.db.Query(query, creditCardID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get credit card statements: %w", err)
+	}
+	defer rows.Close()
+
+	statements := []*models.CreditCardStatement{}
+	for rows.Next() {
+		statement, err := scanCreditCardStatement(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan credit card statement: %w", err)
+		}
+		statements = append(statements, statement)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating credit card statements: %w", err)
+	}
+
+	return statements, nil
+}
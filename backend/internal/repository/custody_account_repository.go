@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/chienchuanw/asset-manager/internal/models"
+	"github.com/google/uuid"
+)
+
+// CustodyAccountRepository 加密貨幣保管帳戶資料存取介面
+type CustodyAccountRepository interface {
+	Create(input *models.CreateCustodyAccountInput) (*models.CustodyAccount, error)
+	GetByID(id uuid.UUID) (*models.CustodyAccount, error)
+	GetAll() ([]*models.CustodyAccount, error)
+	Update(id uuid.UUID, input *models.UpdateCustodyAccountInput) (*models.CustodyAccount, error)
+	Delete(id uuid.UUID) error
+}
+
+// custodyAccountRepository 加密貨幣保管帳戶資料存取實作
+type custodyAccountRepository struct {
+	db *sql.DB
+}
+
+// NewCustodyAccountRepository 建立新的保管帳戶 repository
+func NewCustodyAccountRepository(db *sql.DB) CustodyAccountRepository {
+	return &custodyAccountRepository{db: db}
+}
+
+// custodyAccountColumns 保管帳戶查詢欄位
+const custodyAccountColumns = `id, name, custody_type, provider, note, created_at, updated_at`
+
+// scanCustodyAccount 掃描保管帳戶資料（輔助函式）
+func scanCustodyAccount(scanner rowScanner) (*models.CustodyAccount, error) {
+	account := &models.CustodyAccount{}
+	err := scanner.Scan(
+		&account.ID,
+		&account.Name,
+		&account.CustodyType,
+		&account.Provider,
+		&account.Note,
+		&account.CreatedAt,
+		&account.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+// Create 建立新的保管帳戶
+func (r *custodyAccountRepository) Create(input *models.CreateCustodyAccountInput) (*models.CustodyAccount, error) {
+	query := `
+		INSERT INTO custody_accounts (name, custody_type, provider, note)
+		VALUES ($1, $2, $3, $4)
+		RETURNING ` + custodyAccountColumns
+
+	account, err := scanCustodyAccount(r.db.QueryRow(query, input.Name, input.CustodyType, input.Provider, input.Note))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create custody account: %w", err)
+	}
+
+	return account, nil
+}
+
+// GetByID 根據 ID 取得保管帳戶
+func (r *custodyAccountRepository) GetByID(id uuid.UUID) (*models.CustodyAccount, error) {
+	query := `SELECT ` + custodyAccountColumns + ` FROM custody_accounts WHERE id = $1`
+
+	account, err := scanCustodyAccount(r.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("custody account not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get custody account: %w", err)
+	}
+
+	return account, nil
+}
+
+// GetAll 取得所有保管帳戶
+func (r *custodyAccountRepository) GetAll() ([]*models.CustodyAccount, error) {
+	query := `SELECT ` + custodyAccountColumns + ` FROM custody_accounts ORDER BY created_at`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get custody accounts: %w", err)
+	}
+	defer rows.Close()
+
+	accounts := []*models.CustodyAccount{}
+	for rows.Next() {
+		account, err := scanCustodyAccount(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan custody account: %w", err)
+		}
+		accounts = append(accounts, account)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating custody accounts: %w", err)
+	}
+
+	return accounts, nil
+}
+
+// Update 更新保管帳戶
+func (r *custodyAccountRepository) Update(id uuid.UUID, input *models.UpdateCustodyAccountInput) (*models.CustodyAccount, error) {
+	// 動態建立 UPDATE 語句
+	var setClauses []string
+	var args []interface{}
+	argPosition := 1
+
+	if input.Name != nil {
+		setClauses = append(setClauses, fmt.Sprintf("name = $%d", argPosition))
+		args = append(args, *input.Name)
+		argPosition++
+	}
+	if input.CustodyType != nil {
+		setClauses = append(setClauses, fmt.Sprintf("custody_type = $%d", argPosition))
+		args = append(args, *input.CustodyType)
+		argPosition++
+	}
+	if input.Provider != nil {
+		setClauses = append(setClauses, fmt.Sprintf("provider = $%d", argPosition))
+		args = append(args, *input.Provider)
+		argPosition++
+	}
+	if input.Note != nil {
+		setClauses = append(setClauses, fmt.Sprintf("note = $%d", argPosition))
+		args = append(args, *input.Note)
+		argPosition++
+	}
+
+	// 如果沒有任何欄位需要更新，直接返回現有資料
+	if len(setClauses) == 0 {
+		return r.GetByID(id)
+	}
+
+	args = append(args, id)
+
+	query := fmt.Sprintf(`
+		UPDATE custody_accounts
+		SET %s
+		WHERE id = $%d
+		RETURNING %s
+	`, strings.Join(setClauses, ", "), argPosition, custodyAccountColumns)
+
+	account, err := scanCustodyAccount(r.db.QueryRow(query, args...))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("custody account not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update custody account: %w", err)
+	}
+
+	return account, nil
+}
+
+// Delete 刪除保管帳戶（交易記錄保留，僅解除關聯）
+func (r *custodyAccountRepository) Delete(id uuid.UUID) error {
+	result, err := r.db.Exec(`DELETE FROM custody_accounts WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete custody account: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("custody account not found")
+	}
+
+	return nil
+}
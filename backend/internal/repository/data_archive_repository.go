@@ -0,0 +1,247 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// DataArchiveRepository 完整資料備份與還原的資料存取介面（以資料表為單位，每筆資料以欄位名稱對應值的 JSON 物件表示）
+type DataArchiveRepository interface {
+	GetSchemaVersion() (int64, bool, error)
+	ExportTable(table, orderBy string) ([]json.RawMessage, error)
+	Restore(fn func(tx DataRestoreTx) error) error
+}
+
+// DataRestoreTx 還原時於單一資料庫事務中使用的操作（任一步驟失敗即整批回滾）
+type DataRestoreTx interface {
+	CountRows(table string) (int, error)
+	FindExisting(table string, keyColumns []string, row json.RawMessage) (json.RawMessage, bool, error)
+	InsertRow(table string, row json.RawMessage) (json.RawMessage, error)
+	UpdateRow(table string, keyColumns, columns []string, row json.RawMessage) error
+}
+
+// dataArchiveRepository 完整資料備份與還原的資料存取實作
+type dataArchiveRepository struct {
+	db *sql.DB
+}
+
+// NewDataArchiveRepository 建立新的資料備份 repository
+func NewDataArchiveRepository(db *sql.DB) DataArchiveRepository {
+	return &dataArchiveRepository{db: db}
+}
+
+// GetSchemaVersion 取得目前的 migration 版本與是否為 dirty 狀態（golang-migrate 的 schema_migrations 表）
+func (r *dataArchiveRepository) GetSchemaVersion() (int64, bool, error) {
+	var version int64
+	var dirty bool
+	err := r.db.QueryRow(`SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &dirty)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, false, fmt.Errorf("no migration version recorded")
+		}
+		return 0, false, fmt.Errorf("failed to get schema version: %w", err)
+	}
+	return version, dirty, nil
+}
+
+// ExportTable 匯出資料表的所有資料（每筆資料為一個 JSON 物件）
+func (r *dataArchiveRepository) ExportTable(table, orderBy string) ([]json.RawMessage, error) {
+	query := fmt.Sprintf(`SELECT row_to_json(t) FROM (SELECT * FROM %s ORDER BY %s) t`, pq.QuoteIdentifier(table), orderBy)
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	records := []json.RawMessage{}
+	for rows.Next() {
+		var record []byte
+		if err := rows.Scan(&record); err != nil {
+			return nil, fmt.Errorf("failed to scan %s: %w", table, err)
+		}
+		records = append(records, json.RawMessage(record))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate %s: %w", table, err)
+	}
+
+	return records, nil
+}
+
+// Restore 在單一資料庫事務中執行還原，fn 回傳錯誤時回滾
+func (r *dataArchiveRepository) Restore(fn func(tx DataRestoreTx) error) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(&dataRestoreTx{tx: tx, columns: make(map[string]map[string]bool)}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// dataRestoreTx 還原事務操作實作
+type dataRestoreTx struct {
+	tx      *sql.Tx
+	columns map[string]map[string]bool // 各資料表現有的欄位（快取）
+}
+
+// CountRows 計算資料表的資料筆數
+func (t *dataRestoreTx) CountRows(table string) (int, error) {
+	var count int
+	if err := t.tx.QueryRow(fmt.Sprintf(`SELECT COUNT(*) FROM %s`, pq.QuoteIdentifier(table))).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count %s: %w", table, err)
+	}
+	return count, nil
+}
+
+// FindExisting 依鍵值欄位尋找既有資料，回傳其 id（資料表沒有 id 欄位時為 null）
+func (t *dataRestoreTx) FindExisting(table string, keyColumns []string, row json.RawMessage) (json.RawMessage, bool, error) {
+	conditions := make([]string, len(keyColumns))
+	for i, column := range keyColumns {
+		quoted := pq.QuoteIdentifier(column)
+		conditions[i] = fmt.Sprintf("t.%s IS NOT DISTINCT FROM r.%s", quoted, quoted)
+	}
+
+	quotedTable := pq.QuoteIdentifier(table)
+	query := fmt.Sprintf(`
+		SELECT to_json(t) -> 'id'
+		FROM %s t, json_populate_record(NULL::%s, $1::json) r
+		WHERE %s
+		LIMIT 1
+	`, quotedTable, quotedTable, strings.Join(conditions, " AND "))
+
+	var id sql.NullString
+	err := t.tx.QueryRow(query, string(row)).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to find existing %s: %w", table, err)
+	}
+	if !id.Valid {
+		return json.RawMessage("null"), true, nil
+	}
+	return json.RawMessage(id.String), true, nil
+}
+
+// InsertRow 新增一筆資料（僅寫入資料表現有的欄位），回傳新資料的 id（資料表沒有 id 欄位時為 null）
+func (t *dataRestoreTx) InsertRow(table string, row json.RawMessage) (json.RawMessage, error) {
+	columns, err := t.rowColumns(table, row)
+	if err != nil {
+		return nil, err
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("no matching columns for %s", table)
+	}
+
+	quotedTable := pq.QuoteIdentifier(table)
+	list := quoteColumns(columns, "")
+	query := fmt.Sprintf(`
+		INSERT INTO %s AS t (%s)
+		SELECT %s FROM json_populate_record(NULL::%s, $1::json) r
+		RETURNING to_json(t) -> 'id'
+	`, quotedTable, list, quoteColumns(columns, "r."), quotedTable)
+
+	var id sql.NullString
+	if err := t.tx.QueryRow(query, string(row)).Scan(&id); err != nil {
+		return nil, fmt.Errorf("failed to insert into %s: %w", table, err)
+	}
+	if !id.Valid {
+		return json.RawMessage("null"), nil
+	}
+	return json.RawMessage(id.String), nil
+}
+
+// UpdateRow 以鍵值欄位找到既有資料並更新指定欄位
+func (t *dataRestoreTx) UpdateRow(table string, keyColumns, columns []string, row json.RawMessage) error {
+	if len(columns) == 0 {
+		return nil
+	}
+
+	assignments := make([]string, len(columns))
+	for i, column := range columns {
+		quoted := pq.QuoteIdentifier(column)
+		assignments[i] = fmt.Sprintf("%s = r.%s", quoted, quoted)
+	}
+	conditions := make([]string, len(keyColumns))
+	for i, column := range keyColumns {
+		quoted := pq.QuoteIdentifier(column)
+		conditions[i] = fmt.Sprintf("t.%s IS NOT DISTINCT FROM r.%s", quoted, quoted)
+	}
+
+	quotedTable := pq.QuoteIdentifier(table)
+	query := fmt.Sprintf(`
+		UPDATE %s t SET %s
+		FROM json_populate_record(NULL::%s, $1::json) r
+		WHERE %s
+	`, quotedTable, strings.Join(assignments, ", "), quotedTable, strings.Join(conditions, " AND "))
+
+	if _, err := t.tx.Exec(query, string(row)); err != nil {
+		return fmt.Errorf("failed to update %s: %w", table, err)
+	}
+	return nil
+}
+
+// rowColumns 取得資料中同時存在於資料表的欄位（略過資料表沒有的欄位）
+func (t *dataRestoreTx) rowColumns(table string, row json.RawMessage) ([]string, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(row, &fields); err != nil {
+		return nil, fmt.Errorf("failed to decode %s row: %w", table, err)
+	}
+
+	existing, ok := t.columns[table]
+	if !ok {
+		rows, err := t.tx.Query(`
+			SELECT column_name FROM information_schema.columns
+			WHERE table_schema = current_schema() AND table_name = $1
+		`, table)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get columns of %s: %w", table, err)
+		}
+		defer rows.Close()
+
+		existing = make(map[string]bool)
+		for rows.Next() {
+			var column string
+			if err := rows.Scan(&column); err != nil {
+				return nil, fmt.Errorf("failed to scan column: %w", err)
+			}
+			existing[column] = true
+		}
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("failed to iterate columns: %w", err)
+		}
+		t.columns[table] = existing
+	}
+
+	columns := make([]string, 0, len(fields))
+	for column := range fields {
+		if existing[column] {
+			columns = append(columns, column)
+		}
+	}
+	// 固定欄位順序，讓相同資料產生相同的 SQL
+	sort.Strings(columns)
+	return columns, nil
+}
+
+// quoteColumns 將欄位名稱加上引號並以逗號連接
+func quoteColumns(columns []string, prefix string) string {
+	quoted := make([]string, len(columns))
+	for i, column := range columns {
+		quoted[i] = prefix + pq.QuoteIdentifier(column)
+	}
+	return strings.Join(quoted, ", ")
+}
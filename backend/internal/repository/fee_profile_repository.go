@@ -0,0 +1,342 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/chienchuanw/asset-manager/internal/models"
+	"github.com/google/uuid"
+)
+
+// FeeProfileRepository 手續費設定資料存取介面
+type FeeProfileRepository interface {
+	Create(input *models.CreateFeeProfileInput) (*models.FeeProfile, error)
+	GetByID(id uuid.UUID) (*models.FeeProfile, error)
+	GetAll() ([]*models.FeeProfile, error)
+	Update(id uuid.UUID, input *models.UpdateFeeProfileInput) (*models.FeeProfile, error)
+	Delete(id uuid.UUID) error
+	GetDefault(assetType models.AssetType) (*models.FeeProfile, error)
+	GetByBrokerageAccount(accountID uuid.UUID, assetType models.AssetType) (*models.FeeProfile, error)
+}
+
+// feeProfileRepository 手續費設定資料存取實作
+type feeProfileRepository struct {
+	db *sql.DB
+}
+
+// NewFeeProfileRepository 建立新的手續費設定 repository
+func NewFeeProfileRepository(db *sql.DB) FeeProfileRepository {
+	return &feeProfileRepository{db: db}
+}
+
+// feeProfileColumns 手續費設定查詢欄位
+const feeProfileColumns = `id, name, asset_type, brokerage_account_id, is_default, fee_rate, fee_discount,
+	per_share_fee, min_fee, max_fee, sell_tax_rates, note, created_at, updated_at`
+
+// scanFeeProfile 掃描手續費設定資料（輔助函式）
+func scanFeeProfile(scanner rowScanner) (*models.FeeProfile, error) {
+	profile := &models.FeeProfile{}
+	var sellTaxRates []byte
+	err := scanner.Scan(
+		&profile.ID,
+		&profile.Name,
+		&profile.AssetType,
+		&profile.BrokerageAccountID,
+		&profile.IsDefault,
+		&profile.FeeRate,
+		&profile.FeeDiscount,
+		&profile.PerShareFee,
+		&profile.MinFee,
+		&profile.MaxFee,
+		&sellTaxRates,
+		&profile.Note,
+		&profile.CreatedAt,
+		&profile.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	profile.SellTaxRates = map[models.InstrumentCategory]float64{}
+	if len(sellTaxRates) > 0 {
+		if err := json.Unmarshal(sellTaxRates, &profile.SellTaxRates); err != nil {
+			return nil, fmt.Errorf("failed to decode sell tax rates: %w", err)
+		}
+	}
+	return profile, nil
+}
+
+// encodeSellTaxRates 將各商品類別的交易稅率編碼為 JSON
+func encodeSellTaxRates(rates map[models.InstrumentCategory]float64) ([]byte, error) {
+	if rates == nil {
+		rates = map[models.InstrumentCategory]float64{}
+	}
+	encoded, err := json.Marshal(rates)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode sell tax rates: %w", err)
+	}
+	return encoded, nil
+}
+
+// clearDefaultTx 取消同資產類型其他設定的預設狀態（每個資產類型只能有一個預設設定）
+func clearDefaultTx(tx *sql.Tx, assetType models.AssetType, exceptID uuid.UUID) error {
+	_, err := tx.Exec(`UPDATE fee_profiles SET is_default = FALSE WHERE asset_type = $1 AND is_default AND id <> $2`, assetType, exceptID)
+	if err != nil {
+		return fmt.Errorf("failed to clear default fee profile: %w", err)
+	}
+	return nil
+}
+
+// Create 建立新的手續費設定（設為預設時會取消同資產類型原有的預設設定）
+func (r *feeProfileRepository) Create(input *models.CreateFeeProfileInput) (*models.FeeProfile, error) {
+	sellTaxRates, err := encodeSellTaxRates(input.SellTaxRates)
+	if err != nil {
+		return nil, err
+	}
+
+	feeDiscount := 1.0
+	if input.FeeDiscount != nil {
+		feeDiscount = *input.FeeDiscount
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if input.IsDefault {
+		if err := clearDefaultTx(tx, input.AssetType, uuid.Nil); err != nil {
+			return nil, err
+		}
+	}
+
+	query := `
+		INSERT INTO fee_profiles (name, asset_type, brokerage_account_id, is_default, fee_rate, fee_discount,
+			per_share_fee, min_fee, max_fee, sell_tax_rates, note)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING ` + feeProfileColumns
+
+	profile, err := scanFeeProfile(tx.QueryRow(
+		query,
+		input.Name,
+		input.AssetType,
+		input.BrokerageAccountID,
+		input.IsDefault,
+		input.FeeRate,
+		feeDiscount,
+		input.PerShareFee,
+		input.MinFee,
+		input.MaxFee,
+		sellTaxRates,
+		input.Note,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create fee profile: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return profile, nil
+}
+
+// GetByID 根據 ID 取得手續費設定
+func (r *feeProfileRepository) GetByID(id uuid.UUID) (*models.FeeProfile, error) {
+	query := `SELECT ` + feeProfileColumns + ` FROM fee_profiles WHERE id = $1`
+
+	profile, err := scanFeeProfile(r.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("fee profile not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fee profile: %w", err)
+	}
+
+	return profile, nil
+}
+
+// GetAll 取得所有手續費設定
+func (r *feeProfileRepository) GetAll() ([]*models.FeeProfile, error) {
+	query := `SELECT ` + feeProfileColumns + ` FROM fee_profiles ORDER BY asset_type, is_default DESC, created_at`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fee profiles: %w", err)
+	}
+	defer rows.Close()
+
+	profiles := []*models.FeeProfile{}
+	for rows.Next() {
+		profile, err := scanFeeProfile(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan fee profile: %w", err)
+		}
+		profiles = append(profiles, profile)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating fee profiles: %w", err)
+	}
+
+	return profiles, nil
+}
+
+// Update 更新手續費設定（設為預設時會取消同資產類型原有的預設設定）
+func (r *feeProfileRepository) Update(id uuid.UUID, input *models.UpdateFeeProfileInput) (*models.FeeProfile, error) {
+	// 動態建立 UPDATE 語句
+	var setClauses []string
+	var args []interface{}
+	argPosition := 1
+
+	if input.Name != nil {
+		setClauses = append(setClauses, fmt.Sprintf("name = $%d", argPosition))
+		args = append(args, *input.Name)
+		argPosition++
+	}
+	if input.BrokerageAccountID != nil {
+		setClauses = append(setClauses, fmt.Sprintf("brokerage_account_id = $%d", argPosition))
+		args = append(args, *input.BrokerageAccountID)
+		argPosition++
+	}
+	if input.IsDefault != nil {
+		setClauses = append(setClauses, fmt.Sprintf("is_default = $%d", argPosition))
+		args = append(args, *input.IsDefault)
+		argPosition++
+	}
+	if input.FeeRate != nil {
+		setClauses = append(setClauses, fmt.Sprintf("fee_rate = $%d", argPosition))
+		args = append(args, *input.FeeRate)
+		argPosition++
+	}
+	if input.FeeDiscount != nil {
+		setClauses = append(setClauses, fmt.Sprintf("fee_discount = $%d", argPosition))
+		args = append(args, *input.FeeDiscount)
+		argPosition++
+	}
+	if input.PerShareFee != nil {
+		setClauses = append(setClauses, fmt.Sprintf("per_share_fee = $%d", argPosition))
+		args = append(args, *input.PerShareFee)
+		argPosition++
+	}
+	if input.MinFee != nil {
+		setClauses = append(setClauses, fmt.Sprintf("min_fee = $%d", argPosition))
+		args = append(args, *input.MinFee)
+		argPosition++
+	}
+	if input.MaxFee != nil {
+		setClauses = append(setClauses, fmt.Sprintf("max_fee = $%d", argPosition))
+		args = append(args, *input.MaxFee)
+		argPosition++
+	}
+	if input.SellTaxRates != nil {
+		sellTaxRates, err := encodeSellTaxRates(input.SellTaxRates)
+		if err != nil {
+			return nil, err
+		}
+		setClauses = append(setClauses, fmt.Sprintf("sell_tax_rates = $%d", argPosition))
+		args = append(args, sellTaxRates)
+		argPosition++
+	}
+	if input.Note != nil {
+		setClauses = append(setClauses, fmt.Sprintf("note = $%d", argPosition))
+		args = append(args, *input.Note)
+		argPosition++
+	}
+
+	// 如果沒有任何欄位需要更新，直接返回現有資料
+	if len(setClauses) == 0 {
+		return r.GetByID(id)
+	}
+
+	args = append(args, id)
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if input.IsDefault != nil && *input.IsDefault {
+		_, err := tx.Exec(`
+			UPDATE fee_profiles SET is_default = FALSE
+			WHERE is_default AND id <> $1 AND asset_type = (SELECT asset_type FROM fee_profiles WHERE id = $1)
+		`, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to clear default fee profile: %w", err)
+		}
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE fee_profiles
+		SET %s
+		WHERE id = $%d
+		RETURNING %s
+	`, strings.Join(setClauses, ", "), argPosition, feeProfileColumns)
+
+	profile, err := scanFeeProfile(tx.QueryRow(query, args...))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("fee profile not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update fee profile: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return profile, nil
+}
+
+// Delete 刪除手續費設定
+func (r *feeProfileRepository) Delete(id uuid.UUID) error {
+	result, err := r.db.Exec(`DELETE FROM fee_profiles WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete fee profile: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("fee profile not found")
+	}
+
+	return nil
+}
+
+// GetDefault 取得資產類型的預設手續費設定，未設定時回傳 nil
+func (r *feeProfileRepository) GetDefault(assetType models.AssetType) (*models.FeeProfile, error) {
+	query := `SELECT ` + feeProfileColumns + ` FROM fee_profiles WHERE asset_type = $1 AND is_default`
+
+	profile, err := scanFeeProfile(r.db.QueryRow(query, assetType))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get default fee profile: %w", err)
+	}
+
+	return profile, nil
+}
+
+// GetByBrokerageAccount 取得券商帳戶在資產類型的手續費設定，未設定時回傳 nil
+func (r *feeProfileRepository) GetByBrokerageAccount(accountID uuid.UUID, assetType models.AssetType) (*models.FeeProfile, error) {
+	query := `SELECT ` + feeProfileColumns + ` FROM fee_profiles WHERE brokerage_account_id = $1 AND asset_type = $2`
+
+	profile, err := scanFeeProfile(r.db.QueryRow(query, accountID, assetType))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get brokerage account fee profile: %w", err)
+	}
+
+	return profile, nil
+}
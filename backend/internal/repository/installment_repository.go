@@ -16,9 +16,15 @@ type InstallmentRepository interface {
 	GetByID(id uuid.UUID) (*models.Installment, error)
 	List(filters InstallmentFilters) ([]*models.Installment, error)
 	Update(id uuid.UUID, input *models.UpdateInstallmentInput) (*models.Installment, error)
+	UpdateProgressTx(tx *sql.Tx, id uuid.UUID, paidCount int, status models.InstallmentStatus) error
 	Delete(id uuid.UUID) error
 	GetDueBillings(date time.Time) ([]*models.Installment, error)
 	GetCompletingSoon(remainingCount int) ([]*models.Installment, error)
+	CreatePrepaymentTx(tx *sql.Tx, prepayment *models.InstallmentPrepayment) (*models.InstallmentPrepayment, error)
+	GetPrepayments(installmentID uuid.UUID) ([]*models.InstallmentPrepayment, error)
+	CreatePaymentTx(tx *sql.Tx, payment *models.InstallmentPayment) (*models.InstallmentPayment, error)
+	GetPayments(installmentID uuid.UUID) ([]*models.InstallmentPayment, error)
+	DB() *sql.DB
 }
 
 // InstallmentFilters 分期查詢篩選條件
@@ -39,6 +45,11 @@ func NewInstallmentRepository(db *sql.DB) InstallmentRepository {
 	return &installmentRepository{db: db}
 }
 
+// DB 取得資料庫連線（供 service 開啟交易使用）
+func (r *installmentRepository) DB() *sql.DB {
+	return r.db
+}
+
 // Create 建立新的分期
 func (r *installmentRepository) Create(input *models.CreateInstallmentInput) (*models.Installment, error) {
 	// 建立 Installment 模型並計算利息
@@ -354,6 +365,31 @@ func (r *installmentRepository) Update(id uuid.UUID, input *models.UpdateInstall
 	return installment, nil
 }
 
+// UpdateProgressTx 在指定的資料庫交易中更新分期的已付期數與狀態
+func (r *installmentRepository) UpdateProgressTx(tx *sql.Tx, id uuid.UUID, paidCount int, status models.InstallmentStatus) error {
+	query := `
+		UPDATE installments
+		SET paid_count = $1, status = $2, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $3
+	`
+
+	result, err := tx.Exec(query, paidCount, status, id)
+	if err != nil {
+		return fmt.Errorf("failed to update installment progress: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
 // Delete 刪除分期
 func (r *installmentRepository) Delete(id uuid.UUID) error {
 	query := `DELETE FROM installments WHERE id = $1`
@@ -515,3 +551,139 @@ func (r *installmentRepository) GetCompletingSoon(remainingCount int) ([]*models
 	return installments, nil
 }
 
+// CreatePrepaymentTx 在指定的資料庫交易中新增提前還款記錄
+func (r *installmentRepository) CreatePrepaymentTx(tx *sql.Tx, prepayment *models.InstallmentPrepayment) (*models.InstallmentPrepayment, error) {
+	query := `
+		INSERT INTO installment_prepayments (
+			installment_id, date, amount, after_period, is_payoff, cash_flow_id, note
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at
+	`
+
+	created := *prepayment
+	err := tx.QueryRow(
+		query,
+		prepayment.InstallmentID,
+		prepayment.Date,
+		prepayment.Amount,
+		prepayment.AfterPeriod,
+		prepayment.IsPayoff,
+		prepayment.CashFlowID,
+		prepayment.Note,
+	).Scan(&created.ID, &created.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create installment prepayment: %w", err)
+	}
+
+	return &created, nil
+}
+
+// GetPrepayments 取得分期的提前還款記錄（依套用期數與日期排序）
+func (r *installmentRepository) GetPrepayments(installmentID uuid.UUID) ([]*models.InstallmentPrepayment, error) {
+	query := `
+		SELECT id, installment_id, date, amount, after_period, is_payoff, cash_flow_id, note, created_at
+		FROM installment_prepayments
+		WHERE installment_id = $1
+		ORDER BY after_period, date, created_at
+	`
+
+	rows, err := r.db.Query(query, installmentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get installment prepayments: %w", err)
+	}
+	defer rows.Close()
+
+	prepayments := []*models.InstallmentPrepayment{}
+	for rows.Next() {
+		prepayment := &models.InstallmentPrepayment{}
+		if err := rows.Scan(
+			&prepayment.ID,
+			&prepayment.InstallmentID,
+			&prepayment.Date,
+			&prepayment.Amount,
+			&prepayment.AfterPeriod,
+			&prepayment.IsPayoff,
+			&prepayment.CashFlowID,
+			&prepayment.Note,
+			&prepayment.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan installment prepayment: %w", err)
+		}
+		prepayments = append(prepayments, prepayment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating installment prepayments: %w", err)
+	}
+
+	return prepayments, nil
+}
+
+// CreatePaymentTx 在指定的資料庫交易中建立每期扣款明細
+func (r *installmentRepository) CreatePaymentTx(tx *sql.Tx, payment *models.InstallmentPayment) (*models.InstallmentPayment, error) {
+	query := `
+		INSERT INTO installment_payments (
+			installment_id, period, payment_date, principal_amount, interest_amount, cash_flow_id
+		)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`
+
+	created := *payment
+	err := tx.QueryRow(
+		query,
+		payment.InstallmentID,
+		payment.Period,
+		payment.PaymentDate,
+		payment.PrincipalAmount,
+		payment.InterestAmount,
+		payment.CashFlowID,
+	).Scan(&created.ID, &created.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create installment payment: %w", err)
+	}
+
+	return &created, nil
+}
+
+// GetPayments 取得分期的每期扣款記錄（依期數排序）
+func (r *installmentRepository) GetPayments(installmentID uuid.UUID) ([]*models.InstallmentPayment, error) {
+	query := `
+		SELECT id, installment_id, period, payment_date, principal_amount, interest_amount,
+			cash_flow_id, created_at
+		FROM installment_payments
+		WHERE installment_id = $1
+		ORDER BY period
+	`
+
+	rows, err := r.db.Query(query, installmentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get installment payments: %w", err)
+	}
+	defer rows.Close()
+
+	payments := []*models.InstallmentPayment{}
+	for rows.Next() {
+		payment := &models.InstallmentPayment{}
+		if err := rows.Scan(
+			&payment.ID,
+			&payment.InstallmentID,
+			&payment.Period,
+			&payment.PaymentDate,
+			&payment.PrincipalAmount,
+			&payment.InterestAmount,
+			&payment.CashFlowID,
+			&payment.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan installment payment: %w", err)
+		}
+		payments = append(payments, payment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating installment payments: %w", err)
+	}
+
+	return payments, nil
+}
@@ -0,0 +1,197 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/chienchuanw/asset-manager/internal/models"
+)
+
+// InstrumentRepository 商品主檔資料存取介面
+type InstrumentRepository interface {
+	UpsertBatch(instruments []*models.Instrument) (int, error)
+	GetBySymbol(symbol string, assetType models.AssetType) (*models.Instrument, error)
+	Search(query string, assetType *models.AssetType, limit int) ([]*models.Instrument, error)
+	HasAssetType(assetType models.AssetType) (bool, error)
+}
+
+// instrumentRepository 商品主檔資料存取實作
+type instrumentRepository struct {
+	db *sql.DB
+}
+
+// NewInstrumentRepository 建立新的商品主檔 repository
+func NewInstrumentRepository(db *sql.DB) InstrumentRepository {
+	return &instrumentRepository{db: db}
+}
+
+// instrumentColumns 商品主檔查詢欄位
+const instrumentColumns = `id, symbol, exchange, name_zh_tw, name_en, currency, asset_type, category, lot_size, provider_ids, created_at, updated_at`
+
+// scanInstrument 掃描商品主檔資料（輔助函式）
+func scanInstrument(scanner rowScanner) (*models.Instrument, error) {
+	instrument := &models.Instrument{}
+	var providerIDs []byte
+	err := scanner.Scan(
+		&instrument.ID,
+		&instrument.Symbol,
+		&instrument.Exchange,
+		&instrument.NameZhTW,
+		&instrument.NameEn,
+		&instrument.Currency,
+		&instrument.AssetType,
+		&instrument.Category,
+		&instrument.LotSize,
+		&providerIDs,
+		&instrument.CreatedAt,
+		&instrument.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	instrument.ProviderIDs = map[string]string{}
+	if len(providerIDs) > 0 {
+		if err := json.Unmarshal(providerIDs, &instrument.ProviderIDs); err != nil {
+			return nil, fmt.Errorf("failed to decode provider ids: %w", err)
+		}
+	}
+	return instrument, nil
+}
+
+// UpsertBatch 在單一資料庫事務中新增或更新商品（以資產類型 + 代碼識別）
+// 新資料的名稱為空時保留原有名稱，來源代碼與原有資料合併
+func (r *instrumentRepository) UpsertBatch(instruments []*models.Instrument) (int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO instruments (symbol, exchange, name_zh_tw, name_en, currency, asset_type, category, lot_size, provider_ids)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (asset_type, symbol) DO UPDATE SET
+			exchange = EXCLUDED.exchange,
+			name_zh_tw = COALESCE(NULLIF(EXCLUDED.name_zh_tw, ''), instruments.name_zh_tw),
+			name_en = COALESCE(NULLIF(EXCLUDED.name_en, ''), instruments.name_en),
+			currency = EXCLUDED.currency,
+			category = EXCLUDED.category,
+			lot_size = EXCLUDED.lot_size,
+			provider_ids = instruments.provider_ids || EXCLUDED.provider_ids
+	`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prepare instrument upsert: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, instrument := range instruments {
+		providerIDs := instrument.ProviderIDs
+		if providerIDs == nil {
+			providerIDs = map[string]string{}
+		}
+		encoded, err := json.Marshal(providerIDs)
+		if err != nil {
+			return 0, fmt.Errorf("failed to encode provider ids: %w", err)
+		}
+
+		_, err = stmt.Exec(
+			instrument.Symbol,
+			instrument.Exchange,
+			instrument.NameZhTW,
+			instrument.NameEn,
+			instrument.Currency,
+			instrument.AssetType,
+			instrument.Category,
+			instrument.LotSize,
+			encoded,
+		)
+		if err != nil {
+			return 0, fmt.Errorf("failed to upsert instrument %s: %w", instrument.Symbol, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return len(instruments), nil
+}
+
+// GetBySymbol 根據代碼取得商品（不分大小寫），找不到時回傳 nil
+func (r *instrumentRepository) GetBySymbol(symbol string, assetType models.AssetType) (*models.Instrument, error) {
+	query := `SELECT ` + instrumentColumns + ` FROM instruments WHERE asset_type = $1 AND UPPER(symbol) = UPPER($2)`
+
+	instrument, err := scanInstrument(r.db.QueryRow(query, assetType, symbol))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get instrument: %w", err)
+	}
+
+	return instrument, nil
+}
+
+// Search 依代碼前綴或名稱搜尋商品（代碼完全相符者優先，其次為代碼前綴，最後為名稱包含）
+func (r *instrumentRepository) Search(query string, assetType *models.AssetType, limit int) ([]*models.Instrument, error) {
+	escaped := escapeLikePattern(strings.TrimSpace(query))
+
+	sqlQuery := `
+		SELECT ` + instrumentColumns + `
+		FROM instruments
+		WHERE (symbol ILIKE $1 || '%' OR name_zh_tw ILIKE '%' || $1 || '%' OR name_en ILIKE '%' || $1 || '%')
+	`
+	args := []interface{}{escaped}
+
+	if assetType != nil {
+		args = append(args, *assetType)
+		sqlQuery += fmt.Sprintf(" AND asset_type = $%d", len(args))
+	}
+
+	args = append(args, limit)
+	sqlQuery += fmt.Sprintf(`
+		ORDER BY
+			CASE WHEN symbol ILIKE $1 THEN 0 WHEN symbol ILIKE $1 || '%%' THEN 1 ELSE 2 END,
+			LENGTH(symbol), symbol
+		LIMIT $%d`, len(args))
+
+	rows, err := r.db.Query(sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search instruments: %w", err)
+	}
+	defer rows.Close()
+
+	instruments := []*models.Instrument{}
+	for rows.Next() {
+		instrument, err := scanInstrument(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan instrument: %w", err)
+		}
+		instruments = append(instruments, instrument)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating instruments: %w", err)
+	}
+
+	return instruments, nil
+}
+
+// HasAssetType 檢查資產類型是否已匯入商品主檔
+func (r *instrumentRepository) HasAssetType(assetType models.AssetType) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(`SELECT EXISTS (SELECT 1 FROM instruments WHERE asset_type = $1)`, assetType).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check instruments: %w", err)
+	}
+	return exists, nil
+}
+
+// escapeLikePattern 跳脫 LIKE 模式中的特殊字元
+func escapeLikePattern(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return replacer.Replace(value)
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/chienchuanw/asset-manager/internal/models"
+)
+
+// LastKnownPriceRepository 最後已知價格資料存取介面
+type LastKnownPriceRepository interface {
+	UpsertBatch(prices []*models.Price) error
+	Get(symbol string, assetType models.AssetType) (*models.Price, error)
+}
+
+// lastKnownPriceRepository 最後已知價格資料存取實作
+type lastKnownPriceRepository struct {
+	db *sql.DB
+}
+
+// NewLastKnownPriceRepository 建立新的最後已知價格 repository
+func NewLastKnownPriceRepository(db *sql.DB) LastKnownPriceRepository {
+	return &lastKnownPriceRepository{db: db}
+}
+
+// lastKnownPriceColumns 最後已知價格查詢欄位
+const lastKnownPriceColumns = `symbol, asset_type, price, currency, source, fetched_at`
+
+// scanLastKnownPrice 掃描最後已知價格資料（輔助函式）
+func scanLastKnownPrice(scanner rowScanner) (*models.Price, error) {
+	price := &models.Price{}
+	err := scanner.Scan(
+		&price.Symbol,
+		&price.AssetType,
+		&price.Price,
+		&price.Currency,
+		&price.Source,
+		&price.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return price, nil
+}
+
+// UpsertBatch 在單一資料庫事務中寫入標的最新一次成功取得的價格（較舊的價格不會覆蓋較新的價格）
+func (r *lastKnownPriceRepository) UpsertBatch(prices []*models.Price) error {
+	if len(prices) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO last_known_prices (symbol, asset_type, price, currency, source, fetched_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (symbol, asset_type) DO UPDATE SET
+			price = EXCLUDED.price,
+			currency = EXCLUDED.currency,
+			source = EXCLUDED.source,
+			fetched_at = EXCLUDED.fetched_at,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE last_known_prices.fetched_at <= EXCLUDED.fetched_at
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare last known price upsert: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, price := range prices {
+		_, err := stmt.Exec(price.Symbol, price.AssetType, price.Price, price.Currency, price.Source, price.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to upsert last known price %s: %w", price.Symbol, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// Get 取得標的的最後已知價格（不存在時回傳 nil）
+func (r *lastKnownPriceRepository) Get(symbol string, assetType models.AssetType) (*models.Price, error) {
+	query := `SELECT ` + lastKnownPriceColumns + ` FROM last_known_prices WHERE symbol = $1 AND asset_type = $2`
+
+	price, err := scanLastKnownPrice(r.db.QueryRow(query, symbol, assetType))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get last known price: %w", err)
+	}
+
+	return price, nil
+}
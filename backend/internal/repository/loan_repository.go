@@ -0,0 +1,551 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/chienchuanw/asset-manager/internal/models"
+	"github.com/google/uuid"
+)
+
+// LoanRepository 貸款資料存取介面
+type LoanRepository interface {
+	Create(input *models.CreateLoanInput) (*models.Loan, error)
+	GetByID(id uuid.UUID) (*models.Loan, error)
+	List(filters LoanFilters) ([]*models.Loan, error)
+	Update(id uuid.UUID, input *models.UpdateLoanInput) (*models.Loan, error)
+	Delete(id uuid.UUID) error
+	GetDueBillings(date time.Time) ([]*models.Loan, error)
+	AddRateChange(loanID uuid.UUID, input *models.CreateLoanRateChangeInput) (*models.LoanRateChange, error)
+	GetRateChanges(loanID uuid.UUID) ([]*models.LoanRateChange, error)
+	AddPrepayment(loanID uuid.UUID, input *models.CreateLoanPrepaymentInput) (*models.LoanPrepayment, error)
+	GetPrepayments(loanID uuid.UUID) ([]*models.LoanPrepayment, error)
+	CreatePayment(payment *models.LoanPayment) (*models.LoanPayment, error)
+	CreatePaymentTx(tx *sql.Tx, payment *models.LoanPayment) (*models.LoanPayment, error)
+	UpdateProgressTx(tx *sql.Tx, id uuid.UUID, paidCount int, status models.LoanStatus) error
+	GetPayments(loanID uuid.UUID) ([]*models.LoanPayment, error)
+}
+
+// LoanFilters 貸款查詢篩選條件
+type LoanFilters struct {
+	Status   *models.LoanStatus `json:"status,omitempty"`
+	LoanType *models.LoanType   `json:"loan_type,omitempty"`
+	Limit    int                `json:"limit,omitempty"`
+	Offset   int                `json:"offset,omitempty"`
+}
+
+// loanRepository 貸款資料存取實作
+type loanRepository struct {
+	db *sql.DB
+}
+
+// NewLoanRepository 建立新的貸款 repository
+func NewLoanRepository(db *sql.DB) LoanRepository {
+	return &loanRepository{db: db}
+}
+
+// loanColumns 貸款查詢欄位（與 scanLoan 的順序一致）
+const loanColumns = `
+	l.id, l.name, l.loan_type, l.principal, l.currency, l.annual_rate, l.term_months,
+	l.payment_day, l.paid_count, l.start_date, l.category_id, l.interest_category_id,
+	l.payment_method, l.account_id, l.status, l.note, l.created_at, l.updated_at`
+
+// rowScanner 同時支援 *sql.Row 與 *sql.Rows 的掃描介面
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// queryRower 同時支援 *sql.DB 與 *sql.Tx 的單筆查詢介面
+type queryRower interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// scanLoan 掃描貸款資料（輔助函式）
+func scanLoan(scanner rowScanner) (*models.Loan, error) {
+	loan := &models.Loan{}
+	err := scanner.Scan(
+		&loan.ID,
+		&loan.Name,
+		&loan.LoanType,
+		&loan.Principal,
+		&loan.Currency,
+		&loan.AnnualRate,
+		&loan.TermMonths,
+		&loan.PaymentDay,
+		&loan.PaidCount,
+		&loan.StartDate,
+		&loan.CategoryID,
+		&loan.InterestCategoryID,
+		&loan.PaymentMethod,
+		&loan.AccountID,
+		&loan.Status,
+		&loan.Note,
+		&loan.CreatedAt,
+		&loan.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return loan, nil
+}
+
+// scanLoans 掃描多筆貸款資料
+func scanLoans(rows *sql.Rows) ([]*models.Loan, error) {
+	loans := []*models.Loan{}
+	for rows.Next() {
+		loan, err := scanLoan(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan loan: %w", err)
+		}
+		loans = append(loans, loan)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating loans: %w", err)
+	}
+
+	return loans, nil
+}
+
+// Create 建立新的貸款
+func (r *loanRepository) Create(input *models.CreateLoanInput) (*models.Loan, error) {
+	query := `
+		INSERT INTO loans AS l (
+			name, loan_type, principal, currency, annual_rate, term_months, payment_day,
+			start_date, category_id, interest_category_id, payment_method, account_id, note
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		RETURNING ` + loanColumns
+
+	loan, err := scanLoan(r.db.QueryRow(
+		query,
+		input.Name,
+		input.LoanType,
+		input.Principal,
+		models.CurrencyTWD, // 固定為 TWD
+		input.AnnualRate,
+		input.TermMonths,
+		input.PaymentDay,
+		input.StartDate,
+		input.CategoryID,
+		input.InterestCategoryID,
+		input.PaymentMethod,
+		input.AccountID,
+		input.Note,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create loan: %w", err)
+	}
+
+	return loan, nil
+}
+
+// GetByID 根據 ID 取得貸款
+func (r *loanRepository) GetByID(id uuid.UUID) (*models.Loan, error) {
+	query := `SELECT ` + loanColumns + ` FROM loans l WHERE l.id = $1`
+
+	loan, err := scanLoan(r.db.QueryRow(query, id))
+	if err != nil {
+		return nil, err
+	}
+
+	return loan, nil
+}
+
+// List 取得貸款列表
+func (r *loanRepository) List(filters LoanFilters) ([]*models.Loan, error) {
+	query := `SELECT ` + loanColumns + ` FROM loans l WHERE 1=1`
+
+	args := []interface{}{}
+	argCount := 1
+
+	if filters.Status != nil {
+		query += fmt.Sprintf(" AND l.status = $%d", argCount)
+		args = append(args, *filters.Status)
+		argCount++
+	}
+
+	if filters.LoanType != nil {
+		query += fmt.Sprintf(" AND l.loan_type = $%d", argCount)
+		args = append(args, *filters.LoanType)
+		argCount++
+	}
+
+	query += " ORDER BY l.created_at DESC"
+
+	if filters.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT $%d", argCount)
+		args = append(args, filters.Limit)
+		argCount++
+	}
+
+	if filters.Offset > 0 {
+		query += fmt.Sprintf(" OFFSET $%d", argCount)
+		args = append(args, filters.Offset)
+		argCount++
+	}
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list loans: %w", err)
+	}
+	defer rows.Close()
+
+	return scanLoans(rows)
+}
+
+// Update 更新貸款
+func (r *loanRepository) Update(id uuid.UUID, input *models.UpdateLoanInput) (*models.Loan, error) {
+	updates := []string{}
+	args := []interface{}{}
+	argCount := 1
+
+	if input.Name != nil {
+		updates = append(updates, fmt.Sprintf("name = $%d", argCount))
+		args = append(args, *input.Name)
+		argCount++
+	}
+
+	if input.PaymentDay != nil {
+		updates = append(updates, fmt.Sprintf("payment_day = $%d", argCount))
+		args = append(args, *input.PaymentDay)
+		argCount++
+	}
+
+	if input.CategoryID != nil {
+		updates = append(updates, fmt.Sprintf("category_id = $%d", argCount))
+		args = append(args, *input.CategoryID)
+		argCount++
+	}
+
+	if input.InterestCategoryID != nil {
+		updates = append(updates, fmt.Sprintf("interest_category_id = $%d", argCount))
+		args = append(args, *input.InterestCategoryID)
+		argCount++
+	}
+
+	if input.PaymentMethod != nil {
+		updates = append(updates, fmt.Sprintf("payment_method = $%d", argCount))
+		args = append(args, *input.PaymentMethod)
+		argCount++
+	}
+
+	if input.AccountID != nil {
+		updates = append(updates, fmt.Sprintf("account_id = $%d", argCount))
+		args = append(args, *input.AccountID)
+		argCount++
+	}
+
+	if input.PaidCount != nil {
+		updates = append(updates, fmt.Sprintf("paid_count = $%d", argCount))
+		args = append(args, *input.PaidCount)
+		argCount++
+	}
+
+	if input.Status != nil {
+		updates = append(updates, fmt.Sprintf("status = $%d", argCount))
+		args = append(args, *input.Status)
+		argCount++
+	}
+
+	if input.Note != nil {
+		updates = append(updates, fmt.Sprintf("note = $%d", argCount))
+		args = append(args, *input.Note)
+		argCount++
+	}
+
+	if len(updates) == 0 {
+		return r.GetByID(id)
+	}
+
+	args = append(args, id)
+
+	query := fmt.Sprintf(`
+		UPDATE loans AS l
+		SET %s, updated_at = CURRENT_TIMESTAMP
+		WHERE l.id = $%d
+		RETURNING %s
+	`, strings.Join(updates, ", "), argCount, loanColumns)
+
+	loan, err := scanLoan(r.db.QueryRow(query, args...))
+	if err != nil {
+		return nil, fmt.Errorf("failed to update loan: %w", err)
+	}
+
+	return loan, nil
+}
+
+// Delete 刪除貸款
+func (r *loanRepository) Delete(id uuid.UUID) error {
+	query := `DELETE FROM loans WHERE id = $1`
+	result, err := r.db.Exec(query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete loan: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// GetDueBillings 取得指定日期需要扣款的貸款
+// 月底時一併取得扣款日大於當月天數的貸款，與 Loan.PaymentDate 的月底調整一致
+func (r *loanRepository) GetDueBillings(date time.Time) ([]*models.Loan, error) {
+	day := date.Day()
+	daysInMonth := time.Date(date.Year(), date.Month()+1, 0, 0, 0, 0, 0, date.Location()).Day()
+
+	query := `
+		SELECT ` + loanColumns + `
+		FROM loans l
+		WHERE l.status = $1
+			AND (l.payment_day = $2 OR ($2 = $4 AND l.payment_day > $4))
+			AND l.start_date < $3
+			AND l.paid_count < l.term_months
+		ORDER BY l.name
+	`
+
+	rows, err := r.db.Query(query, models.LoanStatusActive, day, date, daysInMonth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due loan billings: %w", err)
+	}
+	defer rows.Close()
+
+	return scanLoans(rows)
+}
+
+// AddRateChange 新增利率調整記錄（同一生效日重複新增時覆寫利率）
+func (r *loanRepository) AddRateChange(loanID uuid.UUID, input *models.CreateLoanRateChangeInput) (*models.LoanRateChange, error) {
+	query := `
+		INSERT INTO loan_rate_changes (loan_id, effective_date, annual_rate)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (loan_id, effective_date) DO UPDATE SET annual_rate = EXCLUDED.annual_rate
+		RETURNING id, loan_id, effective_date, annual_rate, created_at
+	`
+
+	change := &models.LoanRateChange{}
+	err := r.db.QueryRow(query, loanID, input.EffectiveDate, input.AnnualRate).Scan(
+		&change.ID,
+		&change.LoanID,
+		&change.EffectiveDate,
+		&change.AnnualRate,
+		&change.CreatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add loan rate change: %w", err)
+	}
+
+	return change, nil
+}
+
+// GetRateChanges 取得貸款的利率調整記錄（依生效日排序）
+func (r *loanRepository) GetRateChanges(loanID uuid.UUID) ([]*models.LoanRateChange, error) {
+	query := `
+		SELECT id, loan_id, effective_date, annual_rate, created_at
+		FROM loan_rate_changes
+		WHERE loan_id = $1
+		ORDER BY effective_date
+	`
+
+	rows, err := r.db.Query(query, loanID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get loan rate changes: %w", err)
+	}
+	defer rows.Close()
+
+	changes := []*models.LoanRateChange{}
+	for rows.Next() {
+		change := &models.LoanRateChange{}
+		if err := rows.Scan(
+			&change.ID,
+			&change.LoanID,
+			&change.EffectiveDate,
+			&change.AnnualRate,
+			&change.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan loan rate change: %w", err)
+		}
+		changes = append(changes, change)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating loan rate changes: %w", err)
+	}
+
+	return changes, nil
+}
+
+// AddPrepayment 新增提前還本記錄
+func (r *loanRepository) AddPrepayment(loanID uuid.UUID, input *models.CreateLoanPrepaymentInput) (*models.LoanPrepayment, error) {
+	query := `
+		INSERT INTO loan_prepayments (loan_id, date, amount, note)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, loan_id, date, amount, note, created_at
+	`
+
+	prepayment := &models.LoanPrepayment{}
+	err := r.db.QueryRow(query, loanID, input.Date, input.Amount, input.Note).Scan(
+		&prepayment.ID,
+		&prepayment.LoanID,
+		&prepayment.Date,
+		&prepayment.Amount,
+		&prepayment.Note,
+		&prepayment.CreatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add loan prepayment: %w", err)
+	}
+
+	return prepayment, nil
+}
+
+// GetPrepayments 取得貸款的提前還本記錄（依日期排序）
+func (r *loanRepository) GetPrepayments(loanID uuid.UUID) ([]*models.LoanPrepayment, error) {
+	query := `
+		SELECT id, loan_id, date, amount, note, created_at
+		FROM loan_prepayments
+		WHERE loan_id = $1
+		ORDER BY date
+	`
+
+	rows, err := r.db.Query(query, loanID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get loan prepayments: %w", err)
+	}
+	defer rows.Close()
+
+	prepayments := []*models.LoanPrepayment{}
+	for rows.Next() {
+		prepayment := &models.LoanPrepayment{}
+		if err := rows.Scan(
+			&prepayment.ID,
+			&prepayment.LoanID,
+			&prepayment.Date,
+			&prepayment.Amount,
+			&prepayment.Note,
+			&prepayment.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan loan prepayment: %w", err)
+		}
+		prepayments = append(prepayments, prepayment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating loan prepayments: %w", err)
+	}
+
+	return prepayments, nil
+}
+
+// CreatePayment 建立每期還款記錄
+func (r *loanRepository) CreatePayment(payment *models.LoanPayment) (*models.LoanPayment, error) {
+	return createLoanPayment(r.db, payment)
+}
+
+// CreatePaymentTx 在指定的資料庫交易中建立還款明細
+func (r *loanRepository) CreatePaymentTx(tx *sql.Tx, payment *models.LoanPayment) (*models.LoanPayment, error) {
+	return createLoanPayment(tx, payment)
+}
+
+// createLoanPayment 建立還款明細（CreatePayment 與 CreatePaymentTx 共用）
+func createLoanPayment(q queryRower, payment *models.LoanPayment) (*models.LoanPayment, error) {
+	query := `
+		INSERT INTO loan_payments (
+			loan_id, period, payment_date, principal_amount, interest_amount,
+			principal_cash_flow_id, interest_cash_flow_id
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at
+	`
+
+	created := *payment
+	err := q.QueryRow(
+		query,
+		payment.LoanID,
+		payment.Period,
+		payment.PaymentDate,
+		payment.PrincipalAmount,
+		payment.InterestAmount,
+		payment.PrincipalCashFlowID,
+		payment.InterestCashFlowID,
+	).Scan(&created.ID, &created.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create loan payment: %w", err)
+	}
+
+	return &created, nil
+}
+
+// UpdateProgressTx 在指定的資料庫交易中更新貸款的已付期數與狀態
+func (r *loanRepository) UpdateProgressTx(tx *sql.Tx, id uuid.UUID, paidCount int, status models.LoanStatus) error {
+	query := `
+		UPDATE loans
+		SET paid_count = $1, status = $2, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $3
+	`
+
+	result, err := tx.Exec(query, paidCount, status, id)
+	if err != nil {
+		return fmt.Errorf("failed to update loan progress: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("loan not found")
+	}
+
+	return nil
+}
+
+// GetPayments 取得貸款的還款記錄（依期數排序）
+func (r *loanRepository) GetPayments(loanID uuid.UUID) ([]*models.LoanPayment, error) {
+	query := `
+		SELECT id, loan_id, period, payment_date, principal_amount, interest_amount,
+			principal_cash_flow_id, interest_cash_flow_id, created_at
+		FROM loan_payments
+		WHERE loan_id = $1
+		ORDER BY period
+	`
+
+	rows, err := r.db.Query(query, loanID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get loan payments: %w", err)
+	}
+	defer rows.Close()
+
+	payments := []*models.LoanPayment{}
+	for rows.Next() {
+		payment := &models.LoanPayment{}
+		if err := rows.Scan(
+			&payment.ID,
+			&payment.LoanID,
+			&payment.Period,
+			&payment.PaymentDate,
+			&payment.PrincipalAmount,
+			&payment.InterestAmount,
+			&payment.PrincipalCashFlowID,
+			&payment.InterestCashFlowID,
+			&payment.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan loan payment: %w", err)
+		}
+		payments = append(payments, payment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating loan payments: %w", err)
+	}
+
+	return payments, nil
+}
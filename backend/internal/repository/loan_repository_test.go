@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/chienchuanw/asset-manager/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// cleanupLoans 清理測試資料庫中的貸款記錄
+func cleanupLoans(db *sql.DB) error {
+	_, err := db.Exec("DELETE FROM loans")
+	return err
+}
+
+// TestLoanRepository_GetDueBillings_February 測試二月月底取得扣款日超過當月天數的貸款
+func TestLoanRepository_GetDueBillings_February(t *testing.T) {
+	db, err := setupTestDB()
+	require.NoError(t, err, "Failed to setup test database")
+	defer db.Close()
+
+	// 清理測試資料
+	require.NoError(t, cleanupLoans(db))
+
+	repo := NewLoanRepository(db)
+
+	// 取得測試用的分類 ID
+	categoryID, err := getTestCategory(db, models.CashFlowTypeExpense)
+	require.NoError(t, err, "Failed to get test category")
+
+	// 準備扣款日 15、28、30、31 日的貸款
+	for _, day := range []int{15, 28, 30, 31} {
+		_, err := repo.Create(&models.CreateLoanInput{
+			Name:          fmt.Sprintf("貸款 %02d 日扣款", day),
+			LoanType:      models.LoanTypePersonal,
+			Principal:     120000,
+			AnnualRate:    3,
+			TermMonths:    12,
+			PaymentDay:    day,
+			StartDate:     time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC),
+			CategoryID:    categoryID,
+			PaymentMethod: models.PaymentMethodCash,
+		})
+		require.NoError(t, err)
+	}
+
+	paymentDays := func(loans []*models.Loan) []int {
+		days := make([]int, 0, len(loans))
+		for _, loan := range loans {
+			days = append(days, loan.PaymentDay)
+		}
+		return days
+	}
+
+	// 二月最後一天：扣款日 28、30、31 皆應扣款
+	loans, err := repo.GetDueBillings(time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, []int{28, 30, 31}, paymentDays(loans))
+
+	// 二月 27 日：沒有貸款到期
+	loans, err = repo.GetDueBillings(time.Date(2026, 2, 27, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Empty(t, loans)
+
+	// 三月 30 日：只有扣款日 30 的貸款，31 日的貸款等到 3/31
+	loans, err = repo.GetDueBillings(time.Date(2026, 3, 30, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, []int{30}, paymentDays(loans))
+}
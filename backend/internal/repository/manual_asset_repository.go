@@ -0,0 +1,331 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/chienchuanw/asset-manager/internal/models"
+	"github.com/google/uuid"
+)
+
+// ManualAssetRepository 手動估值資產資料存取介面
+type ManualAssetRepository interface {
+	Create(input *models.CreateManualAssetInput) (*models.ManualAsset, error)
+	GetByID(id uuid.UUID) (*models.ManualAsset, error)
+	List(filters ManualAssetFilters) ([]*models.ManualAsset, error)
+	Update(id uuid.UUID, input *models.UpdateManualAssetInput) (*models.ManualAsset, error)
+	Delete(id uuid.UUID) error
+	AddValuation(assetID uuid.UUID, input *models.CreateManualAssetValuationInput) (*models.ManualAssetValuation, error)
+	GetValuations(assetID uuid.UUID) ([]*models.ManualAssetValuation, error)
+	DeleteValuation(assetID, valuationID uuid.UUID) error
+}
+
+// ManualAssetFilters 手動估值資產查詢篩選條件
+type ManualAssetFilters struct {
+	Status   *models.ManualAssetStatus   `json:"status,omitempty"`
+	Category *models.ManualAssetCategory `json:"category,omitempty"`
+}
+
+// manualAssetRepository 手動估值資產資料存取實作
+type manualAssetRepository struct {
+	db *sql.DB
+}
+
+// NewManualAssetRepository 建立新的手動估值資產 repository
+func NewManualAssetRepository(db *sql.DB) ManualAssetRepository {
+	return &manualAssetRepository{db: db}
+}
+
+// manualAssetColumns 手動估值資產查詢欄位（與 scanManualAsset 的順序一致）
+const manualAssetColumns = `
+	id, name, category, currency, acquisition_date, acquisition_cost, schedule_type,
+	annual_rate, useful_life_months, salvage_value, status, note, created_at, updated_at`
+
+// scanManualAsset 掃描手動估值資產資料（輔助函式）
+func scanManualAsset(scanner rowScanner) (*models.ManualAsset, error) {
+	asset := &models.ManualAsset{}
+	err := scanner.Scan(
+		&asset.ID,
+		&asset.Name,
+		&asset.Category,
+		&asset.Currency,
+		&asset.AcquisitionDate,
+		&asset.AcquisitionCost,
+		&asset.ScheduleType,
+		&asset.AnnualRate,
+		&asset.UsefulLifeMonths,
+		&asset.SalvageValue,
+		&asset.Status,
+		&asset.Note,
+		&asset.CreatedAt,
+		&asset.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return asset, nil
+}
+
+// Create 建立新的手動估值資產
+func (r *manualAssetRepository) Create(input *models.CreateManualAssetInput) (*models.ManualAsset, error) {
+	query := `
+		INSERT INTO manual_assets (
+			name, category, currency, acquisition_date, acquisition_cost, schedule_type,
+			annual_rate, useful_life_months, salvage_value, note
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING ` + manualAssetColumns
+
+	asset, err := scanManualAsset(r.db.QueryRow(
+		query,
+		input.Name,
+		input.Category,
+		input.Currency,
+		input.AcquisitionDate,
+		input.AcquisitionCost,
+		input.ScheduleType,
+		input.AnnualRate,
+		input.UsefulLifeMonths,
+		input.SalvageValue,
+		input.Note,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create manual asset: %w", err)
+	}
+
+	return asset, nil
+}
+
+// GetByID 根據 ID 取得手動估值資產
+func (r *manualAssetRepository) GetByID(id uuid.UUID) (*models.ManualAsset, error) {
+	query := `SELECT ` + manualAssetColumns + ` FROM manual_assets WHERE id = $1`
+
+	asset, err := scanManualAsset(r.db.QueryRow(query, id))
+	if err != nil {
+		return nil, err
+	}
+
+	return asset, nil
+}
+
+// List 取得手動估值資產列表
+func (r *manualAssetRepository) List(filters ManualAssetFilters) ([]*models.ManualAsset, error) {
+	query := `SELECT ` + manualAssetColumns + ` FROM manual_assets WHERE 1=1`
+
+	args := []interface{}{}
+	argCount := 1
+
+	if filters.Status != nil {
+		query += fmt.Sprintf(" AND status = $%d", argCount)
+		args = append(args, *filters.Status)
+		argCount++
+	}
+
+	if filters.Category != nil {
+		query += fmt.Sprintf(" AND category = $%d", argCount)
+		args = append(args, *filters.Category)
+		argCount++
+	}
+
+	query += " ORDER BY acquisition_date DESC, name"
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list manual assets: %w", err)
+	}
+	defer rows.Close()
+
+	assets := []*models.ManualAsset{}
+	for rows.Next() {
+		asset, err := scanManualAsset(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan manual asset: %w", err)
+		}
+		assets = append(assets, asset)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating manual assets: %w", err)
+	}
+
+	return assets, nil
+}
+
+// Update 更新手動估值資產
+func (r *manualAssetRepository) Update(id uuid.UUID, input *models.UpdateManualAssetInput) (*models.ManualAsset, error) {
+	updates := []string{}
+	args := []interface{}{}
+	argCount := 1
+
+	if input.Name != nil {
+		updates = append(updates, fmt.Sprintf("name = $%d", argCount))
+		args = append(args, *input.Name)
+		argCount++
+	}
+
+	if input.Category != nil {
+		updates = append(updates, fmt.Sprintf("category = $%d", argCount))
+		args = append(args, *input.Category)
+		argCount++
+	}
+
+	if input.ScheduleType != nil {
+		updates = append(updates, fmt.Sprintf("schedule_type = $%d", argCount))
+		args = append(args, *input.ScheduleType)
+		argCount++
+	}
+
+	if input.AnnualRate != nil {
+		updates = append(updates, fmt.Sprintf("annual_rate = $%d", argCount))
+		args = append(args, *input.AnnualRate)
+		argCount++
+	}
+
+	if input.UsefulLifeMonths != nil {
+		updates = append(updates, fmt.Sprintf("useful_life_months = $%d", argCount))
+		args = append(args, *input.UsefulLifeMonths)
+		argCount++
+	}
+
+	if input.SalvageValue != nil {
+		updates = append(updates, fmt.Sprintf("salvage_value = $%d", argCount))
+		args = append(args, *input.SalvageValue)
+		argCount++
+	}
+
+	if input.Status != nil {
+		updates = append(updates, fmt.Sprintf("status = $%d", argCount))
+		args = append(args, *input.Status)
+		argCount++
+	}
+
+	if input.Note != nil {
+		updates = append(updates, fmt.Sprintf("note = $%d", argCount))
+		args = append(args, *input.Note)
+		argCount++
+	}
+
+	if len(updates) == 0 {
+		return r.GetByID(id)
+	}
+
+	args = append(args, id)
+
+	query := fmt.Sprintf(`
+		UPDATE manual_assets
+		SET %s, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $%d
+		RETURNING %s
+	`, strings.Join(updates, ", "), argCount, manualAssetColumns)
+
+	asset, err := scanManualAsset(r.db.QueryRow(query, args...))
+	if err != nil {
+		return nil, fmt.Errorf("failed to update manual asset: %w", err)
+	}
+
+	return asset, nil
+}
+
+// Delete 刪除手動估值資產
+func (r *manualAssetRepository) Delete(id uuid.UUID) error {
+	query := `DELETE FROM manual_assets WHERE id = $1`
+	result, err := r.db.Exec(query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete manual asset: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// AddValuation 新增估值記錄（同一日期重複新增時覆寫估值）
+func (r *manualAssetRepository) AddValuation(assetID uuid.UUID, input *models.CreateManualAssetValuationInput) (*models.ManualAssetValuation, error) {
+	query := `
+		INSERT INTO manual_asset_valuations (asset_id, valuation_date, value, note)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (asset_id, valuation_date) DO UPDATE SET value = EXCLUDED.value, note = EXCLUDED.note
+		RETURNING id, asset_id, valuation_date, value, note, created_at
+	`
+
+	valuation := &models.ManualAssetValuation{}
+	err := r.db.QueryRow(query, assetID, input.ValuationDate, input.Value, input.Note).Scan(
+		&valuation.ID,
+		&valuation.AssetID,
+		&valuation.ValuationDate,
+		&valuation.Value,
+		&valuation.Note,
+		&valuation.CreatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add manual asset valuation: %w", err)
+	}
+
+	return valuation, nil
+}
+
+// GetValuations 取得資產的估值記錄（依日期由新到舊排序）
+func (r *manualAssetRepository) GetValuations(assetID uuid.UUID) ([]*models.ManualAssetValuation, error) {
+	query := `
+		SELECT id, asset_id, valuation_date, value, note, created_at
+		FROM manual_asset_valuations
+		WHERE asset_id = $1
+		ORDER BY valuation_date DESC
+	`
+
+	rows, err := r.db.Query(query, assetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get manual asset valuations: %w", err)
+	}
+	defer rows.Close()
+
+	valuations := []*models.ManualAssetValuation{}
+	for rows.Next() {
+		valuation := &models.ManualAssetValuation{}
+		if err := rows.Scan(
+			&valuation.ID,
+			&valuation.AssetID,
+			&valuation.ValuationDate,
+			&valuation.Value,
+			&valuation.Note,
+			&valuation.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan manual asset valuation: %w", err)
+		}
+		valuations = append(valuations, valuation)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating manual asset valuations: %w", err)
+	}
+
+	return valuations, nil
+}
+
+// DeleteValuation 刪除估值記錄
+func (r *manualAssetRepository) DeleteValuation(assetID, valuationID uuid.UUID) error {
+	query := `DELETE FROM manual_asset_valuations WHERE id = $1 AND asset_id = $2`
+	result, err := r.db.Exec(query, valuationID, assetID)
+	if err != nil {
+		return fmt.Errorf("failed to delete manual asset valuation: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/chienchuanw/asset-manager/internal/models"
+	"github.com/google/uuid"
+)
+
+// OptionContractRepository 選擇權合約資料存取介面
+type OptionContractRepository interface {
+	Create(input *models.CreateOptionContractInput) (*models.OptionContract, error)
+	GetByID(id uuid.UUID) (*models.OptionContract, error)
+	GetBySymbol(symbol string) (*models.OptionContract, error)
+	GetAll() ([]*models.OptionContract, error)
+	Delete(id uuid.UUID) error
+}
+
+// optionContractRepository 選擇權合約資料存取實作
+type optionContractRepository struct {
+	db *sql.DB
+}
+
+// NewOptionContractRepository 建立新的選擇權合約 repository
+func NewOptionContractRepository(db *sql.DB) OptionContractRepository {
+	return &optionContractRepository{db: db}
+}
+
+// optionContractColumns 選擇權合約查詢欄位
+const optionContractColumns = `id, symbol, underlying_symbol, option_type, strike_price, expiry_date, multiplier, created_at, updated_at`
+
+// scanOptionContract 掃描選擇權合約資料（輔助函式）
+func scanOptionContract(scanner rowScanner) (*models.OptionContract, error) {
+	contract := &models.OptionContract{}
+	err := scanner.Scan(
+		&contract.ID,
+		&contract.Symbol,
+		&contract.UnderlyingSymbol,
+		&contract.OptionType,
+		&contract.StrikePrice,
+		&contract.ExpiryDate,
+		&contract.Multiplier,
+		&contract.CreatedAt,
+		&contract.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return contract, nil
+}
+
+// Create 建立新的選擇權合約
+func (r *optionContractRepository) Create(input *models.CreateOptionContractInput) (*models.OptionContract, error) {
+	multiplier := models.DefaultOptionMultiplier
+	if input.Multiplier != nil {
+		multiplier = *input.Multiplier
+	}
+
+	query := `
+		INSERT INTO option_contracts (symbol, underlying_symbol, option_type, strike_price, expiry_date, multiplier)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING ` + optionContractColumns
+
+	contract, err := scanOptionContract(r.db.QueryRow(
+		query,
+		input.Symbol,
+		input.UnderlyingSymbol,
+		input.OptionType,
+		input.StrikePrice,
+		input.ExpiryDate,
+		multiplier,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create option contract: %w", err)
+	}
+
+	return contract, nil
+}
+
+// GetByID 根據 ID 取得選擇權合約
+func (r *optionContractRepository) GetByID(id uuid.UUID) (*models.OptionContract, error) {
+	query := `SELECT ` + optionContractColumns + ` FROM option_contracts WHERE id = $1`
+
+	contract, err := scanOptionContract(r.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("option contract not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get option contract: %w", err)
+	}
+
+	return contract, nil
+}
+
+// GetBySymbol 根據合約代碼取得選擇權合約
+func (r *optionContractRepository) GetBySymbol(symbol string) (*models.OptionContract, error) {
+	query := `SELECT ` + optionContractColumns + ` FROM option_contracts WHERE symbol = $1`
+
+	contract, err := scanOptionContract(r.db.QueryRow(query, symbol))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("option contract %s not found", symbol)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get option contract: %w", err)
+	}
+
+	return contract, nil
+}
+
+// GetAll 取得所有選擇權合約（依到期日排序）
+func (r *optionContractRepository) GetAll() ([]*models.OptionContract, error) {
+	query := `SELECT ` + optionContractColumns + ` FROM option_contracts ORDER BY expiry_date, symbol`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get option contracts: %w", err)
+	}
+	defer rows.Close()
+
+	contracts := []*models.OptionContract{}
+	for rows.Next() {
+		contract, err := scanOptionContract(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan option contract: %w", err)
+		}
+		contracts = append(contracts, contract)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating option contracts: %w", err)
+	}
+
+	return contracts, nil
+}
+
+// Delete 刪除選擇權合約
+func (r *optionContractRepository) Delete(id uuid.UUID) error {
+	result, err := r.db.Exec(`DELETE FROM option_contracts WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete option contract: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("option contract not found")
+	}
+
+	return nil
+}
@@ -0,0 +1,460 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/chienchuanw/asset-manager/internal/models"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// RecurringTemplateRepository 定期收支範本資料存取介面
+type RecurringTemplateRepository interface {
+	Create(input *models.CreateRecurringTemplateInput) (*models.RecurringTemplate, error)
+	GetByID(id uuid.UUID) (*models.RecurringTemplate, error)
+	List(filters RecurringTemplateFilters) ([]*models.RecurringTemplate, error)
+	Update(id uuid.UUID, input *models.UpdateRecurringTemplateInput) (*models.RecurringTemplate, error)
+	Delete(id uuid.UUID) error
+	GetActive(date time.Time) ([]*models.RecurringTemplate, error)
+	AddAmountChange(templateID uuid.UUID, input *models.CreateRecurringAmountChangeInput) (*models.RecurringAmountChange, error)
+	GetAmountChanges(templateID uuid.UUID) ([]*models.RecurringAmountChange, error)
+	DeleteAmountChange(templateID, changeID uuid.UUID) error
+}
+
+// RecurringTemplateFilters 定期收支範本查詢篩選條件
+type RecurringTemplateFilters struct {
+	Status *models.RecurringTemplateStatus `json:"status,omitempty"`
+	Type   *models.CashFlowType            `json:"type,omitempty"`
+	Limit  int                             `json:"limit,omitempty"`
+	Offset int                             `json:"offset,omitempty"`
+}
+
+// recurringTemplateRepository 定期收支範本資料存取實作
+type recurringTemplateRepository struct {
+	db *sql.DB
+}
+
+// NewRecurringTemplateRepository 建立新的定期收支範本 repository
+func NewRecurringTemplateRepository(db *sql.DB) RecurringTemplateRepository {
+	return &recurringTemplateRepository{db: db}
+}
+
+// recurringTemplateColumns 定期收支範本查詢欄位（與 scanRecurringTemplate 的順序一致）
+const recurringTemplateColumns = `
+	t.id, t.name, t.type, t.category_id, t.amount, t.currency, t.payment_method,
+	t.account_id, t.target_type, t.target_id, t.frequency, t.interval_count,
+	t.by_weekdays, t.by_month_day, t.by_months, t.business_day_adjustment,
+	t.start_date, t.end_date, t.status, t.note, t.created_at, t.updated_at`
+
+// scanRecurringTemplate 掃描定期收支範本資料（輔助函式）
+func scanRecurringTemplate(scanner rowScanner) (*models.RecurringTemplate, error) {
+	template := &models.RecurringTemplate{}
+	var byWeekdays, byMonths pq.Int64Array
+	err := scanner.Scan(
+		&template.ID,
+		&template.Name,
+		&template.Type,
+		&template.CategoryID,
+		&template.Amount,
+		&template.Currency,
+		&template.PaymentMethod,
+		&template.AccountID,
+		&template.TargetType,
+		&template.TargetID,
+		&template.Frequency,
+		&template.Interval,
+		&byWeekdays,
+		&template.ByMonthDay,
+		&byMonths,
+		&template.BusinessDayAdjustment,
+		&template.StartDate,
+		&template.EndDate,
+		&template.Status,
+		&template.Note,
+		&template.CreatedAt,
+		&template.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	template.ByWeekdays = fromInt64Array(byWeekdays)
+	template.ByMonths = fromInt64Array(byMonths)
+	return template, nil
+}
+
+// scanRecurringTemplates 掃描多筆定期收支範本資料
+func scanRecurringTemplates(rows *sql.Rows) ([]*models.RecurringTemplate, error) {
+	templates := []*models.RecurringTemplate{}
+	for rows.Next() {
+		template, err := scanRecurringTemplate(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan recurring template: %w", err)
+		}
+		templates = append(templates, template)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating recurring templates: %w", err)
+	}
+
+	return templates, nil
+}
+
+// toInt64Array 將整數切片轉為 PostgreSQL 整數陣列
+func toInt64Array(values []int) pq.Int64Array {
+	array := pq.Int64Array{}
+	for _, v := range values {
+		array = append(array, int64(v))
+	}
+	return array
+}
+
+// fromInt64Array 將 PostgreSQL 整數陣列轉為整數切片
+func fromInt64Array(array pq.Int64Array) []int {
+	values := []int{}
+	for _, v := range array {
+		values = append(values, int(v))
+	}
+	return values
+}
+
+// Create 建立新的定期收支範本
+func (r *recurringTemplateRepository) Create(input *models.CreateRecurringTemplateInput) (*models.RecurringTemplate, error) {
+	query := `
+		INSERT INTO recurring_templates AS t (
+			name, type, category_id, amount, currency, payment_method, account_id,
+			target_type, target_id, frequency, interval_count, by_weekdays, by_month_day,
+			by_months, business_day_adjustment, start_date, end_date, note
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
+		RETURNING ` + recurringTemplateColumns
+
+	template, err := scanRecurringTemplate(r.db.QueryRow(
+		query,
+		input.Name,
+		input.Type,
+		input.CategoryID,
+		input.Amount,
+		input.Currency,
+		input.PaymentMethod,
+		input.AccountID,
+		input.TargetType,
+		input.TargetID,
+		input.Frequency,
+		input.Interval,
+		toInt64Array(input.ByWeekdays),
+		input.ByMonthDay,
+		toInt64Array(input.ByMonths),
+		input.BusinessDayAdjustment,
+		input.StartDate,
+		input.EndDate,
+		input.Note,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create recurring template: %w", err)
+	}
+
+	return template, nil
+}
+
+// GetByID 根據 ID 取得定期收支範本
+func (r *recurringTemplateRepository) GetByID(id uuid.UUID) (*models.RecurringTemplate, error) {
+	query := `SELECT ` + recurringTemplateColumns + ` FROM recurring_templates t WHERE t.id = $1`
+
+	template, err := scanRecurringTemplate(r.db.QueryRow(query, id))
+	if err != nil {
+		return nil, err
+	}
+
+	return template, nil
+}
+
+// List 取得定期收支範本列表
+func (r *recurringTemplateRepository) List(filters RecurringTemplateFilters) ([]*models.RecurringTemplate, error) {
+	query := `SELECT ` + recurringTemplateColumns + ` FROM recurring_templates t WHERE 1=1`
+
+	args := []interface{}{}
+	argCount := 1
+
+	if filters.Status != nil {
+		query += fmt.Sprintf(" AND t.status = $%d", argCount)
+		args = append(args, *filters.Status)
+		argCount++
+	}
+
+	if filters.Type != nil {
+		query += fmt.Sprintf(" AND t.type = $%d", argCount)
+		args = append(args, *filters.Type)
+		argCount++
+	}
+
+	query += " ORDER BY t.created_at DESC"
+
+	if filters.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT $%d", argCount)
+		args = append(args, filters.Limit)
+		argCount++
+	}
+
+	if filters.Offset > 0 {
+		query += fmt.Sprintf(" OFFSET $%d", argCount)
+		args = append(args, filters.Offset)
+		argCount++
+	}
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list recurring templates: %w", err)
+	}
+	defer rows.Close()
+
+	return scanRecurringTemplates(rows)
+}
+
+// Update 更新定期收支範本
+func (r *recurringTemplateRepository) Update(id uuid.UUID, input *models.UpdateRecurringTemplateInput) (*models.RecurringTemplate, error) {
+	updates := []string{}
+	args := []interface{}{}
+	argCount := 1
+
+	if input.Name != nil {
+		updates = append(updates, fmt.Sprintf("name = $%d", argCount))
+		args = append(args, *input.Name)
+		argCount++
+	}
+
+	if input.CategoryID != nil {
+		updates = append(updates, fmt.Sprintf("category_id = $%d", argCount))
+		args = append(args, *input.CategoryID)
+		argCount++
+	}
+
+	if input.Amount != nil {
+		updates = append(updates, fmt.Sprintf("amount = $%d", argCount))
+		args = append(args, *input.Amount)
+		argCount++
+	}
+
+	if input.PaymentMethod != nil {
+		updates = append(updates, fmt.Sprintf("payment_method = $%d", argCount))
+		args = append(args, *input.PaymentMethod)
+		argCount++
+	}
+
+	if input.AccountID != nil {
+		updates = append(updates, fmt.Sprintf("account_id = $%d", argCount))
+		args = append(args, *input.AccountID)
+		argCount++
+	}
+
+	if input.TargetType != nil {
+		updates = append(updates, fmt.Sprintf("target_type = $%d", argCount))
+		args = append(args, *input.TargetType)
+		argCount++
+	}
+
+	if input.TargetID != nil {
+		updates = append(updates, fmt.Sprintf("target_id = $%d", argCount))
+		args = append(args, *input.TargetID)
+		argCount++
+	}
+
+	if input.Frequency != nil {
+		updates = append(updates, fmt.Sprintf("frequency = $%d", argCount))
+		args = append(args, *input.Frequency)
+		argCount++
+	}
+
+	if input.Interval != nil {
+		updates = append(updates, fmt.Sprintf("interval_count = $%d", argCount))
+		args = append(args, *input.Interval)
+		argCount++
+	}
+
+	if input.ByWeekdays != nil {
+		updates = append(updates, fmt.Sprintf("by_weekdays = $%d", argCount))
+		args = append(args, toInt64Array(*input.ByWeekdays))
+		argCount++
+	}
+
+	if input.ByMonthDay != nil {
+		updates = append(updates, fmt.Sprintf("by_month_day = $%d", argCount))
+		args = append(args, *input.ByMonthDay)
+		argCount++
+	}
+
+	if input.ByMonths != nil {
+		updates = append(updates, fmt.Sprintf("by_months = $%d", argCount))
+		args = append(args, toInt64Array(*input.ByMonths))
+		argCount++
+	}
+
+	if input.BusinessDayAdjustment != nil {
+		updates = append(updates, fmt.Sprintf("business_day_adjustment = $%d", argCount))
+		args = append(args, *input.BusinessDayAdjustment)
+		argCount++
+	}
+
+	if input.EndDate != nil {
+		updates = append(updates, fmt.Sprintf("end_date = $%d", argCount))
+		args = append(args, *input.EndDate)
+		argCount++
+	}
+
+	if input.Status != nil {
+		updates = append(updates, fmt.Sprintf("status = $%d", argCount))
+		args = append(args, *input.Status)
+		argCount++
+	}
+
+	if input.Note != nil {
+		updates = append(updates, fmt.Sprintf("note = $%d", argCount))
+		args = append(args, *input.Note)
+		argCount++
+	}
+
+	if len(updates) == 0 {
+		return r.GetByID(id)
+	}
+
+	args = append(args, id)
+
+	query := fmt.Sprintf(`
+		UPDATE recurring_templates AS t
+		SET %s, updated_at = CURRENT_TIMESTAMP
+		WHERE t.id = $%d
+		RETURNING %s
+	`, strings.Join(updates, ", "), argCount, recurringTemplateColumns)
+
+	template, err := scanRecurringTemplate(r.db.QueryRow(query, args...))
+	if err != nil {
+		return nil, fmt.Errorf("failed to update recurring template: %w", err)
+	}
+
+	return template, nil
+}
+
+// Delete 刪除定期收支範本
+func (r *recurringTemplateRepository) Delete(id uuid.UUID) error {
+	query := `DELETE FROM recurring_templates WHERE id = $1`
+	result, err := r.db.Exec(query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete recurring template: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// GetActive 取得指定日期可能發生的啟用中範本
+// 營業日調整可能讓發生日前後移動，因此開始日與結束日各放寬數天，實際發生日由模型判斷
+func (r *recurringTemplateRepository) GetActive(date time.Time) ([]*models.RecurringTemplate, error) {
+	query := `
+		SELECT ` + recurringTemplateColumns + `
+		FROM recurring_templates t
+		WHERE t.status = $1
+			AND t.start_date <= $2
+			AND (t.end_date IS NULL OR t.end_date >= $3)
+		ORDER BY t.name
+	`
+
+	rows, err := r.db.Query(query, models.RecurringTemplateStatusActive, date.AddDate(0, 0, 3), date.AddDate(0, 0, -3))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active recurring templates: %w", err)
+	}
+	defer rows.Close()
+
+	return scanRecurringTemplates(rows)
+}
+
+// AddAmountChange 新增金額調整記錄（同一生效日重複新增時覆寫金額）
+func (r *recurringTemplateRepository) AddAmountChange(templateID uuid.UUID, input *models.CreateRecurringAmountChangeInput) (*models.RecurringAmountChange, error) {
+	query := `
+		INSERT INTO recurring_template_amount_changes (template_id, effective_date, amount)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (template_id, effective_date) DO UPDATE SET amount = EXCLUDED.amount
+		RETURNING id, template_id, effective_date, amount, created_at
+	`
+
+	change := &models.RecurringAmountChange{}
+	err := r.db.QueryRow(query, templateID, input.EffectiveDate, input.Amount).Scan(
+		&change.ID,
+		&change.TemplateID,
+		&change.EffectiveDate,
+		&change.Amount,
+		&change.CreatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add recurring amount change: %w", err)
+	}
+
+	return change, nil
+}
+
+// GetAmountChanges 取得範本的金額調整記錄（依生效日排序）
+func (r *recurringTemplateRepository) GetAmountChanges(templateID uuid.UUID) ([]*models.RecurringAmountChange, error) {
+	query := `
+		SELECT id, template_id, effective_date, amount, created_at
+		FROM recurring_template_amount_changes
+		WHERE template_id = $1
+		ORDER BY effective_date
+	`
+
+	rows, err := r.db.Query(query, templateID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recurring amount changes: %w", err)
+	}
+	defer rows.Close()
+
+	changes := []*models.RecurringAmountChange{}
+	for rows.Next() {
+		change := &models.RecurringAmountChange{}
+		if err := rows.Scan(
+			&change.ID,
+			&change.TemplateID,
+			&change.EffectiveDate,
+			&change.Amount,
+			&change.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan recurring amount change: %w", err)
+		}
+		changes = append(changes, change)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating recurring amount changes: %w", err)
+	}
+
+	return changes, nil
+}
+
+// DeleteAmountChange 刪除金額調整記錄
+func (r *recurringTemplateRepository) DeleteAmountChange(templateID, changeID uuid.UUID) error {
+	query := `DELETE FROM recurring_template_amount_changes WHERE id = $1 AND template_id = $2`
+	result, err := r.db.Exec(query, changeID, templateID)
+	if err != nil {
+		return fmt.Errorf("failed to delete recurring amount change: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
@@ -19,6 +19,14 @@ type SubscriptionRepository interface {
 	Delete(id uuid.UUID) error
 	GetDueBillings(date time.Time) ([]*models.Subscription, error)
 	GetExpiringSoon(days int) ([]*models.Subscription, error)
+	AddPriceChange(subscriptionID uuid.UUID, input *models.CreateSubscriptionPriceChangeInput) (*models.SubscriptionPriceChange, error)
+	// UpdateWithPriceChanges 在同一個資料庫交易中新增價格調整記錄並更新訂閱，任一步驟失敗時全部回滾
+	// input 為 nil 時只新增價格調整記錄，回傳的訂閱為 nil
+	UpdateWithPriceChanges(id uuid.UUID, input *models.UpdateSubscriptionInput, changes []*models.CreateSubscriptionPriceChangeInput) (*models.Subscription, []*models.SubscriptionPriceChange, error)
+	GetPriceChanges(subscriptionID uuid.UUID) ([]*models.SubscriptionPriceChange, error)
+	AddUsageNote(subscriptionID uuid.UUID, input *models.CreateSubscriptionUsageNoteInput) (*models.SubscriptionUsageNote, error)
+	GetUsageNotes(subscriptionID uuid.UUID) ([]*models.SubscriptionUsageNote, error)
+	GetLastUsageDates() (map[uuid.UUID]time.Time, error)
 }
 
 // SubscriptionFilters 訂閱查詢篩選條件
@@ -240,6 +248,57 @@ func (r *subscriptionRepository) List(filters SubscriptionFilters) ([]*models.Su
 
 // Update 更新訂閱
 func (r *subscriptionRepository) Update(id uuid.UUID, input *models.UpdateSubscriptionInput) (*models.Subscription, error) {
+	query, args := buildSubscriptionUpdate(id, input)
+	if query == "" {
+		return r.GetByID(id)
+	}
+
+	return updateSubscription(r.db, query, args)
+}
+
+// UpdateWithPriceChanges 在同一個資料庫交易中新增價格調整記錄並更新訂閱
+func (r *subscriptionRepository) UpdateWithPriceChanges(id uuid.UUID, input *models.UpdateSubscriptionInput, changes []*models.CreateSubscriptionPriceChangeInput) (*models.Subscription, []*models.SubscriptionPriceChange, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	saved := make([]*models.SubscriptionPriceChange, 0, len(changes))
+	for _, change := range changes {
+		priceChange, err := addSubscriptionPriceChange(tx, id, change)
+		if err != nil {
+			return nil, nil, err
+		}
+		saved = append(saved, priceChange)
+	}
+
+	var subscription *models.Subscription
+	if input != nil {
+		query, args := buildSubscriptionUpdate(id, input)
+		if query != "" {
+			if subscription, err = updateSubscription(tx, query, args); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	// 沒有需要更新的欄位時回傳目前的訂閱資料
+	if input != nil && subscription == nil {
+		if subscription, err = r.GetByID(id); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return subscription, saved, nil
+}
+
+// buildSubscriptionUpdate 建立訂閱的動態更新語句，沒有需要更新的欄位時回傳空字串
+func buildSubscriptionUpdate(id uuid.UUID, input *models.UpdateSubscriptionInput) (string, []interface{}) {
 	// 建立動態更新語句
 	updates := []string{}
 	args := []interface{}{}
@@ -312,7 +371,7 @@ func (r *subscriptionRepository) Update(id uuid.UUID, input *models.UpdateSubscr
 	}
 
 	if len(updates) == 0 {
-		return r.GetByID(id)
+		return "", nil
 	}
 
 	// 加入 ID 參數
@@ -327,8 +386,13 @@ func (r *subscriptionRepository) Update(id uuid.UUID, input *models.UpdateSubscr
 			created_at, updated_at
 	`, strings.Join(updates, ", "), argCount)
 
+	return query, args
+}
+
+// updateSubscription 執行訂閱更新語句（Update 與 UpdateWithPriceChanges 共用）
+func updateSubscription(q queryRower, query string, args []interface{}) (*models.Subscription, error) {
 	subscription := &models.Subscription{}
-	err := r.db.QueryRow(query, args...).Scan(
+	err := q.QueryRow(query, args...).Scan(
 		&subscription.ID,
 		&subscription.Name,
 		&subscription.Amount,
@@ -513,3 +577,163 @@ func (r *subscriptionRepository) GetExpiringSoon(days int) ([]*models.Subscripti
 
 	return subscriptions, nil
 }
+
+// AddPriceChange 新增價格調整記錄（同一生效日重複新增時覆寫金額與備註）
+func (r *subscriptionRepository) AddPriceChange(subscriptionID uuid.UUID, input *models.CreateSubscriptionPriceChangeInput) (*models.SubscriptionPriceChange, error) {
+	return addSubscriptionPriceChange(r.db, subscriptionID, input)
+}
+
+// addSubscriptionPriceChange 新增價格調整記錄（AddPriceChange 與 UpdateWithPriceChanges 共用）
+func addSubscriptionPriceChange(q queryRower, subscriptionID uuid.UUID, input *models.CreateSubscriptionPriceChangeInput) (*models.SubscriptionPriceChange, error) {
+	query := `
+		INSERT INTO subscription_price_changes (subscription_id, effective_date, amount, note)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (subscription_id, effective_date) DO UPDATE
+			SET amount = EXCLUDED.amount, note = EXCLUDED.note
+		RETURNING id, subscription_id, effective_date, amount, note, created_at
+	`
+
+	change := &models.SubscriptionPriceChange{}
+	err := q.QueryRow(query, subscriptionID, input.EffectiveDate, input.Amount, input.Note).Scan(
+		&change.ID,
+		&change.SubscriptionID,
+		&change.EffectiveDate,
+		&change.Amount,
+		&change.Note,
+		&change.CreatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add subscription price change: %w", err)
+	}
+
+	return change, nil
+}
+
+// GetPriceChanges 取得訂閱的價格調整記錄（依生效日排序）
+func (r *subscriptionRepository) GetPriceChanges(subscriptionID uuid.UUID) ([]*models.SubscriptionPriceChange, error) {
+	query := `
+		SELECT id, subscription_id, effective_date, amount, note, created_at
+		FROM subscription_price_changes
+		WHERE subscription_id = $1
+		ORDER BY effective_date
+	`
+
+	rows, err := r.db.Query(query, subscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subscription price changes: %w", err)
+	}
+	defer rows.Close()
+
+	changes := []*models.SubscriptionPriceChange{}
+	for rows.Next() {
+		change := &models.SubscriptionPriceChange{}
+		if err := rows.Scan(
+			&change.ID,
+			&change.SubscriptionID,
+			&change.EffectiveDate,
+			&change.Amount,
+			&change.Note,
+			&change.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan subscription price change: %w", err)
+		}
+		changes = append(changes, change)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating subscription price changes: %w", err)
+	}
+
+	return changes, nil
+}
+
+// AddUsageNote 新增使用記錄
+func (r *subscriptionRepository) AddUsageNote(subscriptionID uuid.UUID, input *models.CreateSubscriptionUsageNoteInput) (*models.SubscriptionUsageNote, error) {
+	query := `
+		INSERT INTO subscription_usage_notes (subscription_id, used_on, note)
+		VALUES ($1, $2, $3)
+		RETURNING id, subscription_id, used_on, note, created_at
+	`
+
+	note := &models.SubscriptionUsageNote{}
+	err := r.db.QueryRow(query, subscriptionID, input.UsedOn, input.Note).Scan(
+		&note.ID,
+		&note.SubscriptionID,
+		&note.UsedOn,
+		&note.Note,
+		&note.CreatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add subscription usage note: %w", err)
+	}
+
+	return note, nil
+}
+
+// GetUsageNotes 取得訂閱的使用記錄（最新的在前）
+func (r *subscriptionRepository) GetUsageNotes(subscriptionID uuid.UUID) ([]*models.SubscriptionUsageNote, error) {
+	query := `
+		SELECT id, subscription_id, used_on, note, created_at
+		FROM subscription_usage_notes
+		WHERE subscription_id = $1
+		ORDER BY used_on DESC, created_at DESC
+	`
+
+	rows, err := r.db.Query(query, subscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subscription usage notes: %w", err)
+	}
+	defer rows.Close()
+
+	notes := []*models.SubscriptionUsageNote{}
+	for rows.Next() {
+		note := &models.SubscriptionUsageNote{}
+		if err := rows.Scan(
+			&note.ID,
+			&note.SubscriptionID,
+			&note.UsedOn,
+			&note.Note,
+			&note.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan subscription usage note: %w", err)
+		}
+		notes = append(notes, note)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating subscription usage notes: %w", err)
+	}
+
+	return notes, nil
+}
+
+// GetLastUsageDates 取得每個訂閱最近一次的使用日期
+func (r *subscriptionRepository) GetLastUsageDates() (map[uuid.UUID]time.Time, error) {
+	query := `
+		SELECT subscription_id, MAX(used_on)
+		FROM subscription_usage_notes
+		GROUP BY subscription_id
+	`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subscription last usage dates: %w", err)
+	}
+	defer rows.Close()
+
+	lastUsed := make(map[uuid.UUID]time.Time)
+	for rows.Next() {
+		var subscriptionID uuid.UUID
+		var usedOn time.Time
+		if err := rows.Scan(&subscriptionID, &usedOn); err != nil {
+			return nil, fmt.Errorf("failed to scan subscription last usage date: %w", err)
+		}
+		lastUsed[subscriptionID] = usedOn
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating subscription last usage dates: %w", err)
+	}
+
+	return lastUsed, nil
+}
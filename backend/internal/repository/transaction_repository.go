@@ -25,13 +25,15 @@ type TransactionRepository interface {
 
 // TransactionFilters 查詢篩選條件
 type TransactionFilters struct {
-	AssetType       *models.AssetType       `json:"asset_type,omitempty"`
-	TransactionType *models.TransactionType `json:"transaction_type,omitempty"`
-	Symbol          *string                 `json:"symbol,omitempty"`
-	StartDate       *time.Time              `json:"start_date,omitempty"`
-	EndDate         *time.Time              `json:"end_date,omitempty"`
-	Limit           int                     `json:"limit,omitempty"`
-	Offset          int                     `json:"offset,omitempty"`
+	AssetType          *models.AssetType       `json:"asset_type,omitempty"`
+	TransactionType    *models.TransactionType `json:"transaction_type,omitempty"`
+	Symbol             *string                 `json:"symbol,omitempty"`
+	BrokerageAccountID *uuid.UUID              `json:"brokerage_account_id,omitempty"`
+	CustodyAccountID   *uuid.UUID              `json:"custody_account_id,omitempty"` // 轉出或轉入皆符合
+	StartDate          *time.Time              `json:"start_date,omitempty"`
+	EndDate            *time.Time              `json:"end_date,omitempty"`
+	Limit              int                     `json:"limit,omitempty"`
+	Offset             int                     `json:"offset,omitempty"`
 }
 
 // transactionRepository 交易記錄資料存取實作
@@ -52,9 +54,9 @@ func (r *transactionRepository) DB() *sql.DB {
 // Create 建立新的交易記錄
 func (r *transactionRepository) Create(input *models.CreateTransactionInput) (*models.Transaction, error) {
 	query := `
-		INSERT INTO transactions (date, asset_type, symbol, name, transaction_type, quantity, price, amount, fee, tax, currency, note)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
-		RETURNING id, date, asset_type, symbol, name, transaction_type, quantity, price, amount, fee, tax, currency, exchange_rate_id, note, created_at, updated_at
+		INSERT INTO transactions (date, asset_type, symbol, name, transaction_type, quantity, price, amount, fee, tax, currency, brokerage_account_id, custody_account_id, to_custody_account_id, network_fee, swap_id, note)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+		RETURNING id, date, asset_type, symbol, name, transaction_type, quantity, price, amount, fee, tax, currency, exchange_rate_id, brokerage_account_id, custody_account_id, to_custody_account_id, network_fee, swap_id, note, created_at, updated_at
 	`
 
 	transaction := &models.Transaction{}
@@ -71,6 +73,11 @@ func (r *transactionRepository) Create(input *models.CreateTransactionInput) (*m
 		input.Fee,
 		input.Tax,
 		input.Currency,
+		input.BrokerageAccountID,
+		input.CustodyAccountID,
+		input.ToCustodyAccountID,
+		input.NetworkFee,
+		input.SwapID,
 		input.Note,
 	).Scan(
 		&transaction.ID,
@@ -86,6 +93,11 @@ func (r *transactionRepository) Create(input *models.CreateTransactionInput) (*m
 		&transaction.Tax,
 		&transaction.Currency,
 		&transaction.ExchangeRateID,
+		&transaction.BrokerageAccountID,
+		&transaction.CustodyAccountID,
+		&transaction.ToCustodyAccountID,
+		&transaction.NetworkFee,
+		&transaction.SwapID,
 		&transaction.Note,
 		&transaction.CreatedAt,
 		&transaction.UpdatedAt,
@@ -101,9 +113,9 @@ func (r *transactionRepository) Create(input *models.CreateTransactionInput) (*m
 // CreateTx 在指定的資料庫交易中建立新的交易記錄
 func (r *transactionRepository) CreateTx(tx *sql.Tx, input *models.CreateTransactionInput) (*models.Transaction, error) {
 	query := `
-		INSERT INTO transactions (date, asset_type, symbol, name, transaction_type, quantity, price, amount, fee, tax, currency, note)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
-		RETURNING id, date, asset_type, symbol, name, transaction_type, quantity, price, amount, fee, tax, currency, exchange_rate_id, note, created_at, updated_at
+		INSERT INTO transactions (date, asset_type, symbol, name, transaction_type, quantity, price, amount, fee, tax, currency, brokerage_account_id, custody_account_id, to_custody_account_id, network_fee, swap_id, note)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+		RETURNING id, date, asset_type, symbol, name, transaction_type, quantity, price, amount, fee, tax, currency, exchange_rate_id, brokerage_account_id, custody_account_id, to_custody_account_id, network_fee, swap_id, note, created_at, updated_at
 	`
 
 	transaction := &models.Transaction{}
@@ -120,6 +132,11 @@ func (r *transactionRepository) CreateTx(tx *sql.Tx, input *models.CreateTransac
 		input.Fee,
 		input.Tax,
 		input.Currency,
+		input.BrokerageAccountID,
+		input.CustodyAccountID,
+		input.ToCustodyAccountID,
+		input.NetworkFee,
+		input.SwapID,
 		input.Note,
 	).Scan(
 		&transaction.ID,
@@ -135,6 +152,11 @@ func (r *transactionRepository) CreateTx(tx *sql.Tx, input *models.CreateTransac
 		&transaction.Tax,
 		&transaction.Currency,
 		&transaction.ExchangeRateID,
+		&transaction.BrokerageAccountID,
+		&transaction.CustodyAccountID,
+		&transaction.ToCustodyAccountID,
+		&transaction.NetworkFee,
+		&transaction.SwapID,
 		&transaction.Note,
 		&transaction.CreatedAt,
 		&transaction.UpdatedAt,
@@ -150,9 +172,9 @@ func (r *transactionRepository) CreateTx(tx *sql.Tx, input *models.CreateTransac
 // CreateWithExchangeRate 建立新的交易記錄（帶匯率 ID）
 func (r *transactionRepository) CreateWithExchangeRate(input *models.CreateTransactionInput, exchangeRateID int) (*models.Transaction, error) {
 	query := `
-		INSERT INTO transactions (date, asset_type, symbol, name, transaction_type, quantity, price, amount, fee, tax, currency, exchange_rate_id, note)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
-		RETURNING id, date, asset_type, symbol, name, transaction_type, quantity, price, amount, fee, tax, currency, exchange_rate_id, note, created_at, updated_at
+		INSERT INTO transactions (date, asset_type, symbol, name, transaction_type, quantity, price, amount, fee, tax, currency, exchange_rate_id, brokerage_account_id, custody_account_id, to_custody_account_id, network_fee, swap_id, note)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
+		RETURNING id, date, asset_type, symbol, name, transaction_type, quantity, price, amount, fee, tax, currency, exchange_rate_id, brokerage_account_id, custody_account_id, to_custody_account_id, network_fee, swap_id, note, created_at, updated_at
 	`
 
 	transaction := &models.Transaction{}
@@ -170,6 +192,11 @@ func (r *transactionRepository) CreateWithExchangeRate(input *models.CreateTrans
 		input.Tax,
 		input.Currency,
 		exchangeRateID,
+		input.BrokerageAccountID,
+		input.CustodyAccountID,
+		input.ToCustodyAccountID,
+		input.NetworkFee,
+		input.SwapID,
 		input.Note,
 	).Scan(
 		&transaction.ID,
@@ -185,6 +212,11 @@ func (r *transactionRepository) CreateWithExchangeRate(input *models.CreateTrans
 		&transaction.Tax,
 		&transaction.Currency,
 		&transaction.ExchangeRateID,
+		&transaction.BrokerageAccountID,
+		&transaction.CustodyAccountID,
+		&transaction.ToCustodyAccountID,
+		&transaction.NetworkFee,
+		&transaction.SwapID,
 		&transaction.Note,
 		&transaction.CreatedAt,
 		&transaction.UpdatedAt,
@@ -200,9 +232,9 @@ func (r *transactionRepository) CreateWithExchangeRate(input *models.CreateTrans
 // CreateWithExchangeRateTx 在指定的資料庫交易中建立新的交易記錄（帶匯率 ID）
 func (r *transactionRepository) CreateWithExchangeRateTx(tx *sql.Tx, input *models.CreateTransactionInput, exchangeRateID int) (*models.Transaction, error) {
 	query := `
-		INSERT INTO transactions (date, asset_type, symbol, name, transaction_type, quantity, price, amount, fee, tax, currency, exchange_rate_id, note)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
-		RETURNING id, date, asset_type, symbol, name, transaction_type, quantity, price, amount, fee, tax, currency, exchange_rate_id, note, created_at, updated_at
+		INSERT INTO transactions (date, asset_type, symbol, name, transaction_type, quantity, price, amount, fee, tax, currency, exchange_rate_id, brokerage_account_id, custody_account_id, to_custody_account_id, network_fee, swap_id, note)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
+		RETURNING id, date, asset_type, symbol, name, transaction_type, quantity, price, amount, fee, tax, currency, exchange_rate_id, brokerage_account_id, custody_account_id, to_custody_account_id, network_fee, swap_id, note, created_at, updated_at
 	`
 
 	transaction := &models.Transaction{}
@@ -220,6 +252,11 @@ func (r *transactionRepository) CreateWithExchangeRateTx(tx *sql.Tx, input *mode
 		input.Tax,
 		input.Currency,
 		exchangeRateID,
+		input.BrokerageAccountID,
+		input.CustodyAccountID,
+		input.ToCustodyAccountID,
+		input.NetworkFee,
+		input.SwapID,
 		input.Note,
 	).Scan(
 		&transaction.ID,
@@ -235,6 +272,11 @@ func (r *transactionRepository) CreateWithExchangeRateTx(tx *sql.Tx, input *mode
 		&transaction.Tax,
 		&transaction.Currency,
 		&transaction.ExchangeRateID,
+		&transaction.BrokerageAccountID,
+		&transaction.CustodyAccountID,
+		&transaction.ToCustodyAccountID,
+		&transaction.NetworkFee,
+		&transaction.SwapID,
 		&transaction.Note,
 		&transaction.CreatedAt,
 		&transaction.UpdatedAt,
@@ -250,7 +292,7 @@ func (r *transactionRepository) CreateWithExchangeRateTx(tx *sql.Tx, input *mode
 // GetByID 根據 ID 取得交易記錄
 func (r *transactionRepository) GetByID(id uuid.UUID) (*models.Transaction, error) {
 	query := `
-		SELECT id, date, asset_type, symbol, name, transaction_type, quantity, price, amount, fee, tax, currency, exchange_rate_id, note, created_at, updated_at
+		SELECT id, date, asset_type, symbol, name, transaction_type, quantity, price, amount, fee, tax, currency, exchange_rate_id, brokerage_account_id, custody_account_id, to_custody_account_id, network_fee, swap_id, note, created_at, updated_at
 		FROM transactions
 		WHERE id = $1
 	`
@@ -270,6 +312,11 @@ func (r *transactionRepository) GetByID(id uuid.UUID) (*models.Transaction, erro
 		&transaction.Tax,
 		&transaction.Currency,
 		&transaction.ExchangeRateID,
+		&transaction.BrokerageAccountID,
+		&transaction.CustodyAccountID,
+		&transaction.ToCustodyAccountID,
+		&transaction.NetworkFee,
+		&transaction.SwapID,
 		&transaction.Note,
 		&transaction.CreatedAt,
 		&transaction.UpdatedAt,
@@ -288,7 +335,7 @@ func (r *transactionRepository) GetByID(id uuid.UUID) (*models.Transaction, erro
 // GetAll 取得所有交易記錄（支援篩選）
 func (r *transactionRepository) GetAll(filters TransactionFilters) ([]*models.Transaction, error) {
 	query := `
-		SELECT id, date, asset_type, symbol, name, transaction_type, quantity, price, amount, fee, tax, currency, exchange_rate_id, note, created_at, updated_at
+		SELECT id, date, asset_type, symbol, name, transaction_type, quantity, price, amount, fee, tax, currency, exchange_rate_id, brokerage_account_id, custody_account_id, to_custody_account_id, network_fee, swap_id, note, created_at, updated_at
 		FROM transactions
 		WHERE 1=1
 	`
@@ -315,6 +362,18 @@ func (r *transactionRepository) GetAll(filters TransactionFilters) ([]*models.Tr
 		argCount++
 	}
 
+	if filters.BrokerageAccountID != nil {
+		query += fmt.Sprintf(" AND brokerage_account_id = $%d", argCount)
+		args = append(args, *filters.BrokerageAccountID)
+		argCount++
+	}
+
+	if filters.CustodyAccountID != nil {
+		query += fmt.Sprintf(" AND (custody_account_id = $%d OR to_custody_account_id = $%d)", argCount, argCount)
+		args = append(args, *filters.CustodyAccountID)
+		argCount++
+	}
+
 	if filters.StartDate != nil {
 		query += fmt.Sprintf(" AND date >= $%d", argCount)
 		args = append(args, *filters.StartDate)
@@ -366,6 +425,11 @@ func (r *transactionRepository) GetAll(filters TransactionFilters) ([]*models.Tr
 			&transaction.Tax,
 			&transaction.Currency,
 			&transaction.ExchangeRateID,
+			&transaction.BrokerageAccountID,
+			&transaction.CustodyAccountID,
+			&transaction.ToCustodyAccountID,
+			&transaction.NetworkFee,
+			&transaction.SwapID,
 			&transaction.Note,
 			&transaction.CreatedAt,
 			&transaction.UpdatedAt,
@@ -456,6 +520,30 @@ func (r *transactionRepository) Update(id uuid.UUID, input *models.UpdateTransac
 		argCount++
 	}
 
+	if input.BrokerageAccountID != nil {
+		setClauses = append(setClauses, fmt.Sprintf("brokerage_account_id = $%d", argCount))
+		args = append(args, *input.BrokerageAccountID)
+		argCount++
+	}
+
+	if input.CustodyAccountID != nil {
+		setClauses = append(setClauses, fmt.Sprintf("custody_account_id = $%d", argCount))
+		args = append(args, *input.CustodyAccountID)
+		argCount++
+	}
+
+	if input.ToCustodyAccountID != nil {
+		setClauses = append(setClauses, fmt.Sprintf("to_custody_account_id = $%d", argCount))
+		args = append(args, *input.ToCustodyAccountID)
+		argCount++
+	}
+
+	if input.NetworkFee != nil {
+		setClauses = append(setClauses, fmt.Sprintf("network_fee = $%d", argCount))
+		args = append(args, *input.NetworkFee)
+		argCount++
+	}
+
 	if input.Note != nil {
 		setClauses = append(setClauses, fmt.Sprintf("note = $%d", argCount))
 		args = append(args, *input.Note)
@@ -473,7 +561,7 @@ func (r *transactionRepository) Update(id uuid.UUID, input *models.UpdateTransac
 		UPDATE transactions
 		SET %s
 		WHERE id = $%d
-		RETURNING id, date, asset_type, symbol, name, transaction_type, quantity, price, amount, fee, tax, currency, exchange_rate_id, note, created_at, updated_at
+		RETURNING id, date, asset_type, symbol, name, transaction_type, quantity, price, amount, fee, tax, currency, exchange_rate_id, brokerage_account_id, custody_account_id, to_custody_account_id, network_fee, swap_id, note, created_at, updated_at
 	`, strings.Join(setClauses, ", "), argCount)
 
 	transaction := &models.Transaction{}
@@ -491,6 +579,11 @@ func (r *transactionRepository) Update(id uuid.UUID, input *models.UpdateTransac
 		&transaction.Tax,
 		&transaction.Currency,
 		&transaction.ExchangeRateID,
+		&transaction.BrokerageAccountID,
+		&transaction.CustodyAccountID,
+		&transaction.ToCustodyAccountID,
+		&transaction.NetworkFee,
+		&transaction.SwapID,
 		&transaction.Note,
 		&transaction.CreatedAt,
 		&transaction.UpdatedAt,
@@ -3,6 +3,7 @@ package scheduler
 import (
 	"fmt"
 	"log"
+	"strings"
 	"sync"
 	"time"
 
@@ -24,7 +25,10 @@ type SchedulerManager struct {
 	billingService           service.BillingService
 	exchangeRateService      service.ExchangeRateService
 	creditCardService        service.CreditCardService // 新增信用卡服務
+	statementService         service.CreditCardStatementService
 	cashFlowService          service.CashFlowService   // 新增現金流服務
+	forecastService          service.CashFlowForecastService
+	priceRefreshService      service.PriceRefreshService
 	cashFlowReportLogRepo    repository.CashFlowReportLogRepository
 	schedulerLogRepo         repository.SchedulerLogRepository
 	enabled                  bool
@@ -32,6 +36,8 @@ type SchedulerManager struct {
 	discordReportTime        string // 格式: "HH:MM" (例如: "09:00")
 	dailyBillingTime         string // 格式: "HH:MM" (例如: "00:01")
 	creditCardReminderTime   string // 格式: "HH:MM" (例如: "09:00")
+	weeklyForecastTime       string // 格式: "HH:MM"，每週一執行
+	forecastThreshold        float64
 	discordEnabled           bool
 	mu                       sync.RWMutex
 	snapshotJobID            cron.EntryID
@@ -40,12 +46,17 @@ type SchedulerManager struct {
 	creditCardReminderJobID  cron.EntryID // 新增信用卡提醒任務 ID
 	monthlyReportJobID       cron.EntryID // 月度現金流報告任務 ID
 	yearlyReportJobID        cron.EntryID // 年度現金流報告任務 ID
+	weeklyForecastJobID      cron.EntryID // 每週現金流預測任務 ID
+	priceRefreshJobID        cron.EntryID // 背景價格更新任務 ID
 }
 
 // SchedulerManagerConfig 排程器管理器配置
 type SchedulerManagerConfig struct {
 	Enabled           bool   // 是否啟用排程
 	DailySnapshotTime string // 每日快照時間 (格式: "HH:MM")
+
+	// ForecastLowBalanceThreshold 每週現金流預測的低餘額警示門檻（TWD）
+	ForecastLowBalanceThreshold float64
 }
 
 // NewSchedulerManager 建立新的排程器管理器
@@ -58,7 +69,10 @@ func NewSchedulerManager(
 	billingService service.BillingService,
 	exchangeRateService service.ExchangeRateService,
 	creditCardService service.CreditCardService,
+	statementService service.CreditCardStatementService,
 	cashFlowService service.CashFlowService,
+	forecastService service.CashFlowForecastService,
+	priceRefreshService service.PriceRefreshService,
 	schedulerLogRepo repository.SchedulerLogRepository,
 	cashFlowReportLogRepo repository.CashFlowReportLogRepository,
 	config SchedulerManagerConfig,
@@ -73,13 +87,18 @@ func NewSchedulerManager(
 		billingService:         billingService,
 		exchangeRateService:    exchangeRateService,
 		creditCardService:      creditCardService,
+		statementService:       statementService,
 		cashFlowService:        cashFlowService,
+		forecastService:        forecastService,
+		priceRefreshService:    priceRefreshService,
 		schedulerLogRepo:       schedulerLogRepo,
 		cashFlowReportLogRepo:  cashFlowReportLogRepo,
 		enabled:                config.Enabled,
 		dailySnapshotTime:      config.DailySnapshotTime,
 		dailyBillingTime:       "00:01", // 預設在每天 00:01 執行扣款
 		creditCardReminderTime: "09:00", // 預設在每天 09:00 執行信用卡提醒
+		weeklyForecastTime:     "09:00", // 預設在每週一 09:00 發送現金流預測
+		forecastThreshold:      config.ForecastLowBalanceThreshold,
 	}
 }
 
@@ -101,10 +120,12 @@ func (m *SchedulerManager) Start() error {
 		// 不返回錯誤，因為 Discord 報告是可選的
 	}
 
-	// 啟動每日扣款排程
+	// 啟動每日扣款排程，並在背景補跑停機期間漏掉的扣款日
 	if err := m.startDailyBillingSchedule(); err != nil {
 		log.Printf("Warning: Failed to start daily billing schedule: %v", err)
 		// 不返回錯誤，因為扣款排程是可選的
+	} else {
+		go m.runDailyBilling()
 	}
 
 	// 啟動信用卡繳款提醒排程
@@ -125,6 +146,20 @@ func (m *SchedulerManager) Start() error {
 		// 不返回錯誤，因為報告排程是可選的
 	}
 
+	// 啟動每週現金流預測排程
+	if err := m.startWeeklyForecastSchedule(); err != nil {
+		log.Printf("Warning: Failed to start weekly cash flow forecast schedule: %v", err)
+		// 不返回錯誤，因為預測排程是可選的
+	}
+
+	// 啟動背景價格更新排程，並在背景立即更新一次以填補快取
+	if err := m.startPriceRefreshSchedule(); err != nil {
+		log.Printf("Warning: Failed to start price refresh schedule: %v", err)
+		// 不返回錯誤，持倉查詢仍可使用最後已知價格
+	} else if m.priceRefreshService != nil {
+		go m.runPriceRefresh()
+	}
+
 	// 啟動 cron
 	m.cron.Start()
 	log.Println("Scheduler manager started successfully")
@@ -427,12 +462,35 @@ func (m *SchedulerManager) sendCreditCardPaymentReminder() error {
 		return nil
 	}
 
+	if m.statementService == nil {
+		return fmt.Errorf("credit card statement service is not configured")
+	}
+
+	// 取得各信用卡最近一期帳單，已繳清的不需提醒
+	statements := make([]*models.CreditCardStatement, 0, len(creditCards))
+	for _, card := range creditCards {
+		statement, err := m.statementService.GetCurrentStatement(card.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get current statement for credit card %s: %w", card.ID, err)
+		}
+		if statement == nil || statement.Status == models.CreditCardStatementStatusPaid {
+			continue
+		}
+		statement.CreditCard = card
+		statements = append(statements, statement)
+	}
+
+	if len(statements) == 0 {
+		log.Println("All credit card statements due tomorrow are paid")
+		return nil
+	}
+
 	// 發送 Discord 提醒
-	if err := m.discordService.SendCreditCardPaymentReminder(settings.Discord.WebhookURL, creditCards); err != nil {
+	if err := m.discordService.SendCreditCardPaymentReminder(settings.Discord.WebhookURL, statements); err != nil {
 		return fmt.Errorf("failed to send credit card payment reminder: %w", err)
 	}
 
-	log.Printf("Credit card payment reminder sent for %d card(s)", len(creditCards))
+	log.Printf("Credit card payment reminder sent for %d card(s)", len(statements))
 	return nil
 }
 
@@ -602,33 +660,7 @@ func (m *SchedulerManager) startDailyBillingSchedule() error {
 	cronExpr := fmt.Sprintf("%d %d * * *", minute, hour)
 
 	// 新增排程任務
-	jobID, err := m.cron.AddFunc(cronExpr, func() {
-		startTime := time.Now()
-		log.Printf("[%s] Starting daily billing process...", startTime.Format("2006-01-02 15:04:05"))
-
-		var taskErr error
-
-		// 執行每日扣款
-		result, err := m.billingService.ProcessDailyBilling(time.Now())
-		if err != nil {
-			log.Printf("Error processing daily billing: %v", err)
-			taskErr = err
-			m.sendFailureNotification("每日扣款處理", err)
-		} else {
-			log.Printf("Daily billing completed: %d subscriptions, %d installments, total: %.2f TWD",
-				result.SubscriptionCount,
-				result.InstallmentCount,
-				result.TotalAmount,
-			)
-
-			// 發送 Discord 通知（如果啟用）
-			m.sendDailyBillingNotification(result)
-		}
-
-		// 記錄執行結果
-		m.logTaskExecution("daily_billing", startTime, taskErr)
-	})
-
+	jobID, err := m.cron.AddFunc(cronExpr, m.runDailyBilling)
 	if err != nil {
 		return fmt.Errorf("failed to add daily billing job: %w", err)
 	}
@@ -641,6 +673,52 @@ func (m *SchedulerManager) startDailyBillingSchedule() error {
 	return nil
 }
 
+// runDailyBilling 執行每日扣款
+// 會補跑最近一次成功執行之後漏掉的扣款日（例如伺服器停機期間），已扣款的項目不會重複扣款
+func (m *SchedulerManager) runDailyBilling() {
+	startTime := time.Now()
+	log.Printf("[%s] Starting daily billing process...", startTime.Format("2006-01-02 15:04:05"))
+
+	var taskErr error
+
+	// 執行每日扣款（含補跑）
+	results, err := m.billingService.CatchUpBilling(startTime)
+	for i, result := range results {
+		log.Printf("Daily billing for %s completed: %d subscriptions, %d installments, %d loans, %d recurring, %d skipped, total: %.2f TWD",
+			result.Date.Format("2006-01-02"),
+			result.SubscriptionCount,
+			result.InstallmentCount,
+			result.LoanCount,
+			result.RecurringCount,
+			result.SkippedCount,
+			result.TotalAmount,
+		)
+
+		// 發送 Discord 通知（如果啟用）；補跑的日期只在有扣款時通知
+		isLatest := i == len(results)-1 && err == nil
+		if isLatest || result.SubscriptionCount+result.InstallmentCount+result.LoanCount+result.RecurringCount > 0 {
+			m.sendDailyBillingNotification(result)
+		}
+	}
+	if err != nil {
+		log.Printf("Error processing daily billing: %v", err)
+		taskErr = err
+		m.sendFailureNotification("每日扣款處理", err)
+	}
+
+	// 產生已結帳的信用卡帳單
+	if m.statementService != nil {
+		if count, err := m.statementService.GenerateAllStatements(time.Now()); err != nil {
+			log.Printf("Error generating credit card statements: %v", err)
+		} else {
+			log.Printf("Credit card statements generated: %d", count)
+		}
+	}
+
+	// 記錄執行結果
+	m.logTaskExecution("daily_billing", startTime, taskErr)
+}
+
 // sendDailyBillingNotification 發送每日扣款通知到 Discord
 func (m *SchedulerManager) sendDailyBillingNotification(result *service.DailyBillingResult) {
 	// 取得設定
@@ -915,6 +993,132 @@ func (m *SchedulerManager) startYearlyCashFlowReportSchedule() error {
 	return nil
 }
 
+// startWeeklyForecastSchedule 啟動每週現金流預測排程
+func (m *SchedulerManager) startWeeklyForecastSchedule() error {
+	if m.forecastService == nil {
+		return nil
+	}
+
+	// 解析時間
+	hour, minute, err := parseTime(m.weeklyForecastTime)
+	if err != nil {
+		return fmt.Errorf("invalid weekly forecast time format: %w", err)
+	}
+
+	// 建立 cron 表達式（每週一指定時間執行）
+	// 格式: "分 時 日 月 週"
+	cronExpr := fmt.Sprintf("%d %d * * 1", minute, hour)
+
+	// 註冊每週預測任務
+	jobID, err := m.cron.AddFunc(cronExpr, func() {
+		startTime := time.Now()
+		log.Printf("[%s] Running weekly cash flow forecast task...", startTime.Format("2006-01-02 15:04:05"))
+
+		taskErr := m.sendWeeklyForecast()
+		if taskErr != nil {
+			log.Printf("Error sending weekly cash flow forecast: %v", taskErr)
+			m.sendFailureNotification("每週現金流預測", taskErr)
+		} else {
+			log.Println("Weekly cash flow forecast sent successfully")
+		}
+
+		// 記錄執行結果
+		m.logTaskExecution("weekly_cash_flow_forecast", startTime, taskErr)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add weekly cash flow forecast cron job: %w", err)
+	}
+
+	// 更新狀態
+	m.mu.Lock()
+	m.weeklyForecastJobID = jobID
+	m.mu.Unlock()
+
+	log.Printf("Weekly cash flow forecast scheduled at %s every Monday (cron: %s)", m.weeklyForecastTime, cronExpr)
+	return nil
+}
+
+// startPriceRefreshSchedule 啟動背景價格更新排程
+// 每 5 分鐘檢查一次，實際是否向來源請求由各市場的交易時段與更新間隔決定
+func (m *SchedulerManager) startPriceRefreshSchedule() error {
+	if m.priceRefreshService == nil {
+		return nil
+	}
+
+	cronExpr := "*/5 * * * *"
+
+	jobID, err := m.cron.AddFunc(cronExpr, m.runPriceRefresh)
+	if err != nil {
+		return fmt.Errorf("failed to add price refresh cron job: %w", err)
+	}
+
+	m.mu.Lock()
+	m.priceRefreshJobID = jobID
+	m.mu.Unlock()
+
+	log.Printf("Price refresh schedule registered (cron: %s)", cronExpr)
+	return nil
+}
+
+// runPriceRefresh 更新到期的持倉價格（沒有到期的市場時不記錄執行結果）
+func (m *SchedulerManager) runPriceRefresh() {
+	startTime := time.Now()
+
+	result, err := m.priceRefreshService.RefreshDue(startTime)
+	if err != nil {
+		log.Printf("Error refreshing prices: %v", err)
+		m.logTaskExecution("price_refresh", startTime, err)
+		return
+	}
+
+	if len(result.Groups) == 0 {
+		return
+	}
+
+	for _, group := range result.Groups {
+		log.Printf("Price refresh %s (market open: %v): %d/%d symbols refreshed",
+			group.AssetType, group.MarketOpen, group.Refreshed, group.Symbols)
+	}
+
+	var taskErr error
+	if failed := result.FailedGroups(); len(failed) > 0 {
+		messages := make([]string, 0, len(failed))
+		for _, group := range failed {
+			messages = append(messages, fmt.Sprintf("%s: %s", group.AssetType, group.Error))
+		}
+		taskErr = fmt.Errorf("price refresh incomplete: %s", strings.Join(messages, "; "))
+	}
+
+	m.logTaskExecution("price_refresh", startTime, taskErr)
+}
+
+// sendWeeklyForecast 發送每週現金流預測摘要
+func (m *SchedulerManager) sendWeeklyForecast() error {
+	// 取得設定
+	settings, err := m.settingsService.GetSettings()
+	if err != nil {
+		return fmt.Errorf("failed to get settings: %w", err)
+	}
+
+	// 檢查 Discord 是否啟用
+	if !settings.Discord.Enabled || settings.Discord.WebhookURL == "" {
+		log.Println("Discord is disabled or webhook URL is not set, skipping weekly cash flow forecast")
+		return nil
+	}
+
+	forecast, err := m.forecastService.GetForecast(models.DefaultForecastMonths, m.forecastThreshold)
+	if err != nil {
+		return fmt.Errorf("failed to get cash flow forecast: %w", err)
+	}
+
+	message := m.discordService.FormatCashFlowForecast(forecast)
+	if err := m.discordService.SendMessage(settings.Discord.WebhookURL, message); err != nil {
+		return fmt.Errorf("failed to send weekly cash flow forecast: %w", err)
+	}
+
+	return nil
+}
+
 // sendMonthlyCashFlowReport 發送月度現金流報告
 func (m *SchedulerManager) sendMonthlyCashFlowReport(year, month int) error {
 	// 取得設定
@@ -7,6 +7,7 @@ import (
 
 	"github.com/chienchuanw/asset-manager/internal/models"
 	"github.com/chienchuanw/asset-manager/internal/repository"
+	"github.com/chienchuanw/asset-manager/internal/service"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -96,7 +97,10 @@ func TestSchedulerManager_logTaskExecution_Success(t *testing.T) {
 		mockBillingService,
 		mockExchangeRateService,
 		mockCreditCardService,
+		nil,
 		mockCashFlowService,
+		nil,
+		nil,
 		mockSchedulerLogRepo,
 		mockCashFlowReportLogRepo,
 		config,
@@ -147,7 +151,10 @@ func TestSchedulerManager_logTaskExecution_Failed(t *testing.T) {
 		mockBillingService,
 		mockExchangeRateService,
 		mockCreditCardService,
+		nil,
 		mockCashFlowService,
+		nil,
+		nil,
 		mockSchedulerLogRepo,
 		mockCashFlowReportLogRepo,
 		config,
@@ -198,7 +205,10 @@ func TestSchedulerManager_logTaskExecution_NoRepository(t *testing.T) {
 		mockBillingService,
 		mockExchangeRateService,
 		mockCreditCardService,
+		nil,
 		mockCashFlowService,
+		nil,
+		nil,
 		nil, // 沒有 schedulerLogRepo
 		nil, // 沒有 cashFlowReportLogRepo
 		config,
@@ -242,7 +252,10 @@ func TestSchedulerManager_sendFailureNotification_Success(t *testing.T) {
 		mockBillingService,
 		mockExchangeRateService,
 		mockCreditCardService,
+		nil,
 		mockCashFlowService,
+		nil,
+		nil,
 		mockSchedulerLogRepo,
 		mockCashFlowReportLogRepo,
 		config,
@@ -299,7 +312,10 @@ func TestSchedulerManager_sendFailureNotification_DiscordDisabled(t *testing.T)
 		mockBillingService,
 		mockExchangeRateService,
 		mockCreditCardService,
+		nil,
 		mockCashFlowService,
+		nil,
+		nil,
 		mockSchedulerLogRepo,
 		mockCashFlowReportLogRepo,
 		config,
@@ -354,7 +370,10 @@ func TestSchedulerManager_GetTaskSummaries_Success(t *testing.T) {
 		mockBillingService,
 		mockExchangeRateService,
 		mockCreditCardService,
+		nil,
 		mockCashFlowService,
+		nil,
+		nil,
 		mockSchedulerLogRepo,
 		mockCashFlowReportLogRepo,
 		config,
@@ -417,7 +436,10 @@ func TestSchedulerManager_GetTaskSummaries_NoRepository(t *testing.T) {
 		mockBillingService,
 		mockExchangeRateService,
 		mockCreditCardService,
+		nil,
 		mockCashFlowService,
+		nil,
+		nil,
 		nil, // 沒有 schedulerLogRepo
 		nil, // 沒有 cashFlowReportLogRepo
 		config,
@@ -432,3 +454,123 @@ func TestSchedulerManager_GetTaskSummaries_NoRepository(t *testing.T) {
 	assert.Contains(t, err.Error(), "scheduler log repository not available")
 }
 
+
+// TestSchedulerManager_runDailyBilling_CatchUp 測試每日扣款會補跑漏掉的日期，且只通知有扣款的補跑日
+func TestSchedulerManager_runDailyBilling_CatchUp(t *testing.T) {
+	// Arrange
+	mockSnapshotService := new(MockAssetSnapshotService)
+	mockDiscordService := new(MockDiscordService)
+	mockSettingsService := new(MockSettingsService)
+	mockHoldingService := new(MockHoldingService)
+	mockRebalanceService := new(MockRebalanceService)
+	mockBillingService := new(MockBillingService)
+	mockExchangeRateService := new(MockExchangeRateService)
+	mockCreditCardService := new(MockCreditCardService)
+	mockSchedulerLogRepo := new(MockSchedulerLogRepository)
+
+	config := SchedulerManagerConfig{
+		Enabled:           true,
+		DailySnapshotTime: "23:59",
+	}
+
+	mockCashFlowService := new(MockCashFlowService)
+	mockCashFlowReportLogRepo := new(MockCashFlowReportLogRepository)
+
+	manager := NewSchedulerManager(
+		mockSnapshotService,
+		mockDiscordService,
+		mockSettingsService,
+		mockHoldingService,
+		mockRebalanceService,
+		mockBillingService,
+		mockExchangeRateService,
+		mockCreditCardService,
+		nil,
+		mockCashFlowService,
+		nil,
+		nil,
+		mockSchedulerLogRepo,
+		mockCashFlowReportLogRepo,
+		config,
+	)
+
+	// 停機兩天：第一天有扣款、第二天沒有、第三天為今天
+	missedWithBilling := &service.DailyBillingResult{Date: time.Date(2025, 10, 14, 0, 0, 0, 0, time.UTC), SubscriptionCount: 1, TotalAmount: 390}
+	missedEmpty := &service.DailyBillingResult{Date: time.Date(2025, 10, 15, 0, 0, 0, 0, time.UTC)}
+	today := &service.DailyBillingResult{Date: time.Date(2025, 10, 16, 0, 0, 0, 0, time.UTC)}
+	mockBillingService.On("CatchUpBilling", mock.AnythingOfType("time.Time")).Return(
+		[]*service.DailyBillingResult{missedWithBilling, missedEmpty, today}, nil,
+	)
+
+	settings := &models.SettingsGroup{
+		Discord: models.DiscordSettings{
+			Enabled:    true,
+			WebhookURL: "https://discord.com/webhook/test",
+		},
+	}
+	mockSettingsService.On("GetSettings").Return(settings, nil)
+	mockDiscordService.On("SendDailyBillingNotification", "https://discord.com/webhook/test", missedWithBilling).Return(nil)
+	mockDiscordService.On("SendDailyBillingNotification", "https://discord.com/webhook/test", today).Return(nil)
+	mockSchedulerLogRepo.On("Create", mock.MatchedBy(func(log *models.SchedulerLog) bool {
+		return log.TaskName == "daily_billing" && log.Status == "success"
+	})).Return(nil)
+
+	// Act
+	manager.runDailyBilling()
+
+	// Assert
+	mockBillingService.AssertExpectations(t)
+	mockDiscordService.AssertNumberOfCalls(t, "SendDailyBillingNotification", 2)
+	mockSchedulerLogRepo.AssertExpectations(t)
+}
+
+// MockPriceRefreshService 模擬背景價格更新服務
+type MockPriceRefreshService struct {
+	mock.Mock
+}
+
+func (m *MockPriceRefreshService) RefreshDue(now time.Time) (*models.PriceRefreshResult, error) {
+	args := m.Called(now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PriceRefreshResult), args.Error(1)
+}
+
+// TestSchedulerManager_runPriceRefresh 測試背景價格更新只在有到期市場時記錄執行結果，部分失敗記為失敗
+func TestSchedulerManager_runPriceRefresh(t *testing.T) {
+	// Arrange
+	mockPriceRefreshService := new(MockPriceRefreshService)
+	mockSchedulerLogRepo := new(MockSchedulerLogRepository)
+
+	manager := NewSchedulerManager(
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		mockPriceRefreshService,
+		mockSchedulerLogRepo,
+		nil,
+		SchedulerManagerConfig{Enabled: true, DailySnapshotTime: "23:59"},
+	)
+
+	// 第一次沒有到期的市場、第二次美股更新失敗
+	mockPriceRefreshService.On("RefreshDue", mock.AnythingOfType("time.Time")).Return(
+		&models.PriceRefreshResult{Groups: []*models.PriceRefreshGroup{}}, nil,
+	).Once()
+	mockPriceRefreshService.On("RefreshDue", mock.AnythingOfType("time.Time")).Return(
+		&models.PriceRefreshResult{Groups: []*models.PriceRefreshGroup{
+			{AssetType: models.AssetTypeTWStock, MarketOpen: true, Symbols: 2, Refreshed: 2},
+			{AssetType: models.AssetTypeUSStock, Symbols: 1, Error: "rate limit"},
+		}}, nil,
+	).Once()
+	mockSchedulerLogRepo.On("Create", mock.MatchedBy(func(log *models.SchedulerLog) bool {
+		return log.TaskName == "price_refresh" && log.Status == "failed" &&
+			log.ErrorMessage != nil && *log.ErrorMessage == "price refresh incomplete: us-stock: rate limit"
+	})).Return(nil).Once()
+
+	// Act
+	manager.runPriceRefresh()
+	manager.runPriceRefresh()
+
+	// Assert
+	mockPriceRefreshService.AssertExpectations(t)
+	mockSchedulerLogRepo.AssertExpectations(t)
+}
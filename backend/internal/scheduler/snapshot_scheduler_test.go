@@ -1,6 +1,7 @@
 package scheduler
 
 import (
+	"database/sql"
 	"errors"
 	"testing"
 	"time"
@@ -270,8 +271,8 @@ func (m *MockDiscordService) SendInstallmentCompletionNotification(webhookURL st
 	return args.Error(0)
 }
 
-func (m *MockDiscordService) SendCreditCardPaymentReminder(webhookURL string, creditCards []*models.CreditCard) error {
-	args := m.Called(webhookURL, creditCards)
+func (m *MockDiscordService) SendCreditCardPaymentReminder(webhookURL string, statements []*models.CreditCardStatement) error {
+	args := m.Called(webhookURL, statements)
 	return args.Error(0)
 }
 
@@ -291,6 +292,14 @@ func (m *MockDiscordService) FormatYearlyCashFlowReport(summary *models.YearlyCa
 	return args.Get(0).(*models.DiscordMessage)
 }
 
+func (m *MockDiscordService) FormatCashFlowForecast(forecast *models.CashFlowForecast) *models.DiscordMessage {
+	args := m.Called(forecast)
+	if args.Get(0) == nil {
+		return nil
+	}
+	return args.Get(0).(*models.DiscordMessage)
+}
+
 // MockSettingsService 模擬 SettingsService
 type MockSettingsService struct {
 	mock.Mock
@@ -375,6 +384,22 @@ func (m *MockBillingService) ProcessInstallmentBilling(date time.Time) (*service
 	return args.Get(0).(*service.BillingResult), args.Error(1)
 }
 
+func (m *MockBillingService) ProcessLoanBilling(date time.Time) (*service.BillingResult, error) {
+	args := m.Called(date)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*service.BillingResult), args.Error(1)
+}
+
+func (m *MockBillingService) ProcessRecurringBilling(date time.Time) (*service.BillingResult, error) {
+	args := m.Called(date)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*service.BillingResult), args.Error(1)
+}
+
 func (m *MockBillingService) ProcessDailyBilling(date time.Time) (*service.DailyBillingResult, error) {
 	args := m.Called(date)
 	if args.Get(0) == nil {
@@ -383,6 +408,22 @@ func (m *MockBillingService) ProcessDailyBilling(date time.Time) (*service.Daily
 	return args.Get(0).(*service.DailyBillingResult), args.Error(1)
 }
 
+func (m *MockBillingService) CatchUpBilling(today time.Time) ([]*service.DailyBillingResult, error) {
+	args := m.Called(today)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*service.DailyBillingResult), args.Error(1)
+}
+
+func (m *MockBillingService) ListBillingRuns(limit int) ([]*models.BillingRun, error) {
+	args := m.Called(limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.BillingRun), args.Error(1)
+}
+
 // MockCashFlowService 模擬 CashFlowService
 type MockCashFlowService struct {
 	mock.Mock
@@ -396,6 +437,19 @@ func (m *MockCashFlowService) CreateCashFlow(input *models.CreateCashFlowInput)
 	return args.Get(0).(*models.CashFlow), args.Error(1)
 }
 
+func (m *MockCashFlowService) CreateCashFlowTx(tx *sql.Tx, input *models.CreateCashFlowInput) (*models.CashFlow, error) {
+	args := m.Called(tx, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CashFlow), args.Error(1)
+}
+
+func (m *MockCashFlowService) DeleteCashFlowTx(tx *sql.Tx, id uuid.UUID) error {
+	args := m.Called(tx, id)
+	return args.Error(0)
+}
+
 func (m *MockCashFlowService) GetCashFlow(id uuid.UUID) (*models.CashFlow, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
@@ -520,7 +574,10 @@ func TestSchedulerManager_RunSnapshotNow_WithExchangeRateUpdate(t *testing.T) {
 		mockBillingService,
 		mockExchangeRateService,
 		mockCreditCardService,
+		nil,
 		mockCashFlowService,
+		nil,
+		nil,
 		nil, // schedulerLogRepo
 		mockCashFlowReportLogRepo,
 		config,
@@ -573,7 +630,10 @@ func TestSchedulerManager_RunSnapshotNow_ExchangeRateError(t *testing.T) {
 		mockBillingService,
 		mockExchangeRateService,
 		mockCreditCardService,
+		nil,
 		mockCashFlowService,
+		nil,
+		nil,
 		nil, // schedulerLogRepo
 		mockCashFlowReportLogRepo,
 		config,
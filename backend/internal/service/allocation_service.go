@@ -21,38 +21,71 @@ type AllocationService interface {
 
 // allocationService 資產配置服務實作
 type allocationService struct {
-	holdingService HoldingService
+	holdingService     HoldingService
+	manualAssetService ManualAssetService // 選填，用於納入手動估值資產
 }
 
 // NewAllocationService 建立資產配置服務
-func NewAllocationService(holdingService HoldingService) AllocationService {
+func NewAllocationService(holdingService HoldingService, manualAssetService ManualAssetService) AllocationService {
 	return &allocationService{
-		holdingService: holdingService,
+		holdingService:     holdingService,
+		manualAssetService: manualAssetService,
 	}
 }
 
+// loadHoldings 取得所有持倉，並將手動估值資產轉為持倉形式一併納入配置計算
+func (s *allocationService) loadHoldings() ([]*models.Holding, error) {
+	result, err := s.holdingService.GetAllHoldings(models.HoldingFilters{})
+	if err != nil {
+		return nil, err
+	}
+
+	holdings := result.Holdings
+	if s.manualAssetService == nil {
+		return holdings, nil
+	}
+
+	assets, err := s.manualAssetService.GetActiveAssetValues(time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, asset := range assets {
+		holdings = append(holdings, &models.Holding{
+			Symbol:      asset.ID.String(),
+			Name:        asset.Name,
+			AssetType:   models.AssetTypeManual,
+			Quantity:    1,
+			Currency:    asset.Currency,
+			MarketValue: asset.CurrentValueTWD,
+		})
+	}
+
+	return holdings, nil
+}
+
 // GetCurrentAllocation 取得當前資產配置摘要
 func (s *allocationService) GetCurrentAllocation() (*models.AllocationSummary, error) {
-	// 取得所有持倉
-	result, err := s.holdingService.GetAllHoldings(models.HoldingFilters{})
+	// 取得所有持倉（含手動估值資產）
+	holdings, err := s.loadHoldings()
 	if err != nil {
 		return nil, err
 	}
 
 	// 計算總市值
 	var totalMarketValue float64
-	for _, holding := range result.Holdings {
+	for _, holding := range holdings {
 		totalMarketValue += holding.MarketValue
 	}
 
 	// 計算按資產類型的配置
-	byType, err := s.calculateAllocationByType(result.Holdings, totalMarketValue)
+	byType, err := s.calculateAllocationByType(holdings, totalMarketValue)
 	if err != nil {
 		return nil, err
 	}
 
 	// 計算按個別資產的配置
-	byAsset, err := s.calculateAllocationByAsset(result.Holdings, totalMarketValue, 0)
+	byAsset, err := s.calculateAllocationByAsset(holdings, totalMarketValue, 0)
 	if err != nil {
 		return nil, err
 	}
@@ -68,36 +101,36 @@ func (s *allocationService) GetCurrentAllocation() (*models.AllocationSummary, e
 
 // GetAllocationByType 取得按資產類型的配置
 func (s *allocationService) GetAllocationByType() ([]models.AllocationByType, error) {
-	// 取得所有持倉
-	result, err := s.holdingService.GetAllHoldings(models.HoldingFilters{})
+	// 取得所有持倉（含手動估值資產）
+	holdings, err := s.loadHoldings()
 	if err != nil {
 		return nil, err
 	}
 
 	// 計算總市值
 	var totalMarketValue float64
-	for _, holding := range result.Holdings {
+	for _, holding := range holdings {
 		totalMarketValue += holding.MarketValue
 	}
 
-	return s.calculateAllocationByType(result.Holdings, totalMarketValue)
+	return s.calculateAllocationByType(holdings, totalMarketValue)
 }
 
 // GetAllocationByAsset 取得按個別資產的配置
 func (s *allocationService) GetAllocationByAsset(limit int) ([]models.AllocationByAsset, error) {
-	// 取得所有持倉
-	result, err := s.holdingService.GetAllHoldings(models.HoldingFilters{})
+	// 取得所有持倉（含手動估值資產）
+	holdings, err := s.loadHoldings()
 	if err != nil {
 		return nil, err
 	}
 
 	// 計算總市值
 	var totalMarketValue float64
-	for _, holding := range result.Holdings {
+	for _, holding := range holdings {
 		totalMarketValue += holding.MarketValue
 	}
 
-	return s.calculateAllocationByAsset(result.Holdings, totalMarketValue, limit)
+	return s.calculateAllocationByAsset(holdings, totalMarketValue, limit)
 }
 
 // calculateAllocationByType 計算按資產類型的配置
@@ -179,6 +212,8 @@ func getAssetTypeName(assetType models.AssetType) string {
 		return "美股"
 	case models.AssetTypeCrypto:
 		return "加密貨幣"
+	case models.AssetTypeManual:
+		return "其他資產"
 	default:
 		return string(assetType)
 	}
@@ -40,7 +40,7 @@ func (m *MockHoldingServiceForAllocation) FixInsufficientQuantity(input *models.
 // TestAllocationService_GetCurrentAllocation 測試取得當前資產配置
 func TestAllocationService_GetCurrentAllocation(t *testing.T) {
 	mockHoldingService := new(MockHoldingServiceForAllocation)
-	service := NewAllocationService(mockHoldingService)
+	service := NewAllocationService(mockHoldingService, nil)
 
 	// 準備測試資料
 	holdings := []*models.Holding{
@@ -106,7 +106,7 @@ func TestAllocationService_GetCurrentAllocation(t *testing.T) {
 // TestAllocationService_GetCurrentAllocation_EmptyHoldings 測試空持倉情況
 func TestAllocationService_GetCurrentAllocation_EmptyHoldings(t *testing.T) {
 	mockHoldingService := new(MockHoldingServiceForAllocation)
-	service := NewAllocationService(mockHoldingService)
+	service := NewAllocationService(mockHoldingService, nil)
 
 	mockHoldingService.On("GetAllHoldings", models.HoldingFilters{}).Return(&HoldingServiceResult{
 		Holdings: []*models.Holding{},
@@ -127,7 +127,7 @@ func TestAllocationService_GetCurrentAllocation_EmptyHoldings(t *testing.T) {
 // TestAllocationService_GetAllocationByType 測試取得按資產類型的配置
 func TestAllocationService_GetAllocationByType(t *testing.T) {
 	mockHoldingService := new(MockHoldingServiceForAllocation)
-	service := NewAllocationService(mockHoldingService)
+	service := NewAllocationService(mockHoldingService, nil)
 
 	holdings := []*models.Holding{
 		{
@@ -188,7 +188,7 @@ func TestAllocationService_GetAllocationByType(t *testing.T) {
 // TestAllocationService_GetAllocationByAsset 測試取得按個別資產的配置
 func TestAllocationService_GetAllocationByAsset(t *testing.T) {
 	mockHoldingService := new(MockHoldingServiceForAllocation)
-	service := NewAllocationService(mockHoldingService)
+	service := NewAllocationService(mockHoldingService, nil)
 
 	holdings := []*models.Holding{
 		{
@@ -237,7 +237,7 @@ func TestAllocationService_GetAllocationByAsset(t *testing.T) {
 // TestAllocationService_GetAllocationByAsset_WithLimit 測試限制回傳數量
 func TestAllocationService_GetAllocationByAsset_WithLimit(t *testing.T) {
 	mockHoldingService := new(MockHoldingServiceForAllocation)
-	service := NewAllocationService(mockHoldingService)
+	service := NewAllocationService(mockHoldingService, nil)
 
 	holdings := []*models.Holding{
 		{Symbol: "A", MarketValue: 100},
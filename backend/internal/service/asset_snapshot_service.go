@@ -34,8 +34,9 @@ type AssetSnapshotService interface {
 
 // assetSnapshotService 資產快照服務實作
 type assetSnapshotService struct {
-	repo           repository.AssetSnapshotRepository
-	holdingService HoldingService // 用於計算持倉價值
+	repo               repository.AssetSnapshotRepository
+	holdingService     HoldingService     // 用於計算持倉價值
+	manualAssetService ManualAssetService // 用於計算手動估值資產價值（選填）
 }
 
 // NewAssetSnapshotService 建立資產快照服務
@@ -46,10 +47,11 @@ func NewAssetSnapshotService(repo repository.AssetSnapshotRepository) AssetSnaps
 }
 
 // NewAssetSnapshotServiceWithDeps 建立資產快照服務（包含依賴）
-func NewAssetSnapshotServiceWithDeps(repo repository.AssetSnapshotRepository, holdingService HoldingService) AssetSnapshotService {
+func NewAssetSnapshotServiceWithDeps(repo repository.AssetSnapshotRepository, holdingService HoldingService, manualAssetService ManualAssetService) AssetSnapshotService {
 	return &assetSnapshotService{
-		repo:           repo,
-		holdingService: holdingService,
+		repo:               repo,
+		holdingService:     holdingService,
+		manualAssetService: manualAssetService,
 	}
 }
 
@@ -203,16 +205,33 @@ func (s *assetSnapshotService) CreateDailySnapshots() error {
 		}
 	}
 
+	// 手動估值資產（不動產、車輛等）
+	var manualValueTWD float64
+	if s.manualAssetService != nil {
+		assets, err := s.manualAssetService.GetActiveAssetValues(today)
+		if err != nil {
+			return fmt.Errorf("failed to get manual asset values: %w", err)
+		}
+		for _, asset := range assets {
+			manualValueTWD += asset.CurrentValueTWD
+		}
+		totalValueTWD += manualValueTWD
+	}
+
 	// 建立各類型快照
-	snapshots := []struct {
+	type snapshotValue struct {
 		assetType models.SnapshotAssetType
 		value     float64
-	}{
+	}
+	snapshots := []snapshotValue{
 		{models.SnapshotAssetTypeTotal, totalValueTWD},
 		{models.SnapshotAssetTypeTWStock, twStockValueTWD},
 		{models.SnapshotAssetTypeUSStock, usStockValueTWD},
 		{models.SnapshotAssetTypeCrypto, cryptoValueTWD},
 	}
+	if s.manualAssetService != nil {
+		snapshots = append(snapshots, snapshotValue{models.SnapshotAssetTypeManual, manualValueTWD})
+	}
 
 	// 建立或更新快照
 	for _, snapshot := range snapshots {
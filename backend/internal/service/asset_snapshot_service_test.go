@@ -290,7 +290,7 @@ func TestAssetSnapshotService_CreateDailySnapshots_NoDoubleCurrencyConversion(t
 
 	// 建立 repository 和 service
 	assetSnapshotRepo := repository.NewAssetSnapshotRepository(db)
-	service := NewAssetSnapshotServiceWithDeps(assetSnapshotRepo, mockHoldingService)
+	service := NewAssetSnapshotServiceWithDeps(assetSnapshotRepo, mockHoldingService, nil)
 
 	// 準備測試資料：模擬三種資產類型的持倉
 	// 重點：MarketValue 已經是 TWD，不應該再被轉換
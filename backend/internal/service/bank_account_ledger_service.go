@@ -0,0 +1,215 @@
+package service
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/chienchuanw/asset-manager/internal/models"
+	"github.com/chienchuanw/asset-manager/internal/repository"
+	"github.com/google/uuid"
+)
+
+// BankAccountLedgerService 銀行帳戶明細帳與對帳業務邏輯介面
+type BankAccountLedgerService interface {
+	// GetLedger 取得帳戶明細帳（含逐筆累計餘額），startDate/endDate 可為 nil
+	GetLedger(accountID uuid.UUID, startDate, endDate *time.Time) (*models.BankAccountLedger, error)
+	// Reconcile 與對帳單餘額比對，有差額時建立調整記錄
+	Reconcile(accountID uuid.UUID, input *models.ReconcileBankAccountInput) (*models.BankAccountReconciliation, error)
+	ListReconciliations(accountID uuid.UUID) ([]*models.BankAccountReconciliation, error)
+}
+
+// bankAccountLedgerService 銀行帳戶明細帳與對帳業務邏輯實作
+type bankAccountLedgerService struct {
+	bankAccountRepo    repository.BankAccountRepository
+	cashFlowRepo       repository.CashFlowRepository
+	categoryRepo       repository.CategoryRepository
+	reconciliationRepo repository.BankAccountReconciliationRepository
+	cashFlowService    CashFlowService
+}
+
+// NewBankAccountLedgerService 建立新的銀行帳戶明細帳 service
+func NewBankAccountLedgerService(
+	bankAccountRepo repository.BankAccountRepository,
+	cashFlowRepo repository.CashFlowRepository,
+	categoryRepo repository.CategoryRepository,
+	reconciliationRepo repository.BankAccountReconciliationRepository,
+	cashFlowService CashFlowService,
+) BankAccountLedgerService {
+	return &bankAccountLedgerService{
+		bankAccountRepo:    bankAccountRepo,
+		cashFlowRepo:       cashFlowRepo,
+		categoryRepo:       categoryRepo,
+		reconciliationRepo: reconciliationRepo,
+		cashFlowService:    cashFlowService,
+	}
+}
+
+// GetLedger 取得帳戶明細帳
+// 帳戶只保存目前餘額，因此以目前餘額扣除所有記錄的影響反推期初餘額，再依時間順序累計
+func (s *bankAccountLedgerService) GetLedger(accountID uuid.UUID, startDate, endDate *time.Time) (*models.BankAccountLedger, error) {
+	// 驗證日期範圍
+	if startDate != nil && endDate != nil && startDate.After(*endDate) {
+		return nil, fmt.Errorf("start date must be before or equal to end date")
+	}
+
+	account, err := s.bankAccountRepo.GetByID(accountID)
+	if err != nil {
+		return nil, fmt.Errorf("bank account not found: %w", err)
+	}
+
+	cashFlows, err := s.cashFlowRepo.GetAll(repository.CashFlowFilters{BankAccountID: &accountID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cash flows: %w", err)
+	}
+
+	// Repository 依日期由新到舊排序，反轉為由舊到新以便累計
+	changes := make([]float64, len(cashFlows))
+	totalChange := 0.0
+	for i, cashFlow := range cashFlows {
+		changes[i] = cashFlow.BankAccountBalanceChange(accountID)
+		totalChange += changes[i]
+	}
+
+	ledger := &models.BankAccountLedger{
+		Account:   account,
+		StartDate: startDate,
+		EndDate:   endDate,
+		Entries:   []*models.BankAccountLedgerEntry{},
+	}
+
+	balance := roundToCents(account.Balance - totalChange)
+	ledger.OpeningBalance = balance
+	for i := len(cashFlows) - 1; i >= 0; i-- {
+		cashFlow := cashFlows[i]
+		if endDate != nil && cashFlow.Date.After(*endDate) {
+			break
+		}
+
+		balance = roundToCents(balance + changes[i])
+		if startDate != nil && cashFlow.Date.Before(*startDate) {
+			ledger.OpeningBalance = balance
+			continue
+		}
+
+		ledger.Entries = append(ledger.Entries, &models.BankAccountLedgerEntry{
+			CashFlow:       cashFlow,
+			Change:         changes[i],
+			RunningBalance: balance,
+		})
+	}
+	ledger.ClosingBalance = balance
+
+	return ledger, nil
+}
+
+// Reconcile 與對帳單餘額比對
+// 差額以「帳戶調整」分類的收入或支出記錄入帳（日期為對帳單日期），使帳上餘額與對帳單一致
+func (s *bankAccountLedgerService) Reconcile(accountID uuid.UUID, input *models.ReconcileBankAccountInput) (*models.BankAccountReconciliation, error) {
+	if input.StatementDate.IsZero() {
+		return nil, fmt.Errorf("statement date is required")
+	}
+
+	ledger, err := s.GetLedger(accountID, nil, &input.StatementDate)
+	if err != nil {
+		return nil, err
+	}
+
+	reconciliation := &models.BankAccountReconciliation{
+		BankAccountID:    accountID,
+		StatementDate:    input.StatementDate,
+		StatementBalance: input.StatementBalance,
+		LedgerBalance:    ledger.ClosingBalance,
+		Difference:       roundToCents(input.StatementBalance - ledger.ClosingBalance),
+		Note:             input.Note,
+	}
+
+	// 調整記錄（含帳戶餘額變動）與對帳記錄在同一個資料庫交易中建立，任一步驟失敗時整筆回滾
+	tx, err := s.reconciliationRepo.DB().Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// 有差額時建立調整記錄
+	if reconciliation.Difference != 0 {
+		adjustment, err := s.createAdjustment(tx, ledger.Account, input, reconciliation.Difference)
+		if err != nil {
+			return nil, err
+		}
+		reconciliation.AdjustmentCashFlowID = &adjustment.ID
+	}
+
+	created, err := s.reconciliationRepo.CreateTx(tx, reconciliation)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create reconciliation: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return created, nil
+}
+
+// ListReconciliations 取得帳戶的對帳記錄
+func (s *bankAccountLedgerService) ListReconciliations(accountID uuid.UUID) ([]*models.BankAccountReconciliation, error) {
+	// 驗證帳戶是否存在
+	if _, err := s.bankAccountRepo.GetByID(accountID); err != nil {
+		return nil, fmt.Errorf("bank account not found: %w", err)
+	}
+
+	return s.reconciliationRepo.GetByAccountID(accountID)
+}
+
+// createAdjustment 建立對帳差額的調整記錄（差額為正記收入，為負記支出）
+func (s *bankAccountLedgerService) createAdjustment(tx *sql.Tx, account *models.BankAccount, input *models.ReconcileBankAccountInput, difference float64) (*models.CashFlow, error) {
+	flowType := models.CashFlowTypeIncome
+	if difference < 0 {
+		flowType = models.CashFlowTypeExpense
+	}
+
+	category, err := s.findAdjustmentCategory(flowType)
+	if err != nil {
+		return nil, err
+	}
+
+	sourceType := models.SourceTypeBankAccount
+	cashFlow, err := s.cashFlowService.CreateCashFlowTx(tx, &models.CreateCashFlowInput{
+		Date:        input.StatementDate,
+		Type:        flowType,
+		CategoryID:  category.ID,
+		Amount:      math.Abs(difference),
+		Description: fmt.Sprintf("%s 對帳調整", account.BankName),
+		Note:        input.Note,
+		SourceType:  &sourceType,
+		SourceID:    &account.ID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create adjustment: %w", err)
+	}
+
+	return cashFlow, nil
+}
+
+// findAdjustmentCategory 取得對帳調整用的系統分類
+func (s *bankAccountLedgerService) findAdjustmentCategory(flowType models.CashFlowType) (*models.CashFlowCategory, error) {
+	categories, err := s.categoryRepo.GetAll(&flowType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get categories: %w", err)
+	}
+
+	for _, category := range categories {
+		if category.Name == models.BalanceAdjustmentCategoryName {
+			return category, nil
+		}
+	}
+
+	return nil, fmt.Errorf("balance adjustment category not found for type %s", flowType)
+}
+
+// roundToCents 四捨五入至小數點後兩位，避免浮點數累加誤差
+func roundToCents(value float64) float64 {
+	return math.Round(value*100) / 100
+}
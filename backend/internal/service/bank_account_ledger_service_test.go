@@ -0,0 +1,266 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/chienchuanw/asset-manager/internal/models"
+	"github.com/chienchuanw/asset-manager/internal/repository"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockBankAccountReconciliationRepository 對帳記錄 repository 的 mock
+type MockBankAccountReconciliationRepository struct {
+	mock.Mock
+}
+
+func (m *MockBankAccountReconciliationRepository) CreateTx(tx *sql.Tx, reconciliation *models.BankAccountReconciliation) (*models.BankAccountReconciliation, error) {
+	args := m.Called(tx, reconciliation)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BankAccountReconciliation), args.Error(1)
+}
+
+func (m *MockBankAccountReconciliationRepository) GetByAccountID(accountID uuid.UUID) ([]*models.BankAccountReconciliation, error) {
+	args := m.Called(accountID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.BankAccountReconciliation), args.Error(1)
+}
+
+func (m *MockBankAccountReconciliationRepository) DB() *sql.DB {
+	args := m.Called()
+	return args.Get(0).(*sql.DB)
+}
+
+// ledgerTestFixture 明細帳測試資料：目前餘額 10000，三筆記錄（依日期由新到舊）
+type ledgerTestFixture struct {
+	account   *models.BankAccount
+	cashFlows []*models.CashFlow
+}
+
+func newLedgerTestFixture() *ledgerTestFixture {
+	accountID := uuid.New()
+	otherID := uuid.New()
+	bankAccount := models.SourceTypeBankAccount
+
+	return &ledgerTestFixture{
+		account: &models.BankAccount{
+			ID:       accountID,
+			BankName: "台新銀行",
+			Currency: models.CurrencyTWD,
+			Balance:  10000,
+		},
+		cashFlows: []*models.CashFlow{
+			{
+				ID: uuid.New(), Date: time.Date(2025, 1, 20, 0, 0, 0, 0, time.UTC),
+				Type: models.CashFlowTypeExpense, Amount: 500,
+				SourceType: &bankAccount, SourceID: &accountID,
+			},
+			{
+				ID: uuid.New(), Date: time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC),
+				Type: models.CashFlowTypeTransferOut, Amount: 2000,
+				SourceType: &bankAccount, SourceID: &otherID,
+				TargetType: &bankAccount, TargetID: &accountID,
+			},
+			{
+				ID: uuid.New(), Date: time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC),
+				Type: models.CashFlowTypeIncome, Amount: 3000,
+				SourceType: &bankAccount, SourceID: &accountID,
+			},
+		},
+	}
+}
+
+func TestBankAccountLedgerService_GetLedger(t *testing.T) {
+	fixture := newLedgerTestFixture()
+	accountID := fixture.account.ID
+
+	mockBankAccountRepo := new(MockBankAccountRepository)
+	mockCashFlowRepo := new(MockCashFlowRepository)
+	mockBankAccountRepo.On("GetByID", accountID).Return(fixture.account, nil)
+	mockCashFlowRepo.On("GetAll", repository.CashFlowFilters{BankAccountID: &accountID}).Return(fixture.cashFlows, nil)
+
+	service := NewBankAccountLedgerService(mockBankAccountRepo, mockCashFlowRepo, nil, nil, nil)
+
+	t.Run("full history", func(t *testing.T) {
+		ledger, err := service.GetLedger(accountID, nil, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 5500.0, ledger.OpeningBalance)
+		assert.Equal(t, 10000.0, ledger.ClosingBalance)
+		assert.Len(t, ledger.Entries, 3)
+		assert.Equal(t, 8500.0, ledger.Entries[0].RunningBalance)
+		assert.Equal(t, 2000.0, ledger.Entries[1].Change)
+		assert.Equal(t, 10500.0, ledger.Entries[1].RunningBalance)
+		assert.Equal(t, -500.0, ledger.Entries[2].Change)
+		assert.Equal(t, 10000.0, ledger.Entries[2].RunningBalance)
+	})
+
+	t.Run("date range", func(t *testing.T) {
+		startDate := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+		endDate := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+
+		ledger, err := service.GetLedger(accountID, &startDate, &endDate)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 8500.0, ledger.OpeningBalance)
+		assert.Equal(t, 10500.0, ledger.ClosingBalance)
+		assert.Len(t, ledger.Entries, 1)
+	})
+
+	t.Run("invalid date range", func(t *testing.T) {
+		startDate := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+		endDate := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+		ledger, err := service.GetLedger(accountID, &startDate, &endDate)
+
+		assert.Error(t, err)
+		assert.Nil(t, ledger)
+	})
+}
+
+func TestBankAccountLedgerService_Reconcile_CreatesAdjustment(t *testing.T) {
+	db, dbMock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	fixture := newLedgerTestFixture()
+	accountID := fixture.account.ID
+	statementDate := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+
+	mockBankAccountRepo := new(MockBankAccountRepository)
+	mockCashFlowRepo := new(MockCashFlowRepository)
+	mockCategoryRepo := new(MockCategoryRepository)
+	mockReconciliationRepo := new(MockBankAccountReconciliationRepository)
+	cashFlowService := NewCashFlowService(mockCashFlowRepo, mockCategoryRepo, mockBankAccountRepo, nil)
+	service := NewBankAccountLedgerService(mockBankAccountRepo, mockCashFlowRepo, mockCategoryRepo, mockReconciliationRepo, cashFlowService)
+
+	expenseType := models.CashFlowTypeExpense
+	category := &models.CashFlowCategory{ID: uuid.New(), Name: models.BalanceAdjustmentCategoryName, Type: models.CashFlowTypeExpense}
+	adjustment := &models.CashFlow{ID: uuid.New()}
+
+	// 設定 mock 期望：帳上 10500，對帳單 10400，差額 -100 記為支出，調整與對帳記錄在同一個交易中提交
+	dbMock.ExpectBegin()
+	dbMock.ExpectCommit()
+	mockReconciliationRepo.On("DB").Return(db)
+	mockBankAccountRepo.On("GetByID", accountID).Return(fixture.account, nil)
+	mockBankAccountRepo.On("GetByIDForUpdateTx", mock.AnythingOfType("*sql.Tx"), accountID).Return(fixture.account, nil)
+	mockCashFlowRepo.On("GetAll", repository.CashFlowFilters{BankAccountID: &accountID}).Return(fixture.cashFlows, nil)
+	mockCategoryRepo.On("GetAll", &expenseType).Return([]*models.CashFlowCategory{
+		{ID: uuid.New(), Name: "飲食", Type: models.CashFlowTypeExpense},
+		category,
+	}, nil)
+	mockCategoryRepo.On("GetByID", category.ID).Return(category, nil)
+	mockBankAccountRepo.On("UpdateBalanceTx", mock.AnythingOfType("*sql.Tx"), accountID, -100.0).Return(fixture.account, nil)
+	mockCashFlowRepo.On("CreateTx", mock.AnythingOfType("*sql.Tx"), mock.MatchedBy(func(input *models.CreateCashFlowInput) bool {
+		return input.Type == models.CashFlowTypeExpense && input.Amount == 100 &&
+			input.CategoryID == category.ID && input.Date.Equal(statementDate) &&
+			*input.SourceID == accountID
+	})).Return(adjustment, nil)
+	mockReconciliationRepo.On("CreateTx", mock.AnythingOfType("*sql.Tx"), mock.MatchedBy(func(r *models.BankAccountReconciliation) bool {
+		return r.LedgerBalance == 10500 && r.Difference == -100 && *r.AdjustmentCashFlowID == adjustment.ID
+	})).Return(&models.BankAccountReconciliation{ID: uuid.New()}, nil)
+
+	// 執行測試
+	result, err := service.Reconcile(accountID, &models.ReconcileBankAccountInput{
+		StatementDate:    statementDate,
+		StatementBalance: 10400,
+	})
+
+	// 驗證結果
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+	mockCashFlowRepo.AssertExpectations(t)
+	mockBankAccountRepo.AssertExpectations(t)
+	mockReconciliationRepo.AssertExpectations(t)
+	assert.NoError(t, dbMock.ExpectationsWereMet())
+}
+
+func TestBankAccountLedgerService_Reconcile_NoDifference(t *testing.T) {
+	db, dbMock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	fixture := newLedgerTestFixture()
+	accountID := fixture.account.ID
+
+	mockBankAccountRepo := new(MockBankAccountRepository)
+	mockCashFlowRepo := new(MockCashFlowRepository)
+	mockReconciliationRepo := new(MockBankAccountReconciliationRepository)
+	service := NewBankAccountLedgerService(mockBankAccountRepo, mockCashFlowRepo, nil, mockReconciliationRepo, nil)
+
+	// 設定 mock 期望
+	dbMock.ExpectBegin()
+	dbMock.ExpectCommit()
+	mockReconciliationRepo.On("DB").Return(db)
+	mockBankAccountRepo.On("GetByID", accountID).Return(fixture.account, nil)
+	mockCashFlowRepo.On("GetAll", repository.CashFlowFilters{BankAccountID: &accountID}).Return(fixture.cashFlows, nil)
+	mockReconciliationRepo.On("CreateTx", mock.AnythingOfType("*sql.Tx"), mock.MatchedBy(func(r *models.BankAccountReconciliation) bool {
+		return r.Difference == 0 && r.AdjustmentCashFlowID == nil
+	})).Return(&models.BankAccountReconciliation{ID: uuid.New()}, nil)
+
+	// 執行測試
+	result, err := service.Reconcile(accountID, &models.ReconcileBankAccountInput{
+		StatementDate:    time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC),
+		StatementBalance: 10000,
+	})
+
+	// 驗證結果
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+	mockCashFlowRepo.AssertNotCalled(t, "CreateTx", mock.Anything, mock.Anything)
+	mockReconciliationRepo.AssertExpectations(t)
+	assert.NoError(t, dbMock.ExpectationsWereMet())
+}
+
+func TestBankAccountLedgerService_Reconcile_RollsBackAdjustment(t *testing.T) {
+	db, dbMock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	fixture := newLedgerTestFixture()
+	accountID := fixture.account.ID
+
+	mockBankAccountRepo := new(MockBankAccountRepository)
+	mockCashFlowRepo := new(MockCashFlowRepository)
+	mockCategoryRepo := new(MockCategoryRepository)
+	mockReconciliationRepo := new(MockBankAccountReconciliationRepository)
+	cashFlowService := NewCashFlowService(mockCashFlowRepo, mockCategoryRepo, mockBankAccountRepo, nil)
+	service := NewBankAccountLedgerService(mockBankAccountRepo, mockCashFlowRepo, mockCategoryRepo, mockReconciliationRepo, cashFlowService)
+
+	incomeType := models.CashFlowTypeIncome
+	category := &models.CashFlowCategory{ID: uuid.New(), Name: models.BalanceAdjustmentCategoryName, Type: models.CashFlowTypeIncome}
+
+	// 設定 mock 期望：對帳記錄建立失敗時回滾交易，調整記錄與餘額變動一併撤銷
+	dbMock.ExpectBegin()
+	dbMock.ExpectRollback()
+	mockReconciliationRepo.On("DB").Return(db)
+	mockBankAccountRepo.On("GetByID", accountID).Return(fixture.account, nil)
+	mockBankAccountRepo.On("GetByIDForUpdateTx", mock.AnythingOfType("*sql.Tx"), accountID).Return(fixture.account, nil)
+	mockCashFlowRepo.On("GetAll", repository.CashFlowFilters{BankAccountID: &accountID}).Return(fixture.cashFlows, nil)
+	mockCategoryRepo.On("GetAll", &incomeType).Return([]*models.CashFlowCategory{category}, nil)
+	mockCategoryRepo.On("GetByID", category.ID).Return(category, nil)
+	mockBankAccountRepo.On("UpdateBalanceTx", mock.AnythingOfType("*sql.Tx"), accountID, 500.0).Return(fixture.account, nil)
+	mockCashFlowRepo.On("CreateTx", mock.AnythingOfType("*sql.Tx"), mock.AnythingOfType("*models.CreateCashFlowInput")).Return(&models.CashFlow{ID: uuid.New()}, nil)
+	mockReconciliationRepo.On("CreateTx", mock.AnythingOfType("*sql.Tx"), mock.AnythingOfType("*models.BankAccountReconciliation")).Return(nil, errors.New("connection reset"))
+
+	// 執行測試
+	result, err := service.Reconcile(accountID, &models.ReconcileBankAccountInput{
+		StatementDate:    time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC),
+		StatementBalance: 10500,
+	})
+
+	// 驗證結果
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	mockCashFlowRepo.AssertNotCalled(t, "Delete", mock.Anything)
+	assert.NoError(t, dbMock.ExpectationsWereMet())
+}
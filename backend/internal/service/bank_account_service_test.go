@@ -1,6 +1,7 @@
 package service
 
 import (
+	"database/sql"
 	"fmt"
 	"testing"
 
@@ -31,6 +32,14 @@ func (m *MockBankAccountRepository) GetByID(id uuid.UUID) (*models.BankAccount,
 	return args.Get(0).(*models.BankAccount), args.Error(1)
 }
 
+func (m *MockBankAccountRepository) GetByIDForUpdateTx(tx *sql.Tx, id uuid.UUID) (*models.BankAccount, error) {
+	args := m.Called(tx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BankAccount), args.Error(1)
+}
+
 func (m *MockBankAccountRepository) GetAll(currency *models.Currency) ([]*models.BankAccount, error) {
 	args := m.Called(currency)
 	if args.Get(0) == nil {
@@ -55,6 +64,14 @@ func (m *MockBankAccountRepository) UpdateBalance(id uuid.UUID, amount float64)
 	return args.Get(0).(*models.BankAccount), args.Error(1)
 }
 
+func (m *MockBankAccountRepository) UpdateBalanceTx(tx *sql.Tx, id uuid.UUID, amount float64) (*models.BankAccount, error) {
+	args := m.Called(tx, id, amount)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BankAccount), args.Error(1)
+}
+
 func (m *MockBankAccountRepository) Delete(id uuid.UUID) error {
 	args := m.Called(id)
 	return args.Error(0)
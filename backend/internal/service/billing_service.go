@@ -1,6 +1,8 @@
 package service
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
@@ -15,13 +17,28 @@ type BillingService interface {
 	ProcessSubscriptionBilling(date time.Time) (*BillingResult, error)
 	// ProcessInstallmentBilling 處理分期扣款
 	ProcessInstallmentBilling(date time.Time) (*BillingResult, error)
-	// ProcessDailyBilling 處理每日扣款（訂閱 + 分期）
+	// ProcessLoanBilling 處理貸款扣款
+	ProcessLoanBilling(date time.Time) (*BillingResult, error)
+	// ProcessRecurringBilling 處理定期收支範本產生的現金流
+	ProcessRecurringBilling(date time.Time) (*BillingResult, error)
+	// ProcessDailyBilling 處理每日扣款（訂閱 + 分期 + 貸款 + 定期收支），並記錄執行結果
 	ProcessDailyBilling(date time.Time) (*DailyBillingResult, error)
+	// CatchUpBilling 補跑最近一次成功執行之後，以及回溯期間內執行失敗的每日扣款
+	CatchUpBilling(today time.Time) ([]*DailyBillingResult, error)
+	// ListBillingRuns 取得最近的每日扣款執行記錄
+	ListBillingRuns(limit int) ([]*models.BillingRun, error)
 }
 
+// errAlreadyBilled 該來源本期已扣款
+var errAlreadyBilled = errors.New("already billed for this period")
+
+// billingCatchUpWindowDays 補跑時回溯檢查失敗或缺漏執行記錄的天數
+const billingCatchUpWindowDays = 31
+
 // BillingResult 扣款結果
 type BillingResult struct {
 	ProcessedCount   int                `json:"processed_count"`   // 處理的數量
+	SkippedCount     int                `json:"skipped_count"`     // 本期已扣款而略過的數量
 	FailedCount      int                `json:"failed_count"`      // 失敗的數量
 	CreatedCashFlows []*models.CashFlow `json:"created_cash_flows"` // 建立的現金流記錄
 	Errors           []BillingError     `json:"errors,omitempty"`   // 錯誤列表
@@ -29,8 +46,8 @@ type BillingResult struct {
 
 // BillingError 扣款錯誤
 type BillingError struct {
-	ID      uuid.UUID `json:"id"`      // 訂閱或分期的 ID
-	Name    string    `json:"name"`    // 訂閱或分期的名稱
+	ID      uuid.UUID `json:"id"`      // 訂閱、分期、貸款或定期收支範本的 ID
+	Name    string    `json:"name"`    // 訂閱、分期、貸款或定期收支範本的名稱
 	Message string    `json:"message"` // 錯誤訊息
 }
 
@@ -39,29 +56,127 @@ type DailyBillingResult struct {
 	Date              time.Time      `json:"date"`               // 扣款日期
 	SubscriptionCount int            `json:"subscription_count"` // 訂閱扣款數量
 	InstallmentCount  int            `json:"installment_count"`  // 分期扣款數量
-	TotalAmount       float64        `json:"total_amount"`       // 總扣款金額
+	LoanCount         int            `json:"loan_count"`         // 貸款扣款數量
+	RecurringCount    int            `json:"recurring_count"`    // 定期收支產生數量
+	SkippedCount      int            `json:"skipped_count"`      // 本期已扣款而略過的數量
+	TotalAmount       float64        `json:"total_amount"`       // 總扣款金額（僅計支出）
 	SubscriptionResult *BillingResult `json:"subscription_result"` // 訂閱扣款結果
 	InstallmentResult  *BillingResult `json:"installment_result"`  // 分期扣款結果
+	LoanResult         *BillingResult `json:"loan_result"`         // 貸款扣款結果
+	RecurringResult    *BillingResult `json:"recurring_result"`    // 定期收支結果
 }
 
 // billingService 扣款服務實作
+// 每個扣款項目在獨立的資料庫交易中登記扣款、建立現金流（同步更新帳戶餘額）與更新期數，
+// 任一步驟失敗時整筆回滾
 type billingService struct {
-	subscriptionRepo repository.SubscriptionRepository
-	installmentRepo  repository.InstallmentRepository
-	cashFlowRepo     repository.CashFlowRepository
+	subscriptionRepo    repository.SubscriptionRepository
+	installmentRepo     repository.InstallmentRepository
+	loanRepo            repository.LoanRepository
+	recurringRepo       repository.RecurringTemplateRepository
+	ledgerRepo          repository.BillingLedgerRepository
+	bankAccountRepo     repository.BankAccountRepository
+	cashFlowService     CashFlowService
+	exchangeRateService ExchangeRateService
 }
 
 // NewBillingService 建立新的扣款 service
 func NewBillingService(
 	subscriptionRepo repository.SubscriptionRepository,
 	installmentRepo repository.InstallmentRepository,
-	cashFlowRepo repository.CashFlowRepository,
+	loanRepo repository.LoanRepository,
+	recurringRepo repository.RecurringTemplateRepository,
+	ledgerRepo repository.BillingLedgerRepository,
+	bankAccountRepo repository.BankAccountRepository,
+	cashFlowService CashFlowService,
+	exchangeRateService ExchangeRateService,
 ) BillingService {
 	return &billingService{
-		subscriptionRepo: subscriptionRepo,
-		installmentRepo:  installmentRepo,
-		cashFlowRepo:     cashFlowRepo,
+		subscriptionRepo:    subscriptionRepo,
+		installmentRepo:     installmentRepo,
+		loanRepo:            loanRepo,
+		recurringRepo:       recurringRepo,
+		ledgerRepo:          ledgerRepo,
+		bankAccountRepo:     bankAccountRepo,
+		cashFlowService:     cashFlowService,
+		exchangeRateService: exchangeRateService,
+	}
+}
+
+// runInTx 在資料庫交易中執行 fn，fn 回傳錯誤時回滾
+func (s *billingService) runInTx(fn func(tx *sql.Tx) error) error {
+	tx, err := s.ledgerRepo.DB().Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// debitAmount 計算從扣款來源扣除的金額
+// 外幣銀行帳戶以帳戶本身的幣別扣款，其餘來源（現金、信用卡、台幣帳戶）換算為新台幣
+func (s *billingService) debitAmount(amount float64, currency models.Currency, sourceType models.SourceType, sourceID *uuid.UUID, date time.Time) (float64, error) {
+	if currency == "" || currency == models.CurrencyTWD {
+		return amount, nil
+	}
+
+	if sourceType == models.SourceTypeBankAccount && sourceID != nil {
+		account, err := s.bankAccountRepo.GetByID(*sourceID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get bank account: %w", err)
+		}
+		if account.Currency != models.CurrencyTWD {
+			return amount, nil
+		}
+	}
+
+	converted, err := s.exchangeRateService.ConvertToTWD(amount, currency, date)
+	if err != nil {
+		return 0, fmt.Errorf("failed to convert %s amount to TWD: %w", currency, err)
+	}
+
+	return roundToCents(converted), nil
+}
+
+// billingDescription 產生扣款現金流的描述，外幣扣款附註原幣金額
+func billingDescription(description string, amount float64, currency models.Currency) string {
+	if currency == "" || currency == models.CurrencyTWD {
+		return description
+	}
+	return fmt.Sprintf("%s [%s %.2f]", description, currency, amount)
+}
+
+// claimBilling 在交易中登記本期扣款，本期已扣款時回傳 errAlreadyBilled
+// 定期收支可能一個月發生多次，以發生日為期別
+func (s *billingService) claimBilling(tx *sql.Tx, sourceType models.BillingSourceType, sourceID uuid.UUID, date time.Time, amount float64) error {
+	period := models.BillingPeriod(date)
+	if sourceType == models.BillingSourceRecurring {
+		period = models.RecurringBillingPeriod(date)
+	}
+
+	claimed, err := s.ledgerRepo.ClaimRecordTx(tx, &models.BillingRecord{
+		SourceType:  sourceType,
+		SourceID:    sourceID,
+		Period:      period,
+		BillingDate: date,
+		Amount:      amount,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to claim billing record: %w", err)
+	}
+	if !claimed {
+		return errAlreadyBilled
 	}
+	return nil
 }
 
 // ProcessSubscriptionBilling 處理訂閱扣款
@@ -81,35 +196,18 @@ func (s *billingService) ProcessSubscriptionBilling(date time.Time) (*BillingRes
 
 	// 處理每個訂閱
 	for _, subscription := range subscriptions {
-		// 根據訂閱的付款方式設定現金流的來源類型
-		sourceType := subscription.PaymentMethod.ToSourceType()
-		var sourceID *uuid.UUID
-		if subscription.AccountID != nil {
-			sourceID = subscription.AccountID
-		}
-
-		// 建立現金流記錄
-		cashFlowInput := &models.CreateCashFlowInput{
-			Date:        date,
-			Type:        models.CashFlowTypeExpense,
-			CategoryID:  subscription.CategoryID,
-			Amount:      subscription.Amount,
-			Description: fmt.Sprintf("%s - 訂閱扣款", subscription.Name),
-			SourceType:  &sourceType,
-			SourceID:    sourceID,
-		}
-
-		if subscription.Note != nil {
-			cashFlowInput.Note = subscription.Note
+		cashFlow, err := s.processSubscription(subscription, date)
+		if errors.Is(err, errAlreadyBilled) {
+			// 本期已扣款時略過，避免重複扣款
+			result.SkippedCount++
+			continue
 		}
-
-		cashFlow, err := s.cashFlowRepo.Create(cashFlowInput)
 		if err != nil {
 			result.FailedCount++
 			result.Errors = append(result.Errors, BillingError{
 				ID:      subscription.ID,
 				Name:    subscription.Name,
-				Message: fmt.Sprintf("failed to create cash flow: %v", err),
+				Message: err.Error(),
 			})
 			continue
 		}
@@ -121,6 +219,63 @@ func (s *billingService) ProcessSubscriptionBilling(date time.Time) (*BillingRes
 	return result, nil
 }
 
+// processSubscription 在資料庫交易中處理單筆訂閱的當期扣款
+func (s *billingService) processSubscription(subscription *models.Subscription, date time.Time) (*models.CashFlow, error) {
+	// 載入價格調整記錄，以扣款日適用的價格計費
+	priceChanges, err := s.subscriptionRepo.GetPriceChanges(subscription.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get price changes: %w", err)
+	}
+	subscription.PriceChanges = priceChanges
+	price := subscription.PriceOn(date)
+
+	// 根據訂閱的付款方式設定現金流的來源類型
+	sourceType := subscription.PaymentMethod.ToSourceType()
+	var sourceID *uuid.UUID
+	if subscription.AccountID != nil {
+		sourceID = subscription.AccountID
+	}
+
+	amount, err := s.debitAmount(price, subscription.Currency, sourceType, sourceID, date)
+	if err != nil {
+		return nil, err
+	}
+
+	cashFlowInput := &models.CreateCashFlowInput{
+		Date:        date,
+		Type:        models.CashFlowTypeExpense,
+		CategoryID:  subscription.CategoryID,
+		Amount:      amount,
+		Description: billingDescription(fmt.Sprintf("%s - 訂閱扣款", subscription.Name), price, subscription.Currency),
+		SourceType:  &sourceType,
+		SourceID:    sourceID,
+	}
+
+	if subscription.Note != nil {
+		cashFlowInput.Note = subscription.Note
+	}
+
+	var cashFlow *models.CashFlow
+	err = s.runInTx(func(tx *sql.Tx) error {
+		if err := s.claimBilling(tx, models.BillingSourceSubscription, subscription.ID, date, amount); err != nil {
+			return err
+		}
+
+		// 透過現金流 service 建立記錄，同步更新銀行帳戶餘額或信用卡已使用額度
+		created, err := s.cashFlowService.CreateCashFlowTx(tx, cashFlowInput)
+		if err != nil {
+			return fmt.Errorf("failed to create cash flow: %w", err)
+		}
+		cashFlow = created
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return cashFlow, nil
+}
+
 // ProcessInstallmentBilling 處理分期扣款
 func (s *billingService) ProcessInstallmentBilling(date time.Time) (*BillingResult, error) {
 	// 取得當日需要扣款的分期
@@ -138,62 +293,333 @@ func (s *billingService) ProcessInstallmentBilling(date time.Time) (*BillingResu
 
 	// 處理每個分期
 	for _, installment := range installments {
-		// 計算當前期數
-		currentPeriod := installment.PaidCount + 1
+		cashFlow, err := s.processInstallment(installment, date)
+		if errors.Is(err, errAlreadyBilled) {
+			// 本期已扣款時略過，避免重複扣款與重複累計期數
+			result.SkippedCount++
+			continue
+		}
+		if err != nil {
+			result.FailedCount++
+			result.Errors = append(result.Errors, BillingError{
+				ID:      installment.ID,
+				Name:    installment.Name,
+				Message: err.Error(),
+			})
+			continue
+		}
+
+		// 提前清償已完成時不會建立現金流
+		if cashFlow == nil {
+			continue
+		}
+
+		result.ProcessedCount++
+		result.CreatedCashFlows = append(result.CreatedCashFlows, cashFlow)
+	}
+
+	return result, nil
+}
+
+// processInstallment 在資料庫交易中處理單筆分期的當期扣款並更新已付期數
+// 每期金額依付款計畫（含提前還款後的重新攤提），並記錄本金與利息拆分
+func (s *billingService) processInstallment(installment *models.Installment, date time.Time) (*models.CashFlow, error) {
+	// 載入提前還款記錄以計算付款計畫
+	prepayments, err := s.installmentRepo.GetPrepayments(installment.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get prepayments: %w", err)
+	}
+	installment.Prepayments = prepayments
+
+	schedule := installment.GenerateSchedule()
+
+	// 提前還款已清償全部本金時，直接標記為已完成
+	if installment.PaidCount >= len(schedule.Entries) {
+		status := models.InstallmentStatusCompleted
+		if _, err := s.installmentRepo.Update(installment.ID, &models.UpdateInstallmentInput{Status: &status}); err != nil {
+			return nil, fmt.Errorf("failed to update installment status: %w", err)
+		}
+		return nil, nil
+	}
+
+	entry := schedule.Entries[installment.PaidCount]
+
+	// 根據分期的付款方式設定現金流的來源類型
+	sourceType := installment.PaymentMethod.ToSourceType()
+	var sourceID *uuid.UUID
+	if installment.AccountID != nil {
+		sourceID = installment.AccountID
+	}
+
+	amount, err := s.debitAmount(entry.Payment, installment.Currency, sourceType, sourceID, date)
+	if err != nil {
+		return nil, err
+	}
+
+	cashFlowInput := &models.CreateCashFlowInput{
+		Date:        date,
+		Type:        models.CashFlowTypeExpense,
+		CategoryID:  installment.CategoryID,
+		Amount:      amount,
+		Description: billingDescription(fmt.Sprintf("%s - 分期付款 (%d/%d)", installment.Name, entry.Period, installment.InstallmentCount), entry.Payment, installment.Currency),
+		SourceType:  &sourceType,
+		SourceID:    sourceID,
+	}
+
+	if installment.Note != nil {
+		cashFlowInput.Note = installment.Note
+	}
+
+	// 如果已付完所有期數或本金已還清，更新狀態為已完成
+	status := installment.Status
+	if entry.Period >= installment.InstallmentCount || entry.RemainingBalance <= 0 {
+		status = models.InstallmentStatusCompleted
+	}
+
+	var cashFlow *models.CashFlow
+	err = s.runInTx(func(tx *sql.Tx) error {
+		if err := s.claimBilling(tx, models.BillingSourceInstallment, installment.ID, date, amount); err != nil {
+			return err
+		}
 
-		// 根據分期的付款方式設定現金流的來源類型
-		sourceType := installment.PaymentMethod.ToSourceType()
-		var sourceID *uuid.UUID
-		if installment.AccountID != nil {
-			sourceID = installment.AccountID
+		created, err := s.cashFlowService.CreateCashFlowTx(tx, cashFlowInput)
+		if err != nil {
+			return fmt.Errorf("failed to create cash flow: %w", err)
 		}
 
-		// 建立現金流記錄
-		cashFlowInput := &models.CreateCashFlowInput{
-			Date:        date,
-			Type:        models.CashFlowTypeExpense,
-			CategoryID:  installment.CategoryID,
-			Amount:      installment.InstallmentAmount,
-			Description: fmt.Sprintf("%s - 分期付款 (%d/%d)", installment.Name, currentPeriod, installment.InstallmentCount),
-			SourceType:  &sourceType,
-			SourceID:    sourceID,
+		// 記錄扣款明細
+		if _, err := s.installmentRepo.CreatePaymentTx(tx, &models.InstallmentPayment{
+			InstallmentID:   installment.ID,
+			Period:          entry.Period,
+			PaymentDate:     date,
+			PrincipalAmount: entry.Principal,
+			InterestAmount:  entry.Interest,
+			CashFlowID:      &created.ID,
+		}); err != nil {
+			return fmt.Errorf("failed to record installment payment: %w", err)
 		}
 
-		if installment.Note != nil {
-			cashFlowInput.Note = installment.Note
+		if err := s.installmentRepo.UpdateProgressTx(tx, installment.ID, entry.Period, status); err != nil {
+			return fmt.Errorf("failed to update installment: %w", err)
 		}
 
-		cashFlow, err := s.cashFlowRepo.Create(cashFlowInput)
+		cashFlow = created
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return cashFlow, nil
+}
+
+// ProcessLoanBilling 處理貸款扣款
+// 每期依攤還表拆分為本金與利息兩筆現金流，並記錄還款明細
+func (s *billingService) ProcessLoanBilling(date time.Time) (*BillingResult, error) {
+	// 取得當日需要扣款的貸款
+	loans, err := s.loanRepo.GetDueBillings(date)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due loans: %w", err)
+	}
+
+	result := &BillingResult{
+		ProcessedCount:   0,
+		FailedCount:      0,
+		CreatedCashFlows: []*models.CashFlow{},
+		Errors:           []BillingError{},
+	}
+
+	// 處理每筆貸款
+	for _, loan := range loans {
+		cashFlows, err := s.processLoan(loan, date)
+		if errors.Is(err, errAlreadyBilled) {
+			result.SkippedCount++
+			continue
+		}
 		if err != nil {
 			result.FailedCount++
 			result.Errors = append(result.Errors, BillingError{
-				ID:      installment.ID,
-				Name:    installment.Name,
-				Message: fmt.Sprintf("failed to create cash flow: %v", err),
+				ID:      loan.ID,
+				Name:    loan.Name,
+				Message: err.Error(),
 			})
 			continue
 		}
 
-		// 更新分期的已付期數
-		newPaidCount := currentPeriod
-		updateInput := &models.UpdateInstallmentInput{
-			PaidCount: &newPaidCount,
+		// 提前還本已清償時不會建立現金流
+		if len(cashFlows) == 0 {
+			continue
+		}
+
+		result.ProcessedCount++
+		result.CreatedCashFlows = append(result.CreatedCashFlows, cashFlows...)
+	}
+
+	return result, nil
+}
+
+// processLoan 處理單筆貸款的當期扣款
+func (s *billingService) processLoan(loan *models.Loan, date time.Time) ([]*models.CashFlow, error) {
+	// 載入利率調整與提前還本記錄以計算攤還表
+	rateChanges, err := s.loanRepo.GetRateChanges(loan.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rate changes: %w", err)
+	}
+	prepayments, err := s.loanRepo.GetPrepayments(loan.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get prepayments: %w", err)
+	}
+	loan.RateChanges = rateChanges
+	loan.Prepayments = prepayments
+
+	schedule := loan.GenerateAmortizationSchedule(date)
+
+	// 提前還本已清償全部本金時，直接標記為已清償
+	if loan.PaidCount >= len(schedule.Entries) {
+		status := models.LoanStatusPaidOff
+		if _, err := s.loanRepo.Update(loan.ID, &models.UpdateLoanInput{Status: &status}); err != nil {
+			return nil, fmt.Errorf("failed to update loan status: %w", err)
+		}
+		return nil, nil
+	}
+
+	entry := schedule.Entries[loan.PaidCount]
+	totalPeriods := len(schedule.Entries)
+
+	// 根據貸款的付款方式設定現金流的來源類型
+	sourceType := loan.PaymentMethod.ToSourceType()
+	var sourceID *uuid.UUID
+	if loan.AccountID != nil {
+		sourceID = loan.AccountID
+	}
+
+	principal, err := s.debitAmount(entry.Principal, loan.Currency, sourceType, sourceID, date)
+	if err != nil {
+		return nil, err
+	}
+	interest, err := s.debitAmount(entry.Interest, loan.Currency, sourceType, sourceID, date)
+	if err != nil {
+		return nil, err
+	}
+
+	// 如果已還清所有本金，更新狀態為已清償
+	status := loan.Status
+	if entry.Period >= totalPeriods || entry.RemainingBalance <= 0 {
+		status = models.LoanStatusPaidOff
+	}
+
+	cashFlows := []*models.CashFlow{}
+	err = s.runInTx(func(tx *sql.Tx) error {
+		// 本期已扣款時略過，避免重複扣款與重複累計期數
+		if err := s.claimBilling(tx, models.BillingSourceLoan, loan.ID, date, roundToCents(principal+interest)); err != nil {
+			return err
+		}
+
+		payment := &models.LoanPayment{
+			LoanID:          loan.ID,
+			Period:          entry.Period,
+			PaymentDate:     date,
+			PrincipalAmount: entry.Principal,
+			InterestAmount:  entry.Interest,
+		}
+
+		// 建立本金現金流記錄
+		if principal > 0 {
+			cashFlow, err := s.cashFlowService.CreateCashFlowTx(tx, &models.CreateCashFlowInput{
+				Date:        date,
+				Type:        models.CashFlowTypeExpense,
+				CategoryID:  loan.CategoryID,
+				Amount:      principal,
+				Description: billingDescription(fmt.Sprintf("%s - 貸款本金 (%d/%d)", loan.Name, entry.Period, totalPeriods), entry.Principal, loan.Currency),
+				SourceType:  &sourceType,
+				SourceID:    sourceID,
+				Note:        loan.Note,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to create principal cash flow: %w", err)
+			}
+			payment.PrincipalCashFlowID = &cashFlow.ID
+			cashFlows = append(cashFlows, cashFlow)
+		}
+
+		// 建立利息現金流記錄（未設定利息分類時使用本金分類）
+		if interest > 0 {
+			interestCategoryID := loan.CategoryID
+			if loan.InterestCategoryID != nil {
+				interestCategoryID = *loan.InterestCategoryID
+			}
+
+			cashFlow, err := s.cashFlowService.CreateCashFlowTx(tx, &models.CreateCashFlowInput{
+				Date:        date,
+				Type:        models.CashFlowTypeExpense,
+				CategoryID:  interestCategoryID,
+				Amount:      interest,
+				Description: billingDescription(fmt.Sprintf("%s - 貸款利息 (%d/%d)", loan.Name, entry.Period, totalPeriods), entry.Interest, loan.Currency),
+				SourceType:  &sourceType,
+				SourceID:    sourceID,
+				Note:        loan.Note,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to create interest cash flow: %w", err)
+			}
+			payment.InterestCashFlowID = &cashFlow.ID
+			cashFlows = append(cashFlows, cashFlow)
+		}
+
+		// 記錄還款明細
+		if _, err := s.loanRepo.CreatePaymentTx(tx, payment); err != nil {
+			return fmt.Errorf("failed to record loan payment: %w", err)
 		}
 
-		// 如果已付完所有期數，更新狀態為已完成
-		if currentPeriod >= installment.InstallmentCount {
-			status := models.InstallmentStatusCompleted
-			updateInput.Status = &status
+		// 更新貸款的已付期數
+		if err := s.loanRepo.UpdateProgressTx(tx, loan.ID, entry.Period, status); err != nil {
+			return fmt.Errorf("failed to update loan: %w", err)
 		}
 
-		_, err = s.installmentRepo.Update(installment.ID, updateInput)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return cashFlows, nil
+}
+
+// ProcessRecurringBilling 處理定期收支範本
+// 依排程規則判斷當日是否發生，並以當日適用的金額建立收入、支出或轉帳現金流
+func (s *billingService) ProcessRecurringBilling(date time.Time) (*BillingResult, error) {
+	// 取得可能於當日發生的範本
+	templates, err := s.recurringRepo.GetActive(date)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active recurring templates: %w", err)
+	}
+
+	result := &BillingResult{
+		ProcessedCount:   0,
+		FailedCount:      0,
+		CreatedCashFlows: []*models.CashFlow{},
+		Errors:           []BillingError{},
+	}
+
+	// 處理每個範本
+	for _, template := range templates {
+		if !template.OccursOn(date) {
+			continue
+		}
+
+		cashFlow, err := s.processRecurring(template, date)
+		if errors.Is(err, errAlreadyBilled) {
+			result.SkippedCount++
+			continue
+		}
 		if err != nil {
-			// 記錄錯誤但不影響現金流記錄的建立
+			result.FailedCount++
 			result.Errors = append(result.Errors, BillingError{
-				ID:      installment.ID,
-				Name:    installment.Name,
-				Message: fmt.Sprintf("cash flow created but failed to update installment: %v", err),
+				ID:      template.ID,
+				Name:    template.Name,
+				Message: err.Error(),
 			})
+			continue
 		}
 
 		result.ProcessedCount++
@@ -203,8 +629,162 @@ func (s *billingService) ProcessInstallmentBilling(date time.Time) (*BillingResu
 	return result, nil
 }
 
-// ProcessDailyBilling 處理每日扣款（訂閱 + 分期）
+// processRecurring 在資料庫交易中建立定期收支範本當日的現金流
+func (s *billingService) processRecurring(template *models.RecurringTemplate, date time.Time) (*models.CashFlow, error) {
+	// 載入金額調整記錄以取得當日適用金額
+	changes, err := s.recurringRepo.GetAmountChanges(template.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get amount changes: %w", err)
+	}
+	template.AmountChanges = changes
+
+	originalAmount := template.AmountOn(date)
+	// 根據範本的付款方式設定現金流的來源類型
+	sourceType := template.PaymentMethod.ToSourceType()
+	var sourceID *uuid.UUID
+	if template.AccountID != nil {
+		sourceID = template.AccountID
+	}
+
+	amount, err := s.debitAmount(originalAmount, template.Currency, sourceType, sourceID, date)
+	if err != nil {
+		return nil, err
+	}
+
+	cashFlowInput := &models.CreateCashFlowInput{
+		Date:        date,
+		Type:        template.Type,
+		CategoryID:  template.CategoryID,
+		Amount:      amount,
+		Description: billingDescription(fmt.Sprintf("%s - 定期收支", template.Name), originalAmount, template.Currency),
+		SourceType:  &sourceType,
+		SourceID:    sourceID,
+		TargetType:  template.TargetType,
+		TargetID:    template.TargetID,
+		Note:        template.Note,
+	}
+
+	var cashFlow *models.CashFlow
+	err = s.runInTx(func(tx *sql.Tx) error {
+		if err := s.claimBilling(tx, models.BillingSourceRecurring, template.ID, date, amount); err != nil {
+			return err
+		}
+
+		created, err := s.cashFlowService.CreateCashFlowTx(tx, cashFlowInput)
+		if err != nil {
+			return fmt.Errorf("failed to create cash flow: %w", err)
+		}
+		cashFlow = created
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return cashFlow, nil
+}
+
+// ProcessDailyBilling 處理每日扣款（訂閱 + 分期 + 貸款 + 定期收支）
+// 同一期重複執行時不會重複扣款；執行結果會記錄下來供停機後補跑使用
 func (s *billingService) ProcessDailyBilling(date time.Time) (*DailyBillingResult, error) {
+	date = models.BillingDate(date)
+
+	result, err := s.processDailyBilling(date)
+	if err != nil {
+		errMsg := err.Error()
+		if _, recordErr := s.ledgerRepo.UpsertRun(&models.BillingRun{
+			BillingDate:  date,
+			Status:       models.BillingRunStatusFailed,
+			ErrorMessage: &errMsg,
+		}); recordErr != nil {
+			return nil, fmt.Errorf("%w (and failed to record billing run: %v)", err, recordErr)
+		}
+		return nil, err
+	}
+
+	// 有項目扣款失敗時標記為失敗，下次補跑時會重試（已扣款的項目會被略過）
+	run := &models.BillingRun{
+		BillingDate:    date,
+		Status:         models.BillingRunStatusSuccess,
+		ProcessedCount: result.SubscriptionCount + result.InstallmentCount + result.LoanCount + result.RecurringCount,
+		SkippedCount:   result.SkippedCount,
+		FailedCount:    result.SubscriptionResult.FailedCount + result.InstallmentResult.FailedCount + result.LoanResult.FailedCount + result.RecurringResult.FailedCount,
+		TotalAmount:    result.TotalAmount,
+	}
+	if run.FailedCount > 0 {
+		run.Status = models.BillingRunStatusFailed
+		errMsg := fmt.Sprintf("%d billing item(s) failed", run.FailedCount)
+		run.ErrorMessage = &errMsg
+	}
+	if _, err := s.ledgerRepo.UpsertRun(run); err != nil {
+		return nil, fmt.Errorf("failed to record billing run: %w", err)
+	}
+
+	return result, nil
+}
+
+// CatchUpBilling 補跑漏掉或失敗的每日扣款
+// 從最近一次成功執行的隔天開始逐日處理到指定日期，並重試回溯期間內未成功的扣款日
+// （即使之後已有成功的執行）；尚無執行記錄時只處理指定日期
+func (s *billingService) CatchUpBilling(today time.Time) ([]*DailyBillingResult, error) {
+	today = models.BillingDate(today)
+
+	lastRun, err := s.ledgerRepo.GetLastSuccessfulRun()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get last billing run: %w", err)
+	}
+
+	start := today
+	if lastRun != nil && lastRun.BillingDate.Before(today) {
+		start = models.BillingDate(lastRun.BillingDate).AddDate(0, 0, 1)
+	}
+
+	// 回溯期間內的執行記錄：已成功的日期略過，未成功的日期納入補跑
+	runs, err := s.ledgerRepo.ListRunsSince(today.AddDate(0, 0, -billingCatchUpWindowDays))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list billing runs: %w", err)
+	}
+	succeeded := make(map[time.Time]bool, len(runs))
+	for _, run := range runs {
+		date := models.BillingDate(run.BillingDate)
+		if run.Status == models.BillingRunStatusSuccess {
+			succeeded[date] = true
+		} else if date.Before(start) {
+			start = date
+		}
+	}
+
+	results := []*DailyBillingResult{}
+	for date := start; !date.After(today); date = date.AddDate(0, 0, 1) {
+		if succeeded[date] {
+			continue
+		}
+		result, err := s.ProcessDailyBilling(date)
+		if err != nil {
+			return results, fmt.Errorf("failed to process billing for %s: %w", date.Format("2006-01-02"), err)
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// ListBillingRuns 取得最近的每日扣款執行記錄
+func (s *billingService) ListBillingRuns(limit int) ([]*models.BillingRun, error) {
+	if limit <= 0 {
+		limit = 30
+	}
+
+	runs, err := s.ledgerRepo.ListRuns(limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list billing runs: %w", err)
+	}
+
+	return runs, nil
+}
+
+// processDailyBilling 依序處理訂閱、分期、貸款扣款與定期收支
+func (s *billingService) processDailyBilling(date time.Time) (*DailyBillingResult, error) {
 	// 處理訂閱扣款
 	subscriptionResult, err := s.ProcessSubscriptionBilling(date)
 	if err != nil {
@@ -217,6 +797,18 @@ func (s *billingService) ProcessDailyBilling(date time.Time) (*DailyBillingResul
 		return nil, fmt.Errorf("failed to process installment billing: %w", err)
 	}
 
+	// 處理貸款扣款
+	loanResult, err := s.ProcessLoanBilling(date)
+	if err != nil {
+		return nil, fmt.Errorf("failed to process loan billing: %w", err)
+	}
+
+	// 處理定期收支
+	recurringResult, err := s.ProcessRecurringBilling(date)
+	if err != nil {
+		return nil, fmt.Errorf("failed to process recurring billing: %w", err)
+	}
+
 	// 計算總金額
 	totalAmount := 0.0
 	for _, cf := range subscriptionResult.CreatedCashFlows {
@@ -225,14 +817,28 @@ func (s *billingService) ProcessDailyBilling(date time.Time) (*DailyBillingResul
 	for _, cf := range installmentResult.CreatedCashFlows {
 		totalAmount += cf.Amount
 	}
+	for _, cf := range loanResult.CreatedCashFlows {
+		totalAmount += cf.Amount
+	}
+	// 定期收支可能為收入或轉帳，只計入支出
+	for _, cf := range recurringResult.CreatedCashFlows {
+		if cf.Type == models.CashFlowTypeExpense {
+			totalAmount += cf.Amount
+		}
+	}
 
 	result := &DailyBillingResult{
 		Date:               date,
 		SubscriptionCount:  subscriptionResult.ProcessedCount,
 		InstallmentCount:   installmentResult.ProcessedCount,
-		TotalAmount:        totalAmount,
+		LoanCount:          loanResult.ProcessedCount,
+		RecurringCount:     recurringResult.ProcessedCount,
+		SkippedCount:       subscriptionResult.SkippedCount + installmentResult.SkippedCount + loanResult.SkippedCount + recurringResult.SkippedCount,
+		TotalAmount:        roundToCents(totalAmount),
 		SubscriptionResult: subscriptionResult,
 		InstallmentResult:  installmentResult,
+		LoanResult:         loanResult,
+		RecurringResult:    recurringResult,
 	}
 
 	return result, nil
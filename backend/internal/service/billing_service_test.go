@@ -1,38 +1,116 @@
 package service
 
 import (
+	"database/sql"
 	"testing"
 	"time"
 
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/chienchuanw/asset-manager/internal/models"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
+// MockBillingLedgerRepository 扣款記錄 repository 的 mock
+type MockBillingLedgerRepository struct {
+	mock.Mock
+}
+
+func (m *MockBillingLedgerRepository) ClaimRecordTx(tx *sql.Tx, record *models.BillingRecord) (bool, error) {
+	args := m.Called(tx, record)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockBillingLedgerRepository) UpsertRun(run *models.BillingRun) (*models.BillingRun, error) {
+	args := m.Called(run)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BillingRun), args.Error(1)
+}
+
+func (m *MockBillingLedgerRepository) GetLastSuccessfulRun() (*models.BillingRun, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BillingRun), args.Error(1)
+}
+
+func (m *MockBillingLedgerRepository) ListRunsSince(since time.Time) ([]*models.BillingRun, error) {
+	args := m.Called(since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.BillingRun), args.Error(1)
+}
+
+func (m *MockBillingLedgerRepository) ListRuns(limit int) ([]*models.BillingRun, error) {
+	args := m.Called(limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.BillingRun), args.Error(1)
+}
+
+func (m *MockBillingLedgerRepository) DB() *sql.DB {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil
+	}
+	return args.Get(0).(*sql.DB)
+}
+
+// newClaimingLedgerRepository 建立每期皆尚未扣款的扣款記錄 mock
+func newClaimingLedgerRepository(db *sql.DB) *MockBillingLedgerRepository {
+	ledgerRepo := new(MockBillingLedgerRepository)
+	ledgerRepo.On("DB").Return(db)
+	ledgerRepo.On("ClaimRecordTx", mock.AnythingOfType("*sql.Tx"), mock.AnythingOfType("*models.BillingRecord")).Return(true, nil)
+	ledgerRepo.On("UpsertRun", mock.AnythingOfType("*models.BillingRun")).Return(&models.BillingRun{}, nil)
+	return ledgerRepo
+}
+
+// newExpenseCategoryRepository 建立回傳支出分類的分類 repository mock
+func newExpenseCategoryRepository(categoryIDs ...uuid.UUID) *MockCategoryRepository {
+	categoryRepo := new(MockCategoryRepository)
+	for _, categoryID := range categoryIDs {
+		categoryRepo.On("GetByID", categoryID).Return(&models.CashFlowCategory{ID: categoryID, Type: models.CashFlowTypeExpense}, nil)
+	}
+	return categoryRepo
+}
+
 // TestBillingService_ProcessSubscriptionBilling 測試處理訂閱扣款
 func TestBillingService_ProcessSubscriptionBilling(t *testing.T) {
+	db, dbMock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
 	mockSubscriptionRepo := new(MockSubscriptionRepository)
 	mockInstallmentRepo := new(MockInstallmentRepository)
+	mockLoanRepo := new(MockLoanRepository)
 	mockCashFlowRepo := new(MockCashFlowRepository)
-
-	service := NewBillingService(mockSubscriptionRepo, mockInstallmentRepo, mockCashFlowRepo)
+	mockLedgerRepo := newClaimingLedgerRepository(db)
 
 	today := time.Date(2025, 10, 15, 0, 0, 0, 0, time.UTC)
 	categoryID := uuid.New()
 
+	cashFlowService := NewCashFlowService(mockCashFlowRepo, newExpenseCategoryRepository(categoryID), nil, nil)
+	service := NewBillingService(mockSubscriptionRepo, mockInstallmentRepo, mockLoanRepo, nil, mockLedgerRepo, nil, cashFlowService, nil)
+
 	// 準備測試資料
 	subscriptions := []*models.Subscription{
 		{
-			ID:           uuid.New(),
-			Name:         "Netflix",
-			Amount:       390,
-			Currency:     models.CurrencyTWD,
-			BillingCycle: models.BillingCycleMonthly,
-			BillingDay:   15,
-			CategoryID:   categoryID,
-			StartDate:    time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC),
-			Status:       models.SubscriptionStatusActive,
+			ID:            uuid.New(),
+			Name:          "Netflix",
+			Amount:        390,
+			Currency:      models.CurrencyTWD,
+			BillingCycle:  models.BillingCycleMonthly,
+			BillingDay:    15,
+			CategoryID:    categoryID,
+			StartDate:     time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC),
+			PaymentMethod: models.PaymentMethodCash,
+			Status:        models.SubscriptionStatusActive,
 		},
 	}
 
@@ -49,8 +127,11 @@ func TestBillingService_ProcessSubscriptionBilling(t *testing.T) {
 	}
 
 	// 設定 mock 期望
+	dbMock.ExpectBegin()
+	dbMock.ExpectCommit()
+	mockSubscriptionRepo.On("GetPriceChanges", mock.AnythingOfType("uuid.UUID")).Return([]*models.SubscriptionPriceChange{}, nil)
 	mockSubscriptionRepo.On("GetDueBillings", today).Return(subscriptions, nil)
-	mockCashFlowRepo.On("Create", mock.AnythingOfType("*models.CreateCashFlowInput")).Return(expectedCashFlow, nil)
+	mockCashFlowRepo.On("CreateTx", mock.AnythingOfType("*sql.Tx"), mock.AnythingOfType("*models.CreateCashFlowInput")).Return(expectedCashFlow, nil)
 
 	// 執行測試
 	result, err := service.ProcessSubscriptionBilling(today)
@@ -63,19 +144,259 @@ func TestBillingService_ProcessSubscriptionBilling(t *testing.T) {
 	assert.Len(t, result.CreatedCashFlows, 1)
 	mockSubscriptionRepo.AssertExpectations(t)
 	mockCashFlowRepo.AssertExpectations(t)
+	assert.NoError(t, dbMock.ExpectationsWereMet())
+}
+
+// TestBillingService_ProcessSubscriptionBilling_UsesPriceOnBillingDate 測試依扣款日適用的價格扣款
+func TestBillingService_ProcessSubscriptionBilling_UsesPriceOnBillingDate(t *testing.T) {
+	db, dbMock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mockSubscriptionRepo := new(MockSubscriptionRepository)
+	mockCashFlowRepo := new(MockCashFlowRepository)
+	mockLedgerRepo := newClaimingLedgerRepository(db)
+
+	today := time.Date(2025, 10, 15, 0, 0, 0, 0, time.UTC)
+	categoryID := uuid.New()
+	subscriptionID := uuid.New()
+
+	cashFlowService := NewCashFlowService(mockCashFlowRepo, newExpenseCategoryRepository(categoryID), nil, nil)
+	service := NewBillingService(mockSubscriptionRepo, nil, nil, nil, mockLedgerRepo, nil, cashFlowService, nil)
+
+	// 目前金額已調整為 11 月生效的新價格，但 10 月扣款仍應使用舊價格
+	subscription := &models.Subscription{
+		ID:            subscriptionID,
+		Name:          "Spotify",
+		Amount:        199,
+		Currency:      models.CurrencyTWD,
+		BillingCycle:  models.BillingCycleMonthly,
+		BillingDay:    15,
+		CategoryID:    categoryID,
+		StartDate:     time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC),
+		PaymentMethod: models.PaymentMethodCash,
+		Status:        models.SubscriptionStatusActive,
+	}
+	priceChanges := []*models.SubscriptionPriceChange{
+		{SubscriptionID: subscriptionID, EffectiveDate: time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC), Amount: 149},
+		{SubscriptionID: subscriptionID, EffectiveDate: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), Amount: 169},
+		{SubscriptionID: subscriptionID, EffectiveDate: time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC), Amount: 199},
+	}
+
+	// 設定 mock 期望
+	dbMock.ExpectBegin()
+	dbMock.ExpectCommit()
+	mockSubscriptionRepo.On("GetDueBillings", today).Return([]*models.Subscription{subscription}, nil)
+	mockSubscriptionRepo.On("GetPriceChanges", subscriptionID).Return(priceChanges, nil)
+	mockCashFlowRepo.On("CreateTx", mock.AnythingOfType("*sql.Tx"), mock.MatchedBy(func(input *models.CreateCashFlowInput) bool {
+		return input.Amount == 169
+	})).Return(&models.CashFlow{ID: uuid.New(), Amount: 169}, nil)
+
+	// 執行測試
+	result, err := service.ProcessSubscriptionBilling(today)
+
+	// 驗證結果
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.ProcessedCount)
+	assert.Equal(t, 0, result.FailedCount)
+	mockSubscriptionRepo.AssertExpectations(t)
+	mockCashFlowRepo.AssertExpectations(t)
+	assert.NoError(t, dbMock.ExpectationsWereMet())
+}
+
+// TestBillingService_ProcessSubscriptionBilling_USDCreditCard 測試美金訂閱以信用卡扣款時換算為台幣並增加已使用額度
+func TestBillingService_ProcessSubscriptionBilling_USDCreditCard(t *testing.T) {
+	db, dbMock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mockSubscriptionRepo := new(MockSubscriptionRepository)
+	mockCashFlowRepo := new(MockCashFlowRepository)
+	mockCreditCardRepo := new(MockCreditCardRepository)
+	mockExchangeRateService := new(MockExchangeRateService)
+	mockLedgerRepo := newClaimingLedgerRepository(db)
+
+	today := time.Date(2025, 10, 15, 0, 0, 0, 0, time.UTC)
+	categoryID := uuid.New()
+	cardID := uuid.New()
+
+	cashFlowService := NewCashFlowService(mockCashFlowRepo, newExpenseCategoryRepository(categoryID), nil, mockCreditCardRepo)
+	service := NewBillingService(mockSubscriptionRepo, nil, nil, nil, mockLedgerRepo, nil, cashFlowService, mockExchangeRateService)
+
+	// 設定 mock 期望：USD 15.99 以匯率 32 換算為 TWD 511.68
+	dbMock.ExpectBegin()
+	dbMock.ExpectCommit()
+	mockSubscriptionRepo.On("GetPriceChanges", mock.AnythingOfType("uuid.UUID")).Return([]*models.SubscriptionPriceChange{}, nil)
+	mockSubscriptionRepo.On("GetDueBillings", today).Return([]*models.Subscription{
+		{
+			ID:            uuid.New(),
+			Name:          "ChatGPT Plus",
+			Amount:        15.99,
+			Currency:      models.CurrencyUSD,
+			BillingDay:    15,
+			CategoryID:    categoryID,
+			PaymentMethod: models.PaymentMethodCreditCard,
+			AccountID:     &cardID,
+		},
+	}, nil)
+	mockExchangeRateService.On("ConvertToTWD", 15.99, models.CurrencyUSD, today).Return(511.68, nil)
+	mockCreditCardRepo.On("GetByIDForUpdateTx", mock.AnythingOfType("*sql.Tx"), cardID).Return(&models.CreditCard{ID: cardID, CreditLimit: 50000, UsedCredit: 1000}, nil)
+	mockCreditCardRepo.On("UpdateUsedCreditTx", mock.AnythingOfType("*sql.Tx"), cardID, 511.68).Return(&models.CreditCard{}, nil)
+	mockCashFlowRepo.On("CreateTx", mock.AnythingOfType("*sql.Tx"), mock.MatchedBy(func(input *models.CreateCashFlowInput) bool {
+		return input.Amount == 511.68 && input.Description == "ChatGPT Plus - 訂閱扣款 [USD 15.99]"
+	})).Return(&models.CashFlow{ID: uuid.New(), Amount: 511.68}, nil)
+
+	// 執行測試
+	result, err := service.ProcessSubscriptionBilling(today)
+
+	// 驗證結果
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.ProcessedCount)
+	mockCreditCardRepo.AssertExpectations(t)
+	mockCashFlowRepo.AssertExpectations(t)
+	assert.NoError(t, dbMock.ExpectationsWereMet())
+}
+
+// TestBillingService_ProcessSubscriptionBilling_USDBankAccount 測試美金訂閱以美金銀行帳戶扣款時以原幣扣除餘額，台幣帳戶則換算為台幣
+func TestBillingService_ProcessSubscriptionBilling_USDBankAccount(t *testing.T) {
+	tests := []struct {
+		name            string
+		accountCurrency models.Currency
+		debit           float64
+	}{
+		{name: "USD account", accountCurrency: models.CurrencyUSD, debit: 15.99},
+		{name: "TWD account", accountCurrency: models.CurrencyTWD, debit: 511.68},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, dbMock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+
+			mockSubscriptionRepo := new(MockSubscriptionRepository)
+			mockCashFlowRepo := new(MockCashFlowRepository)
+			mockBankAccountRepo := new(MockBankAccountRepository)
+			mockExchangeRateService := new(MockExchangeRateService)
+			mockLedgerRepo := newClaimingLedgerRepository(db)
+
+			today := time.Date(2025, 10, 15, 0, 0, 0, 0, time.UTC)
+			categoryID := uuid.New()
+			accountID := uuid.New()
+			account := &models.BankAccount{ID: accountID, Currency: tt.accountCurrency, Balance: 10000}
+
+			cashFlowService := NewCashFlowService(mockCashFlowRepo, newExpenseCategoryRepository(categoryID), mockBankAccountRepo, nil)
+			service := NewBillingService(mockSubscriptionRepo, nil, nil, nil, mockLedgerRepo, mockBankAccountRepo, cashFlowService, mockExchangeRateService)
+
+			// 設定 mock 期望：美金帳戶直接扣 USD 15.99，台幣帳戶以匯率 32 換算為 TWD 511.68
+			dbMock.ExpectBegin()
+			dbMock.ExpectCommit()
+			mockSubscriptionRepo.On("GetPriceChanges", mock.AnythingOfType("uuid.UUID")).Return([]*models.SubscriptionPriceChange{}, nil)
+			mockSubscriptionRepo.On("GetDueBillings", today).Return([]*models.Subscription{
+				{
+					ID:            uuid.New(),
+					Name:          "ChatGPT Plus",
+					Amount:        15.99,
+					Currency:      models.CurrencyUSD,
+					BillingDay:    15,
+					CategoryID:    categoryID,
+					PaymentMethod: models.PaymentMethodBankAccount,
+					AccountID:     &accountID,
+				},
+			}, nil)
+			if tt.accountCurrency == models.CurrencyTWD {
+				mockExchangeRateService.On("ConvertToTWD", 15.99, models.CurrencyUSD, today).Return(511.68, nil)
+			}
+			mockBankAccountRepo.On("GetByID", accountID).Return(account, nil)
+			mockBankAccountRepo.On("GetByIDForUpdateTx", mock.AnythingOfType("*sql.Tx"), accountID).Return(account, nil)
+			mockBankAccountRepo.On("UpdateBalanceTx", mock.AnythingOfType("*sql.Tx"), accountID, -tt.debit).Return(&models.BankAccount{}, nil)
+			mockCashFlowRepo.On("CreateTx", mock.AnythingOfType("*sql.Tx"), mock.MatchedBy(func(input *models.CreateCashFlowInput) bool {
+				return input.Amount == tt.debit
+			})).Return(&models.CashFlow{ID: uuid.New(), Amount: tt.debit}, nil)
+
+			// 執行測試
+			result, err := service.ProcessSubscriptionBilling(today)
+
+			// 驗證結果
+			assert.NoError(t, err)
+			assert.Equal(t, 1, result.ProcessedCount)
+			mockBankAccountRepo.AssertExpectations(t)
+			mockExchangeRateService.AssertExpectations(t)
+			mockCashFlowRepo.AssertExpectations(t)
+			assert.NoError(t, dbMock.ExpectationsWereMet())
+		})
+	}
+}
+
+// TestBillingService_ProcessSubscriptionBilling_InsufficientBalance 測試銀行帳戶餘額不足時回滾交易
+func TestBillingService_ProcessSubscriptionBilling_InsufficientBalance(t *testing.T) {
+	db, dbMock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mockSubscriptionRepo := new(MockSubscriptionRepository)
+	mockCashFlowRepo := new(MockCashFlowRepository)
+	mockBankAccountRepo := new(MockBankAccountRepository)
+	mockLedgerRepo := newClaimingLedgerRepository(db)
+
+	today := time.Date(2025, 10, 15, 0, 0, 0, 0, time.UTC)
+	categoryID := uuid.New()
+	accountID := uuid.New()
+
+	cashFlowService := NewCashFlowService(mockCashFlowRepo, newExpenseCategoryRepository(categoryID), mockBankAccountRepo, nil)
+	service := NewBillingService(mockSubscriptionRepo, nil, nil, nil, mockLedgerRepo, nil, cashFlowService, nil)
+
+	// 設定 mock 期望：餘額不足，登記的扣款記錄隨交易回滾
+	dbMock.ExpectBegin()
+	dbMock.ExpectRollback()
+	mockSubscriptionRepo.On("GetPriceChanges", mock.AnythingOfType("uuid.UUID")).Return([]*models.SubscriptionPriceChange{}, nil)
+	mockSubscriptionRepo.On("GetDueBillings", today).Return([]*models.Subscription{
+		{
+			ID:            uuid.New(),
+			Name:          "Netflix",
+			Amount:        390,
+			Currency:      models.CurrencyTWD,
+			BillingDay:    15,
+			CategoryID:    categoryID,
+			PaymentMethod: models.PaymentMethodBankAccount,
+			AccountID:     &accountID,
+		},
+	}, nil)
+	mockBankAccountRepo.On("GetByIDForUpdateTx", mock.AnythingOfType("*sql.Tx"), accountID).Return(&models.BankAccount{ID: accountID, Balance: 100}, nil)
+
+	// 執行測試
+	result, err := service.ProcessSubscriptionBilling(today)
+
+	// 驗證結果
+	assert.NoError(t, err)
+	assert.Equal(t, 0, result.ProcessedCount)
+	assert.Equal(t, 1, result.FailedCount)
+	assert.Contains(t, result.Errors[0].Message, "insufficient_balance")
+	mockBankAccountRepo.AssertNotCalled(t, "UpdateBalanceTx", mock.Anything, mock.Anything, mock.Anything)
+	mockCashFlowRepo.AssertNotCalled(t, "CreateTx", mock.Anything, mock.Anything)
+	assert.NoError(t, dbMock.ExpectationsWereMet())
 }
 
 // TestBillingService_ProcessInstallmentBilling 測試處理分期扣款
 func TestBillingService_ProcessInstallmentBilling(t *testing.T) {
+	db, dbMock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
 	mockSubscriptionRepo := new(MockSubscriptionRepository)
 	mockInstallmentRepo := new(MockInstallmentRepository)
+	mockLoanRepo := new(MockLoanRepository)
 	mockCashFlowRepo := new(MockCashFlowRepository)
-
-	service := NewBillingService(mockSubscriptionRepo, mockInstallmentRepo, mockCashFlowRepo)
+	mockBankAccountRepo := new(MockBankAccountRepository)
+	mockLedgerRepo := newClaimingLedgerRepository(db)
 
 	today := time.Date(2025, 10, 15, 0, 0, 0, 0, time.UTC)
 	categoryID := uuid.New()
 	installmentID := uuid.New()
+	accountID := uuid.New()
+
+	cashFlowService := NewCashFlowService(mockCashFlowRepo, newExpenseCategoryRepository(categoryID), mockBankAccountRepo, nil)
+	service := NewBillingService(mockSubscriptionRepo, mockInstallmentRepo, mockLoanRepo, nil, mockLedgerRepo, nil, cashFlowService, nil)
 
 	// 準備測試資料
 	installments := []*models.Installment{
@@ -91,6 +412,8 @@ func TestBillingService_ProcessInstallmentBilling(t *testing.T) {
 			PaidCount:         5,
 			BillingDay:        15,
 			CategoryID:        categoryID,
+			PaymentMethod:     models.PaymentMethodBankAccount,
+			AccountID:         &accountID,
 			StartDate:         time.Date(2025, 5, 15, 0, 0, 0, 0, time.UTC),
 			Status:            models.InstallmentStatusActive,
 		},
@@ -108,26 +431,18 @@ func TestBillingService_ProcessInstallmentBilling(t *testing.T) {
 		UpdatedAt:   time.Now(),
 	}
 
-	updatedInstallment := &models.Installment{
-		ID:                installmentID,
-		Name:              "iPhone 15 Pro",
-		TotalAmount:       36000,
-		Currency:          models.CurrencyTWD,
-		InstallmentCount:  12,
-		InstallmentAmount: 3000,
-		InterestRate:      0,
-		TotalInterest:     0,
-		PaidCount:         6, // 已付期數增加
-		BillingDay:        15,
-		CategoryID:        categoryID,
-		StartDate:         time.Date(2025, 5, 15, 0, 0, 0, 0, time.UTC),
-		Status:            models.InstallmentStatusActive,
-	}
-
-	// 設定 mock 期望
+	// 設定 mock 期望：扣款、扣減帳戶餘額與更新期數在同一交易中完成
+	dbMock.ExpectBegin()
+	dbMock.ExpectCommit()
 	mockInstallmentRepo.On("GetDueBillings", today).Return(installments, nil)
-	mockCashFlowRepo.On("Create", mock.AnythingOfType("*models.CreateCashFlowInput")).Return(expectedCashFlow, nil)
-	mockInstallmentRepo.On("Update", installmentID, mock.AnythingOfType("*models.UpdateInstallmentInput")).Return(updatedInstallment, nil)
+	mockBankAccountRepo.On("GetByIDForUpdateTx", mock.AnythingOfType("*sql.Tx"), accountID).Return(&models.BankAccount{ID: accountID, Balance: 50000}, nil)
+	mockBankAccountRepo.On("UpdateBalanceTx", mock.AnythingOfType("*sql.Tx"), accountID, -3000.0).Return(&models.BankAccount{}, nil)
+	mockCashFlowRepo.On("CreateTx", mock.AnythingOfType("*sql.Tx"), mock.AnythingOfType("*models.CreateCashFlowInput")).Return(expectedCashFlow, nil)
+	mockInstallmentRepo.On("GetPrepayments", installmentID).Return([]*models.InstallmentPrepayment{}, nil)
+	mockInstallmentRepo.On("CreatePaymentTx", mock.AnythingOfType("*sql.Tx"), mock.MatchedBy(func(payment *models.InstallmentPayment) bool {
+		return payment.Period == 6 && payment.PrincipalAmount == 3000 && payment.InterestAmount == 0 && *payment.CashFlowID == expectedCashFlow.ID
+	})).Return(&models.InstallmentPayment{}, nil)
+	mockInstallmentRepo.On("UpdateProgressTx", mock.AnythingOfType("*sql.Tx"), installmentID, 6, models.InstallmentStatusActive).Return(nil)
 
 	// 執行測試
 	result, err := service.ProcessInstallmentBilling(today)
@@ -139,22 +454,123 @@ func TestBillingService_ProcessInstallmentBilling(t *testing.T) {
 	assert.Equal(t, 0, result.FailedCount)
 	assert.Len(t, result.CreatedCashFlows, 1)
 	mockInstallmentRepo.AssertExpectations(t)
+	mockBankAccountRepo.AssertExpectations(t)
 	mockCashFlowRepo.AssertExpectations(t)
+	assert.NoError(t, dbMock.ExpectationsWereMet())
+}
+
+// TestBillingService_ProcessInstallmentBilling_RollsBackOnFailure 測試更新期數失敗時整筆扣款回滾
+func TestBillingService_ProcessInstallmentBilling_RollsBackOnFailure(t *testing.T) {
+	db, dbMock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mockInstallmentRepo := new(MockInstallmentRepository)
+	mockCashFlowRepo := new(MockCashFlowRepository)
+	mockLedgerRepo := newClaimingLedgerRepository(db)
+
+	today := time.Date(2025, 10, 15, 0, 0, 0, 0, time.UTC)
+	categoryID := uuid.New()
+	installmentID := uuid.New()
+
+	cashFlowService := NewCashFlowService(mockCashFlowRepo, newExpenseCategoryRepository(categoryID), nil, nil)
+	service := NewBillingService(nil, mockInstallmentRepo, nil, nil, mockLedgerRepo, nil, cashFlowService, nil)
+
+	// 設定 mock 期望
+	dbMock.ExpectBegin()
+	dbMock.ExpectRollback()
+	mockInstallmentRepo.On("GetDueBillings", today).Return([]*models.Installment{
+		{
+			ID:                installmentID,
+			Name:              "iPhone 15 Pro",
+			TotalAmount:       36000,
+			Currency:          models.CurrencyTWD,
+			InstallmentCount:  12,
+			InstallmentAmount: 3000,
+			PaidCount:         5,
+			BillingDay:        15,
+			CategoryID:        categoryID,
+			PaymentMethod:     models.PaymentMethodCash,
+			Status:            models.InstallmentStatusActive,
+		},
+	}, nil)
+	mockCashFlowRepo.On("CreateTx", mock.AnythingOfType("*sql.Tx"), mock.AnythingOfType("*models.CreateCashFlowInput")).Return(&models.CashFlow{ID: uuid.New(), Amount: 3000}, nil)
+	mockInstallmentRepo.On("GetPrepayments", installmentID).Return([]*models.InstallmentPrepayment{}, nil)
+	mockInstallmentRepo.On("CreatePaymentTx", mock.AnythingOfType("*sql.Tx"), mock.AnythingOfType("*models.InstallmentPayment")).Return(&models.InstallmentPayment{}, nil)
+	mockInstallmentRepo.On("UpdateProgressTx", mock.AnythingOfType("*sql.Tx"), installmentID, 6, models.InstallmentStatusActive).Return(assert.AnError)
+
+	// 執行測試
+	result, err := service.ProcessInstallmentBilling(today)
+
+	// 驗證結果：不計入已處理，也不回傳已回滾的現金流
+	assert.NoError(t, err)
+	assert.Equal(t, 0, result.ProcessedCount)
+	assert.Equal(t, 1, result.FailedCount)
+	assert.Empty(t, result.CreatedCashFlows)
+	assert.NoError(t, dbMock.ExpectationsWereMet())
+}
+
+// TestBillingService_ProcessInstallmentBilling_PaidOff 測試提前清償後不再扣款並標記為已完成
+func TestBillingService_ProcessInstallmentBilling_PaidOff(t *testing.T) {
+	mockInstallmentRepo := new(MockInstallmentRepository)
+	mockLedgerRepo := new(MockBillingLedgerRepository)
+	service := NewBillingService(nil, mockInstallmentRepo, nil, nil, mockLedgerRepo, nil, nil, nil)
+
+	today := time.Date(2025, 10, 15, 0, 0, 0, 0, time.UTC)
+	installmentID := uuid.New()
+
+	// 設定 mock 期望
+	mockInstallmentRepo.On("GetDueBillings", today).Return([]*models.Installment{
+		{
+			ID:                installmentID,
+			Name:              "iPhone 15 Pro",
+			TotalAmount:       36000,
+			Currency:          models.CurrencyTWD,
+			InstallmentCount:  12,
+			InstallmentAmount: 3000,
+			PaidCount:         4,
+			BillingDay:        15,
+			PaymentMethod:     models.PaymentMethodCash,
+			StartDate:         time.Date(2025, 5, 15, 0, 0, 0, 0, time.UTC),
+			Status:            models.InstallmentStatusActive,
+		},
+	}, nil)
+	mockInstallmentRepo.On("GetPrepayments", installmentID).Return([]*models.InstallmentPrepayment{
+		{InstallmentID: installmentID, Amount: 24000, AfterPeriod: 4, IsPayoff: true},
+	}, nil)
+	mockInstallmentRepo.On("Update", installmentID, mock.MatchedBy(func(input *models.UpdateInstallmentInput) bool {
+		return input.Status != nil && *input.Status == models.InstallmentStatusCompleted
+	})).Return(&models.Installment{}, nil)
+
+	// 執行測試
+	result, err := service.ProcessInstallmentBilling(today)
+
+	// 驗證結果：不建立現金流也不登記扣款
+	assert.NoError(t, err)
+	assert.Equal(t, 0, result.ProcessedCount)
+	assert.Equal(t, 0, result.FailedCount)
+	assert.Empty(t, result.CreatedCashFlows)
+	mockInstallmentRepo.AssertExpectations(t)
+	mockLedgerRepo.AssertNotCalled(t, "ClaimRecordTx")
 }
 
 // TestBillingService_ProcessDailyBilling 測試處理每日扣款
 func TestBillingService_ProcessDailyBilling(t *testing.T) {
 	mockSubscriptionRepo := new(MockSubscriptionRepository)
 	mockInstallmentRepo := new(MockInstallmentRepository)
-	mockCashFlowRepo := new(MockCashFlowRepository)
+	mockLoanRepo := new(MockLoanRepository)
+	mockRecurringRepo := new(MockRecurringTemplateRepository)
+	mockLedgerRepo := newClaimingLedgerRepository(nil)
 
-	service := NewBillingService(mockSubscriptionRepo, mockInstallmentRepo, mockCashFlowRepo)
+	service := NewBillingService(mockSubscriptionRepo, mockInstallmentRepo, mockLoanRepo, mockRecurringRepo, mockLedgerRepo, nil, nil, nil)
 
 	today := time.Date(2025, 10, 15, 0, 0, 0, 0, time.UTC)
 
 	// 設定 mock 期望
 	mockSubscriptionRepo.On("GetDueBillings", today).Return([]*models.Subscription{}, nil)
 	mockInstallmentRepo.On("GetDueBillings", today).Return([]*models.Installment{}, nil)
+	mockLoanRepo.On("GetDueBillings", today).Return([]*models.Loan{}, nil)
+	mockRecurringRepo.On("GetActive", today).Return([]*models.RecurringTemplate{}, nil)
 
 	// 執行測試
 	result, err := service.ProcessDailyBilling(today)
@@ -164,7 +580,315 @@ func TestBillingService_ProcessDailyBilling(t *testing.T) {
 	assert.NotNil(t, result)
 	assert.Equal(t, 0, result.SubscriptionCount)
 	assert.Equal(t, 0, result.InstallmentCount)
+	assert.Equal(t, 0, result.LoanCount)
 	mockSubscriptionRepo.AssertExpectations(t)
 	mockInstallmentRepo.AssertExpectations(t)
+	mockLoanRepo.AssertExpectations(t)
+}
+
+// TestBillingService_ProcessLoanBilling 測試處理貸款扣款（本金與利息分開記錄）
+func TestBillingService_ProcessLoanBilling(t *testing.T) {
+	db, dbMock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mockSubscriptionRepo := new(MockSubscriptionRepository)
+	mockInstallmentRepo := new(MockInstallmentRepository)
+	mockLoanRepo := new(MockLoanRepository)
+	mockCashFlowRepo := new(MockCashFlowRepository)
+	mockLedgerRepo := newClaimingLedgerRepository(db)
+
+	today := time.Date(2025, 2, 10, 0, 0, 0, 0, time.UTC)
+	categoryID := uuid.New()
+	interestCategoryID := uuid.New()
+	loanID := uuid.New()
+
+	cashFlowService := NewCashFlowService(mockCashFlowRepo, newExpenseCategoryRepository(categoryID, interestCategoryID), nil, nil)
+	service := NewBillingService(mockSubscriptionRepo, mockInstallmentRepo, mockLoanRepo, nil, mockLedgerRepo, nil, cashFlowService, nil)
+
+	// 準備測試資料：12 萬、年利率 12%、12 期，首期利息為 1200
+	loans := []*models.Loan{
+		{
+			ID:                 loanID,
+			Name:               "信貸",
+			LoanType:           models.LoanTypePersonal,
+			Principal:          120000,
+			Currency:           models.CurrencyTWD,
+			AnnualRate:         12,
+			TermMonths:         12,
+			PaymentDay:         10,
+			PaidCount:          0,
+			StartDate:          time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC),
+			CategoryID:         categoryID,
+			InterestCategoryID: &interestCategoryID,
+			PaymentMethod:      models.PaymentMethodCash,
+			Status:             models.LoanStatusActive,
+		},
+	}
+
+	// 設定 mock 期望
+	dbMock.ExpectBegin()
+	dbMock.ExpectCommit()
+	mockLoanRepo.On("GetDueBillings", today).Return(loans, nil)
+	mockLoanRepo.On("GetRateChanges", loanID).Return([]*models.LoanRateChange{}, nil)
+	mockLoanRepo.On("GetPrepayments", loanID).Return([]*models.LoanPrepayment{}, nil)
+	mockCashFlowRepo.On("CreateTx", mock.AnythingOfType("*sql.Tx"), mock.MatchedBy(func(input *models.CreateCashFlowInput) bool {
+		return input.CategoryID == categoryID && input.Amount == 9461.85
+	})).Return(&models.CashFlow{ID: uuid.New(), Amount: 9461.85}, nil)
+	mockCashFlowRepo.On("CreateTx", mock.AnythingOfType("*sql.Tx"), mock.MatchedBy(func(input *models.CreateCashFlowInput) bool {
+		return input.CategoryID == interestCategoryID && input.Amount == 1200
+	})).Return(&models.CashFlow{ID: uuid.New(), Amount: 1200}, nil)
+	mockLoanRepo.On("CreatePaymentTx", mock.AnythingOfType("*sql.Tx"), mock.MatchedBy(func(payment *models.LoanPayment) bool {
+		return payment.Period == 1 && payment.PrincipalCashFlowID != nil && payment.InterestCashFlowID != nil
+	})).Return(&models.LoanPayment{ID: uuid.New()}, nil)
+	mockLoanRepo.On("UpdateProgressTx", mock.AnythingOfType("*sql.Tx"), loanID, 1, models.LoanStatusActive).Return(nil)
+
+	// 執行測試
+	result, err := service.ProcessLoanBilling(today)
+
+	// 驗證結果
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+	assert.Equal(t, 1, result.ProcessedCount)
+	assert.Equal(t, 0, result.FailedCount)
+	assert.Len(t, result.CreatedCashFlows, 2)
+	mockLoanRepo.AssertExpectations(t)
+	mockCashFlowRepo.AssertExpectations(t)
+	assert.NoError(t, dbMock.ExpectationsWereMet())
+}
+
+// TestBillingService_ProcessSubscriptionBilling_AlreadyBilled 測試同一期重複執行時不會重複扣款
+func TestBillingService_ProcessSubscriptionBilling_AlreadyBilled(t *testing.T) {
+	db, dbMock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mockSubscriptionRepo := new(MockSubscriptionRepository)
+	mockCashFlowRepo := new(MockCashFlowRepository)
+	mockLedgerRepo := new(MockBillingLedgerRepository)
+
+	cashFlowService := NewCashFlowService(mockCashFlowRepo, nil, nil, nil)
+	service := NewBillingService(mockSubscriptionRepo, nil, nil, nil, mockLedgerRepo, nil, cashFlowService, nil)
+
+	today := time.Date(2025, 10, 15, 0, 0, 0, 0, time.UTC)
+	subscriptionID := uuid.New()
+
+	// 設定 mock 期望：本期已有扣款記錄
+	dbMock.ExpectBegin()
+	dbMock.ExpectRollback()
+	mockLedgerRepo.On("DB").Return(db)
+	mockSubscriptionRepo.On("GetPriceChanges", mock.AnythingOfType("uuid.UUID")).Return([]*models.SubscriptionPriceChange{}, nil)
+	mockSubscriptionRepo.On("GetDueBillings", today).Return([]*models.Subscription{
+		{ID: subscriptionID, Name: "Netflix", Amount: 390, BillingDay: 15},
+	}, nil)
+	mockLedgerRepo.On("ClaimRecordTx", mock.AnythingOfType("*sql.Tx"), mock.MatchedBy(func(record *models.BillingRecord) bool {
+		return record.SourceType == models.BillingSourceSubscription && record.SourceID == subscriptionID && record.Period == "2025-10"
+	})).Return(false, nil)
+
+	// 執行測試
+	result, err := service.ProcessSubscriptionBilling(today)
+
+	// 驗證結果
+	assert.NoError(t, err)
+	assert.Equal(t, 0, result.ProcessedCount)
+	assert.Equal(t, 1, result.SkippedCount)
+	mockCashFlowRepo.AssertNotCalled(t, "CreateTx", mock.Anything, mock.Anything)
+	assert.NoError(t, dbMock.ExpectationsWereMet())
+}
+
+// TestBillingService_CatchUpBilling 測試補跑最近一次成功執行之後漏掉的扣款日
+func TestBillingService_CatchUpBilling(t *testing.T) {
+	mockSubscriptionRepo := new(MockSubscriptionRepository)
+	mockInstallmentRepo := new(MockInstallmentRepository)
+	mockLoanRepo := new(MockLoanRepository)
+	mockRecurringRepo := new(MockRecurringTemplateRepository)
+	mockLedgerRepo := new(MockBillingLedgerRepository)
+
+	service := NewBillingService(mockSubscriptionRepo, mockInstallmentRepo, mockLoanRepo, mockRecurringRepo, mockLedgerRepo, nil, nil, nil)
+
+	lastRunDate := time.Date(2025, 10, 13, 0, 0, 0, 0, time.UTC)
+	today := time.Date(2025, 10, 16, 9, 30, 0, 0, time.UTC)
+
+	// 設定 mock 期望：上次成功執行為 10/13，需補跑 10/14 至 10/16
+	mockLedgerRepo.On("GetLastSuccessfulRun").Return(&models.BillingRun{BillingDate: lastRunDate, Status: models.BillingRunStatusSuccess}, nil)
+	mockLedgerRepo.On("ListRunsSince", time.Date(2025, 9, 15, 0, 0, 0, 0, time.UTC)).Return([]*models.BillingRun{
+		{BillingDate: lastRunDate, Status: models.BillingRunStatusSuccess},
+	}, nil)
+	mockSubscriptionRepo.On("GetDueBillings", mock.AnythingOfType("time.Time")).Return([]*models.Subscription{}, nil)
+	mockInstallmentRepo.On("GetDueBillings", mock.AnythingOfType("time.Time")).Return([]*models.Installment{}, nil)
+	mockLoanRepo.On("GetDueBillings", mock.AnythingOfType("time.Time")).Return([]*models.Loan{}, nil)
+	mockRecurringRepo.On("GetActive", mock.AnythingOfType("time.Time")).Return([]*models.RecurringTemplate{}, nil)
+	mockLedgerRepo.On("UpsertRun", mock.MatchedBy(func(run *models.BillingRun) bool {
+		return run.Status == models.BillingRunStatusSuccess
+	})).Return(&models.BillingRun{}, nil)
+
+	// 執行測試
+	results, err := service.CatchUpBilling(today)
+
+	// 驗證結果
+	assert.NoError(t, err)
+	assert.Len(t, results, 3)
+	assert.Equal(t, time.Date(2025, 10, 14, 0, 0, 0, 0, time.UTC), results[0].Date)
+	assert.Equal(t, time.Date(2025, 10, 16, 0, 0, 0, 0, time.UTC), results[2].Date)
+	mockSubscriptionRepo.AssertNumberOfCalls(t, "GetDueBillings", 3)
+	mockLedgerRepo.AssertNumberOfCalls(t, "UpsertRun", 3)
+}
+
+// TestBillingService_CatchUpBilling_RetriesFailedDay 測試失敗的扣款日即使之後已有成功執行仍會重試
+func TestBillingService_CatchUpBilling_RetriesFailedDay(t *testing.T) {
+	mockSubscriptionRepo := new(MockSubscriptionRepository)
+	mockInstallmentRepo := new(MockInstallmentRepository)
+	mockLoanRepo := new(MockLoanRepository)
+	mockRecurringRepo := new(MockRecurringTemplateRepository)
+	mockLedgerRepo := new(MockBillingLedgerRepository)
+
+	service := NewBillingService(mockSubscriptionRepo, mockInstallmentRepo, mockLoanRepo, mockRecurringRepo, mockLedgerRepo, nil, nil, nil)
+
+	failedDate := time.Date(2025, 10, 14, 0, 0, 0, 0, time.UTC)
+	lastRunDate := time.Date(2025, 10, 15, 0, 0, 0, 0, time.UTC)
+	today := time.Date(2025, 10, 16, 0, 0, 0, 0, time.UTC)
+
+	// 設定 mock 期望：10/14 失敗、10/15 成功，需重試 10/14 並處理 10/16
+	mockLedgerRepo.On("GetLastSuccessfulRun").Return(&models.BillingRun{BillingDate: lastRunDate, Status: models.BillingRunStatusSuccess}, nil)
+	mockLedgerRepo.On("ListRunsSince", mock.AnythingOfType("time.Time")).Return([]*models.BillingRun{
+		{BillingDate: failedDate, Status: models.BillingRunStatusFailed},
+		{BillingDate: lastRunDate, Status: models.BillingRunStatusSuccess},
+	}, nil)
+	mockSubscriptionRepo.On("GetDueBillings", mock.AnythingOfType("time.Time")).Return([]*models.Subscription{}, nil)
+	mockInstallmentRepo.On("GetDueBillings", mock.AnythingOfType("time.Time")).Return([]*models.Installment{}, nil)
+	mockLoanRepo.On("GetDueBillings", mock.AnythingOfType("time.Time")).Return([]*models.Loan{}, nil)
+	mockRecurringRepo.On("GetActive", mock.AnythingOfType("time.Time")).Return([]*models.RecurringTemplate{}, nil)
+	mockLedgerRepo.On("UpsertRun", mock.AnythingOfType("*models.BillingRun")).Return(&models.BillingRun{}, nil)
+
+	// 執行測試
+	results, err := service.CatchUpBilling(today)
+
+	// 驗證結果
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+	assert.Equal(t, failedDate, results[0].Date)
+	assert.Equal(t, today, results[1].Date)
+	mockSubscriptionRepo.AssertNotCalled(t, "GetDueBillings", lastRunDate)
+}
+
+// TestBillingService_CatchUpBilling_NoHistory 測試尚無執行記錄時只處理當日
+func TestBillingService_CatchUpBilling_NoHistory(t *testing.T) {
+	mockSubscriptionRepo := new(MockSubscriptionRepository)
+	mockInstallmentRepo := new(MockInstallmentRepository)
+	mockLoanRepo := new(MockLoanRepository)
+	mockRecurringRepo := new(MockRecurringTemplateRepository)
+	mockLedgerRepo := new(MockBillingLedgerRepository)
+
+	service := NewBillingService(mockSubscriptionRepo, mockInstallmentRepo, mockLoanRepo, mockRecurringRepo, mockLedgerRepo, nil, nil, nil)
+
+	today := time.Date(2025, 10, 16, 0, 0, 0, 0, time.UTC)
+
+	// 設定 mock 期望
+	mockLedgerRepo.On("GetLastSuccessfulRun").Return(nil, nil)
+	mockLedgerRepo.On("ListRunsSince", mock.AnythingOfType("time.Time")).Return([]*models.BillingRun{}, nil)
+	mockSubscriptionRepo.On("GetDueBillings", today).Return([]*models.Subscription{}, nil)
+	mockInstallmentRepo.On("GetDueBillings", today).Return([]*models.Installment{}, nil)
+	mockLoanRepo.On("GetDueBillings", today).Return([]*models.Loan{}, nil)
+	mockRecurringRepo.On("GetActive", today).Return([]*models.RecurringTemplate{}, nil)
+	mockLedgerRepo.On("UpsertRun", mock.AnythingOfType("*models.BillingRun")).Return(&models.BillingRun{}, nil)
+
+	// 執行測試
+	results, err := service.CatchUpBilling(today)
+
+	// 驗證結果
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
 }
 
+// TestBillingService_ProcessRecurringBilling 測試定期收支產生收入現金流（月底最後一個營業日入帳、套用調薪）
+func TestBillingService_ProcessRecurringBilling(t *testing.T) {
+	db, dbMock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mockRecurringRepo := new(MockRecurringTemplateRepository)
+	mockCashFlowRepo := new(MockCashFlowRepository)
+	mockBankAccountRepo := new(MockBankAccountRepository)
+	mockCategoryRepo := new(MockCategoryRepository)
+	mockLedgerRepo := new(MockBillingLedgerRepository)
+
+	// 2025-05-31 是週六，薪資提前至 5/30 入帳
+	today := time.Date(2025, 5, 30, 0, 0, 0, 0, time.UTC)
+	categoryID := uuid.New()
+	salaryID := uuid.New()
+	rentID := uuid.New()
+	accountID := uuid.New()
+	lastDay := models.LastDayOfMonth
+	rentDay := 5
+
+	cashFlowService := NewCashFlowService(mockCashFlowRepo, mockCategoryRepo, mockBankAccountRepo, nil)
+	service := NewBillingService(nil, nil, nil, mockRecurringRepo, mockLedgerRepo, nil, cashFlowService, nil)
+
+	templates := []*models.RecurringTemplate{
+		{
+			ID:                    salaryID,
+			Name:                  "薪資",
+			Type:                  models.CashFlowTypeIncome,
+			CategoryID:            categoryID,
+			Amount:                50000,
+			Currency:              models.CurrencyTWD,
+			PaymentMethod:         models.PaymentMethodBankAccount,
+			AccountID:             &accountID,
+			Frequency:             models.RecurrenceMonthly,
+			Interval:              1,
+			ByMonthDay:            &lastDay,
+			BusinessDayAdjustment: models.BusinessDayAdjustmentPrevious,
+			StartDate:             time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			Status:                models.RecurringTemplateStatusActive,
+		},
+		{
+			// 每月 5 日收租，今天不會發生
+			ID:                    rentID,
+			Name:                  "房租收入",
+			Type:                  models.CashFlowTypeIncome,
+			CategoryID:            categoryID,
+			Amount:                15000,
+			Currency:              models.CurrencyTWD,
+			PaymentMethod:         models.PaymentMethodCash,
+			Frequency:             models.RecurrenceMonthly,
+			Interval:              1,
+			ByMonthDay:            &rentDay,
+			BusinessDayAdjustment: models.BusinessDayAdjustmentNone,
+			StartDate:             time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			Status:                models.RecurringTemplateStatusActive,
+		},
+	}
+
+	// 設定 mock 期望
+	dbMock.ExpectBegin()
+	dbMock.ExpectCommit()
+	mockRecurringRepo.On("GetActive", today).Return(templates, nil)
+	mockRecurringRepo.On("GetAmountChanges", salaryID).Return([]*models.RecurringAmountChange{
+		{TemplateID: salaryID, EffectiveDate: time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC), Amount: 53000},
+	}, nil)
+	mockLedgerRepo.On("DB").Return(db)
+	mockLedgerRepo.On("ClaimRecordTx", mock.AnythingOfType("*sql.Tx"), mock.MatchedBy(func(record *models.BillingRecord) bool {
+		return record.SourceType == models.BillingSourceRecurring && record.SourceID == salaryID && record.Period == "2025-05-30"
+	})).Return(true, nil)
+	mockCategoryRepo.On("GetByID", categoryID).Return(&models.CashFlowCategory{ID: categoryID, Type: models.CashFlowTypeIncome}, nil)
+	mockBankAccountRepo.On("GetByIDForUpdateTx", mock.AnythingOfType("*sql.Tx"), accountID).Return(&models.BankAccount{ID: accountID, Balance: 10000}, nil).Maybe()
+	mockBankAccountRepo.On("UpdateBalanceTx", mock.AnythingOfType("*sql.Tx"), accountID, 53000.0).Return(&models.BankAccount{}, nil)
+	mockCashFlowRepo.On("CreateTx", mock.AnythingOfType("*sql.Tx"), mock.MatchedBy(func(input *models.CreateCashFlowInput) bool {
+		return input.Type == models.CashFlowTypeIncome && input.Amount == 53000 && input.Description == "薪資 - 定期收支"
+	})).Return(&models.CashFlow{ID: uuid.New(), Type: models.CashFlowTypeIncome, Amount: 53000}, nil)
+
+	// 執行測試
+	result, err := service.ProcessRecurringBilling(today)
+
+	// 驗證結果
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+	assert.Equal(t, 1, result.ProcessedCount)
+	assert.Equal(t, 0, result.FailedCount)
+	assert.Len(t, result.CreatedCashFlows, 1)
+	mockRecurringRepo.AssertNotCalled(t, "GetAmountChanges", rentID)
+	mockLedgerRepo.AssertExpectations(t)
+	mockBankAccountRepo.AssertExpectations(t)
+	mockCashFlowRepo.AssertExpectations(t)
+	assert.NoError(t, dbMock.ExpectationsWereMet())
+}
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/chienchuanw/asset-manager/internal/models"
+)
+
+// binanceImporter Binance 現貨交易歷史匯入器
+// 支援新版（Date(UTC), Pair, Side, Price, Executed, Amount, Fee，數量帶幣別後綴）與舊版
+// （Date(UTC), Market, Type, Price, Amount, Total, Fee, Fee Coin）匯出格式；僅匯入以美元穩定幣計價的交易對，
+// 幣幣交易對請改以互換記錄。交易歷史不含質押與空投收入
+type binanceImporter struct{}
+
+// Info 對帳單格式說明
+func (i *binanceImporter) Info() models.BrokerStatementFormatInfo {
+	return models.BrokerStatementFormatInfo{
+		Format:      models.BrokerStatementFormatBinance,
+		Name:        "Binance",
+		AssetType:   models.AssetTypeCrypto,
+		Description: "現貨交易歷史（Orders → Trade History → Export）；以美元穩定幣計價的交易對視為美元，手續費換算為美元",
+	}
+}
+
+// Parse 解析交易歷史
+func (i *binanceImporter) Parse(data []byte) (*models.BrokerStatementPreview, error) {
+	header, records, err := readStatementCSV(data, []string{"Date(UTC)", "Date (UTC)", "Date"}, []string{"Pair", "Market"}, []string{"Side", "Type"})
+	if err != nil {
+		return nil, err
+	}
+
+	col := func(names ...string) int { return findColumn(header, names...) }
+	dateCol, pairCol, sideCol, priceCol := col("Date(UTC)", "Date (UTC)", "Date"), col("Pair", "Market"), col("Side", "Type"), col("Price")
+	executedCol, amountCol, totalCol := col("Executed"), col("Amount"), col("Total")
+	feeCol, feeCoinCol := col("Fee"), col("Fee Coin")
+	// 新版格式以 Executed 記錄成交數量、Amount 記錄成交金額
+	legacy := executedCol < 0
+	if legacy {
+		executedCol, amountCol = amountCol, totalCol
+	}
+
+	preview := &models.BrokerStatementPreview{}
+	for index, record := range records {
+		row := index + 1
+		if isBlankRecord(record) {
+			continue
+		}
+
+		date, err := parseStatementDate(csvField(record, dateCol))
+		if err != nil {
+			preview.Errors = append(preview.Errors, statementRowError(row, "date", err.Error()))
+			continue
+		}
+
+		side := strings.ToUpper(csvField(record, sideCol))
+		var transactionType models.TransactionType
+		switch side {
+		case "BUY":
+			transactionType = models.TransactionTypeBuy
+		case "SELL":
+			transactionType = models.TransactionTypeSell
+		default:
+			preview.Errors = append(preview.Errors, statementRowError(row, "transaction_type", fmt.Sprintf("不支援的交易方向: %s", side)))
+			continue
+		}
+
+		quantity, base, errQuantity := splitBinanceAmount(csvField(record, executedCol))
+		amount, quote, errAmount := splitBinanceAmount(csvField(record, amountCol))
+		price, errPrice := parseStatementNumber(csvField(record, priceCol))
+		fee, feeCoin, errFee := splitBinanceAmount(csvField(record, feeCol))
+		if err := firstError(errQuantity, errAmount, errPrice, errFee); err != nil {
+			preview.Errors = append(preview.Errors, statementRowError(row, "row", err.Error()))
+			continue
+		}
+		if legacy {
+			base, quote = splitBinancePair(csvField(record, pairCol))
+			feeCoin = strings.ToUpper(csvField(record, feeCoinCol))
+		}
+
+		pair := strings.ToUpper(csvField(record, pairCol))
+		if base == "" || quantity <= 0 {
+			preview.Errors = append(preview.Errors, statementRowError(row, "quantity", fmt.Sprintf("無法判斷 %s 的成交數量", pair)))
+			continue
+		}
+		if quote != "USD" && !models.IsStablecoin(quote) {
+			preview.Warnings = append(preview.Warnings, statementRowError(row, "symbol",
+				fmt.Sprintf("略過非美元穩定幣計價的交易對 %s，請改以互換記錄", pair)))
+			continue
+		}
+
+		if amount == 0 {
+			amount = quantity * price
+		}
+		if price == 0 {
+			price = amount / quantity
+		}
+
+		note := fmt.Sprintf("匯入自 Binance 交易歷史（%s）", pair)
+		tx := &models.CreateTransactionInput{
+			Date:            date,
+			AssetType:       models.AssetTypeCrypto,
+			Symbol:          base,
+			TransactionType: transactionType,
+			Quantity:        quantity,
+			Price:           price,
+			Amount:          amount,
+			Currency:        models.CurrencyUSD,
+			Note:            &note,
+		}
+
+		// 手續費換算為美元：以計價幣支付時直接使用，以交易幣支付時乘上成交價，其他幣別（例如 BNB）需手動填寫
+		switch {
+		case fee == 0:
+			zero := 0.0
+			tx.Fee = &zero
+		case feeCoin == "" || feeCoin == quote:
+			tx.Fee = &fee
+		case feeCoin == base:
+			feeValue := math.Round(fee*price*100) / 100
+			tx.Fee = &feeValue
+		default:
+			preview.Warnings = append(preview.Warnings, statementRowError(row, "fee",
+				fmt.Sprintf("手續費以 %s %g 支付，請手動填寫換算後的美元金額", feeCoin, fee)))
+		}
+
+		preview.Entries = append(preview.Entries, &models.BrokerStatementEntry{Row: row, Transaction: tx})
+	}
+
+	return preview, nil
+}
+
+// binanceQuoteAssets 依長度排序的常見計價幣（用於拆解舊版格式的交易對）
+var binanceQuoteAssets = []string{"FDUSD", "USDT", "USDC", "BUSD", "TUSD", "USDP", "DAI", "USD", "BTC", "ETH", "BNB"}
+
+// splitBinancePair 拆解交易對為交易幣與計價幣（例如 BTCUSDT → BTC、USDT）
+func splitBinancePair(pair string) (string, string) {
+	pair = strings.ToUpper(strings.NewReplacer("/", "", "-", "", "_", "").Replace(strings.TrimSpace(pair)))
+	for _, quote := range binanceQuoteAssets {
+		if strings.HasSuffix(pair, quote) && len(pair) > len(quote) {
+			return strings.TrimSuffix(pair, quote), quote
+		}
+	}
+	return "", ""
+}
+
+// splitBinanceAmount 拆解帶幣別後綴的數量（例如 "0.01BTC" → 0.01、BTC；純數字時幣別為空字串）
+func splitBinanceAmount(value string) (float64, string, error) {
+	value = strings.ReplaceAll(strings.TrimSpace(value), ",", "")
+	end := 0
+	for end < len(value) && (value[end] == '.' || value[end] == '-' || (value[end] >= '0' && value[end] <= '9')) {
+		end++
+	}
+
+	number, err := parseStatementNumber(value[:end])
+	if err != nil {
+		return 0, "", err
+	}
+	return number, strings.ToUpper(strings.TrimSpace(value[end:])), nil
+}
+
+// firstError 回傳第一個非 nil 的錯誤
+func firstError(errs ...error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+
+	"github.com/chienchuanw/asset-manager/internal/models"
+)
+
+// firstradeImporter Firstrade 帳戶歷史匯入器
+// 欄位為 Symbol, Quantity, Price, Action, Description, TradeDate, SettledDate, Interest, Amount, Commission, Fee, CUSIP, RecordType；
+// RecordType 為 Trade 的是成交，Financial 中的股息與代扣稅款（NRA TAX）合併為股息交易，其餘現金異動略過
+type firstradeImporter struct{}
+
+// firstradeSharesPattern 股息說明中的持股數（例如 "CASH DIV ON 100 SHS"）
+var firstradeSharesPattern = regexp.MustCompile(`(?i)ON\s+([0-9,]*\.?[0-9]+)\s+SHS`)
+
+// Info 對帳單格式說明
+func (i *firstradeImporter) Info() models.BrokerStatementFormatInfo {
+	return models.BrokerStatementFormatInfo{
+		Format:      models.BrokerStatementFormatFirstrade,
+		Name:        "Firstrade",
+		AssetType:   models.AssetTypeUSStock,
+		Description: "帳戶歷史（Accounts → History → Download CSV）；Commission 記為手續費、Fee（SEC / TAF 規費）記為交易稅",
+	}
+}
+
+// Parse 解析帳戶歷史
+func (i *firstradeImporter) Parse(data []byte) (*models.BrokerStatementPreview, error) {
+	header, records, err := readStatementCSV(data, []string{"Symbol"}, []string{"Action"}, []string{"TradeDate", "Trade Date"}, []string{"RecordType", "Record Type"})
+	if err != nil {
+		return nil, err
+	}
+
+	col := func(names ...string) int { return findColumn(header, names...) }
+	symbolCol, quantityCol, priceCol := col("Symbol"), col("Quantity"), col("Price")
+	actionCol, descriptionCol, dateCol := col("Action"), col("Description"), col("TradeDate", "Trade Date")
+	amountCol, commissionCol, feeCol, recordTypeCol := col("Amount"), col("Commission"), col("Fee"), col("RecordType", "Record Type")
+
+	preview := &models.BrokerStatementPreview{}
+	withholding := make(map[string]float64)
+	withholdingRows := make(map[string]int)
+
+	for index, record := range records {
+		row := index + 1
+		if isBlankRecord(record) {
+			continue
+		}
+
+		symbol := strings.ToUpper(csvField(record, symbolCol))
+		action := strings.ToUpper(csvField(record, actionCol))
+		description := csvField(record, descriptionCol)
+		recordType := strings.ToUpper(csvField(record, recordTypeCol))
+
+		date, err := parseStatementDate(csvField(record, dateCol))
+		if err != nil {
+			preview.Errors = append(preview.Errors, statementRowError(row, "date", err.Error()))
+			continue
+		}
+
+		numbers := make(map[string]float64)
+		numberErr := false
+		for field, index := range map[string]int{"quantity": quantityCol, "price": priceCol, "amount": amountCol, "commission": commissionCol, "fee": feeCol} {
+			value, err := parseStatementNumber(csvField(record, index))
+			if err != nil {
+				preview.Errors = append(preview.Errors, statementRowError(row, field, err.Error()))
+				numberErr = true
+				break
+			}
+			numbers[field] = value
+		}
+		if numberErr {
+			continue
+		}
+
+		note := "匯入自 Firstrade 對帳單"
+		upperDescription := strings.ToUpper(description)
+
+		switch {
+		case recordType == "TRADE":
+			transactionType, ok := firstradeTransactionType(action)
+			if !ok {
+				preview.Errors = append(preview.Errors, statementRowError(row, "transaction_type", fmt.Sprintf("不支援的交易類別: %s", action)))
+				continue
+			}
+			quantity, price := math.Abs(numbers["quantity"]), math.Abs(numbers["price"])
+			if symbol == "" || quantity == 0 {
+				preview.Errors = append(preview.Errors, statementRowError(row, "quantity", "缺少標的代碼或成交數量"))
+				continue
+			}
+
+			fee := math.Abs(numbers["commission"])
+			tx := &models.CreateTransactionInput{
+				Date:            date,
+				AssetType:       models.AssetTypeUSStock,
+				Symbol:          symbol,
+				Name:            description,
+				TransactionType: transactionType,
+				Quantity:        quantity,
+				Price:           price,
+				Amount:          math.Round(quantity*price*100) / 100,
+				Fee:             &fee,
+				Currency:        models.CurrencyUSD,
+				Note:            &note,
+			}
+			if regulatoryFee := math.Abs(numbers["fee"]); regulatoryFee > 0 {
+				tx.Tax = &regulatoryFee
+			}
+			preview.Entries = append(preview.Entries, &models.BrokerStatementEntry{Row: row, Transaction: tx})
+
+		case symbol != "" && numbers["amount"] < 0 && (strings.Contains(upperDescription, "TAX") || strings.Contains(upperDescription, "WITHHOLDING")):
+			key := statementDividendKey(symbol, date)
+			withholding[key] += -numbers["amount"]
+			withholdingRows[key] = row
+
+		case symbol != "" && (strings.Contains(action, "DIVIDEND") || strings.Contains(upperDescription, "DIV")):
+			amount := numbers["amount"]
+			if amount <= 0 {
+				preview.Warnings = append(preview.Warnings, statementRowError(row, "amount", fmt.Sprintf("略過股息沖銷 %s %.2f，請手動調整", symbol, amount)))
+				continue
+			}
+
+			shares := math.Abs(numbers["quantity"])
+			if match := firstradeSharesPattern.FindStringSubmatch(description); match != nil && shares == 0 {
+				shares, _ = parseStatementNumber(match[1])
+			}
+
+			tx := &models.CreateTransactionInput{
+				Date:            date,
+				AssetType:       models.AssetTypeUSStock,
+				Symbol:          symbol,
+				TransactionType: models.TransactionTypeDividend,
+				Amount:          amount,
+				Currency:        models.CurrencyUSD,
+				Note:            &note,
+			}
+			tx.Quantity, tx.Price = dividendQuantityPrice(amount, shares, 0)
+			preview.Entries = append(preview.Entries, &models.BrokerStatementEntry{Row: row, Transaction: tx})
+		}
+	}
+
+	attachWithholdingTax(preview, withholding, withholdingRows)
+
+	return preview, nil
+}
+
+// firstradeTransactionType 依 Action 判斷交易類型（SELL SHORT 為放空、BUY TO COVER 為回補）
+func firstradeTransactionType(action string) (models.TransactionType, bool) {
+	switch {
+	case strings.Contains(action, "SHORT"):
+		return models.TransactionTypeShort, true
+	case strings.Contains(action, "COVER"):
+		return models.TransactionTypeCover, true
+	case strings.HasPrefix(action, "SELL"):
+		return models.TransactionTypeSell, true
+	case strings.HasPrefix(action, "BUY"):
+		return models.TransactionTypeBuy, true
+	}
+	return "", false
+}
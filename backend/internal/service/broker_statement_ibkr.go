@@ -0,0 +1,382 @@
+package service
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/chienchuanw/asset-manager/internal/models"
+)
+
+// ibkrImporter Interactive Brokers Flex Query 匯入器
+// 支援 XML 與 CSV 輸出，讀取 Trades（成交）與 Cash Transactions（股息、代扣稅款）區段；僅匯入美元計價的股票
+type ibkrImporter struct{}
+
+// ibkrRecordKind Flex Query 記錄類型
+type ibkrRecordKind int
+
+const (
+	ibkrRecordTrade ibkrRecordKind = iota
+	ibkrRecordCash
+)
+
+// ibkrRecord Flex Query 的單筆記錄（欄位名稱已正規化為小寫並移除空白與斜線）
+type ibkrRecord struct {
+	row    int
+	kind   ibkrRecordKind
+	fields map[string]string
+}
+
+// get 依候選欄位名稱取得值
+func (r ibkrRecord) get(keys ...string) string {
+	for _, key := range keys {
+		if value, exists := r.fields[key]; exists && strings.TrimSpace(value) != "" {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+// ibkrPerSharePattern 股息說明中的每股配息（例如 "CASH DIVIDEND USD 0.24 PER SHARE"）
+var ibkrPerSharePattern = regexp.MustCompile(`(?i)([0-9]*\.?[0-9]+)\s+PER\s+SHARE`)
+
+// Info 對帳單格式說明
+func (i *ibkrImporter) Info() models.BrokerStatementFormatInfo {
+	return models.BrokerStatementFormatInfo{
+		Format:      models.BrokerStatementFormatIBKR,
+		Name:        "Interactive Brokers",
+		AssetType:   models.AssetTypeUSStock,
+		Description: "Flex Query（XML 或 CSV），需包含 Trades 與 Cash Transactions 區段；股息與同日代扣稅款合併為一筆",
+	}
+}
+
+// Parse 解析 Flex Query
+func (i *ibkrImporter) Parse(data []byte) (*models.BrokerStatementPreview, error) {
+	content, err := decodeStatement(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []ibkrRecord
+	if bytes.HasPrefix(bytes.TrimSpace(content), []byte("<")) {
+		records, err = readIBKRFlexXML(content)
+	} else {
+		records, err = readIBKRFlexCSV(content)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &models.BrokerStatementPreview{}
+	withholding := make(map[string]float64) // 標的與日期 → 代扣稅款
+	withholdingRows := make(map[string]int)
+
+	for _, record := range records {
+		switch record.kind {
+		case ibkrRecordTrade:
+			if entry := i.parseTrade(record, preview); entry != nil {
+				preview.Entries = append(preview.Entries, entry)
+			}
+		case ibkrRecordCash:
+			cashType := strings.ToLower(record.get("type"))
+			switch {
+			case strings.Contains(cashType, "withholding"):
+				date, err := parseStatementDate(record.get("datetime", "settledate", "reportdate", "date"))
+				if err != nil || record.get("symbol") == "" {
+					continue
+				}
+				amount, _ := parseStatementNumber(record.get("amount"))
+				key := statementDividendKey(ibkrSymbol(record.get("symbol")), date)
+				withholding[key] += -amount
+				withholdingRows[key] = record.row
+			case strings.Contains(cashType, "dividend"):
+				if entry := i.parseDividend(record, preview); entry != nil {
+					preview.Entries = append(preview.Entries, entry)
+				}
+			}
+		}
+	}
+
+	attachWithholdingTax(preview, withholding, withholdingRows)
+
+	return preview, nil
+}
+
+// parseTrade 解析成交記錄；非逐筆成交明細（ORDER、SUMMARY）直接略過，不支援的商品記為提醒
+func (i *ibkrImporter) parseTrade(record ibkrRecord, preview *models.BrokerStatementPreview) *models.BrokerStatementEntry {
+	if level := record.get("levelofdetail"); level != "" && !strings.EqualFold(level, "EXECUTION") {
+		return nil
+	}
+
+	symbol := ibkrSymbol(record.get("symbol"))
+	assetCategory := strings.ToUpper(record.get("assetcategory", "assetclass"))
+	if assetCategory != "STK" {
+		preview.Warnings = append(preview.Warnings, statementRowError(record.row, "asset_type",
+			fmt.Sprintf("略過不支援的商品類別 %s（%s）", assetCategory, symbol)))
+		return nil
+	}
+
+	currency := strings.ToUpper(record.get("currency", "currencyprimary"))
+	if currency != string(models.CurrencyUSD) {
+		preview.Warnings = append(preview.Warnings, statementRowError(record.row, "currency",
+			fmt.Sprintf("略過非美元計價的交易 %s（%s）", symbol, currency)))
+		return nil
+	}
+
+	date, err := parseStatementDate(record.get("tradedate", "datetime", "date"))
+	if err != nil {
+		preview.Errors = append(preview.Errors, statementRowError(record.row, "date", err.Error()))
+		return nil
+	}
+
+	quantity, errQuantity := parseStatementNumber(record.get("quantity"))
+	price, errPrice := parseStatementNumber(record.get("tradeprice"))
+	proceeds, errProceeds := parseStatementNumber(record.get("proceeds"))
+	commission, errCommission := parseStatementNumber(record.get("ibcommission", "commission"))
+	taxes, errTaxes := parseStatementNumber(record.get("taxes"))
+	for _, err := range []error{errQuantity, errPrice, errProceeds, errCommission, errTaxes} {
+		if err != nil {
+			preview.Errors = append(preview.Errors, statementRowError(record.row, "row", err.Error()))
+			return nil
+		}
+	}
+	if quantity == 0 {
+		preview.Errors = append(preview.Errors, statementRowError(record.row, "quantity", "成交數量不可為 0"))
+		return nil
+	}
+
+	side := strings.ToUpper(record.get("buysell"))
+	if strings.Contains(side, "CA.") {
+		// 已取消的成交
+		return nil
+	}
+	if side == "" {
+		side = "BUY"
+		if quantity < 0 {
+			side = "SELL"
+		}
+	}
+
+	// 賣出開倉為放空、買進平倉為回補（未提供開平倉標記時視為一般買賣）
+	transactionType := models.TransactionTypeBuy
+	openClose := strings.ToUpper(record.get("opencloseindicator"))
+	switch {
+	case strings.HasPrefix(side, "SELL") && openClose == "O":
+		transactionType = models.TransactionTypeShort
+	case strings.HasPrefix(side, "SELL"):
+		transactionType = models.TransactionTypeSell
+	case openClose == "C":
+		// 買進平倉為回補空頭部位
+		transactionType = models.TransactionTypeCover
+	}
+
+	quantity = math.Abs(quantity)
+	amount := math.Abs(proceeds)
+	if amount == 0 {
+		amount = quantity * price
+	}
+
+	fee := math.Abs(commission)
+	note := "匯入自 IBKR 對帳單"
+	if tradeID := record.get("tradeid", "transactionid"); tradeID != "" {
+		note = fmt.Sprintf("匯入自 IBKR 對帳單（Trade ID %s）", tradeID)
+	}
+	tx := &models.CreateTransactionInput{
+		Date:            date,
+		AssetType:       models.AssetTypeUSStock,
+		Symbol:          symbol,
+		Name:            record.get("description"),
+		TransactionType: transactionType,
+		Quantity:        quantity,
+		Price:           price,
+		Amount:          amount,
+		Fee:             &fee,
+		Currency:        models.CurrencyUSD,
+		Note:            &note,
+	}
+	if taxes != 0 {
+		tax := math.Abs(taxes)
+		tx.Tax = &tax
+	}
+
+	return &models.BrokerStatementEntry{Row: record.row, Transaction: tx}
+}
+
+// parseDividend 解析股息（含 Payment In Lieu Of Dividends）；負數金額為沖銷，記為提醒
+func (i *ibkrImporter) parseDividend(record ibkrRecord, preview *models.BrokerStatementPreview) *models.BrokerStatementEntry {
+	symbol := ibkrSymbol(record.get("symbol"))
+	if symbol == "" {
+		return nil
+	}
+
+	currency := strings.ToUpper(record.get("currency", "currencyprimary"))
+	if currency != string(models.CurrencyUSD) {
+		preview.Warnings = append(preview.Warnings, statementRowError(record.row, "currency",
+			fmt.Sprintf("略過非美元計價的股息 %s（%s）", symbol, currency)))
+		return nil
+	}
+
+	date, err := parseStatementDate(record.get("datetime", "settledate", "reportdate", "date"))
+	if err != nil {
+		preview.Errors = append(preview.Errors, statementRowError(record.row, "date", err.Error()))
+		return nil
+	}
+
+	amount, err := parseStatementNumber(record.get("amount"))
+	if err != nil {
+		preview.Errors = append(preview.Errors, statementRowError(record.row, "amount", err.Error()))
+		return nil
+	}
+	if amount <= 0 {
+		preview.Warnings = append(preview.Warnings, statementRowError(record.row, "amount",
+			fmt.Sprintf("略過股息沖銷 %s %.2f，請手動調整", symbol, amount)))
+		return nil
+	}
+
+	perShare := 0.0
+	description := record.get("description")
+	if match := ibkrPerSharePattern.FindStringSubmatch(description); match != nil {
+		perShare, _ = strconv.ParseFloat(match[1], 64)
+	}
+
+	note := "匯入自 IBKR 對帳單"
+	tx := &models.CreateTransactionInput{
+		Date:            date,
+		AssetType:       models.AssetTypeUSStock,
+		Symbol:          symbol,
+		TransactionType: models.TransactionTypeDividend,
+		Amount:          amount,
+		Currency:        models.CurrencyUSD,
+		Note:            &note,
+	}
+	tx.Quantity, tx.Price = dividendQuantityPrice(amount, 0, perShare)
+
+	return &models.BrokerStatementEntry{Row: record.row, Transaction: tx}
+}
+
+// ibkrSymbol 轉換 IBKR 代碼（以空白分隔股票類別，例如 "BRK B" → "BRK.B"）
+func ibkrSymbol(symbol string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(symbol), "."))
+}
+
+// normalizeIBKRField 正規化欄位名稱（小寫並移除空白與斜線，例如 "Buy/Sell" → "buysell"）
+func normalizeIBKRField(name string) string {
+	return strings.ToLower(strings.NewReplacer(" ", "", "/", "", "_", "").Replace(strings.TrimSpace(name)))
+}
+
+// readIBKRFlexXML 讀取 XML 格式的 Flex Query（Trade 與 CashTransaction 元素）
+func readIBKRFlexXML(content []byte) ([]ibkrRecord, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(content))
+	records := []ibkrRecord{}
+	row := 0
+	foundRoot := false
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse xml: %w", err)
+		}
+
+		element, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		var kind ibkrRecordKind
+		switch element.Name.Local {
+		case "FlexQueryResponse", "FlexStatements":
+			foundRoot = true
+			continue
+		case "Trade":
+			kind = ibkrRecordTrade
+		case "CashTransaction":
+			kind = ibkrRecordCash
+		default:
+			continue
+		}
+
+		row++
+		fields := make(map[string]string, len(element.Attr))
+		for _, attr := range element.Attr {
+			fields[normalizeIBKRField(attr.Name.Local)] = attr.Value
+		}
+		records = append(records, ibkrRecord{row: row, kind: kind, fields: fields})
+	}
+
+	if !foundRoot {
+		return nil, fmt.Errorf("not a flex query response")
+	}
+	return records, nil
+}
+
+// readIBKRFlexCSV 讀取 CSV 格式的 Flex Query
+// 每個區段各有 header（含 Symbol 與 TradePrice 為成交、含 Symbol 與 Amount 為現金交易）；
+// 若匯出時勾選 header/trailer，資料列以 HEADER/DATA 開頭並忽略 BOF/EOF/BOS/EOS 列
+func readIBKRFlexCSV(content []byte) ([]ibkrRecord, error) {
+	csvReader := csv.NewReader(bytes.NewReader(content))
+	csvReader.FieldsPerRecord = -1
+	csvReader.LazyQuotes = true
+
+	rows, err := csvReader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse file: %w", err)
+	}
+
+	records := []ibkrRecord{}
+	var header []string
+	var kind ibkrRecordKind
+	dataRow := 0
+
+	for _, row := range rows {
+		if isBlankRecord(row) {
+			continue
+		}
+
+		switch strings.ToUpper(strings.TrimSpace(row[0])) {
+		case "BOF", "EOF", "BOS", "EOS":
+			continue
+		case "HEADER", "DATA":
+			if len(row) < 2 {
+				continue
+			}
+			row = row[2:]
+		}
+
+		normalized := make([]string, len(row))
+		for i, name := range row {
+			normalized[i] = normalizeIBKRField(name)
+		}
+		if findColumn(normalized, "symbol") >= 0 && findColumn(normalized, "tradeprice", "amount") >= 0 {
+			header = normalized
+			kind = ibkrRecordCash
+			if findColumn(normalized, "tradeprice") >= 0 {
+				kind = ibkrRecordTrade
+			}
+			continue
+		}
+		if header == nil {
+			continue
+		}
+
+		dataRow++
+		fields := make(map[string]string, len(header))
+		for i, name := range header {
+			fields[name] = csvField(row, i)
+		}
+		records = append(records, ibkrRecord{row: dataRow, kind: kind, fields: fields})
+	}
+
+	if header == nil {
+		return nil, fmt.Errorf("header row not found")
+	}
+	return records, nil
+}
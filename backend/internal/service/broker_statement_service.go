@@ -0,0 +1,377 @@
+package service
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/chienchuanw/asset-manager/internal/models"
+	"github.com/chienchuanw/asset-manager/internal/repository"
+	"github.com/google/uuid"
+	"golang.org/x/text/encoding/traditionalchinese"
+)
+
+// BrokerStatementImporter 券商對帳單匯入器（每種對帳單格式一個實作）
+type BrokerStatementImporter interface {
+	// Info 對帳單格式說明
+	Info() models.BrokerStatementFormatInfo
+
+	// Parse 解析對帳單內容，回傳可匯入的交易、無法解析的行與略過記錄的提醒
+	// 檔案本身無法讀取或不是此格式時回傳 error
+	Parse(data []byte) (*models.BrokerStatementPreview, error)
+}
+
+// BrokerStatementService 券商對帳單匯入服務介面
+type BrokerStatementService interface {
+	// Register 註冊對帳單匯入器（同格式的匯入器會被取代）
+	Register(importer BrokerStatementImporter)
+
+	// Formats 列出支援的對帳單格式
+	Formats() []models.BrokerStatementFormatInfo
+
+	// Preview 解析對帳單並標示與既有交易重複的記錄，不寫入資料庫
+	// brokerageAccountID 可為 nil，有值時套用至所有解析出的交易
+	Preview(format models.BrokerStatementFormat, reader io.Reader, brokerageAccountID *uuid.UUID) (*models.BrokerStatementPreview, error)
+}
+
+type brokerStatementService struct {
+	transactionRepo   repository.TransactionRepository // 可為 nil（不檢查重複）
+	feeProfileService FeeProfileService                // 可為 nil（不補上手續費與交易稅）
+	importers         map[models.BrokerStatementFormat]BrokerStatementImporter
+}
+
+// NewBrokerStatementService 建立新的券商對帳單匯入服務，並註冊內建的匯入器
+func NewBrokerStatementService(transactionRepo repository.TransactionRepository, feeProfileService FeeProfileService) BrokerStatementService {
+	s := &brokerStatementService{
+		transactionRepo:   transactionRepo,
+		feeProfileService: feeProfileService,
+		importers:         make(map[models.BrokerStatementFormat]BrokerStatementImporter),
+	}
+
+	s.Register(newTWBrokerImporter(models.BrokerStatementFormatYuanta, "元大證券"))
+	s.Register(newTWBrokerImporter(models.BrokerStatementFormatFubon, "富邦證券"))
+	s.Register(newTWBrokerImporter(models.BrokerStatementFormatSinoPac, "永豐金證券"))
+	s.Register(&ibkrImporter{})
+	s.Register(&firstradeImporter{})
+	s.Register(&binanceImporter{})
+
+	return s
+}
+
+// Register 註冊對帳單匯入器
+func (s *brokerStatementService) Register(importer BrokerStatementImporter) {
+	s.importers[importer.Info().Format] = importer
+}
+
+// Formats 列出支援的對帳單格式（依格式代碼排序）
+func (s *brokerStatementService) Formats() []models.BrokerStatementFormatInfo {
+	formats := make([]models.BrokerStatementFormatInfo, 0, len(s.importers))
+	for _, importer := range s.importers {
+		formats = append(formats, importer.Info())
+	}
+	sort.Slice(formats, func(i, j int) bool {
+		return formats[i].Format < formats[j].Format
+	})
+	return formats
+}
+
+// Preview 解析對帳單並標示與既有交易重複的記錄
+func (s *brokerStatementService) Preview(format models.BrokerStatementFormat, reader io.Reader, brokerageAccountID *uuid.UUID) (*models.BrokerStatementPreview, error) {
+	importer, exists := s.importers[format]
+	if !exists {
+		return nil, fmt.Errorf("unsupported statement format: %s", format)
+	}
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read statement: %w", err)
+	}
+
+	preview, err := importer.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s statement: %w", format, err)
+	}
+	preview.Format = format
+	if preview.Entries == nil {
+		preview.Entries = []*models.BrokerStatementEntry{}
+	}
+
+	for _, entry := range preview.Entries {
+		entry.Transaction.BrokerageAccountID = brokerageAccountID
+		preview.Warnings = append(preview.Warnings, applyImportTradingCosts(s.feeProfileService, entry.Transaction, entry.Row)...)
+	}
+
+	if err := s.markDuplicates(preview); err != nil {
+		return nil, err
+	}
+
+	return preview, nil
+}
+
+// markDuplicates 比對同資產類型、期間內的既有交易，日期、標的、交易類型、數量與金額皆相同者視為重複
+// 每筆既有交易只會對應一筆對帳單記錄（同日多筆相同成交時，僅超出既有筆數的部分視為新交易）
+func (s *brokerStatementService) markDuplicates(preview *models.BrokerStatementPreview) error {
+	if s.transactionRepo == nil || len(preview.Entries) == 0 {
+		return nil
+	}
+
+	byAssetType := make(map[models.AssetType][]*models.BrokerStatementEntry)
+	for _, entry := range preview.Entries {
+		assetType := entry.Transaction.AssetType
+		byAssetType[assetType] = append(byAssetType[assetType], entry)
+	}
+
+	for assetType, entries := range byAssetType {
+		startDate, endDate := entries[0].Transaction.Date, entries[0].Transaction.Date
+		for _, entry := range entries[1:] {
+			if entry.Transaction.Date.Before(startDate) {
+				startDate = entry.Transaction.Date
+			}
+			if entry.Transaction.Date.After(endDate) {
+				endDate = entry.Transaction.Date
+			}
+		}
+
+		assetType := assetType
+		existing, err := s.transactionRepo.GetAll(repository.TransactionFilters{
+			AssetType: &assetType,
+			StartDate: &startDate,
+			EndDate:   &endDate,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get existing transactions: %w", err)
+		}
+
+		matched := make(map[uuid.UUID]bool)
+		for _, entry := range entries {
+			for _, transaction := range existing {
+				if matched[transaction.ID] || !isDuplicateTransaction(entry.Transaction, transaction) {
+					continue
+				}
+				matched[transaction.ID] = true
+				transactionID := transaction.ID
+				entry.Duplicate = true
+				entry.DuplicateTransactionID = &transactionID
+				preview.DuplicateCount++
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// isDuplicateTransaction 判斷對帳單記錄是否與既有交易相同（金額允許 0.01 或萬分之一的誤差）
+func isDuplicateTransaction(input *models.CreateTransactionInput, transaction *models.Transaction) bool {
+	if input.Date.Format("2006-01-02") != transaction.Date.Format("2006-01-02") {
+		return false
+	}
+	if !strings.EqualFold(input.Symbol, transaction.Symbol) || input.TransactionType != transaction.TransactionType {
+		return false
+	}
+	if math.Abs(input.Quantity-transaction.Quantity) > 1e-8 {
+		return false
+	}
+	tolerance := math.Max(0.01, math.Abs(input.Amount)*1e-4)
+	return math.Abs(input.Amount-transaction.Amount) <= tolerance
+}
+
+// applyImportTradingCosts 依手續費設定補上匯入交易未填寫的手續費與交易稅，回傳與設定不符的提醒
+func applyImportTradingCosts(feeProfileService FeeProfileService, tx *models.CreateTransactionInput, rowNum int) []models.CSVValidationError {
+	if feeProfileService == nil {
+		return nil
+	}
+
+	warnings, err := feeProfileService.ApplyTradingCosts(tx, nil)
+	if err != nil {
+		return []models.CSVValidationError{{
+			Row:     rowNum,
+			Field:   "fee",
+			Message: fmt.Sprintf("無法計算手續費與交易稅: %v", err),
+		}}
+	}
+
+	rowWarnings := make([]models.CSVValidationError, 0, len(warnings))
+	for _, warning := range warnings {
+		field, _ := warning.Details["field"].(string)
+		rowWarnings = append(rowWarnings, models.CSVValidationError{
+			Row:     rowNum,
+			Field:   field,
+			Message: warning.Message,
+		})
+	}
+	return rowWarnings
+}
+
+// ==================== 對帳單共用解析工具 ====================
+
+// decodeStatement 移除 BOM，非 UTF-8 的內容視為 Big5（台灣券商匯出檔常見編碼）轉為 UTF-8
+func decodeStatement(data []byte) ([]byte, error) {
+	data = bytes.TrimPrefix(data, []byte("\ufeff"))
+	if utf8.Valid(data) {
+		return data, nil
+	}
+
+	decoded, err := traditionalchinese.Big5.NewDecoder().Bytes(data)
+	if err != nil {
+		return nil, fmt.Errorf("file must be UTF-8 or Big5 encoded: %w", err)
+	}
+	return decoded, nil
+}
+
+// readStatementCSV 讀取對帳單 CSV，略過 header 之前的標題列（例如帳號、期間）
+// header 為第一個包含所有 required 欄位（任一候選名稱）的列；回傳 header 與其後的資料列
+func readStatementCSV(data []byte, required ...[]string) ([]string, [][]string, error) {
+	content, err := decodeStatement(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	csvReader := csv.NewReader(bytes.NewReader(content))
+	csvReader.FieldsPerRecord = -1
+	csvReader.LazyQuotes = true
+	csvReader.TrimLeadingSpace = true
+
+	records, err := csvReader.ReadAll()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse file: %w", err)
+	}
+
+	for i, record := range records {
+		header := make([]string, len(record))
+		for j, name := range record {
+			header[j] = strings.TrimSpace(name)
+		}
+
+		found := true
+		for _, candidates := range required {
+			if findColumn(header, candidates...) < 0 {
+				found = false
+				break
+			}
+		}
+		if found {
+			return header, records[i+1:], nil
+		}
+	}
+
+	return nil, nil, fmt.Errorf("header row not found")
+}
+
+// isBlankRecord 是否為空白列（所有欄位皆為空白）
+func isBlankRecord(record []string) bool {
+	for _, field := range record {
+		if strings.TrimSpace(field) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// parseStatementNumber 解析對帳單數字（移除千分位、貨幣符號，括號表示負數）
+func parseStatementNumber(value string) (float64, error) {
+	value = strings.TrimSpace(value)
+	negative := strings.HasPrefix(value, "(") && strings.HasSuffix(value, ")")
+	value = strings.Trim(value, "()")
+	value = strings.NewReplacer(",", "", "$", "", "NT", "", " ", "").Replace(value)
+	if value == "" || value == "-" || value == "--" {
+		return 0, nil
+	}
+
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number: %s", value)
+	}
+	if negative {
+		number = -number
+	}
+	return number, nil
+}
+
+// parseStatementDate 解析對帳單日期（支援 YYYY-MM-DD、YYYY/MM/DD、YYYYMMDD、MM/DD/YYYY 與民國年 YYY/MM/DD）
+// 日期後方的時間（空白、分號或逗號分隔）會被忽略
+func parseStatementDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if index := strings.IndexAny(value, " ;,T"); index > 0 {
+		value = value[:index]
+	}
+
+	for _, layout := range []string{"2006-01-02", "2006/01/02", "20060102", "01/02/2006", "1/2/2006", "2006/1/2"} {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date, nil
+		}
+	}
+
+	// 民國年（例如 114/10/18 或 114-10-18）
+	parts := strings.FieldsFunc(value, func(r rune) bool { return r == '/' || r == '-' || r == '.' })
+	if len(parts) == 3 && len(parts[0]) <= 3 {
+		year, errYear := strconv.Atoi(parts[0])
+		month, errMonth := strconv.Atoi(parts[1])
+		day, errDay := strconv.Atoi(parts[2])
+		if errYear == nil && errMonth == nil && errDay == nil && month >= 1 && month <= 12 && day >= 1 && day <= 31 {
+			return time.Date(year+1911, time.Month(month), day, 0, 0, 0, 0, time.UTC), nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid date: %s", value)
+}
+
+// dividendQuantityPrice 依配息總額推算股息交易的數量與單價
+// 已知股數或每股配息時以其推算，皆未知時記為 1 單位、單價為配息總額（金額 = 數量 × 單價）
+func dividendQuantityPrice(amount, shares, perShare float64) (float64, float64) {
+	switch {
+	case shares > 0:
+		return shares, amount / shares
+	case perShare > 0:
+		return amount / perShare, perShare
+	}
+	return 1, amount
+}
+
+// statementDividendKey 股息與代扣稅款的對應鍵（標的與日期）
+func statementDividendKey(symbol string, date time.Time) string {
+	return strings.ToUpper(symbol) + " " + date.Format("2006-01-02")
+}
+
+// attachWithholdingTax 將代扣稅款（以 statementDividendKey 彙總的正數金額）併入同標的、同日期股息的稅額
+// 找不到對應股息的代扣稅款記為提醒
+func attachWithholdingTax(preview *models.BrokerStatementPreview, withholding map[string]float64, rows map[string]int) {
+	for _, entry := range preview.Entries {
+		if entry.Transaction.TransactionType != models.TransactionTypeDividend {
+			continue
+		}
+		key := statementDividendKey(entry.Transaction.Symbol, entry.Transaction.Date)
+		if tax, exists := withholding[key]; exists {
+			if tax > 0 {
+				tax = math.Round(tax*100) / 100
+				entry.Transaction.Tax = &tax
+			}
+			delete(withholding, key)
+		}
+	}
+
+	keys := make([]string, 0, len(withholding))
+	for key := range withholding {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if math.Abs(withholding[key]) < 0.005 {
+			continue
+		}
+		preview.Warnings = append(preview.Warnings, statementRowError(rows[key], "tax",
+			fmt.Sprintf("找不到代扣稅款 %.2f 對應的股息（%s），請手動調整", withholding[key], key)))
+	}
+}
+
+// statementRowError 建立對帳單解析錯誤
+func statementRowError(row int, field, message string) models.CSVValidationError {
+	return models.CSVValidationError{Row: row, Field: field, Message: message}
+}
@@ -0,0 +1,277 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chienchuanw/asset-manager/internal/models"
+	"github.com/chienchuanw/asset-manager/internal/repository"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/encoding/traditionalchinese"
+)
+
+// TestTWBrokerImporter_Parse 測試台灣券商成交明細（Big5、民國年、融券與現金股利）
+func TestTWBrokerImporter_Parse(t *testing.T) {
+	content := "元大證券 成交明細\n" +
+		"帳號,9876543\n" +
+		"成交日期,股票代號,股票名稱,買賣別,成交股數,成交單價,成交價金,手續費,交易稅,二代健保補充保費,應收付金額\n" +
+		"114/10/15,2330,台積電,現股買進,\"1,000\",620,\"620,000\",883,0,0,\"-620,883\"\n" +
+		"114/10/16,0050,元大台灣50,現股賣出,500,150,\"75,000\",106,75,0,\"74,819\"\n" +
+		"114/10/16,2603,長榮,融券賣出,2000,200,\"400,000\",570,1200,0,\"398,230\"\n" +
+		"114/10/17,2884,玉山金,現金股利,5000,,\"5,000\",0,0,105,\"4,895\"\n" +
+		"114/10/17,2884,玉山金,股票股利,100,,,,,,\n" +
+		",,,合計,,,,,,,\n"
+	big5, err := traditionalchinese.Big5.NewEncoder().String(content)
+	require.NoError(t, err)
+
+	preview, err := newTWBrokerImporter(models.BrokerStatementFormatYuanta, "元大證券").Parse([]byte(big5))
+
+	require.NoError(t, err)
+	require.Len(t, preview.Entries, 4)
+	require.Len(t, preview.Errors, 1)
+	assert.Equal(t, "transaction_type", preview.Errors[0].Field)
+	assert.Equal(t, 5, preview.Errors[0].Row)
+
+	buy := preview.Entries[0].Transaction
+	assert.Equal(t, time.Date(2025, 10, 15, 0, 0, 0, 0, time.UTC), buy.Date)
+	assert.Equal(t, "2330", buy.Symbol)
+	assert.Equal(t, "台積電", buy.Name)
+	assert.Equal(t, models.TransactionTypeBuy, buy.TransactionType)
+	assert.Equal(t, 1000.0, buy.Quantity)
+	assert.Equal(t, 620000.0, buy.Amount)
+	assert.Equal(t, 883.0, *buy.Fee)
+	assert.Equal(t, models.CurrencyTWD, buy.Currency)
+
+	sell := preview.Entries[1].Transaction
+	assert.Equal(t, models.TransactionTypeSell, sell.TransactionType)
+	assert.Equal(t, 75.0, *sell.Tax)
+
+	assert.Equal(t, models.TransactionTypeShort, preview.Entries[2].Transaction.TransactionType)
+
+	dividend := preview.Entries[3].Transaction
+	assert.Equal(t, models.TransactionTypeDividend, dividend.TransactionType)
+	assert.Equal(t, 5000.0, dividend.Amount)
+	assert.Equal(t, 5000.0, dividend.Quantity)
+	assert.Equal(t, 1.0, dividend.Price)
+	assert.Equal(t, 105.0, *dividend.Tax)
+}
+
+// TestIBKRImporter_ParseXML 測試 IBKR Flex Query XML（成交、放空、股息與代扣稅款）
+func TestIBKRImporter_ParseXML(t *testing.T) {
+	content := `<?xml version="1.0" encoding="UTF-8"?>
+<FlexQueryResponse queryName="trades" type="AF">
+<FlexStatements count="1">
+<FlexStatement accountId="U1234567">
+<Trades>
+<Trade assetCategory="STK" symbol="AAPL" description="APPLE INC" currency="USD" tradeDate="20251015" quantity="10" tradePrice="230.5" proceeds="-2305" ibCommission="-1" taxes="0" buySell="BUY" openCloseIndicator="O" tradeID="111" levelOfDetail="EXECUTION" />
+<Trade assetCategory="STK" symbol="BRK B" description="BERKSHIRE HATHAWAY INC-CL B" currency="USD" tradeDate="20251016" quantity="-5" tradePrice="480" proceeds="2400" ibCommission="-1.02" taxes="0" buySell="SELL" openCloseIndicator="C" tradeID="112" levelOfDetail="EXECUTION" />
+<Trade assetCategory="STK" symbol="TSLA" description="TESLA INC" currency="USD" tradeDate="20251016" quantity="-3" tradePrice="250" proceeds="750" ibCommission="-1" buySell="SELL" openCloseIndicator="O" tradeID="113" levelOfDetail="EXECUTION" />
+<Trade assetCategory="OPT" symbol="AAPL  251121C00250000" currency="USD" tradeDate="20251016" quantity="1" tradePrice="2.5" proceeds="-250" ibCommission="-0.7" buySell="BUY" tradeID="114" levelOfDetail="EXECUTION" />
+<Trade assetCategory="STK" symbol="AAPL" currency="USD" tradeDate="20251015" quantity="10" tradePrice="230.5" buySell="BUY" levelOfDetail="ORDER" />
+</Trades>
+<CashTransactions>
+<CashTransaction type="Dividends" symbol="AAPL" currency="USD" dateTime="20251110;202000" amount="2.6" description="AAPL(US0378331005) CASH DIVIDEND USD 0.26 PER SHARE (Ordinary Dividend)" />
+<CashTransaction type="Withholding Tax" symbol="AAPL" currency="USD" dateTime="20251110;202000" amount="-0.78" description="AAPL(US0378331005) CASH DIVIDEND USD 0.26 PER SHARE - US TAX" />
+<CashTransaction type="Deposits/Withdrawals" currency="USD" dateTime="20251001" amount="10000" description="CASH RECEIPTS" />
+</CashTransactions>
+</FlexStatement>
+</FlexStatements>
+</FlexQueryResponse>`
+
+	preview, err := (&ibkrImporter{}).Parse([]byte(content))
+
+	require.NoError(t, err)
+	require.Len(t, preview.Entries, 4)
+	assert.Empty(t, preview.Errors)
+	require.Len(t, preview.Warnings, 1)
+	assert.Equal(t, "asset_type", preview.Warnings[0].Field)
+
+	buy := preview.Entries[0].Transaction
+	assert.Equal(t, "AAPL", buy.Symbol)
+	assert.Equal(t, models.AssetTypeUSStock, buy.AssetType)
+	assert.Equal(t, models.TransactionTypeBuy, buy.TransactionType)
+	assert.Equal(t, 2305.0, buy.Amount)
+	assert.Equal(t, 1.0, *buy.Fee)
+	assert.Nil(t, buy.Tax)
+	assert.Contains(t, *buy.Note, "111")
+
+	sell := preview.Entries[1].Transaction
+	assert.Equal(t, "BRK.B", sell.Symbol)
+	assert.Equal(t, models.TransactionTypeSell, sell.TransactionType)
+	assert.Equal(t, 5.0, sell.Quantity)
+
+	assert.Equal(t, models.TransactionTypeShort, preview.Entries[2].Transaction.TransactionType)
+
+	dividend := preview.Entries[3].Transaction
+	assert.Equal(t, models.TransactionTypeDividend, dividend.TransactionType)
+	assert.Equal(t, time.Date(2025, 11, 10, 0, 0, 0, 0, time.UTC), dividend.Date)
+	assert.Equal(t, 2.6, dividend.Amount)
+	assert.InDelta(t, 10.0, dividend.Quantity, 1e-9)
+	assert.Equal(t, 0.26, dividend.Price)
+	require.NotNil(t, dividend.Tax)
+	assert.Equal(t, 0.78, *dividend.Tax)
+}
+
+// TestIBKRImporter_ParseCSV 測試 IBKR Flex Query CSV（含 HEADER/DATA 標記與多個區段）
+func TestIBKRImporter_ParseCSV(t *testing.T) {
+	content := `"BOF","U1234567","trades","1","20251001","20251031"
+"HEADER","TRNT","ClientAccountID","AssetClass","Symbol","CurrencyPrimary","TradeDate","Quantity","TradePrice","Proceeds","IBCommission","Buy/Sell","Open/CloseIndicator"
+"DATA","TRNT","U1234567","STK","MSFT","USD","2025-10-20","4","510","-2040","-1","BUY","C"
+"EOS","TRNT","1"
+"HEADER","CTRN","ClientAccountID","Type","Symbol","CurrencyPrimary","Date/Time","Amount","Description"
+"DATA","CTRN","U1234567","Dividends","MSFT","USD","2025-10-21","3.64","MSFT CASH DIVIDEND USD 0.91 PER SHARE"
+"EOF","U1234567"
+`
+
+	preview, err := (&ibkrImporter{}).Parse([]byte(content))
+
+	require.NoError(t, err)
+	require.Len(t, preview.Entries, 2)
+	assert.Equal(t, models.TransactionTypeCover, preview.Entries[0].Transaction.TransactionType)
+	assert.Equal(t, 4.0, preview.Entries[0].Transaction.Quantity)
+	assert.Equal(t, models.TransactionTypeDividend, preview.Entries[1].Transaction.TransactionType)
+	assert.InDelta(t, 4.0, preview.Entries[1].Transaction.Quantity, 1e-9)
+}
+
+// TestFirstradeImporter_Parse 測試 Firstrade 帳戶歷史（成交、股息與 NRA 代扣稅款）
+func TestFirstradeImporter_Parse(t *testing.T) {
+	content := "Symbol,Quantity,Price,Action,Description,TradeDate,SettledDate,Interest,Amount,Commission,Fee,CUSIP,RecordType\n" +
+		"VOO,2,550.25,BUY,VANGUARD S&P 500 ETF,2025-10-01,2025-10-02,0,-1100.50,0,0,922908363,Trade\n" +
+		"VOO,-1,560,SELL,VANGUARD S&P 500 ETF,2025-10-10,2025-10-14,0,559.97,0,0.03,922908363,Trade\n" +
+		"VOO,0,0,Other,VANGUARD S&P 500 ETF CASH DIV ON 2 SHS REC 09/29/25 PAY 10/01/25,2025-10-01,2025-10-01,0,3.48,0,0,922908363,Financial\n" +
+		"VOO,0,0,Other,VANGUARD S&P 500 ETF NRA TAX ADJ,2025-10-01,2025-10-01,0,-1.04,0,0,922908363,Financial\n" +
+		",0,0,Other,INTEREST ON CREDIT BALANCE,2025-10-31,2025-10-31,0.12,0.12,0,0,,Financial\n"
+
+	preview, err := (&firstradeImporter{}).Parse([]byte(content))
+
+	require.NoError(t, err)
+	assert.Empty(t, preview.Errors)
+	require.Len(t, preview.Entries, 3)
+
+	buy := preview.Entries[0].Transaction
+	assert.Equal(t, models.TransactionTypeBuy, buy.TransactionType)
+	assert.Equal(t, 1100.5, buy.Amount)
+	assert.Equal(t, 0.0, *buy.Fee)
+
+	sell := preview.Entries[1].Transaction
+	assert.Equal(t, models.TransactionTypeSell, sell.TransactionType)
+	assert.Equal(t, 1.0, sell.Quantity)
+	assert.Equal(t, 0.03, *sell.Tax)
+
+	dividend := preview.Entries[2].Transaction
+	assert.Equal(t, models.TransactionTypeDividend, dividend.TransactionType)
+	assert.Equal(t, 3.48, dividend.Amount)
+	assert.Equal(t, 2.0, dividend.Quantity)
+	assert.Equal(t, 1.74, dividend.Price)
+	assert.Equal(t, 1.04, *dividend.Tax)
+}
+
+// TestBinanceImporter_Parse 測試 Binance 交易歷史（新舊格式、手續費換算與不支援的交易對）
+func TestBinanceImporter_Parse(t *testing.T) {
+	t.Run("新版格式", func(t *testing.T) {
+		content := "Date(UTC),Pair,Side,Price,Executed,Amount,Fee\n" +
+			"2025-10-18 09:30:00,BTCUSDT,BUY,100000,0.01BTC,1000USDT,0.00001BTC\n" +
+			"2025-10-18 10:00:00,ETHUSDT,SELL,4000,0.5ETH,2000USDT,2USDT\n" +
+			"2025-10-18 11:00:00,SOLUSDT,BUY,200,1SOL,200USDT,0.0003BNB\n" +
+			"2025-10-18 12:00:00,ETHBTC,BUY,0.04,1ETH,0.04BTC,0.001ETH\n"
+
+		preview, err := (&binanceImporter{}).Parse([]byte(content))
+
+		require.NoError(t, err)
+		require.Len(t, preview.Entries, 3)
+		require.Len(t, preview.Warnings, 2)
+
+		btc := preview.Entries[0].Transaction
+		assert.Equal(t, "BTC", btc.Symbol)
+		assert.Equal(t, models.AssetTypeCrypto, btc.AssetType)
+		assert.Equal(t, 0.01, btc.Quantity)
+		assert.Equal(t, 1000.0, btc.Amount)
+		assert.Equal(t, 1.0, *btc.Fee)
+		assert.Equal(t, models.CurrencyUSD, btc.Currency)
+
+		assert.Equal(t, models.TransactionTypeSell, preview.Entries[1].Transaction.TransactionType)
+		assert.Equal(t, 2.0, *preview.Entries[1].Transaction.Fee)
+
+		assert.Nil(t, preview.Entries[2].Transaction.Fee, "以 BNB 支付的手續費需手動填寫")
+		assert.Equal(t, "fee", preview.Warnings[0].Field)
+		assert.Equal(t, "symbol", preview.Warnings[1].Field)
+	})
+
+	t.Run("舊版格式", func(t *testing.T) {
+		content := "Date(UTC),Market,Type,Price,Amount,Total,Fee,Fee Coin\n" +
+			"2024-03-01 08:00:00,ETHUSDT,BUY,3400,0.2,680,0.00015,ETH\n"
+
+		preview, err := (&binanceImporter{}).Parse([]byte(content))
+
+		require.NoError(t, err)
+		require.Len(t, preview.Entries, 1)
+		eth := preview.Entries[0].Transaction
+		assert.Equal(t, "ETH", eth.Symbol)
+		assert.Equal(t, 0.2, eth.Quantity)
+		assert.Equal(t, 680.0, eth.Amount)
+		assert.Equal(t, 0.51, *eth.Fee)
+	})
+}
+
+// TestBrokerStatementService_Preview 測試預覽時標示重複交易並套用券商帳戶
+func TestBrokerStatementService_Preview(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	svc := NewBrokerStatementService(mockRepo, nil)
+
+	existingID := uuid.New()
+	mockRepo.On("GetAll", mock.MatchedBy(func(filters repository.TransactionFilters) bool {
+		return filters.AssetType != nil && *filters.AssetType == models.AssetTypeCrypto &&
+			filters.StartDate.Equal(time.Date(2025, 10, 18, 0, 0, 0, 0, time.UTC)) &&
+			filters.EndDate.Equal(time.Date(2025, 10, 19, 0, 0, 0, 0, time.UTC))
+	})).Return([]*models.Transaction{
+		{
+			ID:              existingID,
+			Date:            time.Date(2025, 10, 18, 0, 0, 0, 0, time.UTC),
+			AssetType:       models.AssetTypeCrypto,
+			Symbol:          "BTC",
+			TransactionType: models.TransactionTypeBuy,
+			Quantity:        0.01,
+			Amount:          1000,
+		},
+	}, nil)
+
+	// 同日兩筆相同成交，既有交易只對應第一筆
+	content := "Date(UTC),Pair,Side,Price,Executed,Amount,Fee\n" +
+		"2025-10-18 09:30:00,BTCUSDT,BUY,100000,0.01BTC,1000USDT,1USDT\n" +
+		"2025-10-18 09:30:05,BTCUSDT,BUY,100000,0.01BTC,1000USDT,1USDT\n" +
+		"2025-10-19 09:30:00,BTCUSDT,BUY,101000,0.01BTC,1010USDT,1.01USDT\n"
+	accountID := uuid.New()
+
+	preview, err := svc.Preview(models.BrokerStatementFormatBinance, strings.NewReader(content), &accountID)
+
+	require.NoError(t, err)
+	assert.Equal(t, models.BrokerStatementFormatBinance, preview.Format)
+	require.Len(t, preview.Entries, 3)
+	assert.Equal(t, 1, preview.DuplicateCount)
+	assert.True(t, preview.Entries[0].Duplicate)
+	assert.Equal(t, existingID, *preview.Entries[0].DuplicateTransactionID)
+	assert.False(t, preview.Entries[1].Duplicate)
+	assert.False(t, preview.Entries[2].Duplicate)
+	assert.Equal(t, accountID, *preview.Entries[2].Transaction.BrokerageAccountID)
+	mockRepo.AssertExpectations(t)
+
+	_, err = svc.Preview("unknown", strings.NewReader(content), nil)
+	assert.Error(t, err)
+}
+
+// TestParseStatementDate 測試對帳單日期格式
+func TestParseStatementDate(t *testing.T) {
+	expected := time.Date(2025, 10, 18, 0, 0, 0, 0, time.UTC)
+	for _, value := range []string{"2025-10-18", "2025/10/18", "20251018", "10/18/2025", "114/10/18", "20251018;093000", "2025-10-18 09:30:00", "2025-10-18, 09:30:00"} {
+		date, err := parseStatementDate(value)
+		require.NoError(t, err, value)
+		assert.Equal(t, expected, date, value)
+	}
+
+	_, err := parseStatementDate("not a date")
+	assert.Error(t, err)
+}
@@ -0,0 +1,233 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/chienchuanw/asset-manager/internal/models"
+)
+
+// twBrokerImporter 台灣券商成交明細匯入器（元大、富邦、永豐金）
+// 各券商匯出的欄位名稱略有不同，以候選欄位名稱對應；檔案可為 UTF-8 或 Big5，header 前的標題列會被略過
+type twBrokerImporter struct {
+	format models.BrokerStatementFormat
+	broker string
+}
+
+// newTWBrokerImporter 建立台灣券商成交明細匯入器
+func newTWBrokerImporter(format models.BrokerStatementFormat, broker string) *twBrokerImporter {
+	return &twBrokerImporter{format: format, broker: broker}
+}
+
+// 台灣券商成交明細的候選欄位名稱
+var (
+	twStatementDateColumns     = []string{"成交日期", "交易日期", "成交日", "日期"}
+	twStatementCodeColumns     = []string{"股票代號", "商品代號", "證券代號", "代號"}
+	twStatementNameColumns     = []string{"股票名稱", "商品名稱", "證券名稱", "名稱"}
+	twStatementCombinedColumns = []string{"股票", "商品", "證券"} // 代號與名稱在同一欄（例如「2330 台積電」）
+	twStatementSideColumns     = []string{"買賣別", "交易類別", "交易別", "買賣", "類別"}
+	twStatementQuantityColumns = []string{"成交股數", "成交數量", "股數", "數量"}
+	twStatementPriceColumns    = []string{"成交單價", "成交價格", "成交價", "單價", "價格"}
+	twStatementAmountColumns   = []string{"成交價金", "成交金額", "價金", "金額"}
+	twStatementFeeColumns      = []string{"手續費"}
+	twStatementTaxColumns      = []string{"交易稅", "證交稅"}
+	twStatementPremiumColumns  = []string{"二代健保補充保費", "補充保費", "健保補充保費"}
+	twStatementNetColumns      = []string{"應收付金額", "淨收付金額", "淨收付", "客戶收付", "收付金額"}
+)
+
+// Info 對帳單格式說明
+func (i *twBrokerImporter) Info() models.BrokerStatementFormatInfo {
+	return models.BrokerStatementFormatInfo{
+		Format:      i.format,
+		Name:        i.broker,
+		AssetType:   models.AssetTypeTWStock,
+		Description: fmt.Sprintf("%s成交明細或對帳單（CSV，UTF-8 或 Big5），含現股、融資融券與現金股利", i.broker),
+	}
+}
+
+// Parse 解析成交明細
+func (i *twBrokerImporter) Parse(data []byte) (*models.BrokerStatementPreview, error) {
+	header, records, err := readStatementCSV(data, twStatementDateColumns, twStatementSideColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	codeCol := findColumn(header, twStatementCodeColumns...)
+	combinedCol := findColumn(header, twStatementCombinedColumns...)
+	if codeCol < 0 && combinedCol < 0 {
+		return nil, fmt.Errorf("missing stock code column")
+	}
+
+	columns := twStatementColumns{
+		date:     findColumn(header, twStatementDateColumns...),
+		code:     codeCol,
+		name:     findColumn(header, twStatementNameColumns...),
+		combined: combinedCol,
+		side:     findColumn(header, twStatementSideColumns...),
+		quantity: findColumn(header, twStatementQuantityColumns...),
+		price:    findColumn(header, twStatementPriceColumns...),
+		amount:   findColumn(header, twStatementAmountColumns...),
+		fee:      findColumn(header, twStatementFeeColumns...),
+		tax:      findColumn(header, twStatementTaxColumns...),
+		premium:  findColumn(header, twStatementPremiumColumns...),
+		net:      findColumn(header, twStatementNetColumns...),
+	}
+
+	preview := &models.BrokerStatementPreview{}
+	for index, record := range records {
+		row := index + 1
+		if isBlankRecord(record) {
+			continue
+		}
+
+		tx, rowErr := i.parseRecord(record, columns, row)
+		if rowErr != nil {
+			preview.Errors = append(preview.Errors, *rowErr)
+			continue
+		}
+		if tx == nil {
+			continue
+		}
+		preview.Entries = append(preview.Entries, &models.BrokerStatementEntry{Row: row, Transaction: tx})
+	}
+
+	return preview, nil
+}
+
+// twStatementColumns 成交明細各欄位的索引（-1 表示不存在）
+type twStatementColumns struct {
+	date, code, name, combined, side, quantity, price, amount, fee, tax, premium, net int
+}
+
+// parseRecord 解析單筆成交明細；合計、小計等彙總列回傳 (nil, nil) 表示略過
+func (i *twBrokerImporter) parseRecord(record []string, columns twStatementColumns, row int) (*models.CreateTransactionInput, *models.CSVValidationError) {
+	code, name := twStatementSymbol(csvField(record, columns.code), csvField(record, columns.name), csvField(record, columns.combined))
+	if code == "" {
+		// 合計、小計等彙總列沒有股票代號
+		if strings.Contains(strings.Join(record, ""), "計") {
+			return nil, nil
+		}
+		rowErr := statementRowError(row, "symbol", "缺少股票代號")
+		return nil, &rowErr
+	}
+
+	date, err := parseStatementDate(csvField(record, columns.date))
+	if err != nil {
+		rowErr := statementRowError(row, "date", fmt.Sprintf("日期格式錯誤: %s", csvField(record, columns.date)))
+		return nil, &rowErr
+	}
+
+	side := csvField(record, columns.side)
+	transactionType, ok := twStatementTransactionType(side)
+	if !ok {
+		rowErr := statementRowError(row, "transaction_type", fmt.Sprintf("不支援的交易類別: %s", side))
+		return nil, &rowErr
+	}
+
+	numbers := make(map[string]float64)
+	for field, col := range map[string]int{
+		"quantity": columns.quantity, "price": columns.price, "amount": columns.amount,
+		"fee": columns.fee, "tax": columns.tax, "premium": columns.premium, "net": columns.net,
+	} {
+		value, err := parseStatementNumber(csvField(record, col))
+		if err != nil {
+			rowErr := statementRowError(row, field, err.Error())
+			return nil, &rowErr
+		}
+		numbers[field] = value
+	}
+
+	note := fmt.Sprintf("匯入自%s對帳單", i.broker)
+	tx := &models.CreateTransactionInput{
+		Date:            date,
+		AssetType:       models.AssetTypeTWStock,
+		Symbol:          code,
+		Name:            name,
+		TransactionType: transactionType,
+		Currency:        models.CurrencyTWD,
+		Note:            &note,
+	}
+
+	if transactionType == models.TransactionTypeDividend {
+		// 現金股利：金額為配息總額（未列示時以淨收付加回補充保費），補充保費記為稅額
+		amount := numbers["amount"]
+		if amount <= 0 {
+			amount = math.Abs(numbers["net"]) + math.Abs(numbers["premium"])
+		}
+		if amount <= 0 {
+			rowErr := statementRowError(row, "amount", "缺少股利金額")
+			return nil, &rowErr
+		}
+		tx.Amount = amount
+		tx.Quantity, tx.Price = dividendQuantityPrice(amount, math.Abs(numbers["quantity"]), 0)
+		if premium := math.Abs(numbers["premium"]); premium > 0 {
+			tx.Tax = &premium
+		}
+		return tx, nil
+	}
+
+	tx.Quantity = math.Abs(numbers["quantity"])
+	tx.Price = math.Abs(numbers["price"])
+	if tx.Quantity <= 0 {
+		rowErr := statementRowError(row, "quantity", "成交股數必須大於 0")
+		return nil, &rowErr
+	}
+
+	tx.Amount = math.Abs(numbers["amount"])
+	if tx.Amount == 0 {
+		tx.Amount = tx.Quantity * tx.Price
+	}
+	if tx.Price == 0 {
+		tx.Price = tx.Amount / tx.Quantity
+	}
+
+	if columns.fee >= 0 && csvField(record, columns.fee) != "" {
+		fee := math.Abs(numbers["fee"])
+		tx.Fee = &fee
+	}
+	if columns.tax >= 0 && csvField(record, columns.tax) != "" {
+		tax := math.Abs(numbers["tax"])
+		tx.Tax = &tax
+	}
+
+	return tx, nil
+}
+
+// twStatementSymbol 取得股票代號與名稱（支援代號、名稱同欄，移除 .TW / .TWO 後綴）
+func twStatementSymbol(code, name, combined string) (string, string) {
+	if code == "" && combined != "" {
+		parts := strings.Fields(strings.ReplaceAll(combined, "\u3000", " "))
+		if len(parts) > 0 {
+			code = parts[0]
+		}
+		if len(parts) > 1 && name == "" {
+			name = strings.Join(parts[1:], " ")
+		}
+	}
+
+	code = strings.ToUpper(strings.TrimSpace(code))
+	code = strings.TrimSuffix(strings.TrimSuffix(code, ".TWO"), ".TW")
+	// 部分券商以 ="2330" 形式避免試算表去除開頭的 0
+	code = strings.Trim(code, "=\"'")
+	return code, strings.TrimSpace(name)
+}
+
+// twStatementTransactionType 依買賣別判斷交易類型（融券賣出為放空、融券買進為回補；股票股利不支援）
+func twStatementTransactionType(side string) (models.TransactionType, bool) {
+	switch {
+	case strings.Contains(side, "股票股利") || strings.Contains(side, "配股"):
+		return "", false
+	case strings.Contains(side, "股利") || strings.Contains(side, "股息") || strings.Contains(side, "配息"):
+		return models.TransactionTypeDividend, true
+	case strings.Contains(side, "券賣"):
+		return models.TransactionTypeShort, true
+	case strings.Contains(side, "券買"):
+		return models.TransactionTypeCover, true
+	case strings.Contains(side, "賣"):
+		return models.TransactionTypeSell, true
+	case strings.Contains(side, "買"):
+		return models.TransactionTypeBuy, true
+	}
+	return "", false
+}
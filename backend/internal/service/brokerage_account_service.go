@@ -0,0 +1,328 @@
+package service
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/chienchuanw/asset-manager/internal/models"
+	"github.com/chienchuanw/asset-manager/internal/repository"
+	"github.com/google/uuid"
+)
+
+// BrokerageAccountService 券商帳戶業務邏輯介面
+type BrokerageAccountService interface {
+	CreateBrokerageAccount(input *models.CreateBrokerageAccountInput) (*models.BrokerageAccount, error)
+	GetBrokerageAccount(id uuid.UUID) (*models.BrokerageAccount, error)
+	ListBrokerageAccounts() ([]*models.BrokerageAccount, error)
+	UpdateBrokerageAccount(id uuid.UUID, input *models.UpdateBrokerageAccountInput) (*models.BrokerageAccount, error)
+	DeleteBrokerageAccount(id uuid.UUID) error
+	GetMovements(id uuid.UUID) ([]*models.BrokerageCashMovement, error)
+	GetHoldings(id uuid.UUID) (*HoldingServiceResult, error)
+
+	// SettleTransactionTx 在指定的資料庫交易中產生交易的交割現金異動
+	SettleTransactionTx(tx *sql.Tx, transaction *models.Transaction) error
+	// ResettleTransaction 撤銷交易原有的交割現金異動後重新交割（交易更新後使用）
+	ResettleTransaction(transaction *models.Transaction) error
+	// ReverseTransaction 撤銷交易的交割現金異動（交易刪除前使用）
+	ReverseTransaction(transactionID uuid.UUID) error
+}
+
+// brokerageAccountService 券商帳戶業務邏輯實作
+type brokerageAccountService struct {
+	repo            repository.BrokerageAccountRepository
+	bankAccountRepo repository.BankAccountRepository
+	categoryRepo    repository.CategoryRepository
+	cashFlowService CashFlowService
+	holdingService  HoldingService
+}
+
+// NewBrokerageAccountService 建立新的券商帳戶 service
+func NewBrokerageAccountService(
+	repo repository.BrokerageAccountRepository,
+	bankAccountRepo repository.BankAccountRepository,
+	categoryRepo repository.CategoryRepository,
+	cashFlowService CashFlowService,
+	holdingService HoldingService,
+) BrokerageAccountService {
+	return &brokerageAccountService{
+		repo:            repo,
+		bankAccountRepo: bankAccountRepo,
+		categoryRepo:    categoryRepo,
+		cashFlowService: cashFlowService,
+		holdingService:  holdingService,
+	}
+}
+
+// CreateBrokerageAccount 建立新的券商帳戶
+func (s *brokerageAccountService) CreateBrokerageAccount(input *models.CreateBrokerageAccountInput) (*models.BrokerageAccount, error) {
+	if input.Name == "" {
+		return nil, fmt.Errorf("brokerage account name is required")
+	}
+
+	if !input.Currency.Validate() {
+		return nil, fmt.Errorf("invalid currency: %s", input.Currency)
+	}
+
+	if input.CashBalance < 0 {
+		return nil, fmt.Errorf("cash balance must be non-negative")
+	}
+
+	if input.SettlementAccountID != nil {
+		if err := s.validateSettlementAccount(*input.SettlementAccountID, input.Currency); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.repo.Create(input)
+}
+
+// GetBrokerageAccount 取得單筆券商帳戶
+func (s *brokerageAccountService) GetBrokerageAccount(id uuid.UUID) (*models.BrokerageAccount, error) {
+	return s.repo.GetByID(id)
+}
+
+// ListBrokerageAccounts 取得所有券商帳戶
+func (s *brokerageAccountService) ListBrokerageAccounts() ([]*models.BrokerageAccount, error) {
+	return s.repo.GetAll()
+}
+
+// UpdateBrokerageAccount 更新券商帳戶
+func (s *brokerageAccountService) UpdateBrokerageAccount(id uuid.UUID, input *models.UpdateBrokerageAccountInput) (*models.BrokerageAccount, error) {
+	if input.Name != nil && *input.Name == "" {
+		return nil, fmt.Errorf("brokerage account name cannot be empty")
+	}
+
+	if input.CashBalance != nil && *input.CashBalance < 0 {
+		return nil, fmt.Errorf("cash balance must be non-negative")
+	}
+
+	if input.SettlementAccountID != nil {
+		account, err := s.repo.GetByID(id)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.validateSettlementAccount(*input.SettlementAccountID, account.Currency); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.repo.Update(id, input)
+}
+
+// DeleteBrokerageAccount 刪除券商帳戶（交易記錄保留，僅解除關聯）
+func (s *brokerageAccountService) DeleteBrokerageAccount(id uuid.UUID) error {
+	return s.repo.Delete(id)
+}
+
+// GetMovements 取得券商帳戶的交割現金異動
+func (s *brokerageAccountService) GetMovements(id uuid.UUID) ([]*models.BrokerageCashMovement, error) {
+	if _, err := s.repo.GetByID(id); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetMovements(id)
+}
+
+// GetHoldings 取得券商帳戶的持倉
+func (s *brokerageAccountService) GetHoldings(id uuid.UUID) (*HoldingServiceResult, error) {
+	if _, err := s.repo.GetByID(id); err != nil {
+		return nil, err
+	}
+
+	return s.holdingService.GetAllHoldings(models.HoldingFilters{BrokerageAccountID: &id})
+}
+
+// SettleTransactionTx 在指定的資料庫交易中產生交易的交割現金異動
+// 有設定交割銀行帳戶時，於交割日在該帳戶建立轉帳現金流；否則直接異動券商現金餘額
+func (s *brokerageAccountService) SettleTransactionTx(tx *sql.Tx, transaction *models.Transaction) error {
+	if transaction.BrokerageAccountID == nil {
+		return nil
+	}
+
+	amount := roundToCents(transaction.CashAmount())
+	if amount == 0 {
+		return nil
+	}
+
+	account, err := s.repo.GetByID(*transaction.BrokerageAccountID)
+	if err != nil {
+		return err
+	}
+
+	if transaction.Currency != account.Currency {
+		return fmt.Errorf("transaction currency (%s) does not match brokerage account currency (%s)", transaction.Currency, account.Currency)
+	}
+
+	movement := &models.BrokerageCashMovement{
+		BrokerageAccountID: account.ID,
+		TransactionID:      transaction.ID,
+		Type:               transaction.TransactionType,
+		Amount:             amount,
+		TradeDate:          transaction.Date,
+		SettlementDate:     models.SettlementDate(transaction.AssetType, transaction.Date),
+	}
+
+	if account.SettlementAccountID != nil {
+		cashFlow, err := s.createSettlementCashFlow(tx, account, transaction, movement)
+		if err != nil {
+			return err
+		}
+		movement.BankAccountID = account.SettlementAccountID
+		movement.CashFlowID = &cashFlow.ID
+	} else {
+		updated, err := s.repo.UpdateCashBalanceTx(tx, account.ID, amount)
+		if err != nil {
+			return err
+		}
+		if updated.CashBalance < 0 {
+			return fmt.Errorf("insufficient_balance:brokerage_account:%.2f:%.2f", account.CashBalance, -amount)
+		}
+	}
+
+	if _, err := s.repo.CreateMovementTx(tx, movement); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ResettleTransaction 在同一個資料庫交易中撤銷交易原有的交割現金異動並重新交割
+func (s *brokerageAccountService) ResettleTransaction(transaction *models.Transaction) error {
+	return s.runInTx(func(tx *sql.Tx) error {
+		if err := s.reverseTransactionTx(tx, transaction.ID); err != nil {
+			return err
+		}
+		return s.SettleTransactionTx(tx, transaction)
+	})
+}
+
+// ReverseTransaction 撤銷交易的交割現金異動
+func (s *brokerageAccountService) ReverseTransaction(transactionID uuid.UUID) error {
+	return s.runInTx(func(tx *sql.Tx) error {
+		return s.reverseTransactionTx(tx, transactionID)
+	})
+}
+
+// runInTx 在資料庫交易中執行 fn，fn 回傳錯誤時回滾
+func (s *brokerageAccountService) runInTx(fn func(tx *sql.Tx) error) error {
+	tx, err := s.repo.DB().Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// reverseTransactionTx 在指定的資料庫交易中撤銷交易的交割現金異動
+// 由銀行帳戶交割者刪除對應現金流（一併回復銀行餘額），否則回復券商現金餘額
+func (s *brokerageAccountService) reverseTransactionTx(tx *sql.Tx, transactionID uuid.UUID) error {
+	movements, err := s.repo.GetMovementsByTransactionID(transactionID)
+	if err != nil {
+		return err
+	}
+
+	for _, movement := range movements {
+		if movement.CashFlowID != nil {
+			if err := s.cashFlowService.DeleteCashFlowTx(tx, *movement.CashFlowID); err != nil {
+				return fmt.Errorf("failed to reverse settlement cash flow: %w", err)
+			}
+		} else if movement.BankAccountID == nil {
+			if _, err := s.repo.UpdateCashBalanceTx(tx, movement.BrokerageAccountID, -movement.Amount); err != nil {
+				return fmt.Errorf("failed to reverse brokerage cash balance: %w", err)
+			}
+		}
+
+		if err := s.repo.DeleteMovementTx(tx, movement.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// createSettlementCashFlow 在交割銀行帳戶建立交割現金流
+func (s *brokerageAccountService) createSettlementCashFlow(tx *sql.Tx, account *models.BrokerageAccount, transaction *models.Transaction, movement *models.BrokerageCashMovement) (*models.CashFlow, error) {
+	flowType := models.CashFlowTypeTransferIn
+	if movement.Amount < 0 {
+		flowType = models.CashFlowTypeTransferOut
+	}
+
+	category, err := s.findSettlementCategory(flowType)
+	if err != nil {
+		return nil, err
+	}
+
+	amount := movement.Amount
+	if amount < 0 {
+		amount = -amount
+	}
+
+	sourceType := models.SourceTypeBankAccount
+	cashFlow, err := s.cashFlowService.CreateCashFlowTx(tx, &models.CreateCashFlowInput{
+		Date:        movement.SettlementDate,
+		Type:        flowType,
+		CategoryID:  category.ID,
+		Amount:      amount,
+		Description: settlementDescription(transaction, account),
+		SourceType:  &sourceType,
+		SourceID:    account.SettlementAccountID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create settlement cash flow: %w", err)
+	}
+
+	return cashFlow, nil
+}
+
+// findSettlementCategory 取得交割現金流使用的系統分類
+func (s *brokerageAccountService) findSettlementCategory(flowType models.CashFlowType) (*models.CashFlowCategory, error) {
+	categories, err := s.categoryRepo.GetAll(&flowType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get categories: %w", err)
+	}
+
+	for _, category := range categories {
+		if category.Name == models.SettlementCategoryName {
+			return category, nil
+		}
+	}
+
+	return nil, fmt.Errorf("settlement category not found for type %s", flowType)
+}
+
+// validateSettlementAccount 驗證交割銀行帳戶存在且幣別與券商帳戶一致
+func (s *brokerageAccountService) validateSettlementAccount(bankAccountID uuid.UUID, currency models.Currency) error {
+	bankAccount, err := s.bankAccountRepo.GetByID(bankAccountID)
+	if err != nil {
+		return fmt.Errorf("invalid settlement account: %w", err)
+	}
+
+	if bankAccount.Currency != currency {
+		return fmt.Errorf("settlement account currency (%s) does not match brokerage account currency (%s)", bankAccount.Currency, currency)
+	}
+
+	return nil
+}
+
+// settlementDescription 產生交割現金流的描述，例如「買進 2330 台積電 - 元大證券」
+func settlementDescription(transaction *models.Transaction, account *models.BrokerageAccount) string {
+	label := map[models.TransactionType]string{
+		models.TransactionTypeBuy:      "買進",
+		models.TransactionTypeSell:     "賣出",
+		models.TransactionTypeDividend: "股息",
+		models.TransactionTypeFee:      "手續費",
+		models.TransactionTypeShort:    "放空",
+		models.TransactionTypeCover:    "回補",
+	}[transaction.TransactionType]
+
+	return fmt.Sprintf("%s %s %s - %s", label, transaction.Symbol, transaction.Name, account.Name)
+}
@@ -0,0 +1,241 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/chienchuanw/asset-manager/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// newTestBuyTransaction 建立測試用台股買進交易（2025-10-16 週四，成交 100000、手續費 142）
+func newTestBuyTransaction(accountID uuid.UUID) *models.Transaction {
+	fee := 142.0
+	return &models.Transaction{
+		ID:                 uuid.New(),
+		Date:               time.Date(2025, 10, 16, 0, 0, 0, 0, time.UTC),
+		AssetType:          models.AssetTypeTWStock,
+		Symbol:             "2330",
+		Name:               "台積電",
+		TransactionType:    models.TransactionTypeBuy,
+		Quantity:           100,
+		Price:              1000,
+		Amount:             100000,
+		Fee:                &fee,
+		Currency:           models.CurrencyTWD,
+		BrokerageAccountID: &accountID,
+	}
+}
+
+func TestBrokerageAccountService_SettleTransactionTx_SettlementBankAccount(t *testing.T) {
+	db, dbMock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mockRepo := new(MockBrokerageAccountRepository)
+	mockBankAccountRepo := new(MockBankAccountRepository)
+	mockCategoryRepo := new(MockCategoryRepository)
+	mockCashFlowRepo := new(MockCashFlowRepository)
+
+	accountID := uuid.New()
+	bankAccountID := uuid.New()
+	categoryID := uuid.New()
+	cashFlowID := uuid.New()
+	transaction := newTestBuyTransaction(accountID)
+	settlementDate := time.Date(2025, 10, 20, 0, 0, 0, 0, time.UTC)
+
+	cashFlowService := NewCashFlowService(mockCashFlowRepo, mockCategoryRepo, mockBankAccountRepo, nil)
+	service := NewBrokerageAccountService(mockRepo, mockBankAccountRepo, mockCategoryRepo, cashFlowService, nil)
+
+	// 設定 mock 期望：於 T+2 交割日自交割銀行帳戶轉出成交金額加手續費
+	transferOut := models.CashFlowTypeTransferOut
+	category := &models.CashFlowCategory{ID: categoryID, Name: models.SettlementCategoryName, Type: models.CashFlowTypeTransferOut}
+	dbMock.ExpectBegin()
+	mockRepo.On("GetByID", accountID).Return(&models.BrokerageAccount{
+		ID:                  accountID,
+		Name:                "元大證券",
+		Currency:            models.CurrencyTWD,
+		SettlementAccountID: &bankAccountID,
+	}, nil)
+	mockCategoryRepo.On("GetAll", &transferOut).Return([]*models.CashFlowCategory{
+		{ID: uuid.New(), Name: "移轉", Type: models.CashFlowTypeTransferOut},
+		category,
+	}, nil)
+	mockCategoryRepo.On("GetByID", categoryID).Return(category, nil)
+	mockBankAccountRepo.On("GetByIDForUpdateTx", mock.AnythingOfType("*sql.Tx"), bankAccountID).Return(&models.BankAccount{ID: bankAccountID, Balance: 200000}, nil)
+	mockBankAccountRepo.On("UpdateBalanceTx", mock.AnythingOfType("*sql.Tx"), bankAccountID, -100142.0).Return(&models.BankAccount{}, nil)
+	mockCashFlowRepo.On("CreateTx", mock.AnythingOfType("*sql.Tx"), mock.MatchedBy(func(input *models.CreateCashFlowInput) bool {
+		return input.Amount == 100142 && input.Date.Equal(settlementDate) && input.Description == "買進 2330 台積電 - 元大證券"
+	})).Return(&models.CashFlow{ID: cashFlowID}, nil)
+	mockRepo.On("CreateMovementTx", mock.AnythingOfType("*sql.Tx"), mock.MatchedBy(func(movement *models.BrokerageCashMovement) bool {
+		return movement.Amount == -100142 && movement.SettlementDate.Equal(settlementDate) &&
+			*movement.BankAccountID == bankAccountID && *movement.CashFlowID == cashFlowID
+	})).Return(&models.BrokerageCashMovement{}, nil)
+
+	// 執行測試
+	tx, err := db.Begin()
+	assert.NoError(t, err)
+	err = service.SettleTransactionTx(tx, transaction)
+
+	// 驗證結果：不異動券商現金餘額
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+	mockCashFlowRepo.AssertExpectations(t)
+	mockBankAccountRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "UpdateCashBalanceTx")
+}
+
+func TestBrokerageAccountService_SettleTransactionTx_InsufficientCashBalance(t *testing.T) {
+	db, dbMock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mockRepo := new(MockBrokerageAccountRepository)
+	service := NewBrokerageAccountService(mockRepo, nil, nil, nil, nil)
+
+	accountID := uuid.New()
+	transaction := newTestBuyTransaction(accountID)
+
+	// 設定 mock 期望：無交割銀行帳戶，直接扣除券商現金餘額
+	dbMock.ExpectBegin()
+	mockRepo.On("GetByID", accountID).Return(&models.BrokerageAccount{ID: accountID, Currency: models.CurrencyTWD, CashBalance: 50000}, nil)
+	mockRepo.On("UpdateCashBalanceTx", mock.AnythingOfType("*sql.Tx"), accountID, -100142.0).Return(&models.BrokerageAccount{ID: accountID, CashBalance: -50142}, nil)
+
+	// 執行測試
+	tx, err := db.Begin()
+	assert.NoError(t, err)
+	err = service.SettleTransactionTx(tx, transaction)
+
+	// 驗證結果
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "insufficient_balance:brokerage_account")
+	mockRepo.AssertNotCalled(t, "CreateMovementTx")
+}
+
+func TestBrokerageAccountService_SettleTransactionTx_CurrencyMismatch(t *testing.T) {
+	mockRepo := new(MockBrokerageAccountRepository)
+	service := NewBrokerageAccountService(mockRepo, nil, nil, nil, nil)
+
+	accountID := uuid.New()
+	transaction := newTestBuyTransaction(accountID)
+
+	mockRepo.On("GetByID", accountID).Return(&models.BrokerageAccount{ID: accountID, Currency: models.CurrencyUSD}, nil)
+
+	// 執行測試
+	err := service.SettleTransactionTx(nil, transaction)
+
+	// 驗證結果
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "does not match brokerage account currency")
+}
+
+func TestBrokerageAccountService_ReverseTransaction(t *testing.T) {
+	db, dbMock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mockRepo := new(MockBrokerageAccountRepository)
+	service := NewBrokerageAccountService(mockRepo, nil, nil, nil, nil)
+
+	accountID := uuid.New()
+	transactionID := uuid.New()
+	movementID := uuid.New()
+
+	// 設定 mock 期望：在交易中回復券商現金餘額並刪除異動記錄
+	dbMock.ExpectBegin()
+	dbMock.ExpectCommit()
+	mockRepo.On("DB").Return(db)
+	mockRepo.On("GetMovementsByTransactionID", transactionID).Return([]*models.BrokerageCashMovement{
+		{ID: movementID, BrokerageAccountID: accountID, TransactionID: transactionID, Amount: -100142},
+	}, nil)
+	mockRepo.On("UpdateCashBalanceTx", mock.AnythingOfType("*sql.Tx"), accountID, 100142.0).Return(&models.BrokerageAccount{}, nil)
+	mockRepo.On("DeleteMovementTx", mock.AnythingOfType("*sql.Tx"), movementID).Return(nil)
+
+	// 執行測試
+	err = service.ReverseTransaction(transactionID)
+
+	// 驗證結果
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+	assert.NoError(t, dbMock.ExpectationsWereMet())
+}
+
+func TestBrokerageAccountService_ResettleTransaction_SingleTransaction(t *testing.T) {
+	db, dbMock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mockRepo := new(MockBrokerageAccountRepository)
+	mockBankAccountRepo := new(MockBankAccountRepository)
+	mockCategoryRepo := new(MockCategoryRepository)
+	mockCashFlowRepo := new(MockCashFlowRepository)
+	cashFlowService := NewCashFlowService(mockCashFlowRepo, mockCategoryRepo, mockBankAccountRepo, nil)
+	service := NewBrokerageAccountService(mockRepo, mockBankAccountRepo, mockCategoryRepo, cashFlowService, nil)
+
+	accountID := uuid.New()
+	bankAccountID := uuid.New()
+	oldCashFlowID := uuid.New()
+	oldMovementID := uuid.New()
+	transaction := newTestBuyTransaction(accountID)
+	bankAccount := models.SourceTypeBankAccount
+	transferOut := models.CashFlowTypeTransferOut
+	category := &models.CashFlowCategory{ID: uuid.New(), Name: models.SettlementCategoryName, Type: models.CashFlowTypeTransferOut}
+
+	// 設定 mock 期望：重新交割時重新產生交割現金流，新交割失敗時整筆回滾、原交割不會被撤銷
+	dbMock.ExpectBegin()
+	dbMock.ExpectRollback()
+	mockRepo.On("DB").Return(db)
+	mockRepo.On("GetMovementsByTransactionID", transaction.ID).Return([]*models.BrokerageCashMovement{
+		{ID: oldMovementID, BrokerageAccountID: accountID, TransactionID: transaction.ID, Amount: -50071, BankAccountID: &bankAccountID, CashFlowID: &oldCashFlowID},
+	}, nil)
+	mockCashFlowRepo.On("GetByID", oldCashFlowID).Return(&models.CashFlow{
+		ID: oldCashFlowID, Type: models.CashFlowTypeTransferOut, Amount: 50071, SourceType: &bankAccount, SourceID: &bankAccountID,
+	}, nil)
+	mockBankAccountRepo.On("GetByIDForUpdateTx", mock.AnythingOfType("*sql.Tx"), bankAccountID).Return(&models.BankAccount{ID: bankAccountID, Balance: 60000}, nil)
+	mockBankAccountRepo.On("UpdateBalanceTx", mock.AnythingOfType("*sql.Tx"), bankAccountID, 50071.0).Return(&models.BankAccount{}, nil)
+	mockCashFlowRepo.On("DeleteTx", mock.AnythingOfType("*sql.Tx"), oldCashFlowID).Return(nil)
+	mockRepo.On("DeleteMovementTx", mock.AnythingOfType("*sql.Tx"), oldMovementID).Return(nil)
+	mockRepo.On("GetByID", accountID).Return(&models.BrokerageAccount{
+		ID:                  accountID,
+		Name:                "元大證券",
+		Currency:            models.CurrencyTWD,
+		SettlementAccountID: &bankAccountID,
+	}, nil)
+	mockCategoryRepo.On("GetAll", &transferOut).Return([]*models.CashFlowCategory{category}, nil)
+	mockCategoryRepo.On("GetByID", category.ID).Return(category, nil)
+
+	// 執行測試：交割帳戶餘額（鎖定讀取時仍為 60000）不足以支付 100142
+	err = service.ResettleTransaction(transaction)
+
+	// 驗證結果
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "insufficient_balance:bank_account")
+	mockCashFlowRepo.AssertNotCalled(t, "CreateTx", mock.Anything, mock.Anything)
+	mockRepo.AssertNotCalled(t, "CreateMovementTx", mock.Anything, mock.Anything)
+	assert.NoError(t, dbMock.ExpectationsWereMet())
+}
+
+func TestBrokerageAccountService_CreateBrokerageAccount_SettlementCurrencyMismatch(t *testing.T) {
+	mockRepo := new(MockBrokerageAccountRepository)
+	mockBankAccountRepo := new(MockBankAccountRepository)
+	service := NewBrokerageAccountService(mockRepo, mockBankAccountRepo, nil, nil, nil)
+
+	bankAccountID := uuid.New()
+	mockBankAccountRepo.On("GetByID", bankAccountID).Return(&models.BankAccount{ID: bankAccountID, Currency: models.CurrencyUSD}, nil)
+
+	// 執行測試
+	account, err := service.CreateBrokerageAccount(&models.CreateBrokerageAccountInput{
+		Name:                "元大證券",
+		Broker:              "元大",
+		Currency:            models.CurrencyTWD,
+		SettlementAccountID: &bankAccountID,
+	})
+
+	// 驗證結果
+	assert.Error(t, err)
+	assert.Nil(t, account)
+	mockRepo.AssertNotCalled(t, "Create")
+}
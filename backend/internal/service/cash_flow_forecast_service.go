@@ -0,0 +1,746 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/chienchuanw/asset-manager/internal/models"
+	"github.com/chienchuanw/asset-manager/internal/repository"
+	"github.com/google/uuid"
+)
+
+// CashFlowForecastService 現金流預測業務邏輯介面
+type CashFlowForecastService interface {
+	// GetForecast 預測未來數個月每日的銀行帳戶餘額與總流動性
+	// months: 預測月數（1-12，0 使用預設值）
+	// threshold: 帳戶餘額低於此金額時產生警示
+	GetForecast(months int, threshold float64) (*models.CashFlowForecast, error)
+}
+
+// cashFlowForecastService 現金流預測業務邏輯實作
+type cashFlowForecastService struct {
+	bankAccountRepo     repository.BankAccountRepository
+	creditCardRepo      repository.CreditCardRepository
+	statementRepo       repository.CreditCardStatementRepository
+	subscriptionRepo    repository.SubscriptionRepository
+	installmentRepo     repository.InstallmentRepository
+	loanRepo            repository.LoanRepository
+	recurringRepo       repository.RecurringTemplateRepository
+	cashFlowRepo        repository.CashFlowRepository
+	exchangeRateService ExchangeRateService
+}
+
+// NewCashFlowForecastService 建立新的現金流預測 service
+func NewCashFlowForecastService(
+	bankAccountRepo repository.BankAccountRepository,
+	creditCardRepo repository.CreditCardRepository,
+	statementRepo repository.CreditCardStatementRepository,
+	subscriptionRepo repository.SubscriptionRepository,
+	installmentRepo repository.InstallmentRepository,
+	loanRepo repository.LoanRepository,
+	recurringRepo repository.RecurringTemplateRepository,
+	cashFlowRepo repository.CashFlowRepository,
+	exchangeRateService ExchangeRateService,
+) CashFlowForecastService {
+	return &cashFlowForecastService{
+		bankAccountRepo:     bankAccountRepo,
+		creditCardRepo:      creditCardRepo,
+		statementRepo:       statementRepo,
+		subscriptionRepo:    subscriptionRepo,
+		installmentRepo:     installmentRepo,
+		loanRepo:            loanRepo,
+		recurringRepo:       recurringRepo,
+		cashFlowRepo:        cashFlowRepo,
+		exchangeRateService: exchangeRateService,
+	}
+}
+
+// forecastBuilder 累積預測期間內各帳戶與信用卡的每日變動
+type forecastBuilder struct {
+	today    time.Time
+	end      time.Time
+	days     int
+	forecast *models.CashFlowForecast
+
+	accountIndex map[uuid.UUID]int
+	deltas       [][]float64 // 各帳戶每日的排程變動
+	unassigned   []float64   // 找不到繳款帳戶的信用卡繳款（僅影響總流動性）
+
+	cards       map[uuid.UUID]*models.CreditCard
+	cardCycles  map[uuid.UUID]map[time.Time]float64 // 各信用卡每個結帳日累積的應繳金額
+	cardPayers  map[uuid.UUID]*uuid.UUID            // 各信用卡的繳款帳戶
+	cardCredits []*cardCredit                       // 排程中的信用卡繳款（自應繳金額扣除）
+
+	scheduledCategories map[uuid.UUID]bool
+}
+
+// cardCredit 排程中的信用卡繳款
+type cardCredit struct {
+	cardID uuid.UUID
+	date   time.Time
+	amount float64
+}
+
+// GetForecast 預測未來數個月每日的銀行帳戶餘額與總流動性
+func (s *cashFlowForecastService) GetForecast(months int, threshold float64) (*models.CashFlowForecast, error) {
+	if months == 0 {
+		months = models.DefaultForecastMonths
+	}
+
+	if months < 1 || months > models.MaxForecastMonths {
+		return nil, fmt.Errorf("months must be between 1 and %d", models.MaxForecastMonths)
+	}
+
+	return s.buildForecast(models.BillingDate(time.Now()), months, threshold)
+}
+
+// buildForecast 以指定日期為起點建立預測
+// 排程項目（訂閱、分期、貸款、定期收支）依各自的扣款日計入，
+// 以信用卡支付的項目於對應帳單的繳款截止日才自繳款帳戶扣除，
+// 其餘支出以過去數個月的分類平均（排除已有排程的分類）平均分攤到每一天
+func (s *cashFlowForecastService) buildForecast(today time.Time, months int, threshold float64) (*models.CashFlowForecast, error) {
+	end := today.AddDate(0, months, 0)
+	days := int(end.Sub(today).Hours() / 24)
+
+	b := &forecastBuilder{
+		today: today,
+		end:   end,
+		days:  days,
+		forecast: &models.CashFlowForecast{
+			StartDate:        today,
+			EndDate:          end,
+			Months:           months,
+			Threshold:        threshold,
+			Accounts:         []*models.AccountForecast{},
+			Liquidity:        []*models.ForecastBalancePoint{},
+			Events:           []*models.ForecastEvent{},
+			CategorySpending: []*models.ForecastCategorySpending{},
+			Warnings:         []*models.ForecastWarning{},
+		},
+		accountIndex:        make(map[uuid.UUID]int),
+		unassigned:          make([]float64, days+1),
+		cards:               make(map[uuid.UUID]*models.CreditCard),
+		cardCycles:          make(map[uuid.UUID]map[time.Time]float64),
+		cardPayers:          make(map[uuid.UUID]*uuid.UUID),
+		scheduledCategories: make(map[uuid.UUID]bool),
+	}
+
+	if err := s.loadAccounts(b); err != nil {
+		return nil, err
+	}
+	if err := s.loadCreditCards(b); err != nil {
+		return nil, err
+	}
+	if err := s.addSubscriptions(b); err != nil {
+		return nil, err
+	}
+	if err := s.addInstallments(b); err != nil {
+		return nil, err
+	}
+	if err := s.addLoans(b); err != nil {
+		return nil, err
+	}
+	if err := s.addRecurringTemplates(b); err != nil {
+		return nil, err
+	}
+
+	accountDrift, cardDrift, err := s.averageSpending(b)
+	if err != nil {
+		return nil, err
+	}
+
+	b.settleCreditCards(cardDrift)
+	b.project(accountDrift, threshold)
+
+	sort.SliceStable(b.forecast.Events, func(i, j int) bool {
+		return b.forecast.Events[i].Date.Before(b.forecast.Events[j].Date)
+	})
+
+	return b.forecast, nil
+}
+
+// loadAccounts 載入銀行帳戶目前餘額（換算為 TWD）
+func (s *cashFlowForecastService) loadAccounts(b *forecastBuilder) error {
+	accounts, err := s.bankAccountRepo.GetAll(nil)
+	if err != nil {
+		return fmt.Errorf("failed to get bank accounts: %w", err)
+	}
+
+	for _, account := range accounts {
+		balance, err := s.convertToTWD(account.Balance, account.Currency, b.today)
+		if err != nil {
+			return err
+		}
+
+		b.accountIndex[account.ID] = len(b.forecast.Accounts)
+		b.deltas = append(b.deltas, make([]float64, b.days+1))
+		b.forecast.Accounts = append(b.forecast.Accounts, &models.AccountForecast{
+			AccountID:          account.ID,
+			BankName:           account.BankName,
+			AccountNumberLast4: account.AccountNumberLast4,
+			Currency:           account.Currency,
+			StartingBalance:    balance,
+			DailyBalances:      []*models.ForecastBalancePoint{},
+		})
+	}
+
+	return nil
+}
+
+// loadCreditCards 載入信用卡未繳帳單、尚未出帳的已使用額度與繳款帳戶
+func (s *cashFlowForecastService) loadCreditCards(b *forecastBuilder) error {
+	cards, err := s.creditCardRepo.GetAll()
+	if err != nil {
+		return fmt.Errorf("failed to get credit cards: %w", err)
+	}
+
+	transferOut := models.CashFlowTypeTransferOut
+	for _, card := range cards {
+		b.cards[card.ID] = card
+		b.cardCycles[card.ID] = make(map[time.Time]float64)
+
+		statements, err := s.statementRepo.GetByCreditCardID(card.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get credit card statements: %w", err)
+		}
+
+		// 已出帳但尚未繳清的帳單
+		outstanding := 0.0
+		statementClosings := make(map[time.Time]bool)
+		for _, statement := range statements {
+			closing := models.BillingDate(statement.ClosingDate)
+			statementClosings[closing] = true
+			if amount := statement.OutstandingAmount(); amount > 0 {
+				b.cardCycles[card.ID][closing] += amount
+				outstanding += amount
+			}
+		}
+
+		// 尚未出帳的消費計入本期帳單
+		if unbilled := card.UsedCredit - outstanding; unbilled > 0 {
+			closing := cycleClosing(card, b.today)
+			if statementClosings[closing] {
+				closing = card.NextClosingDate(closing)
+			}
+			b.cardCycles[card.ID][closing] += unbilled
+		}
+
+		// 以最近一次由銀行帳戶繳款的帳戶作為繳款帳戶
+		payments, err := s.cashFlowRepo.GetAll(repository.CashFlowFilters{
+			CreditCardID: &card.ID,
+			Type:         &transferOut,
+			Limit:        20,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get credit card payments: %w", err)
+		}
+		for _, payment := range payments {
+			if !payment.IsCreditCardPayment(card.ID) || payment.SourceType == nil ||
+				*payment.SourceType != models.SourceTypeBankAccount || payment.SourceID == nil {
+				continue
+			}
+			if _, ok := b.accountIndex[*payment.SourceID]; ok {
+				b.cardPayers[card.ID] = payment.SourceID
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// addSubscriptions 加入有效訂閱的扣款（依扣款日適用的價格）
+func (s *cashFlowForecastService) addSubscriptions(b *forecastBuilder) error {
+	status := models.SubscriptionStatusActive
+	subscriptions, err := s.subscriptionRepo.List(repository.SubscriptionFilters{Status: &status})
+	if err != nil {
+		return fmt.Errorf("failed to list subscriptions: %w", err)
+	}
+
+	for _, subscription := range subscriptions {
+		b.scheduledCategories[subscription.CategoryID] = true
+
+		changes, err := s.subscriptionRepo.GetPriceChanges(subscription.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get price changes: %w", err)
+		}
+		subscription.PriceChanges = changes
+
+		for date := subscription.NextBillingDate(b.today); !date.After(b.end); {
+			if subscription.EndDate != nil && date.After(*subscription.EndDate) {
+				break
+			}
+
+			if date.After(b.today) {
+				amount, err := s.convertToTWD(subscription.PriceOn(date), subscription.Currency, date)
+				if err != nil {
+					return err
+				}
+				b.addEvent(&models.ForecastEvent{
+					Date:     date,
+					Source:   models.ForecastSourceSubscription,
+					SourceID: subscription.ID,
+					Name:     subscription.Name,
+					Amount:   -amount,
+				}, subscription.PaymentMethod, subscription.AccountID)
+			}
+
+			next := subscription.NextBillingDate(date)
+			if !next.After(date) {
+				break
+			}
+			date = next
+		}
+	}
+
+	return nil
+}
+
+// addInstallments 加入進行中分期的剩餘期數（依付款計畫，含提前還款）
+func (s *cashFlowForecastService) addInstallments(b *forecastBuilder) error {
+	status := models.InstallmentStatusActive
+	installments, err := s.installmentRepo.List(repository.InstallmentFilters{Status: &status})
+	if err != nil {
+		return fmt.Errorf("failed to list installments: %w", err)
+	}
+
+	for _, installment := range installments {
+		b.scheduledCategories[installment.CategoryID] = true
+
+		// 依付款計畫計算每期金額（含提前還款後的重新攤提）
+		prepayments, err := s.installmentRepo.GetPrepayments(installment.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get installment prepayments: %w", err)
+		}
+		installment.Prepayments = prepayments
+
+		for _, entry := range installment.GenerateSchedule().Entries {
+			if entry.IsPaid {
+				continue
+			}
+			date := entry.BillingDate
+			if date.After(b.end) {
+				break
+			}
+			if !date.After(b.today) {
+				continue
+			}
+
+			amount, err := s.convertToTWD(entry.Payment, installment.Currency, date)
+			if err != nil {
+				return err
+			}
+			b.addEvent(&models.ForecastEvent{
+				Date:     date,
+				Source:   models.ForecastSourceInstallment,
+				SourceID: installment.ID,
+				Name:     installment.Name,
+				Amount:   -amount,
+			}, installment.PaymentMethod, installment.AccountID)
+		}
+	}
+
+	return nil
+}
+
+// addLoans 加入還款中貸款的未繳期數（依攤還表，含利率調整與提前還本）
+func (s *cashFlowForecastService) addLoans(b *forecastBuilder) error {
+	status := models.LoanStatusActive
+	loans, err := s.loanRepo.List(repository.LoanFilters{Status: &status})
+	if err != nil {
+		return fmt.Errorf("failed to list loans: %w", err)
+	}
+
+	for _, loan := range loans {
+		b.scheduledCategories[loan.CategoryID] = true
+		if loan.InterestCategoryID != nil {
+			b.scheduledCategories[*loan.InterestCategoryID] = true
+		}
+
+		if loan.RateChanges, err = s.loanRepo.GetRateChanges(loan.ID); err != nil {
+			return fmt.Errorf("failed to get rate changes: %w", err)
+		}
+		if loan.Prepayments, err = s.loanRepo.GetPrepayments(loan.ID); err != nil {
+			return fmt.Errorf("failed to get prepayments: %w", err)
+		}
+
+		for _, entry := range loan.GenerateAmortizationSchedule(b.today).Entries {
+			if entry.Period <= loan.PaidCount || !entry.PaymentDate.After(b.today) {
+				continue
+			}
+			if entry.PaymentDate.After(b.end) {
+				break
+			}
+
+			amount, err := s.convertToTWD(entry.Payment, loan.Currency, entry.PaymentDate)
+			if err != nil {
+				return err
+			}
+			b.addEvent(&models.ForecastEvent{
+				Date:     entry.PaymentDate,
+				Source:   models.ForecastSourceLoan,
+				SourceID: loan.ID,
+				Name:     fmt.Sprintf("%s 第 %d 期", loan.Name, entry.Period),
+				Amount:   -amount,
+			}, loan.PaymentMethod, loan.AccountID)
+		}
+	}
+
+	return nil
+}
+
+// addRecurringTemplates 加入啟用中定期收支範本的發生日（含轉帳目標）
+func (s *cashFlowForecastService) addRecurringTemplates(b *forecastBuilder) error {
+	status := models.RecurringTemplateStatusActive
+	templates, err := s.recurringRepo.List(repository.RecurringTemplateFilters{Status: &status})
+	if err != nil {
+		return fmt.Errorf("failed to list recurring templates: %w", err)
+	}
+
+	for _, template := range templates {
+		if template.Type == models.CashFlowTypeExpense {
+			b.scheduledCategories[template.CategoryID] = true
+		}
+
+		if template.AmountChanges, err = s.recurringRepo.GetAmountChanges(template.ID); err != nil {
+			return fmt.Errorf("failed to get amount changes: %w", err)
+		}
+
+		for _, occurrence := range template.Occurrences(b.today.AddDate(0, 0, 1), b.end) {
+			amount, err := s.convertToTWD(occurrence.Amount, occurrence.Currency, occurrence.Date)
+			if err != nil {
+				return err
+			}
+
+			signed := amount
+			if template.Type == models.CashFlowTypeExpense || template.Type == models.CashFlowTypeTransferOut {
+				signed = -amount
+			}
+
+			b.addEvent(&models.ForecastEvent{
+				Date:     occurrence.Date,
+				Source:   models.ForecastSourceRecurring,
+				SourceID: template.ID,
+				Name:     template.Name,
+				Amount:   signed,
+			}, template.PaymentMethod, template.AccountID)
+
+			// 轉帳目標：轉入銀行帳戶或繳納信用卡
+			if template.Type != models.CashFlowTypeTransferOut || template.TargetType == nil || template.TargetID == nil {
+				continue
+			}
+			switch *template.TargetType {
+			case models.SourceTypeBankAccount:
+				b.addEvent(&models.ForecastEvent{
+					Date:     occurrence.Date,
+					Source:   models.ForecastSourceRecurring,
+					SourceID: template.ID,
+					Name:     template.Name,
+					Amount:   amount,
+				}, models.PaymentMethodBankAccount, template.TargetID)
+			case models.SourceTypeCreditCard:
+				if _, ok := b.cards[*template.TargetID]; ok {
+					b.cardCredits = append(b.cardCredits, &cardCredit{cardID: *template.TargetID, date: occurrence.Date, amount: amount})
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// averageSpending 以過去數個月的非排程支出推估每日平均支出
+// 回傳各銀行帳戶與各信用卡的每日平均支出（TWD）
+func (s *cashFlowForecastService) averageSpending(b *forecastBuilder) ([]float64, map[uuid.UUID]float64, error) {
+	from := b.today.AddDate(0, -models.DefaultForecastLookbackMonths, 0)
+	to := b.today.AddDate(0, 0, -1)
+	lookbackDays := b.today.Sub(from).Hours() / 24
+
+	expense := models.CashFlowTypeExpense
+	cashFlows, err := s.cashFlowRepo.GetAll(repository.CashFlowFilters{
+		Type:      &expense,
+		StartDate: &from,
+		EndDate:   &to,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get historical cash flows: %w", err)
+	}
+
+	accountDrift := make([]float64, len(b.forecast.Accounts))
+	cardDrift := make(map[uuid.UUID]float64)
+	categoryTotals := make(map[uuid.UUID]*models.ForecastCategorySpending)
+	total := 0.0
+
+	for _, cashFlow := range cashFlows {
+		// 已有排程的分類由排程項目計入，避免重複計算
+		if b.scheduledCategories[cashFlow.CategoryID] {
+			continue
+		}
+
+		amount, err := s.convertToTWD(cashFlow.Amount, cashFlow.Currency, cashFlow.Date)
+		if err != nil {
+			return nil, nil, err
+		}
+		total += amount
+
+		category, ok := categoryTotals[cashFlow.CategoryID]
+		if !ok {
+			category = &models.ForecastCategorySpending{CategoryID: cashFlow.CategoryID}
+			if cashFlow.Category != nil {
+				category.CategoryName = cashFlow.Category.Name
+			}
+			categoryTotals[cashFlow.CategoryID] = category
+			b.forecast.CategorySpending = append(b.forecast.CategorySpending, category)
+		}
+		category.MonthlyAverage += amount
+
+		if cashFlow.SourceType == nil || cashFlow.SourceID == nil {
+			continue
+		}
+		switch *cashFlow.SourceType {
+		case models.SourceTypeBankAccount:
+			if index, ok := b.accountIndex[*cashFlow.SourceID]; ok {
+				accountDrift[index] += amount / lookbackDays
+			}
+		case models.SourceTypeCreditCard:
+			if _, ok := b.cards[*cashFlow.SourceID]; ok {
+				cardDrift[*cashFlow.SourceID] += amount / lookbackDays
+			}
+		}
+	}
+
+	for _, category := range b.forecast.CategorySpending {
+		category.MonthlyAverage = roundToCents(category.MonthlyAverage / models.DefaultForecastLookbackMonths)
+	}
+	sort.SliceStable(b.forecast.CategorySpending, func(i, j int) bool {
+		return b.forecast.CategorySpending[i].MonthlyAverage > b.forecast.CategorySpending[j].MonthlyAverage
+	})
+	b.forecast.AverageMonthlySpend = roundToCents(total / models.DefaultForecastLookbackMonths)
+
+	for i, account := range b.forecast.Accounts {
+		account.AverageDailySpend = roundToCents(accountDrift[i])
+	}
+
+	return accountDrift, cardDrift, nil
+}
+
+// addEvent 記錄預測事件並依付款方式計入銀行帳戶或信用卡帳單
+func (b *forecastBuilder) addEvent(event *models.ForecastEvent, paymentMethod models.PaymentMethod, accountID *uuid.UUID) {
+	event.Amount = roundToCents(event.Amount)
+
+	switch paymentMethod {
+	case models.PaymentMethodBankAccount:
+		if accountID != nil {
+			if _, ok := b.accountIndex[*accountID]; ok {
+				event.AccountID = accountID
+				b.applyToAccount(accountID, event.Date, event.Amount)
+			}
+		}
+	case models.PaymentMethodCreditCard:
+		if accountID != nil {
+			event.CreditCardID = accountID
+			if card, ok := b.cards[*accountID]; ok {
+				// 支出增加應繳金額、退款減少應繳金額
+				b.cardCycles[card.ID][cycleClosing(card, event.Date)] -= event.Amount
+			}
+		}
+	}
+
+	b.forecast.Events = append(b.forecast.Events, event)
+}
+
+// applyToAccount 將金額計入指定日期的銀行帳戶變動（找不到帳戶時僅計入總流動性）
+func (b *forecastBuilder) applyToAccount(accountID *uuid.UUID, date time.Time, amount float64) {
+	day := int(date.Sub(b.today).Hours() / 24)
+	if day < 0 || day > b.days {
+		return
+	}
+
+	if accountID != nil {
+		if index, ok := b.accountIndex[*accountID]; ok {
+			b.deltas[index][day] += amount
+		} else {
+			b.unassigned[day] += amount
+		}
+	} else {
+		b.unassigned[day] += amount
+	}
+
+	if amount > 0 {
+		b.forecast.ScheduledInflow += amount
+	} else {
+		b.forecast.ScheduledOutflow -= amount
+	}
+}
+
+// settleCreditCards 將信用卡每日平均支出計入帳單，扣除排程繳款後於繳款截止日自繳款帳戶扣除
+func (b *forecastBuilder) settleCreditCards(cardDrift map[uuid.UUID]float64) {
+	for cardID, drift := range cardDrift {
+		card := b.cards[cardID]
+		for day := 1; day <= b.days; day++ {
+			b.cardCycles[cardID][cycleClosing(card, b.today.AddDate(0, 0, day))] += drift
+		}
+	}
+
+	// 排程繳款自該日之前最早尚有餘額的帳單扣除
+	for _, credit := range b.cardCredits {
+		remaining := credit.amount
+		for _, closing := range sortedClosings(b.cardCycles[credit.cardID]) {
+			if remaining <= 0 || closing.After(credit.date) {
+				break
+			}
+			if due := b.cardCycles[credit.cardID][closing]; due > 0 {
+				paid := due
+				if paid > remaining {
+					paid = remaining
+				}
+				b.cardCycles[credit.cardID][closing] -= paid
+				remaining -= paid
+			}
+		}
+	}
+
+	cardIDs := make([]uuid.UUID, 0, len(b.cards))
+	for cardID := range b.cards {
+		cardIDs = append(cardIDs, cardID)
+	}
+	sort.Slice(cardIDs, func(i, j int) bool { return cardIDs[i].String() < cardIDs[j].String() })
+
+	for _, cardID := range cardIDs {
+		card := b.cards[cardID]
+		for _, closing := range sortedClosings(b.cardCycles[cardID]) {
+			amount := roundToCents(b.cardCycles[cardID][closing])
+			if amount <= 0 {
+				continue
+			}
+
+			// 已逾期的帳單視為明天繳款
+			due := card.DueDateFor(closing)
+			if !due.After(b.today) {
+				due = b.today.AddDate(0, 0, 1)
+			}
+			if due.After(b.end) {
+				continue
+			}
+
+			payer := b.cardPayers[cardID]
+			b.forecast.Events = append(b.forecast.Events, &models.ForecastEvent{
+				Date:         due,
+				Source:       models.ForecastSourceCreditCardPayment,
+				SourceID:     cardID,
+				Name:         fmt.Sprintf("%s %s", card.IssuingBank, card.CardName),
+				Amount:       -amount,
+				AccountID:    payer,
+				CreditCardID: &card.ID,
+			})
+			b.applyToAccount(payer, due, -amount)
+		}
+	}
+}
+
+// project 逐日計算各帳戶與總流動性的預測餘額，並產生低於門檻的警示
+func (b *forecastBuilder) project(accountDrift []float64, threshold float64) {
+	liquidity := make([]float64, b.days+1)
+
+	for i, account := range b.forecast.Accounts {
+		balance := account.StartingBalance
+		account.LowestBalance = balance
+		account.LowestBalanceDate = b.today
+		var warning *models.ForecastWarning
+
+		for day := 0; day <= b.days; day++ {
+			date := b.today.AddDate(0, 0, day)
+			if day > 0 {
+				balance -= accountDrift[i]
+			}
+			balance += b.deltas[i][day]
+			rounded := roundToCents(balance)
+
+			account.DailyBalances = append(account.DailyBalances, &models.ForecastBalancePoint{Date: date, Balance: rounded})
+			liquidity[day] += rounded
+
+			if rounded < account.LowestBalance {
+				account.LowestBalance = rounded
+				account.LowestBalanceDate = date
+			}
+
+			if rounded < threshold && warning == nil {
+				warning = &models.ForecastWarning{
+					AccountID:   account.AccountID,
+					AccountName: account.AccountName(),
+					Date:        date,
+					Threshold:   threshold,
+				}
+			}
+		}
+
+		account.EndingBalance = account.DailyBalances[len(account.DailyBalances)-1].Balance
+		if warning != nil {
+			warning.LowestBalance = account.LowestBalance
+			warning.LowestBalanceDate = account.LowestBalanceDate
+			b.forecast.Warnings = append(b.forecast.Warnings, warning)
+		}
+	}
+
+	unassigned := 0.0
+	for day := 0; day <= b.days; day++ {
+		unassigned += b.unassigned[day]
+		point := &models.ForecastBalancePoint{
+			Date:    b.today.AddDate(0, 0, day),
+			Balance: roundToCents(liquidity[day] + unassigned),
+		}
+		b.forecast.Liquidity = append(b.forecast.Liquidity, point)
+
+		if day == 0 || point.Balance < b.forecast.LowestLiquidity {
+			b.forecast.LowestLiquidity = point.Balance
+			b.forecast.LowestLiquidityDate = point.Date
+		}
+	}
+
+	b.forecast.StartingLiquidity = b.forecast.Liquidity[0].Balance
+	b.forecast.EndingLiquidity = b.forecast.Liquidity[len(b.forecast.Liquidity)-1].Balance
+	b.forecast.ScheduledInflow = roundToCents(b.forecast.ScheduledInflow)
+	b.forecast.ScheduledOutflow = roundToCents(b.forecast.ScheduledOutflow)
+
+	sort.SliceStable(b.forecast.Warnings, func(i, j int) bool {
+		return b.forecast.Warnings[i].Date.Before(b.forecast.Warnings[j].Date)
+	})
+}
+
+// convertToTWD 將金額換算為 TWD
+func (s *cashFlowForecastService) convertToTWD(amount float64, currency models.Currency, date time.Time) (float64, error) {
+	if currency == "" || currency == models.CurrencyTWD {
+		return amount, nil
+	}
+
+	if s.exchangeRateService == nil {
+		return 0, fmt.Errorf("exchange rate service is not configured for currency: %s", currency)
+	}
+
+	converted, err := s.exchangeRateService.ConvertToTWD(amount, currency, date)
+	if err != nil {
+		return 0, fmt.Errorf("failed to convert %s amount to TWD: %w", currency, err)
+	}
+
+	return roundToCents(converted), nil
+}
+
+// cycleClosing 取得消費日所屬帳單的結帳日（結帳日當天的消費計入當期）
+func cycleClosing(card *models.CreditCard, date time.Time) time.Time {
+	closing := card.LastClosingDate(date)
+	if closing.Before(date) {
+		closing = card.NextClosingDate(closing)
+	}
+	return closing
+}
+
+// sortedClosings 依日期排序結帳日
+func sortedClosings(cycles map[time.Time]float64) []time.Time {
+	closings := make([]time.Time, 0, len(cycles))
+	for closing := range cycles {
+		closings = append(closings, closing)
+	}
+	sort.Slice(closings, func(i, j int) bool { return closings[i].Before(closings[j]) })
+	return closings
+}
@@ -0,0 +1,273 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chienchuanw/asset-manager/internal/models"
+	"github.com/chienchuanw/asset-manager/internal/repository"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// forecastTestMocks 現金流預測測試所需的 mock
+type forecastTestMocks struct {
+	bankAccountRepo  *MockBankAccountRepository
+	creditCardRepo   *MockCreditCardRepository
+	statementRepo    *MockCreditCardStatementRepository
+	subscriptionRepo *MockSubscriptionRepository
+	installmentRepo  *MockInstallmentRepository
+	loanRepo         *MockLoanRepository
+	recurringRepo    *MockRecurringTemplateRepository
+	cashFlowRepo     *MockCashFlowRepository
+}
+
+func newForecastTestService() (*cashFlowForecastService, *forecastTestMocks) {
+	mocks := &forecastTestMocks{
+		bankAccountRepo:  new(MockBankAccountRepository),
+		creditCardRepo:   new(MockCreditCardRepository),
+		statementRepo:    new(MockCreditCardStatementRepository),
+		subscriptionRepo: new(MockSubscriptionRepository),
+		installmentRepo:  new(MockInstallmentRepository),
+		loanRepo:         new(MockLoanRepository),
+		recurringRepo:    new(MockRecurringTemplateRepository),
+		cashFlowRepo:     new(MockCashFlowRepository),
+	}
+
+	service := NewCashFlowForecastService(
+		mocks.bankAccountRepo,
+		mocks.creditCardRepo,
+		mocks.statementRepo,
+		mocks.subscriptionRepo,
+		mocks.installmentRepo,
+		mocks.loanRepo,
+		mocks.recurringRepo,
+		mocks.cashFlowRepo,
+		nil,
+	).(*cashFlowForecastService)
+
+	return service, mocks
+}
+
+func TestCashFlowForecastService_BuildForecast(t *testing.T) {
+	service, mocks := newForecastTestService()
+
+	today := time.Date(2025, 10, 15, 0, 0, 0, 0, time.UTC)
+	mainAccountID := uuid.New()
+	spendingAccountID := uuid.New()
+	cardID := uuid.New()
+	foodCategoryID := uuid.New()
+	streamingCategoryID := uuid.New()
+	salaryCategoryID := uuid.New()
+	monthDay := 5
+
+	accounts := []*models.BankAccount{
+		{ID: mainAccountID, BankName: "台新", AccountNumberLast4: "1234", Currency: models.CurrencyTWD, Balance: 50000},
+		{ID: spendingAccountID, BankName: "國泰", AccountNumberLast4: "5678", Currency: models.CurrencyTWD, Balance: 5000},
+	}
+	card := &models.CreditCard{
+		ID:            cardID,
+		IssuingBank:   "玉山",
+		CardName:      "Pi 卡",
+		BillingDay:    5,
+		PaymentDueDay: 25,
+		UsedCredit:    8000,
+	}
+	statements := []*models.CreditCardStatement{
+		{
+			CreditCardID:   cardID,
+			ClosingDate:    time.Date(2025, 10, 5, 0, 0, 0, 0, time.UTC),
+			DueDate:        time.Date(2025, 10, 25, 0, 0, 0, 0, time.UTC),
+			ClosingBalance: 6000,
+		},
+	}
+	bankSource := models.SourceTypeBankAccount
+	cardTarget := models.SourceTypeCreditCard
+	cardPayments := []*models.CashFlow{
+		{
+			Type:       models.CashFlowTypeTransferOut,
+			Amount:     5000,
+			SourceType: &bankSource,
+			SourceID:   &mainAccountID,
+			TargetType: &cardTarget,
+			TargetID:   &cardID,
+		},
+	}
+	subscriptions := []*models.Subscription{
+		{
+			ID:            uuid.New(),
+			Name:          "Netflix",
+			Amount:        390,
+			Currency:      models.CurrencyTWD,
+			BillingCycle:  models.BillingCycleMonthly,
+			BillingDay:    20,
+			CategoryID:    streamingCategoryID,
+			PaymentMethod: models.PaymentMethodBankAccount,
+			AccountID:     &spendingAccountID,
+			StartDate:     time.Date(2025, 1, 20, 0, 0, 0, 0, time.UTC),
+			Status:        models.SubscriptionStatusActive,
+		},
+	}
+	templates := []*models.RecurringTemplate{
+		{
+			ID:                    uuid.New(),
+			Name:                  "薪資",
+			Type:                  models.CashFlowTypeIncome,
+			CategoryID:            salaryCategoryID,
+			Amount:                60000,
+			Currency:              models.CurrencyTWD,
+			PaymentMethod:         models.PaymentMethodBankAccount,
+			AccountID:             &mainAccountID,
+			Frequency:             models.RecurrenceMonthly,
+			Interval:              1,
+			ByMonthDay:            &monthDay,
+			BusinessDayAdjustment: models.BusinessDayAdjustmentNone,
+			StartDate:             time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC),
+			Status:                models.RecurringTemplateStatusActive,
+		},
+	}
+	// 過去三個月：餐飲 9000 由國泰帳戶支付，串流分類已有訂閱排程因此不列入平均
+	history := []*models.CashFlow{
+		{Type: models.CashFlowTypeExpense, Date: time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC), CategoryID: foodCategoryID, Amount: 9000, Currency: models.CurrencyTWD,
+			SourceType: &bankSource, SourceID: &spendingAccountID, Category: &models.CashFlowCategory{ID: foodCategoryID, Name: "餐飲"}},
+		{Type: models.CashFlowTypeExpense, Date: time.Date(2025, 9, 20, 0, 0, 0, 0, time.UTC), CategoryID: streamingCategoryID, Amount: 390, Currency: models.CurrencyTWD,
+			SourceType: &bankSource, SourceID: &spendingAccountID},
+	}
+
+	// 設定 mock 期望
+	mocks.bankAccountRepo.On("GetAll", (*models.Currency)(nil)).Return(accounts, nil)
+	mocks.creditCardRepo.On("GetAll").Return([]*models.CreditCard{card}, nil)
+	mocks.statementRepo.On("GetByCreditCardID", cardID).Return(statements, nil)
+	mocks.cashFlowRepo.On("GetAll", mock.MatchedBy(func(filters repository.CashFlowFilters) bool {
+		return filters.CreditCardID != nil
+	})).Return(cardPayments, nil)
+	mocks.cashFlowRepo.On("GetAll", mock.MatchedBy(func(filters repository.CashFlowFilters) bool {
+		return filters.CreditCardID == nil && filters.StartDate != nil
+	})).Return(history, nil)
+	mocks.subscriptionRepo.On("List", mock.AnythingOfType("repository.SubscriptionFilters")).Return(subscriptions, nil)
+	mocks.subscriptionRepo.On("GetPriceChanges", subscriptions[0].ID).Return([]*models.SubscriptionPriceChange{}, nil)
+	mocks.installmentRepo.On("List", mock.AnythingOfType("repository.InstallmentFilters")).Return([]*models.Installment{}, nil)
+	mocks.loanRepo.On("List", mock.AnythingOfType("repository.LoanFilters")).Return([]*models.Loan{}, nil)
+	mocks.recurringRepo.On("List", mock.AnythingOfType("repository.RecurringTemplateFilters")).Return(templates, nil)
+	mocks.recurringRepo.On("GetAmountChanges", templates[0].ID).Return([]*models.RecurringAmountChange{}, nil)
+
+	// 執行測試
+	forecast, err := service.buildForecast(today, 1, 2000)
+
+	// 驗證結果
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 11, 15, 0, 0, 0, 0, time.UTC), forecast.EndDate)
+	assert.Len(t, forecast.Liquidity, 32)
+
+	// 事件：訂閱扣款、信用卡帳單繳款、薪資入帳（本期未出帳金額於預測期間後才到期）
+	require.Len(t, forecast.Events, 3)
+	assert.Equal(t, models.ForecastSourceSubscription, forecast.Events[0].Source)
+	assert.Equal(t, -390.0, forecast.Events[0].Amount)
+	assert.Equal(t, models.ForecastSourceCreditCardPayment, forecast.Events[1].Source)
+	assert.Equal(t, time.Date(2025, 10, 25, 0, 0, 0, 0, time.UTC), forecast.Events[1].Date)
+	assert.Equal(t, -6000.0, forecast.Events[1].Amount)
+	require.NotNil(t, forecast.Events[1].AccountID)
+	assert.Equal(t, mainAccountID, *forecast.Events[1].AccountID)
+	assert.Equal(t, models.ForecastSourceRecurring, forecast.Events[2].Source)
+	assert.Equal(t, 60000.0, forecast.Events[2].Amount)
+
+	assert.Equal(t, 60000.0, forecast.ScheduledInflow)
+	assert.Equal(t, 6390.0, forecast.ScheduledOutflow)
+
+	// 歷史平均只計入非排程分類
+	require.Len(t, forecast.CategorySpending, 1)
+	assert.Equal(t, "餐飲", forecast.CategorySpending[0].CategoryName)
+	assert.Equal(t, 3000.0, forecast.CategorySpending[0].MonthlyAverage)
+
+	require.Len(t, forecast.Accounts, 2)
+	main := forecast.Accounts[0]
+	assert.Equal(t, 104000.0, main.EndingBalance)
+	assert.Equal(t, 44000.0, main.LowestBalance)
+
+	dailySpend := 9000.0 / 92
+	spending := forecast.Accounts[1]
+	assert.InDelta(t, 5000-390-dailySpend*31, spending.EndingBalance, 0.05)
+	assert.InDelta(t, forecast.EndingLiquidity, main.EndingBalance+spending.EndingBalance, 0.01)
+
+	// 國泰帳戶低於門檻
+	require.Len(t, forecast.Warnings, 1)
+	assert.Equal(t, spendingAccountID, forecast.Warnings[0].AccountID)
+	assert.Equal(t, "國泰 (5678)", forecast.Warnings[0].AccountName)
+	assert.Equal(t, 2000.0, forecast.Warnings[0].Threshold)
+}
+
+func TestCashFlowForecastService_CreditCardCharges(t *testing.T) {
+	service, mocks := newForecastTestService()
+
+	today := time.Date(2025, 10, 15, 0, 0, 0, 0, time.UTC)
+	cardID := uuid.New()
+	card := &models.CreditCard{
+		ID:            cardID,
+		IssuingBank:   "國泰",
+		CardName:      "CUBE",
+		BillingDay:    20,
+		PaymentDueDay: 5,
+	}
+	installment := &models.Installment{
+		ID:                uuid.New(),
+		Name:              "iPhone",
+		TotalAmount:       36000,
+		InstallmentCount:  12,
+		InstallmentAmount: 3000,
+		PaidCount:         3,
+		BillingDay:        18,
+		CategoryID:        uuid.New(),
+		PaymentMethod:     models.PaymentMethodCreditCard,
+		AccountID:         &cardID,
+		StartDate:         time.Date(2025, 7, 18, 0, 0, 0, 0, time.UTC),
+		Status:            models.InstallmentStatusActive,
+	}
+
+	// 設定 mock 期望
+	mocks.bankAccountRepo.On("GetAll", (*models.Currency)(nil)).Return([]*models.BankAccount{}, nil)
+	mocks.creditCardRepo.On("GetAll").Return([]*models.CreditCard{card}, nil)
+	mocks.statementRepo.On("GetByCreditCardID", cardID).Return([]*models.CreditCardStatement{}, nil)
+	mocks.cashFlowRepo.On("GetAll", mock.AnythingOfType("repository.CashFlowFilters")).Return([]*models.CashFlow{}, nil)
+	mocks.subscriptionRepo.On("List", mock.AnythingOfType("repository.SubscriptionFilters")).Return([]*models.Subscription{}, nil)
+	mocks.installmentRepo.On("List", mock.AnythingOfType("repository.InstallmentFilters")).Return([]*models.Installment{installment}, nil)
+	mocks.installmentRepo.On("GetPrepayments", installment.ID).Return([]*models.InstallmentPrepayment{}, nil)
+	mocks.loanRepo.On("List", mock.AnythingOfType("repository.LoanFilters")).Return([]*models.Loan{}, nil)
+	mocks.recurringRepo.On("List", mock.AnythingOfType("repository.RecurringTemplateFilters")).Return([]*models.RecurringTemplate{}, nil)
+
+	// 執行測試
+	forecast, err := service.buildForecast(today, 2, 0)
+
+	// 驗證結果：10/18、11/18 分期刷卡，分別於 11/5、12/5 繳款（找不到繳款帳戶時僅計入總流動性）
+	require.NoError(t, err)
+	require.Len(t, forecast.Events, 4)
+	assert.Equal(t, time.Date(2025, 10, 18, 0, 0, 0, 0, time.UTC), forecast.Events[0].Date)
+	assert.Equal(t, &cardID, forecast.Events[0].CreditCardID)
+	assert.Nil(t, forecast.Events[0].AccountID)
+
+	payments := []*models.ForecastEvent{}
+	for _, event := range forecast.Events {
+		if event.Source == models.ForecastSourceCreditCardPayment {
+			payments = append(payments, event)
+		}
+	}
+	require.Len(t, payments, 2)
+	assert.Equal(t, time.Date(2025, 11, 5, 0, 0, 0, 0, time.UTC), payments[0].Date)
+	assert.Equal(t, -3000.0, payments[0].Amount)
+	assert.Equal(t, time.Date(2025, 12, 5, 0, 0, 0, 0, time.UTC), payments[1].Date)
+
+	assert.Equal(t, 0.0, forecast.StartingLiquidity)
+	assert.Equal(t, -6000.0, forecast.EndingLiquidity)
+	assert.Equal(t, 6000.0, forecast.ScheduledOutflow)
+}
+
+func TestCashFlowForecastService_GetForecast_InvalidMonths(t *testing.T) {
+	service, _ := newForecastTestService()
+
+	forecast, err := service.GetForecast(13, 0)
+
+	assert.Error(t, err)
+	assert.Nil(t, forecast)
+	assert.Contains(t, err.Error(), "months must be between 1 and 12")
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"testing"
+	"time"
 
 	"github.com/chienchuanw/asset-manager/internal/models"
 	"github.com/stretchr/testify/assert"
@@ -252,3 +253,40 @@ func TestFormatYearlyCashFlowReport(t *testing.T) {
 	assert.Contains(t, message.Content, "與去年")
 }
 
+
+// TestFormatCashFlowForecast 測試現金流預測摘要格式化
+func TestFormatCashFlowForecast(t *testing.T) {
+	service := NewDiscordService()
+
+	start := time.Date(2025, 10, 13, 0, 0, 0, 0, time.UTC)
+	forecast := &models.CashFlowForecast{
+		StartDate:           start,
+		EndDate:             start.AddDate(0, 3, 0),
+		Months:              3,
+		Threshold:           10000,
+		StartingLiquidity:   150000,
+		EndingLiquidity:     180000,
+		LowestLiquidity:     120000,
+		LowestLiquidityDate: time.Date(2025, 10, 25, 0, 0, 0, 0, time.UTC),
+		Accounts: []*models.AccountForecast{
+			{BankName: "國泰", AccountNumberLast4: "5678", StartingBalance: 20000, EndingBalance: 8000, LowestBalance: 6500},
+		},
+		Events: []*models.ForecastEvent{
+			{Date: time.Date(2025, 10, 15, 0, 0, 0, 0, time.UTC), Name: "Netflix", Amount: -390},
+			{Date: time.Date(2025, 11, 5, 0, 0, 0, 0, time.UTC), Name: "薪資", Amount: 60000},
+		},
+		Warnings: []*models.ForecastWarning{
+			{AccountName: "國泰 (5678)", Date: time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC), LowestBalance: 6500, LowestBalanceDate: time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC), Threshold: 10000},
+		},
+	}
+
+	message := service.FormatCashFlowForecast(forecast)
+	assert.NotNil(t, message)
+	assert.Contains(t, message.Content, "未來 3 個月現金流預測")
+	assert.Contains(t, message.Content, "150,000")
+	assert.Contains(t, message.Content, "國泰 (5678)")
+	assert.Contains(t, message.Content, "12/01 起低於門檻")
+	assert.Contains(t, message.Content, "Netflix")
+	// 超過 7 天的排程不列入
+	assert.NotContains(t, message.Content, "薪資")
+}
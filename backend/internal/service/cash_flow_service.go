@@ -1,6 +1,7 @@
 package service
 
 import (
+	"database/sql"
 	"fmt"
 	"time"
 
@@ -12,10 +13,14 @@ import (
 // CashFlowService 現金流記錄業務邏輯介面
 type CashFlowService interface {
 	CreateCashFlow(input *models.CreateCashFlowInput) (*models.CashFlow, error)
+	// CreateCashFlowTx 在指定的資料庫交易中建立現金流記錄並更新帳戶餘額
+	CreateCashFlowTx(tx *sql.Tx, input *models.CreateCashFlowInput) (*models.CashFlow, error)
 	GetCashFlow(id uuid.UUID) (*models.CashFlow, error)
 	ListCashFlows(filters repository.CashFlowFilters) ([]*models.CashFlow, error)
 	UpdateCashFlow(id uuid.UUID, input *models.UpdateCashFlowInput) (*models.CashFlow, error)
 	DeleteCashFlow(id uuid.UUID) error
+	// DeleteCashFlowTx 在指定的資料庫交易中刪除現金流記錄並回復帳戶餘額
+	DeleteCashFlowTx(tx *sql.Tx, id uuid.UUID) error
 	GetSummary(startDate, endDate time.Time) (*repository.CashFlowSummary, error)
 	GetMonthlySummaryWithComparison(year, month int) (*models.MonthlyCashFlowSummary, error)
 	GetYearlySummaryWithComparison(year int) (*models.YearlyCashFlowSummary, error)
@@ -46,6 +51,18 @@ func NewCashFlowService(
 
 // CreateCashFlow 建立新的現金流記錄
 func (s *cashFlowService) CreateCashFlow(input *models.CreateCashFlowInput) (*models.CashFlow, error) {
+	return s.createCashFlow(nil, input)
+}
+
+// CreateCashFlowTx 在指定的資料庫交易中建立新的現金流記錄
+// 失敗時由呼叫端回滾交易，餘額變動會一併撤銷
+func (s *cashFlowService) CreateCashFlowTx(tx *sql.Tx, input *models.CreateCashFlowInput) (*models.CashFlow, error) {
+	return s.createCashFlow(tx, input)
+}
+
+// createCashFlow 建立現金流記錄並更新餘額
+// tx 為 nil 時直接寫入，失敗時手動回復已變動的餘額
+func (s *cashFlowService) createCashFlow(tx *sql.Tx, input *models.CreateCashFlowInput) (*models.CashFlow, error) {
 	// 驗證現金流類型
 	if !input.Type.Validate() {
 		return nil, fmt.Errorf("invalid cash flow type: %s", input.Type)
@@ -88,7 +105,7 @@ func (s *cashFlowService) CreateCashFlow(input *models.CreateCashFlowInput) (*mo
 
 	// 驗證並處理付款方式 (source)
 	if input.SourceType != nil && input.SourceID != nil {
-		err := s.validateAndUpdateBalance(input.Type, *input.SourceType, *input.SourceID, input.Amount)
+		err := s.validateAndUpdateBalance(tx, input.Type, *input.SourceType, *input.SourceID, input.Amount)
 		if err != nil {
 			return nil, fmt.Errorf("failed to update balance: %w", err)
 		}
@@ -104,10 +121,10 @@ func (s *cashFlowService) CreateCashFlow(input *models.CreateCashFlowInput) (*mo
 			}
 		} else if input.TargetID != nil {
 			// 其他類型的轉帳：需要驗證並更新目標帳戶餘額
-			err := s.validateAndUpdateTarget(*input.TargetType, *input.TargetID, input.Amount)
+			err := s.validateAndUpdateTarget(tx, *input.TargetType, *input.TargetID, input.Amount)
 			if err != nil {
 				// 如果目標更新失敗，需要回復 source 的餘額變動
-				if input.SourceType != nil && input.SourceID != nil {
+				if tx == nil && input.SourceType != nil && input.SourceID != nil {
 					s.revertBalanceUpdate(input.Type, *input.SourceType, *input.SourceID, input.Amount)
 				}
 				return nil, fmt.Errorf("failed to update target balance: %w", err)
@@ -119,8 +136,17 @@ func (s *cashFlowService) CreateCashFlow(input *models.CreateCashFlowInput) (*mo
 	}
 
 	// 呼叫 repository 建立現金流記錄
-	cashFlow, err := s.repo.Create(input)
+	var cashFlow *models.CashFlow
+	if tx != nil {
+		cashFlow, err = s.repo.CreateTx(tx, input)
+	} else {
+		cashFlow, err = s.repo.Create(input)
+	}
 	if err != nil {
+		// 在交易中時由回滾撤銷餘額變動
+		if tx != nil {
+			return nil, fmt.Errorf("failed to create cash flow: %w", err)
+		}
 		// 如果建立失敗，需要回復所有餘額變動
 		if input.SourceType != nil && input.SourceID != nil {
 			s.revertBalanceUpdate(input.Type, *input.SourceType, *input.SourceID, input.Amount)
@@ -221,7 +247,7 @@ func (s *cashFlowService) DeleteCashFlow(id uuid.UUID) error {
 
 	// 回復餘額變動（在刪除記錄之前）
 	if cashFlow.SourceType != nil && cashFlow.SourceID != nil {
-		err = s.revertBalanceUpdateWithError(cashFlow.Type, *cashFlow.SourceType, *cashFlow.SourceID, cashFlow.Amount)
+		err = s.revertBalanceUpdateWithError(nil, cashFlow.Type, *cashFlow.SourceType, *cashFlow.SourceID, cashFlow.Amount)
 		if err != nil {
 			return fmt.Errorf("failed to revert balance update: %w", err)
 		}
@@ -232,8 +258,30 @@ func (s *cashFlowService) DeleteCashFlow(id uuid.UUID) error {
 	if err != nil {
 		// 如果刪除失敗，需要重新套用餘額變動
 		if cashFlow.SourceType != nil && cashFlow.SourceID != nil {
-			s.validateAndUpdateBalance(cashFlow.Type, *cashFlow.SourceType, *cashFlow.SourceID, cashFlow.Amount)
+			s.validateAndUpdateBalance(nil, cashFlow.Type, *cashFlow.SourceType, *cashFlow.SourceID, cashFlow.Amount)
+		}
+		return fmt.Errorf("failed to delete cash flow: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteCashFlowTx 在指定的資料庫交易中刪除現金流記錄並回復帳戶餘額
+// 失敗時由呼叫端回滾交易，餘額變動會一併撤銷
+func (s *cashFlowService) DeleteCashFlowTx(tx *sql.Tx, id uuid.UUID) error {
+	cashFlow, err := s.repo.GetByID(id)
+	if err != nil {
+		return fmt.Errorf("failed to get cash flow for deletion: %w", err)
+	}
+
+	if cashFlow.SourceType != nil && cashFlow.SourceID != nil {
+		err = s.revertBalanceUpdateWithError(tx, cashFlow.Type, *cashFlow.SourceType, *cashFlow.SourceID, cashFlow.Amount)
+		if err != nil {
+			return fmt.Errorf("failed to revert balance update: %w", err)
 		}
+	}
+
+	if err := s.repo.DeleteTx(tx, id); err != nil {
 		return fmt.Errorf("failed to delete cash flow: %w", err)
 	}
 
@@ -250,8 +298,8 @@ func (s *cashFlowService) GetSummary(startDate, endDate time.Time) (*repository.
 	return s.repo.GetSummary(startDate, endDate)
 }
 
-// validateAndUpdateBalance 驗證付款方式並更新對應的餘額
-func (s *cashFlowService) validateAndUpdateBalance(cashFlowType models.CashFlowType, sourceType models.SourceType, sourceID uuid.UUID, amount float64) error {
+// validateAndUpdateBalance 驗證付款方式並更新對應的餘額（tx 不為 nil 時在該交易中更新）
+func (s *cashFlowService) validateAndUpdateBalance(tx *sql.Tx, cashFlowType models.CashFlowType, sourceType models.SourceType, sourceID uuid.UUID, amount float64) error {
 	// 驗證 SourceType 是否有效
 	if !sourceType.Validate() {
 		return fmt.Errorf("invalid source type: %s", sourceType)
@@ -260,9 +308,9 @@ func (s *cashFlowService) validateAndUpdateBalance(cashFlowType models.CashFlowT
 	// 根據 SourceType 處理不同的付款方式
 	switch sourceType {
 	case models.SourceTypeBankAccount:
-		return s.updateBankAccountBalance(cashFlowType, sourceID, amount)
+		return s.updateBankAccountBalance(tx, cashFlowType, sourceID, amount)
 	case models.SourceTypeCreditCard:
-		return s.updateCreditCardBalance(cashFlowType, sourceID, amount)
+		return s.updateCreditCardBalance(tx, cashFlowType, sourceID, amount)
 	case models.SourceTypeManual:
 		// 現金交易，不需要更新任何餘額
 		return nil
@@ -277,29 +325,47 @@ func (s *cashFlowService) revertBalanceUpdate(cashFlowType models.CashFlowType,
 	// 回復操作：將原本的金額變動反向操作
 	switch sourceType {
 	case models.SourceTypeBankAccount:
-		s.updateBankAccountBalance(cashFlowType, sourceID, -amount)
+		s.updateBankAccountBalance(nil, cashFlowType, sourceID, -amount)
 	case models.SourceTypeCreditCard:
-		s.updateCreditCardBalance(cashFlowType, sourceID, -amount)
+		s.updateCreditCardBalance(nil, cashFlowType, sourceID, -amount)
 	}
 	// 忽略錯誤，因為這是回復操作
 }
 
-// revertBalanceUpdateWithError 回復餘額變動並返回錯誤（用於刪除操作）
-func (s *cashFlowService) revertBalanceUpdateWithError(cashFlowType models.CashFlowType, sourceType models.SourceType, sourceID uuid.UUID, amount float64) error {
+// revertBalanceUpdateWithError 回復餘額變動並返回錯誤（用於刪除操作，tx 不為 nil 時在該交易中更新）
+func (s *cashFlowService) revertBalanceUpdateWithError(tx *sql.Tx, cashFlowType models.CashFlowType, sourceType models.SourceType, sourceID uuid.UUID, amount float64) error {
 	// 回復操作：將原本的金額變動反向操作
 	switch sourceType {
 	case models.SourceTypeBankAccount:
-		return s.updateBankAccountBalance(cashFlowType, sourceID, -amount)
+		return s.updateBankAccountBalance(tx, cashFlowType, sourceID, -amount)
 	case models.SourceTypeCreditCard:
-		return s.updateCreditCardBalance(cashFlowType, sourceID, -amount)
+		return s.updateCreditCardBalance(tx, cashFlowType, sourceID, -amount)
 	default:
 		return nil // manual 類型不需要回復餘額
 	}
 }
 
+// getBankAccount 取得銀行帳戶；在交易中以 SELECT ... FOR UPDATE 讀取並鎖定，
+// 避免並行交易在餘額檢查之後、更新之前修改餘額
+func (s *cashFlowService) getBankAccount(tx *sql.Tx, accountID uuid.UUID) (*models.BankAccount, error) {
+	if tx != nil {
+		return s.bankAccountRepo.GetByIDForUpdateTx(tx, accountID)
+	}
+	return s.bankAccountRepo.GetByID(accountID)
+}
+
+// getCreditCard 取得信用卡；在交易中以 SELECT ... FOR UPDATE 讀取並鎖定，
+// 避免並行交易在額度檢查之後、更新之前修改已使用額度
+func (s *cashFlowService) getCreditCard(tx *sql.Tx, cardID uuid.UUID) (*models.CreditCard, error) {
+	if tx != nil {
+		return s.creditCardRepo.GetByIDForUpdateTx(tx, cardID)
+	}
+	return s.creditCardRepo.GetByID(cardID)
+}
+
 // updateBankAccountBalance 更新銀行帳戶餘額
-func (s *cashFlowService) updateBankAccountBalance(cashFlowType models.CashFlowType, accountID uuid.UUID, amount float64) error {
-	account, err := s.bankAccountRepo.GetByID(accountID)
+func (s *cashFlowService) updateBankAccountBalance(tx *sql.Tx, cashFlowType models.CashFlowType, accountID uuid.UUID, amount float64) error {
+	account, err := s.getBankAccount(tx, accountID)
 	if err != nil {
 		return fmt.Errorf("bank account not found: %w", err)
 	}
@@ -316,7 +382,11 @@ func (s *cashFlowService) updateBankAccountBalance(cashFlowType models.CashFlowT
 		}
 	}
 
-	_, err = s.bankAccountRepo.UpdateBalance(accountID, balanceChange)
+	if tx != nil {
+		_, err = s.bankAccountRepo.UpdateBalanceTx(tx, accountID, balanceChange)
+	} else {
+		_, err = s.bankAccountRepo.UpdateBalance(accountID, balanceChange)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to update bank account balance: %w", err)
 	}
@@ -325,8 +395,8 @@ func (s *cashFlowService) updateBankAccountBalance(cashFlowType models.CashFlowT
 }
 
 // updateCreditCardBalance 更新信用卡已使用額度
-func (s *cashFlowService) updateCreditCardBalance(cashFlowType models.CashFlowType, cardID uuid.UUID, amount float64) error {
-	card, err := s.creditCardRepo.GetByID(cardID)
+func (s *cashFlowService) updateCreditCardBalance(tx *sql.Tx, cashFlowType models.CashFlowType, cardID uuid.UUID, amount float64) error {
+	card, err := s.getCreditCard(tx, cardID)
 	if err != nil {
 		return fmt.Errorf("credit card not found: %w", err)
 	}
@@ -342,7 +412,11 @@ func (s *cashFlowService) updateCreditCardBalance(cashFlowType models.CashFlowTy
 		}
 	}
 
-	_, err = s.creditCardRepo.UpdateUsedCredit(cardID, usedCreditChange)
+	if tx != nil {
+		_, err = s.creditCardRepo.UpdateUsedCreditTx(tx, cardID, usedCreditChange)
+	} else {
+		_, err = s.creditCardRepo.UpdateUsedCredit(cardID, usedCreditChange)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to update credit card used credit: %w", err)
 	}
@@ -351,11 +425,11 @@ func (s *cashFlowService) updateCreditCardBalance(cashFlowType models.CashFlowTy
 }
 
 // validateAndUpdateTarget 驗證並更新轉帳目標的餘額
-func (s *cashFlowService) validateAndUpdateTarget(targetType models.SourceType, targetID uuid.UUID, amount float64) error {
+func (s *cashFlowService) validateAndUpdateTarget(tx *sql.Tx, targetType models.SourceType, targetID uuid.UUID, amount float64) error {
 	switch targetType {
 	case models.SourceTypeCreditCard:
 		// 驗證信用卡是否存在並取得目前的已使用額度
-		card, err := s.creditCardRepo.GetByID(targetID)
+		card, err := s.getCreditCard(tx, targetID)
 		if err != nil {
 			return fmt.Errorf("credit card not found: %w", err)
 		}
@@ -368,20 +442,28 @@ func (s *cashFlowService) validateAndUpdateTarget(targetType models.SourceType,
 		}
 
 		// 繳款給信用卡 → 減少已使用額度
-		_, err = s.creditCardRepo.UpdateUsedCredit(targetID, -actualDeduction)
+		if tx != nil {
+			_, err = s.creditCardRepo.UpdateUsedCreditTx(tx, targetID, -actualDeduction)
+		} else {
+			_, err = s.creditCardRepo.UpdateUsedCredit(targetID, -actualDeduction)
+		}
 		if err != nil {
 			return fmt.Errorf("failed to update credit card used credit: %w", err)
 		}
 
 	case models.SourceTypeBankAccount:
 		// 驗證銀行帳戶是否存在
-		_, err := s.bankAccountRepo.GetByID(targetID)
+		_, err := s.getBankAccount(tx, targetID)
 		if err != nil {
 			return fmt.Errorf("bank account not found: %w", err)
 		}
 
 		// 轉帳到銀行帳戶 → 增加餘額
-		_, err = s.bankAccountRepo.UpdateBalance(targetID, amount)
+		if tx != nil {
+			_, err = s.bankAccountRepo.UpdateBalanceTx(tx, targetID, amount)
+		} else {
+			_, err = s.bankAccountRepo.UpdateBalance(targetID, amount)
+		}
 		if err != nil {
 			return fmt.Errorf("failed to update bank account balance: %w", err)
 		}
@@ -442,11 +524,11 @@ func (s *cashFlowService) handlePaymentMethodChange(original *models.CashFlow, i
 
 	// 套用新的餘額變動
 	if newSourceType != nil && newSourceID != nil {
-		err := s.validateAndUpdateBalance(original.Type, *newSourceType, *newSourceID, newAmount)
+		err := s.validateAndUpdateBalance(nil, original.Type, *newSourceType, *newSourceID, newAmount)
 		if err != nil {
 			// 如果新的餘額變動失敗，需要回復原本的餘額變動
 			if original.SourceType != nil && original.SourceID != nil {
-				s.validateAndUpdateBalance(original.Type, *original.SourceType, *original.SourceID, original.Amount)
+				s.validateAndUpdateBalance(nil, original.Type, *original.SourceType, *original.SourceID, original.Amount)
 			}
 			return err
 		}
@@ -491,7 +573,7 @@ func (s *cashFlowService) revertPaymentMethodChange(original *models.CashFlow, i
 
 	// 重新套用原本的餘額變動
 	if original.SourceType != nil && original.SourceID != nil {
-		s.validateAndUpdateBalance(original.Type, *original.SourceType, *original.SourceID, original.Amount)
+		s.validateAndUpdateBalance(nil, original.Type, *original.SourceType, *original.SourceID, original.Amount)
 	}
 }
 
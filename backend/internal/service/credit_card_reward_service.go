@@ -0,0 +1,377 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/chienchuanw/asset-manager/internal/models"
+	"github.com/chienchuanw/asset-manager/internal/repository"
+	"github.com/google/uuid"
+)
+
+// defaultRewardRecommendationAmount 未指定金額時用於試算回饋的消費金額
+const defaultRewardRecommendationAmount = 1000.0
+
+// CreditCardRewardService 信用卡回饋業務邏輯介面
+type CreditCardRewardService interface {
+	CreateRule(creditCardID uuid.UUID, input *models.CreateCreditCardRewardRuleInput) (*models.CreditCardRewardRule, error)
+	ListRules(creditCardID uuid.UUID) ([]*models.CreditCardRewardRule, error)
+	UpdateRule(creditCardID, ruleID uuid.UUID, input *models.UpdateCreditCardRewardRuleInput) (*models.CreditCardRewardRule, error)
+	DeleteRule(creditCardID, ruleID uuid.UUID) error
+	// GetRewardReport 取得期間內各結帳週期的回饋統計（期間會擴展至完整週期以正確計算上限）
+	GetRewardReport(creditCardID uuid.UUID, startDate, endDate time.Time) (*models.CreditCardRewardReport, error)
+	// RecommendCard 依分類（與商家）試算各信用卡回饋，並推薦回饋最高的卡片
+	RecommendCard(categoryID uuid.UUID, merchant string, amount float64, date time.Time) (*models.CreditCardRewardRecommendation, error)
+}
+
+// creditCardRewardService 信用卡回饋業務邏輯實作
+type creditCardRewardService struct {
+	repo           repository.CreditCardRewardRuleRepository
+	creditCardRepo repository.CreditCardRepository
+	cashFlowRepo   repository.CashFlowRepository
+	categoryRepo   repository.CategoryRepository
+}
+
+// NewCreditCardRewardService 建立新的信用卡回饋 service
+func NewCreditCardRewardService(
+	repo repository.CreditCardRewardRuleRepository,
+	creditCardRepo repository.CreditCardRepository,
+	cashFlowRepo repository.CashFlowRepository,
+	categoryRepo repository.CategoryRepository,
+) CreditCardRewardService {
+	return &creditCardRewardService{
+		repo:           repo,
+		creditCardRepo: creditCardRepo,
+		cashFlowRepo:   cashFlowRepo,
+		categoryRepo:   categoryRepo,
+	}
+}
+
+// CreateRule 建立回饋規則
+func (s *creditCardRewardService) CreateRule(creditCardID uuid.UUID, input *models.CreateCreditCardRewardRuleInput) (*models.CreditCardRewardRule, error) {
+	if err := input.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid input: %w", err)
+	}
+
+	if _, err := s.creditCardRepo.GetByID(creditCardID); err != nil {
+		return nil, fmt.Errorf("credit card not found: %w", err)
+	}
+
+	if input.CategoryID != nil {
+		if err := s.validateCategory(*input.CategoryID); err != nil {
+			return nil, err
+		}
+	}
+
+	rule, err := s.repo.Create(creditCardID, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create reward rule: %w", err)
+	}
+
+	return rule, nil
+}
+
+// ListRules 取得信用卡的回饋規則
+func (s *creditCardRewardService) ListRules(creditCardID uuid.UUID) ([]*models.CreditCardRewardRule, error) {
+	rules, err := s.repo.GetByCreditCardID(creditCardID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reward rules: %w", err)
+	}
+
+	return rules, nil
+}
+
+// UpdateRule 更新回饋規則
+func (s *creditCardRewardService) UpdateRule(creditCardID, ruleID uuid.UUID, input *models.UpdateCreditCardRewardRuleInput) (*models.CreditCardRewardRule, error) {
+	if err := input.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid input: %w", err)
+	}
+
+	if _, err := s.getRule(creditCardID, ruleID); err != nil {
+		return nil, err
+	}
+
+	if input.CategoryID != nil {
+		if err := s.validateCategory(*input.CategoryID); err != nil {
+			return nil, err
+		}
+	}
+
+	rule, err := s.repo.Update(ruleID, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update reward rule: %w", err)
+	}
+
+	return rule, nil
+}
+
+// DeleteRule 刪除回饋規則
+func (s *creditCardRewardService) DeleteRule(creditCardID, ruleID uuid.UUID) error {
+	if _, err := s.getRule(creditCardID, ruleID); err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(ruleID); err != nil {
+		return fmt.Errorf("failed to delete reward rule: %w", err)
+	}
+
+	return nil
+}
+
+// GetRewardReport 取得信用卡回饋報表
+func (s *creditCardRewardService) GetRewardReport(creditCardID uuid.UUID, startDate, endDate time.Time) (*models.CreditCardRewardReport, error) {
+	if endDate.Before(startDate) {
+		return nil, fmt.Errorf("end date must be after start date")
+	}
+
+	card, err := s.creditCardRepo.GetByID(creditCardID)
+	if err != nil {
+		return nil, fmt.Errorf("credit card not found: %w", err)
+	}
+
+	rules, err := s.repo.GetByCreditCardID(creditCardID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reward rules: %w", err)
+	}
+
+	// 回饋上限以週期計算，因此從起始日所屬週期的第一天開始統計
+	periodStart := creditCardCyclePeriodStart(card, card.CycleClosingDate(startDate))
+	cashFlows, err := s.cashFlowRepo.GetAll(repository.CashFlowFilters{
+		CreditCardID: &creditCardID,
+		StartDate:    &periodStart,
+		EndDate:      &endDate,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cash flows: %w", err)
+	}
+
+	report := &models.CreditCardRewardReport{
+		CreditCardID: creditCardID,
+		StartDate:    periodStart,
+		EndDate:      endDate,
+		Cycles:       accrueCreditCardRewards(card, rules, cashFlows),
+	}
+	for _, cycle := range report.Cycles {
+		report.TotalSpending += cycle.Spending
+		report.EstimatedReward += cycle.EstimatedReward
+		report.EarnedReward += cycle.EarnedReward
+		report.CappedReward += cycle.CappedReward
+	}
+	report.TotalSpending = roundToCents(report.TotalSpending)
+	report.EstimatedReward = roundToCents(report.EstimatedReward)
+	report.EarnedReward = roundToCents(report.EarnedReward)
+	report.CappedReward = roundToCents(report.CappedReward)
+	if report.TotalSpending > 0 {
+		report.EffectiveRate = roundToCents(report.EarnedReward / report.TotalSpending * 100)
+	}
+
+	return report, nil
+}
+
+// RecommendCard 推薦指定分類回饋最高的信用卡
+func (s *creditCardRewardService) RecommendCard(categoryID uuid.UUID, merchant string, amount float64, date time.Time) (*models.CreditCardRewardRecommendation, error) {
+	if err := s.validateCategory(categoryID); err != nil {
+		return nil, err
+	}
+
+	if amount <= 0 {
+		amount = defaultRewardRecommendationAmount
+	}
+
+	cards, err := s.creditCardRepo.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get credit cards: %w", err)
+	}
+
+	allRules, err := s.repo.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reward rules: %w", err)
+	}
+
+	rulesByCard := make(map[uuid.UUID][]*models.CreditCardRewardRule)
+	for _, rule := range allRules {
+		rulesByCard[rule.CreditCardID] = append(rulesByCard[rule.CreditCardID], rule)
+	}
+
+	recommendation := &models.CreditCardRewardRecommendation{
+		CategoryID: categoryID,
+		Merchant:   merchant,
+		Amount:     amount,
+		Options:    []*models.CreditCardRewardOption{},
+	}
+
+	for _, card := range cards {
+		option := &models.CreditCardRewardOption{
+			CreditCardID: card.ID,
+			IssuingBank:  card.IssuingBank,
+			CardName:     card.CardName,
+		}
+
+		rule := models.MatchCreditCardRewardRule(rulesByCard[card.ID], categoryID, merchant)
+		if rule != nil {
+			option.RuleID = &rule.ID
+			option.RuleName = rule.Name
+			option.Rate = rule.Rate
+			option.EstimatedReward = models.CreditCardReward(amount, rule.Rate)
+
+			if rule.CycleCap != nil {
+				remaining, err := s.remainingCycleCap(card, rulesByCard[card.ID], rule, date)
+				if err != nil {
+					return nil, err
+				}
+				option.RemainingCap = &remaining
+				if option.EstimatedReward > remaining {
+					option.EstimatedReward = remaining
+				}
+			}
+		}
+
+		recommendation.Options = append(recommendation.Options, option)
+	}
+
+	sort.SliceStable(recommendation.Options, func(i, j int) bool {
+		if recommendation.Options[i].EstimatedReward != recommendation.Options[j].EstimatedReward {
+			return recommendation.Options[i].EstimatedReward > recommendation.Options[j].EstimatedReward
+		}
+		return recommendation.Options[i].Rate > recommendation.Options[j].Rate
+	})
+
+	if len(recommendation.Options) > 0 && recommendation.Options[0].EstimatedReward > 0 {
+		recommendation.Best = recommendation.Options[0]
+	}
+
+	return recommendation, nil
+}
+
+// remainingCycleCap 計算規則在指定日期所屬週期內剩餘的回饋上限
+func (s *creditCardRewardService) remainingCycleCap(card *models.CreditCard, rules []*models.CreditCardRewardRule, rule *models.CreditCardRewardRule, date time.Time) (float64, error) {
+	periodStart := creditCardCyclePeriodStart(card, card.CycleClosingDate(date))
+	cashFlows, err := s.cashFlowRepo.GetAll(repository.CashFlowFilters{
+		CreditCardID: &card.ID,
+		StartDate:    &periodStart,
+		EndDate:      &date,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get cash flows: %w", err)
+	}
+
+	earned := 0.0
+	for _, cycle := range accrueCreditCardRewards(card, rules, cashFlows) {
+		for _, accrual := range cycle.Accruals {
+			if accrual.RuleID != nil && *accrual.RuleID == rule.ID {
+				earned += accrual.EarnedReward
+			}
+		}
+	}
+
+	remaining := roundToCents(*rule.CycleCap - earned)
+	if remaining < 0 {
+		remaining = 0
+	}
+	return remaining, nil
+}
+
+// getRule 取得並確認回饋規則屬於指定信用卡
+func (s *creditCardRewardService) getRule(creditCardID, ruleID uuid.UUID) (*models.CreditCardRewardRule, error) {
+	rule, err := s.repo.GetByID(ruleID)
+	if err != nil {
+		return nil, err
+	}
+
+	if rule.CreditCardID != creditCardID {
+		return nil, fmt.Errorf("credit card reward rule not found")
+	}
+
+	return rule, nil
+}
+
+// validateCategory 驗證分類存在且為支出分類
+func (s *creditCardRewardService) validateCategory(categoryID uuid.UUID) error {
+	category, err := s.categoryRepo.GetByID(categoryID)
+	if err != nil {
+		return fmt.Errorf("category not found: %w", err)
+	}
+
+	if category.Type != models.CashFlowTypeExpense {
+		return fmt.Errorf("reward rules only apply to expense categories")
+	}
+
+	return nil
+}
+
+// creditCardCyclePeriodStart 取得結帳週期的起日（上期結帳日隔天）
+func creditCardCyclePeriodStart(card *models.CreditCard, closingDate time.Time) time.Time {
+	return card.LastClosingDate(closingDate.AddDate(0, 0, -1)).AddDate(0, 0, 1)
+}
+
+// accrueCreditCardRewards 依結帳週期累計信用卡消費的回饋（由舊到新）
+// 每期依消費日期順序套用規則，超過規則上限的部分記為損失的回饋
+func accrueCreditCardRewards(card *models.CreditCard, rules []*models.CreditCardRewardRule, cashFlows []*models.CashFlow) []*models.CreditCardRewardCycle {
+	expenses := []*models.CashFlow{}
+	for _, cashFlow := range cashFlows {
+		if cashFlow.Type == models.CashFlowTypeExpense && cashFlow.SourceType != nil &&
+			*cashFlow.SourceType == models.SourceTypeCreditCard && cashFlow.SourceID != nil && *cashFlow.SourceID == card.ID {
+			expenses = append(expenses, cashFlow)
+		}
+	}
+	sort.SliceStable(expenses, func(i, j int) bool {
+		if !expenses[i].Date.Equal(expenses[j].Date) {
+			return expenses[i].Date.Before(expenses[j].Date)
+		}
+		return expenses[i].CreatedAt.Before(expenses[j].CreatedAt)
+	})
+
+	cycles := []*models.CreditCardRewardCycle{}
+	var cycle *models.CreditCardRewardCycle
+	var earnedByRule map[uuid.UUID]float64
+
+	for _, expense := range expenses {
+		closing := card.CycleClosingDate(expense.Date)
+		if cycle == nil || !cycle.ClosingDate.Equal(closing) {
+			cycle = &models.CreditCardRewardCycle{
+				PeriodStart: creditCardCyclePeriodStart(card, closing),
+				ClosingDate: closing,
+				Accruals:    []*models.CreditCardRewardAccrual{},
+			}
+			cycles = append(cycles, cycle)
+			earnedByRule = make(map[uuid.UUID]float64)
+		}
+
+		accrual := &models.CreditCardRewardAccrual{
+			CashFlowID:  expense.ID,
+			Date:        expense.Date,
+			Description: expense.Description,
+			CategoryID:  expense.CategoryID,
+			Amount:      expense.Amount,
+		}
+
+		if rule := models.MatchCreditCardRewardRule(rules, expense.CategoryID, expense.Description); rule != nil {
+			accrual.RuleID = &rule.ID
+			accrual.RuleName = rule.Name
+			accrual.Rate = rule.Rate
+			accrual.EstimatedReward = models.CreditCardReward(expense.Amount, rule.Rate)
+			accrual.EarnedReward = accrual.EstimatedReward
+
+			if rule.CycleCap != nil {
+				remaining := roundToCents(*rule.CycleCap - earnedByRule[rule.ID])
+				if remaining < 0 {
+					remaining = 0
+				}
+				if accrual.EarnedReward > remaining {
+					accrual.EarnedReward = remaining
+				}
+			}
+			accrual.CappedReward = roundToCents(accrual.EstimatedReward - accrual.EarnedReward)
+			earnedByRule[rule.ID] += accrual.EarnedReward
+		}
+
+		cycle.Spending = roundToCents(cycle.Spending + accrual.Amount)
+		cycle.EstimatedReward = roundToCents(cycle.EstimatedReward + accrual.EstimatedReward)
+		cycle.EarnedReward = roundToCents(cycle.EarnedReward + accrual.EarnedReward)
+		cycle.CappedReward = roundToCents(cycle.CappedReward + accrual.CappedReward)
+		cycle.Accruals = append(cycle.Accruals, accrual)
+	}
+
+	return cycles
+}
@@ -0,0 +1,224 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chienchuanw/asset-manager/internal/models"
+	"github.com/chienchuanw/asset-manager/internal/repository"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockCreditCardRewardRuleRepository 信用卡回饋規則 repository 的 mock
+type MockCreditCardRewardRuleRepository struct {
+	mock.Mock
+}
+
+func (m *MockCreditCardRewardRuleRepository) Create(creditCardID uuid.UUID, input *models.CreateCreditCardRewardRuleInput) (*models.CreditCardRewardRule, error) {
+	args := m.Called(creditCardID, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CreditCardRewardRule), args.Error(1)
+}
+
+func (m *MockCreditCardRewardRuleRepository) GetByID(id uuid.UUID) (*models.CreditCardRewardRule, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CreditCardRewardRule), args.Error(1)
+}
+
+func (m *MockCreditCardRewardRuleRepository) GetByCreditCardID(creditCardID uuid.UUID) ([]*models.CreditCardRewardRule, error) {
+	args := m.Called(creditCardID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.CreditCardRewardRule), args.Error(1)
+}
+
+func (m *MockCreditCardRewardRuleRepository) GetAll() ([]*models.CreditCardRewardRule, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.CreditCardRewardRule), args.Error(1)
+}
+
+func (m *MockCreditCardRewardRuleRepository) Update(id uuid.UUID, input *models.UpdateCreditCardRewardRuleInput) (*models.CreditCardRewardRule, error) {
+	args := m.Called(id, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CreditCardRewardRule), args.Error(1)
+}
+
+func (m *MockCreditCardRewardRuleRepository) Delete(id uuid.UUID) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+// rewardTestFixture 回饋測試資料：帳單日 5 日，一般 1%，餐飲 5%（每期上限 100）
+type rewardTestFixture struct {
+	card        *models.CreditCard
+	rules       []*models.CreditCardRewardRule
+	diningID    uuid.UUID
+	transportID uuid.UUID
+	cashFlows   []*models.CashFlow
+}
+
+func newRewardTestFixture() *rewardTestFixture {
+	cardID := uuid.New()
+	diningID := uuid.New()
+	transportID := uuid.New()
+	creditCard := models.SourceTypeCreditCard
+	cycleCap := 100.0
+
+	expense := func(date time.Time, categoryID uuid.UUID, amount float64, description string) *models.CashFlow {
+		return &models.CashFlow{
+			ID: uuid.New(), Date: date, Type: models.CashFlowTypeExpense,
+			CategoryID: categoryID, Amount: amount, Description: description,
+			SourceType: &creditCard, SourceID: &cardID,
+		}
+	}
+
+	return &rewardTestFixture{
+		card: &models.CreditCard{ID: cardID, IssuingBank: "玉山銀行", CardName: "Pi 拍錢包卡", BillingDay: 5, PaymentDueDay: 25},
+		rules: []*models.CreditCardRewardRule{
+			{ID: uuid.New(), CreditCardID: cardID, Name: "一般消費", Rate: 1, IsActive: true},
+			{ID: uuid.New(), CreditCardID: cardID, Name: "餐飲加碼", CategoryID: &diningID, Rate: 5, CycleCap: &cycleCap, IsActive: true},
+		},
+		diningID:    diningID,
+		transportID: transportID,
+		// 依日期由新到舊（與 repository 回傳順序一致）
+		cashFlows: []*models.CashFlow{
+			expense(time.Date(2025, 2, 10, 0, 0, 0, 0, time.UTC), diningID, 400, "早午餐"),
+			expense(time.Date(2025, 1, 25, 0, 0, 0, 0, time.UTC), transportID, 2000, "高鐵"),
+			expense(time.Date(2025, 1, 20, 0, 0, 0, 0, time.UTC), diningID, 1000, "聚餐"),
+			expense(time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC), diningID, 1500, "尾牙"),
+		},
+	}
+}
+
+func TestAccrueCreditCardRewards(t *testing.T) {
+	fixture := newRewardTestFixture()
+
+	cycles := accrueCreditCardRewards(fixture.card, fixture.rules, fixture.cashFlows)
+
+	assert.Len(t, cycles, 2)
+
+	first := cycles[0]
+	assert.Equal(t, time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC), first.PeriodStart)
+	assert.Equal(t, time.Date(2025, 2, 5, 0, 0, 0, 0, time.UTC), first.ClosingDate)
+	assert.Equal(t, 4500.0, first.Spending)
+	assert.Equal(t, 145.0, first.EstimatedReward)
+	assert.Equal(t, 120.0, first.EarnedReward)
+	assert.Equal(t, 25.0, first.CappedReward)
+	assert.Len(t, first.Accruals, 3)
+	assert.Equal(t, 75.0, first.Accruals[0].EarnedReward)
+	assert.Equal(t, 25.0, first.Accruals[1].EarnedReward)
+	assert.Equal(t, 25.0, first.Accruals[1].CappedReward)
+	assert.Equal(t, "一般消費", first.Accruals[2].RuleName)
+
+	// 新週期上限重新計算
+	second := cycles[1]
+	assert.Equal(t, 400.0, second.Spending)
+	assert.Equal(t, 20.0, second.EarnedReward)
+	assert.Equal(t, 0.0, second.CappedReward)
+}
+
+func TestCreditCardRewardService_GetRewardReport(t *testing.T) {
+	fixture := newRewardTestFixture()
+	cardID := fixture.card.ID
+	startDate := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC)
+	periodStart := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+
+	mockRepo := new(MockCreditCardRewardRuleRepository)
+	mockCreditCardRepo := new(MockCreditCardRepository)
+	mockCashFlowRepo := new(MockCashFlowRepository)
+	service := NewCreditCardRewardService(mockRepo, mockCreditCardRepo, mockCashFlowRepo, nil)
+
+	// 設定 mock 期望：起始日擴展至所屬週期第一天
+	mockCreditCardRepo.On("GetByID", cardID).Return(fixture.card, nil)
+	mockRepo.On("GetByCreditCardID", cardID).Return(fixture.rules, nil)
+	mockCashFlowRepo.On("GetAll", repository.CashFlowFilters{
+		CreditCardID: &cardID,
+		StartDate:    &periodStart,
+		EndDate:      &endDate,
+	}).Return(fixture.cashFlows, nil)
+
+	// 執行測試
+	report, err := service.GetRewardReport(cardID, startDate, endDate)
+
+	// 驗證結果
+	assert.NoError(t, err)
+	assert.Equal(t, periodStart, report.StartDate)
+	assert.Equal(t, 4900.0, report.TotalSpending)
+	assert.Equal(t, 140.0, report.EarnedReward)
+	assert.Equal(t, 25.0, report.CappedReward)
+	assert.Equal(t, 2.86, report.EffectiveRate)
+	mockCashFlowRepo.AssertExpectations(t)
+}
+
+func TestCreditCardRewardService_RecommendCard(t *testing.T) {
+	fixture := newRewardTestFixture()
+	cardID := fixture.card.ID
+	date := time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)
+	periodStart := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+
+	// 另一張卡：餐飲 3% 無上限
+	otherCard := &models.CreditCard{ID: uuid.New(), IssuingBank: "台新銀行", CardName: "@GoGo 卡", BillingDay: 15}
+	otherRule := &models.CreditCardRewardRule{
+		ID: uuid.New(), CreditCardID: otherCard.ID, Name: "餐飲", CategoryID: &fixture.diningID, Rate: 3, IsActive: true,
+	}
+
+	mockRepo := new(MockCreditCardRewardRuleRepository)
+	mockCreditCardRepo := new(MockCreditCardRepository)
+	mockCashFlowRepo := new(MockCashFlowRepository)
+	mockCategoryRepo := new(MockCategoryRepository)
+	service := NewCreditCardRewardService(mockRepo, mockCreditCardRepo, mockCashFlowRepo, mockCategoryRepo)
+
+	// 設定 mock 期望：第一張卡本期餐飲回饋已達上限
+	mockCategoryRepo.On("GetByID", fixture.diningID).Return(&models.CashFlowCategory{ID: fixture.diningID, Type: models.CashFlowTypeExpense}, nil)
+	mockCreditCardRepo.On("GetAll").Return([]*models.CreditCard{fixture.card, otherCard}, nil)
+	mockRepo.On("GetAll").Return(append(fixture.rules, otherRule), nil)
+	mockCashFlowRepo.On("GetAll", repository.CashFlowFilters{
+		CreditCardID: &cardID,
+		StartDate:    &periodStart,
+		EndDate:      &date,
+	}).Return(fixture.cashFlows[1:], nil)
+
+	// 執行測試
+	recommendation, err := service.RecommendCard(fixture.diningID, "", 1000, date)
+
+	// 驗證結果
+	assert.NoError(t, err)
+	assert.Len(t, recommendation.Options, 2)
+	assert.Equal(t, otherCard.ID, recommendation.Best.CreditCardID)
+	assert.Equal(t, 30.0, recommendation.Best.EstimatedReward)
+	assert.Equal(t, 0.0, recommendation.Options[1].EstimatedReward)
+	assert.Equal(t, 0.0, *recommendation.Options[1].RemainingCap)
+}
+
+func TestCreditCardRewardService_CreateRule_RejectsIncomeCategory(t *testing.T) {
+	cardID := uuid.New()
+	categoryID := uuid.New()
+
+	mockRepo := new(MockCreditCardRewardRuleRepository)
+	mockCreditCardRepo := new(MockCreditCardRepository)
+	mockCategoryRepo := new(MockCategoryRepository)
+	service := NewCreditCardRewardService(mockRepo, mockCreditCardRepo, nil, mockCategoryRepo)
+
+	mockCreditCardRepo.On("GetByID", cardID).Return(&models.CreditCard{ID: cardID}, nil)
+	mockCategoryRepo.On("GetByID", categoryID).Return(&models.CashFlowCategory{ID: categoryID, Type: models.CashFlowTypeIncome}, nil)
+
+	rule, err := service.CreateRule(cardID, &models.CreateCreditCardRewardRuleInput{Name: "薪資", CategoryID: &categoryID, Rate: 1})
+
+	assert.Error(t, err)
+	assert.Nil(t, rule)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
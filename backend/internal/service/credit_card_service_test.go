@@ -1,6 +1,7 @@
 package service
 
 import (
+	"database/sql"
 	"fmt"
 	"testing"
 
@@ -31,6 +32,14 @@ func (m *MockCreditCardRepository) GetByID(id uuid.UUID) (*models.CreditCard, er
 	return args.Get(0).(*models.CreditCard), args.Error(1)
 }
 
+func (m *MockCreditCardRepository) GetByIDForUpdateTx(tx *sql.Tx, id uuid.UUID) (*models.CreditCard, error) {
+	args := m.Called(tx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CreditCard), args.Error(1)
+}
+
 func (m *MockCreditCardRepository) GetAll() ([]*models.CreditCard, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
@@ -87,6 +96,14 @@ func (m *MockCreditCardRepository) UpdateUsedCredit(id uuid.UUID, amount float64
 	return args.Get(0).(*models.CreditCard), args.Error(1)
 }
 
+func (m *MockCreditCardRepository) UpdateUsedCreditTx(tx *sql.Tx, id uuid.UUID, amount float64) (*models.CreditCard, error) {
+	args := m.Called(tx, id, amount)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CreditCard), args.Error(1)
+}
+
 func (m *MockCreditCardRepository) Delete(id uuid.UUID) error {
 	args := m.Called(id)
 	return args.Error(0)
@@ -0,0 +1,280 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/chienchuanw/asset-manager/internal/models"
+	"github.com/chienchuanw/asset-manager/internal/repository"
+	"github.com/google/uuid"
+)
+
+// CreditCardStatementService 信用卡帳單業務邏輯介面
+type CreditCardStatementService interface {
+	// GenerateStatements 依現金流重新產生信用卡在指定日期前已結帳的所有帳單
+	GenerateStatements(creditCardID uuid.UUID, asOf time.Time) ([]*models.CreditCardStatement, error)
+	// GenerateAllStatements 為所有信用卡產生帳單，回傳產生的帳單數
+	GenerateAllStatements(asOf time.Time) (int, error)
+	// ListStatements 取得已產生的信用卡帳單（依結帳日由新到舊）
+	ListStatements(creditCardID uuid.UUID) ([]*models.CreditCardStatement, error)
+	// GetStatement 取得單筆帳單（含週期內的交易明細）
+	GetStatement(creditCardID, statementID uuid.UUID) (*models.CreditCardStatement, error)
+	// GetCurrentStatement 取得最近一期已結帳的帳單，尚無帳單時回傳 nil
+	GetCurrentStatement(creditCardID uuid.UUID) (*models.CreditCardStatement, error)
+}
+
+// creditCardStatementService 信用卡帳單業務邏輯實作
+type creditCardStatementService struct {
+	repo           repository.CreditCardStatementRepository
+	creditCardRepo repository.CreditCardRepository
+	cashFlowRepo   repository.CashFlowRepository
+}
+
+// NewCreditCardStatementService 建立新的信用卡帳單 service
+func NewCreditCardStatementService(
+	repo repository.CreditCardStatementRepository,
+	creditCardRepo repository.CreditCardRepository,
+	cashFlowRepo repository.CashFlowRepository,
+) CreditCardStatementService {
+	return &creditCardStatementService{
+		repo:           repo,
+		creditCardRepo: creditCardRepo,
+		cashFlowRepo:   cashFlowRepo,
+	}
+}
+
+// GenerateStatements 重新產生信用卡帳單
+// 信用卡只保存目前已使用額度，因此以目前額度扣除所有記錄的影響反推期初餘額，再逐期累計
+func (s *creditCardStatementService) GenerateStatements(creditCardID uuid.UUID, asOf time.Time) ([]*models.CreditCardStatement, error) {
+	card, err := s.creditCardRepo.GetByID(creditCardID)
+	if err != nil {
+		return nil, fmt.Errorf("credit card not found: %w", err)
+	}
+
+	cashFlows, err := s.cashFlowRepo.GetAll(repository.CashFlowFilters{CreditCardID: &creditCardID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cash flows: %w", err)
+	}
+
+	statements := buildCreditCardStatements(card, cashFlows, asOf)
+
+	saved := make([]*models.CreditCardStatement, 0, len(statements))
+	for _, statement := range statements {
+		result, err := s.repo.Upsert(statement)
+		if err != nil {
+			return nil, fmt.Errorf("failed to save statement for %s: %w", statement.ClosingDate.Format("2006-01-02"), err)
+		}
+		saved = append(saved, result)
+	}
+
+	return saved, nil
+}
+
+// GenerateAllStatements 為所有信用卡產生帳單
+func (s *creditCardStatementService) GenerateAllStatements(asOf time.Time) (int, error) {
+	cards, err := s.creditCardRepo.GetAll()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get credit cards: %w", err)
+	}
+
+	count := 0
+	for _, card := range cards {
+		statements, err := s.GenerateStatements(card.ID, asOf)
+		if err != nil {
+			return count, fmt.Errorf("failed to generate statements for credit card %s: %w", card.ID, err)
+		}
+		count += len(statements)
+	}
+
+	return count, nil
+}
+
+// ListStatements 取得已產生的信用卡帳單列表
+// 帳單由排程與信用卡現金流異動時重新產生，讀取時不寫入資料庫
+func (s *creditCardStatementService) ListStatements(creditCardID uuid.UUID) ([]*models.CreditCardStatement, error) {
+	return s.repo.GetByCreditCardID(creditCardID)
+}
+
+// GetStatement 取得單筆帳單（含週期內的交易明細）
+func (s *creditCardStatementService) GetStatement(creditCardID, statementID uuid.UUID) (*models.CreditCardStatement, error) {
+	statement, err := s.repo.GetByID(statementID)
+	if err != nil {
+		return nil, err
+	}
+
+	if statement.CreditCardID != creditCardID {
+		return nil, fmt.Errorf("credit card statement not found")
+	}
+
+	transactions, err := s.cashFlowRepo.GetAll(repository.CashFlowFilters{
+		CreditCardID: &creditCardID,
+		StartDate:    &statement.PeriodStart,
+		EndDate:      &statement.ClosingDate,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get statement transactions: %w", err)
+	}
+	statement.Transactions = transactions
+
+	return statement, nil
+}
+
+// GetCurrentStatement 取得已產生帳單中最近一期已結帳的帳單
+func (s *creditCardStatementService) GetCurrentStatement(creditCardID uuid.UUID) (*models.CreditCardStatement, error) {
+	statements, err := s.repo.GetByCreditCardID(creditCardID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(statements) == 0 {
+		return nil, nil
+	}
+
+	return statements[0], nil
+}
+
+// statementSyncingCashFlowService 在信用卡現金流新增、修改或刪除後重新產生該卡的帳單
+// 交易中建立的現金流（每日扣款）尚未提交，由扣款排程完成後統一產生帳單
+type statementSyncingCashFlowService struct {
+	CashFlowService
+	statementService CreditCardStatementService
+}
+
+// NewStatementSyncingCashFlowService 建立會同步信用卡帳單的現金流 service
+func NewStatementSyncingCashFlowService(cashFlowService CashFlowService, statementService CreditCardStatementService) CashFlowService {
+	return &statementSyncingCashFlowService{
+		CashFlowService:  cashFlowService,
+		statementService: statementService,
+	}
+}
+
+// CreateCashFlow 建立現金流記錄，涉及信用卡時重新產生帳單
+func (s *statementSyncingCashFlowService) CreateCashFlow(input *models.CreateCashFlowInput) (*models.CashFlow, error) {
+	cashFlow, err := s.CashFlowService.CreateCashFlow(input)
+	if err != nil {
+		return nil, err
+	}
+
+	s.regenerateStatements(cashFlow)
+	return cashFlow, nil
+}
+
+// UpdateCashFlow 更新現金流記錄，修改前後涉及的信用卡皆重新產生帳單
+func (s *statementSyncingCashFlowService) UpdateCashFlow(id uuid.UUID, input *models.UpdateCashFlowInput) (*models.CashFlow, error) {
+	existing, err := s.CashFlowService.GetCashFlow(id)
+	if err != nil {
+		return nil, err
+	}
+
+	cashFlow, err := s.CashFlowService.UpdateCashFlow(id, input)
+	if err != nil {
+		return nil, err
+	}
+
+	s.regenerateStatements(existing, cashFlow)
+	return cashFlow, nil
+}
+
+// DeleteCashFlow 刪除現金流記錄，涉及信用卡時重新產生帳單
+func (s *statementSyncingCashFlowService) DeleteCashFlow(id uuid.UUID) error {
+	existing, err := s.CashFlowService.GetCashFlow(id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.CashFlowService.DeleteCashFlow(id); err != nil {
+		return err
+	}
+
+	s.regenerateStatements(existing)
+	return nil
+}
+
+// regenerateStatements 重新產生現金流涉及的信用卡（付款來源或繳款目標）帳單
+// 現金流已寫入，帳單產生失敗時僅記錄錯誤，下次排程會再重新產生
+func (s *statementSyncingCashFlowService) regenerateStatements(cashFlows ...*models.CashFlow) {
+	cardIDs := map[uuid.UUID]bool{}
+	for _, cashFlow := range cashFlows {
+		if cashFlow.SourceType != nil && *cashFlow.SourceType == models.SourceTypeCreditCard && cashFlow.SourceID != nil {
+			cardIDs[*cashFlow.SourceID] = true
+		}
+		if cashFlow.TargetType != nil && *cashFlow.TargetType == models.SourceTypeCreditCard && cashFlow.TargetID != nil {
+			cardIDs[*cashFlow.TargetID] = true
+		}
+	}
+
+	for cardID := range cardIDs {
+		if _, err := s.statementService.GenerateStatements(cardID, time.Now()); err != nil {
+			log.Printf("Warning: failed to regenerate statements for credit card %s: %v", cardID, err)
+		}
+	}
+}
+
+// buildCreditCardStatements 依現金流計算各結帳週期的帳單（由舊到新）
+// cashFlows 需為 repository 回傳的順序（依日期由新到舊）
+func buildCreditCardStatements(card *models.CreditCard, cashFlows []*models.CashFlow, asOf time.Time) []*models.CreditCardStatement {
+	lastClosing := card.LastClosingDate(asOf)
+
+	// 反推第一筆記錄之前的已使用額度
+	totalChange := 0.0
+	for _, cashFlow := range cashFlows {
+		totalChange += cashFlow.CreditCardBalanceChange(card.ID)
+	}
+	balance := roundToCents(card.UsedCredit - totalChange)
+
+	// 第一個週期：涵蓋最早一筆記錄（無記錄時從建立日開始）
+	firstDate := card.CreatedAt
+	if len(cashFlows) > 0 {
+		firstDate = cashFlows[len(cashFlows)-1].Date
+	}
+	firstDate = time.Date(firstDate.Year(), firstDate.Month(), firstDate.Day(), 0, 0, 0, 0, time.UTC)
+	closing := card.LastClosingDate(firstDate)
+	if closing.Before(firstDate) {
+		closing = card.NextClosingDate(closing)
+	}
+
+	statements := []*models.CreditCardStatement{}
+	index := len(cashFlows) - 1
+	periodStart := card.LastClosingDate(closing.AddDate(0, 0, -1)).AddDate(0, 0, 1)
+	for !closing.After(lastClosing) {
+		statement := &models.CreditCardStatement{
+			CreditCardID:    card.ID,
+			PeriodStart:     periodStart,
+			ClosingDate:     closing,
+			DueDate:         card.DueDateFor(closing),
+			PreviousBalance: balance,
+		}
+
+		// 累計本期交易
+		for ; index >= 0 && !cashFlows[index].Date.After(closing); index-- {
+			change := cashFlows[index].CreditCardBalanceChange(card.ID)
+			if change > 0 {
+				statement.NewCharges += change
+			} else {
+				statement.PaymentsAndCredits -= change
+			}
+		}
+		statement.NewCharges = roundToCents(statement.NewCharges)
+		statement.PaymentsAndCredits = roundToCents(statement.PaymentsAndCredits)
+		balance = roundToCents(balance + statement.NewCharges - statement.PaymentsAndCredits)
+		statement.ClosingBalance = balance
+		statement.MinimumPayment = models.CreditCardMinimumPayment(balance)
+
+		// 結帳日後至下次結帳日（或 asOf）前的繳款視為繳納本期帳單
+		nextClosing := card.NextClosingDate(closing)
+		for j := index; j >= 0 && !cashFlows[j].Date.After(nextClosing) && !cashFlows[j].Date.After(asOf); j-- {
+			if cashFlows[j].IsCreditCardPayment(card.ID) {
+				statement.PaidAmount += cashFlows[j].Amount
+			}
+		}
+		statement.PaidAmount = roundToCents(statement.PaidAmount)
+		statement.UpdateStatus()
+
+		statements = append(statements, statement)
+		periodStart = closing.AddDate(0, 0, 1)
+		closing = nextClosing
+	}
+
+	return statements
+}
@@ -0,0 +1,236 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chienchuanw/asset-manager/internal/models"
+	"github.com/chienchuanw/asset-manager/internal/repository"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockCreditCardStatementRepository 信用卡帳單 repository 的 mock
+type MockCreditCardStatementRepository struct {
+	mock.Mock
+}
+
+func (m *MockCreditCardStatementRepository) Upsert(statement *models.CreditCardStatement) (*models.CreditCardStatement, error) {
+	args := m.Called(statement)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CreditCardStatement), args.Error(1)
+}
+
+func (m *MockCreditCardStatementRepository) GetByID(id uuid.UUID) (*models.CreditCardStatement, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CreditCardStatement), args.Error(1)
+}
+
+func (m *MockCreditCardStatementRepository) GetByCreditCardID(creditCardID uuid.UUID) ([]*models.CreditCardStatement, error) {
+	args := m.Called(creditCardID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.CreditCardStatement), args.Error(1)
+}
+
+// newStatementTestCard 帳單日 5 日、繳款日 25 日，目前已使用 4000
+// 記錄（由新到舊）：2/20 繳款 6000、2/10 消費 4000、1/20 消費 1000、1/10 消費 5000
+func newStatementTestCard() (*models.CreditCard, []*models.CashFlow) {
+	cardID := uuid.New()
+	accountID := uuid.New()
+	creditCard := models.SourceTypeCreditCard
+	bankAccount := models.SourceTypeBankAccount
+
+	card := &models.CreditCard{
+		ID:            cardID,
+		IssuingBank:   "國泰世華",
+		CardName:      "CUBE 卡",
+		BillingDay:    5,
+		PaymentDueDay: 25,
+		CreditLimit:   100000,
+		UsedCredit:    4000,
+	}
+
+	cashFlows := []*models.CashFlow{
+		{
+			ID: uuid.New(), Date: time.Date(2025, 2, 20, 0, 0, 0, 0, time.UTC),
+			Type: models.CashFlowTypeTransferOut, Amount: 6000,
+			SourceType: &bankAccount, SourceID: &accountID,
+			TargetType: &creditCard, TargetID: &cardID,
+		},
+		{
+			ID: uuid.New(), Date: time.Date(2025, 2, 10, 0, 0, 0, 0, time.UTC),
+			Type: models.CashFlowTypeExpense, Amount: 4000,
+			SourceType: &creditCard, SourceID: &cardID,
+		},
+		{
+			ID: uuid.New(), Date: time.Date(2025, 1, 20, 0, 0, 0, 0, time.UTC),
+			Type: models.CashFlowTypeExpense, Amount: 1000,
+			SourceType: &creditCard, SourceID: &cardID,
+		},
+		{
+			ID: uuid.New(), Date: time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC),
+			Type: models.CashFlowTypeExpense, Amount: 5000,
+			SourceType: &creditCard, SourceID: &cardID,
+		},
+	}
+
+	return card, cashFlows
+}
+
+func TestBuildCreditCardStatements(t *testing.T) {
+	card, cashFlows := newStatementTestCard()
+
+	statements := buildCreditCardStatements(card, cashFlows, time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC))
+
+	assert.Len(t, statements, 2)
+
+	first := statements[0]
+	assert.Equal(t, time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC), first.PeriodStart)
+	assert.Equal(t, time.Date(2025, 2, 5, 0, 0, 0, 0, time.UTC), first.ClosingDate)
+	assert.Equal(t, time.Date(2025, 2, 25, 0, 0, 0, 0, time.UTC), first.DueDate)
+	assert.Equal(t, 0.0, first.PreviousBalance)
+	assert.Equal(t, 6000.0, first.NewCharges)
+	assert.Equal(t, 6000.0, first.ClosingBalance)
+	assert.Equal(t, 1000.0, first.MinimumPayment)
+	assert.Equal(t, 6000.0, first.PaidAmount)
+	assert.Equal(t, models.CreditCardStatementStatusPaid, first.Status)
+
+	second := statements[1]
+	assert.Equal(t, time.Date(2025, 2, 6, 0, 0, 0, 0, time.UTC), second.PeriodStart)
+	assert.Equal(t, time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC), second.ClosingDate)
+	assert.Equal(t, 6000.0, second.PreviousBalance)
+	assert.Equal(t, 4000.0, second.NewCharges)
+	assert.Equal(t, 6000.0, second.PaymentsAndCredits)
+	assert.Equal(t, 4000.0, second.ClosingBalance)
+	assert.Equal(t, 0.0, second.PaidAmount)
+	assert.Equal(t, models.CreditCardStatementStatusUnpaid, second.Status)
+}
+
+func TestBuildCreditCardStatements_BeforeFirstClosing(t *testing.T) {
+	card, cashFlows := newStatementTestCard()
+
+	// 第一次結帳前不產生帳單
+	statements := buildCreditCardStatements(card, cashFlows, time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC))
+
+	assert.Empty(t, statements)
+}
+
+func TestCreditCardStatementService_GenerateStatements(t *testing.T) {
+	card, cashFlows := newStatementTestCard()
+
+	mockRepo := new(MockCreditCardStatementRepository)
+	mockCreditCardRepo := new(MockCreditCardRepository)
+	mockCashFlowRepo := new(MockCashFlowRepository)
+	service := NewCreditCardStatementService(mockRepo, mockCreditCardRepo, mockCashFlowRepo)
+
+	// 設定 mock 期望
+	mockCreditCardRepo.On("GetByID", card.ID).Return(card, nil)
+	mockCashFlowRepo.On("GetAll", repository.CashFlowFilters{CreditCardID: &card.ID}).Return(cashFlows, nil)
+	mockRepo.On("Upsert", mock.AnythingOfType("*models.CreditCardStatement")).Return(&models.CreditCardStatement{ID: uuid.New()}, nil)
+
+	// 執行測試
+	statements, err := service.GenerateStatements(card.ID, time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC))
+
+	// 驗證結果
+	assert.NoError(t, err)
+	assert.Len(t, statements, 2)
+	mockRepo.AssertNumberOfCalls(t, "Upsert", 2)
+	mockCashFlowRepo.AssertExpectations(t)
+}
+
+func TestCreditCardStatementService_ReadsStoredStatements(t *testing.T) {
+	cardID := uuid.New()
+	latest := &models.CreditCardStatement{ID: uuid.New(), CreditCardID: cardID, ClosingDate: time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC)}
+	previous := &models.CreditCardStatement{ID: uuid.New(), CreditCardID: cardID, ClosingDate: time.Date(2025, 2, 5, 0, 0, 0, 0, time.UTC)}
+
+	mockRepo := new(MockCreditCardStatementRepository)
+	service := NewCreditCardStatementService(mockRepo, nil, nil)
+
+	// 設定 mock 期望：repository 依結帳日由新到舊回傳
+	mockRepo.On("GetByCreditCardID", cardID).Return([]*models.CreditCardStatement{latest, previous}, nil)
+
+	// 執行測試
+	statements, err := service.ListStatements(cardID)
+	assert.NoError(t, err)
+	assert.Len(t, statements, 2)
+
+	current, err := service.GetCurrentStatement(cardID)
+	assert.NoError(t, err)
+	assert.Equal(t, latest.ID, current.ID)
+
+	// 讀取帳單不應重新產生或寫入
+	mockRepo.AssertNotCalled(t, "Upsert", mock.Anything)
+}
+
+func TestStatementSyncingCashFlowService_RegeneratesCreditCardStatements(t *testing.T) {
+	card, cashFlows := newStatementTestCard()
+	categoryID := uuid.New()
+	creditCard := models.SourceTypeCreditCard
+
+	mockRepo := new(MockCreditCardStatementRepository)
+	mockCreditCardRepo := new(MockCreditCardRepository)
+	mockCashFlowRepo := new(MockCashFlowRepository)
+	statementService := NewCreditCardStatementService(mockRepo, mockCreditCardRepo, mockCashFlowRepo)
+	service := NewStatementSyncingCashFlowService(NewCashFlowService(mockCashFlowRepo, newExpenseCategoryRepository(categoryID), nil, mockCreditCardRepo), statementService)
+
+	input := &models.CreateCashFlowInput{
+		Date:        time.Date(2025, 2, 12, 0, 0, 0, 0, time.UTC),
+		Type:        models.CashFlowTypeExpense,
+		CategoryID:  categoryID,
+		Amount:      800,
+		Description: "晚餐",
+		SourceType:  &creditCard,
+		SourceID:    &card.ID,
+	}
+
+	// 設定 mock 期望：建立現金流後以最新記錄重新產生該卡帳單
+	mockCreditCardRepo.On("GetByID", card.ID).Return(card, nil)
+	mockCreditCardRepo.On("UpdateUsedCredit", card.ID, 800.0).Return(card, nil)
+	mockCashFlowRepo.On("Create", input).Return(&models.CashFlow{ID: uuid.New(), Type: input.Type, Amount: 800, SourceType: &creditCard, SourceID: &card.ID}, nil)
+	mockCashFlowRepo.On("GetAll", repository.CashFlowFilters{CreditCardID: &card.ID}).Return(cashFlows, nil)
+	mockRepo.On("Upsert", mock.AnythingOfType("*models.CreditCardStatement")).Return(&models.CreditCardStatement{ID: uuid.New()}, nil)
+
+	// 執行測試
+	_, err := service.CreateCashFlow(input)
+
+	// 驗證結果
+	assert.NoError(t, err)
+	mockCashFlowRepo.AssertExpectations(t)
+	mockRepo.AssertCalled(t, "Upsert", mock.AnythingOfType("*models.CreditCardStatement"))
+}
+
+func TestStatementSyncingCashFlowService_SkipsNonCreditCardFlows(t *testing.T) {
+	categoryID := uuid.New()
+
+	mockRepo := new(MockCreditCardStatementRepository)
+	mockCashFlowRepo := new(MockCashFlowRepository)
+	statementService := NewCreditCardStatementService(mockRepo, nil, mockCashFlowRepo)
+	service := NewStatementSyncingCashFlowService(NewCashFlowService(mockCashFlowRepo, newExpenseCategoryRepository(categoryID), nil, nil), statementService)
+
+	input := &models.CreateCashFlowInput{
+		Date:        time.Date(2025, 2, 12, 0, 0, 0, 0, time.UTC),
+		Type:        models.CashFlowTypeExpense,
+		CategoryID:  categoryID,
+		Amount:      120,
+		Description: "午餐",
+	}
+
+	// 設定 mock 期望：現金支出不涉及信用卡
+	mockCashFlowRepo.On("Create", input).Return(&models.CashFlow{ID: uuid.New(), Type: input.Type, Amount: 120}, nil)
+
+	// 執行測試
+	_, err := service.CreateCashFlow(input)
+
+	// 驗證結果
+	assert.NoError(t, err)
+	mockCashFlowRepo.AssertNotCalled(t, "GetAll", mock.Anything)
+	mockRepo.AssertNotCalled(t, "Upsert", mock.Anything)
+}
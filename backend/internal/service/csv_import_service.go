@@ -17,11 +17,14 @@ type CSVImportService interface {
 	ParseCSV(reader io.Reader) *models.CSVImportResult
 }
 
-type csvImportService struct{}
+type csvImportService struct {
+	feeProfileService FeeProfileService // 可為 nil（不自動補上手續費與交易稅）
+}
 
 // NewCSVImportService 建立新的 CSV 匯入服務
-func NewCSVImportService() CSVImportService {
-	return &csvImportService{}
+// feeProfileService 用於補上未填寫的手續費與交易稅並標示與設定不符的值，可為 nil
+func NewCSVImportService(feeProfileService FeeProfileService) CSVImportService {
+	return &csvImportService{feeProfileService: feeProfileService}
 }
 
 // GenerateTemplate 生成 CSV 樣板
@@ -102,6 +105,7 @@ func (s *csvImportService) ParseCSV(reader io.Reader) *models.CSVImportResult {
 			result.Success = false
 			result.Errors = append(result.Errors, errs...)
 		} else {
+			result.Warnings = append(result.Warnings, applyImportTradingCosts(s.feeProfileService, tx, rowNum)...)
 			result.Transactions = append(result.Transactions, tx)
 		}
 	}
@@ -10,7 +10,7 @@ import (
 
 // TestGenerateCSVTemplate 測試生成 CSV 樣板
 func TestGenerateCSVTemplate(t *testing.T) {
-	service := NewCSVImportService()
+	service := NewCSVImportService(nil)
 
 	csv := service.GenerateTemplate()
 
@@ -38,7 +38,7 @@ func TestGenerateCSVTemplate(t *testing.T) {
 
 // TestParseCSV_Success 測試成功解析 CSV
 func TestParseCSV_Success(t *testing.T) {
-	service := NewCSVImportService()
+	service := NewCSVImportService(nil)
 
 	csvContent := `date,asset_type,symbol,name,transaction_type,quantity,price,fee,tax,currency,note
 2025-01-15,tw_stock,2330,台積電,buy,10,620,28,,TWD,測試交易`
@@ -62,7 +62,7 @@ func TestParseCSV_Success(t *testing.T) {
 
 // TestParseCSV_DateWithSlash 測試 YYYY/MM/DD 日期格式
 func TestParseCSV_DateWithSlash(t *testing.T) {
-	service := NewCSVImportService()
+	service := NewCSVImportService(nil)
 
 	csvContent := `date,asset_type,symbol,name,transaction_type,quantity,price,fee,tax,currency,note
 2025/01/15,tw_stock,2330,台積電,buy,10,620,28,,TWD,測試交易`
@@ -82,7 +82,7 @@ func TestParseCSV_DateWithSlash(t *testing.T) {
 
 // TestParseCSV_InvalidDate 測試無效的日期格式
 func TestParseCSV_InvalidDate(t *testing.T) {
-	service := NewCSVImportService()
+	service := NewCSVImportService(nil)
 
 	csvContent := `date,asset_type,symbol,name,transaction_type,quantity,price,fee,tax,currency,note
 15-01-2025,tw_stock,2330,台積電,buy,10,620,28,,TWD,`
@@ -98,7 +98,7 @@ func TestParseCSV_InvalidDate(t *testing.T) {
 
 // TestParseCSV_MissingRequiredField 測試缺少必填欄位
 func TestParseCSV_MissingRequiredField(t *testing.T) {
-	service := NewCSVImportService()
+	service := NewCSVImportService(nil)
 
 	csvContent := `date,asset_type,symbol,name,transaction_type,quantity,price,fee,tax,currency,note
 2025-01-15,tw_stock,,台積電,buy,10,620,28,,TWD,`
@@ -112,7 +112,7 @@ func TestParseCSV_MissingRequiredField(t *testing.T) {
 
 // TestParseCSV_InvalidAssetType 測試無效的資產類別
 func TestParseCSV_InvalidAssetType(t *testing.T) {
-	service := NewCSVImportService()
+	service := NewCSVImportService(nil)
 
 	csvContent := `date,asset_type,symbol,name,transaction_type,quantity,price,fee,tax,currency,note
 2025-01-15,invalid_type,2330,台積電,buy,10,620,28,,TWD,`
@@ -126,7 +126,7 @@ func TestParseCSV_InvalidAssetType(t *testing.T) {
 
 // TestParseCSV_InvalidQuantity 測試無效的數量
 func TestParseCSV_InvalidQuantity(t *testing.T) {
-	service := NewCSVImportService()
+	service := NewCSVImportService(nil)
 
 	csvContent := `date,asset_type,symbol,name,transaction_type,quantity,price,fee,tax,currency,note
 2025-01-15,tw_stock,2330,台積電,buy,abc,620,28,,TWD,`
@@ -140,7 +140,7 @@ func TestParseCSV_InvalidQuantity(t *testing.T) {
 
 // TestParseCSV_MultipleRows 測試多筆交易記錄
 func TestParseCSV_MultipleRows(t *testing.T) {
-	service := NewCSVImportService()
+	service := NewCSVImportService(nil)
 
 	csvContent := `date,asset_type,symbol,name,transaction_type,quantity,price,fee,tax,currency,note
 2025-01-15,tw_stock,2330,台積電,buy,10,620,28,,TWD,
@@ -155,7 +155,7 @@ func TestParseCSV_MultipleRows(t *testing.T) {
 
 // TestParseCSV_PartialErrors 測試部分資料有錯誤
 func TestParseCSV_PartialErrors(t *testing.T) {
-	service := NewCSVImportService()
+	service := NewCSVImportService(nil)
 
 	csvContent := `date,asset_type,symbol,name,transaction_type,quantity,price,fee,tax,currency,note
 2025-01-15,tw_stock,2330,台積電,buy,10,620,28,,TWD,
@@ -171,3 +171,35 @@ invalid-date,us_stock,AAPL,Apple,buy,5,150,1,,USD,
 	assert.Equal(t, "date", result.Errors[0].Field)
 }
 
+
+// TestParseCSV_AppliesFeeProfile 測試匯入時依手續費設定補上手續費並提醒不符的手續費
+func TestParseCSV_AppliesFeeProfile(t *testing.T) {
+	discount := 0.28
+	feeProfileService := NewFeeProfileService(&fakeFeeProfileRepository{}, nil)
+	_, err := feeProfileService.CreateProfile(&models.CreateFeeProfileInput{
+		Name:        "電子下單 2.8 折",
+		AssetType:   models.AssetTypeTWStock,
+		IsDefault:   true,
+		FeeRate:     0.001425,
+		FeeDiscount: &discount,
+		MinFee:      20,
+	})
+	assert.NoError(t, err)
+
+	service := NewCSVImportService(feeProfileService)
+
+	csvContent := `date,asset_type,symbol,name,transaction_type,quantity,price,fee,tax,currency,note
+2025-01-15,tw_stock,2330,台積電,sell,1000,1000,,,TWD,
+2025-01-16,tw_stock,2330,台積電,buy,1000,1000,1425,,TWD,`
+
+	result := service.ParseCSV(strings.NewReader(csvContent))
+
+	assert.True(t, result.Success)
+	assert.Len(t, result.Transactions, 2)
+	assert.Equal(t, 399.0, *result.Transactions[0].Fee)
+	assert.Equal(t, 3000.0, *result.Transactions[0].Tax)
+	assert.Equal(t, 1425.0, *result.Transactions[1].Fee)
+	assert.Len(t, result.Warnings, 1)
+	assert.Equal(t, 2, result.Warnings[0].Row)
+	assert.Equal(t, "fee", result.Warnings[0].Field)
+}
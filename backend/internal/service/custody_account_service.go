@@ -0,0 +1,193 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/chienchuanw/asset-manager/internal/models"
+	"github.com/chienchuanw/asset-manager/internal/repository"
+	"github.com/google/uuid"
+)
+
+// unassignedCustodyName 未指定保管帳戶的交易所歸屬的位置名稱
+const unassignedCustodyName = "未指定"
+
+// CustodyAccountService 加密貨幣保管帳戶業務邏輯介面
+type CustodyAccountService interface {
+	CreateCustodyAccount(input *models.CreateCustodyAccountInput) (*models.CustodyAccount, error)
+	GetCustodyAccount(id uuid.UUID) (*models.CustodyAccount, error)
+	ListCustodyAccounts() ([]*models.CustodyAccount, error)
+	UpdateCustodyAccount(id uuid.UUID, input *models.UpdateCustodyAccountInput) (*models.CustodyAccount, error)
+	DeleteCustodyAccount(id uuid.UUID) error
+	// GetHoldingsByCustody 依保管位置列出加密貨幣持倉（成本與市值由整體 FIFO 持倉按數量分攤）
+	GetHoldingsByCustody() ([]*models.CustodyHolding, error)
+}
+
+// custodyAccountService 加密貨幣保管帳戶業務邏輯實作
+type custodyAccountService struct {
+	repo            repository.CustodyAccountRepository
+	transactionRepo repository.TransactionRepository
+	holdingService  HoldingService
+}
+
+// NewCustodyAccountService 建立新的保管帳戶 service
+func NewCustodyAccountService(
+	repo repository.CustodyAccountRepository,
+	transactionRepo repository.TransactionRepository,
+	holdingService HoldingService,
+) CustodyAccountService {
+	return &custodyAccountService{
+		repo:            repo,
+		transactionRepo: transactionRepo,
+		holdingService:  holdingService,
+	}
+}
+
+// CreateCustodyAccount 建立新的保管帳戶
+func (s *custodyAccountService) CreateCustodyAccount(input *models.CreateCustodyAccountInput) (*models.CustodyAccount, error) {
+	if input.Name == "" {
+		return nil, fmt.Errorf("custody account name is required")
+	}
+
+	if !input.CustodyType.Validate() {
+		return nil, fmt.Errorf("invalid custody type: %s", input.CustodyType)
+	}
+
+	return s.repo.Create(input)
+}
+
+// GetCustodyAccount 取得單筆保管帳戶
+func (s *custodyAccountService) GetCustodyAccount(id uuid.UUID) (*models.CustodyAccount, error) {
+	return s.repo.GetByID(id)
+}
+
+// ListCustodyAccounts 取得所有保管帳戶
+func (s *custodyAccountService) ListCustodyAccounts() ([]*models.CustodyAccount, error) {
+	return s.repo.GetAll()
+}
+
+// UpdateCustodyAccount 更新保管帳戶
+func (s *custodyAccountService) UpdateCustodyAccount(id uuid.UUID, input *models.UpdateCustodyAccountInput) (*models.CustodyAccount, error) {
+	if input.Name != nil && *input.Name == "" {
+		return nil, fmt.Errorf("custody account name cannot be empty")
+	}
+
+	if input.CustodyType != nil && !input.CustodyType.Validate() {
+		return nil, fmt.Errorf("invalid custody type: %s", *input.CustodyType)
+	}
+
+	return s.repo.Update(id, input)
+}
+
+// DeleteCustodyAccount 刪除保管帳戶
+func (s *custodyAccountService) DeleteCustodyAccount(id uuid.UUID) error {
+	return s.repo.Delete(id)
+}
+
+// custodyPosition 保管位置與標的的組合鍵
+type custodyPosition struct {
+	custodyAccountID uuid.UUID // uuid.Nil 代表未指定
+	symbol           string
+}
+
+// GetHoldingsByCustody 依保管位置列出加密貨幣持倉
+// 數量由各位置的買賣、收入與轉移交易累計；成本與市值依整體持倉的平均成本與現價分攤，
+// 因此各位置加總恆等於整體 FIFO 持倉，轉移不會改變成本批次
+func (s *custodyAccountService) GetHoldingsByCustody() ([]*models.CustodyHolding, error) {
+	assetType := models.AssetTypeCrypto
+
+	transactions, err := s.transactionRepo.GetAll(repository.TransactionFilters{AssetType: &assetType})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions: %w", err)
+	}
+
+	result, err := s.holdingService.GetAllHoldings(models.HoldingFilters{AssetType: &assetType})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get holdings: %w", err)
+	}
+
+	accounts, err := s.repo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	accountNames := make(map[uuid.UUID]string, len(accounts))
+	for _, account := range accounts {
+		accountNames[account.ID] = account.Name
+	}
+
+	quantities := custodyQuantities(transactions)
+
+	holdings := []*models.CustodyHolding{}
+	for _, holding := range result.Holdings {
+		if holding.Quantity <= 0 {
+			continue
+		}
+
+		for position, quantity := range quantities {
+			if position.symbol != holding.Symbol || math.Abs(quantity) < 1e-12 {
+				continue
+			}
+
+			share := quantity / holding.Quantity
+			custodyHolding := &models.CustodyHolding{
+				CustodyAccountName: unassignedCustodyName,
+				Symbol:             holding.Symbol,
+				Name:               holding.Name,
+				Quantity:           quantity,
+				AvgCost:            holding.AvgCost,
+				TotalCost:          holding.TotalCost * share,
+				MarketValue:        holding.MarketValue * share,
+			}
+			custodyHolding.UnrealizedPL = custodyHolding.MarketValue - custodyHolding.TotalCost
+
+			if position.custodyAccountID != uuid.Nil {
+				id := position.custodyAccountID
+				custodyHolding.CustodyAccountID = &id
+				if name, ok := accountNames[id]; ok {
+					custodyHolding.CustodyAccountName = name
+				}
+			}
+
+			holdings = append(holdings, custodyHolding)
+		}
+	}
+
+	sort.Slice(holdings, func(i, j int) bool {
+		if holdings[i].CustodyAccountName != holdings[j].CustodyAccountName {
+			return holdings[i].CustodyAccountName < holdings[j].CustodyAccountName
+		}
+		return holdings[i].Symbol < holdings[j].Symbol
+	})
+
+	return holdings, nil
+}
+
+// custodyQuantities 累計各保管位置的持有數量
+// 買進與質押、空投收入計入保管帳戶；賣出自保管帳戶扣除；
+// 轉移自轉出帳戶扣除轉出數量，轉入帳戶增加扣除網路手續費後的數量
+func custodyQuantities(transactions []*models.Transaction) map[custodyPosition]float64 {
+	quantities := make(map[custodyPosition]float64)
+
+	positionOf := func(accountID *uuid.UUID, symbol string) custodyPosition {
+		position := custodyPosition{symbol: symbol}
+		if accountID != nil {
+			position.custodyAccountID = *accountID
+		}
+		return position
+	}
+
+	for _, tx := range transactions {
+		switch tx.TransactionType {
+		case models.TransactionTypeBuy, models.TransactionTypeStaking, models.TransactionTypeAirdrop:
+			quantities[positionOf(tx.CustodyAccountID, tx.Symbol)] += tx.Quantity
+		case models.TransactionTypeSell:
+			quantities[positionOf(tx.CustodyAccountID, tx.Symbol)] -= tx.Quantity
+		case models.TransactionTypeTransfer:
+			quantities[positionOf(tx.CustodyAccountID, tx.Symbol)] -= tx.Quantity
+			quantities[positionOf(tx.ToCustodyAccountID, tx.Symbol)] += tx.ReceivedQuantity()
+		}
+	}
+
+	return quantities
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chienchuanw/asset-manager/internal/models"
+	"github.com/chienchuanw/asset-manager/internal/repository"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCustodyAccountService_GetHoldingsByCustody(t *testing.T) {
+	mockRepo := new(MockCustodyAccountRepository)
+	mockTransactionRepo := new(MockTransactionRepository)
+	mockHoldingService := new(MockHoldingService)
+	service := NewCustodyAccountService(mockRepo, mockTransactionRepo, mockHoldingService)
+
+	exchangeID := uuid.New()
+	walletID := uuid.New()
+	assetType := models.AssetTypeCrypto
+	date := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// 設定 mock 期望：交易所買進 2 BTC，轉移 1 BTC 至冷錢包（網路手續費 0.01），之後在交易所賣出 0.5 BTC
+	mockTransactionRepo.On("GetAll", repository.TransactionFilters{AssetType: &assetType}).Return([]*models.Transaction{
+		{Date: date, Symbol: "BTC", TransactionType: models.TransactionTypeBuy, Quantity: 2, CustodyAccountID: &exchangeID},
+		{Date: date, Symbol: "BTC", TransactionType: models.TransactionTypeTransfer, Quantity: 1, NetworkFee: ptrFloat64(0.01), CustodyAccountID: &exchangeID, ToCustodyAccountID: &walletID},
+		{Date: date, Symbol: "BTC", TransactionType: models.TransactionTypeSell, Quantity: 0.5, CustodyAccountID: &exchangeID},
+		{Date: date, Symbol: "BTC", TransactionType: models.TransactionTypeStaking, Quantity: 0.01},
+	}, nil)
+	mockHoldingService.On("GetAllHoldings", models.HoldingFilters{AssetType: &assetType}).Return(&HoldingServiceResult{
+		Holdings: []*models.Holding{
+			{Symbol: "BTC", Name: "Bitcoin", Quantity: 1.5, AvgCost: 1000000, TotalCost: 1500000, MarketValue: 3000000},
+		},
+	}, nil)
+	mockRepo.On("GetAll").Return([]*models.CustodyAccount{
+		{ID: exchangeID, Name: "Binance"},
+		{ID: walletID, Name: "Ledger"},
+	}, nil)
+
+	// 執行測試
+	holdings, err := service.GetHoldingsByCustody()
+
+	// 驗證結果：各位置依數量分攤整體成本與市值
+	assert.NoError(t, err)
+	assert.Len(t, holdings, 3)
+
+	assert.Equal(t, "Binance", holdings[0].CustodyAccountName)
+	assert.InDelta(t, 0.5, holdings[0].Quantity, 1e-9)
+	assert.InDelta(t, 500000, holdings[0].TotalCost, 0.01)
+
+	assert.Equal(t, "Ledger", holdings[1].CustodyAccountName)
+	assert.Equal(t, walletID, *holdings[1].CustodyAccountID)
+	assert.InDelta(t, 0.99, holdings[1].Quantity, 1e-9)
+	assert.InDelta(t, 1980000, holdings[1].MarketValue, 0.01)
+	assert.InDelta(t, 990000, holdings[1].UnrealizedPL, 0.01)
+
+	assert.Equal(t, unassignedCustodyName, holdings[2].CustodyAccountName)
+	assert.Nil(t, holdings[2].CustodyAccountID)
+	assert.InDelta(t, 0.01, holdings[2].Quantity, 1e-9)
+}
+
+func TestCreateTransaction_TransferValidation(t *testing.T) {
+	service := NewTransactionService(new(MockTransactionRepository), nil, nil, nil, nil, nil, nil, nil)
+
+	exchangeID := uuid.New()
+	base := func() *models.CreateTransactionInput {
+		return &models.CreateTransactionInput{
+			Date:               time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+			AssetType:          models.AssetTypeCrypto,
+			Symbol:             "BTC",
+			Name:               "Bitcoin",
+			TransactionType:    models.TransactionTypeTransfer,
+			Quantity:           1,
+			Currency:           models.CurrencyUSD,
+			CustodyAccountID:   &exchangeID,
+			ToCustodyAccountID: &exchangeID,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(input *models.CreateTransactionInput)
+		wantErr string
+	}{
+		{name: "same source and destination", modify: func(input *models.CreateTransactionInput) {}, wantErr: "must be different"},
+		{name: "missing destination", modify: func(input *models.CreateTransactionInput) { input.ToCustodyAccountID = nil }, wantErr: "are required"},
+		{name: "network fee exceeds quantity", modify: func(input *models.CreateTransactionInput) {
+			walletID := uuid.New()
+			input.ToCustodyAccountID = &walletID
+			input.NetworkFee = ptrFloat64(1)
+		}, wantErr: "network fee"},
+		{name: "non-crypto asset", modify: func(input *models.CreateTransactionInput) { input.AssetType = models.AssetTypeUSStock }, wantErr: "only allowed for crypto"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := base()
+			tt.modify(input)
+
+			result, err := service.CreateTransaction(input)
+
+			assert.Error(t, err)
+			assert.Nil(t, result)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
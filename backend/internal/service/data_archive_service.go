@@ -0,0 +1,527 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/chienchuanw/asset-manager/internal/models"
+	"github.com/chienchuanw/asset-manager/internal/repository"
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrDataArchiveIncompatible 備份檔與目前的資料庫不相容（格式版本或 schema 版本不同）
+	ErrDataArchiveIncompatible = errors.New("incompatible data archive")
+	// ErrDataRestoreNotEmpty 還原目標資料庫已有資料（僅能還原至空的資料庫）
+	ErrDataRestoreNotEmpty = errors.New("target database is not empty")
+	// ErrDataArchiveTooLarge 備份檔超過大小上限
+	ErrDataArchiveTooLarge = errors.New("data archive is too large")
+)
+
+// MaxDataArchiveSize 備份檔（以及 ZIP 中每個檔案解壓縮後）的大小上限
+const MaxDataArchiveSize int64 = 256 << 20
+
+// ZIP 備份檔中的檔案名稱
+const (
+	dataArchiveManifestFile = "manifest.json"
+	dataArchiveTableDir     = "tables/"
+)
+
+// DataArchiveService 完整資料備份與還原服務介面
+type DataArchiveService interface {
+	Export() (*models.DataArchive, error)
+	WriteArchive(w io.Writer, archive *models.DataArchive, format models.DataArchiveFormat) error
+	ReadArchive(r io.Reader) (*models.DataArchive, error)
+	Restore(archive *models.DataArchive) (*models.DataRestoreResult, error)
+}
+
+// dataArchiveService 完整資料備份與還原服務實作
+type dataArchiveService struct {
+	repo    repository.DataArchiveRepository
+	maxSize int64
+}
+
+// NewDataArchiveService 建立新的資料備份 service
+func NewDataArchiveService(repo repository.DataArchiveRepository) DataArchiveService {
+	return &dataArchiveService{repo: repo, maxSize: MaxDataArchiveSize}
+}
+
+// archiveIDKind 資料表主鍵類型
+type archiveIDKind int
+
+const (
+	archiveIDUUID   archiveIDKind = iota // UUID 主鍵（還原時產生新的 UUID）
+	archiveIDSerial                      // SERIAL 主鍵（還原時由資料庫產生）
+	archiveIDNone                        // 沒有 id 欄位（複合主鍵）
+)
+
+// archiveTable 備份資料表定義
+type archiveTable struct {
+	name          string
+	idKind        archiveIDKind
+	orderBy       string
+	refs          map[string]string // 外鍵欄位 → 參照的資料表（參照的資料表必須排在前面）
+	softRefs      []string          // 多型參照欄位（例如 cash_flows.source_id），對應到任一已還原資料的新 id
+	matchColumns  []string          // migration 會建立預設資料的資料表：以這些欄位對應既有資料，不新增重複資料
+	updateColumns []string          // 對應到既有資料時以備份值覆寫的欄位
+}
+
+// dataArchiveTables 備份的資料表（依還原順序排列，被參照的資料表在前）
+// 排程執行記錄（scheduler_logs、cash_flow_report_logs）屬於操作記錄，不納入備份
+var dataArchiveTables = []archiveTable{
+	{name: "settings", matchColumns: []string{"key"}, updateColumns: []string{"value"}},
+	{name: "cash_flow_categories", matchColumns: []string{"name", "type"}},
+	{name: "exchange_rates", idKind: archiveIDSerial, matchColumns: []string{"from_currency", "to_currency", "date"}, updateColumns: []string{"rate"}},
+	{name: "instruments", matchColumns: []string{"asset_type", "symbol"}},
+	{name: "last_known_prices", idKind: archiveIDNone, orderBy: "asset_type, symbol", matchColumns: []string{"symbol", "asset_type"}, updateColumns: []string{"price", "currency", "source", "fetched_at"}},
+	{name: "option_contracts"},
+	{name: "bank_accounts"},
+	{name: "credit_card_groups"},
+	{name: "credit_cards", refs: map[string]string{"group_id": "credit_card_groups"}},
+	{name: "custody_accounts"},
+	{name: "brokerage_accounts", refs: map[string]string{"settlement_account_id": "bank_accounts"}},
+	{name: "fee_profiles", refs: map[string]string{"brokerage_account_id": "brokerage_accounts"}},
+	{name: "subscriptions", refs: map[string]string{"category_id": "cash_flow_categories"}, softRefs: []string{"account_id"}},
+	{name: "subscription_price_changes", refs: map[string]string{"subscription_id": "subscriptions"}},
+	{name: "subscription_usage_notes", refs: map[string]string{"subscription_id": "subscriptions"}},
+	{name: "installments", refs: map[string]string{"category_id": "cash_flow_categories"}, softRefs: []string{"account_id"}},
+	{name: "loans", refs: map[string]string{"category_id": "cash_flow_categories", "interest_category_id": "cash_flow_categories"}, softRefs: []string{"account_id"}},
+	{name: "recurring_templates", refs: map[string]string{"category_id": "cash_flow_categories"}, softRefs: []string{"account_id", "target_id"}},
+	{name: "recurring_template_amount_changes", refs: map[string]string{"template_id": "recurring_templates"}},
+	{name: "manual_assets"},
+	{name: "manual_asset_valuations", refs: map[string]string{"asset_id": "manual_assets"}},
+	{name: "cash_flows", refs: map[string]string{"category_id": "cash_flow_categories"}, softRefs: []string{"source_id", "target_id"}},
+	{name: "installment_prepayments", refs: map[string]string{"installment_id": "installments", "cash_flow_id": "cash_flows"}},
+	{name: "installment_payments", refs: map[string]string{"installment_id": "installments", "cash_flow_id": "cash_flows"}},
+	{name: "loan_rate_changes", refs: map[string]string{"loan_id": "loans"}},
+	{name: "loan_prepayments", refs: map[string]string{"loan_id": "loans"}},
+	{name: "loan_payments", refs: map[string]string{"loan_id": "loans", "principal_cash_flow_id": "cash_flows", "interest_cash_flow_id": "cash_flows"}},
+	{name: "billing_records", softRefs: []string{"source_id"}},
+	{name: "billing_runs"},
+	{name: "bank_account_reconciliations", refs: map[string]string{"bank_account_id": "bank_accounts", "adjustment_cash_flow_id": "cash_flows"}},
+	{name: "credit_card_statements", refs: map[string]string{"credit_card_id": "credit_cards"}},
+	{name: "credit_card_reward_rules", refs: map[string]string{"credit_card_id": "credit_cards", "category_id": "cash_flow_categories"}},
+	{name: "transactions", refs: map[string]string{"exchange_rate_id": "exchange_rates", "brokerage_account_id": "brokerage_accounts", "custody_account_id": "custody_accounts", "to_custody_account_id": "custody_accounts"}},
+	{name: "realized_profits", refs: map[string]string{"transaction_id": "transactions"}},
+	{name: "brokerage_cash_movements", refs: map[string]string{"brokerage_account_id": "brokerage_accounts", "transaction_id": "transactions", "bank_account_id": "bank_accounts", "cash_flow_id": "cash_flows"}},
+	{name: "asset_snapshots"},
+	{name: "daily_performance_snapshots"},
+	{name: "daily_performance_snapshot_details", refs: map[string]string{"snapshot_id": "daily_performance_snapshots"}},
+}
+
+// Export 匯出所有資料表為備份檔
+func (s *dataArchiveService) Export() (*models.DataArchive, error) {
+	version, dirty, err := s.repo.GetSchemaVersion()
+	if err != nil {
+		return nil, err
+	}
+	if dirty {
+		return nil, fmt.Errorf("database schema version %d is dirty", version)
+	}
+
+	archive := &models.DataArchive{
+		Manifest: models.DataArchiveManifest{
+			Application:   models.DataArchiveApplication,
+			FormatVersion: models.DataArchiveFormatVersion,
+			SchemaVersion: version,
+			CreatedAt:     time.Now().UTC(),
+		},
+		Tables: make(map[string][]json.RawMessage, len(dataArchiveTables)),
+	}
+
+	for _, table := range dataArchiveTables {
+		orderBy := table.orderBy
+		if orderBy == "" {
+			orderBy = "id"
+		}
+		rows, err := s.repo.ExportTable(table.name, orderBy)
+		if err != nil {
+			return nil, err
+		}
+		archive.Tables[table.name] = rows
+		archive.Manifest.Tables = append(archive.Manifest.Tables, models.DataArchiveTableInfo{Name: table.name, Rows: len(rows)})
+	}
+
+	return archive, nil
+}
+
+// WriteArchive 將備份檔寫出為 JSON 或 ZIP
+func (s *dataArchiveService) WriteArchive(w io.Writer, archive *models.DataArchive, format models.DataArchiveFormat) error {
+	switch format {
+	case models.DataArchiveFormatJSON:
+		if err := json.NewEncoder(w).Encode(archive); err != nil {
+			return fmt.Errorf("failed to encode archive: %w", err)
+		}
+		return nil
+	case models.DataArchiveFormatZIP:
+		return writeDataArchiveZIP(w, archive)
+	}
+	return fmt.Errorf("unsupported archive format: %s", format)
+}
+
+// writeDataArchiveZIP 寫出 ZIP 備份檔（manifest.json 與 tables/<資料表>.json）
+func writeDataArchiveZIP(w io.Writer, archive *models.DataArchive) error {
+	zw := zip.NewWriter(w)
+
+	manifest, err := json.MarshalIndent(archive.Manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	if err := writeZIPFile(zw, dataArchiveManifestFile, manifest); err != nil {
+		return err
+	}
+
+	for _, table := range archive.Manifest.Tables {
+		rows := archive.Tables[table.Name]
+		if rows == nil {
+			rows = []json.RawMessage{}
+		}
+		data, err := json.Marshal(rows)
+		if err != nil {
+			return fmt.Errorf("failed to encode %s: %w", table.Name, err)
+		}
+		if err := writeZIPFile(zw, dataArchiveTableDir+table.Name+".json", data); err != nil {
+			return err
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("failed to finish archive: %w", err)
+	}
+	return nil
+}
+
+// writeZIPFile 在 ZIP 中新增一個檔案
+func writeZIPFile(zw *zip.Writer, name string, data []byte) error {
+	f, err := zw.Create(name)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", name, err)
+	}
+	if _, err := f.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+// ReadArchive 讀取 JSON 或 ZIP 備份檔（依檔案內容自動判斷），超過大小上限時回傳 ErrDataArchiveTooLarge
+func (s *dataArchiveService) ReadArchive(r io.Reader) (*models.DataArchive, error) {
+	// 多讀一個位元組以判斷是否超過上限
+	data, err := io.ReadAll(io.LimitReader(r, s.maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive: %w", err)
+	}
+	if int64(len(data)) > s.maxSize {
+		return nil, fmt.Errorf("%w: exceeds %d bytes", ErrDataArchiveTooLarge, s.maxSize)
+	}
+
+	var archive *models.DataArchive
+	if bytes.HasPrefix(data, []byte("PK\x03\x04")) {
+		archive, err = readDataArchiveZIP(data, s.maxSize)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		archive = &models.DataArchive{}
+		if err := json.Unmarshal(data, archive); err != nil {
+			return nil, fmt.Errorf("failed to decode archive: %w", err)
+		}
+	}
+
+	for _, table := range archive.Manifest.Tables {
+		if len(archive.Tables[table.Name]) != table.Rows {
+			return nil, fmt.Errorf("archive table %s has %d rows, manifest says %d", table.Name, len(archive.Tables[table.Name]), table.Rows)
+		}
+	}
+	return archive, nil
+}
+
+// readDataArchiveZIP 讀取 ZIP 備份檔，每個檔案解壓縮後不得超過 maxSize
+func readDataArchiveZIP(data []byte, maxSize int64) (*models.DataArchive, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive: %w", err)
+	}
+
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	archive := &models.DataArchive{Tables: make(map[string][]json.RawMessage)}
+	if err := readZIPJSON(files, dataArchiveManifestFile, maxSize, &archive.Manifest); err != nil {
+		return nil, err
+	}
+	for _, table := range archive.Manifest.Tables {
+		var rows []json.RawMessage
+		if err := readZIPJSON(files, dataArchiveTableDir+table.Name+".json", maxSize, &rows); err != nil {
+			return nil, err
+		}
+		archive.Tables[table.Name] = rows
+	}
+	return archive, nil
+}
+
+// readZIPJSON 讀取 ZIP 中的 JSON 檔案（解壓縮後超過 maxSize 時回傳 ErrDataArchiveTooLarge）
+func readZIPJSON(files map[string]*zip.File, name string, maxSize int64, v interface{}) error {
+	f, ok := files[name]
+	if !ok {
+		return fmt.Errorf("archive is missing %s", name)
+	}
+	if f.UncompressedSize64 > uint64(maxSize) {
+		return fmt.Errorf("%w: %s exceeds %d bytes", ErrDataArchiveTooLarge, name, maxSize)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", name, err)
+	}
+	defer rc.Close()
+
+	// 不信任 ZIP 標頭記載的大小，解壓縮時同樣限制讀取量
+	data, err := io.ReadAll(io.LimitReader(rc, maxSize+1))
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	if int64(len(data)) > maxSize {
+		return fmt.Errorf("%w: %s exceeds %d bytes", ErrDataArchiveTooLarge, name, maxSize)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to decode %s: %w", name, err)
+	}
+	return nil
+}
+
+// Restore 將備份檔還原至空的資料庫（單一資料庫事務）
+// 所有 id 皆重新產生並改寫參照欄位；migration 建立的預設資料（設定、分類、匯率）以鍵值欄位對應既有資料
+func (s *dataArchiveService) Restore(archive *models.DataArchive) (*models.DataRestoreResult, error) {
+	version, dirty, err := s.repo.GetSchemaVersion()
+	if err != nil {
+		return nil, err
+	}
+	if dirty {
+		return nil, fmt.Errorf("%w: database schema version %d is dirty", ErrDataArchiveIncompatible, version)
+	}
+	if err := validateDataArchive(archive, version); err != nil {
+		return nil, err
+	}
+
+	result := &models.DataRestoreResult{SchemaVersion: version}
+	err = s.repo.Restore(func(tx repository.DataRestoreTx) error {
+		// 僅能還原至沒有使用者資料的資料庫
+		for _, table := range dataArchiveTables {
+			if table.matchColumns != nil {
+				continue
+			}
+			count, err := tx.CountRows(table.name)
+			if err != nil {
+				return err
+			}
+			if count > 0 {
+				return fmt.Errorf("%w: %s has %d rows", ErrDataRestoreNotEmpty, table.name, count)
+			}
+		}
+
+		restorer := &archiveRestorer{tx: tx, ids: make(map[string]map[string]json.RawMessage)}
+		return restorer.restore(archive, result)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// validateDataArchive 檢查備份檔的格式版本、schema 版本與資料表
+func validateDataArchive(archive *models.DataArchive, schemaVersion int64) error {
+	manifest := archive.Manifest
+	if manifest.Application != models.DataArchiveApplication {
+		return fmt.Errorf("%w: not an %s archive", ErrDataArchiveIncompatible, models.DataArchiveApplication)
+	}
+	if manifest.FormatVersion < 1 || manifest.FormatVersion > models.DataArchiveFormatVersion {
+		return fmt.Errorf("%w: unsupported archive format version %d", ErrDataArchiveIncompatible, manifest.FormatVersion)
+	}
+	if manifest.SchemaVersion != schemaVersion {
+		return fmt.Errorf("%w: archive schema version %d does not match database schema version %d (run migrations to the same version first)",
+			ErrDataArchiveIncompatible, manifest.SchemaVersion, schemaVersion)
+	}
+
+	known := make(map[string]bool, len(dataArchiveTables))
+	for _, table := range dataArchiveTables {
+		known[table.name] = true
+	}
+	for name := range archive.Tables {
+		if !known[name] {
+			return fmt.Errorf("%w: unknown table %s", ErrDataArchiveIncompatible, name)
+		}
+	}
+	return nil
+}
+
+// archiveRestorer 還原過程的狀態（各資料表舊 id 對應新 id）
+type archiveRestorer struct {
+	tx  repository.DataRestoreTx
+	ids map[string]map[string]json.RawMessage // 資料表 → 舊 id（JSON 字面值）→ 新 id
+}
+
+// restore 依資料表順序還原所有資料
+func (r *archiveRestorer) restore(archive *models.DataArchive, result *models.DataRestoreResult) error {
+	// 先為所有 UUID 主鍵的資料產生新 id，讓多型參照欄位不受資料表順序限制
+	for _, table := range dataArchiveTables {
+		if table.idKind != archiveIDUUID || table.matchColumns != nil {
+			continue
+		}
+		for index, row := range archive.Tables[table.name] {
+			fields, err := decodeArchiveRow(table.name, index, row)
+			if err != nil {
+				return err
+			}
+			id, ok := fields["id"]
+			if !ok {
+				return fmt.Errorf("%s row %d has no id", table.name, index+1)
+			}
+			newID, _ := json.Marshal(uuid.New())
+			r.mapID(table.name, id, newID)
+		}
+	}
+
+	for _, table := range dataArchiveTables {
+		tableResult := models.DataRestoreTableResult{Name: table.name}
+		for index, row := range archive.Tables[table.name] {
+			merged, err := r.restoreRow(table, index, row)
+			if err != nil {
+				return err
+			}
+			if merged {
+				tableResult.Merged++
+			} else {
+				tableResult.Inserted++
+			}
+		}
+		result.Tables = append(result.Tables, tableResult)
+		result.Inserted += tableResult.Inserted
+		result.Merged += tableResult.Merged
+	}
+	return nil
+}
+
+// restoreRow 改寫單筆資料的 id 與參照欄位後寫入，回傳是否對應到既有資料
+func (r *archiveRestorer) restoreRow(table archiveTable, index int, row json.RawMessage) (bool, error) {
+	fields, err := decodeArchiveRow(table.name, index, row)
+	if err != nil {
+		return false, err
+	}
+	oldID := fields["id"]
+
+	for column, target := range table.refs {
+		value, ok := fields[column]
+		if !ok || isJSONNull(value) {
+			continue
+		}
+		newID, ok := r.ids[target][archiveIDKey(value)]
+		if !ok {
+			return false, fmt.Errorf("%s row %d: %s references missing %s %s", table.name, index+1, column, target, value)
+		}
+		fields[column] = newID
+	}
+	for _, column := range table.softRefs {
+		value, ok := fields[column]
+		if !ok || isJSONNull(value) {
+			continue
+		}
+		if newID, ok := r.lookupAnyID(value); ok {
+			fields[column] = newID
+		}
+	}
+
+	if table.matchColumns != nil {
+		encoded, err := json.Marshal(fields)
+		if err != nil {
+			return false, fmt.Errorf("failed to encode %s row %d: %w", table.name, index+1, err)
+		}
+		existingID, found, err := r.tx.FindExisting(table.name, table.matchColumns, encoded)
+		if err != nil {
+			return false, err
+		}
+		if found {
+			if err := r.tx.UpdateRow(table.name, table.matchColumns, table.updateColumns, encoded); err != nil {
+				return false, err
+			}
+			if table.idKind != archiveIDNone {
+				r.mapID(table.name, oldID, existingID)
+			}
+			return true, nil
+		}
+	}
+
+	switch table.idKind {
+	case archiveIDUUID:
+		newID, ok := r.ids[table.name][archiveIDKey(oldID)]
+		if !ok {
+			newID, _ = json.Marshal(uuid.New())
+		}
+		fields["id"] = newID
+	case archiveIDSerial:
+		delete(fields, "id")
+	}
+
+	encoded, err := json.Marshal(fields)
+	if err != nil {
+		return false, fmt.Errorf("failed to encode %s row %d: %w", table.name, index+1, err)
+	}
+	newID, err := r.tx.InsertRow(table.name, encoded)
+	if err != nil {
+		return false, fmt.Errorf("failed to restore %s row %d: %w", table.name, index+1, err)
+	}
+	if table.idKind != archiveIDNone {
+		r.mapID(table.name, oldID, newID)
+	}
+	return false, nil
+}
+
+// mapID 記錄舊 id 對應的新 id
+func (r *archiveRestorer) mapID(table string, oldID, newID json.RawMessage) {
+	if oldID == nil {
+		return
+	}
+	if r.ids[table] == nil {
+		r.ids[table] = make(map[string]json.RawMessage)
+	}
+	r.ids[table][archiveIDKey(oldID)] = newID
+}
+
+// lookupAnyID 在所有資料表中尋找舊 id 對應的新 id（UUID 在各資料表間不會重複）
+func (r *archiveRestorer) lookupAnyID(oldID json.RawMessage) (json.RawMessage, bool) {
+	key := archiveIDKey(oldID)
+	for _, table := range dataArchiveTables {
+		if table.idKind != archiveIDUUID {
+			continue
+		}
+		if newID, ok := r.ids[table.name][key]; ok {
+			return newID, true
+		}
+	}
+	return nil, false
+}
+
+// decodeArchiveRow 解析單筆備份資料
+func decodeArchiveRow(table string, index int, row json.RawMessage) (map[string]json.RawMessage, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(row, &fields); err != nil {
+		return nil, fmt.Errorf("failed to decode %s row %d: %w", table, index+1, err)
+	}
+	return fields, nil
+}
+
+// archiveIDKey 將 id 的 JSON 字面值正規化為對應表的鍵
+func archiveIDKey(id json.RawMessage) string {
+	return string(bytes.TrimSpace(id))
+}
+
+// isJSONNull 判斷 JSON 值是否為 null
+func isJSONNull(value json.RawMessage) bool {
+	return archiveIDKey(value) == "null"
+}
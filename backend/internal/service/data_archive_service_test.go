@@ -0,0 +1,370 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/chienchuanw/asset-manager/internal/models"
+	"github.com/chienchuanw/asset-manager/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDataArchiveRepository 測試用資料備份 repository（以記憶體中的資料表模擬）
+type fakeDataArchiveRepository struct {
+	schemaVersion int64
+	dirty         bool
+	tables        map[string][]map[string]json.RawMessage
+	nextSerial    int
+}
+
+func newFakeDataArchiveRepository(schemaVersion int64) *fakeDataArchiveRepository {
+	return &fakeDataArchiveRepository{
+		schemaVersion: schemaVersion,
+		tables:        make(map[string][]map[string]json.RawMessage),
+		nextSerial:    100,
+	}
+}
+
+func (r *fakeDataArchiveRepository) GetSchemaVersion() (int64, bool, error) {
+	return r.schemaVersion, r.dirty, nil
+}
+
+func (r *fakeDataArchiveRepository) ExportTable(table, orderBy string) ([]json.RawMessage, error) {
+	rows := []json.RawMessage{}
+	for _, fields := range r.tables[table] {
+		encoded, err := json.Marshal(fields)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, encoded)
+	}
+	return rows, nil
+}
+
+func (r *fakeDataArchiveRepository) Restore(fn func(tx repository.DataRestoreTx) error) error {
+	// 模擬事務：失敗時還原為原本的資料
+	snapshot := make(map[string][]map[string]json.RawMessage, len(r.tables))
+	for table, rows := range r.tables {
+		snapshot[table] = append([]map[string]json.RawMessage(nil), rows...)
+	}
+	if err := fn(r); err != nil {
+		r.tables = snapshot
+		return err
+	}
+	return nil
+}
+
+func (r *fakeDataArchiveRepository) CountRows(table string) (int, error) {
+	return len(r.tables[table]), nil
+}
+
+func (r *fakeDataArchiveRepository) find(table string, keyColumns []string, row json.RawMessage) (map[string]json.RawMessage, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(row, &fields); err != nil {
+		return nil, err
+	}
+	for _, existing := range r.tables[table] {
+		matched := true
+		for _, column := range keyColumns {
+			if string(existing[column]) != string(fields[column]) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return existing, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeDataArchiveRepository) FindExisting(table string, keyColumns []string, row json.RawMessage) (json.RawMessage, bool, error) {
+	existing, err := r.find(table, keyColumns, row)
+	if err != nil || existing == nil {
+		return nil, false, err
+	}
+	if id, ok := existing["id"]; ok {
+		return id, true, nil
+	}
+	return json.RawMessage("null"), true, nil
+}
+
+func (r *fakeDataArchiveRepository) InsertRow(table string, row json.RawMessage) (json.RawMessage, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(row, &fields); err != nil {
+		return nil, err
+	}
+	if table == "exchange_rates" {
+		r.nextSerial++
+		fields["id"] = json.RawMessage(fmt.Sprint(r.nextSerial))
+	}
+	r.tables[table] = append(r.tables[table], fields)
+	if id, ok := fields["id"]; ok {
+		return id, nil
+	}
+	return json.RawMessage("null"), nil
+}
+
+func (r *fakeDataArchiveRepository) UpdateRow(table string, keyColumns, columns []string, row json.RawMessage) error {
+	existing, err := r.find(table, keyColumns, row)
+	if err != nil || existing == nil {
+		return err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(row, &fields); err != nil {
+		return err
+	}
+	for _, column := range columns {
+		existing[column] = fields[column]
+	}
+	return nil
+}
+
+// archiveRow 建立測試用的備份資料列
+func archiveRow(t *testing.T, fields map[string]interface{}) json.RawMessage {
+	t.Helper()
+	encoded, err := json.Marshal(fields)
+	require.NoError(t, err)
+	return encoded
+}
+
+// testDataArchive 建立測試用備份檔
+func testDataArchive(t *testing.T, schemaVersion int64) *models.DataArchive {
+	t.Helper()
+	return &models.DataArchive{
+		Manifest: models.DataArchiveManifest{
+			Application:   models.DataArchiveApplication,
+			FormatVersion: models.DataArchiveFormatVersion,
+			SchemaVersion: schemaVersion,
+		},
+		Tables: map[string][]json.RawMessage{
+			"settings": {
+				archiveRow(t, map[string]interface{}{"id": "00000000-0000-0000-0000-0000000000a1", "key": "discord_webhook", "value": "https://example.com/hook"}),
+			},
+			"cash_flow_categories": {
+				archiveRow(t, map[string]interface{}{"id": "00000000-0000-0000-0000-0000000000c1", "name": "薪資", "type": "income", "is_system": true}),
+				archiveRow(t, map[string]interface{}{"id": "00000000-0000-0000-0000-0000000000c2", "name": "寵物", "type": "expense", "is_system": false}),
+			},
+			"exchange_rates": {
+				archiveRow(t, map[string]interface{}{"id": 7, "from_currency": "USD", "to_currency": "TWD", "rate": 31.5, "date": "2024-05-01"}),
+			},
+			"bank_accounts": {
+				archiveRow(t, map[string]interface{}{"id": "00000000-0000-0000-0000-0000000000b1", "bank_name": "玉山銀行"}),
+			},
+			"subscriptions": {
+				archiveRow(t, map[string]interface{}{"id": "00000000-0000-0000-0000-0000000000d1", "name": "Netflix", "category_id": "00000000-0000-0000-0000-0000000000c2", "account_id": "00000000-0000-0000-0000-0000000000b1"}),
+			},
+			"cash_flows": {
+				archiveRow(t, map[string]interface{}{"id": "00000000-0000-0000-0000-0000000000e1", "category_id": "00000000-0000-0000-0000-0000000000c1", "source_type": "manual", "source_id": nil}),
+				archiveRow(t, map[string]interface{}{"id": "00000000-0000-0000-0000-0000000000e2", "category_id": "00000000-0000-0000-0000-0000000000c2", "source_type": "subscription", "source_id": "00000000-0000-0000-0000-0000000000d1"}),
+			},
+			"transactions": {
+				archiveRow(t, map[string]interface{}{"id": "00000000-0000-0000-0000-0000000000f1", "symbol": "AAPL", "exchange_rate_id": 7, "brokerage_account_id": nil}),
+			},
+			"realized_profits": {
+				archiveRow(t, map[string]interface{}{"id": "00000000-0000-0000-0000-0000000000f2", "transaction_id": "00000000-0000-0000-0000-0000000000f1"}),
+			},
+		},
+	}
+}
+
+func TestDataArchiveTables_ReferencesPointToEarlierTables(t *testing.T) {
+	seen := make(map[string]archiveIDKind)
+	for _, table := range dataArchiveTables {
+		for column, target := range table.refs {
+			_, ok := seen[target]
+			assert.True(t, ok, "%s.%s references %s which is restored later", table.name, column, target)
+		}
+		_, duplicate := seen[table.name]
+		assert.False(t, duplicate, "table %s listed twice", table.name)
+		seen[table.name] = table.idKind
+	}
+}
+
+func TestDataArchiveService_WriteReadRoundTrip(t *testing.T) {
+	repo := newFakeDataArchiveRepository(45)
+	repo.tables["bank_accounts"] = []map[string]json.RawMessage{
+		{"id": json.RawMessage(`"00000000-0000-0000-0000-0000000000b1"`), "balance": json.RawMessage(`12345.67`)},
+	}
+	service := NewDataArchiveService(repo)
+
+	archive, err := service.Export()
+	require.NoError(t, err)
+	assert.Equal(t, int64(45), archive.Manifest.SchemaVersion)
+	assert.Equal(t, models.DataArchiveFormatVersion, archive.Manifest.FormatVersion)
+	assert.Len(t, archive.Manifest.Tables, len(dataArchiveTables))
+
+	for _, format := range []models.DataArchiveFormat{models.DataArchiveFormatJSON, models.DataArchiveFormatZIP} {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, service.WriteArchive(&buf, archive, format))
+
+			decoded, err := service.ReadArchive(&buf)
+			require.NoError(t, err)
+			assert.Equal(t, archive.Manifest.SchemaVersion, decoded.Manifest.SchemaVersion)
+			assert.Equal(t, archive.Manifest.Tables, decoded.Manifest.Tables)
+			require.Len(t, decoded.Tables["bank_accounts"], 1)
+			assert.JSONEq(t, `{"id":"00000000-0000-0000-0000-0000000000b1","balance":12345.67}`, string(decoded.Tables["bank_accounts"][0]))
+		})
+	}
+}
+
+func TestDataArchiveService_ReadArchive_RejectsRowCountMismatch(t *testing.T) {
+	service := NewDataArchiveService(newFakeDataArchiveRepository(45))
+	data := `{"manifest":{"application":"asset-manager","format_version":1,"schema_version":45,"tables":[{"name":"bank_accounts","rows":2}]},"tables":{"bank_accounts":[{"id":"x"}]}}`
+
+	_, err := service.ReadArchive(bytes.NewBufferString(data))
+	assert.Error(t, err)
+}
+
+func TestDataArchiveService_ReadArchive_RejectsOversizedArchive(t *testing.T) {
+	repo := newFakeDataArchiveRepository(45)
+	archive, err := NewDataArchiveService(repo).Export()
+	require.NoError(t, err)
+
+	for _, format := range []models.DataArchiveFormat{models.DataArchiveFormatJSON, models.DataArchiveFormatZIP} {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, NewDataArchiveService(repo).WriteArchive(&buf, archive, format))
+
+			// 上限恰好等於檔案大小時可以讀取
+			service := &dataArchiveService{repo: repo, maxSize: int64(buf.Len())}
+			_, err := service.ReadArchive(bytes.NewReader(buf.Bytes()))
+			require.NoError(t, err)
+
+			service.maxSize = int64(buf.Len()) - 1
+			_, err = service.ReadArchive(bytes.NewReader(buf.Bytes()))
+			assert.ErrorIs(t, err, ErrDataArchiveTooLarge)
+		})
+	}
+}
+
+func TestDataArchiveService_ReadArchive_RejectsOversizedZIPEntry(t *testing.T) {
+	// 解壓縮後的 manifest 遠大於壓縮後的檔案
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.Create(dataArchiveManifestFile)
+	require.NoError(t, err)
+	_, err = w.Write([]byte(`{"application":"asset-manager","padding":"` + strings.Repeat(" ", 64<<10) + `"}`))
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+
+	service := &dataArchiveService{repo: newFakeDataArchiveRepository(45), maxSize: 16 << 10}
+	require.Less(t, buf.Len(), 16<<10)
+
+	_, err = service.ReadArchive(&buf)
+	assert.ErrorIs(t, err, ErrDataArchiveTooLarge)
+}
+
+func TestValidateDataArchive(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(archive *models.DataArchive)
+		valid  bool
+	}{
+		{name: "matching versions", modify: func(archive *models.DataArchive) {}, valid: true},
+		{name: "other application", modify: func(archive *models.DataArchive) { archive.Manifest.Application = "other" }},
+		{name: "newer format version", modify: func(archive *models.DataArchive) {
+			archive.Manifest.FormatVersion = models.DataArchiveFormatVersion + 1
+		}},
+		{name: "older schema version", modify: func(archive *models.DataArchive) { archive.Manifest.SchemaVersion = 44 }},
+		{name: "unknown table", modify: func(archive *models.DataArchive) { archive.Tables["unknown"] = nil }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			archive := testDataArchive(t, 45)
+			tt.modify(archive)
+			err := validateDataArchive(archive, 45)
+			if tt.valid {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, ErrDataArchiveIncompatible)
+		})
+	}
+}
+
+func TestDataArchiveService_Restore_RemapsIDs(t *testing.T) {
+	repo := newFakeDataArchiveRepository(45)
+	// migration 建立的預設資料
+	repo.tables["settings"] = []map[string]json.RawMessage{
+		{"id": json.RawMessage(`"00000000-0000-0000-0000-000000000a99"`), "key": json.RawMessage(`"discord_webhook"`), "value": json.RawMessage(`""`)},
+	}
+	repo.tables["cash_flow_categories"] = []map[string]json.RawMessage{
+		{"id": json.RawMessage(`"00000000-0000-0000-0000-000000000c99"`), "name": json.RawMessage(`"薪資"`), "type": json.RawMessage(`"income"`), "is_system": json.RawMessage(`true`)},
+	}
+	service := NewDataArchiveService(repo)
+
+	result, err := service.Restore(testDataArchive(t, 45))
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Merged)
+	assert.Equal(t, 8, result.Inserted)
+
+	// 設定以備份值覆寫
+	require.Len(t, repo.tables["settings"], 1)
+	assert.Equal(t, `"https://example.com/hook"`, string(repo.tables["settings"][0]["value"]))
+
+	// 同名分類對應到既有分類，新分類產生新 id
+	require.Len(t, repo.tables["cash_flow_categories"], 2)
+	seededCategory := repo.tables["cash_flow_categories"][0]["id"]
+	newCategory := repo.tables["cash_flow_categories"][1]["id"]
+	assert.NotEqual(t, `"00000000-0000-0000-0000-0000000000c2"`, string(newCategory))
+
+	bankAccountID := repo.tables["bank_accounts"][0]["id"]
+	assert.NotEqual(t, `"00000000-0000-0000-0000-0000000000b1"`, string(bankAccountID))
+
+	subscription := repo.tables["subscriptions"][0]
+	assert.Equal(t, string(newCategory), string(subscription["category_id"]))
+	assert.Equal(t, string(bankAccountID), string(subscription["account_id"]))
+
+	cashFlows := repo.tables["cash_flows"]
+	require.Len(t, cashFlows, 2)
+	assert.Equal(t, string(seededCategory), string(cashFlows[0]["category_id"]))
+	assert.Equal(t, "null", string(cashFlows[0]["source_id"]))
+	assert.Equal(t, string(subscription["id"]), string(cashFlows[1]["source_id"]))
+
+	// SERIAL 主鍵由資料庫產生，參照欄位改寫為新 id
+	require.Len(t, repo.tables["exchange_rates"], 1)
+	assert.Equal(t, "101", string(repo.tables["exchange_rates"][0]["id"]))
+	transaction := repo.tables["transactions"][0]
+	assert.Equal(t, "101", string(transaction["exchange_rate_id"]))
+	assert.Equal(t, string(transaction["id"]), string(repo.tables["realized_profits"][0]["transaction_id"]))
+}
+
+func TestDataArchiveService_Restore_RejectsNonEmptyDatabase(t *testing.T) {
+	repo := newFakeDataArchiveRepository(45)
+	repo.tables["transactions"] = []map[string]json.RawMessage{
+		{"id": json.RawMessage(`"00000000-0000-0000-0000-000000000001"`)},
+	}
+	service := NewDataArchiveService(repo)
+
+	_, err := service.Restore(testDataArchive(t, 45))
+	assert.ErrorIs(t, err, ErrDataRestoreNotEmpty)
+	assert.Empty(t, repo.tables["cash_flows"])
+}
+
+func TestDataArchiveService_Restore_RejectsSchemaMismatch(t *testing.T) {
+	service := NewDataArchiveService(newFakeDataArchiveRepository(46))
+
+	_, err := service.Restore(testDataArchive(t, 45))
+	assert.ErrorIs(t, err, ErrDataArchiveIncompatible)
+}
+
+func TestDataArchiveService_Restore_RejectsMissingReference(t *testing.T) {
+	repo := newFakeDataArchiveRepository(45)
+	archive := testDataArchive(t, 45)
+	archive.Tables["realized_profits"] = []json.RawMessage{
+		archiveRow(t, map[string]interface{}{"id": "00000000-0000-0000-0000-0000000000f3", "transaction_id": "00000000-0000-0000-0000-000000000404"}),
+	}
+
+	_, err := NewDataArchiveService(repo).Restore(archive)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "references missing transactions")
+	assert.Empty(t, repo.tables["transactions"])
+}
@@ -27,14 +27,17 @@ type DiscordService interface {
 	// SendInstallmentCompletionNotification 發送分期完成通知
 	SendInstallmentCompletionNotification(webhookURL string, installments []*models.Installment, remainingCount int) error
 
-	// SendCreditCardPaymentReminder 發送信用卡繳款提醒
-	SendCreditCardPaymentReminder(webhookURL string, creditCards []*models.CreditCard) error
+	// SendCreditCardPaymentReminder 發送信用卡繳款提醒（帳單需帶入 CreditCard）
+	SendCreditCardPaymentReminder(webhookURL string, statements []*models.CreditCardStatement) error
 
 	// FormatMonthlyCashFlowReport 格式化月度現金流報告訊息
 	FormatMonthlyCashFlowReport(summary *models.MonthlyCashFlowSummary) *models.DiscordMessage
 
 	// FormatYearlyCashFlowReport 格式化年度現金流報告訊息
 	FormatYearlyCashFlowReport(summary *models.YearlyCashFlowSummary) *models.DiscordMessage
+
+	// FormatCashFlowForecast 格式化每週現金流預測摘要
+	FormatCashFlowForecast(forecast *models.CashFlowForecast) *models.DiscordMessage
 }
 
 // discordService Discord 服務實作
@@ -327,7 +330,7 @@ func (s *discordService) SendDailyBillingNotification(webhookURL string, result
 	}
 
 	// 如果沒有任何扣款，不發送通知
-	if result.SubscriptionCount == 0 && result.InstallmentCount == 0 {
+	if result.SubscriptionCount == 0 && result.InstallmentCount == 0 && result.LoanCount == 0 && result.RecurringCount == 0 {
 		return nil
 	}
 
@@ -346,9 +349,11 @@ func (s *discordService) SendDailyBillingNotification(webhookURL string, result
 	// 總覽
 	embed.Fields = append(embed.Fields, models.DiscordEmbedField{
 		Name: "📊 扣款總覽",
-		Value: fmt.Sprintf("訂閱扣款：%d 筆\n分期扣款：%d 筆\n總金額：NT$ %.2f",
+		Value: fmt.Sprintf("訂閱扣款：%d 筆\n分期扣款：%d 筆\n貸款扣款：%d 筆\n定期收支：%d 筆\n總金額：NT$ %.2f",
 			result.SubscriptionCount,
 			result.InstallmentCount,
+			result.LoanCount,
+			result.RecurringCount,
 			result.TotalAmount,
 		),
 		Inline: false,
@@ -388,8 +393,48 @@ func (s *discordService) SendDailyBillingNotification(webhookURL string, result
 		})
 	}
 
+	// 貸款扣款詳情
+	if result.LoanCount > 0 && result.LoanResult != nil && len(result.LoanResult.CreatedCashFlows) > 0 {
+		loanText := ""
+		for i, cf := range result.LoanResult.CreatedCashFlows {
+			if i >= 5 { // 最多顯示 5 筆
+				loanText += fmt.Sprintf("...及其他 %d 筆\n", len(result.LoanResult.CreatedCashFlows)-5)
+				break
+			}
+			loanText += fmt.Sprintf("• %s - NT$ %.2f\n", cf.Description, cf.Amount)
+		}
+		embed.Fields = append(embed.Fields, models.DiscordEmbedField{
+			Name:   "🏦 貸款扣款",
+			Value:  loanText,
+			Inline: false,
+		})
+	}
+
+	// 定期收支詳情
+	if result.RecurringCount > 0 && result.RecurringResult != nil && len(result.RecurringResult.CreatedCashFlows) > 0 {
+		recurringText := ""
+		for i, cf := range result.RecurringResult.CreatedCashFlows {
+			if i >= 5 { // 最多顯示 5 筆
+				recurringText += fmt.Sprintf("...及其他 %d 筆\n", len(result.RecurringResult.CreatedCashFlows)-5)
+				break
+			}
+			recurringText += fmt.Sprintf("• %s - NT$ %.2f\n", cf.Description, cf.Amount)
+		}
+		embed.Fields = append(embed.Fields, models.DiscordEmbedField{
+			Name:   "🔁 定期收支",
+			Value:  recurringText,
+			Inline: false,
+		})
+	}
+
 	// 錯誤訊息（如果有）
 	totalErrors := len(result.SubscriptionResult.Errors) + len(result.InstallmentResult.Errors)
+	if result.LoanResult != nil {
+		totalErrors += len(result.LoanResult.Errors)
+	}
+	if result.RecurringResult != nil {
+		totalErrors += len(result.RecurringResult.Errors)
+	}
 	if totalErrors > 0 {
 		errorText := fmt.Sprintf("⚠️ 有 %d 筆扣款失敗，請檢查系統日誌", totalErrors)
 		embed.Fields = append(embed.Fields, models.DiscordEmbedField{
@@ -503,8 +548,8 @@ func (s *discordService) SendInstallmentCompletionNotification(webhookURL string
 }
 
 // SendCreditCardPaymentReminder 發送信用卡繳款提醒
-func (s *discordService) SendCreditCardPaymentReminder(webhookURL string, creditCards []*models.CreditCard) error {
-	if len(creditCards) == 0 {
+func (s *discordService) SendCreditCardPaymentReminder(webhookURL string, statements []*models.CreditCardStatement) error {
+	if len(statements) == 0 {
 		return nil
 	}
 
@@ -520,30 +565,34 @@ func (s *discordService) SendCreditCardPaymentReminder(webhookURL string, credit
 		},
 	}
 
-	// 信用卡列表
+	// 信用卡帳單列表
 	cardText := ""
-	for i, card := range creditCards {
+	for i, statement := range statements {
 		if i >= 10 { // 最多顯示 10 張
-			cardText += fmt.Sprintf("...及其他 %d 張信用卡\n", len(creditCards)-10)
+			cardText += fmt.Sprintf("...及其他 %d 張信用卡\n", len(statements)-10)
 			break
 		}
 
-		availableCredit := card.CreditLimit - card.UsedCredit
-		utilizationRate := (card.UsedCredit / card.CreditLimit) * 100
+		card := statement.CreditCard
+		if card == nil {
+			continue
+		}
 
 		cardText += fmt.Sprintf(
 			"💳 **%s %s** (****%s)\n"+
-				"   繳款日: 每月 %d 號\n"+
-				"   目前已使用額度: NT$ %s\n"+
-				"   可用額度: NT$ %s\n"+
-				"   使用率: %.1f%%\n\n",
+				"   繳款截止日: %s\n"+
+				"   本期應繳總額: NT$ %s\n"+
+				"   最低應繳金額: NT$ %s\n"+
+				"   已繳金額: NT$ %s\n"+
+				"   尚未繳納: NT$ %s\n\n",
 			card.IssuingBank,
 			card.CardName,
 			card.CardNumberLast4,
-			card.PaymentDueDay,
-			formatCurrency(card.UsedCredit),
-			formatCurrency(availableCredit),
-			utilizationRate,
+			statement.DueDate.Format("2006-01-02"),
+			formatCurrency(statement.ClosingBalance),
+			formatCurrency(statement.MinimumPayment),
+			formatCurrency(statement.PaidAmount),
+			formatCurrency(statement.OutstandingAmount()),
 		)
 	}
 
@@ -773,3 +822,68 @@ func (s *discordService) FormatYearlyCashFlowReport(summary *models.YearlyCashFl
 	}
 }
 
+
+// FormatCashFlowForecast 格式化每週現金流預測摘要
+func (s *discordService) FormatCashFlowForecast(forecast *models.CashFlowForecast) *models.DiscordMessage {
+	// 建立標題
+	title := fmt.Sprintf("🔮 【未來 %d 個月現金流預測】%s ~ %s",
+		forecast.Months,
+		forecast.StartDate.Format("2006-01-02"),
+		forecast.EndDate.Format("2006-01-02"))
+
+	// 總流動性
+	content := fmt.Sprintf("\n💧 目前流動性：NT$ %s\n", formatCurrency(forecast.StartingLiquidity))
+	content += fmt.Sprintf("📅 期末預測：NT$ %s\n", formatCurrency(forecast.EndingLiquidity))
+	content += fmt.Sprintf("📉 最低點：NT$ %s（%s）\n",
+		formatCurrency(forecast.LowestLiquidity),
+		forecast.LowestLiquidityDate.Format("01/02"))
+	content += fmt.Sprintf("\n💰 排程流入：NT$ %s\n", formatCurrency(forecast.ScheduledInflow))
+	content += fmt.Sprintf("💸 排程流出：NT$ %s\n", formatCurrency(forecast.ScheduledOutflow))
+	content += fmt.Sprintf("🛒 平均每月其他支出：NT$ %s\n", formatCurrency(forecast.AverageMonthlySpend))
+
+	// 各帳戶預測
+	if len(forecast.Accounts) > 0 {
+		content += "\n🏦 帳戶預測：\n"
+		for _, account := range forecast.Accounts {
+			content += fmt.Sprintf("  • %s：NT$ %s → NT$ %s（最低 NT$ %s）\n",
+				account.AccountName(),
+				formatCurrency(account.StartingBalance),
+				formatCurrency(account.EndingBalance),
+				formatCurrency(account.LowestBalance))
+		}
+	}
+
+	// 低餘額警示
+	if len(forecast.Warnings) > 0 {
+		content += fmt.Sprintf("\n⚠️ 低於 NT$ %s 的帳戶：\n", formatCurrency(forecast.Threshold))
+		for _, warning := range forecast.Warnings {
+			content += fmt.Sprintf("  • %s：%s 起低於門檻，最低 NT$ %s（%s）\n",
+				warning.AccountName,
+				warning.Date.Format("01/02"),
+				formatCurrency(warning.LowestBalance),
+				warning.LowestBalanceDate.Format("01/02"))
+		}
+	} else {
+		content += "\n✅ 預測期間內所有帳戶餘額皆高於門檻\n"
+	}
+
+	// 未來 7 天的排程
+	weekEnd := forecast.StartDate.AddDate(0, 0, 7)
+	upcoming := ""
+	for _, event := range forecast.Events {
+		if event.Date.After(weekEnd) {
+			break
+		}
+		upcoming += fmt.Sprintf("  • %s %s：NT$ %s\n",
+			event.Date.Format("01/02"),
+			event.Name,
+			formatCurrency(event.Amount))
+	}
+	if upcoming != "" {
+		content += "\n🗓️ 未來 7 天排程：\n" + upcoming
+	}
+
+	return &models.DiscordMessage{
+		Content: title + content,
+	}
+}
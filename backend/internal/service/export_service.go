@@ -0,0 +1,385 @@
+package service
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/chienchuanw/asset-manager/internal/i18n"
+	"github.com/chienchuanw/asset-manager/internal/models"
+	"github.com/chienchuanw/asset-manager/internal/repository"
+	"github.com/google/uuid"
+)
+
+// ExportService 報表匯出服務介面（CSV / XLSX，標題依語系翻譯）
+type ExportService interface {
+	ExportCashFlows(filters repository.CashFlowFilters, locale i18n.Locale) (*models.ExportSheet, error)
+	ExportMonthlySummary(filters repository.CashFlowFilters, locale i18n.Locale) (*models.ExportSheet, error)
+	ExportTransactions(filters repository.TransactionFilters, locale i18n.Locale) (*models.ExportSheet, error)
+	ExportRealizedProfits(filters models.RealizedProfitFilters, locale i18n.Locale) (*models.ExportSheet, error)
+	WriteSheet(w io.Writer, sheet *models.ExportSheet, format models.ExportFormat) error
+}
+
+// exportService 報表匯出服務實作
+type exportService struct {
+	cashFlowService      CashFlowService
+	transactionService   TransactionService
+	realizedProfitRepo   repository.RealizedProfitRepository
+	bankAccountRepo      repository.BankAccountRepository
+	creditCardRepo       repository.CreditCardRepository
+	brokerageAccountRepo repository.BrokerageAccountRepository
+	custodyAccountRepo   repository.CustodyAccountRepository
+}
+
+// NewExportService 建立新的報表匯出 service
+// 現金流與交易記錄透過 ListCashFlows / ListTransactions 取得，篩選條件與列表 API 相同
+func NewExportService(
+	cashFlowService CashFlowService,
+	transactionService TransactionService,
+	realizedProfitRepo repository.RealizedProfitRepository,
+	bankAccountRepo repository.BankAccountRepository,
+	creditCardRepo repository.CreditCardRepository,
+	brokerageAccountRepo repository.BrokerageAccountRepository,
+	custodyAccountRepo repository.CustodyAccountRepository,
+) ExportService {
+	return &exportService{
+		cashFlowService:      cashFlowService,
+		transactionService:   transactionService,
+		realizedProfitRepo:   realizedProfitRepo,
+		bankAccountRepo:      bankAccountRepo,
+		creditCardRepo:       creditCardRepo,
+		brokerageAccountRepo: brokerageAccountRepo,
+		custodyAccountRepo:   custodyAccountRepo,
+	}
+}
+
+// ExportCashFlows 匯出現金流記錄（分類、銀行帳戶與信用卡顯示名稱）
+func (s *exportService) ExportCashFlows(filters repository.CashFlowFilters, locale i18n.Locale) (*models.ExportSheet, error) {
+	cashFlows, err := s.cashFlowService.ListCashFlows(filters)
+	if err != nil {
+		return nil, err
+	}
+
+	accounts, err := s.cashFlowAccountNames()
+	if err != nil {
+		return nil, err
+	}
+
+	return buildCashFlowSheet(cashFlows, accounts, locale), nil
+}
+
+// ExportMonthlySummary 匯出現金流月度摘要（依月份與幣別彙總篩選後的現金流）
+func (s *exportService) ExportMonthlySummary(filters repository.CashFlowFilters, locale i18n.Locale) (*models.ExportSheet, error) {
+	cashFlows, err := s.cashFlowService.ListCashFlows(filters)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildMonthlySummarySheet(cashFlows, locale), nil
+}
+
+// ExportTransactions 匯出交易記錄（券商與保管帳戶顯示名稱）
+func (s *exportService) ExportTransactions(filters repository.TransactionFilters, locale i18n.Locale) (*models.ExportSheet, error) {
+	transactions, err := s.transactionService.ListTransactions(filters)
+	if err != nil {
+		return nil, err
+	}
+
+	accounts := make(map[uuid.UUID]string)
+	brokerageAccounts, err := s.brokerageAccountRepo.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get brokerage accounts: %w", err)
+	}
+	for _, account := range brokerageAccounts {
+		accounts[account.ID] = account.Name
+	}
+	custodyAccounts, err := s.custodyAccountRepo.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get custody accounts: %w", err)
+	}
+	for _, account := range custodyAccounts {
+		accounts[account.ID] = account.Name
+	}
+
+	return buildTransactionSheet(transactions, accounts, locale), nil
+}
+
+// ExportRealizedProfits 匯出已實現損益
+func (s *exportService) ExportRealizedProfits(filters models.RealizedProfitFilters, locale i18n.Locale) (*models.ExportSheet, error) {
+	if filters.AssetType != nil && !filters.AssetType.Validate() {
+		return nil, fmt.Errorf("invalid asset type filter: %s", *filters.AssetType)
+	}
+
+	profits, err := s.realizedProfitRepo.GetAll(filters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get realized profits: %w", err)
+	}
+
+	return buildRealizedProfitSheet(profits, locale), nil
+}
+
+// WriteSheet 將表格寫出為 CSV 或 XLSX
+func (s *exportService) WriteSheet(w io.Writer, sheet *models.ExportSheet, format models.ExportFormat) error {
+	return writeExportSheet(w, sheet, format)
+}
+
+// cashFlowAccountNames 取得銀行帳戶與信用卡的顯示名稱
+func (s *exportService) cashFlowAccountNames() (map[uuid.UUID]string, error) {
+	names := make(map[uuid.UUID]string)
+
+	bankAccounts, err := s.bankAccountRepo.GetAll(nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bank accounts: %w", err)
+	}
+	for _, account := range bankAccounts {
+		names[account.ID] = exportAccountName(account.BankName, account.AccountNumberLast4)
+	}
+
+	creditCards, err := s.creditCardRepo.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get credit cards: %w", err)
+	}
+	for _, card := range creditCards {
+		names[card.ID] = exportAccountName(strings.TrimSpace(card.IssuingBank+" "+card.CardName), card.CardNumberLast4)
+	}
+
+	return names, nil
+}
+
+// exportAccountName 帳戶顯示名稱（附上末四碼）
+func exportAccountName(name, last4 string) string {
+	if last4 == "" {
+		return name
+	}
+	return fmt.Sprintf("%s (%s)", name, last4)
+}
+
+// buildCashFlowSheet 建立現金流表格（依日期由舊到新排列）
+func buildCashFlowSheet(cashFlows []*models.CashFlow, accounts map[uuid.UUID]string, locale i18n.Locale) *models.ExportSheet {
+	sheet := &models.ExportSheet{
+		Name: i18n.T(locale, "EXPORT_SHEET_CASH_FLOWS"),
+		Headers: exportHeaders(locale,
+			"EXPORT_COLUMN_DATE", "EXPORT_COLUMN_TYPE", "EXPORT_COLUMN_CATEGORY", "EXPORT_COLUMN_AMOUNT",
+			"EXPORT_COLUMN_CURRENCY", "EXPORT_COLUMN_DESCRIPTION", "EXPORT_COLUMN_ACCOUNT",
+			"EXPORT_COLUMN_TARGET_ACCOUNT", "EXPORT_COLUMN_NOTE"),
+	}
+
+	sorted := append([]*models.CashFlow(nil), cashFlows...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Date.Before(sorted[j].Date) })
+
+	for _, cf := range sorted {
+		category := ""
+		if cf.Category != nil {
+			category = cf.Category.Name
+		}
+		sheet.Rows = append(sheet.Rows, []interface{}{
+			cf.Date,
+			exportLabel(locale, "EXPORT_CASH_FLOW_TYPE_", string(cf.Type)),
+			category,
+			cf.Amount,
+			string(cf.Currency),
+			cf.Description,
+			cashFlowAccountLabel(cf.SourceType, cf.SourceID, accounts, locale),
+			cashFlowAccountLabel(cf.TargetType, cf.TargetID, accounts, locale),
+			exportOptionalString(cf.Note),
+		})
+	}
+	return sheet
+}
+
+// cashFlowAccountLabel 現金流來源或目標的顯示名稱（銀行帳戶與信用卡顯示名稱，其他來源顯示類型）
+func cashFlowAccountLabel(sourceType *models.SourceType, sourceID *uuid.UUID, accounts map[uuid.UUID]string, locale i18n.Locale) string {
+	if sourceType == nil {
+		return ""
+	}
+	if sourceID != nil && (*sourceType == models.SourceTypeBankAccount || *sourceType == models.SourceTypeCreditCard) {
+		if name, ok := accounts[*sourceID]; ok {
+			return name
+		}
+	}
+	return exportLabel(locale, "EXPORT_SOURCE_TYPE_", string(*sourceType))
+}
+
+// monthlySummaryRow 月度摘要的單一月份與幣別
+type monthlySummaryRow struct {
+	month, currency                          string
+	income, expense, transferIn, transferOut float64
+	incomeCount, expenseCount                int
+}
+
+// buildMonthlySummarySheet 建立月度摘要表格（依月份與幣別彙總，不同幣別分列不換算）
+func buildMonthlySummarySheet(cashFlows []*models.CashFlow, locale i18n.Locale) *models.ExportSheet {
+	sheet := &models.ExportSheet{
+		Name: i18n.T(locale, "EXPORT_SHEET_MONTHLY_SUMMARY"),
+		Headers: exportHeaders(locale,
+			"EXPORT_COLUMN_MONTH", "EXPORT_COLUMN_CURRENCY", "EXPORT_COLUMN_INCOME", "EXPORT_COLUMN_EXPENSE",
+			"EXPORT_COLUMN_NET_CASH_FLOW", "EXPORT_COLUMN_TRANSFER_IN", "EXPORT_COLUMN_TRANSFER_OUT",
+			"EXPORT_COLUMN_INCOME_COUNT", "EXPORT_COLUMN_EXPENSE_COUNT"),
+	}
+
+	summaries := make(map[string]*monthlySummaryRow)
+	for _, cf := range cashFlows {
+		month := cf.Date.Format("2006-01")
+		key := month + "|" + string(cf.Currency)
+		summary, ok := summaries[key]
+		if !ok {
+			summary = &monthlySummaryRow{month: month, currency: string(cf.Currency)}
+			summaries[key] = summary
+		}
+
+		switch cf.Type {
+		case models.CashFlowTypeIncome:
+			summary.income += cf.Amount
+			summary.incomeCount++
+		case models.CashFlowTypeExpense:
+			summary.expense += cf.Amount
+			summary.expenseCount++
+		case models.CashFlowTypeTransferIn:
+			summary.transferIn += cf.Amount
+		case models.CashFlowTypeTransferOut:
+			summary.transferOut += cf.Amount
+		}
+	}
+
+	rows := make([]*monthlySummaryRow, 0, len(summaries))
+	for _, summary := range summaries {
+		rows = append(rows, summary)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].month != rows[j].month {
+			return rows[i].month < rows[j].month
+		}
+		return rows[i].currency < rows[j].currency
+	})
+
+	for _, row := range rows {
+		sheet.Rows = append(sheet.Rows, []interface{}{
+			row.month,
+			row.currency,
+			roundToCents(row.income),
+			roundToCents(row.expense),
+			roundToCents(row.income - row.expense),
+			roundToCents(row.transferIn),
+			roundToCents(row.transferOut),
+			row.incomeCount,
+			row.expenseCount,
+		})
+	}
+	return sheet
+}
+
+// buildTransactionSheet 建立交易記錄表格（依日期由舊到新排列）
+func buildTransactionSheet(transactions []*models.Transaction, accounts map[uuid.UUID]string, locale i18n.Locale) *models.ExportSheet {
+	sheet := &models.ExportSheet{
+		Name: i18n.T(locale, "EXPORT_SHEET_TRANSACTIONS"),
+		Headers: exportHeaders(locale,
+			"EXPORT_COLUMN_DATE", "EXPORT_COLUMN_ASSET_TYPE", "EXPORT_COLUMN_SYMBOL", "EXPORT_COLUMN_NAME",
+			"EXPORT_COLUMN_TRANSACTION_TYPE", "EXPORT_COLUMN_QUANTITY", "EXPORT_COLUMN_PRICE", "EXPORT_COLUMN_AMOUNT",
+			"EXPORT_COLUMN_FEE", "EXPORT_COLUMN_TAX", "EXPORT_COLUMN_CURRENCY", "EXPORT_COLUMN_BROKERAGE_ACCOUNT",
+			"EXPORT_COLUMN_CUSTODY_ACCOUNT", "EXPORT_COLUMN_TO_CUSTODY_ACCOUNT", "EXPORT_COLUMN_NOTE"),
+	}
+
+	sorted := append([]*models.Transaction(nil), transactions...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Date.Before(sorted[j].Date) })
+
+	for _, tx := range sorted {
+		sheet.Rows = append(sheet.Rows, []interface{}{
+			tx.Date,
+			exportLabel(locale, "EXPORT_ASSET_TYPE_", string(tx.AssetType)),
+			tx.Symbol,
+			tx.Name,
+			exportLabel(locale, "EXPORT_TRANSACTION_TYPE_", string(tx.TransactionType)),
+			tx.Quantity,
+			tx.Price,
+			tx.Amount,
+			exportOptionalFloat(tx.Fee),
+			exportOptionalFloat(tx.Tax),
+			string(tx.Currency),
+			exportAccountLabel(tx.BrokerageAccountID, accounts),
+			exportAccountLabel(tx.CustodyAccountID, accounts),
+			exportAccountLabel(tx.ToCustodyAccountID, accounts),
+			exportOptionalString(tx.Note),
+		})
+	}
+	return sheet
+}
+
+// buildRealizedProfitSheet 建立已實現損益表格（依賣出日期由舊到新排列）
+func buildRealizedProfitSheet(profits []*models.RealizedProfit, locale i18n.Locale) *models.ExportSheet {
+	sheet := &models.ExportSheet{
+		Name: i18n.T(locale, "EXPORT_SHEET_REALIZED_PROFITS"),
+		Headers: exportHeaders(locale,
+			"EXPORT_COLUMN_SELL_DATE", "EXPORT_COLUMN_ASSET_TYPE", "EXPORT_COLUMN_SYMBOL", "EXPORT_COLUMN_QUANTITY",
+			"EXPORT_COLUMN_SELL_PRICE", "EXPORT_COLUMN_SELL_AMOUNT", "EXPORT_COLUMN_SELL_FEE", "EXPORT_COLUMN_COST_BASIS",
+			"EXPORT_COLUMN_REALIZED_PL", "EXPORT_COLUMN_REALIZED_PL_PCT", "EXPORT_COLUMN_CURRENCY"),
+	}
+
+	sorted := append([]*models.RealizedProfit(nil), profits...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].SellDate.Before(sorted[j].SellDate) })
+
+	for _, profit := range sorted {
+		sheet.Rows = append(sheet.Rows, []interface{}{
+			profit.SellDate,
+			exportLabel(locale, "EXPORT_ASSET_TYPE_", string(profit.AssetType)),
+			profit.Symbol,
+			profit.Quantity,
+			profit.SellPrice,
+			profit.SellAmount,
+			profit.SellFee,
+			profit.CostBasis,
+			profit.RealizedPL,
+			profit.RealizedPLPct,
+			profit.Currency,
+		})
+	}
+	return sheet
+}
+
+// exportHeaders 依語系翻譯表格標題
+func exportHeaders(locale i18n.Locale, keys ...string) []string {
+	headers := make([]string, len(keys))
+	for i, key := range keys {
+		headers[i] = i18n.T(locale, key)
+	}
+	return headers
+}
+
+// exportLabel 依語系翻譯代碼值（例如 transfer_in、tw-stock），沒有翻譯時保留原值
+func exportLabel(locale i18n.Locale, prefix, value string) string {
+	if value == "" {
+		return ""
+	}
+	key := prefix + strings.ToUpper(strings.ReplaceAll(value, "-", "_"))
+	if label := i18n.T(locale, key); label != key {
+		return label
+	}
+	return value
+}
+
+// exportAccountLabel 帳戶顯示名稱（找不到時顯示 ID）
+func exportAccountLabel(id *uuid.UUID, accounts map[uuid.UUID]string) interface{} {
+	if id == nil {
+		return nil
+	}
+	if name, ok := accounts[*id]; ok {
+		return name
+	}
+	return id.String()
+}
+
+// exportOptionalString 將可為空的文字轉為儲存格值
+func exportOptionalString(value *string) interface{} {
+	if value == nil {
+		return nil
+	}
+	return *value
+}
+
+// exportOptionalFloat 將可為空的數字轉為儲存格值
+func exportOptionalFloat(value *float64) interface{} {
+	if value == nil {
+		return nil
+	}
+	return *value
+}
@@ -0,0 +1,244 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chienchuanw/asset-manager/internal/i18n"
+	"github.com/chienchuanw/asset-manager/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func exportTestDate(day int) time.Time {
+	return time.Date(2025, 3, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestBuildCashFlowSheet_LocalizedAndResolved(t *testing.T) {
+	require.NoError(t, i18n.Init())
+
+	bankID := uuid.New()
+	cardID := uuid.New()
+	bank := models.SourceTypeBankAccount
+	card := models.SourceTypeCreditCard
+	cash := models.SourceTypeCash
+	note := "午餐"
+
+	cashFlows := []*models.CashFlow{
+		{
+			Date: exportTestDate(5), Type: models.CashFlowTypeExpense, Amount: 120, Currency: models.CurrencyTWD,
+			Description: "便當", SourceType: &card, SourceID: &cardID, Note: &note,
+			Category: &models.CashFlowCategory{Name: "飲食"},
+		},
+		{
+			Date: exportTestDate(1), Type: models.CashFlowTypeIncome, Amount: 50000, Currency: models.CurrencyTWD,
+			Description: "薪資", SourceType: &bank, SourceID: &bankID,
+			Category: &models.CashFlowCategory{Name: "薪資"},
+		},
+		{
+			Date: exportTestDate(3), Type: models.CashFlowTypeExpense, Amount: 80, Currency: models.CurrencyTWD,
+			Description: "飲料", SourceType: &cash,
+		},
+	}
+	accounts := map[uuid.UUID]string{
+		bankID: "台新銀行 (1234)",
+		cardID: "玉山卡 (5678)",
+	}
+
+	sheet := buildCashFlowSheet(cashFlows, accounts, i18n.LocaleZhTW)
+
+	assert.Equal(t, "現金流", sheet.Name)
+	assert.Equal(t, "日期", sheet.Headers[0])
+	assert.Equal(t, "類型", sheet.Headers[1])
+	require.Len(t, sheet.Rows, 3)
+
+	// 依日期由舊到新排列
+	assert.Equal(t, exportTestDate(1), sheet.Rows[0][0])
+	assert.Equal(t, "台新銀行 (1234)", sheet.Rows[0][6])
+	assert.Equal(t, "現金", sheet.Rows[1][6])
+	assert.Equal(t, "", sheet.Rows[1][2])
+	assert.Equal(t, "支出", sheet.Rows[2][1])
+	assert.Equal(t, "飲食", sheet.Rows[2][2])
+	assert.Equal(t, 120.0, sheet.Rows[2][3])
+	assert.Equal(t, "玉山卡 (5678)", sheet.Rows[2][6])
+	assert.Equal(t, "午餐", sheet.Rows[2][8])
+
+	english := buildCashFlowSheet(cashFlows, accounts, i18n.LocaleEn)
+	assert.Equal(t, "Cash Flows", english.Name)
+	assert.Equal(t, "Date", english.Headers[0])
+	assert.Equal(t, "Expense", english.Rows[2][1])
+}
+
+func TestBuildMonthlySummarySheet(t *testing.T) {
+	require.NoError(t, i18n.Init())
+
+	cashFlows := []*models.CashFlow{
+		{Date: time.Date(2025, 2, 10, 0, 0, 0, 0, time.UTC), Type: models.CashFlowTypeExpense, Amount: 300.1, Currency: models.CurrencyTWD},
+		{Date: time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC), Type: models.CashFlowTypeIncome, Amount: 1000, Currency: models.CurrencyTWD},
+		{Date: time.Date(2025, 1, 20, 0, 0, 0, 0, time.UTC), Type: models.CashFlowTypeExpense, Amount: 250.5, Currency: models.CurrencyTWD},
+		{Date: time.Date(2025, 1, 21, 0, 0, 0, 0, time.UTC), Type: models.CashFlowTypeTransferOut, Amount: 100, Currency: models.CurrencyTWD},
+		{Date: time.Date(2025, 1, 22, 0, 0, 0, 0, time.UTC), Type: models.CashFlowTypeIncome, Amount: 20, Currency: models.CurrencyUSD},
+	}
+
+	sheet := buildMonthlySummarySheet(cashFlows, i18n.LocaleEn)
+
+	require.Len(t, sheet.Rows, 3)
+	assert.Equal(t, []interface{}{"2025-01", "TWD", 1000.0, 250.5, 749.5, 0.0, 100.0, 1, 1}, sheet.Rows[0])
+	assert.Equal(t, []interface{}{"2025-01", "USD", 20.0, 0.0, 20.0, 0.0, 0.0, 1, 0}, sheet.Rows[1])
+	assert.Equal(t, []interface{}{"2025-02", "TWD", 0.0, 300.1, -300.1, 0.0, 0.0, 0, 1}, sheet.Rows[2])
+}
+
+func TestBuildTransactionSheet(t *testing.T) {
+	require.NoError(t, i18n.Init())
+
+	accountID := uuid.New()
+	fee := 20.0
+	transactions := []*models.Transaction{
+		{
+			Date: exportTestDate(2), AssetType: models.AssetTypeTWStock, Symbol: "2330", Name: "台積電",
+			TransactionType: models.TransactionTypeBuy, Quantity: 1000, Price: 600, Amount: 600000,
+			Fee: &fee, Currency: models.CurrencyTWD, BrokerageAccountID: &accountID,
+		},
+	}
+
+	sheet := buildTransactionSheet(transactions, map[uuid.UUID]string{accountID: "元大證券"}, i18n.LocaleZhTW)
+
+	require.Len(t, sheet.Rows, 1)
+	row := sheet.Rows[0]
+	assert.Equal(t, "台股", row[1])
+	assert.Equal(t, "買入", row[4])
+	assert.Equal(t, 20.0, row[8])
+	assert.Nil(t, row[9])
+	assert.Equal(t, "元大證券", row[11])
+	assert.Nil(t, row[12])
+}
+
+func TestWriteExportCSV(t *testing.T) {
+	sheet := &models.ExportSheet{
+		Name:    "Cash Flows",
+		Headers: []string{"Date", "Description", "Amount"},
+		Rows: [][]interface{}{
+			{exportTestDate(1), "Coffee, large", 125.5},
+			{exportTestDate(2), nil, 3},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, writeExportSheet(&buf, sheet, models.ExportFormatCSV))
+
+	assert.Equal(t, "\ufeffDate,Description,Amount\n2025-03-01,\"Coffee, large\",125.5\n2025-03-02,,3\n", buf.String())
+}
+
+func TestWriteExportXLSX(t *testing.T) {
+	sheet := &models.ExportSheet{
+		Name:    "現金流/報表",
+		Headers: []string{"日期", "說明", "金額"},
+		Rows: [][]interface{}{
+			{exportTestDate(1), "A & B", 125.5},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, writeExportSheet(&buf, sheet, models.ExportFormatXLSX))
+
+	reader, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+
+	files := make(map[string]string)
+	for _, file := range reader.File {
+		rc, err := file.Open()
+		require.NoError(t, err)
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		require.NoError(t, err)
+		files[file.Name] = string(data)
+	}
+
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/styles.xml", "xl/worksheets/sheet1.xml"} {
+		assert.Contains(t, files, name)
+	}
+	assert.Contains(t, files["xl/workbook.xml"], `name="現金流報表"`)
+
+	worksheet := files["xl/worksheets/sheet1.xml"]
+	assert.Contains(t, worksheet, `<c r="A1" s="2" t="inlineStr"><is><t xml:space="preserve">日期</t></is></c>`)
+	assert.Contains(t, worksheet, `<c r="A2" s="1"><v>45717</v></c>`)
+	assert.Contains(t, worksheet, `<t xml:space="preserve">A &amp; B</t>`)
+	assert.Contains(t, worksheet, `<c r="C2"><v>125.5</v></c>`)
+	assert.True(t, strings.HasSuffix(worksheet, `</sheetData></worksheet>`))
+}
+
+func TestWriteExportCSV_FormulaInjection(t *testing.T) {
+	sheet := &models.ExportSheet{
+		Name:    "Cash Flows",
+		Headers: []string{"Description", "Note", "Amount"},
+		Rows: [][]interface{}{
+			{"=HYPERLINK(\"http://evil.example\")", "@SUM(A1)", -125.5},
+			{"+1", "-2", 3},
+			{"Coffee - large", "", nil},
+		},
+	}
+
+	var csvBuf bytes.Buffer
+	require.NoError(t, writeExportSheet(&csvBuf, sheet, models.ExportFormatCSV))
+
+	records, err := csv.NewReader(strings.NewReader(strings.TrimPrefix(csvBuf.String(), "\ufeff"))).ReadAll()
+	require.NoError(t, err)
+	// 文字加上 ' 前綴，數字維持原值
+	assert.Equal(t, []string{"'=HYPERLINK(\"http://evil.example\")", "'@SUM(A1)", "-125.5"}, records[1])
+	assert.Equal(t, []string{"'+1", "'-2", "3"}, records[2])
+	assert.Equal(t, []string{"Coffee - large", "", ""}, records[3])
+}
+
+func TestWriteExportXLSX_TextWrittenUnchanged(t *testing.T) {
+	sheet := &models.ExportSheet{
+		Name:    "Cash Flows",
+		Headers: []string{"Description", "Note", "Amount"},
+		Rows: [][]interface{}{
+			{"=HYPERLINK(\"http://evil.example\")", "@SUM(A1)", -125.5},
+			{"+1", "-2", 3},
+		},
+	}
+
+	var xlsxBuf bytes.Buffer
+	require.NoError(t, writeExportSheet(&xlsxBuf, sheet, models.ExportFormatXLSX))
+
+	reader, err := zip.NewReader(bytes.NewReader(xlsxBuf.Bytes()), int64(xlsxBuf.Len()))
+	require.NoError(t, err)
+	var worksheet string
+	for _, file := range reader.File {
+		if file.Name != "xl/worksheets/sheet1.xml" {
+			continue
+		}
+		rc, err := file.Open()
+		require.NoError(t, err)
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		require.NoError(t, err)
+		worksheet = string(data)
+	}
+
+	// inline string 不會被當成公式，文字維持原值
+	assert.Contains(t, worksheet, `<c r="A2" t="inlineStr"><is><t xml:space="preserve">=HYPERLINK(&#34;http://evil.example&#34;)</t></is></c>`)
+	assert.Contains(t, worksheet, `<c r="B3" t="inlineStr"><is><t xml:space="preserve">-2</t></is></c>`)
+	assert.Contains(t, worksheet, `<c r="C2"><v>-125.5</v></c>`)
+}
+
+func TestWriteExportSheet_InvalidFormat(t *testing.T) {
+	var buf bytes.Buffer
+	err := writeExportSheet(&buf, &models.ExportSheet{}, models.ExportFormat("pdf"))
+	assert.Error(t, err)
+}
+
+func TestXLSXColumnName(t *testing.T) {
+	assert.Equal(t, "A", xlsxColumnName(0))
+	assert.Equal(t, "Z", xlsxColumnName(25))
+	assert.Equal(t, "AA", xlsxColumnName(26))
+	assert.Equal(t, "AZ", xlsxColumnName(51))
+	assert.Equal(t, "BA", xlsxColumnName(52))
+}
@@ -0,0 +1,213 @@
+package service
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chienchuanw/asset-manager/internal/models"
+)
+
+// exportDateLayout 匯出檔案中的日期格式
+const exportDateLayout = "2006-01-02"
+
+// writeExportSheet 依格式寫出表格
+func writeExportSheet(w io.Writer, sheet *models.ExportSheet, format models.ExportFormat) error {
+	switch format {
+	case models.ExportFormatCSV:
+		return writeExportCSV(w, sheet)
+	case models.ExportFormatXLSX:
+		return writeExportXLSX(w, sheet)
+	}
+	return fmt.Errorf("unsupported export format: %s", format)
+}
+
+// writeExportCSV 寫出 CSV（開頭加上 UTF-8 BOM，讓 Excel 正確辨識中文）
+func writeExportCSV(w io.Writer, sheet *models.ExportSheet) error {
+	if _, err := io.WriteString(w, "\ufeff"); err != nil {
+		return fmt.Errorf("failed to write csv: %w", err)
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(sheet.Headers); err != nil {
+		return fmt.Errorf("failed to write csv header: %w", err)
+	}
+	for _, row := range sheet.Rows {
+		record := make([]string, len(row))
+		for i, value := range row {
+			record[i] = formatExportCSVCell(value)
+		}
+		if err := cw.Write(record); err != nil {
+			return fmt.Errorf("failed to write csv row: %w", err)
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("failed to write csv: %w", err)
+	}
+	return nil
+}
+
+// formatExportCell 將儲存格值轉為文字
+func formatExportCell(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case int:
+		return strconv.Itoa(v)
+	case time.Time:
+		return v.Format(exportDateLayout)
+	}
+	return fmt.Sprint(value)
+}
+
+// formatExportCSVCell 將 CSV 儲存格值轉為文字，文字儲存格經過公式注入防護
+// （XLSX 以 inline string 寫出文字，不會被當成公式，因此不需要）
+func formatExportCSVCell(value interface{}) string {
+	switch value.(type) {
+	case nil, float64, int, time.Time:
+		return formatExportCell(value)
+	}
+	return escapeExportFormula(formatExportCell(value))
+}
+
+// escapeExportFormula 以 = + - @ 等字元開頭的文字前加上 '，避免試算表開啟 CSV 時將使用者輸入（例如備註）當成公式執行
+func escapeExportFormula(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+// XLSX 活頁簿的固定檔案（單一工作表；樣式 1 為日期、樣式 2 為粗體標題）
+const (
+	xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/><Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/></Types>`
+	xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`
+	xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/><Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/></Relationships>`
+	xlsxStyles = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><numFmts count="1"><numFmt numFmtId="164" formatCode="yyyy-mm-dd"/></numFmts><fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts><fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills><borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders><cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs><cellXfs count="3"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/><xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/><xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/></cellXfs></styleSheet>`
+)
+
+// xlsxEpoch Excel 日期序號的起點（1900 日期系統）
+var xlsxEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+// writeExportXLSX 寫出只有一個工作表的 XLSX 活頁簿（數字與日期保留原始型別，文字以 inline string 寫入）
+func writeExportXLSX(w io.Writer, sheet *models.ExportSheet) error {
+	zw := zip.NewWriter(w)
+
+	workbook := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="%s" sheetId="1" r:id="rId1"/></sheets></workbook>`, xlsxEscape(xlsxSheetName(sheet.Name)))
+
+	for _, file := range []struct{ name, content string }{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/workbook.xml", workbook},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+		{"xl/styles.xml", xlsxStyles},
+	} {
+		if err := writeZIPFile(zw, file.name, []byte(file.content)); err != nil {
+			return err
+		}
+	}
+
+	f, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return fmt.Errorf("failed to create worksheet: %w", err)
+	}
+	bw := bufio.NewWriter(f)
+	bw.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews><sheetData>`)
+
+	headers := make([]interface{}, len(sheet.Headers))
+	for i, header := range sheet.Headers {
+		headers[i] = header
+	}
+	writeXLSXRow(bw, 1, headers, 2)
+	for i, row := range sheet.Rows {
+		writeXLSXRow(bw, i+2, row, 0)
+	}
+
+	bw.WriteString(`</sheetData></worksheet>`)
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("failed to write worksheet: %w", err)
+	}
+
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("failed to finish workbook: %w", err)
+	}
+	return nil
+}
+
+// writeXLSXRow 寫出工作表的一列（style 為文字與數字儲存格的樣式）
+func writeXLSXRow(bw *bufio.Writer, rowNum int, values []interface{}, style int) {
+	fmt.Fprintf(bw, `<row r="%d">`, rowNum)
+	for col, value := range values {
+		ref := xlsxColumnName(col) + strconv.Itoa(rowNum)
+		styleAttr := ""
+		if style > 0 {
+			styleAttr = fmt.Sprintf(` s="%d"`, style)
+		}
+
+		switch v := value.(type) {
+		case nil:
+			continue
+		case float64:
+			fmt.Fprintf(bw, `<c r="%s"%s><v>%s</v></c>`, ref, styleAttr, strconv.FormatFloat(v, 'f', -1, 64))
+		case int:
+			fmt.Fprintf(bw, `<c r="%s"%s><v>%d</v></c>`, ref, styleAttr, v)
+		case time.Time:
+			date := time.Date(v.Year(), v.Month(), v.Day(), 0, 0, 0, 0, time.UTC)
+			fmt.Fprintf(bw, `<c r="%s" s="1"><v>%d</v></c>`, ref, int(date.Sub(xlsxEpoch).Hours()/24))
+		default:
+			fmt.Fprintf(bw, `<c r="%s"%s t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, ref, styleAttr, xlsxEscape(formatExportCell(value)))
+		}
+	}
+	bw.WriteString(`</row>`)
+}
+
+// xlsxColumnName 將從 0 開始的欄位索引轉為 Excel 欄名（A、B、…、Z、AA…）
+func xlsxColumnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
+
+// xlsxSheetName 工作表名稱（移除 Excel 不允許的字元並限制 31 字）
+func xlsxSheetName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return -1
+		}
+		return r
+	}, name)
+	if runes := []rune(name); len(runes) > 31 {
+		name = string(runes[:31])
+	}
+	if name == "" {
+		return "Sheet1"
+	}
+	return name
+}
+
+// xlsxEscape 跳脫 XML 特殊字元
+func xlsxEscape(s string) string {
+	var sb strings.Builder
+	xml.EscapeText(&sb, []byte(s))
+	return sb.String()
+}
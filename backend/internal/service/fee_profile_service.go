@@ -0,0 +1,263 @@
+package service
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/chienchuanw/asset-manager/internal/models"
+	"github.com/chienchuanw/asset-manager/internal/repository"
+	"github.com/google/uuid"
+)
+
+// FeeProfileService 券商手續費設定業務邏輯介面
+type FeeProfileService interface {
+	CreateProfile(input *models.CreateFeeProfileInput) (*models.FeeProfile, error)
+	GetProfile(id uuid.UUID) (*models.FeeProfile, error)
+	ListProfiles() ([]*models.FeeProfile, error)
+	UpdateProfile(id uuid.UUID, input *models.UpdateFeeProfileInput) (*models.FeeProfile, error)
+	DeleteProfile(id uuid.UUID) error
+
+	// ApplyTradingCosts 依適用的手續費設定補上交易未填寫的手續費與交易稅，已填寫但與設定不符時回傳警告
+	// 設定的優先順序：券商帳戶的設定 → 資產類型的預設設定 → 台股法定費率（僅補值、不檢查差異）
+	// instrument 可為 nil（台股會另外查詢商品主檔以判斷商品類別）
+	ApplyTradingCosts(input *models.CreateTransactionInput, instrument *models.Instrument) ([]*models.Warning, error)
+}
+
+// feeProfileService 券商手續費設定業務邏輯實作
+type feeProfileService struct {
+	repo           repository.FeeProfileRepository
+	instrumentRepo repository.InstrumentRepository // 可為 nil（台股依代號判斷商品類別）
+}
+
+// NewFeeProfileService 建立新的手續費設定 service
+func NewFeeProfileService(repo repository.FeeProfileRepository, instrumentRepo repository.InstrumentRepository) FeeProfileService {
+	return &feeProfileService{
+		repo:           repo,
+		instrumentRepo: instrumentRepo,
+	}
+}
+
+// CreateProfile 建立新的手續費設定
+func (s *feeProfileService) CreateProfile(input *models.CreateFeeProfileInput) (*models.FeeProfile, error) {
+	if input.Name == "" {
+		return nil, fmt.Errorf("fee profile name is required")
+	}
+
+	if input.AssetType != models.AssetTypeTWStock && input.AssetType != models.AssetTypeUSStock {
+		return nil, fmt.Errorf("fee profiles only support tw-stock and us-stock, got %s", input.AssetType)
+	}
+
+	if err := validateFeeProfileRates(input.FeeRate, input.FeeDiscount, input.PerShareFee, input.MinFee, input.MaxFee, input.SellTaxRates); err != nil {
+		return nil, err
+	}
+
+	return s.repo.Create(input)
+}
+
+// GetProfile 取得單筆手續費設定
+func (s *feeProfileService) GetProfile(id uuid.UUID) (*models.FeeProfile, error) {
+	return s.repo.GetByID(id)
+}
+
+// ListProfiles 取得所有手續費設定
+func (s *feeProfileService) ListProfiles() ([]*models.FeeProfile, error) {
+	return s.repo.GetAll()
+}
+
+// UpdateProfile 更新手續費設定
+func (s *feeProfileService) UpdateProfile(id uuid.UUID, input *models.UpdateFeeProfileInput) (*models.FeeProfile, error) {
+	if input.Name != nil && *input.Name == "" {
+		return nil, fmt.Errorf("fee profile name cannot be empty")
+	}
+
+	profile, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// 以更新後的值驗證（未填寫的欄位沿用原設定）
+	feeRate, perShareFee, minFee, maxFee := profile.FeeRate, profile.PerShareFee, profile.MinFee, profile.MaxFee
+	if input.FeeRate != nil {
+		feeRate = *input.FeeRate
+	}
+	if input.PerShareFee != nil {
+		perShareFee = *input.PerShareFee
+	}
+	if input.MinFee != nil {
+		minFee = *input.MinFee
+	}
+	if input.MaxFee != nil {
+		maxFee = input.MaxFee
+	}
+	if err := validateFeeProfileRates(feeRate, input.FeeDiscount, perShareFee, minFee, maxFee, input.SellTaxRates); err != nil {
+		return nil, err
+	}
+
+	return s.repo.Update(id, input)
+}
+
+// DeleteProfile 刪除手續費設定
+func (s *feeProfileService) DeleteProfile(id uuid.UUID) error {
+	return s.repo.Delete(id)
+}
+
+// validateFeeProfileRates 驗證費率、折扣、最低／最高手續費與各商品類別的交易稅率
+func validateFeeProfileRates(feeRate float64, feeDiscount *float64, perShareFee, minFee float64, maxFee *float64, sellTaxRates map[models.InstrumentCategory]float64) error {
+	if feeRate < 0 || perShareFee < 0 || minFee < 0 {
+		return fmt.Errorf("fee rate, per-share fee and minimum fee must be non-negative")
+	}
+
+	if feeDiscount != nil && (*feeDiscount <= 0 || *feeDiscount > 1) {
+		return fmt.Errorf("fee discount must be greater than 0 and at most 1")
+	}
+
+	if maxFee != nil && *maxFee < minFee {
+		return fmt.Errorf("maximum fee must not be less than minimum fee")
+	}
+
+	for category, rate := range sellTaxRates {
+		if !category.Validate() {
+			return fmt.Errorf("invalid instrument category: %s", category)
+		}
+		if rate < 0 || rate >= 1 {
+			return fmt.Errorf("sell tax rate for %s must be between 0 and 1", category)
+		}
+	}
+
+	return nil
+}
+
+// ApplyTradingCosts 依適用的手續費設定補上交易未填寫的手續費與交易稅，已填寫但與設定不符時回傳警告
+func (s *feeProfileService) ApplyTradingCosts(input *models.CreateTransactionInput, instrument *models.Instrument) ([]*models.Warning, error) {
+	if input.AssetType != models.AssetTypeTWStock && input.AssetType != models.AssetTypeUSStock {
+		return nil, nil
+	}
+
+	profile, err := s.resolveProfile(input)
+	if err != nil {
+		return nil, err
+	}
+
+	if instrument == nil && s.instrumentRepo != nil && input.AssetType == models.AssetTypeTWStock {
+		instrument, err = s.instrumentRepo.GetBySymbol(input.Symbol, input.AssetType)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// 未設定手續費設定時，台股以法定費率補值（券商折扣不同，不檢查差異）
+	if profile == nil {
+		applyTWTradingCostDefaults(input, instrument)
+		return nil, nil
+	}
+
+	if input.Currency != feeProfileCurrency(profile) {
+		return nil, nil
+	}
+
+	return applyFeeProfile(input, profile, tradingCostCategory(input, instrument), true), nil
+}
+
+// resolveProfile 取得交易適用的手續費設定（券商帳戶的設定優先，其次為預設設定），皆未設定時回傳 nil
+func (s *feeProfileService) resolveProfile(input *models.CreateTransactionInput) (*models.FeeProfile, error) {
+	if input.BrokerageAccountID != nil {
+		profile, err := s.repo.GetByBrokerageAccount(*input.BrokerageAccountID, input.AssetType)
+		if err != nil {
+			return nil, err
+		}
+		if profile != nil {
+			return profile, nil
+		}
+	}
+
+	return s.repo.GetDefault(input.AssetType)
+}
+
+// feeProfileCurrency 手續費設定的計價幣別（台股為新台幣、美股為美元）
+func feeProfileCurrency(profile *models.FeeProfile) models.Currency {
+	if profile.AssetType == models.AssetTypeTWStock {
+		return models.CurrencyTWD
+	}
+	return models.CurrencyUSD
+}
+
+// tradingCostCategory 取得計算交易稅使用的商品類別（台股優先使用商品主檔，其次依代號判斷；美股視為股票）
+func tradingCostCategory(input *models.CreateTransactionInput, instrument *models.Instrument) models.InstrumentCategory {
+	if input.AssetType != models.AssetTypeTWStock {
+		return models.InstrumentCategoryStock
+	}
+	if instrument != nil && instrument.Category != "" {
+		return instrument.Category
+	}
+	return models.ClassifyTWInstrument(input.Symbol)
+}
+
+// feeProfileSellTaxRate 取得設定中商品類別的賣出稅率，未設定的台股類別使用法定稅率
+func feeProfileSellTaxRate(profile *models.FeeProfile, category models.InstrumentCategory, input *models.CreateTransactionInput) float64 {
+	if rate, exists := profile.SellTaxRates[category]; exists {
+		return rate
+	}
+	if profile.AssetType == models.AssetTypeTWStock {
+		return twTransactionTaxRate(category, input.Date)
+	}
+	return 0
+}
+
+// applyFeeProfile 依手續費設定補上未填寫的手續費與交易稅
+// checkDeviation 為 true 時，已填寫的值與設定計算結果相差超過容許誤差會回傳警告
+func applyFeeProfile(input *models.CreateTransactionInput, profile *models.FeeProfile, category models.InstrumentCategory, checkDeviation bool) []*models.Warning {
+	if input.Amount <= 0 {
+		return nil
+	}
+
+	switch input.TransactionType {
+	case models.TransactionTypeBuy, models.TransactionTypeSell, models.TransactionTypeShort, models.TransactionTypeCover:
+	default:
+		return nil
+	}
+
+	warnings := []*models.Warning{}
+
+	expectedFee := profile.CalculateFee(input.Amount, input.Quantity)
+	if input.Fee == nil {
+		input.Fee = &expectedFee
+	} else if checkDeviation && math.Abs(*input.Fee-expectedFee) > profile.CostTolerance() {
+		warnings = append(warnings, newCostDeviationWarning(models.WarningCodeFeeDeviation, input.Symbol, "fee", *input.Fee, expectedFee, profile))
+	}
+
+	if input.TransactionType == models.TransactionTypeSell || input.TransactionType == models.TransactionTypeShort {
+		expectedTax := profile.CalculateSellTax(input.Amount, feeProfileSellTaxRate(profile, category, input))
+		if input.Tax == nil {
+			input.Tax = &expectedTax
+		} else if checkDeviation && math.Abs(*input.Tax-expectedTax) > profile.CostTolerance() {
+			warnings = append(warnings, newCostDeviationWarning(models.WarningCodeTaxDeviation, input.Symbol, "tax", *input.Tax, expectedTax, profile))
+		}
+	}
+
+	if len(warnings) == 0 {
+		return nil
+	}
+	return warnings
+}
+
+// costFieldLabels 手續費與交易稅欄位的顯示名稱
+var costFieldLabels = map[string]string{
+	"fee": "手續費",
+	"tax": "交易稅",
+}
+
+// newCostDeviationWarning 建立手續費或交易稅與設定不符的警告
+func newCostDeviationWarning(code models.WarningCode, symbol, field string, actual, expected float64, profile *models.FeeProfile) *models.Warning {
+	return &models.Warning{
+		Code:    code,
+		Symbol:  symbol,
+		Message: fmt.Sprintf("標的 %s 的%s %.2f 與手續費設定「%s」計算的 %.2f 不符", symbol, costFieldLabels[field], actual, profile.Name, expected),
+		Details: map[string]interface{}{
+			"field":        field,
+			"actual":       actual,
+			"expected":     expected,
+			"profile_id":   profile.ID,
+			"profile_name": profile.Name,
+		},
+	}
+}
@@ -0,0 +1,277 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chienchuanw/asset-manager/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeFeeProfileRepository 測試用手續費設定 repository
+type fakeFeeProfileRepository struct {
+	profiles []*models.FeeProfile
+}
+
+func (r *fakeFeeProfileRepository) Create(input *models.CreateFeeProfileInput) (*models.FeeProfile, error) {
+	profile := &models.FeeProfile{
+		ID:                 uuid.New(),
+		Name:               input.Name,
+		AssetType:          input.AssetType,
+		BrokerageAccountID: input.BrokerageAccountID,
+		IsDefault:          input.IsDefault,
+		FeeRate:            input.FeeRate,
+		FeeDiscount:        1,
+		PerShareFee:        input.PerShareFee,
+		MinFee:             input.MinFee,
+		MaxFee:             input.MaxFee,
+		SellTaxRates:       input.SellTaxRates,
+	}
+	if input.FeeDiscount != nil {
+		profile.FeeDiscount = *input.FeeDiscount
+	}
+	r.profiles = append(r.profiles, profile)
+	return profile, nil
+}
+
+func (r *fakeFeeProfileRepository) GetByID(id uuid.UUID) (*models.FeeProfile, error) {
+	for _, profile := range r.profiles {
+		if profile.ID == id {
+			return profile, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeFeeProfileRepository) GetAll() ([]*models.FeeProfile, error) {
+	return r.profiles, nil
+}
+
+func (r *fakeFeeProfileRepository) Update(id uuid.UUID, input *models.UpdateFeeProfileInput) (*models.FeeProfile, error) {
+	return r.GetByID(id)
+}
+
+func (r *fakeFeeProfileRepository) Delete(id uuid.UUID) error {
+	return nil
+}
+
+func (r *fakeFeeProfileRepository) GetDefault(assetType models.AssetType) (*models.FeeProfile, error) {
+	for _, profile := range r.profiles {
+		if profile.AssetType == assetType && profile.IsDefault {
+			return profile, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeFeeProfileRepository) GetByBrokerageAccount(accountID uuid.UUID, assetType models.AssetType) (*models.FeeProfile, error) {
+	for _, profile := range r.profiles {
+		if profile.AssetType == assetType && profile.BrokerageAccountID != nil && *profile.BrokerageAccountID == accountID {
+			return profile, nil
+		}
+	}
+	return nil, nil
+}
+
+// newTWFeeProfileTestInput 建立測試用台股交易輸入
+func newTWFeeProfileTestInput(transactionType models.TransactionType, symbol string, amount float64) *models.CreateTransactionInput {
+	return &models.CreateTransactionInput{
+		Date:            time.Date(2025, 10, 22, 0, 0, 0, 0, time.UTC),
+		AssetType:       models.AssetTypeTWStock,
+		Symbol:          symbol,
+		TransactionType: transactionType,
+		Quantity:        1000,
+		Amount:          amount,
+		Currency:        models.CurrencyTWD,
+	}
+}
+
+// TestFeeProfileService_ApplyTradingCosts 測試依手續費設定補上手續費與交易稅
+func TestFeeProfileService_ApplyTradingCosts(t *testing.T) {
+	discount := 0.28
+	repo := &fakeFeeProfileRepository{}
+	svc := NewFeeProfileService(repo, &fakeInstrumentRepository{})
+	defaultProfile, err := svc.CreateProfile(&models.CreateFeeProfileInput{
+		Name:        "電子下單 2.8 折",
+		AssetType:   models.AssetTypeTWStock,
+		IsDefault:   true,
+		FeeRate:     0.001425,
+		FeeDiscount: &discount,
+		MinFee:      20,
+	})
+	require.NoError(t, err)
+
+	t.Run("依折扣計算手續費並以無條件捨去取整數", func(t *testing.T) {
+		input := newTWFeeProfileTestInput(models.TransactionTypeBuy, "2330", 1000000)
+
+		warnings, err := svc.ApplyTradingCosts(input, nil)
+
+		require.NoError(t, err)
+		assert.Empty(t, warnings)
+		require.NotNil(t, input.Fee)
+		assert.Equal(t, 399.0, *input.Fee)
+		assert.Nil(t, input.Tax)
+	})
+
+	t.Run("低於最低手續費時收取最低手續費", func(t *testing.T) {
+		input := newTWFeeProfileTestInput(models.TransactionTypeBuy, "2330", 10000)
+
+		_, err := svc.ApplyTradingCosts(input, nil)
+
+		require.NoError(t, err)
+		require.NotNil(t, input.Fee)
+		assert.Equal(t, 20.0, *input.Fee)
+	})
+
+	t.Run("賣出時依代號判斷的商品類別套用法定交易稅", func(t *testing.T) {
+		input := newTWFeeProfileTestInput(models.TransactionTypeSell, "0050", 1000000)
+
+		_, err := svc.ApplyTradingCosts(input, nil)
+
+		require.NoError(t, err)
+		require.NotNil(t, input.Tax)
+		assert.Equal(t, 1000.0, *input.Tax)
+	})
+
+	t.Run("已填寫的手續費與設定不符時回傳警告", func(t *testing.T) {
+		fee, tax := 1425.0, 3000.0
+		input := newTWFeeProfileTestInput(models.TransactionTypeSell, "2330", 1000000)
+		input.Fee, input.Tax = &fee, &tax
+
+		warnings, err := svc.ApplyTradingCosts(input, nil)
+
+		require.NoError(t, err)
+		require.Len(t, warnings, 1)
+		assert.Equal(t, models.WarningCodeFeeDeviation, warnings[0].Code)
+		assert.Equal(t, 399.0, warnings[0].Details["expected"])
+		assert.Equal(t, defaultProfile.ID, warnings[0].Details["profile_id"])
+		assert.Equal(t, 1425.0, *input.Fee, "已填寫的手續費不應被覆寫")
+	})
+
+	t.Run("容許誤差內不回傳警告", func(t *testing.T) {
+		fee := 400.0
+		input := newTWFeeProfileTestInput(models.TransactionTypeBuy, "2330", 1000000)
+		input.Fee = &fee
+
+		warnings, err := svc.ApplyTradingCosts(input, nil)
+
+		require.NoError(t, err)
+		assert.Empty(t, warnings)
+	})
+
+	t.Run("券商帳戶的設定優先於預設設定", func(t *testing.T) {
+		accountID := uuid.New()
+		accountDiscount := 0.6
+		_, err := svc.CreateProfile(&models.CreateFeeProfileInput{
+			Name:               "券商 A 6 折",
+			AssetType:          models.AssetTypeTWStock,
+			BrokerageAccountID: &accountID,
+			FeeRate:            0.001425,
+			FeeDiscount:        &accountDiscount,
+			MinFee:             1,
+		})
+		require.NoError(t, err)
+
+		input := newTWFeeProfileTestInput(models.TransactionTypeBuy, "2330", 1000000)
+		input.BrokerageAccountID = &accountID
+
+		_, err = svc.ApplyTradingCosts(input, nil)
+
+		require.NoError(t, err)
+		require.NotNil(t, input.Fee)
+		assert.Equal(t, 855.0, *input.Fee)
+	})
+}
+
+// TestFeeProfileService_ApplyTradingCosts_USStock 測試美股每股手續費與最高手續費
+func TestFeeProfileService_ApplyTradingCosts_USStock(t *testing.T) {
+	maxFee := 5.0
+	repo := &fakeFeeProfileRepository{}
+	svc := NewFeeProfileService(repo, nil)
+	_, err := svc.CreateProfile(&models.CreateFeeProfileInput{
+		Name:         "每股 0.005",
+		AssetType:    models.AssetTypeUSStock,
+		IsDefault:    true,
+		PerShareFee:  0.005,
+		MinFee:       1,
+		MaxFee:       &maxFee,
+		SellTaxRates: map[models.InstrumentCategory]float64{models.InstrumentCategoryStock: 0.0000278},
+	})
+	require.NoError(t, err)
+
+	newInput := func(quantity, amount float64) *models.CreateTransactionInput {
+		return &models.CreateTransactionInput{
+			Date:            time.Date(2025, 10, 22, 0, 0, 0, 0, time.UTC),
+			AssetType:       models.AssetTypeUSStock,
+			Symbol:          "AAPL",
+			TransactionType: models.TransactionTypeSell,
+			Quantity:        quantity,
+			Amount:          amount,
+			Currency:        models.CurrencyUSD,
+		}
+	}
+
+	input := newInput(300, 60000)
+	_, err = svc.ApplyTradingCosts(input, nil)
+	require.NoError(t, err)
+	require.NotNil(t, input.Fee)
+	require.NotNil(t, input.Tax)
+	assert.Equal(t, 1.5, *input.Fee)
+	assert.Equal(t, 1.67, *input.Tax)
+
+	input = newInput(5000, 1000000)
+	_, err = svc.ApplyTradingCosts(input, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 5.0, *input.Fee, "超過最高手續費時以最高手續費計")
+
+	// 幣別與設定不符時不套用
+	input = newInput(300, 60000)
+	input.Currency = models.CurrencyTWD
+	_, err = svc.ApplyTradingCosts(input, nil)
+	require.NoError(t, err)
+	assert.Nil(t, input.Fee)
+}
+
+// TestFeeProfileService_ApplyTradingCosts_StatutoryFallback 測試未設定手續費設定時以法定費率補值
+func TestFeeProfileService_ApplyTradingCosts_StatutoryFallback(t *testing.T) {
+	instrumentRepo := &fakeInstrumentRepository{instruments: []*models.Instrument{
+		{Symbol: "2330", AssetType: models.AssetTypeTWStock, Category: models.InstrumentCategoryStock},
+	}}
+	svc := NewFeeProfileService(&fakeFeeProfileRepository{}, instrumentRepo)
+
+	fee := 600.0
+	input := newTWFeeProfileTestInput(models.TransactionTypeSell, "2330", 1000000)
+	input.Fee = &fee
+
+	warnings, err := svc.ApplyTradingCosts(input, nil)
+
+	require.NoError(t, err)
+	assert.Empty(t, warnings, "法定費率不檢查券商折扣造成的差異")
+	assert.Equal(t, 600.0, *input.Fee)
+	require.NotNil(t, input.Tax)
+	assert.Equal(t, 3000.0, *input.Tax)
+}
+
+// TestFeeProfileService_CreateProfile_Validation 測試手續費設定的驗證
+func TestFeeProfileService_CreateProfile_Validation(t *testing.T) {
+	svc := NewFeeProfileService(&fakeFeeProfileRepository{}, nil)
+	invalidDiscount, invalidMaxFee := 1.5, 10.0
+
+	_, err := svc.CreateProfile(&models.CreateFeeProfileInput{Name: "加密貨幣", AssetType: models.AssetTypeCrypto})
+	assert.Error(t, err)
+
+	_, err = svc.CreateProfile(&models.CreateFeeProfileInput{Name: "折扣錯誤", AssetType: models.AssetTypeTWStock, FeeDiscount: &invalidDiscount})
+	assert.Error(t, err)
+
+	_, err = svc.CreateProfile(&models.CreateFeeProfileInput{Name: "上限錯誤", AssetType: models.AssetTypeTWStock, MinFee: 20, MaxFee: &invalidMaxFee})
+	assert.Error(t, err)
+
+	_, err = svc.CreateProfile(&models.CreateFeeProfileInput{
+		Name:         "類別錯誤",
+		AssetType:    models.AssetTypeTWStock,
+		SellTaxRates: map[models.InstrumentCategory]float64{"warrant": 0.001},
+	})
+	assert.Error(t, err)
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"errors"
 	"fmt"
 	"sort"
 	"time"
@@ -8,6 +9,15 @@ import (
 	"github.com/chienchuanw/asset-manager/internal/models"
 )
 
+// errPositionConflict 交易方向與現有部位衝突（例如持有空頭部位時買進、持有多頭部位時放空）
+var errPositionConflict = errors.New("position direction conflict")
+
+// ClosingBasis 了結部位的 FIFO 基礎
+type ClosingBasis struct {
+	IsShort bool    // 了結的是否為空頭部位
+	Basis   float64 // 多頭部位為成本基礎；空頭部位為放空所得（扣除手續費，TWD）
+}
+
 // FIFOCalculatorResult FIFO 計算結果（包含持倉和警告）
 type FIFOCalculatorResult struct {
 	Holdings map[string]*models.Holding // 成功計算的持倉
@@ -24,6 +34,9 @@ type FIFOCalculator interface {
 
 	// CalculateCostBasis 計算賣出交易的成本基礎（使用 FIFO 規則）
 	CalculateCostBasis(symbol string, sellTransaction *models.Transaction, allTransactions []*models.Transaction) (float64, error)
+
+	// CalculateClosingBasis 計算回補、選擇權到期或履約交易所了結部位的 FIFO 基礎
+	CalculateClosingBasis(symbol string, closingTransaction *models.Transaction, allTransactions []*models.Transaction) (*ClosingBasis, error)
 }
 
 // fifoCalculator FIFO 計算器實作
@@ -64,30 +77,10 @@ func (c *fifoCalculator) CalculateHoldingForSymbol(symbol string, transactions [
 		assetType = tx.AssetType
 		name = tx.Name
 
-		switch tx.TransactionType {
-		case models.TransactionTypeBuy:
-			// 買入：新增成本批次
-			batch, err := c.processBuy(tx)
-			if err != nil {
-				return nil, err
-			}
-			costBatches = append(costBatches, batch)
-
-		case models.TransactionTypeSell:
-			// 賣出：使用 FIFO 扣除成本批次
-			var err error
-			costBatches, err = c.processSell(tx, costBatches)
-			if err != nil {
-				return nil, err
-			}
-
-		case models.TransactionTypeDividend:
-			// 股利：不影響持倉成本，跳過
-			continue
-
-		case models.TransactionTypeFee:
-			// 單獨的手續費記錄：暫時跳過（手續費已在買賣時處理）
-			continue
+		var err error
+		costBatches, err = c.applyTransaction(tx, costBatches)
+		if err != nil {
+			return nil, err
 		}
 	}
 
@@ -121,6 +114,15 @@ func (c *fifoCalculator) CalculateAllHoldings(transactions []*models.Transaction
 				warnings = append(warnings, warning)
 				continue
 			}
+			// 交易方向與部位衝突同樣視為交易記錄有誤，記錄警告並跳過此標的
+			if errors.Is(err, errPositionConflict) {
+				warnings = append(warnings, &models.Warning{
+					Code:    models.WarningCodePositionConflict,
+					Symbol:  symbol,
+					Message: fmt.Sprintf("標的 %s 的交易方向與部位衝突：%v", symbol, err),
+				})
+				continue
+			}
 			// 其他錯誤直接返回
 			return nil, fmt.Errorf("failed to calculate holding for %s: %w", symbol, err)
 		}
@@ -137,6 +139,63 @@ func (c *fifoCalculator) CalculateAllHoldings(transactions []*models.Transaction
 	}, nil
 }
 
+// applyTransaction 依交易類型更新成本批次
+func (c *fifoCalculator) applyTransaction(tx *models.Transaction, batches []*models.CostBatch) ([]*models.CostBatch, error) {
+	short := isShortPosition(batches)
+
+	switch tx.TransactionType {
+	case models.TransactionTypeBuy, models.TransactionTypeStaking, models.TransactionTypeAirdrop:
+		// 買入、質押與空投收入：新增成本批次（質押、空投以取得時的市值作為成本）
+		if short {
+			return nil, fmt.Errorf("%w: cannot %s %s while holding a short position", errPositionConflict, tx.TransactionType, tx.Symbol)
+		}
+		batch, err := c.processBuy(tx)
+		if err != nil {
+			return nil, err
+		}
+		return append(batches, batch), nil
+
+	case models.TransactionTypeSell:
+		// 賣出：使用 FIFO 扣除成本批次
+		if short {
+			return nil, fmt.Errorf("%w: cannot sell %s while holding a short position", errPositionConflict, tx.Symbol)
+		}
+		return c.processSell(tx, batches)
+
+	case models.TransactionTypeShort:
+		// 放空：新增空頭批次
+		if len(batches) > 0 && !short {
+			return nil, fmt.Errorf("%w: cannot short %s while holding a long position", errPositionConflict, tx.Symbol)
+		}
+		batch, err := c.processShort(tx)
+		if err != nil {
+			return nil, err
+		}
+		return append(batches, batch), nil
+
+	case models.TransactionTypeCover:
+		// 回補：使用 FIFO 沖銷空頭批次
+		if len(batches) > 0 && !short {
+			return nil, fmt.Errorf("%w: cannot cover %s while holding a long position", errPositionConflict, tx.Symbol)
+		}
+		return c.processCover(tx, batches)
+
+	case models.TransactionTypeExpiration, models.TransactionTypeAssignment:
+		// 選擇權到期或履約：依部位方向以 FIFO 了結指定口數
+		if short {
+			return c.processCover(tx, batches)
+		}
+		return c.processSell(tx, batches)
+
+	case models.TransactionTypeTransfer:
+		// 內部轉移：批次不變，僅扣除網路手續費消耗的數量
+		return c.processTransfer(tx, batches)
+	}
+
+	// 股利與單獨的手續費記錄不影響持倉成本
+	return batches, nil
+}
+
 // processBuy 處理買入交易，建立新的成本批次
 func (c *fifoCalculator) processBuy(tx *models.Transaction) (*models.CostBatch, error) {
 	// 計算含手續費的總成本（原幣別）
@@ -215,6 +274,81 @@ func (c *fifoCalculator) processSell(tx *models.Transaction, batches []*models.C
 	return newBatches, nil
 }
 
+// processShort 處理放空交易，建立空頭批次
+// 批次數量為負數，單位成本為扣除手續費後的每單位放空所得
+func (c *fifoCalculator) processShort(tx *models.Transaction) (*models.CostBatch, error) {
+	// 計算扣除手續費的放空所得（原幣別）
+	proceedsOriginal := tx.Amount
+	if tx.Fee != nil {
+		proceedsOriginal -= *tx.Fee
+	}
+
+	proceedsTWD := proceedsOriginal
+	exchangeRate := 1.0
+	if tx.Currency == models.CurrencyUSD {
+		var err error
+		proceedsTWD, err = c.exchangeRateService.ConvertToTWD(proceedsOriginal, tx.Currency, tx.Date)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert proceeds to TWD for %s on %s: %w", tx.Symbol, tx.Date.Format("2006-01-02"), err)
+		}
+		if proceedsOriginal != 0 {
+			exchangeRate = proceedsTWD / proceedsOriginal
+		}
+	}
+
+	return &models.CostBatch{
+		Date:             tx.Date,
+		Quantity:         -tx.Quantity,
+		UnitCost:         proceedsTWD / tx.Quantity,
+		UnitCostOriginal: proceedsOriginal / tx.Quantity,
+		OriginalQty:      -tx.Quantity,
+		Currency:         tx.Currency,
+		ExchangeRate:     exchangeRate,
+	}, nil
+}
+
+// processCover 處理回補交易，使用 FIFO 沖銷空頭批次
+func (c *fifoCalculator) processCover(tx *models.Transaction, batches []*models.CostBatch) ([]*models.CostBatch, error) {
+	remainingToCover := tx.Quantity
+	newBatches := []*models.CostBatch{}
+
+	for _, batch := range batches {
+		if remainingToCover <= 0 {
+			newBatches = append(newBatches, batch)
+			continue
+		}
+
+		if -batch.Quantity <= remainingToCover {
+			// 這個空頭批次全部回補
+			remainingToCover += batch.Quantity
+		} else {
+			// 這個空頭批次部分回補
+			batch.Quantity += remainingToCover
+			remainingToCover = 0
+			newBatches = append(newBatches, batch)
+		}
+	}
+
+	if remainingToCover > 0 {
+		return nil, fmt.Errorf("insufficient quantity to cover: trying to cover %.2f but only have %.2f short",
+			tx.Quantity, tx.Quantity-remainingToCover)
+	}
+
+	return newBatches, nil
+}
+
+// processTransfer 處理保管帳戶間的內部轉移
+// 轉移不是應稅事件，批次維持原有順序與成本；網路手續費消耗的數量依 FIFO 自最早批次扣除
+func (c *fifoCalculator) processTransfer(tx *models.Transaction, batches []*models.CostBatch) ([]*models.CostBatch, error) {
+	if tx.NetworkFee == nil || *tx.NetworkFee <= 0 {
+		return batches, nil
+	}
+
+	feeTx := *tx
+	feeTx.Quantity = *tx.NetworkFee
+	return c.processSell(&feeTx, batches)
+}
+
 // calculateHoldingFromBatches 從成本批次計算持倉資訊
 func (c *fifoCalculator) calculateHoldingFromBatches(symbol, name string, assetType models.AssetType, batches []*models.CostBatch) *models.Holding {
 	var totalQuantity float64
@@ -239,6 +373,7 @@ func (c *fifoCalculator) calculateHoldingFromBatches(symbol, name string, assetT
 		AvgCostOriginal: avgCostOriginal,
 		TotalCost:       totalCostTWD,
 		LastUpdated:     time.Now(),
+		IsShort:         totalQuantity < 0,
 	}
 }
 
@@ -265,20 +400,10 @@ func (c *fifoCalculator) CalculateCostBasis(symbol string, sellTransaction *mode
 	costBatches := []*models.CostBatch{}
 
 	for _, tx := range symbolTransactions {
-		switch tx.TransactionType {
-		case models.TransactionTypeBuy:
-			batch, err := c.processBuy(tx)
-			if err != nil {
-				return 0, err
-			}
-			costBatches = append(costBatches, batch)
-
-		case models.TransactionTypeSell:
-			var err error
-			costBatches, err = c.processSell(tx, costBatches)
-			if err != nil {
-				return 0, err
-			}
+		var err error
+		costBatches, err = c.applyTransaction(tx, costBatches)
+		if err != nil {
+			return 0, err
 		}
 	}
 
@@ -291,6 +416,81 @@ func (c *fifoCalculator) CalculateCostBasis(symbol string, sellTransaction *mode
 	return costBasis, nil
 }
 
+// CalculateClosingBasis 計算回補、選擇權到期或履約交易所了結部位的 FIFO 基礎
+func (c *fifoCalculator) CalculateClosingBasis(symbol string, closingTransaction *models.Transaction, allTransactions []*models.Transaction) (*ClosingBasis, error) {
+	if !closingTransaction.TransactionType.IsClosing() || closingTransaction.TransactionType == models.TransactionTypeSell {
+		return nil, fmt.Errorf("transaction is not a cover, expiration or assignment transaction")
+	}
+
+	if closingTransaction.Symbol != symbol {
+		return nil, fmt.Errorf("transaction symbol %s does not match requested symbol %s", closingTransaction.Symbol, symbol)
+	}
+
+	// 重建了結交易之前的成本批次
+	symbolTransactions := filterTransactionsBeforeSell(allTransactions, symbol, closingTransaction.Date)
+	sort.Slice(symbolTransactions, func(i, j int) bool {
+		return symbolTransactions[i].Date.Before(symbolTransactions[j].Date)
+	})
+
+	costBatches := []*models.CostBatch{}
+	for _, tx := range symbolTransactions {
+		var err error
+		costBatches, err = c.applyTransaction(tx, costBatches)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if isShortPosition(costBatches) {
+		proceeds, err := c.calculateShortProceedsFromBatches(closingTransaction.Quantity, costBatches)
+		if err != nil {
+			return nil, err
+		}
+		return &ClosingBasis{IsShort: true, Basis: proceeds}, nil
+	}
+
+	if closingTransaction.TransactionType == models.TransactionTypeCover {
+		if len(costBatches) > 0 {
+			return nil, fmt.Errorf("%w: cannot cover %s while holding a long position", errPositionConflict, symbol)
+		}
+		return nil, fmt.Errorf("insufficient quantity to cover: trying to cover %.2f but only have 0.00 short", closingTransaction.Quantity)
+	}
+
+	costBasis, err := c.calculateCostBasisFromBatches(closingTransaction.Quantity, costBatches)
+	if err != nil {
+		return nil, err
+	}
+	return &ClosingBasis{IsShort: false, Basis: costBasis}, nil
+}
+
+// calculateShortProceedsFromBatches 從空頭批次計算回補數量對應的放空所得
+func (c *fifoCalculator) calculateShortProceedsFromBatches(coverQuantity float64, batches []*models.CostBatch) (float64, error) {
+	remainingToCover := coverQuantity
+	totalProceeds := 0.0
+
+	for _, batch := range batches {
+		if remainingToCover <= 0 {
+			break
+		}
+
+		shortQuantity := -batch.Quantity
+		if shortQuantity <= remainingToCover {
+			totalProceeds += shortQuantity * batch.UnitCost
+			remainingToCover -= shortQuantity
+		} else {
+			totalProceeds += remainingToCover * batch.UnitCost
+			remainingToCover = 0
+		}
+	}
+
+	if remainingToCover > 0 {
+		return 0, fmt.Errorf("insufficient quantity to cover: trying to cover %.2f but only have %.2f short",
+			coverQuantity, coverQuantity-remainingToCover)
+	}
+
+	return totalProceeds, nil
+}
+
 // calculateCostBasisFromBatches 從成本批次計算賣出的成本基礎
 func (c *fifoCalculator) calculateCostBasisFromBatches(sellQuantity float64, batches []*models.CostBatch) (float64, error) {
 	remainingToSell := sellQuantity
@@ -334,6 +534,11 @@ func filterTransactionsBySymbol(transactions []*models.Transaction, symbol strin
 	return result
 }
 
+// isShortPosition 成本批次是否為空頭部位（同一標的的批次方向一致）
+func isShortPosition(batches []*models.CostBatch) bool {
+	return len(batches) > 0 && batches[0].Quantity < 0
+}
+
 // getUniqueSymbols 取得所有唯一的標的代碼
 func getUniqueSymbols(transactions []*models.Transaction) []string {
 	symbolMap := make(map[string]bool)
@@ -379,8 +584,12 @@ func createInsufficientQuantityWarning(symbol string, err error) *models.Warning
 	errMsg := err.Error()
 
 	var required, available float64
-	// 嘗試從錯誤訊息中解析數字
-	fmt.Sscanf(errMsg, "insufficient quantity to sell: trying to sell %f but only have %f", &required, &available)
+	action := "賣出"
+	// 嘗試從錯誤訊息中解析數字（賣出或回補）
+	if n, _ := fmt.Sscanf(errMsg, "insufficient quantity to sell: trying to sell %f but only have %f", &required, &available); n < 2 {
+		fmt.Sscanf(errMsg, "insufficient quantity to cover: trying to cover %f but only have %f", &required, &available)
+		action = "回補"
+	}
 
 	missing := required - available
 	if missing < 0 {
@@ -390,7 +599,7 @@ func createInsufficientQuantityWarning(symbol string, err error) *models.Warning
 	return &models.Warning{
 		Code:    models.WarningCodeInsufficientQuantity,
 		Symbol:  symbol,
-		Message: fmt.Sprintf("標的 %s 的交易記錄不完整：嘗試賣出 %.2f 股，但只有 %.2f 股可用", symbol, required, available),
+		Message: fmt.Sprintf("標的 %s 的交易記錄不完整：嘗試%s %.2f 股，但只有 %.2f 股可用", symbol, action, required, available),
 		Details: map[string]interface{}{
 			"required":  required,
 			"available": available,
@@ -1014,6 +1014,127 @@ func TestFIFO_AvgCostOriginal_MultipleBuys(t *testing.T) {
 	assert.InDelta(t, 153.87, holding.AvgCostOriginal, 0.01)
 }
 
+// ==================== 加密貨幣保管與收入測試 ====================
+
+// TestFIFO_TransferKeepsLotsAndDeductsNetworkFee 測試內部轉移不影響成本批次，僅扣除網路手續費
+func TestFIFO_TransferKeepsLotsAndDeductsNetworkFee(t *testing.T) {
+	// Arrange: 兩批買入後轉移 1 BTC 至冷錢包，網路手續費 0.01 BTC，最後賣出 1 BTC
+	transactions := []*models.Transaction{
+		{Date: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), AssetType: models.AssetTypeCrypto, Symbol: "BTC", Name: "Bitcoin", TransactionType: models.TransactionTypeBuy, Quantity: 1, Amount: 1000000, Currency: models.CurrencyTWD},
+		{Date: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), AssetType: models.AssetTypeCrypto, Symbol: "BTC", Name: "Bitcoin", TransactionType: models.TransactionTypeBuy, Quantity: 1, Amount: 2000000, Currency: models.CurrencyTWD},
+		{Date: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), AssetType: models.AssetTypeCrypto, Symbol: "BTC", Name: "Bitcoin", TransactionType: models.TransactionTypeTransfer, Quantity: 1, NetworkFee: ptrFloat64(0.01), Currency: models.CurrencyTWD},
+	}
+	sell := &models.Transaction{Date: time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC), AssetType: models.AssetTypeCrypto, Symbol: "BTC", TransactionType: models.TransactionTypeSell, Quantity: 1, Amount: 2500000, Currency: models.CurrencyTWD}
+
+	calculator := NewFIFOCalculator(newMockExchangeRateForTWD())
+
+	// Act
+	holding, err := calculator.CalculateHoldingForSymbol("BTC", transactions)
+	costBasis, costErr := calculator.CalculateCostBasis("BTC", sell, append(transactions, sell))
+
+	// Assert: 手續費自最早批次扣除，賣出成本仍依原批次順序計算
+	assert.NoError(t, err)
+	assert.InDelta(t, 1.99, holding.Quantity, 1e-9)
+	assert.InDelta(t, 2990000, holding.TotalCost, 0.01)
+	assert.NoError(t, costErr)
+	// 成本基礎 = 0.99 * 1000000 + 0.01 * 2000000
+	assert.InDelta(t, 1010000, costBasis, 0.01)
+}
+
+// TestFIFO_StakingAndAirdropCreateLots 測試質押與空投收入以取得時市值建立成本批次
+func TestFIFO_StakingAndAirdropCreateLots(t *testing.T) {
+	// Arrange
+	transactions := []*models.Transaction{
+		{Date: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), AssetType: models.AssetTypeCrypto, Symbol: "ETH", Name: "Ethereum", TransactionType: models.TransactionTypeBuy, Quantity: 2, Amount: 200000, Currency: models.CurrencyTWD},
+		{Date: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), AssetType: models.AssetTypeCrypto, Symbol: "ETH", Name: "Ethereum", TransactionType: models.TransactionTypeStaking, Quantity: 0.1, Amount: 12000, Currency: models.CurrencyTWD},
+		{Date: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), AssetType: models.AssetTypeCrypto, Symbol: "ETH", Name: "Ethereum", TransactionType: models.TransactionTypeAirdrop, Quantity: 0.4, Amount: 0, Currency: models.CurrencyTWD},
+	}
+
+	calculator := NewFIFOCalculator(newMockExchangeRateForTWD())
+
+	// Act
+	holding, err := calculator.CalculateHoldingForSymbol("ETH", transactions)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.InDelta(t, 2.5, holding.Quantity, 1e-9)
+	assert.InDelta(t, 212000, holding.TotalCost, 0.01)
+}
+
+// TestFIFO_ShortAndPartialCover 測試放空建立空頭批次、回補依 FIFO 沖銷
+func TestFIFO_ShortAndPartialCover(t *testing.T) {
+	// Arrange: 兩次放空後回補 15 股
+	transactions := []*models.Transaction{
+		{Date: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), AssetType: models.AssetTypeTWStock, Symbol: "2603", Name: "長榮", TransactionType: models.TransactionTypeShort, Quantity: 10, Amount: 2000, Fee: ptrFloat64(20), Currency: models.CurrencyTWD},
+		{Date: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), AssetType: models.AssetTypeTWStock, Symbol: "2603", Name: "長榮", TransactionType: models.TransactionTypeShort, Quantity: 10, Amount: 1800, Currency: models.CurrencyTWD},
+		{Date: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), AssetType: models.AssetTypeTWStock, Symbol: "2603", Name: "長榮", TransactionType: models.TransactionTypeCover, Quantity: 15, Amount: 2400, Currency: models.CurrencyTWD},
+	}
+	cover := &models.Transaction{Date: time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC), AssetType: models.AssetTypeTWStock, Symbol: "2603", TransactionType: models.TransactionTypeCover, Quantity: 5, Amount: 700, Currency: models.CurrencyTWD}
+
+	calculator := NewFIFOCalculator(newMockExchangeRateForTWD())
+
+	// Act
+	holding, err := calculator.CalculateHoldingForSymbol("2603", transactions)
+	closing, closingErr := calculator.CalculateClosingBasis("2603", cover, append(transactions, cover))
+
+	// Assert: 剩餘 5 股空頭部位來自第二批（每股所得 180），數量與成本皆為負數
+	assert.NoError(t, err)
+	assert.True(t, holding.IsShort)
+	assert.InDelta(t, -5, holding.Quantity, 1e-9)
+	assert.InDelta(t, -900, holding.TotalCost, 0.01)
+	assert.InDelta(t, 180, holding.AvgCost, 0.01)
+	assert.NoError(t, closingErr)
+	assert.True(t, closing.IsShort)
+	assert.InDelta(t, 900, closing.Basis, 0.01)
+}
+
+// TestFIFO_ShortPositionConflictsBecomeWarnings 測試持有空頭部位時買進會產生警告而非中斷計算
+func TestFIFO_ShortPositionConflictsBecomeWarnings(t *testing.T) {
+	// Arrange
+	transactions := []*models.Transaction{
+		{Date: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), AssetType: models.AssetTypeUSStock, Symbol: "TSLA", Name: "Tesla", TransactionType: models.TransactionTypeShort, Quantity: 5, Amount: 1000, Currency: models.CurrencyTWD},
+		{Date: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), AssetType: models.AssetTypeUSStock, Symbol: "TSLA", Name: "Tesla", TransactionType: models.TransactionTypeBuy, Quantity: 5, Amount: 900, Currency: models.CurrencyTWD},
+		{Date: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), AssetType: models.AssetTypeTWStock, Symbol: "2330", Name: "台積電", TransactionType: models.TransactionTypeBuy, Quantity: 10, Amount: 5000, Currency: models.CurrencyTWD},
+	}
+
+	calculator := NewFIFOCalculator(newMockExchangeRateForTWD())
+
+	// Act
+	result, err := calculator.CalculateAllHoldings(transactions)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, result.Holdings, 1)
+	assert.NotNil(t, result.Holdings["2330"])
+	assert.Len(t, result.Warnings, 1)
+	assert.Equal(t, models.WarningCodePositionConflict, result.Warnings[0].Code)
+	assert.Equal(t, "TSLA", result.Warnings[0].Symbol)
+}
+
+// TestFIFO_OptionExpirationClosesPosition 測試賣出選擇權到期失效後部位歸零，權利金全數實現
+func TestFIFO_OptionExpirationClosesPosition(t *testing.T) {
+	// Arrange: 賣出 2 口買權，權利金每股 1.5（乘數 100）
+	symbol := "AAPL251219C00250000"
+	transactions := []*models.Transaction{
+		{Date: time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC), AssetType: models.AssetTypeUSOption, Symbol: symbol, Name: "AAPL 250 Call", TransactionType: models.TransactionTypeShort, Quantity: 2, Price: 1.5, Amount: 300, Fee: ptrFloat64(2), Currency: models.CurrencyTWD},
+	}
+	expiration := &models.Transaction{Date: time.Date(2025, 12, 19, 0, 0, 0, 0, time.UTC), AssetType: models.AssetTypeUSOption, Symbol: symbol, TransactionType: models.TransactionTypeExpiration, Quantity: 2, Currency: models.CurrencyTWD}
+	all := append(transactions, expiration)
+
+	calculator := NewFIFOCalculator(newMockExchangeRateForTWD())
+
+	// Act
+	closing, err := calculator.CalculateClosingBasis(symbol, expiration, all)
+	holding, holdingErr := calculator.CalculateHoldingForSymbol(symbol, all)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.True(t, closing.IsShort)
+	assert.InDelta(t, 298, closing.Basis, 0.01)
+	assert.NoError(t, holdingErr)
+	assert.Nil(t, holding)
+}
+
 // ==================== 輔助函式 ====================
 
 // ptrFloat64 建立 float64 指標（方便測試）
@@ -3,6 +3,8 @@ package service
 import (
 	"fmt"
 	"log"
+	"math"
+	"time"
 
 	"github.com/chienchuanw/asset-manager/internal/models"
 	"github.com/chienchuanw/asset-manager/internal/repository"
@@ -33,6 +35,7 @@ type holdingService struct {
 	fifoCalculator    FIFOCalculator
 	priceService      PriceService
 	exchangeRateService ExchangeRateService
+	optionContractRepo  repository.OptionContractRepository
 }
 
 // NewHoldingService 建立新的持倉服務
@@ -41,12 +44,14 @@ func NewHoldingService(
 	fifoCalculator FIFOCalculator,
 	priceService PriceService,
 	exchangeRateService ExchangeRateService,
+	optionContractRepo repository.OptionContractRepository,
 ) HoldingService {
 	return &holdingService{
 		transactionRepo:   transactionRepo,
 		fifoCalculator:    fifoCalculator,
 		priceService:      priceService,
 		exchangeRateService: exchangeRateService,
+		optionContractRepo:  optionContractRepo,
 	}
 }
 
@@ -56,8 +61,9 @@ func (s *holdingService) GetAllHoldings(filters models.HoldingFilters) (*Holding
 
 	// 1. 從 Repository 取得交易記錄
 	txFilters := repository.TransactionFilters{
-		AssetType: filters.AssetType,
-		Symbol:    filters.Symbol,
+		AssetType:          filters.AssetType,
+		Symbol:             filters.Symbol,
+		BrokerageAccountID: filters.BrokerageAccountID,
 	}
 
 	log.Println("[DEBUG] Step 1: Fetching transactions from repository...")
@@ -97,12 +103,11 @@ func (s *holdingService) GetAllHoldings(filters models.HoldingFilters) (*Holding
 
 	// 3. 準備批次取得價格
 	log.Println("[DEBUG] Step 3: Preparing to fetch prices...")
-	symbols := make([]string, 0, len(result.Holdings))
-	assetTypes := make(map[string]models.AssetType)
+	assetTypes := priceTargets(result.Holdings, s.optionContractRepo)
 
-	for symbol, holding := range result.Holdings {
+	symbols := make([]string, 0, len(assetTypes))
+	for symbol := range assetTypes {
 		symbols = append(symbols, symbol)
-		assetTypes[symbol] = holding.AssetType
 	}
 	log.Printf("[DEBUG] Step 3: Need to fetch prices for %d symbols: %v", len(symbols), symbols)
 
@@ -127,7 +132,11 @@ func (s *holdingService) GetAllHoldings(filters models.HoldingFilters) (*Holding
 			symbol, holding.AssetType, holding.Quantity)
 
 		price, exists := prices[symbol]
-		if exists && price.Price > 0 {
+		if holding.OptionContract != nil {
+			price, exists = optionIntrinsicPrice(holding.OptionContract, prices)
+		}
+		// 價外選擇權的內含價值為 0，仍屬有效價格
+		if exists && (price.Price > 0 || holding.OptionContract != nil) {
 			log.Printf("[DEBUG] Price found for %s: %.4f (Source: %s, IsStale: %v)",
 				symbol, price.Price, price.Source, price.IsStale)
 
@@ -156,19 +165,11 @@ func (s *holdingService) GetAllHoldings(filters models.HoldingFilters) (*Holding
 
 			holding.CurrentPriceTWD = priceTWD
 
-			// 計算市值（TWD）
-			holding.MarketValue = holding.Quantity * priceTWD
-			holding.UnrealizedPL = holding.MarketValue - holding.TotalCost
-
-			// 計算未實現損益百分比
-			if holding.TotalCost > 0 {
-				holding.UnrealizedPLPct = (holding.UnrealizedPL / holding.TotalCost) * 100
-			}
+			// 計算市值與未實現損益（TWD）
+			applyMarketValue(holding, priceTWD)
 
 			// 傳遞價格來源資訊
-			holding.PriceSource = price.Source
-			holding.IsPriceStale = price.IsStale
-			holding.PriceStaleReason = price.StaleReason
+			applyPriceSource(holding, price)
 
 			log.Printf("[DEBUG] Calculated P&L for %s: MarketValue=%.2f, UnrealizedPL=%.2f (%.2f%%)",
 				symbol, holding.MarketValue, holding.UnrealizedPL, holding.UnrealizedPLPct)
@@ -234,10 +235,25 @@ func (s *holdingService) GetHoldingBySymbol(symbol string) (*models.Holding, err
 		return nil, fmt.Errorf("holding not found for symbol: %s (all sold)", symbol)
 	}
 
-	// 3. 取得價格
-	price, err := s.priceService.GetPrice(symbol, holding.AssetType)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get price: %w", err)
+	// 3. 取得價格（選擇權以標的股票報價計算內含價值）
+	var price *models.Price
+	if holding.AssetType == models.AssetTypeUSOption {
+		if err := attachOptionContract(s.optionContractRepo, holding); err != nil {
+			return nil, err
+		}
+		underlying, err := s.priceService.GetPrice(holding.OptionContract.UnderlyingSymbol, models.AssetTypeUSStock)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get underlying price: %w", err)
+		}
+		price, _ = optionIntrinsicPrice(holding.OptionContract, map[string]*models.Price{underlying.Symbol: underlying})
+		if price == nil {
+			return nil, fmt.Errorf("failed to get underlying price for %s", symbol)
+		}
+	} else {
+		price, err = s.priceService.GetPrice(symbol, holding.AssetType)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get price: %w", err)
+		}
 	}
 
 	// 4. 整合價格資訊並計算損益（統一轉換為 TWD）
@@ -256,21 +272,99 @@ func (s *holdingService) GetHoldingBySymbol(symbol string) (*models.Holding, err
 	}
 	holding.CurrentPriceTWD = priceTWD
 
-	// 計算市值（TWD）
-	holding.MarketValue = holding.Quantity * priceTWD
-	holding.UnrealizedPL = holding.MarketValue - holding.TotalCost
-
-	// 計算未實現損益百分比
-	if holding.TotalCost > 0 {
-		holding.UnrealizedPLPct = (holding.UnrealizedPL / holding.TotalCost) * 100
-	}
+	// 計算市值與未實現損益（TWD）
+	applyMarketValue(holding, priceTWD)
 
 	// 傳遞價格來源資訊
+	applyPriceSource(holding, price)
+
+	return holding, nil
+}
+
+// applyPriceSource 傳遞價格來源、過期狀態與價格年齡
+func applyPriceSource(holding *models.Holding, price *models.Price) {
 	holding.PriceSource = price.Source
 	holding.IsPriceStale = price.IsStale
 	holding.PriceStaleReason = price.StaleReason
 
-	return holding, nil
+	if !price.UpdatedAt.IsZero() {
+		updatedAt := price.UpdatedAt
+		holding.PriceUpdatedAt = &updatedAt
+		holding.PriceAgeSeconds = int64(time.Since(updatedAt).Seconds())
+	}
+}
+
+// priceTargets 取得持倉需要報價的標的與資產類型
+// 選擇權改以標的股票報價計算內含價值，找不到合約規格時不取價（以成本作為市值）
+func priceTargets(holdings map[string]*models.Holding, optionContractRepo repository.OptionContractRepository) map[string]models.AssetType {
+	assetTypes := make(map[string]models.AssetType)
+
+	for symbol, holding := range holdings {
+		if holding.AssetType == models.AssetTypeUSOption {
+			if err := attachOptionContract(optionContractRepo, holding); err != nil {
+				log.Printf("[WARNING] %v", err)
+				continue
+			}
+			assetTypes[holding.OptionContract.UnderlyingSymbol] = models.AssetTypeUSStock
+			continue
+		}
+		assetTypes[symbol] = holding.AssetType
+	}
+
+	return assetTypes
+}
+
+// attachOptionContract 載入選擇權持倉的合約規格與乘數
+func attachOptionContract(optionContractRepo repository.OptionContractRepository, holding *models.Holding) error {
+	if optionContractRepo == nil {
+		return fmt.Errorf("option contract repository is not configured for %s", holding.Symbol)
+	}
+
+	contract, err := optionContractRepo.GetBySymbol(holding.Symbol)
+	if err != nil {
+		return fmt.Errorf("failed to get option contract for %s: %w", holding.Symbol, err)
+	}
+
+	holding.OptionContract = contract
+	holding.Multiplier = contract.Multiplier
+	return nil
+}
+
+// optionIntrinsicPrice 以標的股票報價計算選擇權的每股內含價值
+// 合約已過到期日但尚未登錄到期或履約事件時，標記為過期價格提醒使用者補登
+func optionIntrinsicPrice(contract *models.OptionContract, prices map[string]*models.Price) (*models.Price, bool) {
+	underlying, exists := prices[contract.UnderlyingSymbol]
+	if !exists || underlying.Price <= 0 {
+		return nil, false
+	}
+
+	price := *underlying
+	price.Symbol = contract.Symbol
+	price.AssetType = models.AssetTypeUSOption
+	price.Price = contract.IntrinsicValue(underlying.Price)
+	price.Source = "intrinsic"
+	if contract.IsExpired(time.Now()) {
+		price.IsStale = true
+		price.StaleReason = "Option contract expired without expiration or assignment event"
+	}
+
+	return &price, true
+}
+
+// applyMarketValue 依 TWD 價格計算持倉市值與未實現損益
+// 空頭部位的數量與成本皆為負數，市值亦為負數（回補所需金額），損益百分比以成本絕對值計算
+func applyMarketValue(holding *models.Holding, priceTWD float64) {
+	multiplier := holding.Multiplier
+	if multiplier == 0 {
+		multiplier = 1
+	}
+
+	holding.MarketValue = holding.Quantity * priceTWD * multiplier
+	holding.UnrealizedPL = holding.MarketValue - holding.TotalCost
+
+	if holding.TotalCost != 0 {
+		holding.UnrealizedPLPct = (holding.UnrealizedPL / math.Abs(holding.TotalCost)) * 100
+	}
 }
 
 // getCurrencyForAssetType 根據資產類型取得幣別
@@ -278,7 +372,7 @@ func (s *holdingService) getCurrencyForAssetType(assetType models.AssetType) mod
 	switch assetType {
 	case models.AssetTypeTWStock:
 		return models.CurrencyTWD
-	case models.AssetTypeUSStock:
+	case models.AssetTypeUSStock, models.AssetTypeUSOption:
 		return models.CurrencyUSD
 	case models.AssetTypeCrypto:
 		return models.CurrencyUSD // 加密貨幣使用 USD 計價
@@ -20,7 +20,7 @@ func TestFixInsufficientQuantity_Success(t *testing.T) {
 	mockPriceService := new(MockPriceService)
 	mockExchangeRateService := new(MockExchangeRateService)
 
-	service := NewHoldingService(mockRepo, mockFIFOCalc, mockPriceService, mockExchangeRateService)
+	service := NewHoldingService(mockRepo, mockFIFOCalc, mockPriceService, mockExchangeRateService, nil)
 
 	symbol := "2330"
 	currentHolding := 100.0
@@ -113,7 +113,7 @@ func TestFixInsufficientQuantity_WithEstimatedCost(t *testing.T) {
 	mockPriceService := new(MockPriceService)
 	mockExchangeRateService := new(MockExchangeRateService)
 
-	service := NewHoldingService(mockRepo, mockFIFOCalc, mockPriceService, mockExchangeRateService)
+	service := NewHoldingService(mockRepo, mockFIFOCalc, mockPriceService, mockExchangeRateService, nil)
 
 	symbol := "2330"
 	currentHolding := 100.0
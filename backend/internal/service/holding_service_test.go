@@ -117,6 +117,14 @@ func (m *MockPriceService) RefreshPrice(symbol string, assetType models.AssetTyp
 	return args.Get(0).(*models.Price), args.Error(1)
 }
 
+func (m *MockPriceService) RefreshPrices(symbols []string, assetTypes map[string]models.AssetType) (map[string]*models.Price, error) {
+	args := m.Called(symbols, assetTypes)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]*models.Price), args.Error(1)
+}
+
 // MockExchangeRateService Exchange Rate Service 的 Mock
 type MockExchangeRateService struct {
 	mock.Mock
@@ -159,7 +167,7 @@ func TestGetAllHoldings_Success(t *testing.T) {
 	mockPriceService := new(MockPriceService)
 	mockExchangeRateService := new(MockExchangeRateService)
 	fifoCalculator := NewFIFOCalculator(mockExchangeRateService)
-	service := NewHoldingService(mockRepo, fifoCalculator, mockPriceService, mockExchangeRateService)
+	service := NewHoldingService(mockRepo, fifoCalculator, mockPriceService, mockExchangeRateService, nil)
 
 	// 準備交易記錄
 	transactions := []*models.Transaction{
@@ -250,7 +258,7 @@ func TestGetAllHoldings_EmptyTransactions(t *testing.T) {
 	mockPriceService := new(MockPriceService)
 	mockExchangeRateService := new(MockExchangeRateService)
 	fifoCalculator := NewFIFOCalculator(mockExchangeRateService)
-	service := NewHoldingService(mockRepo, fifoCalculator, mockPriceService, mockExchangeRateService)
+	service := NewHoldingService(mockRepo, fifoCalculator, mockPriceService, mockExchangeRateService, nil)
 
 	// Mock 設定
 	mockRepo.On("GetAll", mock.Anything).Return([]*models.Transaction{}, nil)
@@ -273,7 +281,7 @@ func TestGetAllHoldings_WithAssetTypeFilter(t *testing.T) {
 	mockPriceService := new(MockPriceService)
 	mockExchangeRateService := new(MockExchangeRateService)
 	fifoCalculator := NewFIFOCalculator(mockExchangeRateService)
-	service := NewHoldingService(mockRepo, fifoCalculator, mockPriceService, mockExchangeRateService)
+	service := NewHoldingService(mockRepo, fifoCalculator, mockPriceService, mockExchangeRateService, nil)
 
 	assetType := models.AssetTypeTWStock
 	filters := models.HoldingFilters{
@@ -334,7 +342,7 @@ func TestGetHoldingBySymbol_Success(t *testing.T) {
 	mockPriceService := new(MockPriceService)
 	mockExchangeRateService := new(MockExchangeRateService)
 	fifoCalculator := NewFIFOCalculator(mockExchangeRateService)
-	service := NewHoldingService(mockRepo, fifoCalculator, mockPriceService, mockExchangeRateService)
+	service := NewHoldingService(mockRepo, fifoCalculator, mockPriceService, mockExchangeRateService, nil)
 
 	symbol := "2330"
 	transactions := []*models.Transaction{
@@ -382,6 +390,55 @@ func TestGetHoldingBySymbol_Success(t *testing.T) {
 	mockPriceService.AssertExpectations(t)
 }
 
+// TestGetHoldingBySymbol_StalePriceReportsAge 測試使用最後已知價格時回報價格年齡
+func TestGetHoldingBySymbol_StalePriceReportsAge(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockTransactionRepositoryForHolding)
+	mockPriceService := new(MockPriceService)
+	mockExchangeRateService := new(MockExchangeRateService)
+	fifoCalculator := NewFIFOCalculator(mockExchangeRateService)
+	service := NewHoldingService(mockRepo, fifoCalculator, mockPriceService, mockExchangeRateService, nil)
+
+	transactions := []*models.Transaction{
+		{
+			Date:            time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			AssetType:       models.AssetTypeTWStock,
+			Symbol:          "2330",
+			Name:            "台積電",
+			TransactionType: models.TransactionTypeBuy,
+			Quantity:        100,
+			Price:           500,
+			Amount:          50000,
+		},
+	}
+
+	fetchedAt := time.Now().Add(-26 * time.Hour)
+	price := newLastKnownStalePrice(&models.Price{
+		Symbol:    "2330",
+		AssetType: models.AssetTypeTWStock,
+		Price:     600,
+		Currency:  "TWD",
+		Source:    PriceProviderFinMind,
+		UpdatedAt: fetchedAt,
+	}, "All live price providers failed")
+
+	mockRepo.On("GetAll", mock.Anything).Return(transactions, nil)
+	mockPriceService.On("GetPrice", "2330", models.AssetTypeTWStock).Return(price, nil)
+	mockExchangeRateService.On("ConvertToTWD", 600.0, models.CurrencyTWD, mock.Anything).Return(600.0, nil)
+
+	// Act
+	holding, err := service.GetHoldingBySymbol("2330")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 60000.0, holding.MarketValue)
+	assert.Equal(t, PriceProviderLastKnown, holding.PriceSource)
+	assert.True(t, holding.IsPriceStale)
+	assert.Contains(t, holding.PriceStaleReason, "1d 2h old")
+	assert.True(t, holding.PriceUpdatedAt.Equal(fetchedAt))
+	assert.InDelta(t, 26*60*60, holding.PriceAgeSeconds, 5)
+}
+
 // TestGetHoldingBySymbol_NotFound 測試標的不存在
 func TestGetHoldingBySymbol_NotFound(t *testing.T) {
 	// Arrange
@@ -389,7 +446,7 @@ func TestGetHoldingBySymbol_NotFound(t *testing.T) {
 	mockPriceService := new(MockPriceService)
 	mockExchangeRateService := new(MockExchangeRateService)
 	fifoCalculator := NewFIFOCalculator(mockExchangeRateService)
-	service := NewHoldingService(mockRepo, fifoCalculator, mockPriceService, mockExchangeRateService)
+	service := NewHoldingService(mockRepo, fifoCalculator, mockPriceService, mockExchangeRateService, nil)
 
 	symbol := "9999"
 
@@ -407,3 +464,64 @@ func TestGetHoldingBySymbol_NotFound(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+// TestGetAllHoldings_ShortOptionValuedAtIntrinsicValue 測試賣出買權以標的價格計算內含價值並乘上合約乘數
+func TestGetAllHoldings_ShortOptionValuedAtIntrinsicValue(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockTransactionRepositoryForHolding)
+	mockPriceService := new(MockPriceService)
+	mockExchangeRateService := new(MockExchangeRateService)
+	mockOptionRepo := new(MockOptionContractRepository)
+	fifoCalculator := NewFIFOCalculator(mockExchangeRateService)
+	service := NewHoldingService(mockRepo, fifoCalculator, mockPriceService, mockExchangeRateService, mockOptionRepo)
+
+	symbol := "AAPL991217C00200000"
+	tradeDate := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+	transactions := []*models.Transaction{
+		{
+			Date:            tradeDate,
+			AssetType:       models.AssetTypeUSOption,
+			Symbol:          symbol,
+			Name:            "AAPL 200 Call",
+			TransactionType: models.TransactionTypeShort,
+			Quantity:        1,
+			Price:           5,
+			Amount:          500,
+			Currency:        models.CurrencyUSD,
+		},
+	}
+	contract := &models.OptionContract{
+		Symbol:           symbol,
+		UnderlyingSymbol: "AAPL",
+		OptionType:       models.OptionTypeCall,
+		StrikePrice:      200,
+		ExpiryDate:       time.Date(2099, 12, 17, 0, 0, 0, 0, time.UTC),
+		Multiplier:       100,
+	}
+	prices := map[string]*models.Price{
+		"AAPL": {Symbol: "AAPL", AssetType: models.AssetTypeUSStock, Price: 210, Currency: "USD", Source: "mock", UpdatedAt: time.Now()},
+	}
+
+	mockRepo.On("GetAll", mock.Anything).Return(transactions, nil)
+	mockOptionRepo.On("GetBySymbol", symbol).Return(contract, nil)
+	// 只向價格服務查詢標的股票報價
+	mockPriceService.On("GetPrices", []string{"AAPL"}, map[string]models.AssetType{"AAPL": models.AssetTypeUSStock}).Return(prices, nil)
+	mockExchangeRateService.On("ConvertToTWD", 500.0, models.CurrencyUSD, tradeDate).Return(15000.0, nil)
+	mockExchangeRateService.On("ConvertToTWD", 10.0, models.CurrencyUSD, mock.Anything).Return(300.0, nil)
+
+	// Act
+	result, err := service.GetAllHoldings(models.HoldingFilters{})
+
+	// Assert: 空頭部位市值 = -1 口 * 每股內含價值 300 TWD * 100 股
+	assert.NoError(t, err)
+	assert.Len(t, result.Holdings, 1)
+	holding := result.Holdings[0]
+	assert.True(t, holding.IsShort)
+	assert.Equal(t, 100.0, holding.Multiplier)
+	assert.Equal(t, 10.0, holding.CurrentPrice)
+	assert.Equal(t, "intrinsic", holding.PriceSource)
+	assert.InDelta(t, -15000, holding.TotalCost, 0.01)
+	assert.InDelta(t, -30000, holding.MarketValue, 0.01)
+	assert.InDelta(t, -15000, holding.UnrealizedPL, 0.01)
+	assert.InDelta(t, -100, holding.UnrealizedPLPct, 0.01)
+	mockPriceService.AssertExpectations(t)
+}
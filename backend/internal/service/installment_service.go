@@ -1,6 +1,7 @@
 package service
 
 import (
+	"database/sql"
 	"fmt"
 	"time"
 
@@ -18,22 +19,30 @@ type InstallmentService interface {
 	DeleteInstallment(id uuid.UUID) error
 	GetDueBillings(date time.Time) ([]*models.Installment, error)
 	GetCompletingSoon(remainingCount int) ([]*models.Installment, error)
+	AddPrepayment(id uuid.UUID, input *models.CreateInstallmentPrepaymentInput) (*models.InstallmentPrepayment, error)
+	PayOff(id uuid.UUID, input *models.InstallmentPayoffInput) (*models.InstallmentPrepayment, error)
 }
 
 // installmentService 分期業務邏輯實作
 type installmentService struct {
-	repo         repository.InstallmentRepository
-	categoryRepo repository.CategoryRepository
+	repo                repository.InstallmentRepository
+	categoryRepo        repository.CategoryRepository
+	cashFlowService     CashFlowService
+	exchangeRateService ExchangeRateService
 }
 
 // NewInstallmentService 建立新的分期 service
 func NewInstallmentService(
 	repo repository.InstallmentRepository,
 	categoryRepo repository.CategoryRepository,
+	cashFlowService CashFlowService,
+	exchangeRateService ExchangeRateService,
 ) InstallmentService {
 	return &installmentService{
-		repo:         repo,
-		categoryRepo: categoryRepo,
+		repo:                repo,
+		categoryRepo:        categoryRepo,
+		cashFlowService:     cashFlowService,
+		exchangeRateService: exchangeRateService,
 	}
 }
 
@@ -97,9 +106,28 @@ func (s *installmentService) CreateInstallment(input *models.CreateInstallmentIn
 	return installment, nil
 }
 
-// GetInstallment 取得單筆分期
+// GetInstallment 取得單筆分期（含提前還款記錄與付款計畫）
 func (s *installmentService) GetInstallment(id uuid.UUID) (*models.Installment, error) {
-	return s.repo.GetByID(id)
+	installment, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	prepayments, err := s.repo.GetPrepayments(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get prepayments: %w", err)
+	}
+	installment.Prepayments = prepayments
+
+	payments, err := s.repo.GetPayments(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payments: %w", err)
+	}
+
+	installment.Schedule = installment.GenerateSchedule()
+	linkScheduleCashFlows(installment.Schedule, payments, prepayments)
+
+	return installment, nil
 }
 
 // ListInstallments 取得分期列表
@@ -192,3 +220,184 @@ func (s *installmentService) GetCompletingSoon(remainingCount int) ([]*models.In
 	return s.repo.GetCompletingSoon(remainingCount)
 }
 
+// AddPrepayment 新增部分提前還款
+// 提前還款於目前已付期數之後套用，之後每期金額依剩餘本金重新攤提；金額等於剩餘本金時視為提前清償
+func (s *installmentService) AddPrepayment(id uuid.UUID, input *models.CreateInstallmentPrepaymentInput) (*models.InstallmentPrepayment, error) {
+	installment, err := s.GetInstallment(id)
+	if err != nil {
+		return nil, fmt.Errorf("installment not found: %w", err)
+	}
+
+	// 只有進行中的分期可以提前還款
+	if !installment.IsActive() {
+		return nil, fmt.Errorf("installment is not active")
+	}
+
+	// 驗證金額
+	if input.Amount <= 0 {
+		return nil, fmt.Errorf("prepayment amount must be greater than zero")
+	}
+
+	// 驗證日期
+	if input.Date.IsZero() {
+		return nil, fmt.Errorf("prepayment date is required")
+	}
+
+	if input.Date.Before(installment.StartDate) {
+		return nil, fmt.Errorf("prepayment date cannot be before installment start date")
+	}
+
+	// 提前還款金額不可超過剩餘本金
+	remaining := installment.Schedule.RemainingPrincipal
+	if roundToCents(input.Amount) > remaining {
+		return nil, fmt.Errorf("prepayment amount %.2f exceeds remaining principal %.2f", input.Amount, remaining)
+	}
+
+	isPayoff := roundToCents(input.Amount) == remaining
+	return s.recordPrepayment(installment, input.Date, roundToCents(input.Amount), isPayoff, input.Note)
+}
+
+// PayOff 提前清償分期，以剩餘本金一次付清並免除之後的利息
+func (s *installmentService) PayOff(id uuid.UUID, input *models.InstallmentPayoffInput) (*models.InstallmentPrepayment, error) {
+	installment, err := s.GetInstallment(id)
+	if err != nil {
+		return nil, fmt.Errorf("installment not found: %w", err)
+	}
+
+	// 只有進行中的分期可以提前清償
+	if !installment.IsActive() {
+		return nil, fmt.Errorf("installment is not active")
+	}
+
+	// 驗證日期
+	if input.Date.IsZero() {
+		return nil, fmt.Errorf("payoff date is required")
+	}
+
+	if input.Date.Before(installment.StartDate) {
+		return nil, fmt.Errorf("payoff date cannot be before installment start date")
+	}
+
+	remaining := installment.Schedule.RemainingPrincipal
+	if remaining <= 0 {
+		return nil, fmt.Errorf("installment has no remaining principal")
+	}
+
+	return s.recordPrepayment(installment, input.Date, remaining, true, input.Note)
+}
+
+// recordPrepayment 在資料庫交易中建立提前還款的現金流與記錄，提前清償時同時將分期標記為已完成
+func (s *installmentService) recordPrepayment(installment *models.Installment, date time.Time, amount float64, isPayoff bool, note *string) (*models.InstallmentPrepayment, error) {
+	amountTWD, err := s.convertToTWD(amount, installment.Currency, date)
+	if err != nil {
+		return nil, err
+	}
+
+	label := "提前還款"
+	if isPayoff {
+		label = "提前清償"
+	}
+
+	// 根據分期的付款方式設定現金流的來源類型
+	sourceType := installment.PaymentMethod.ToSourceType()
+	cashFlowInput := &models.CreateCashFlowInput{
+		Date:        date,
+		Type:        models.CashFlowTypeExpense,
+		CategoryID:  installment.CategoryID,
+		Amount:      roundToCents(amountTWD),
+		Description: billingDescription(fmt.Sprintf("%s - %s", installment.Name, label), amount, installment.Currency),
+		SourceType:  &sourceType,
+		SourceID:    installment.AccountID,
+		Note:        note,
+	}
+
+	var prepayment *models.InstallmentPrepayment
+	err = s.runInTx(func(tx *sql.Tx) error {
+		cashFlow, err := s.cashFlowService.CreateCashFlowTx(tx, cashFlowInput)
+		if err != nil {
+			return fmt.Errorf("failed to create cash flow: %w", err)
+		}
+
+		created, err := s.repo.CreatePrepaymentTx(tx, &models.InstallmentPrepayment{
+			InstallmentID: installment.ID,
+			Date:          date,
+			Amount:        amount,
+			AfterPeriod:   installment.PaidCount,
+			IsPayoff:      isPayoff,
+			CashFlowID:    &cashFlow.ID,
+			Note:          note,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add prepayment: %w", err)
+		}
+
+		// 提前清償後不再扣款
+		if isPayoff {
+			if err := s.repo.UpdateProgressTx(tx, installment.ID, installment.PaidCount, models.InstallmentStatusCompleted); err != nil {
+				return fmt.Errorf("failed to update installment: %w", err)
+			}
+		}
+
+		prepayment = created
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return prepayment, nil
+}
+
+// runInTx 在資料庫交易中執行 fn，fn 回傳錯誤時回滾
+func (s *installmentService) runInTx(fn func(tx *sql.Tx) error) error {
+	tx, err := s.repo.DB().Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// convertToTWD 將金額換算為 TWD（現金流一律以 TWD 記錄）
+func (s *installmentService) convertToTWD(amount float64, currency models.Currency, date time.Time) (float64, error) {
+	if currency == models.CurrencyTWD || currency == "" {
+		return amount, nil
+	}
+
+	if s.exchangeRateService == nil {
+		return 0, fmt.Errorf("exchange rate service is not configured for currency: %s", currency)
+	}
+
+	converted, err := s.exchangeRateService.ConvertToTWD(amount, currency, date)
+	if err != nil {
+		return 0, fmt.Errorf("failed to convert %s to TWD: %w", currency, err)
+	}
+
+	return converted, nil
+}
+
+// linkScheduleCashFlows 將每期扣款與提前還款的現金流關聯到付款計畫
+func linkScheduleCashFlows(schedule *models.InstallmentSchedule, payments []*models.InstallmentPayment, prepayments []*models.InstallmentPrepayment) {
+	for index := range schedule.Entries {
+		entry := &schedule.Entries[index]
+		for _, payment := range payments {
+			if payment.Period == entry.Period && payment.CashFlowID != nil {
+				entry.CashFlowIDs = append(entry.CashFlowIDs, *payment.CashFlowID)
+			}
+		}
+		for _, prepayment := range prepayments {
+			if prepayment.AfterPeriod == entry.Period && prepayment.CashFlowID != nil {
+				entry.CashFlowIDs = append(entry.CashFlowIDs, *prepayment.CashFlowID)
+			}
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/chienchuanw/asset-manager/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// newTestServiceInstallment 建立測試用分期（無息，每期 3000，已付 4 期）
+func newTestServiceInstallment(id uuid.UUID) *models.Installment {
+	return &models.Installment{
+		ID:                id,
+		Name:              "iPhone 15 Pro",
+		TotalAmount:       36000,
+		Currency:          models.CurrencyTWD,
+		InstallmentCount:  12,
+		InstallmentAmount: 3000,
+		PaidCount:         4,
+		BillingDay:        15,
+		CategoryID:        uuid.New(),
+		PaymentMethod:     models.PaymentMethodCash,
+		StartDate:         time.Date(2025, 5, 15, 0, 0, 0, 0, time.UTC),
+		Status:            models.InstallmentStatusActive,
+	}
+}
+
+func TestInstallmentService_GetInstallment_LinksCashFlows(t *testing.T) {
+	mockRepo := new(MockInstallmentRepository)
+	service := NewInstallmentService(mockRepo, new(MockCategoryRepository), nil, nil)
+
+	installmentID := uuid.New()
+	paymentCashFlowID := uuid.New()
+	prepaymentCashFlowID := uuid.New()
+
+	// 設定 mock 期望
+	mockRepo.On("GetByID", installmentID).Return(newTestServiceInstallment(installmentID), nil)
+	mockRepo.On("GetPrepayments", installmentID).Return([]*models.InstallmentPrepayment{
+		{InstallmentID: installmentID, Amount: 6000, AfterPeriod: 4, CashFlowID: &prepaymentCashFlowID},
+	}, nil)
+	mockRepo.On("GetPayments", installmentID).Return([]*models.InstallmentPayment{
+		{InstallmentID: installmentID, Period: 4, PrincipalAmount: 3000, CashFlowID: &paymentCashFlowID},
+	}, nil)
+
+	// 執行測試
+	installment, err := service.GetInstallment(installmentID)
+
+	// 驗證結果：第 4 期關聯扣款與提前還款的現金流，之後每期金額重新攤提
+	assert.NoError(t, err)
+	assert.NotNil(t, installment.Schedule)
+	assert.Len(t, installment.Schedule.Entries, 12)
+	assert.Equal(t, []uuid.UUID{paymentCashFlowID, prepaymentCashFlowID}, installment.Schedule.Entries[3].CashFlowIDs)
+	assert.Empty(t, installment.Schedule.Entries[4].CashFlowIDs)
+	assert.Equal(t, 18000.0, installment.Schedule.RemainingPrincipal)
+	assert.Equal(t, 2250.0, installment.Schedule.Entries[4].Payment)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestInstallmentService_AddPrepayment_ExceedsRemainingPrincipal(t *testing.T) {
+	mockRepo := new(MockInstallmentRepository)
+	service := NewInstallmentService(mockRepo, new(MockCategoryRepository), nil, nil)
+
+	installmentID := uuid.New()
+
+	// 設定 mock 期望
+	mockRepo.On("GetByID", installmentID).Return(newTestServiceInstallment(installmentID), nil)
+	mockRepo.On("GetPrepayments", installmentID).Return([]*models.InstallmentPrepayment{}, nil)
+	mockRepo.On("GetPayments", installmentID).Return([]*models.InstallmentPayment{}, nil)
+
+	// 執行測試
+	result, err := service.AddPrepayment(installmentID, &models.CreateInstallmentPrepaymentInput{
+		Date:   time.Date(2025, 9, 20, 0, 0, 0, 0, time.UTC),
+		Amount: 30000,
+	})
+
+	// 驗證結果
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "exceeds remaining principal")
+	mockRepo.AssertNotCalled(t, "CreatePrepaymentTx")
+}
+
+func TestInstallmentService_PayOff(t *testing.T) {
+	db, dbMock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mockRepo := new(MockInstallmentRepository)
+	mockCashFlowRepo := new(MockCashFlowRepository)
+
+	installmentID := uuid.New()
+	installment := newTestServiceInstallment(installmentID)
+	cashFlowID := uuid.New()
+
+	cashFlowService := NewCashFlowService(mockCashFlowRepo, newExpenseCategoryRepository(installment.CategoryID), nil, nil)
+	service := NewInstallmentService(mockRepo, new(MockCategoryRepository), cashFlowService, nil)
+
+	// 設定 mock 期望：現金流、提前清償記錄與狀態更新在同一交易中完成
+	dbMock.ExpectBegin()
+	dbMock.ExpectCommit()
+	mockRepo.On("DB").Return(db)
+	mockRepo.On("GetByID", installmentID).Return(installment, nil)
+	mockRepo.On("GetPrepayments", installmentID).Return([]*models.InstallmentPrepayment{}, nil)
+	mockRepo.On("GetPayments", installmentID).Return([]*models.InstallmentPayment{}, nil)
+	mockCashFlowRepo.On("CreateTx", mock.AnythingOfType("*sql.Tx"), mock.MatchedBy(func(input *models.CreateCashFlowInput) bool {
+		return input.Amount == 24000 && input.Description == "iPhone 15 Pro - 提前清償"
+	})).Return(&models.CashFlow{ID: cashFlowID, Amount: 24000}, nil)
+	mockRepo.On("CreatePrepaymentTx", mock.AnythingOfType("*sql.Tx"), mock.MatchedBy(func(prepayment *models.InstallmentPrepayment) bool {
+		return prepayment.Amount == 24000 && prepayment.AfterPeriod == 4 && prepayment.IsPayoff && *prepayment.CashFlowID == cashFlowID
+	})).Return(&models.InstallmentPrepayment{ID: uuid.New(), Amount: 24000, IsPayoff: true}, nil)
+	mockRepo.On("UpdateProgressTx", mock.AnythingOfType("*sql.Tx"), installmentID, 4, models.InstallmentStatusCompleted).Return(nil)
+
+	// 執行測試
+	result, err := service.PayOff(installmentID, &models.InstallmentPayoffInput{
+		Date: time.Date(2025, 9, 20, 0, 0, 0, 0, time.UTC),
+	})
+
+	// 驗證結果
+	assert.NoError(t, err)
+	assert.True(t, result.IsPayoff)
+	assert.Equal(t, 24000.0, result.Amount)
+	mockRepo.AssertExpectations(t)
+	mockCashFlowRepo.AssertExpectations(t)
+	assert.NoError(t, dbMock.ExpectationsWereMet())
+}
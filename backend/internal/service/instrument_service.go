@@ -0,0 +1,412 @@
+package service
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/chienchuanw/asset-manager/internal/external"
+	"github.com/chienchuanw/asset-manager/internal/models"
+	"github.com/chienchuanw/asset-manager/internal/repository"
+)
+
+const (
+	defaultInstrumentSearchLimit = 20
+	maxInstrumentSearchLimit     = 50
+
+	// twStockLotSize 台股一張 1000 股
+	twStockLotSize = 1000
+)
+
+// InstrumentService 商品主檔服務介面
+type InstrumentService interface {
+	// Search 依代碼或名稱搜尋商品（assetType 可為 nil，limit 為 0 時使用預設值）
+	Search(query string, assetType *models.AssetType, limit int) ([]*models.Instrument, error)
+
+	// Import 解析來源清單檔案並寫入商品主檔
+	Import(source models.InstrumentSource, reader io.Reader) (*models.InstrumentImportResult, error)
+}
+
+// instrumentService 商品主檔服務實作
+type instrumentService struct {
+	repo repository.InstrumentRepository
+}
+
+// NewInstrumentService 建立新的商品主檔服務
+func NewInstrumentService(repo repository.InstrumentRepository) InstrumentService {
+	return &instrumentService{repo: repo}
+}
+
+// Search 依代碼或名稱搜尋商品
+func (s *instrumentService) Search(query string, assetType *models.AssetType, limit int) ([]*models.Instrument, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, fmt.Errorf("search query is required")
+	}
+
+	if assetType != nil && !assetType.Validate() {
+		return nil, fmt.Errorf("invalid asset type: %s", *assetType)
+	}
+
+	if limit <= 0 {
+		limit = defaultInstrumentSearchLimit
+	}
+	if limit > maxInstrumentSearchLimit {
+		limit = maxInstrumentSearchLimit
+	}
+
+	return s.repo.Search(query, assetType, limit)
+}
+
+// Import 解析來源清單檔案並寫入商品主檔
+func (s *instrumentService) Import(source models.InstrumentSource, reader io.Reader) (*models.InstrumentImportResult, error) {
+	var instruments []*models.Instrument
+	var skipped int
+	var err error
+
+	switch source {
+	case models.InstrumentSourceTWSE:
+		instruments, skipped, err = parseTWInstrumentCSV(reader, models.ExchangeTWSE)
+	case models.InstrumentSourceTPEx:
+		instruments, skipped, err = parseTWInstrumentCSV(reader, models.ExchangeTPEx)
+	case models.InstrumentSourceEmerging:
+		instruments, skipped, err = parseTWInstrumentCSV(reader, models.ExchangeEmerging)
+	case models.InstrumentSourceUSTickers:
+		instruments, skipped, err = parseUSTickerList(reader)
+	case models.InstrumentSourceCoinGecko:
+		instruments, skipped, err = parseCoinGeckoCoinList(reader)
+	default:
+		return nil, fmt.Errorf("invalid instrument source: %s", source)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s instruments: %w", source, err)
+	}
+
+	result := &models.InstrumentImportResult{
+		Source:  source,
+		Parsed:  len(instruments),
+		Skipped: skipped,
+	}
+	if len(instruments) == 0 {
+		return result, nil
+	}
+
+	imported, err := s.repo.UpsertBatch(instruments)
+	if err != nil {
+		return nil, err
+	}
+	result.Imported = imported
+
+	return result, nil
+}
+
+// lookupInstrument 查詢交易標的是否存在於商品主檔
+// 商品主檔未設定或該資產類型尚未匯入時回傳 (nil, nil)，不限制交易；已匯入但查無代碼時回傳錯誤
+func lookupInstrument(repo repository.InstrumentRepository, symbol string, assetType models.AssetType) (*models.Instrument, error) {
+	if repo == nil {
+		return nil, nil
+	}
+	if assetType != models.AssetTypeTWStock && assetType != models.AssetTypeUSStock && assetType != models.AssetTypeCrypto {
+		return nil, nil
+	}
+
+	instrument, err := repo.GetBySymbol(symbol, assetType)
+	if err != nil {
+		return nil, err
+	}
+	if instrument != nil {
+		return instrument, nil
+	}
+
+	seeded, err := repo.HasAssetType(assetType)
+	if err != nil {
+		return nil, err
+	}
+	if seeded {
+		return nil, fmt.Errorf("unknown %s symbol: %s", assetType, symbol)
+	}
+	return nil, nil
+}
+
+// ==================== 來源清單解析 ====================
+
+// readInstrumentCSV 讀取 CSV 檔案（需為 UTF-8，會移除 BOM），回傳 header 與資料列
+func readInstrumentCSV(reader io.Reader, comma rune) ([]string, [][]string, error) {
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	content = []byte(strings.TrimPrefix(string(content), "\ufeff"))
+	if !utf8.Valid(content) {
+		return nil, nil, fmt.Errorf("file must be UTF-8 encoded")
+	}
+
+	csvReader := csv.NewReader(strings.NewReader(string(content)))
+	csvReader.Comma = comma
+	csvReader.FieldsPerRecord = -1
+	csvReader.LazyQuotes = true
+	csvReader.TrimLeadingSpace = true
+
+	records, err := csvReader.ReadAll()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse file: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, nil, fmt.Errorf("file is empty")
+	}
+
+	header := make([]string, len(records[0]))
+	for i, name := range records[0] {
+		header[i] = strings.TrimSpace(name)
+	}
+	return header, records[1:], nil
+}
+
+// findColumn 依候選名稱（依優先順序）找出欄位索引，找不到時回傳 -1
+func findColumn(header []string, candidates ...string) int {
+	for _, candidate := range candidates {
+		for i, name := range header {
+			if strings.EqualFold(name, candidate) {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// csvField 取得資料列的欄位值（欄位不存在時回傳空字串）
+func csvField(record []string, index int) string {
+	if index < 0 || index >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[index])
+}
+
+// isInstrumentCode 檢查代碼是否為合理的證券代碼（英數字與 . - 組成）
+func isInstrumentCode(code string) bool {
+	if code == "" || len(code) > 20 {
+		return false
+	}
+	for _, r := range code {
+		isAlnum := (r >= '0' && r <= '9') || (r >= 'A' && r <= 'Z') || (r >= 'a' && r <= 'z')
+		if !isAlnum && r != '.' && r != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+// parseTWInstrumentCSV 解析證交所／櫃買中心清單
+// 支援公開資訊觀測站上市（櫃）公司基本資料（公司代號、公司簡稱、英文簡稱）
+// 與 ISIN 證券清單（有價證券代號及名稱，以全形空白分隔）
+// 有「市場別」欄位時依該欄判斷上市、上櫃或興櫃，商品類別依代號編碼規則判斷
+func parseTWInstrumentCSV(reader io.Reader, exchange string) ([]*models.Instrument, int, error) {
+	header, records, err := readInstrumentCSV(reader, ',')
+	if err != nil {
+		return nil, 0, err
+	}
+
+	combinedCol := findColumn(header, "有價證券代號及名稱")
+	codeCol := findColumn(header, "公司代號", "證券代號", "有價證券代號", "股票代號", "代號", "Code")
+	nameCol := findColumn(header, "公司簡稱", "證券名稱", "有價證券名稱", "股票名稱", "公司名稱", "名稱", "Name")
+	nameEnCol := findColumn(header, "英文簡稱", "英文名稱", "English Name")
+	marketCol := findColumn(header, "市場別")
+
+	if combinedCol < 0 && (codeCol < 0 || nameCol < 0) {
+		return nil, 0, fmt.Errorf("missing code or name column")
+	}
+
+	instruments := []*models.Instrument{}
+	seen := make(map[string]bool)
+	skipped := 0
+
+	for _, record := range records {
+		code, name := csvField(record, codeCol), csvField(record, nameCol)
+		if combinedCol >= 0 {
+			parts := strings.Fields(strings.ReplaceAll(csvField(record, combinedCol), "\u3000", " "))
+			if len(parts) >= 2 {
+				code, name = parts[0], strings.Join(parts[1:], " ")
+			} else {
+				code, name = "", ""
+			}
+		}
+
+		if !isInstrumentCode(code) || name == "" || seen[code] {
+			skipped++
+			continue
+		}
+		seen[code] = true
+
+		recordExchange := twExchangeFromMarket(csvField(record, marketCol), exchange)
+		providerIDs := map[string]string{PriceProviderFinMind: code}
+		if quoteSymbol := twYahooSymbol(code, recordExchange); quoteSymbol != "" {
+			providerIDs[PriceProviderYahoo] = quoteSymbol
+		}
+
+		instruments = append(instruments, &models.Instrument{
+			Symbol:      code,
+			Exchange:    recordExchange,
+			NameZhTW:    name,
+			NameEn:      csvField(record, nameEnCol),
+			Currency:    models.CurrencyTWD,
+			AssetType:   models.AssetTypeTWStock,
+			Category:    models.ClassifyTWInstrument(code),
+			LotSize:     twStockLotSize,
+			ProviderIDs: providerIDs,
+		})
+	}
+
+	return instruments, skipped, nil
+}
+
+// twExchangeFromMarket 依 ISIN 清單的市場別（上市、上櫃、興櫃一般板等）判斷交易所，無法判斷時使用匯入來源的交易所
+func twExchangeFromMarket(market string, fallback string) string {
+	switch {
+	case strings.Contains(market, "興櫃"):
+		return models.ExchangeEmerging
+	case strings.Contains(market, "上櫃"):
+		return models.ExchangeTPEx
+	case strings.Contains(market, "上市"):
+		return models.ExchangeTWSE
+	}
+	return fallback
+}
+
+// twYahooSymbol 取得台股在 Yahoo Finance 的代碼（上市加 .TW、上櫃加 .TWO；興櫃無報價，回傳空字串）
+func twYahooSymbol(symbol string, exchange string) string {
+	switch exchange {
+	case models.ExchangeTWSE:
+		return symbol + ".TW"
+	case models.ExchangeTPEx:
+		return symbol + ".TWO"
+	}
+	return ""
+}
+
+// usExchangeNames Nasdaq Trader otherlisted.txt 的交易所代碼
+var usExchangeNames = map[string]string{
+	"A": "NYSE American",
+	"N": "NYSE",
+	"P": "NYSE Arca",
+	"Z": "Cboe BZX",
+	"V": "IEX",
+	"Q": models.ExchangeNASDAQ,
+}
+
+// parseUSTickerList 解析美股代碼清單
+// 支援 Nasdaq Trader 的 nasdaqlisted.txt、otherlisted.txt（以 | 分隔）與 symbol,name[,exchange] 格式的 CSV
+func parseUSTickerList(reader io.Reader) ([]*models.Instrument, int, error) {
+	bufReader := bufio.NewReader(reader)
+	firstLine, err := bufReader.Peek(512)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, 0, fmt.Errorf("failed to read file: %w", err)
+	}
+	comma := ','
+	if strings.Contains(strings.SplitN(string(firstLine), "\n", 2)[0], "|") {
+		comma = '|'
+	}
+
+	header, records, err := readInstrumentCSV(bufReader, comma)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	symbolCol := findColumn(header, "Symbol", "ACT Symbol", "Ticker")
+	nameCol := findColumn(header, "Security Name", "Name", "Company Name")
+	exchangeCol := findColumn(header, "Exchange")
+	testIssueCol := findColumn(header, "Test Issue")
+
+	if symbolCol < 0 || nameCol < 0 {
+		return nil, 0, fmt.Errorf("missing symbol or name column")
+	}
+
+	instruments := []*models.Instrument{}
+	seen := make(map[string]bool)
+	skipped := 0
+
+	for _, record := range records {
+		symbol := strings.ToUpper(csvField(record, symbolCol))
+		name := csvField(record, nameCol)
+
+		// 略過測試代碼與檔案結尾的建立時間列（File Creation Time）
+		if csvField(record, testIssueCol) == "Y" || !isInstrumentCode(symbol) || name == "" || seen[symbol] {
+			skipped++
+			continue
+		}
+		seen[symbol] = true
+
+		// Security Name 格式為「公司名稱 - 證券類別」，只保留公司名稱
+		if index := strings.Index(name, " - "); index > 0 {
+			name = name[:index]
+		}
+
+		exchange := csvField(record, exchangeCol)
+		if mapped, exists := usExchangeNames[exchange]; exists {
+			exchange = mapped
+		}
+		if exchange == "" {
+			exchange = models.ExchangeNASDAQ
+		}
+
+		instruments = append(instruments, &models.Instrument{
+			Symbol:      symbol,
+			Exchange:    exchange,
+			NameEn:      name,
+			Currency:    models.CurrencyUSD,
+			AssetType:   models.AssetTypeUSStock,
+			LotSize:     1,
+			ProviderIDs: map[string]string{PriceProviderYahoo: strings.ReplaceAll(symbol, ".", "-")},
+		})
+	}
+
+	return instruments, skipped, nil
+}
+
+// coinGeckoCoin CoinGecko /coins/list 與 /coins/markets 的共同欄位
+type coinGeckoCoin struct {
+	ID     string `json:"id"`
+	Symbol string `json:"symbol"`
+	Name   string `json:"name"`
+}
+
+// parseCoinGeckoCoinList 解析 CoinGecko 幣種清單
+// 同一代碼可能對應多個幣種：內建對應優先，其次取檔案中第一筆（/coins/markets 依市值排序，第一筆即市值最大者）
+func parseCoinGeckoCoinList(reader io.Reader) ([]*models.Instrument, int, error) {
+	var coins []coinGeckoCoin
+	if err := json.NewDecoder(reader).Decode(&coins); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode coin list: %w", err)
+	}
+
+	instruments := []*models.Instrument{}
+	seen := make(map[string]bool)
+	skipped := 0
+
+	for _, coin := range coins {
+		symbol := strings.ToUpper(strings.TrimSpace(coin.Symbol))
+		if coin.ID == "" || !isInstrumentCode(symbol) || seen[symbol] {
+			skipped++
+			continue
+		}
+		if defaultID, exists := external.DefaultCoinGeckoID(symbol); exists && defaultID != coin.ID {
+			skipped++
+			continue
+		}
+		seen[symbol] = true
+
+		instruments = append(instruments, &models.Instrument{
+			Symbol:      symbol,
+			Exchange:    models.ExchangeCrypto,
+			NameEn:      strings.TrimSpace(coin.Name),
+			Currency:    models.CurrencyUSD,
+			AssetType:   models.AssetTypeCrypto,
+			LotSize:     1,
+			ProviderIDs: map[string]string{PriceProviderCoinGecko: coin.ID},
+		})
+	}
+
+	return instruments, skipped, nil
+}
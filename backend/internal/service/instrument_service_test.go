@@ -0,0 +1,265 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chienchuanw/asset-manager/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeInstrumentRepository 測試用商品主檔 repository
+type fakeInstrumentRepository struct {
+	instruments []*models.Instrument
+	searchLimit int
+}
+
+func (r *fakeInstrumentRepository) UpsertBatch(instruments []*models.Instrument) (int, error) {
+	r.instruments = append(r.instruments, instruments...)
+	return len(instruments), nil
+}
+
+func (r *fakeInstrumentRepository) GetBySymbol(symbol string, assetType models.AssetType) (*models.Instrument, error) {
+	for _, instrument := range r.instruments {
+		if instrument.AssetType == assetType && strings.EqualFold(instrument.Symbol, symbol) {
+			return instrument, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeInstrumentRepository) Search(query string, assetType *models.AssetType, limit int) ([]*models.Instrument, error) {
+	r.searchLimit = limit
+	return []*models.Instrument{}, nil
+}
+
+func (r *fakeInstrumentRepository) HasAssetType(assetType models.AssetType) (bool, error) {
+	for _, instrument := range r.instruments {
+		if instrument.AssetType == assetType {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func TestParseTWInstrumentCSV_CompanyList(t *testing.T) {
+	csvContent := "\ufeff出表日期,公司代號,公司名稱,公司簡稱,英文簡稱\n" +
+		"1141018,2330,台灣積體電路製造股份有限公司,台積電,TSMC\n" +
+		"1141018,2317,鴻海精密工業股份有限公司,鴻海,Hon Hai\n" +
+		"1141018,2330,重複資料,台積電,TSMC\n" +
+		"1141018,,缺少代號,無代號,\n"
+
+	instruments, skipped, err := parseTWInstrumentCSV(strings.NewReader(csvContent), models.ExchangeTWSE)
+
+	require.NoError(t, err)
+	require.Len(t, instruments, 2)
+	assert.Equal(t, 2, skipped)
+	assert.Equal(t, "2330", instruments[0].Symbol)
+	assert.Equal(t, "台積電", instruments[0].NameZhTW)
+	assert.Equal(t, "TSMC", instruments[0].NameEn)
+	assert.Equal(t, models.ExchangeTWSE, instruments[0].Exchange)
+	assert.Equal(t, models.CurrencyTWD, instruments[0].Currency)
+	assert.Equal(t, 1000.0, instruments[0].LotSize)
+}
+
+func TestParseTWInstrumentCSV_ISINList(t *testing.T) {
+	csvContent := "有價證券代號及名稱,國際證券辨識號碼(ISIN Code),上市日,市場別,產業別\n" +
+		"股票,,,,\n" +
+		"6488　環球晶,TW0006488000,2015/09/25,上櫃,半導體業\n"
+
+	instruments, skipped, err := parseTWInstrumentCSV(strings.NewReader(csvContent), models.ExchangeTPEx)
+
+	require.NoError(t, err)
+	require.Len(t, instruments, 1)
+	assert.Equal(t, 1, skipped)
+	assert.Equal(t, "6488", instruments[0].Symbol)
+	assert.Equal(t, "環球晶", instruments[0].NameZhTW)
+	assert.Equal(t, models.ExchangeTPEx, instruments[0].Exchange)
+	assert.Equal(t, models.InstrumentCategoryStock, instruments[0].Category)
+	assert.Equal(t, "6488.TWO", instruments[0].ProviderIDs[PriceProviderYahoo])
+}
+
+func TestParseTWInstrumentCSV_MarketAndCategory(t *testing.T) {
+	csvContent := "有價證券代號及名稱,國際證券辨識號碼(ISIN Code),上市日,市場別,產業別\n" +
+		"00679B　元大美債20年,TW00000679B0,2017/01/17,上櫃,\n" +
+		"00631L　元大台灣50正2,TW00000631L5,2014/10/31,上市,\n" +
+		"6869　雲豹能源,TW0006869001,2021/12/28,興櫃一般板,綠能環保\n"
+
+	instruments, _, err := parseTWInstrumentCSV(strings.NewReader(csvContent), models.ExchangeTWSE)
+
+	require.NoError(t, err)
+	require.Len(t, instruments, 3)
+
+	assert.Equal(t, models.ExchangeTPEx, instruments[0].Exchange)
+	assert.Equal(t, models.InstrumentCategoryBondETF, instruments[0].Category)
+
+	assert.Equal(t, models.ExchangeTWSE, instruments[1].Exchange)
+	assert.Equal(t, models.InstrumentCategoryLeveragedETF, instruments[1].Category)
+	assert.Equal(t, "00631L.TW", instruments[1].ProviderIDs[PriceProviderYahoo])
+
+	// 興櫃股票沒有 Yahoo Finance 報價代碼
+	assert.Equal(t, models.ExchangeEmerging, instruments[2].Exchange)
+	assert.Equal(t, models.InstrumentCategoryStock, instruments[2].Category)
+	assert.NotContains(t, instruments[2].ProviderIDs, PriceProviderYahoo)
+}
+
+func TestParseUSTickerList_NasdaqTraderFiles(t *testing.T) {
+	nasdaqListed := "Symbol|Security Name|Market Category|Test Issue|Financial Status|Round Lot Size|ETF|NextShares\n" +
+		"AAPL|Apple Inc. - Common Stock|Q|N|N|100|N|N\n" +
+		"ZAZZT|Tick Pilot Test Stock Class A Common Stock|G|Y|N|100|N|N\n" +
+		"File Creation Time: 1017202522:01||||||\n"
+
+	instruments, skipped, err := parseUSTickerList(strings.NewReader(nasdaqListed))
+
+	require.NoError(t, err)
+	require.Len(t, instruments, 1)
+	assert.Equal(t, 2, skipped)
+	assert.Equal(t, "AAPL", instruments[0].Symbol)
+	assert.Equal(t, "Apple Inc.", instruments[0].NameEn)
+	assert.Equal(t, models.ExchangeNASDAQ, instruments[0].Exchange)
+	assert.Equal(t, models.CurrencyUSD, instruments[0].Currency)
+
+	otherListed := "ACT Symbol|Security Name|Exchange|CQS Symbol|ETF|Round Lot Size|Test Issue|NASDAQ Symbol\n" +
+		"BRK.B|Berkshire Hathaway Inc. Class B Common Stock|N|BRK.B|N|100|N|BRK.B\n"
+
+	instruments, _, err = parseUSTickerList(strings.NewReader(otherListed))
+
+	require.NoError(t, err)
+	require.Len(t, instruments, 1)
+	assert.Equal(t, "NYSE", instruments[0].Exchange)
+	assert.Equal(t, "BRK-B", instruments[0].ProviderIDs[PriceProviderYahoo])
+}
+
+func TestParseCoinGeckoCoinList_PrefersKnownAndFirstCoin(t *testing.T) {
+	coinList := `[
+		{"id": "bitcoin", "symbol": "btc", "name": "Bitcoin"},
+		{"id": "bitcoin-bep2", "symbol": "btc", "name": "Bitcoin BEP2"},
+		{"id": "ethereum-wormhole", "symbol": "eth", "name": "Ethereum (Wormhole)"},
+		{"id": "ethereum", "symbol": "eth", "name": "Ethereum"},
+		{"id": "chainlink", "symbol": "link", "name": "Chainlink"},
+		{"id": "fake-link", "symbol": "link", "name": "Fake Link"}
+	]`
+
+	instruments, skipped, err := parseCoinGeckoCoinList(strings.NewReader(coinList))
+
+	require.NoError(t, err)
+	require.Len(t, instruments, 3)
+	assert.Equal(t, 3, skipped)
+
+	ids := map[string]string{}
+	for _, instrument := range instruments {
+		ids[instrument.Symbol] = instrument.ProviderIDs[PriceProviderCoinGecko]
+	}
+	// 內建對應的代碼不會被同代碼的其他幣種覆蓋，其餘以第一筆為準
+	assert.Equal(t, "bitcoin", ids["BTC"])
+	assert.Equal(t, "ethereum", ids["ETH"])
+	assert.Equal(t, "chainlink", ids["LINK"])
+}
+
+func TestInstrumentService_Import(t *testing.T) {
+	repo := &fakeInstrumentRepository{}
+	svc := NewInstrumentService(repo)
+
+	result, err := svc.Import(models.InstrumentSourceCoinGecko, strings.NewReader(`[{"id": "solana", "symbol": "sol", "name": "Solana"}]`))
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Parsed)
+	assert.Equal(t, 1, result.Imported)
+	require.Len(t, repo.instruments, 1)
+	assert.Equal(t, "SOL", repo.instruments[0].Symbol)
+
+	_, err = svc.Import(models.InstrumentSource("unknown"), strings.NewReader(""))
+	assert.Error(t, err)
+}
+
+func TestInstrumentService_SearchValidation(t *testing.T) {
+	repo := &fakeInstrumentRepository{}
+	svc := NewInstrumentService(repo)
+
+	_, err := svc.Search("  ", nil, 0)
+	assert.Error(t, err)
+
+	invalid := models.AssetType("bond")
+	_, err = svc.Search("2330", &invalid, 0)
+	assert.Error(t, err)
+
+	_, err = svc.Search("2330", nil, 0)
+	require.NoError(t, err)
+	assert.Equal(t, defaultInstrumentSearchLimit, repo.searchLimit)
+
+	_, err = svc.Search("2330", nil, 500)
+	require.NoError(t, err)
+	assert.Equal(t, maxInstrumentSearchLimit, repo.searchLimit)
+}
+
+func TestCreateTransaction_ValidatesAgainstInstruments(t *testing.T) {
+	instrumentRepo := &fakeInstrumentRepository{instruments: []*models.Instrument{
+		{Symbol: "AAPL", NameEn: "Apple Inc.", AssetType: models.AssetTypeUSStock, Currency: models.CurrencyUSD, LotSize: 1},
+	}}
+	mockRepo := new(MockTransactionRepository)
+	svc := NewTransactionService(mockRepo, new(MockRealizedProfitRepository), new(MockFIFOCalculator), new(MockExchangeRateService), nil, nil, instrumentRepo, nil)
+
+	newInput := func(assetType models.AssetType, symbol string) *models.CreateTransactionInput {
+		return &models.CreateTransactionInput{
+			Date:            time.Date(2025, 10, 22, 0, 0, 0, 0, time.UTC),
+			AssetType:       assetType,
+			Symbol:          symbol,
+			TransactionType: models.TransactionTypeBuy,
+			Quantity:        10,
+			Price:           600,
+			Amount:          6000,
+			Currency:        models.CurrencyTWD,
+		}
+	}
+
+	t.Run("unknown symbol is rejected once the asset type is seeded", func(t *testing.T) {
+		_, err := svc.CreateTransaction(newInput(models.AssetTypeUSStock, "AAPLL"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unknown us-stock symbol: AAPLL")
+	})
+
+	t.Run("symbol case and name come from the instrument", func(t *testing.T) {
+		input := newInput(models.AssetTypeUSStock, "aapl")
+		mockRepo.On("Create", mock.MatchedBy(func(in *models.CreateTransactionInput) bool {
+			return in.Symbol == "AAPL" && in.Name == "Apple Inc."
+		})).Return(&models.Transaction{ID: uuid.New(), Symbol: "AAPL"}, nil).Once()
+
+		_, err := svc.CreateTransaction(input)
+		require.NoError(t, err)
+	})
+
+	t.Run("tw-stock buys get the default fee when it is omitted", func(t *testing.T) {
+		instrumentRepo.instruments = append(instrumentRepo.instruments, &models.Instrument{
+			Symbol: "0056", NameZhTW: "元大高股息", AssetType: models.AssetTypeTWStock, Category: models.InstrumentCategoryETF, Currency: models.CurrencyTWD, LotSize: 1000,
+		})
+		defer func() { instrumentRepo.instruments = instrumentRepo.instruments[:1] }()
+
+		input := newInput(models.AssetTypeTWStock, "0056")
+		input.Amount = 360000
+		mockRepo.On("Create", mock.MatchedBy(func(in *models.CreateTransactionInput) bool {
+			return in.Symbol == "0056" && in.Fee != nil && *in.Fee == 513 && in.Tax == nil
+		})).Return(&models.Transaction{ID: uuid.New(), Symbol: "0056"}, nil).Once()
+
+		_, err := svc.CreateTransaction(input)
+		require.NoError(t, err)
+	})
+
+	t.Run("asset types without instruments are not validated but still need a name", func(t *testing.T) {
+		_, err := svc.CreateTransaction(newInput(models.AssetTypeTWStock, "2330"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "name is required")
+
+		input := newInput(models.AssetTypeTWStock, "2330")
+		input.Name = "台積電"
+		mockRepo.On("Create", input).Return(&models.Transaction{ID: uuid.New(), Symbol: "2330"}, nil).Once()
+
+		_, err = svc.CreateTransaction(input)
+		require.NoError(t, err)
+	})
+
+	mockRepo.AssertExpectations(t)
+}
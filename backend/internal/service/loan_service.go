@@ -0,0 +1,362 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/chienchuanw/asset-manager/internal/models"
+	"github.com/chienchuanw/asset-manager/internal/repository"
+	"github.com/google/uuid"
+)
+
+// LoanService 貸款業務邏輯介面
+type LoanService interface {
+	CreateLoan(input *models.CreateLoanInput) (*models.Loan, error)
+	GetLoan(id uuid.UUID) (*models.Loan, error)
+	ListLoans(filters repository.LoanFilters) ([]*models.Loan, error)
+	UpdateLoan(id uuid.UUID, input *models.UpdateLoanInput) (*models.Loan, error)
+	DeleteLoan(id uuid.UUID) error
+	GetAmortizationSchedule(id uuid.UUID, asOf time.Time) (*models.AmortizationSchedule, error)
+	GetPayments(id uuid.UUID) ([]*models.LoanPayment, error)
+	AddRateChange(id uuid.UUID, input *models.CreateLoanRateChangeInput) (*models.LoanRateChange, error)
+	AddPrepayment(id uuid.UUID, input *models.CreateLoanPrepaymentInput) (*models.LoanPrepayment, error)
+	GetOutstandingBalances(asOf time.Time) ([]*models.LoanBalance, error)
+}
+
+// loanService 貸款業務邏輯實作
+type loanService struct {
+	repo         repository.LoanRepository
+	categoryRepo repository.CategoryRepository
+}
+
+// NewLoanService 建立新的貸款 service
+func NewLoanService(
+	repo repository.LoanRepository,
+	categoryRepo repository.CategoryRepository,
+) LoanService {
+	return &loanService{
+		repo:         repo,
+		categoryRepo: categoryRepo,
+	}
+}
+
+// CreateLoan 建立新的貸款
+func (s *loanService) CreateLoan(input *models.CreateLoanInput) (*models.Loan, error) {
+	// 驗證貸款名稱
+	if input.Name == "" {
+		return nil, fmt.Errorf("loan name is required")
+	}
+
+	if len(input.Name) > 255 {
+		return nil, fmt.Errorf("loan name must not exceed 255 characters")
+	}
+
+	// 驗證貸款類型
+	if !input.LoanType.Validate() {
+		return nil, fmt.Errorf("invalid loan type: %s", input.LoanType)
+	}
+
+	// 驗證本金
+	if input.Principal <= 0 {
+		return nil, fmt.Errorf("principal must be greater than zero")
+	}
+
+	// 驗證利率
+	if input.AnnualRate < 0 {
+		return nil, fmt.Errorf("annual rate cannot be negative")
+	}
+
+	// 驗證期數
+	if input.TermMonths <= 0 {
+		return nil, fmt.Errorf("term months must be greater than zero")
+	}
+
+	// 驗證還款日
+	if input.PaymentDay < 1 || input.PaymentDay > 31 {
+		return nil, fmt.Errorf("payment day must be between 1 and 31")
+	}
+
+	// 驗證本金分類
+	if err := s.validateExpenseCategory(input.CategoryID); err != nil {
+		return nil, err
+	}
+
+	// 驗證利息分類
+	if input.InterestCategoryID != nil {
+		if err := s.validateExpenseCategory(*input.InterestCategoryID); err != nil {
+			return nil, err
+		}
+	}
+
+	// 驗證付款方式
+	if !input.PaymentMethod.Validate() {
+		return nil, fmt.Errorf("invalid payment method: %s", input.PaymentMethod)
+	}
+
+	// 驗證帳戶 ID（當付款方式需要帳戶時）
+	if input.PaymentMethod.RequiresAccountID() && input.AccountID == nil {
+		return nil, fmt.Errorf("account ID is required for payment method: %s", input.PaymentMethod)
+	}
+
+	// 呼叫 repository 建立貸款
+	loan, err := s.repo.Create(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create loan: %w", err)
+	}
+
+	return loan, nil
+}
+
+// GetLoan 取得單筆貸款（含利率調整與提前還本記錄）
+func (s *loanService) GetLoan(id uuid.UUID) (*models.Loan, error) {
+	loan, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.loadDetails(loan); err != nil {
+		return nil, err
+	}
+
+	return loan, nil
+}
+
+// ListLoans 取得貸款列表
+func (s *loanService) ListLoans(filters repository.LoanFilters) ([]*models.Loan, error) {
+	// 驗證篩選條件
+	if filters.Status != nil && !filters.Status.Validate() {
+		return nil, fmt.Errorf("invalid loan status filter: %s", *filters.Status)
+	}
+
+	if filters.LoanType != nil && !filters.LoanType.Validate() {
+		return nil, fmt.Errorf("invalid loan type filter: %s", *filters.LoanType)
+	}
+
+	return s.repo.List(filters)
+}
+
+// UpdateLoan 更新貸款
+func (s *loanService) UpdateLoan(id uuid.UUID, input *models.UpdateLoanInput) (*models.Loan, error) {
+	// 驗證貸款是否存在
+	existing, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("loan not found: %w", err)
+	}
+
+	// 驗證貸款名稱
+	if input.Name != nil {
+		if *input.Name == "" {
+			return nil, fmt.Errorf("loan name cannot be empty")
+		}
+		if len(*input.Name) > 255 {
+			return nil, fmt.Errorf("loan name must not exceed 255 characters")
+		}
+	}
+
+	// 驗證分類
+	if input.CategoryID != nil {
+		if err := s.validateExpenseCategory(*input.CategoryID); err != nil {
+			return nil, err
+		}
+	}
+
+	if input.InterestCategoryID != nil {
+		if err := s.validateExpenseCategory(*input.InterestCategoryID); err != nil {
+			return nil, err
+		}
+	}
+
+	// 驗證付款方式
+	if input.PaymentMethod != nil {
+		if !input.PaymentMethod.Validate() {
+			return nil, fmt.Errorf("invalid payment method: %s", *input.PaymentMethod)
+		}
+
+		// 驗證帳戶 ID（當付款方式需要帳戶時）
+		if input.PaymentMethod.RequiresAccountID() && input.AccountID == nil {
+			return nil, fmt.Errorf("account ID is required for payment method: %s", *input.PaymentMethod)
+		}
+	}
+
+	// 驗證已付期數
+	if input.PaidCount != nil && (*input.PaidCount < 0 || *input.PaidCount > existing.TermMonths) {
+		return nil, fmt.Errorf("paid count must be between 0 and %d", existing.TermMonths)
+	}
+
+	// 驗證狀態
+	if input.Status != nil && !input.Status.Validate() {
+		return nil, fmt.Errorf("invalid loan status: %s", *input.Status)
+	}
+
+	// 呼叫 repository 更新貸款
+	loan, err := s.repo.Update(id, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update loan: %w", err)
+	}
+
+	return loan, nil
+}
+
+// DeleteLoan 刪除貸款
+func (s *loanService) DeleteLoan(id uuid.UUID) error {
+	// 驗證貸款是否存在
+	_, err := s.repo.GetByID(id)
+	if err != nil {
+		return fmt.Errorf("loan not found: %w", err)
+	}
+
+	// 呼叫 repository 刪除貸款
+	if err := s.repo.Delete(id); err != nil {
+		return fmt.Errorf("failed to delete loan: %w", err)
+	}
+
+	return nil
+}
+
+// GetAmortizationSchedule 取得貸款攤還表（剩餘本金以 asOf 為基準日）
+func (s *loanService) GetAmortizationSchedule(id uuid.UUID, asOf time.Time) (*models.AmortizationSchedule, error) {
+	loan, err := s.GetLoan(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return loan.GenerateAmortizationSchedule(asOf), nil
+}
+
+// GetPayments 取得貸款已扣款的還款記錄
+func (s *loanService) GetPayments(id uuid.UUID) ([]*models.LoanPayment, error) {
+	// 驗證貸款是否存在
+	_, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("loan not found: %w", err)
+	}
+
+	return s.repo.GetPayments(id)
+}
+
+// AddRateChange 新增利率調整
+func (s *loanService) AddRateChange(id uuid.UUID, input *models.CreateLoanRateChangeInput) (*models.LoanRateChange, error) {
+	// 驗證貸款是否存在
+	loan, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("loan not found: %w", err)
+	}
+
+	// 驗證利率
+	if input.AnnualRate < 0 {
+		return nil, fmt.Errorf("annual rate cannot be negative")
+	}
+
+	// 驗證生效日期
+	if input.EffectiveDate.IsZero() {
+		return nil, fmt.Errorf("effective date is required")
+	}
+
+	if input.EffectiveDate.Before(loan.StartDate) {
+		return nil, fmt.Errorf("effective date cannot be before loan start date")
+	}
+
+	change, err := s.repo.AddRateChange(id, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add rate change: %w", err)
+	}
+
+	return change, nil
+}
+
+// AddPrepayment 新增提前還本
+func (s *loanService) AddPrepayment(id uuid.UUID, input *models.CreateLoanPrepaymentInput) (*models.LoanPrepayment, error) {
+	// 驗證貸款是否存在並載入明細
+	loan, err := s.GetLoan(id)
+	if err != nil {
+		return nil, fmt.Errorf("loan not found: %w", err)
+	}
+
+	// 只有還款中的貸款可以提前還本
+	if !loan.IsActive() {
+		return nil, fmt.Errorf("loan is not active")
+	}
+
+	// 驗證金額
+	if input.Amount <= 0 {
+		return nil, fmt.Errorf("prepayment amount must be greater than zero")
+	}
+
+	// 驗證日期
+	if input.Date.IsZero() {
+		return nil, fmt.Errorf("prepayment date is required")
+	}
+
+	if input.Date.Before(loan.StartDate) {
+		return nil, fmt.Errorf("prepayment date cannot be before loan start date")
+	}
+
+	// 提前還本金額不可超過還本當日的剩餘本金
+	remaining := loan.RemainingBalance(loan.GenerateAmortizationSchedule(input.Date), input.Date)
+	if input.Amount > remaining {
+		return nil, fmt.Errorf("prepayment amount %.2f exceeds remaining balance %.2f", input.Amount, remaining)
+	}
+
+	prepayment, err := s.repo.AddPrepayment(id, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add prepayment: %w", err)
+	}
+
+	return prepayment, nil
+}
+
+// GetOutstandingBalances 取得所有還款中貸款於 asOf 當日的剩餘本金
+func (s *loanService) GetOutstandingBalances(asOf time.Time) ([]*models.LoanBalance, error) {
+	status := models.LoanStatusActive
+	loans, err := s.repo.List(repository.LoanFilters{Status: &status})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list active loans: %w", err)
+	}
+
+	balances := make([]*models.LoanBalance, 0, len(loans))
+	for _, loan := range loans {
+		if err := s.loadDetails(loan); err != nil {
+			return nil, err
+		}
+
+		balances = append(balances, &models.LoanBalance{
+			LoanID:           loan.ID,
+			Name:             loan.Name,
+			LoanType:         loan.LoanType,
+			RemainingBalance: loan.RemainingBalance(loan.GenerateAmortizationSchedule(asOf), asOf),
+		})
+	}
+
+	return balances, nil
+}
+
+// loadDetails 載入貸款的利率調整與提前還本記錄
+func (s *loanService) loadDetails(loan *models.Loan) error {
+	rateChanges, err := s.repo.GetRateChanges(loan.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get rate changes: %w", err)
+	}
+
+	prepayments, err := s.repo.GetPrepayments(loan.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get prepayments: %w", err)
+	}
+
+	loan.RateChanges = rateChanges
+	loan.Prepayments = prepayments
+	return nil
+}
+
+// validateExpenseCategory 驗證分類是否存在且為支出類型
+func (s *loanService) validateExpenseCategory(categoryID uuid.UUID) error {
+	category, err := s.categoryRepo.GetByID(categoryID)
+	if err != nil {
+		return fmt.Errorf("invalid category: %w", err)
+	}
+
+	if category.Type != models.CashFlowTypeExpense {
+		return fmt.Errorf("loan category must be expense type")
+	}
+
+	return nil
+}
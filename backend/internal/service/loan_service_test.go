@@ -0,0 +1,216 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chienchuanw/asset-manager/internal/models"
+	"github.com/chienchuanw/asset-manager/internal/repository"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestServiceLoan 建立測試用貸款
+func newTestServiceLoan(id uuid.UUID) *models.Loan {
+	return &models.Loan{
+		ID:            id,
+		Name:          "房貸",
+		LoanType:      models.LoanTypeMortgage,
+		Principal:     120000,
+		Currency:      models.CurrencyTWD,
+		AnnualRate:    12,
+		TermMonths:    12,
+		PaymentDay:    10,
+		PaidCount:     1,
+		StartDate:     time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC),
+		CategoryID:    uuid.New(),
+		PaymentMethod: models.PaymentMethodCash,
+		Status:        models.LoanStatusActive,
+	}
+}
+
+func TestLoanService_CreateLoan(t *testing.T) {
+	mockRepo := new(MockLoanRepository)
+	mockCategoryRepo := new(MockCategoryRepository)
+	service := NewLoanService(mockRepo, mockCategoryRepo)
+
+	categoryID := uuid.New()
+	category := &models.CashFlowCategory{
+		ID:   categoryID,
+		Name: "房貸",
+		Type: models.CashFlowTypeExpense,
+	}
+
+	input := &models.CreateLoanInput{
+		Name:          "房貸",
+		LoanType:      models.LoanTypeMortgage,
+		Principal:     5000000,
+		AnnualRate:    2.185,
+		TermMonths:    360,
+		PaymentDay:    5,
+		StartDate:     time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC),
+		CategoryID:    categoryID,
+		PaymentMethod: models.PaymentMethodCash,
+	}
+
+	expected := &models.Loan{ID: uuid.New(), Name: input.Name}
+
+	// 設定 mock 期望
+	mockCategoryRepo.On("GetByID", categoryID).Return(category, nil)
+	mockRepo.On("Create", input).Return(expected, nil)
+
+	// 執行測試
+	result, err := service.CreateLoan(input)
+
+	// 驗證結果
+	assert.NoError(t, err)
+	assert.Equal(t, expected.ID, result.ID)
+	mockCategoryRepo.AssertExpectations(t)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestLoanService_CreateLoan_InvalidInput(t *testing.T) {
+	mockRepo := new(MockLoanRepository)
+	mockCategoryRepo := new(MockCategoryRepository)
+	service := NewLoanService(mockRepo, mockCategoryRepo)
+
+	valid := func() *models.CreateLoanInput {
+		return &models.CreateLoanInput{
+			Name:          "車貸",
+			LoanType:      models.LoanTypeCar,
+			Principal:     600000,
+			AnnualRate:    3,
+			TermMonths:    60,
+			PaymentDay:    10,
+			StartDate:     time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC),
+			CategoryID:    uuid.New(),
+			PaymentMethod: models.PaymentMethodCash,
+		}
+	}
+
+	tests := []struct {
+		name        string
+		modify      func(input *models.CreateLoanInput)
+		expectedErr string
+	}{
+		{
+			name:        "空白名稱",
+			modify:      func(input *models.CreateLoanInput) { input.Name = "" },
+			expectedErr: "loan name is required",
+		},
+		{
+			name:        "無效類型",
+			modify:      func(input *models.CreateLoanInput) { input.LoanType = "student" },
+			expectedErr: "invalid loan type",
+		},
+		{
+			name:        "本金為零",
+			modify:      func(input *models.CreateLoanInput) { input.Principal = 0 },
+			expectedErr: "principal must be greater than zero",
+		},
+		{
+			name:        "負利率",
+			modify:      func(input *models.CreateLoanInput) { input.AnnualRate = -1 },
+			expectedErr: "annual rate cannot be negative",
+		},
+		{
+			name:        "期數為零",
+			modify:      func(input *models.CreateLoanInput) { input.TermMonths = 0 },
+			expectedErr: "term months must be greater than zero",
+		},
+		{
+			name:        "還款日超出範圍",
+			modify:      func(input *models.CreateLoanInput) { input.PaymentDay = 32 },
+			expectedErr: "payment day must be between 1 and 31",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := valid()
+			tt.modify(input)
+
+			result, err := service.CreateLoan(input)
+
+			assert.Error(t, err)
+			assert.Nil(t, result)
+			assert.Contains(t, err.Error(), tt.expectedErr)
+		})
+	}
+}
+
+func TestLoanService_GetAmortizationSchedule(t *testing.T) {
+	mockRepo := new(MockLoanRepository)
+	mockCategoryRepo := new(MockCategoryRepository)
+	service := NewLoanService(mockRepo, mockCategoryRepo)
+
+	loanID := uuid.New()
+	loan := newTestServiceLoan(loanID)
+
+	// 設定 mock 期望
+	mockRepo.On("GetByID", loanID).Return(loan, nil)
+	mockRepo.On("GetRateChanges", loanID).Return([]*models.LoanRateChange{}, nil)
+	mockRepo.On("GetPrepayments", loanID).Return([]*models.LoanPrepayment{}, nil)
+
+	// 執行測試
+	schedule, err := service.GetAmortizationSchedule(loanID, time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC))
+
+	// 驗證結果
+	assert.NoError(t, err)
+	assert.Len(t, schedule.Entries, 12)
+	assert.True(t, schedule.Entries[0].IsPaid)
+	assert.False(t, schedule.Entries[1].IsPaid)
+	assert.Equal(t, 110538.15, schedule.RemainingBalance)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestLoanService_AddPrepayment_ExceedsBalance(t *testing.T) {
+	mockRepo := new(MockLoanRepository)
+	mockCategoryRepo := new(MockCategoryRepository)
+	service := NewLoanService(mockRepo, mockCategoryRepo)
+
+	loanID := uuid.New()
+	loan := newTestServiceLoan(loanID)
+
+	// 設定 mock 期望
+	mockRepo.On("GetByID", loanID).Return(loan, nil)
+	mockRepo.On("GetRateChanges", loanID).Return([]*models.LoanRateChange{}, nil)
+	mockRepo.On("GetPrepayments", loanID).Return([]*models.LoanPrepayment{}, nil)
+
+	// 執行測試
+	result, err := service.AddPrepayment(loanID, &models.CreateLoanPrepaymentInput{
+		Date:   time.Date(2025, 2, 20, 0, 0, 0, 0, time.UTC),
+		Amount: 200000,
+	})
+
+	// 驗證結果
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "exceeds remaining balance")
+	mockRepo.AssertNotCalled(t, "AddPrepayment")
+}
+
+func TestLoanService_GetOutstandingBalances(t *testing.T) {
+	mockRepo := new(MockLoanRepository)
+	mockCategoryRepo := new(MockCategoryRepository)
+	service := NewLoanService(mockRepo, mockCategoryRepo)
+
+	loanID := uuid.New()
+	loan := newTestServiceLoan(loanID)
+	status := models.LoanStatusActive
+
+	// 設定 mock 期望
+	mockRepo.On("List", repository.LoanFilters{Status: &status}).Return([]*models.Loan{loan}, nil)
+	mockRepo.On("GetRateChanges", loanID).Return([]*models.LoanRateChange{}, nil)
+	mockRepo.On("GetPrepayments", loanID).Return([]*models.LoanPrepayment{}, nil)
+
+	// 執行測試
+	balances, err := service.GetOutstandingBalances(time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC))
+
+	// 驗證結果
+	assert.NoError(t, err)
+	assert.Len(t, balances, 1)
+	assert.Equal(t, loanID, balances[0].LoanID)
+	assert.Equal(t, 110538.15, balances[0].RemainingBalance)
+	mockRepo.AssertExpectations(t)
+}
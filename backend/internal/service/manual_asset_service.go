@@ -0,0 +1,319 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/chienchuanw/asset-manager/internal/models"
+	"github.com/chienchuanw/asset-manager/internal/repository"
+	"github.com/google/uuid"
+)
+
+// ManualAssetService 手動估值資產業務邏輯介面
+type ManualAssetService interface {
+	CreateManualAsset(input *models.CreateManualAssetInput) (*models.ManualAsset, error)
+	GetManualAsset(id uuid.UUID) (*models.ManualAsset, error)
+	ListManualAssets(filters repository.ManualAssetFilters) ([]*models.ManualAsset, error)
+	UpdateManualAsset(id uuid.UUID, input *models.UpdateManualAssetInput) (*models.ManualAsset, error)
+	DeleteManualAsset(id uuid.UUID) error
+	AddValuation(id uuid.UUID, input *models.CreateManualAssetValuationInput) (*models.ManualAssetValuation, error)
+	GetValuations(id uuid.UUID) ([]*models.ManualAssetValuation, error)
+	DeleteValuation(id, valuationID uuid.UUID) error
+	// GetActiveAssetValues 取得所有持有中資產在指定日期的估值（含 TWD 換算）
+	GetActiveAssetValues(date time.Time) ([]*models.ManualAsset, error)
+}
+
+// manualAssetService 手動估值資產業務邏輯實作
+type manualAssetService struct {
+	repo                repository.ManualAssetRepository
+	exchangeRateService ExchangeRateService
+}
+
+// NewManualAssetService 建立新的手動估值資產 service
+func NewManualAssetService(
+	repo repository.ManualAssetRepository,
+	exchangeRateService ExchangeRateService,
+) ManualAssetService {
+	return &manualAssetService{
+		repo:                repo,
+		exchangeRateService: exchangeRateService,
+	}
+}
+
+// CreateManualAsset 建立新的手動估值資產
+func (s *manualAssetService) CreateManualAsset(input *models.CreateManualAssetInput) (*models.ManualAsset, error) {
+	// 驗證名稱
+	if input.Name == "" {
+		return nil, fmt.Errorf("manual asset name is required")
+	}
+
+	if len(input.Name) > 255 {
+		return nil, fmt.Errorf("manual asset name must not exceed 255 characters")
+	}
+
+	// 驗證分類
+	if !input.Category.Validate() {
+		return nil, fmt.Errorf("invalid manual asset category: %s", input.Category)
+	}
+
+	// 驗證幣別
+	if !input.Currency.Validate() {
+		return nil, fmt.Errorf("invalid currency: %s", input.Currency)
+	}
+
+	// 驗證取得成本
+	if input.AcquisitionCost < 0 {
+		return nil, fmt.Errorf("acquisition cost cannot be negative")
+	}
+
+	// 驗證估值調整設定
+	if err := validateValuationSchedule(input.ScheduleType, input.AnnualRate, input.UsefulLifeMonths, input.SalvageValue); err != nil {
+		return nil, err
+	}
+
+	asset, err := s.repo.Create(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create manual asset: %w", err)
+	}
+
+	if err := s.fillValues(asset, time.Now()); err != nil {
+		return nil, err
+	}
+
+	return asset, nil
+}
+
+// GetManualAsset 取得單筆手動估值資產（含估值記錄與目前估值）
+func (s *manualAssetService) GetManualAsset(id uuid.UUID) (*models.ManualAsset, error) {
+	asset, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.fillValues(asset, time.Now()); err != nil {
+		return nil, err
+	}
+
+	return asset, nil
+}
+
+// ListManualAssets 取得手動估值資產列表（含估值記錄與目前估值）
+func (s *manualAssetService) ListManualAssets(filters repository.ManualAssetFilters) ([]*models.ManualAsset, error) {
+	// 驗證篩選條件
+	if filters.Status != nil && !filters.Status.Validate() {
+		return nil, fmt.Errorf("invalid manual asset status filter: %s", *filters.Status)
+	}
+
+	if filters.Category != nil && !filters.Category.Validate() {
+		return nil, fmt.Errorf("invalid manual asset category filter: %s", *filters.Category)
+	}
+
+	assets, err := s.repo.List(filters)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for _, asset := range assets {
+		if err := s.fillValues(asset, now); err != nil {
+			return nil, err
+		}
+	}
+
+	return assets, nil
+}
+
+// UpdateManualAsset 更新手動估值資產
+func (s *manualAssetService) UpdateManualAsset(id uuid.UUID, input *models.UpdateManualAssetInput) (*models.ManualAsset, error) {
+	// 驗證資產是否存在
+	existing, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("manual asset not found: %w", err)
+	}
+
+	// 驗證名稱
+	if input.Name != nil {
+		if *input.Name == "" {
+			return nil, fmt.Errorf("manual asset name cannot be empty")
+		}
+		if len(*input.Name) > 255 {
+			return nil, fmt.Errorf("manual asset name must not exceed 255 characters")
+		}
+	}
+
+	// 驗證分類
+	if input.Category != nil && !input.Category.Validate() {
+		return nil, fmt.Errorf("invalid manual asset category: %s", *input.Category)
+	}
+
+	// 驗證狀態
+	if input.Status != nil && !input.Status.Validate() {
+		return nil, fmt.Errorf("invalid manual asset status: %s", *input.Status)
+	}
+
+	// 以更新後的設定驗證估值調整方式
+	scheduleType := existing.ScheduleType
+	if input.ScheduleType != nil {
+		scheduleType = *input.ScheduleType
+	}
+	annualRate := existing.AnnualRate
+	if input.AnnualRate != nil {
+		annualRate = input.AnnualRate
+	}
+	usefulLifeMonths := existing.UsefulLifeMonths
+	if input.UsefulLifeMonths != nil {
+		usefulLifeMonths = input.UsefulLifeMonths
+	}
+	salvageValue := existing.SalvageValue
+	if input.SalvageValue != nil {
+		salvageValue = input.SalvageValue
+	}
+	if err := validateValuationSchedule(scheduleType, annualRate, usefulLifeMonths, salvageValue); err != nil {
+		return nil, err
+	}
+
+	asset, err := s.repo.Update(id, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update manual asset: %w", err)
+	}
+
+	if err := s.fillValues(asset, time.Now()); err != nil {
+		return nil, err
+	}
+
+	return asset, nil
+}
+
+// DeleteManualAsset 刪除手動估值資產
+func (s *manualAssetService) DeleteManualAsset(id uuid.UUID) error {
+	// 驗證資產是否存在
+	_, err := s.repo.GetByID(id)
+	if err != nil {
+		return fmt.Errorf("manual asset not found: %w", err)
+	}
+
+	if err := s.repo.Delete(id); err != nil {
+		return fmt.Errorf("failed to delete manual asset: %w", err)
+	}
+
+	return nil
+}
+
+// AddValuation 新增估值記錄
+func (s *manualAssetService) AddValuation(id uuid.UUID, input *models.CreateManualAssetValuationInput) (*models.ManualAssetValuation, error) {
+	// 驗證資產是否存在
+	asset, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("manual asset not found: %w", err)
+	}
+
+	// 驗證估值
+	if input.Value < 0 {
+		return nil, fmt.Errorf("valuation value cannot be negative")
+	}
+
+	// 驗證估值日期
+	if input.ValuationDate.IsZero() {
+		return nil, fmt.Errorf("valuation date is required")
+	}
+
+	if input.ValuationDate.Before(asset.AcquisitionDate) {
+		return nil, fmt.Errorf("valuation date cannot be before acquisition date")
+	}
+
+	valuation, err := s.repo.AddValuation(id, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add valuation: %w", err)
+	}
+
+	return valuation, nil
+}
+
+// GetValuations 取得資產的估值記錄
+func (s *manualAssetService) GetValuations(id uuid.UUID) ([]*models.ManualAssetValuation, error) {
+	// 驗證資產是否存在
+	_, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("manual asset not found: %w", err)
+	}
+
+	return s.repo.GetValuations(id)
+}
+
+// DeleteValuation 刪除估值記錄
+func (s *manualAssetService) DeleteValuation(id, valuationID uuid.UUID) error {
+	if err := s.repo.DeleteValuation(id, valuationID); err != nil {
+		return fmt.Errorf("failed to delete valuation: %w", err)
+	}
+
+	return nil
+}
+
+// GetActiveAssetValues 取得所有持有中資產在指定日期的估值（含 TWD 換算）
+func (s *manualAssetService) GetActiveAssetValues(date time.Time) ([]*models.ManualAsset, error) {
+	status := models.ManualAssetStatusActive
+	assets, err := s.repo.List(repository.ManualAssetFilters{Status: &status})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list manual assets: %w", err)
+	}
+
+	result := make([]*models.ManualAsset, 0, len(assets))
+	for _, asset := range assets {
+		// 尚未取得的資產不計入
+		if date.Before(asset.AcquisitionDate) {
+			continue
+		}
+
+		if err := s.fillValues(asset, date); err != nil {
+			return nil, err
+		}
+		result = append(result, asset)
+	}
+
+	return result, nil
+}
+
+// fillValues 載入估值記錄並計算指定日期的估值
+func (s *manualAssetService) fillValues(asset *models.ManualAsset, date time.Time) error {
+	valuations, err := s.repo.GetValuations(asset.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get valuations: %w", err)
+	}
+	asset.Valuations = valuations
+
+	asset.CurrentValue = asset.ValueOn(date)
+	valueTWD, err := s.exchangeRateService.ConvertToTWD(asset.CurrentValue, asset.Currency, date)
+	if err != nil {
+		return fmt.Errorf("failed to convert value of manual asset %s: %w", asset.ID, err)
+	}
+	asset.CurrentValueTWD = valueTWD
+
+	return nil
+}
+
+// validateValuationSchedule 驗證估值調整設定
+func validateValuationSchedule(scheduleType models.ValuationScheduleType, annualRate *float64, usefulLifeMonths *int, salvageValue *float64) error {
+	if !scheduleType.Validate() {
+		return fmt.Errorf("invalid schedule type: %s", scheduleType)
+	}
+
+	switch scheduleType {
+	case models.ValuationScheduleStraightLine:
+		if usefulLifeMonths == nil || *usefulLifeMonths <= 0 {
+			return fmt.Errorf("useful life months is required for straight line depreciation")
+		}
+	case models.ValuationScheduleCompound:
+		if annualRate == nil {
+			return fmt.Errorf("annual rate is required for compound schedule")
+		}
+		if *annualRate <= -100 {
+			return fmt.Errorf("annual rate must be greater than -100")
+		}
+	}
+
+	if salvageValue != nil && *salvageValue < 0 {
+		return fmt.Errorf("salvage value cannot be negative")
+	}
+
+	return nil
+}
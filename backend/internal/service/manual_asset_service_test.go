@@ -0,0 +1,151 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chienchuanw/asset-manager/internal/models"
+	"github.com/chienchuanw/asset-manager/internal/repository"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestManualAssetService_CreateManualAsset_InvalidInput(t *testing.T) {
+	mockRepo := new(MockManualAssetRepository)
+	service := NewManualAssetService(mockRepo, new(MockExchangeRateServiceForFIFO))
+
+	valid := func() *models.CreateManualAssetInput {
+		return &models.CreateManualAssetInput{
+			Name:            "自住房屋",
+			Category:        models.ManualAssetCategoryRealEstate,
+			Currency:        models.CurrencyTWD,
+			AcquisitionDate: time.Date(2020, 5, 1, 0, 0, 0, 0, time.UTC),
+			AcquisitionCost: 15000000,
+			ScheduleType:    models.ValuationScheduleNone,
+		}
+	}
+
+	tests := []struct {
+		name        string
+		modify      func(input *models.CreateManualAssetInput)
+		expectedErr string
+	}{
+		{
+			name:        "空白名稱",
+			modify:      func(input *models.CreateManualAssetInput) { input.Name = "" },
+			expectedErr: "manual asset name is required",
+		},
+		{
+			name:        "無效分類",
+			modify:      func(input *models.CreateManualAssetInput) { input.Category = "stock" },
+			expectedErr: "invalid manual asset category",
+		},
+		{
+			name:        "負取得成本",
+			modify:      func(input *models.CreateManualAssetInput) { input.AcquisitionCost = -1 },
+			expectedErr: "acquisition cost cannot be negative",
+		},
+		{
+			name: "直線折舊缺少耐用月數",
+			modify: func(input *models.CreateManualAssetInput) {
+				input.ScheduleType = models.ValuationScheduleStraightLine
+			},
+			expectedErr: "useful life months is required",
+		},
+		{
+			name: "複合增值缺少年利率",
+			modify: func(input *models.CreateManualAssetInput) {
+				input.ScheduleType = models.ValuationScheduleCompound
+			},
+			expectedErr: "annual rate is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := valid()
+			tt.modify(input)
+
+			result, err := service.CreateManualAsset(input)
+
+			assert.Error(t, err)
+			assert.Nil(t, result)
+			assert.Contains(t, err.Error(), tt.expectedErr)
+		})
+	}
+	mockRepo.AssertNotCalled(t, "Create")
+}
+
+func TestManualAssetService_AddValuation_BeforeAcquisition(t *testing.T) {
+	mockRepo := new(MockManualAssetRepository)
+	service := NewManualAssetService(mockRepo, new(MockExchangeRateServiceForFIFO))
+
+	assetID := uuid.New()
+	asset := &models.ManualAsset{
+		ID:              assetID,
+		AcquisitionDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	// 設定 mock 期望
+	mockRepo.On("GetByID", assetID).Return(asset, nil)
+
+	// 執行測試
+	result, err := service.AddValuation(assetID, &models.CreateManualAssetValuationInput{
+		ValuationDate: time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC),
+		Value:         100000,
+	})
+
+	// 驗證結果
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "before acquisition date")
+	mockRepo.AssertNotCalled(t, "AddValuation", mock.Anything, mock.Anything)
+}
+
+func TestManualAssetService_GetActiveAssetValues(t *testing.T) {
+	mockRepo := new(MockManualAssetRepository)
+	mockExchangeRate := new(MockExchangeRateServiceForFIFO)
+	service := NewManualAssetService(mockRepo, mockExchangeRate)
+
+	date := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	status := models.ManualAssetStatusActive
+
+	held := &models.ManualAsset{
+		ID:              uuid.New(),
+		Name:            "私募股權",
+		Currency:        models.CurrencyUSD,
+		AcquisitionDate: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		AcquisitionCost: 10000,
+		ScheduleType:    models.ValuationScheduleNone,
+		Status:          models.ManualAssetStatusActive,
+	}
+	future := &models.ManualAsset{
+		ID:              uuid.New(),
+		Name:            "預售屋",
+		Currency:        models.CurrencyTWD,
+		AcquisitionDate: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		AcquisitionCost: 8000000,
+		ScheduleType:    models.ValuationScheduleNone,
+		Status:          models.ManualAssetStatusActive,
+	}
+	valuations := []*models.ManualAssetValuation{
+		{AssetID: held.ID, ValuationDate: time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC), Value: 12000},
+	}
+
+	// 設定 mock 期望
+	mockRepo.On("List", repository.ManualAssetFilters{Status: &status}).Return([]*models.ManualAsset{held, future}, nil)
+	mockRepo.On("GetValuations", held.ID).Return(valuations, nil)
+	mockExchangeRate.On("ConvertToTWD", 12000.0, models.CurrencyUSD, date).Return(384000.0, nil)
+
+	// 執行測試
+	assets, err := service.GetActiveAssetValues(date)
+
+	// 驗證結果：尚未取得的資產不計入
+	assert.NoError(t, err)
+	assert.Len(t, assets, 1)
+	assert.Equal(t, 12000.0, assets[0].CurrentValue)
+	assert.Equal(t, 384000.0, assets[0].CurrentValueTWD)
+	mockRepo.AssertExpectations(t)
+	mockExchangeRate.AssertExpectations(t)
+}
@@ -0,0 +1,199 @@
+package service
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/chienchuanw/asset-manager/internal/models"
+)
+
+// builtinMarketHolidays 內建休市日資料，每年需依證交所與 NYSE 公告更新；
+// 可透過 MARKET_HOLIDAYS_FILE 改用外部檔案，或以 MARKET_HOLIDAYS_* 環境變數補充
+//
+//go:embed market_holidays.json
+var builtinMarketHolidays []byte
+
+// marketHolidayList 單一市場的休市日與提前收盤日（YYYY-MM-DD）
+type marketHolidayList struct {
+	Holidays    []string `json:"holidays"`
+	EarlyCloses []string `json:"early_closes"`
+}
+
+// loadMarketHolidays 讀取休市日資料，未指定檔案時使用內建資料
+func loadMarketHolidays(path string) (map[models.AssetType]marketHolidayList, error) {
+	data := builtinMarketHolidays
+	if path != "" {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read market holidays file: %w", err)
+		}
+		data = content
+	}
+
+	holidays := make(map[models.AssetType]marketHolidayList)
+	if err := json.Unmarshal(data, &holidays); err != nil {
+		return nil, fmt.Errorf("failed to parse market holidays: %w", err)
+	}
+
+	for assetType, list := range holidays {
+		for _, date := range append(list.Holidays, list.EarlyCloses...) {
+			if _, err := time.Parse("2006-01-02", date); err != nil {
+				return nil, fmt.Errorf("invalid %s market holiday date: %s", assetType, date)
+			}
+		}
+	}
+
+	return holidays, nil
+}
+
+// marketCalendar 市場交易時段與休市日
+type marketCalendar struct {
+	location    *time.Location
+	open        time.Duration // 開盤時間（距當地午夜）
+	close       time.Duration // 收盤時間（距當地午夜）
+	earlyClose  time.Duration // 提前收盤時間
+	holidays    map[string]bool
+	earlyCloses map[string]bool
+}
+
+// newMarketCalendars 建立台股與美股的交易行事曆（加密貨幣 24 小時交易，不需行事曆）
+// 休市日資料讀取失敗時改用內建資料；目前年度沒有休市日資料時記錄警告
+func newMarketCalendars(config PriceRefreshConfig) map[models.AssetType]*marketCalendar {
+	holidays, err := loadMarketHolidays(config.HolidaysFile)
+	if err != nil {
+		log.Printf("Warning: %v, using built-in market holidays", err)
+		if holidays, err = loadMarketHolidays(""); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+	}
+
+	twStock := holidays[models.AssetTypeTWStock]
+	usStock := holidays[models.AssetTypeUSStock]
+	calendars := map[models.AssetType]*marketCalendar{
+		models.AssetTypeTWStock: {
+			location:    loadMarketLocation("Asia/Taipei", 8*3600),
+			open:        9 * time.Hour,
+			close:       13*time.Hour + 30*time.Minute,
+			holidays:    dateSet(twStock.Holidays, config.ExtraHolidays[models.AssetTypeTWStock]),
+			earlyCloses: dateSet(twStock.EarlyCloses),
+		},
+		models.AssetTypeUSStock: {
+			location:    loadMarketLocation("America/New_York", -5*3600),
+			open:        9*time.Hour + 30*time.Minute,
+			close:       16 * time.Hour,
+			earlyClose:  13 * time.Hour,
+			holidays:    dateSet(usStock.Holidays, config.ExtraHolidays[models.AssetTypeUSStock]),
+			earlyCloses: dateSet(usStock.EarlyCloses),
+		},
+	}
+
+	missingHolidayYear(calendars, time.Now().Year())
+	return calendars
+}
+
+// missingHolidayYear 回傳指定年度沒有任何休市日資料的市場並記錄警告
+// （缺少資料時國定假日會被當成交易日，背景更新與價格新舊判斷都會受影響）
+func missingHolidayYear(calendars map[models.AssetType]*marketCalendar, year int) []models.AssetType {
+	prefix := fmt.Sprintf("%d-", year)
+	missing := []models.AssetType{}
+	for assetType, calendar := range calendars {
+		covered := false
+		for date := range calendar.holidays {
+			if len(date) > len(prefix) && date[:len(prefix)] == prefix {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			missing = append(missing, assetType)
+		}
+	}
+
+	sort.Slice(missing, func(i, j int) bool { return missing[i] < missing[j] })
+	for _, assetType := range missing {
+		log.Printf("Warning: no %s market holiday data for %d, holidays will be treated as trading days; update market_holidays.json or set MARKET_HOLIDAYS_FILE", assetType, year)
+	}
+	return missing
+}
+
+// loadMarketLocation 載入市場時區（失敗時使用固定時差）
+func loadMarketLocation(name string, offset int) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.FixedZone(name, offset)
+	}
+	return loc
+}
+
+// dateSet 將日期字串列表轉換為集合
+func dateSet(lists ...[]string) map[string]bool {
+	set := make(map[string]bool)
+	for _, list := range lists {
+		for _, date := range list {
+			set[date] = true
+		}
+	}
+	return set
+}
+
+// IsTradingDay 判斷當地日期是否為交易日
+func (c *marketCalendar) IsTradingDay(t time.Time) bool {
+	local := t.In(c.location)
+	if local.Weekday() == time.Saturday || local.Weekday() == time.Sunday {
+		return false
+	}
+	return !c.holidays[local.Format("2006-01-02")]
+}
+
+// IsOpen 判斷目前是否在交易時段內
+func (c *marketCalendar) IsOpen(t time.Time) bool {
+	if !c.IsTradingDay(t) {
+		return false
+	}
+
+	local := t.In(c.location)
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, c.location)
+	sinceMidnight := local.Sub(midnight)
+	return sinceMidnight >= c.open && sinceMidnight < c.closeOn(local)
+}
+
+// LastClose 取得指定時間（含）以前最近一次收盤的時間，找不到時回傳零值
+func (c *marketCalendar) LastClose(t time.Time) time.Time {
+	local := t.In(c.location)
+
+	// 連續休市最長約兩週（農曆春節），往回找 14 天即可
+	for i := 0; i <= 14; i++ {
+		day := local.AddDate(0, 0, -i)
+		if !c.IsTradingDay(day) {
+			continue
+		}
+
+		midnight := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, c.location)
+		closeAt := midnight.Add(c.closeOn(day))
+		if !closeAt.After(t) {
+			return closeAt
+		}
+	}
+
+	return time.Time{}
+}
+
+// openOn 取得 t 當日的開盤時間
+func (c *marketCalendar) openOn(t time.Time) time.Time {
+	local := t.In(c.location)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, c.location).Add(c.open)
+}
+
+// closeOn 取得當地日期的收盤時間（距當地午夜）
+func (c *marketCalendar) closeOn(local time.Time) time.Duration {
+	if c.earlyCloses[local.Format("2006-01-02")] {
+		return c.earlyClose
+	}
+	return c.close
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/chienchuanw/asset-manager/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarketCalendar_TWSE(t *testing.T) {
+	calendar := newMarketCalendars(PriceRefreshConfig{})[models.AssetTypeTWStock]
+	taipei := calendar.location
+
+	// 交易日 09:00–13:30
+	assert.True(t, calendar.IsOpen(time.Date(2026, 10, 19, 10, 0, 0, 0, taipei)))
+	assert.False(t, calendar.IsOpen(time.Date(2026, 10, 19, 8, 59, 0, 0, taipei)))
+	assert.False(t, calendar.IsOpen(time.Date(2026, 10, 19, 13, 30, 0, 0, taipei)))
+
+	// 週末與國慶日補假休市
+	assert.False(t, calendar.IsOpen(time.Date(2026, 10, 17, 10, 0, 0, 0, taipei)))
+	assert.False(t, calendar.IsOpen(time.Date(2026, 10, 9, 10, 0, 0, 0, taipei)))
+
+	// 週一開盤前，最近一次收盤為上週五（10/16）
+	lastClose := calendar.LastClose(time.Date(2026, 10, 19, 8, 0, 0, 0, taipei))
+	assert.Equal(t, time.Date(2026, 10, 16, 13, 30, 0, 0, taipei), lastClose.In(taipei))
+}
+
+func TestMarketCalendar_NYSE(t *testing.T) {
+	calendar := newMarketCalendars(PriceRefreshConfig{
+		ExtraHolidays: map[models.AssetType][]string{
+			models.AssetTypeUSStock: {"2026-10-20"},
+		},
+	})[models.AssetTypeUSStock]
+
+	// 夏令時間：09:30 EDT = 13:30 UTC；冬令時間：09:30 EST = 14:30 UTC
+	assert.True(t, calendar.IsOpen(time.Date(2026, 10, 19, 13, 30, 0, 0, time.UTC)))
+	assert.False(t, calendar.IsOpen(time.Date(2026, 12, 1, 14, 0, 0, 0, time.UTC)))
+	assert.True(t, calendar.IsOpen(time.Date(2026, 12, 1, 14, 30, 0, 0, time.UTC)))
+
+	// 額外設定的休市日
+	assert.False(t, calendar.IsOpen(time.Date(2026, 10, 20, 15, 0, 0, 0, time.UTC)))
+
+	// 感恩節隔天 13:00 提前收盤
+	newYork := calendar.location
+	assert.False(t, calendar.IsOpen(time.Date(2026, 11, 27, 13, 30, 0, 0, newYork)))
+	lastClose := calendar.LastClose(time.Date(2026, 11, 28, 12, 0, 0, 0, newYork))
+	assert.Equal(t, time.Date(2026, 11, 27, 13, 0, 0, 0, newYork), lastClose.In(newYork))
+}
+
+func TestMarketCalendar_MissingHolidayYear(t *testing.T) {
+	calendars := newMarketCalendars(PriceRefreshConfig{})
+
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	// 內建資料涵蓋 2026 年
+	assert.Empty(t, missingHolidayYear(calendars, 2026))
+	assert.Empty(t, logs.String())
+
+	// 沒有資料的年度需回報兩個市場並記錄警告
+	missing := missingHolidayYear(calendars, 2099)
+	assert.Equal(t, []models.AssetType{models.AssetTypeTWStock, models.AssetTypeUSStock}, missing)
+	assert.Contains(t, logs.String(), "Warning: no tw-stock market holiday data for 2099")
+	assert.Contains(t, logs.String(), "Warning: no us-stock market holiday data for 2099")
+}
+
+func TestMarketCalendar_HolidaysFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "holidays.json")
+	content := `{"tw-stock": {"holidays": ["2027-02-05"]}, "us-stock": {"holidays": ["2027-01-01"], "early_closes": ["2027-11-26"]}}`
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	calendars := newMarketCalendars(PriceRefreshConfig{HolidaysFile: path})
+	assert.Empty(t, missingHolidayYear(calendars, 2027))
+
+	taipei := calendars[models.AssetTypeTWStock].location
+	assert.False(t, calendars[models.AssetTypeTWStock].IsTradingDay(time.Date(2027, 2, 5, 10, 0, 0, 0, taipei)))
+
+	newYork := calendars[models.AssetTypeUSStock].location
+	assert.False(t, calendars[models.AssetTypeUSStock].IsOpen(time.Date(2027, 11, 26, 14, 0, 0, 0, newYork)))
+
+	// 檔案格式錯誤時改用內建資料
+	assert.NoError(t, os.WriteFile(path, []byte(`{"us-stock": {"holidays": ["2027-13-01"]}}`), 0o600))
+	calendars = newMarketCalendars(PriceRefreshConfig{HolidaysFile: path})
+	assert.Empty(t, missingHolidayYear(calendars, 2026))
+}
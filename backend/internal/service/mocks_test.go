@@ -1,6 +1,7 @@
 package service
 
 import (
+	"database/sql"
 	"time"
 
 	"github.com/chienchuanw/asset-manager/internal/models"
@@ -22,6 +23,14 @@ func (m *MockCashFlowRepository) Create(input *models.CreateCashFlowInput) (*mod
 	return args.Get(0).(*models.CashFlow), args.Error(1)
 }
 
+func (m *MockCashFlowRepository) CreateTx(tx *sql.Tx, input *models.CreateCashFlowInput) (*models.CashFlow, error) {
+	args := m.Called(tx, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CashFlow), args.Error(1)
+}
+
 func (m *MockCashFlowRepository) GetByID(id uuid.UUID) (*models.CashFlow, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
@@ -51,6 +60,11 @@ func (m *MockCashFlowRepository) Delete(id uuid.UUID) error {
 	return args.Error(0)
 }
 
+func (m *MockCashFlowRepository) DeleteTx(tx *sql.Tx, id uuid.UUID) error {
+	args := m.Called(tx, id)
+	return args.Error(0)
+}
+
 func (m *MockCashFlowRepository) GetSummary(startDate, endDate time.Time) (*repository.CashFlowSummary, error) {
 	args := m.Called(startDate, endDate)
 	if args.Get(0) == nil {
@@ -128,6 +142,11 @@ func (m *MockInstallmentRepository) Update(id uuid.UUID, input *models.UpdateIns
 	return args.Get(0).(*models.Installment), args.Error(1)
 }
 
+func (m *MockInstallmentRepository) UpdateProgressTx(tx *sql.Tx, id uuid.UUID, paidCount int, status models.InstallmentStatus) error {
+	args := m.Called(tx, id, paidCount, status)
+	return args.Error(0)
+}
+
 func (m *MockInstallmentRepository) Delete(id uuid.UUID) error {
 	args := m.Called(id)
 	return args.Error(0)
@@ -149,6 +168,46 @@ func (m *MockInstallmentRepository) GetCompletingSoon(remainingCount int) ([]*mo
 	return args.Get(0).([]*models.Installment), args.Error(1)
 }
 
+func (m *MockInstallmentRepository) CreatePrepaymentTx(tx *sql.Tx, prepayment *models.InstallmentPrepayment) (*models.InstallmentPrepayment, error) {
+	args := m.Called(tx, prepayment)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.InstallmentPrepayment), args.Error(1)
+}
+
+func (m *MockInstallmentRepository) GetPrepayments(installmentID uuid.UUID) ([]*models.InstallmentPrepayment, error) {
+	args := m.Called(installmentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.InstallmentPrepayment), args.Error(1)
+}
+
+func (m *MockInstallmentRepository) CreatePaymentTx(tx *sql.Tx, payment *models.InstallmentPayment) (*models.InstallmentPayment, error) {
+	args := m.Called(tx, payment)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.InstallmentPayment), args.Error(1)
+}
+
+func (m *MockInstallmentRepository) GetPayments(installmentID uuid.UUID) ([]*models.InstallmentPayment, error) {
+	args := m.Called(installmentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.InstallmentPayment), args.Error(1)
+}
+
+func (m *MockInstallmentRepository) DB() *sql.DB {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil
+	}
+	return args.Get(0).(*sql.DB)
+}
+
 // MockCategoryRepository 分類 repository 的 mock
 type MockCategoryRepository struct {
 	mock.Mock
@@ -205,3 +264,427 @@ func (m *MockCategoryRepository) GetMaxSortOrder(flowType models.CashFlowType) (
 	args := m.Called(flowType)
 	return args.Int(0), args.Error(1)
 }
+
+// MockLoanRepository 貸款 repository 的 mock
+type MockLoanRepository struct {
+	mock.Mock
+}
+
+func (m *MockLoanRepository) Create(input *models.CreateLoanInput) (*models.Loan, error) {
+	args := m.Called(input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Loan), args.Error(1)
+}
+
+func (m *MockLoanRepository) GetByID(id uuid.UUID) (*models.Loan, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Loan), args.Error(1)
+}
+
+func (m *MockLoanRepository) List(filters repository.LoanFilters) ([]*models.Loan, error) {
+	args := m.Called(filters)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Loan), args.Error(1)
+}
+
+func (m *MockLoanRepository) Update(id uuid.UUID, input *models.UpdateLoanInput) (*models.Loan, error) {
+	args := m.Called(id, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Loan), args.Error(1)
+}
+
+func (m *MockLoanRepository) Delete(id uuid.UUID) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockLoanRepository) GetDueBillings(date time.Time) ([]*models.Loan, error) {
+	args := m.Called(date)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Loan), args.Error(1)
+}
+
+func (m *MockLoanRepository) AddRateChange(loanID uuid.UUID, input *models.CreateLoanRateChangeInput) (*models.LoanRateChange, error) {
+	args := m.Called(loanID, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.LoanRateChange), args.Error(1)
+}
+
+func (m *MockLoanRepository) GetRateChanges(loanID uuid.UUID) ([]*models.LoanRateChange, error) {
+	args := m.Called(loanID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.LoanRateChange), args.Error(1)
+}
+
+func (m *MockLoanRepository) AddPrepayment(loanID uuid.UUID, input *models.CreateLoanPrepaymentInput) (*models.LoanPrepayment, error) {
+	args := m.Called(loanID, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.LoanPrepayment), args.Error(1)
+}
+
+func (m *MockLoanRepository) GetPrepayments(loanID uuid.UUID) ([]*models.LoanPrepayment, error) {
+	args := m.Called(loanID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.LoanPrepayment), args.Error(1)
+}
+
+func (m *MockLoanRepository) CreatePayment(payment *models.LoanPayment) (*models.LoanPayment, error) {
+	args := m.Called(payment)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.LoanPayment), args.Error(1)
+}
+
+func (m *MockLoanRepository) CreatePaymentTx(tx *sql.Tx, payment *models.LoanPayment) (*models.LoanPayment, error) {
+	args := m.Called(tx, payment)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.LoanPayment), args.Error(1)
+}
+
+func (m *MockLoanRepository) UpdateProgressTx(tx *sql.Tx, id uuid.UUID, paidCount int, status models.LoanStatus) error {
+	args := m.Called(tx, id, paidCount, status)
+	return args.Error(0)
+}
+
+func (m *MockLoanRepository) GetPayments(loanID uuid.UUID) ([]*models.LoanPayment, error) {
+	args := m.Called(loanID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.LoanPayment), args.Error(1)
+}
+
+// MockManualAssetRepository 手動估值資產 repository 的 mock
+type MockManualAssetRepository struct {
+	mock.Mock
+}
+
+func (m *MockManualAssetRepository) Create(input *models.CreateManualAssetInput) (*models.ManualAsset, error) {
+	args := m.Called(input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ManualAsset), args.Error(1)
+}
+
+func (m *MockManualAssetRepository) GetByID(id uuid.UUID) (*models.ManualAsset, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ManualAsset), args.Error(1)
+}
+
+func (m *MockManualAssetRepository) List(filters repository.ManualAssetFilters) ([]*models.ManualAsset, error) {
+	args := m.Called(filters)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.ManualAsset), args.Error(1)
+}
+
+func (m *MockManualAssetRepository) Update(id uuid.UUID, input *models.UpdateManualAssetInput) (*models.ManualAsset, error) {
+	args := m.Called(id, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ManualAsset), args.Error(1)
+}
+
+func (m *MockManualAssetRepository) Delete(id uuid.UUID) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockManualAssetRepository) AddValuation(assetID uuid.UUID, input *models.CreateManualAssetValuationInput) (*models.ManualAssetValuation, error) {
+	args := m.Called(assetID, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ManualAssetValuation), args.Error(1)
+}
+
+func (m *MockManualAssetRepository) GetValuations(assetID uuid.UUID) ([]*models.ManualAssetValuation, error) {
+	args := m.Called(assetID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.ManualAssetValuation), args.Error(1)
+}
+
+func (m *MockManualAssetRepository) DeleteValuation(assetID, valuationID uuid.UUID) error {
+	args := m.Called(assetID, valuationID)
+	return args.Error(0)
+}
+
+// MockRecurringTemplateRepository 定期收支範本 repository 的 mock
+type MockRecurringTemplateRepository struct {
+	mock.Mock
+}
+
+func (m *MockRecurringTemplateRepository) Create(input *models.CreateRecurringTemplateInput) (*models.RecurringTemplate, error) {
+	args := m.Called(input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.RecurringTemplate), args.Error(1)
+}
+
+func (m *MockRecurringTemplateRepository) GetByID(id uuid.UUID) (*models.RecurringTemplate, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.RecurringTemplate), args.Error(1)
+}
+
+func (m *MockRecurringTemplateRepository) List(filters repository.RecurringTemplateFilters) ([]*models.RecurringTemplate, error) {
+	args := m.Called(filters)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.RecurringTemplate), args.Error(1)
+}
+
+func (m *MockRecurringTemplateRepository) Update(id uuid.UUID, input *models.UpdateRecurringTemplateInput) (*models.RecurringTemplate, error) {
+	args := m.Called(id, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.RecurringTemplate), args.Error(1)
+}
+
+func (m *MockRecurringTemplateRepository) Delete(id uuid.UUID) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockRecurringTemplateRepository) GetActive(date time.Time) ([]*models.RecurringTemplate, error) {
+	args := m.Called(date)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.RecurringTemplate), args.Error(1)
+}
+
+func (m *MockRecurringTemplateRepository) AddAmountChange(templateID uuid.UUID, input *models.CreateRecurringAmountChangeInput) (*models.RecurringAmountChange, error) {
+	args := m.Called(templateID, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.RecurringAmountChange), args.Error(1)
+}
+
+func (m *MockRecurringTemplateRepository) GetAmountChanges(templateID uuid.UUID) ([]*models.RecurringAmountChange, error) {
+	args := m.Called(templateID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.RecurringAmountChange), args.Error(1)
+}
+
+func (m *MockRecurringTemplateRepository) DeleteAmountChange(templateID, changeID uuid.UUID) error {
+	args := m.Called(templateID, changeID)
+	return args.Error(0)
+}
+
+// MockBrokerageAccountRepository 券商帳戶 repository 的 mock
+type MockBrokerageAccountRepository struct {
+	mock.Mock
+}
+
+func (m *MockBrokerageAccountRepository) DB() *sql.DB {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil
+	}
+	return args.Get(0).(*sql.DB)
+}
+
+func (m *MockBrokerageAccountRepository) Create(input *models.CreateBrokerageAccountInput) (*models.BrokerageAccount, error) {
+	args := m.Called(input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BrokerageAccount), args.Error(1)
+}
+
+func (m *MockBrokerageAccountRepository) GetByID(id uuid.UUID) (*models.BrokerageAccount, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BrokerageAccount), args.Error(1)
+}
+
+func (m *MockBrokerageAccountRepository) GetAll() ([]*models.BrokerageAccount, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.BrokerageAccount), args.Error(1)
+}
+
+func (m *MockBrokerageAccountRepository) Update(id uuid.UUID, input *models.UpdateBrokerageAccountInput) (*models.BrokerageAccount, error) {
+	args := m.Called(id, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BrokerageAccount), args.Error(1)
+}
+
+func (m *MockBrokerageAccountRepository) Delete(id uuid.UUID) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockBrokerageAccountRepository) UpdateCashBalance(id uuid.UUID, amount float64) (*models.BrokerageAccount, error) {
+	args := m.Called(id, amount)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BrokerageAccount), args.Error(1)
+}
+
+func (m *MockBrokerageAccountRepository) UpdateCashBalanceTx(tx *sql.Tx, id uuid.UUID, amount float64) (*models.BrokerageAccount, error) {
+	args := m.Called(tx, id, amount)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BrokerageAccount), args.Error(1)
+}
+
+func (m *MockBrokerageAccountRepository) CreateMovementTx(tx *sql.Tx, movement *models.BrokerageCashMovement) (*models.BrokerageCashMovement, error) {
+	args := m.Called(tx, movement)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BrokerageCashMovement), args.Error(1)
+}
+
+func (m *MockBrokerageAccountRepository) GetMovements(accountID uuid.UUID) ([]*models.BrokerageCashMovement, error) {
+	args := m.Called(accountID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.BrokerageCashMovement), args.Error(1)
+}
+
+func (m *MockBrokerageAccountRepository) GetMovementsByTransactionID(transactionID uuid.UUID) ([]*models.BrokerageCashMovement, error) {
+	args := m.Called(transactionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.BrokerageCashMovement), args.Error(1)
+}
+
+func (m *MockBrokerageAccountRepository) DeleteMovementTx(tx *sql.Tx, id uuid.UUID) error {
+	args := m.Called(tx, id)
+	return args.Error(0)
+}
+
+// MockCustodyAccountRepository 保管帳戶 repository 的 mock
+type MockCustodyAccountRepository struct {
+	mock.Mock
+}
+
+func (m *MockCustodyAccountRepository) Create(input *models.CreateCustodyAccountInput) (*models.CustodyAccount, error) {
+	args := m.Called(input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CustodyAccount), args.Error(1)
+}
+
+func (m *MockCustodyAccountRepository) GetByID(id uuid.UUID) (*models.CustodyAccount, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CustodyAccount), args.Error(1)
+}
+
+func (m *MockCustodyAccountRepository) GetAll() ([]*models.CustodyAccount, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.CustodyAccount), args.Error(1)
+}
+
+func (m *MockCustodyAccountRepository) Update(id uuid.UUID, input *models.UpdateCustodyAccountInput) (*models.CustodyAccount, error) {
+	args := m.Called(id, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CustodyAccount), args.Error(1)
+}
+
+func (m *MockCustodyAccountRepository) Delete(id uuid.UUID) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+// MockOptionContractRepository 選擇權合約 repository 的 mock
+type MockOptionContractRepository struct {
+	mock.Mock
+}
+
+func (m *MockOptionContractRepository) Create(input *models.CreateOptionContractInput) (*models.OptionContract, error) {
+	args := m.Called(input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.OptionContract), args.Error(1)
+}
+
+func (m *MockOptionContractRepository) GetByID(id uuid.UUID) (*models.OptionContract, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.OptionContract), args.Error(1)
+}
+
+func (m *MockOptionContractRepository) GetBySymbol(symbol string) (*models.OptionContract, error) {
+	args := m.Called(symbol)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.OptionContract), args.Error(1)
+}
+
+func (m *MockOptionContractRepository) GetAll() ([]*models.OptionContract, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.OptionContract), args.Error(1)
+}
+
+func (m *MockOptionContractRepository) Delete(id uuid.UUID) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
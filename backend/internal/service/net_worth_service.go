@@ -0,0 +1,126 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/chienchuanw/asset-manager/internal/models"
+	"github.com/chienchuanw/asset-manager/internal/repository"
+)
+
+// NetWorthService 淨值計算服務介面
+type NetWorthService interface {
+	// GetNetWorth 計算目前的淨值（資產 - 負債）
+	GetNetWorth() (*models.NetWorth, error)
+}
+
+// netWorthService 淨值計算服務實作
+type netWorthService struct {
+	holdingService      HoldingService
+	bankAccountRepo     repository.BankAccountRepository
+	creditCardRepo      repository.CreditCardRepository
+	installmentRepo     repository.InstallmentRepository
+	loanService         LoanService
+	manualAssetService  ManualAssetService
+	exchangeRateService ExchangeRateService
+}
+
+// NewNetWorthService 建立新的淨值計算服務
+func NewNetWorthService(
+	holdingService HoldingService,
+	bankAccountRepo repository.BankAccountRepository,
+	creditCardRepo repository.CreditCardRepository,
+	installmentRepo repository.InstallmentRepository,
+	loanService LoanService,
+	manualAssetService ManualAssetService,
+	exchangeRateService ExchangeRateService,
+) NetWorthService {
+	return &netWorthService{
+		holdingService:      holdingService,
+		bankAccountRepo:     bankAccountRepo,
+		creditCardRepo:      creditCardRepo,
+		installmentRepo:     installmentRepo,
+		loanService:         loanService,
+		manualAssetService:  manualAssetService,
+		exchangeRateService: exchangeRateService,
+	}
+}
+
+// GetNetWorth 計算目前的淨值（資產 - 負債）
+func (s *netWorthService) GetNetWorth() (*models.NetWorth, error) {
+	now := time.Now()
+	result := &models.NetWorth{Date: now}
+
+	// 1. 投資持倉市值（已換算為 TWD）
+	holdingResult, err := s.holdingService.GetAllHoldings(models.HoldingFilters{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get holdings: %w", err)
+	}
+	for _, holding := range holdingResult.Holdings {
+		result.InvestmentValue += holding.MarketValue
+	}
+
+	// 2. 銀行帳戶餘額（外幣換算為 TWD）
+	accounts, err := s.bankAccountRepo.GetAll(nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bank accounts: %w", err)
+	}
+	for _, account := range accounts {
+		balance, err := s.exchangeRateService.ConvertToTWD(account.Balance, account.Currency, now)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert balance of bank account %s: %w", account.ID, err)
+		}
+		result.CashValue += balance
+	}
+
+	// 3. 手動估值資產（已換算為 TWD）
+	manualAssets, err := s.manualAssetService.GetActiveAssetValues(now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get manual asset values: %w", err)
+	}
+	for _, asset := range manualAssets {
+		result.ManualAssetValue += asset.CurrentValueTWD
+	}
+	result.ManualAssets = manualAssets
+
+	// 4. 信用卡已使用額度
+	cards, err := s.creditCardRepo.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get credit cards: %w", err)
+	}
+	for _, card := range cards {
+		result.CreditCardDebt += card.UsedCredit
+	}
+
+	// 5. 進行中分期的剩餘應付金額
+	installmentStatus := models.InstallmentStatusActive
+	installments, err := s.installmentRepo.List(repository.InstallmentFilters{Status: &installmentStatus})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get installments: %w", err)
+	}
+	for _, installment := range installments {
+		// 載入提前還款記錄，剩餘金額依重新攤提後的付款計畫計算
+		prepayments, err := s.installmentRepo.GetPrepayments(installment.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get installment prepayments: %w", err)
+		}
+		installment.Prepayments = prepayments
+		result.InstallmentDebt += installment.RemainingAmount()
+	}
+
+	// 6. 貸款剩餘本金
+	loans, err := s.loanService.GetOutstandingBalances(now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get loan balances: %w", err)
+	}
+	for _, loan := range loans {
+		result.LoanDebt += loan.RemainingBalance
+	}
+	result.Loans = loans
+
+	result.TotalAssets = result.InvestmentValue + result.CashValue + result.ManualAssetValue
+	result.TotalLiabilities = result.CreditCardDebt + result.InstallmentDebt + result.LoanDebt
+	result.NetWorth = result.TotalAssets - result.TotalLiabilities
+
+	return result, nil
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/chienchuanw/asset-manager/internal/models"
+	"github.com/chienchuanw/asset-manager/internal/repository"
+	"github.com/google/uuid"
+)
+
+// OptionContractService 選擇權合約業務邏輯介面
+type OptionContractService interface {
+	CreateOptionContract(input *models.CreateOptionContractInput) (*models.OptionContract, error)
+	GetOptionContract(id uuid.UUID) (*models.OptionContract, error)
+	ListOptionContracts() ([]*models.OptionContract, error)
+	DeleteOptionContract(id uuid.UUID) error
+}
+
+// optionContractService 選擇權合約業務邏輯實作
+type optionContractService struct {
+	repo repository.OptionContractRepository
+}
+
+// NewOptionContractService 建立新的選擇權合約 service
+func NewOptionContractService(repo repository.OptionContractRepository) OptionContractService {
+	return &optionContractService{repo: repo}
+}
+
+// CreateOptionContract 建立新的選擇權合約
+func (s *optionContractService) CreateOptionContract(input *models.CreateOptionContractInput) (*models.OptionContract, error) {
+	input.Symbol = strings.ToUpper(strings.TrimSpace(input.Symbol))
+	input.UnderlyingSymbol = strings.ToUpper(strings.TrimSpace(input.UnderlyingSymbol))
+
+	if input.Symbol == "" || input.UnderlyingSymbol == "" {
+		return nil, fmt.Errorf("contract symbol and underlying symbol are required")
+	}
+
+	if input.Symbol == input.UnderlyingSymbol {
+		return nil, fmt.Errorf("contract symbol must differ from underlying symbol")
+	}
+
+	if !input.OptionType.Validate() {
+		return nil, fmt.Errorf("invalid option type: %s", input.OptionType)
+	}
+
+	if input.StrikePrice <= 0 {
+		return nil, fmt.Errorf("strike price must be greater than zero")
+	}
+
+	if input.Multiplier != nil && *input.Multiplier <= 0 {
+		return nil, fmt.Errorf("multiplier must be greater than zero")
+	}
+
+	return s.repo.Create(input)
+}
+
+// GetOptionContract 取得單筆選擇權合約
+func (s *optionContractService) GetOptionContract(id uuid.UUID) (*models.OptionContract, error) {
+	return s.repo.GetByID(id)
+}
+
+// ListOptionContracts 取得所有選擇權合約
+func (s *optionContractService) ListOptionContracts() ([]*models.OptionContract, error) {
+	return s.repo.GetAll()
+}
+
+// DeleteOptionContract 刪除選擇權合約
+func (s *optionContractService) DeleteOptionContract(id uuid.UUID) error {
+	return s.repo.Delete(id)
+}
@@ -0,0 +1,679 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chienchuanw/asset-manager/internal/external"
+	"github.com/chienchuanw/asset-manager/internal/models"
+	"github.com/chienchuanw/asset-manager/internal/repository"
+)
+
+// 價格來源名稱（用於設定來源順序與狀態查詢）
+const (
+	PriceProviderFinMind      = external.ProviderFinMind
+	PriceProviderYahoo        = external.ProviderYahoo
+	PriceProviderAlphaVantage = external.ProviderAlphaVantage
+	PriceProviderCoinGecko    = external.ProviderCoinGecko
+	PriceProviderLastKnown    = "last_known"
+)
+
+// unhealthyConsecutiveFailures 連續失敗達此次數即標記為不健康（僅供狀態顯示，仍會依序嘗試）
+const unhealthyConsecutiveFailures = 3
+
+// PriceProvider 價格來源介面
+type PriceProvider interface {
+	// Name 來源名稱
+	Name() string
+
+	// Supports 是否支援指定的資產類型
+	Supports(assetType models.AssetType) bool
+
+	// FetchPrices 取得多個同類型標的的價格（可只回傳部分標的）
+	FetchPrices(symbols []string, assetType models.AssetType) (map[string]*models.Price, error)
+}
+
+// priceRecorder 需要記錄其他來源成功取得之價格的來源（例如最後已知價格）
+type priceRecorder interface {
+	Remember(prices []*models.Price)
+}
+
+// quotaReporter 受速率限制的來源，回報今日已使用的請求次數與每日上限（0 表示不限制）
+type quotaReporter interface {
+	QuotaUsage() (used int, quota int)
+}
+
+// providerErrors 各來源的錯誤（保留原始錯誤，讓呼叫端可用 errors.Is 判斷速率限制等類型）
+type providerErrors []error
+
+func (e providerErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (e providerErrors) Unwrap() []error { return e }
+
+// PriceProviderRegistry 價格來源註冊表，管理各資產類型的來源順序與健康統計
+type PriceProviderRegistry interface {
+	// Register 註冊價格來源（同名來源會被取代）
+	Register(provider PriceProvider)
+
+	// SetChain 設定資產類型的來源順序
+	SetChain(assetType models.AssetType, names []string) error
+
+	// Chain 取得資產類型的來源順序
+	Chain(assetType models.AssetType) []PriceProvider
+
+	// FetchPrices 依來源順序取得價格，前一個來源失敗或缺漏的標的改由下一個來源補上
+	FetchPrices(symbols []string, assetType models.AssetType) (map[string]*models.Price, error)
+
+	// Status 取得來源順序與各來源的健康統計
+	Status() *models.PriceProvidersOverview
+}
+
+// providerStats 單一來源的呼叫統計
+type providerStats struct {
+	totalRequests       int64
+	successCount        int64
+	failureCount        int64
+	consecutiveFailures int
+	totalLatency        time.Duration
+	lastSuccessAt       *time.Time
+	lastFailureAt       *time.Time
+	lastError           string
+}
+
+// priceProviderRegistry 價格來源註冊表實作
+type priceProviderRegistry struct {
+	mu        sync.RWMutex
+	providers map[string]PriceProvider
+	order     []string // 註冊順序（狀態輸出使用）
+	chains    map[models.AssetType][]string
+	stats     map[string]*providerStats
+}
+
+// NewPriceProviderRegistry 建立空的價格來源註冊表
+func NewPriceProviderRegistry() PriceProviderRegistry {
+	return &priceProviderRegistry{
+		providers: make(map[string]PriceProvider),
+		chains:    make(map[models.AssetType][]string),
+		stats:     make(map[string]*providerStats),
+	}
+}
+
+// NewDefaultPriceProviderRegistry 建立內建來源的註冊表
+// 預設順序：台股 FinMind → Yahoo（上市 .TW、上櫃 .TWO）→ 最後已知價格；美股 Yahoo → Alpha Vantage → 最後已知價格；加密貨幣 CoinGecko → 最後已知價格
+// 可透過環境變數 PRICE_PROVIDERS_TW_STOCK、PRICE_PROVIDERS_US_STOCK、PRICE_PROVIDERS_CRYPTO（以逗號分隔）覆寫
+// lastKnownRepo 用於永久保存最後已知價格，可為 nil（僅保存在記憶體中）
+// instrumentRepo 提供加密貨幣的 CoinGecko ID 與台股的上市／上櫃別，可為 nil（僅使用內建對應，台股視為上市）
+func NewDefaultPriceProviderRegistry(finmindAPIKey, coingeckoAPIKey, alphaVantageAPIKey string, lastKnownRepo repository.LastKnownPriceRepository, instrumentRepo repository.InstrumentRepository) PriceProviderRegistry {
+	coingeckoClient := external.NewCoinGeckoClient(coingeckoAPIKey)
+	if instrumentRepo != nil {
+		coingeckoClient.SetCoinIDResolver(func(symbol string) (string, bool) {
+			instrument, err := instrumentRepo.GetBySymbol(symbol, models.AssetTypeCrypto)
+			if err != nil || instrument == nil {
+				return "", false
+			}
+			coinID := instrument.ProviderIDs[PriceProviderCoinGecko]
+			return coinID, coinID != ""
+		})
+	}
+
+	registry := NewPriceProviderRegistry()
+	registry.Register(&finmindPriceProvider{client: external.NewFinMindClient(finmindAPIKey)})
+	registry.Register(&yahooPriceProvider{client: external.NewYahooFinanceClient(), instrumentRepo: instrumentRepo})
+	registry.Register(&alphaVantagePriceProvider{client: external.NewAlphaVantageClient(alphaVantageAPIKey)})
+	registry.Register(&coingeckoPriceProvider{client: coingeckoClient})
+	registry.Register(NewLastKnownPriceProvider(lastKnownRepo))
+
+	chains := map[models.AssetType][]string{
+		models.AssetTypeTWStock: {PriceProviderFinMind, PriceProviderYahoo, PriceProviderLastKnown},
+		models.AssetTypeUSStock: {PriceProviderYahoo, PriceProviderAlphaVantage, PriceProviderLastKnown},
+		models.AssetTypeCrypto:  {PriceProviderCoinGecko, PriceProviderLastKnown},
+	}
+	for assetType, names := range PriceProviderChainsFromEnv() {
+		chains[assetType] = names
+	}
+
+	for assetType, names := range chains {
+		if err := registry.SetChain(assetType, names); err != nil {
+			log.Printf("Warning: invalid price provider chain for %s: %v", assetType, err)
+		}
+	}
+
+	return registry
+}
+
+// PriceProviderChainsFromEnv 讀取環境變數中的來源順序設定
+func PriceProviderChainsFromEnv() map[models.AssetType][]string {
+	envKeys := map[models.AssetType]string{
+		models.AssetTypeTWStock: "PRICE_PROVIDERS_TW_STOCK",
+		models.AssetTypeUSStock: "PRICE_PROVIDERS_US_STOCK",
+		models.AssetTypeCrypto:  "PRICE_PROVIDERS_CRYPTO",
+	}
+
+	chains := make(map[models.AssetType][]string)
+	for assetType, key := range envKeys {
+		value := os.Getenv(key)
+		if value == "" {
+			continue
+		}
+
+		names := []string{}
+		for _, name := range strings.Split(value, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, name)
+			}
+		}
+		chains[assetType] = names
+	}
+	return chains
+}
+
+// Register 註冊價格來源（同名來源會被取代）
+func (r *priceProviderRegistry) Register(provider PriceProvider) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	name := provider.Name()
+	if _, exists := r.providers[name]; !exists {
+		r.order = append(r.order, name)
+		r.stats[name] = &providerStats{}
+	}
+	r.providers[name] = provider
+}
+
+// SetChain 設定資產類型的來源順序
+func (r *priceProviderRegistry) SetChain(assetType models.AssetType, names []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(names) == 0 {
+		return fmt.Errorf("price provider chain for %s cannot be empty", assetType)
+	}
+
+	for _, name := range names {
+		provider, exists := r.providers[name]
+		if !exists {
+			return fmt.Errorf("unknown price provider: %s", name)
+		}
+		if !provider.Supports(assetType) {
+			return fmt.Errorf("price provider %s does not support %s", name, assetType)
+		}
+	}
+
+	r.chains[assetType] = append([]string(nil), names...)
+	return nil
+}
+
+// Chain 取得資產類型的來源順序
+func (r *priceProviderRegistry) Chain(assetType models.AssetType) []PriceProvider {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	chain := make([]PriceProvider, 0, len(r.chains[assetType]))
+	for _, name := range r.chains[assetType] {
+		chain = append(chain, r.providers[name])
+	}
+	return chain
+}
+
+// FetchPrices 依來源順序取得價格，前一個來源失敗或缺漏的標的改由下一個來源補上
+func (r *priceProviderRegistry) FetchPrices(symbols []string, assetType models.AssetType) (map[string]*models.Price, error) {
+	chain := r.Chain(assetType)
+	if len(chain) == 0 {
+		return nil, fmt.Errorf("unsupported asset type: %s", assetType)
+	}
+
+	prices := make(map[string]*models.Price)
+	remaining := symbols
+	var errs providerErrors
+	fresh := []*models.Price{}
+
+	for _, provider := range chain {
+		if len(remaining) == 0 {
+			break
+		}
+
+		start := time.Now()
+		fetched, err := provider.FetchPrices(remaining, assetType)
+		if err == nil && len(fetched) == 0 {
+			err = fmt.Errorf("no prices returned for %s", strings.Join(remaining, ","))
+		}
+		// 查無標的代表來源正常回應，不計入健康統計的失敗
+		if errors.Is(err, external.ErrSymbolNotFound) {
+			r.recordResult(provider.Name(), time.Since(start), nil)
+		} else {
+			r.recordResult(provider.Name(), time.Since(start), err)
+		}
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", provider.Name(), err))
+			continue
+		}
+
+		missing := []string{}
+		for _, symbol := range remaining {
+			price, exists := fetched[symbol]
+			if !exists || price.Price <= 0 {
+				missing = append(missing, symbol)
+				continue
+			}
+			prices[symbol] = price
+			if !price.IsStale {
+				fresh = append(fresh, price)
+			}
+		}
+		remaining = missing
+	}
+
+	r.remember(fresh)
+
+	if len(prices) == 0 {
+		return nil, fmt.Errorf("all price providers failed for %s: %w", assetType, errs)
+	}
+
+	return prices, nil
+}
+
+// Status 取得來源順序與各來源的健康統計
+func (r *priceProviderRegistry) Status() *models.PriceProvidersOverview {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	overview := &models.PriceProvidersOverview{
+		Chains:    []*models.PriceProviderChain{},
+		Providers: []*models.PriceProviderStatus{},
+	}
+
+	for assetType, names := range r.chains {
+		overview.Chains = append(overview.Chains, &models.PriceProviderChain{
+			AssetType: assetType,
+			Providers: append([]string(nil), names...),
+		})
+	}
+	sort.Slice(overview.Chains, func(i, j int) bool {
+		return overview.Chains[i].AssetType < overview.Chains[j].AssetType
+	})
+
+	for _, name := range r.order {
+		provider := r.providers[name]
+		stats := r.stats[name]
+
+		assetTypes := []models.AssetType{}
+		for _, assetType := range []models.AssetType{models.AssetTypeTWStock, models.AssetTypeUSStock, models.AssetTypeCrypto} {
+			if provider.Supports(assetType) {
+				assetTypes = append(assetTypes, assetType)
+			}
+		}
+
+		status := &models.PriceProviderStatus{
+			Name:                name,
+			AssetTypes:          assetTypes,
+			Healthy:             stats.consecutiveFailures < unhealthyConsecutiveFailures,
+			TotalRequests:       stats.totalRequests,
+			SuccessCount:        stats.successCount,
+			FailureCount:        stats.failureCount,
+			ConsecutiveFailures: stats.consecutiveFailures,
+			LastSuccessAt:       stats.lastSuccessAt,
+			LastFailureAt:       stats.lastFailureAt,
+			LastError:           stats.lastError,
+		}
+		if reporter, ok := provider.(quotaReporter); ok {
+			status.DailyRequests, status.DailyQuota = reporter.QuotaUsage()
+		}
+		if stats.totalRequests > 0 {
+			status.AvgLatencyMs = float64(stats.totalLatency.Milliseconds()) / float64(stats.totalRequests)
+		}
+		overview.Providers = append(overview.Providers, status)
+	}
+
+	return overview
+}
+
+// recordResult 記錄單次呼叫結果
+func (r *priceProviderRegistry) recordResult(name string, latency time.Duration, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stats, exists := r.stats[name]
+	if !exists {
+		return
+	}
+
+	now := time.Now()
+	stats.totalRequests++
+	stats.totalLatency += latency
+	if err != nil {
+		stats.failureCount++
+		stats.consecutiveFailures++
+		stats.lastFailureAt = &now
+		stats.lastError = err.Error()
+		return
+	}
+
+	stats.successCount++
+	stats.consecutiveFailures = 0
+	stats.lastSuccessAt = &now
+}
+
+// remember 將單次查詢成功取得的價格交給需要記錄的來源（例如最後已知價格）
+// 記錄可能寫入資料庫，因此只在鎖內複製來源清單，於鎖外寫入
+func (r *priceProviderRegistry) remember(prices []*models.Price) {
+	if len(prices) == 0 {
+		return
+	}
+
+	r.mu.RLock()
+	recorders := []priceRecorder{}
+	for _, provider := range r.providers {
+		if recorder, ok := provider.(priceRecorder); ok {
+			recorders = append(recorders, recorder)
+		}
+	}
+	r.mu.RUnlock()
+
+	for _, recorder := range recorders {
+		recorder.Remember(prices)
+	}
+}
+
+// ==================== 內建價格來源 ====================
+
+// newProviderPrice 建立由來源取得的價格資料
+func newProviderPrice(symbol string, assetType models.AssetType, price float64, source string) *models.Price {
+	currency := "USD"
+	if assetType == models.AssetTypeTWStock {
+		currency = "TWD"
+	}
+
+	return &models.Price{
+		Symbol:    symbol,
+		AssetType: assetType,
+		Price:     price,
+		Currency:  currency,
+		Source:    source,
+		UpdatedAt: time.Now(),
+	}
+}
+
+// toProviderPrices 將外部 API 回傳的價格轉換為價格資料
+func toProviderPrices(raw map[string]float64, assetType models.AssetType, source string) map[string]*models.Price {
+	prices := make(map[string]*models.Price, len(raw))
+	for symbol, price := range raw {
+		prices[symbol] = newProviderPrice(symbol, assetType, price, source)
+	}
+	return prices
+}
+
+// finmindPriceProvider FinMind 台股價格來源
+type finmindPriceProvider struct {
+	client *external.FinMindClient
+}
+
+func (p *finmindPriceProvider) Name() string { return PriceProviderFinMind }
+
+func (p *finmindPriceProvider) QuotaUsage() (int, int) {
+	return external.ProviderRateLimiter(p.Name()).Usage()
+}
+
+func (p *finmindPriceProvider) Supports(assetType models.AssetType) bool {
+	return assetType == models.AssetTypeTWStock
+}
+
+func (p *finmindPriceProvider) FetchPrices(symbols []string, assetType models.AssetType) (map[string]*models.Price, error) {
+	raw, err := p.client.GetMultipleStockPrices(symbols)
+	if err != nil {
+		return nil, err
+	}
+	return toProviderPrices(raw, assetType, p.Name()), nil
+}
+
+// yahooPriceProvider Yahoo Finance 美股與台股價格來源（支援批次查詢、無需 API Key）
+type yahooPriceProvider struct {
+	client         *external.YahooFinanceClient
+	instrumentRepo repository.InstrumentRepository // 可為 nil（台股視為上市，使用 .TW 代碼）
+}
+
+func (p *yahooPriceProvider) Name() string { return PriceProviderYahoo }
+
+func (p *yahooPriceProvider) QuotaUsage() (int, int) {
+	return external.ProviderRateLimiter(p.Name()).Usage()
+}
+
+func (p *yahooPriceProvider) Supports(assetType models.AssetType) bool {
+	return assetType == models.AssetTypeUSStock || assetType == models.AssetTypeTWStock
+}
+
+func (p *yahooPriceProvider) FetchPrices(symbols []string, assetType models.AssetType) (map[string]*models.Price, error) {
+	// Yahoo Finance 代碼 → 原始代碼
+	quoteSymbols := make(map[string]string, len(symbols))
+	requested := make([]string, 0, len(symbols))
+	for _, symbol := range symbols {
+		quoteSymbol := p.quoteSymbol(symbol, assetType)
+		if quoteSymbol == "" {
+			continue
+		}
+		quoteSymbols[strings.ToUpper(quoteSymbol)] = symbol
+		requested = append(requested, quoteSymbol)
+	}
+	if len(requested) == 0 {
+		return nil, fmt.Errorf("no Yahoo Finance symbols for %s: %w", strings.Join(symbols, ","), external.ErrSymbolNotFound)
+	}
+
+	raw, err := p.client.GetMultipleStockPrices(requested)
+	if err != nil {
+		return nil, err
+	}
+
+	prices := make(map[string]*models.Price, len(raw))
+	for quoteSymbol, price := range raw {
+		if symbol, exists := quoteSymbols[strings.ToUpper(quoteSymbol)]; exists {
+			prices[symbol] = newProviderPrice(symbol, assetType, price, p.Name())
+		}
+	}
+	return prices, nil
+}
+
+// quoteSymbol 取得標的在 Yahoo Finance 的代碼，優先使用商品主檔中的來源代碼
+// 台股依上市／上櫃別加上 .TW 或 .TWO，興櫃股票沒有報價時回傳空字串
+func (p *yahooPriceProvider) quoteSymbol(symbol string, assetType models.AssetType) string {
+	exchange := models.ExchangeTWSE
+	if p.instrumentRepo != nil {
+		instrument, err := p.instrumentRepo.GetBySymbol(symbol, assetType)
+		if err == nil && instrument != nil {
+			if quoteSymbol := instrument.ProviderIDs[PriceProviderYahoo]; quoteSymbol != "" {
+				return quoteSymbol
+			}
+			exchange = instrument.Exchange
+		}
+	}
+
+	if assetType == models.AssetTypeTWStock {
+		return twYahooSymbol(symbol, exchange)
+	}
+	return symbol
+}
+
+// alphaVantagePriceProvider Alpha Vantage 美股價格來源（免費版每分鐘 5 次、每日 25 次）
+type alphaVantagePriceProvider struct {
+	client *external.AlphaVantageClient
+}
+
+func (p *alphaVantagePriceProvider) Name() string { return PriceProviderAlphaVantage }
+
+func (p *alphaVantagePriceProvider) QuotaUsage() (int, int) {
+	return external.ProviderRateLimiter(p.Name()).Usage()
+}
+
+func (p *alphaVantagePriceProvider) Supports(assetType models.AssetType) bool {
+	return assetType == models.AssetTypeUSStock
+}
+
+func (p *alphaVantagePriceProvider) FetchPrices(symbols []string, assetType models.AssetType) (map[string]*models.Price, error) {
+	raw, err := p.client.GetMultipleStockPrices(symbols)
+	if err != nil {
+		return nil, err
+	}
+	return toProviderPrices(raw, assetType, p.Name()), nil
+}
+
+// coingeckoPriceProvider CoinGecko 加密貨幣價格來源（以 USD 計價）
+type coingeckoPriceProvider struct {
+	client *external.CoinGeckoClient
+}
+
+func (p *coingeckoPriceProvider) Name() string { return PriceProviderCoinGecko }
+
+func (p *coingeckoPriceProvider) QuotaUsage() (int, int) {
+	return external.ProviderRateLimiter(p.Name()).Usage()
+}
+
+func (p *coingeckoPriceProvider) Supports(assetType models.AssetType) bool {
+	return assetType == models.AssetTypeCrypto
+}
+
+func (p *coingeckoPriceProvider) FetchPrices(symbols []string, assetType models.AssetType) (map[string]*models.Price, error) {
+	raw, err := p.client.GetMultipleCryptoPrices(symbols, "usd")
+	if err != nil {
+		return nil, err
+	}
+	return toProviderPrices(raw, assetType, p.Name()), nil
+}
+
+// ==================== 最後已知價格 ====================
+
+// lastKnownPriceProvider 最後已知價格來源
+// 記錄其他來源最近一次成功取得的價格（記憶體 + 資料庫），所有即時來源皆失敗時回傳並標記為過期
+type lastKnownPriceProvider struct {
+	mu     sync.RWMutex
+	prices map[string]*models.Price
+	repo   repository.LastKnownPriceRepository // 可為 nil（僅保存在記憶體中）
+}
+
+// NewLastKnownPriceProvider 建立最後已知價格來源
+func NewLastKnownPriceProvider(repo repository.LastKnownPriceRepository) PriceProvider {
+	return &lastKnownPriceProvider{
+		prices: make(map[string]*models.Price),
+		repo:   repo,
+	}
+}
+
+func (p *lastKnownPriceProvider) Name() string { return PriceProviderLastKnown }
+
+func (p *lastKnownPriceProvider) Supports(assetType models.AssetType) bool {
+	return assetType == models.AssetTypeTWStock || assetType == models.AssetTypeUSStock || assetType == models.AssetTypeCrypto
+}
+
+// Remember 記錄成功取得的價格（單次批次寫入資料庫）
+func (p *lastKnownPriceProvider) Remember(prices []*models.Price) {
+	stored := make([]*models.Price, len(prices))
+	for i, price := range prices {
+		copied := *price
+		stored[i] = &copied
+	}
+
+	p.mu.Lock()
+	for _, price := range stored {
+		p.prices[lastKnownPriceKey(price.Symbol, price.AssetType)] = price
+	}
+	p.mu.Unlock()
+
+	if p.repo != nil {
+		if err := p.repo.UpsertBatch(stored); err != nil {
+			log.Printf("Warning: failed to save %d last known prices: %v", len(stored), err)
+		}
+	}
+}
+
+func (p *lastKnownPriceProvider) FetchPrices(symbols []string, assetType models.AssetType) (map[string]*models.Price, error) {
+	prices := make(map[string]*models.Price)
+	var lookupErr error
+
+	for _, symbol := range symbols {
+		stored, err := p.lookup(symbol, assetType)
+		if err != nil {
+			lookupErr = err
+			continue
+		}
+		if stored == nil {
+			continue
+		}
+
+		prices[symbol] = newLastKnownStalePrice(stored, "All live price providers failed")
+	}
+
+	if len(prices) == 0 && lookupErr != nil {
+		return nil, lookupErr
+	}
+
+	return prices, nil
+}
+
+// lookup 依序從記憶體與資料庫取得最後已知價格
+func (p *lastKnownPriceProvider) lookup(symbol string, assetType models.AssetType) (*models.Price, error) {
+	key := lastKnownPriceKey(symbol, assetType)
+
+	p.mu.RLock()
+	stored, exists := p.prices[key]
+	p.mu.RUnlock()
+	if exists || p.repo == nil {
+		return stored, nil
+	}
+
+	stored, err := p.repo.Get(symbol, assetType)
+	if err != nil {
+		return nil, err
+	}
+	if stored != nil {
+		p.mu.Lock()
+		p.prices[key] = stored
+		p.mu.Unlock()
+	}
+
+	return stored, nil
+}
+
+// lastKnownPriceKey 最後已知價格的索引鍵
+func lastKnownPriceKey(symbol string, assetType models.AssetType) string {
+	return fmt.Sprintf("%s:%s", assetType, symbol)
+}
+
+// newLastKnownStalePrice 以最後已知價格建立過期價格資料（UpdatedAt 保留實際取得時間）
+func newLastKnownStalePrice(stored *models.Price, reason string) *models.Price {
+	price := *stored
+	price.Source = PriceProviderLastKnown
+	price.IsStale = true
+	price.StaleReason = fmt.Sprintf("%s, using last known price from %s (%s, %s old)",
+		reason, stored.UpdatedAt.Format("2006-01-02 15:04"), stored.Source, formatPriceAge(time.Since(stored.UpdatedAt)))
+	return &price
+}
+
+// formatPriceAge 將價格年齡格式化為易讀字串（例如：2d 3h、45m）
+func formatPriceAge(age time.Duration) string {
+	if age < time.Minute {
+		return "<1m"
+	}
+
+	days := int(age.Hours()) / 24
+	hours := int(age.Hours()) % 24
+	minutes := int(age.Minutes()) % 60
+
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd %dh", days, hours)
+	case hours > 0:
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	default:
+		return fmt.Sprintf("%dm", minutes)
+	}
+}
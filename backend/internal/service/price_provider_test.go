@@ -0,0 +1,260 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/chienchuanw/asset-manager/internal/external"
+	"github.com/chienchuanw/asset-manager/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePriceProvider 測試用價格來源
+type fakePriceProvider struct {
+	name   string
+	prices map[string]float64
+	err    error
+	calls  [][]string
+}
+
+func (p *fakePriceProvider) Name() string { return p.name }
+
+func (p *fakePriceProvider) Supports(assetType models.AssetType) bool {
+	return assetType == models.AssetTypeUSStock
+}
+
+func (p *fakePriceProvider) FetchPrices(symbols []string, assetType models.AssetType) (map[string]*models.Price, error) {
+	p.calls = append(p.calls, symbols)
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	prices := make(map[string]*models.Price)
+	for _, symbol := range symbols {
+		if price, exists := p.prices[symbol]; exists {
+			prices[symbol] = newProviderPrice(symbol, assetType, price, p.name)
+		}
+	}
+	return prices, nil
+}
+
+func newTestPriceProviderRegistry(t *testing.T, primary, secondary *fakePriceProvider) PriceProviderRegistry {
+	registry := NewPriceProviderRegistry()
+	registry.Register(primary)
+	registry.Register(secondary)
+	registry.Register(NewLastKnownPriceProvider(nil))
+	require.NoError(t, registry.SetChain(models.AssetTypeUSStock, []string{primary.name, secondary.name, PriceProviderLastKnown}))
+	return registry
+}
+
+func TestPriceProviderRegistry_FailsOverToNextProvider(t *testing.T) {
+	primary := &fakePriceProvider{name: "primary", err: errors.New("rate limit exceeded")}
+	secondary := &fakePriceProvider{name: "secondary", prices: map[string]float64{"AAPL": 190, "MSFT": 410}}
+	registry := newTestPriceProviderRegistry(t, primary, secondary)
+
+	prices, err := registry.FetchPrices([]string{"AAPL", "MSFT"}, models.AssetTypeUSStock)
+
+	require.NoError(t, err)
+	assert.Equal(t, 190.0, prices["AAPL"].Price)
+	assert.Equal(t, "secondary", prices["AAPL"].Source)
+	assert.Equal(t, "USD", prices["MSFT"].Currency)
+
+	status := registry.Status()
+	require.Len(t, status.Providers, 3)
+	assert.Equal(t, "primary", status.Providers[0].Name)
+	assert.Equal(t, int64(1), status.Providers[0].FailureCount)
+	assert.Equal(t, 1, status.Providers[0].ConsecutiveFailures)
+	assert.Equal(t, "rate limit exceeded", status.Providers[0].LastError)
+	assert.True(t, status.Providers[0].Healthy)
+	assert.Equal(t, int64(1), status.Providers[1].SuccessCount)
+	assert.NotNil(t, status.Providers[1].LastSuccessAt)
+	// 所有標的都已取得，不需呼叫最後已知價格
+	assert.Equal(t, int64(0), status.Providers[2].TotalRequests)
+}
+
+func TestPriceProviderRegistry_OnlyMissingSymbolsGoToNextProvider(t *testing.T) {
+	primary := &fakePriceProvider{name: "primary", prices: map[string]float64{"AAPL": 190}}
+	secondary := &fakePriceProvider{name: "secondary", prices: map[string]float64{"AAPL": 1, "MSFT": 410}}
+	registry := newTestPriceProviderRegistry(t, primary, secondary)
+
+	prices, err := registry.FetchPrices([]string{"AAPL", "MSFT"}, models.AssetTypeUSStock)
+
+	require.NoError(t, err)
+	assert.Equal(t, 190.0, prices["AAPL"].Price)
+	assert.Equal(t, 410.0, prices["MSFT"].Price)
+	require.Len(t, secondary.calls, 1)
+	assert.Equal(t, []string{"MSFT"}, secondary.calls[0])
+}
+
+func TestPriceProviderRegistry_FallsBackToLastKnownPrice(t *testing.T) {
+	primary := &fakePriceProvider{name: "primary", prices: map[string]float64{"AAPL": 190}}
+	secondary := &fakePriceProvider{name: "secondary", err: errors.New("timeout")}
+	registry := newTestPriceProviderRegistry(t, primary, secondary)
+
+	_, err := registry.FetchPrices([]string{"AAPL"}, models.AssetTypeUSStock)
+	require.NoError(t, err)
+
+	// 所有即時來源皆失敗，改用最後已知價格並標記為過期
+	primary.err = errors.New("service unavailable")
+	prices, err := registry.FetchPrices([]string{"AAPL"}, models.AssetTypeUSStock)
+
+	require.NoError(t, err)
+	assert.Equal(t, 190.0, prices["AAPL"].Price)
+	assert.Equal(t, PriceProviderLastKnown, prices["AAPL"].Source)
+	assert.True(t, prices["AAPL"].IsStale)
+	assert.Contains(t, prices["AAPL"].StaleReason, "primary")
+}
+
+// fakeLastKnownPriceRepository 測試用最後已知價格 repository
+type fakeLastKnownPriceRepository struct {
+	prices   map[string]*models.Price
+	batches  int
+	onUpsert func()
+}
+
+func (r *fakeLastKnownPriceRepository) UpsertBatch(prices []*models.Price) error {
+	if r.onUpsert != nil {
+		r.onUpsert()
+	}
+	r.batches++
+	for _, price := range prices {
+		stored := *price
+		r.prices[lastKnownPriceKey(price.Symbol, price.AssetType)] = &stored
+	}
+	return nil
+}
+
+func (r *fakeLastKnownPriceRepository) Get(symbol string, assetType models.AssetType) (*models.Price, error) {
+	return r.prices[lastKnownPriceKey(symbol, assetType)], nil
+}
+
+func TestLastKnownPriceProvider_PersistsAcrossRestarts(t *testing.T) {
+	repo := &fakeLastKnownPriceRepository{prices: make(map[string]*models.Price)}
+	fetchedAt := time.Now().Add(-3 * time.Hour)
+
+	// 成功取得的價格寫入資料庫
+	NewLastKnownPriceProvider(repo).(priceRecorder).Remember([]*models.Price{{
+		Symbol:    "AAPL",
+		AssetType: models.AssetTypeUSStock,
+		Price:     190,
+		Currency:  "USD",
+		Source:    PriceProviderYahoo,
+		UpdatedAt: fetchedAt,
+	}})
+
+	// 重新啟動後（記憶體為空）仍可從資料庫取得
+	prices, err := NewLastKnownPriceProvider(repo).FetchPrices([]string{"AAPL", "MSFT"}, models.AssetTypeUSStock)
+
+	require.NoError(t, err)
+	require.Len(t, prices, 1)
+	assert.Equal(t, 190.0, prices["AAPL"].Price)
+	assert.True(t, prices["AAPL"].IsStale)
+	assert.True(t, prices["AAPL"].UpdatedAt.Equal(fetchedAt))
+	assert.Contains(t, prices["AAPL"].StaleReason, "yahoo, 3h 0m old")
+}
+
+func TestPriceProviderRegistry_RemembersPricesInOneBatchOutsideLock(t *testing.T) {
+	primary := &fakePriceProvider{name: "primary", prices: map[string]float64{"AAPL": 190}}
+	secondary := &fakePriceProvider{name: "secondary", prices: map[string]float64{"MSFT": 410}}
+	repo := &fakeLastKnownPriceRepository{prices: make(map[string]*models.Price)}
+
+	registry := NewPriceProviderRegistry()
+	registry.Register(primary)
+	registry.Register(secondary)
+	registry.Register(NewLastKnownPriceProvider(repo))
+	require.NoError(t, registry.SetChain(models.AssetTypeUSStock, []string{primary.name, secondary.name, PriceProviderLastKnown}))
+
+	// 寫入資料庫時不得持有註冊表的鎖（寫入期間取得寫入鎖不應被阻塞）
+	repo.onUpsert = func() {
+		locked := make(chan struct{})
+		go func() {
+			registry.(*priceProviderRegistry).recordResult("primary", 0, nil)
+			close(locked)
+		}()
+		select {
+		case <-locked:
+		case <-time.After(time.Second):
+			t.Error("registry lock held while saving last known prices")
+		}
+	}
+
+	_, err := registry.FetchPrices([]string{"AAPL", "MSFT"}, models.AssetTypeUSStock)
+	require.NoError(t, err)
+
+	// 兩個來源取得的價格在同一次查詢中只寫入一次
+	assert.Equal(t, 1, repo.batches)
+	assert.Len(t, repo.prices, 2)
+}
+
+func TestFormatPriceAge(t *testing.T) {
+	assert.Equal(t, "<1m", formatPriceAge(30*time.Second))
+	assert.Equal(t, "45m", formatPriceAge(45*time.Minute))
+	assert.Equal(t, "2h 5m", formatPriceAge(2*time.Hour+5*time.Minute))
+	assert.Equal(t, "3d 4h", formatPriceAge(76*time.Hour))
+}
+
+func TestPriceProviderRegistry_AllProvidersFail(t *testing.T) {
+	primary := &fakePriceProvider{name: "primary", err: errors.New("service unavailable")}
+	secondary := &fakePriceProvider{name: "secondary", err: errors.New("timeout")}
+	registry := newTestPriceProviderRegistry(t, primary, secondary)
+
+	for i := 0; i < unhealthyConsecutiveFailures; i++ {
+		_, err := registry.FetchPrices([]string{"AAPL"}, models.AssetTypeUSStock)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "primary: service unavailable")
+	}
+
+	status := registry.Status()
+	assert.False(t, status.Providers[0].Healthy)
+	assert.Equal(t, int64(unhealthyConsecutiveFailures), status.Providers[2].FailureCount)
+}
+
+func TestPriceProviderRegistry_PreservesTypedErrors(t *testing.T) {
+	primary := &fakePriceProvider{name: "primary", err: fmt.Errorf("Alpha Vantage API rate limit: %w", external.ErrQuotaExhausted)}
+	secondary := &fakePriceProvider{name: "secondary", err: fmt.Errorf("no price data found for symbol XYZ: %w", external.ErrSymbolNotFound)}
+	registry := newTestPriceProviderRegistry(t, primary, secondary)
+
+	_, err := registry.FetchPrices([]string{"XYZ"}, models.AssetTypeUSStock)
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, external.ErrRateLimited)
+	assert.ErrorIs(t, err, external.ErrSymbolNotFound)
+
+	// 查無標的代表來源正常回應，不計入失敗
+	status := registry.Status()
+	assert.Equal(t, int64(1), status.Providers[0].FailureCount)
+	assert.Equal(t, int64(0), status.Providers[1].FailureCount)
+	assert.True(t, status.Providers[1].Healthy)
+}
+
+func TestPriceProviderRegistry_SetChainValidation(t *testing.T) {
+	registry := NewPriceProviderRegistry()
+	registry.Register(&fakePriceProvider{name: "primary"})
+
+	assert.Error(t, registry.SetChain(models.AssetTypeUSStock, []string{"unknown"}))
+	assert.Error(t, registry.SetChain(models.AssetTypeTWStock, []string{"primary"}))
+	assert.Error(t, registry.SetChain(models.AssetTypeUSStock, []string{}))
+	assert.NoError(t, registry.SetChain(models.AssetTypeUSStock, []string{"primary"}))
+
+	_, err := registry.FetchPrices([]string{"2330"}, models.AssetTypeTWStock)
+	assert.Error(t, err)
+}
+
+func TestProviderChainPriceService_StablecoinSkipsProviders(t *testing.T) {
+	primary := &fakePriceProvider{name: "primary", prices: map[string]float64{"AAPL": 190}}
+	secondary := &fakePriceProvider{name: "secondary"}
+	svc := NewProviderChainPriceService(newTestPriceProviderRegistry(t, primary, secondary))
+
+	prices, err := svc.GetPrices([]string{"AAPL", "USDT"}, map[string]models.AssetType{
+		"AAPL": models.AssetTypeUSStock,
+		"USDT": models.AssetTypeCrypto,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 190.0, prices["AAPL"].Price)
+	assert.Equal(t, 1.0, prices["USDT"].Price)
+	assert.Equal(t, "peg", prices["USDT"].Source)
+}
@@ -0,0 +1,318 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chienchuanw/asset-manager/internal/models"
+	"github.com/chienchuanw/asset-manager/internal/repository"
+)
+
+// closingPriceDelay 收盤後延遲更新的時間（等待來源更新收盤價）
+const closingPriceDelay = 30 * time.Minute
+
+// closingRetryMaxBackoff 收盤價更新失敗後重試間隔的上限
+const closingRetryMaxBackoff = 2 * time.Hour
+
+// staleRefreshIntervals 快取價格超過幾個更新間隔未更新即視為過期（容許偶發的更新失敗）
+const staleRefreshIntervals = 3
+
+// PriceRefreshConfig 背景價格更新設定
+type PriceRefreshConfig struct {
+	TWStockInterval time.Duration                 // 台股交易時段內的更新間隔（FinMind 免費版每小時 600 次）
+	USStockInterval time.Duration                 // 美股交易時段內的更新間隔（Alpha Vantage 備援每分鐘 5 次、每日 25 次）
+	CryptoInterval  time.Duration                 // 加密貨幣更新間隔（24 小時交易，CoinGecko 免費版每分鐘約 30 次）
+	ExtraHolidays   map[models.AssetType][]string // 額外休市日（YYYY-MM-DD），補充內建行事曆
+	HolidaysFile    string                        // 休市日資料檔（JSON），未設定時使用內建資料
+}
+
+// DefaultPriceRefreshConfig 預設背景價格更新設定
+func DefaultPriceRefreshConfig() PriceRefreshConfig {
+	return PriceRefreshConfig{
+		TWStockInterval: 5 * time.Minute,
+		USStockInterval: 15 * time.Minute,
+		CryptoInterval:  1 * time.Hour,
+		ExtraHolidays:   map[models.AssetType][]string{},
+	}
+}
+
+// PriceRefreshConfigFromEnv 讀取環境變數中的背景價格更新設定
+// PRICE_REFRESH_TW_STOCK_INTERVAL、PRICE_REFRESH_US_STOCK_INTERVAL、PRICE_REFRESH_CRYPTO_INTERVAL（例如 10m）
+// MARKET_HOLIDAYS_TW_STOCK、MARKET_HOLIDAYS_US_STOCK（以逗號分隔的 YYYY-MM-DD）
+// MARKET_HOLIDAYS_FILE（格式與內建的 market_holidays.json 相同）
+func PriceRefreshConfigFromEnv() PriceRefreshConfig {
+	config := DefaultPriceRefreshConfig()
+	config.HolidaysFile = os.Getenv("MARKET_HOLIDAYS_FILE")
+
+	intervals := map[string]*time.Duration{
+		"PRICE_REFRESH_TW_STOCK_INTERVAL": &config.TWStockInterval,
+		"PRICE_REFRESH_US_STOCK_INTERVAL": &config.USStockInterval,
+		"PRICE_REFRESH_CRYPTO_INTERVAL":   &config.CryptoInterval,
+	}
+	for key, target := range intervals {
+		value := os.Getenv(key)
+		if value == "" {
+			continue
+		}
+		duration, err := time.ParseDuration(value)
+		if err != nil || duration <= 0 {
+			log.Printf("Warning: invalid %s: %s, using default %v", key, value, *target)
+			continue
+		}
+		*target = duration
+	}
+
+	holidayKeys := map[models.AssetType]string{
+		models.AssetTypeTWStock: "MARKET_HOLIDAYS_TW_STOCK",
+		models.AssetTypeUSStock: "MARKET_HOLIDAYS_US_STOCK",
+	}
+	for assetType, key := range holidayKeys {
+		for _, date := range strings.Split(os.Getenv(key), ",") {
+			date = strings.TrimSpace(date)
+			if date == "" {
+				continue
+			}
+			if _, err := time.Parse("2006-01-02", date); err != nil {
+				log.Printf("Warning: invalid date in %s: %s", key, date)
+				continue
+			}
+			config.ExtraHolidays[assetType] = append(config.ExtraHolidays[assetType], date)
+		}
+	}
+
+	return config
+}
+
+// intervalFor 取得資產類型的更新間隔
+func (c PriceRefreshConfig) intervalFor(assetType models.AssetType) time.Duration {
+	switch assetType {
+	case models.AssetTypeTWStock:
+		return c.TWStockInterval
+	case models.AssetTypeUSStock:
+		return c.USStockInterval
+	default:
+		return c.CryptoInterval
+	}
+}
+
+// PriceRefreshService 背景價格更新服務介面
+type PriceRefreshService interface {
+	// RefreshDue 更新目前到期的持倉價格（交易時段內依間隔更新，休市後於收盤時更新一次）
+	RefreshDue(now time.Time) (*models.PriceRefreshResult, error)
+}
+
+// priceRefreshService 背景價格更新服務實作
+type priceRefreshService struct {
+	transactionRepo    repository.TransactionRepository
+	fifoCalculator     FIFOCalculator
+	priceService       PriceService
+	optionContractRepo repository.OptionContractRepository
+	config             PriceRefreshConfig
+	calendars          map[models.AssetType]*marketCalendar
+
+	mu              sync.Mutex
+	lastAttemptAt   map[models.AssetType]time.Time // 最近一次請求時間（不論成功與否）
+	lastRefreshAt   map[models.AssetType]time.Time // 最近一次成功更新時間
+	closingFailures map[models.AssetType]int       // 收盤價連續更新失敗次數
+}
+
+// NewPriceRefreshService 建立背景價格更新服務
+// priceService 應為快取價格服務，RefreshPrices 取得的價格會寫回快取供持倉查詢使用
+func NewPriceRefreshService(
+	transactionRepo repository.TransactionRepository,
+	fifoCalculator FIFOCalculator,
+	priceService PriceService,
+	optionContractRepo repository.OptionContractRepository,
+	config PriceRefreshConfig,
+) PriceRefreshService {
+	return &priceRefreshService{
+		transactionRepo:    transactionRepo,
+		fifoCalculator:     fifoCalculator,
+		priceService:       priceService,
+		optionContractRepo: optionContractRepo,
+		config:             config,
+		calendars:          newMarketCalendars(config),
+		lastAttemptAt:      make(map[models.AssetType]time.Time),
+		lastRefreshAt:      make(map[models.AssetType]time.Time),
+		closingFailures:    make(map[models.AssetType]int),
+	}
+}
+
+// RefreshDue 更新目前到期的持倉價格
+func (s *priceRefreshService) RefreshDue(now time.Time) (*models.PriceRefreshResult, error) {
+	// 同一時間只允許一次更新，避免排程與啟動時的更新重疊造成重複請求
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	targets, err := s.heldPriceTargets()
+	if err != nil {
+		return nil, err
+	}
+
+	// 按資產類型分組，各組以單次批次請求更新
+	grouped := make(map[models.AssetType][]string)
+	for symbol, assetType := range targets {
+		grouped[assetType] = append(grouped[assetType], symbol)
+	}
+
+	result := &models.PriceRefreshResult{
+		RunAt:  now,
+		Groups: []*models.PriceRefreshGroup{},
+	}
+
+	for _, assetType := range []models.AssetType{models.AssetTypeTWStock, models.AssetTypeUSStock, models.AssetTypeCrypto} {
+		symbols := grouped[assetType]
+		if len(symbols) == 0 {
+			continue
+		}
+
+		due, marketOpen := s.isDue(assetType, now)
+		if !due {
+			continue
+		}
+
+		group := &models.PriceRefreshGroup{
+			AssetType:  assetType,
+			MarketOpen: marketOpen,
+			Symbols:    len(symbols),
+		}
+
+		groupTypes := make(map[string]models.AssetType, len(symbols))
+		for _, symbol := range symbols {
+			groupTypes[symbol] = assetType
+		}
+
+		// 無論成功與否都記錄請求時間，交易時段內失敗時等下一個間隔再試，避免持續請求已達上限的來源
+		// 成功時才記錄更新時間，收盤價更新失敗時會在退避間隔後重試
+		prices, err := s.priceService.RefreshPrices(symbols, groupTypes)
+		s.lastAttemptAt[assetType] = now
+
+		if err != nil {
+			group.Error = err.Error()
+			if !marketOpen {
+				s.closingFailures[assetType]++
+			}
+		} else {
+			s.lastRefreshAt[assetType] = now
+			delete(s.closingFailures, assetType)
+			for _, price := range prices {
+				if !price.IsStale {
+					group.Refreshed++
+				}
+			}
+			if group.Refreshed < group.Symbols {
+				group.Error = fmt.Sprintf("refreshed %d of %d symbols", group.Refreshed, group.Symbols)
+			}
+		}
+
+		result.Groups = append(result.Groups, group)
+	}
+
+	return result, nil
+}
+
+// heldPriceTargets 取得目前持倉需要報價的標的
+func (s *priceRefreshService) heldPriceTargets() (map[string]models.AssetType, error) {
+	transactions, err := s.transactionRepo.GetAll(repository.TransactionFilters{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions: %w", err)
+	}
+	if len(transactions) == 0 {
+		return map[string]models.AssetType{}, nil
+	}
+
+	result, err := s.fifoCalculator.CalculateAllHoldings(transactions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate holdings: %w", err)
+	}
+
+	return priceTargets(result.Holdings, s.optionContractRepo), nil
+}
+
+// isDue 判斷資產類型是否需要更新，並回傳市場是否在交易時段內
+func (s *priceRefreshService) isDue(assetType models.AssetType, now time.Time) (bool, bool) {
+	lastAttemptAt, attempted := s.lastAttemptAt[assetType]
+
+	calendar := s.calendars[assetType]
+	if calendar == nil {
+		// 加密貨幣 24 小時交易
+		return !attempted || now.Sub(lastAttemptAt) >= s.config.CryptoInterval, true
+	}
+
+	// 啟動後第一次執行一律更新，填補快取
+	if !attempted {
+		return true, calendar.IsOpen(now)
+	}
+
+	if calendar.IsOpen(now) {
+		return now.Sub(lastAttemptAt) >= s.config.intervalFor(assetType), true
+	}
+
+	// 休市期間：收盤後延遲一段時間更新一次收盤價，成功後不再請求
+	lastClose := calendar.LastClose(now)
+	if lastClose.IsZero() {
+		return false, false
+	}
+	closingRefreshAt := lastClose.Add(closingPriceDelay)
+	if now.Before(closingRefreshAt) || !s.lastRefreshAt[assetType].Before(closingRefreshAt) {
+		return false, false
+	}
+	if lastAttemptAt.Before(closingRefreshAt) {
+		return true, false
+	}
+	return now.Sub(lastAttemptAt) >= s.closingRetryBackoff(assetType), false
+}
+
+// closingRetryBackoff 收盤價更新失敗後的重試間隔（自更新間隔起每次加倍，最多 closingRetryMaxBackoff）
+func (s *priceRefreshService) closingRetryBackoff(assetType models.AssetType) time.Duration {
+	backoff := s.config.intervalFor(assetType)
+	for i := 1; i < s.closingFailures[assetType] && backoff < closingRetryMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > closingRetryMaxBackoff {
+		return closingRetryMaxBackoff
+	}
+	return backoff
+}
+
+// priceFreshness 依背景更新排程判斷快取價格是否過期
+type priceFreshness struct {
+	config    PriceRefreshConfig
+	calendars map[models.AssetType]*marketCalendar
+}
+
+// newPriceFreshness 建立快取價格新舊判斷
+func newPriceFreshness(config PriceRefreshConfig) *priceFreshness {
+	return &priceFreshness{
+		config:    config,
+		calendars: newMarketCalendars(config),
+	}
+}
+
+// IsStale 判斷快取價格是否落後背景更新排程
+// 交易時段內需在數個更新間隔內更新過；休市期間需涵蓋最近一次收盤
+func (f *priceFreshness) IsStale(assetType models.AssetType, cachedAt time.Time, now time.Time) bool {
+	tolerance := staleRefreshIntervals * f.config.intervalFor(assetType)
+
+	calendar := f.calendars[assetType]
+	if calendar == nil {
+		// 加密貨幣 24 小時交易
+		return now.Sub(cachedAt) > tolerance
+	}
+
+	// 開盤初期尚未經過足夠的更新間隔時，以前一次收盤為準
+	required := now.Add(-tolerance)
+	if !calendar.IsOpen(now) || required.Before(calendar.openOn(now)) {
+		lastClose := calendar.LastClose(now)
+		if lastClose.IsZero() {
+			return false
+		}
+		required = lastClose.Add(-tolerance)
+	}
+
+	return cachedAt.Before(required)
+}
@@ -0,0 +1,197 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/chienchuanw/asset-manager/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestPriceRefreshService 建立持有台股 2330 與 BTC 的背景價格更新服務
+func newTestPriceRefreshService() (PriceRefreshService, *MockPriceService) {
+	mockRepo := new(MockTransactionRepositoryForHolding)
+	mockPriceService := new(MockPriceService)
+
+	transactions := []*models.Transaction{
+		{
+			Date:            time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			AssetType:       models.AssetTypeTWStock,
+			Symbol:          "2330",
+			TransactionType: models.TransactionTypeBuy,
+			Quantity:        100,
+			Price:           500,
+			Amount:          50000,
+			Currency:        models.CurrencyTWD,
+		},
+		{
+			Date:            time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			AssetType:       models.AssetTypeCrypto,
+			Symbol:          "BTC",
+			TransactionType: models.TransactionTypeBuy,
+			Quantity:        1,
+			Price:           3000000,
+			Amount:          3000000,
+			Currency:        models.CurrencyTWD,
+		},
+	}
+	mockRepo.On("GetAll", mock.Anything).Return(transactions, nil)
+
+	for symbol, assetType := range map[string]models.AssetType{"2330": models.AssetTypeTWStock, "BTC": models.AssetTypeCrypto} {
+		mockPriceService.On("RefreshPrices", []string{symbol}, map[string]models.AssetType{symbol: assetType}).
+			Return(map[string]*models.Price{symbol: {Symbol: symbol, AssetType: assetType, Price: 100}}, nil)
+	}
+
+	service := NewPriceRefreshService(mockRepo, NewFIFOCalculator(newMockExchangeRateForTWD()), mockPriceService, nil, DefaultPriceRefreshConfig())
+	return service, mockPriceService
+}
+
+func refreshedAssetTypes(result *models.PriceRefreshResult) []models.AssetType {
+	assetTypes := []models.AssetType{}
+	for _, group := range result.Groups {
+		assetTypes = append(assetTypes, group.AssetType)
+	}
+	return assetTypes
+}
+
+func TestPriceRefresh_MarketHoursIntervals(t *testing.T) {
+	service, mockPriceService := newTestPriceRefreshService()
+	taipei := newMarketCalendars(PriceRefreshConfig{})[models.AssetTypeTWStock].location
+	open := time.Date(2026, 10, 19, 10, 0, 0, 0, taipei)
+
+	// 啟動後第一次執行：全部更新
+	result, err := service.RefreshDue(open)
+	require.NoError(t, err)
+	assert.Equal(t, []models.AssetType{models.AssetTypeTWStock, models.AssetTypeCrypto}, refreshedAssetTypes(result))
+	assert.True(t, result.Groups[0].MarketOpen)
+	assert.Equal(t, 1, result.Groups[0].Refreshed)
+
+	// 交易時段內 5 分鐘後只更新台股，加密貨幣每小時更新
+	result, err = service.RefreshDue(open.Add(5 * time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, []models.AssetType{models.AssetTypeTWStock}, refreshedAssetTypes(result))
+
+	result, err = service.RefreshDue(open.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, []models.AssetType{models.AssetTypeTWStock, models.AssetTypeCrypto}, refreshedAssetTypes(result))
+
+	mockPriceService.AssertNumberOfCalls(t, "RefreshPrices", 5)
+}
+
+func TestPriceRefresh_ClosedMarketRefreshesOnceAfterClose(t *testing.T) {
+	service, _ := newTestPriceRefreshService()
+	taipei := newMarketCalendars(PriceRefreshConfig{})[models.AssetTypeTWStock].location
+	beforeClose := time.Date(2026, 10, 19, 13, 25, 0, 0, taipei)
+
+	_, err := service.RefreshDue(beforeClose)
+	require.NoError(t, err)
+
+	// 收盤後尚未達延遲時間：不更新台股
+	result, err := service.RefreshDue(time.Date(2026, 10, 19, 13, 45, 0, 0, taipei))
+	require.NoError(t, err)
+	assert.Empty(t, result.Groups)
+
+	// 收盤 30 分鐘後更新一次收盤價
+	result, err = service.RefreshDue(time.Date(2026, 10, 19, 14, 0, 0, 0, taipei))
+	require.NoError(t, err)
+	require.Len(t, result.Groups, 1)
+	assert.Equal(t, models.AssetTypeTWStock, result.Groups[0].AssetType)
+	assert.False(t, result.Groups[0].MarketOpen)
+
+	// 休市期間不再請求
+	result, err = service.RefreshDue(time.Date(2026, 10, 19, 20, 0, 0, 0, taipei))
+	require.NoError(t, err)
+	assert.Equal(t, []models.AssetType{models.AssetTypeCrypto}, refreshedAssetTypes(result))
+}
+
+func TestPriceRefresh_ClosingRefreshRetriesWithBackoff(t *testing.T) {
+	mockRepo := new(MockTransactionRepositoryForHolding)
+	mockRepo.On("GetAll", mock.Anything).Return([]*models.Transaction{
+		{
+			Date:            time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			AssetType:       models.AssetTypeTWStock,
+			Symbol:          "2330",
+			TransactionType: models.TransactionTypeBuy,
+			Quantity:        100,
+			Price:           500,
+			Amount:          50000,
+			Currency:        models.CurrencyTWD,
+		},
+	}, nil)
+
+	symbols := []string{"2330"}
+	assetTypes := map[string]models.AssetType{"2330": models.AssetTypeTWStock}
+	prices := map[string]*models.Price{"2330": {Symbol: "2330", AssetType: models.AssetTypeTWStock, Price: 100}}
+	mockPriceService := new(MockPriceService)
+	mockPriceService.On("RefreshPrices", symbols, assetTypes).Return(prices, nil).Once()
+	mockPriceService.On("RefreshPrices", symbols, assetTypes).Return(nil, errors.New("rate limited")).Twice()
+	mockPriceService.On("RefreshPrices", symbols, assetTypes).Return(prices, nil).Once()
+
+	service := NewPriceRefreshService(mockRepo, NewFIFOCalculator(newMockExchangeRateForTWD()), mockPriceService, nil, DefaultPriceRefreshConfig())
+	taipei := newMarketCalendars(PriceRefreshConfig{})[models.AssetTypeTWStock].location
+	at := func(hour, minute int) time.Time {
+		return time.Date(2026, 10, 19, hour, minute, 0, 0, taipei)
+	}
+
+	_, err := service.RefreshDue(at(13, 25))
+	require.NoError(t, err)
+
+	// 收盤價更新失敗
+	result, err := service.RefreshDue(at(14, 0))
+	require.NoError(t, err)
+	require.Len(t, result.Groups, 1)
+	assert.Equal(t, "rate limited", result.Groups[0].Error)
+
+	// 一個更新間隔（5 分鐘）後重試，再次失敗後間隔加倍為 10 分鐘
+	result, err = service.RefreshDue(at(14, 3))
+	require.NoError(t, err)
+	assert.Empty(t, result.Groups)
+
+	result, err = service.RefreshDue(at(14, 5))
+	require.NoError(t, err)
+	require.Len(t, result.Groups, 1)
+	assert.NotEmpty(t, result.Groups[0].Error)
+
+	result, err = service.RefreshDue(at(14, 10))
+	require.NoError(t, err)
+	assert.Empty(t, result.Groups)
+
+	result, err = service.RefreshDue(at(14, 15))
+	require.NoError(t, err)
+	require.Len(t, result.Groups, 1)
+	assert.Empty(t, result.Groups[0].Error)
+
+	// 成功後休市期間不再請求
+	result, err = service.RefreshDue(at(20, 0))
+	require.NoError(t, err)
+	assert.Empty(t, result.Groups)
+
+	mockPriceService.AssertNumberOfCalls(t, "RefreshPrices", 4)
+}
+
+func TestPriceFreshness_IsStale(t *testing.T) {
+	freshness := newPriceFreshness(DefaultPriceRefreshConfig())
+	taipei := freshness.calendars[models.AssetTypeTWStock].location
+
+	// 交易時段內：台股 5 分鐘更新，超過 3 個間隔未更新即過期
+	now := time.Date(2026, 10, 19, 11, 0, 0, 0, taipei)
+	assert.False(t, freshness.IsStale(models.AssetTypeTWStock, now.Add(-10*time.Minute), now))
+	assert.True(t, freshness.IsStale(models.AssetTypeTWStock, now.Add(-20*time.Minute), now))
+
+	// 開盤初期：前一交易日（10/16）收盤後的價格仍有效
+	opening := time.Date(2026, 10, 19, 9, 5, 0, 0, taipei)
+	assert.False(t, freshness.IsStale(models.AssetTypeTWStock, time.Date(2026, 10, 16, 14, 0, 0, 0, taipei), opening))
+	assert.True(t, freshness.IsStale(models.AssetTypeTWStock, time.Date(2026, 10, 15, 14, 0, 0, 0, taipei), opening))
+
+	// 週末休市：需涵蓋週五收盤
+	weekend := time.Date(2026, 10, 18, 12, 0, 0, 0, taipei)
+	assert.False(t, freshness.IsStale(models.AssetTypeTWStock, time.Date(2026, 10, 16, 14, 0, 0, 0, taipei), weekend))
+	assert.True(t, freshness.IsStale(models.AssetTypeTWStock, time.Date(2026, 10, 16, 11, 0, 0, 0, taipei), weekend))
+
+	// 加密貨幣每小時更新，超過 3 小時未更新即過期
+	assert.False(t, freshness.IsStale(models.AssetTypeCrypto, weekend.Add(-2*time.Hour), weekend))
+	assert.True(t, freshness.IsStale(models.AssetTypeCrypto, weekend.Add(-4*time.Hour), weekend))
+}
@@ -1,12 +1,14 @@
 package service
 
 import (
+	"errors"
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/chienchuanw/asset-manager/internal/cache"
+	"github.com/chienchuanw/asset-manager/internal/external"
 	"github.com/chienchuanw/asset-manager/internal/models"
+	"github.com/chienchuanw/asset-manager/internal/repository"
 )
 
 // PriceService 價格服務介面
@@ -19,6 +21,9 @@ type PriceService interface {
 
 	// RefreshPrice 手動更新價格（清除快取並重新取得）
 	RefreshPrice(symbol string, assetType models.AssetType) (*models.Price, error)
+
+	// RefreshPrices 批次更新價格（略過快取直接向來源取得，並寫回快取）
+	RefreshPrices(symbols []string, assetTypes map[string]models.AssetType) (map[string]*models.Price, error)
 }
 
 // mockPriceService Mock 價格服務（暫時使用固定價格）
@@ -102,6 +107,11 @@ func (s *mockPriceService) RefreshPrice(symbol string, assetType models.AssetTyp
 	return s.GetPrice(symbol, assetType)
 }
 
+// RefreshPrices 批次更新價格
+func (s *mockPriceService) RefreshPrices(symbols []string, assetTypes map[string]models.AssetType) (map[string]*models.Price, error) {
+	return s.GetPrices(symbols, assetTypes)
+}
+
 // ==================== Cached Price Service ====================
 
 // cachedPriceService 帶 Redis 快取的價格服務
@@ -110,15 +120,37 @@ type cachedPriceService struct {
 	fallback            PriceService // 當快取失效時使用的備用服務（例如 Mock 或真實 API）
 	defaultExpiration   time.Duration
 	usStockExpiration   time.Duration // 美股專用快取時間（較長，避免 API 限制）
+	lastKnownRepo       repository.LastKnownPriceRepository // 最後已知價格（備用服務失敗時使用，可為 nil）
+	cacheOnly           bool // 價格由背景排程更新，查詢時只讀取快取與最後已知價格
+	freshness           *priceFreshness // 背景更新模式下判斷快取價格是否落後更新排程
 }
 
+// backgroundRefreshCacheExpiration 背景更新模式的快取時間（需涵蓋連假休市期間，價格新舊由更新排程控制）
+const backgroundRefreshCacheExpiration = 7 * 24 * time.Hour
+
 // NewCachedPriceService 建立帶快取的價格服務
-func NewCachedPriceService(redisCache *cache.RedisCache, fallback PriceService, cacheExpiration time.Duration) PriceService {
+func NewCachedPriceService(redisCache *cache.RedisCache, fallback PriceService, cacheExpiration time.Duration, lastKnownRepo repository.LastKnownPriceRepository) PriceService {
 	return &cachedPriceService{
 		cache:             redisCache,
 		fallback:          fallback,
 		defaultExpiration: cacheExpiration,
 		usStockExpiration: 1 * time.Hour, // 美股快取 1 小時（避免 Alpha Vantage API 限制）
+		lastKnownRepo:     lastKnownRepo,
+	}
+}
+
+// NewCacheOnlyPriceService 建立只讀取快取的價格服務（價格由背景排程透過 RefreshPrices 更新）
+// 快取未命中時改用最後已知價格，兩者皆無時（例如剛買入的新標的）才即時向來源取得
+// 快取保留時間較長，命中時依 config 的更新排程判斷價格是否過期
+func NewCacheOnlyPriceService(redisCache *cache.RedisCache, fallback PriceService, lastKnownRepo repository.LastKnownPriceRepository, config PriceRefreshConfig) PriceService {
+	return &cachedPriceService{
+		cache:             redisCache,
+		fallback:          fallback,
+		defaultExpiration: backgroundRefreshCacheExpiration,
+		usStockExpiration: backgroundRefreshCacheExpiration,
+		lastKnownRepo:     lastKnownRepo,
+		cacheOnly:         true,
+		freshness:         newPriceFreshness(config),
 	}
 }
 
@@ -144,84 +176,80 @@ func (s *cachedPriceService) GetPrice(symbol string, assetType models.AssetType)
 	cacheErr := s.cache.Get(cacheKey, &cachedPrice)
 	if cacheErr == nil {
 		// 快取命中，返回快取資料
-		return &models.Price{
+		price := &models.Price{
 			Symbol:    cachedPrice.Symbol,
 			AssetType: cachedPrice.AssetType,
 			Price:     cachedPrice.Price,
 			Currency:  cachedPrice.Currency,
 			Source:    "cache",
 			UpdatedAt: cachedPrice.CachedAt,
-		}, nil
+		}
+
+		// 背景更新模式的快取保留較久，更新排程落後時（例如來源持續失敗）標記為過期
+		now := time.Now()
+		if s.freshness != nil && s.freshness.IsStale(assetType, cachedPrice.CachedAt, now) {
+			price.IsStale = true
+			price.StaleReason = fmt.Sprintf("Background price refresh is behind, using cached price from %s (%s old)",
+				cachedPrice.CachedAt.Format("2006-01-02 15:04"), formatPriceAge(now.Sub(cachedPrice.CachedAt)))
+		}
+		return price, nil
+	}
+
+	// 背景更新模式：快取未命中時不等待外部 API，改用最後已知價格
+	if s.cacheOnly {
+		if stale := s.lastKnownPrice(symbol, assetType, "Waiting for background price refresh"); stale != nil {
+			return stale, nil
+		}
 	}
 
 	// 2. 快取未命中，從 fallback 服務取得
 	price, err := s.fallback.GetPrice(symbol, assetType)
 	if err != nil {
-		// 檢查是否為 API rate limit 錯誤
-		errMsg := strings.ToLower(err.Error())
-		isRateLimit := strings.Contains(errMsg, "rate limit") ||
-		               strings.Contains(errMsg, "api rate limit") ||
-		               strings.Contains(errMsg, "standard api rate limit")
-
-		// 如果是 rate limit
-		if isRateLimit {
-			// 嘗試使用舊的快取資料（即使已過期）
-			if cacheErr == nil {
-				fmt.Printf("Warning: API rate limit for %s, using stale cache\n", symbol)
-				return &models.Price{
-					Symbol:      cachedPrice.Symbol,
-					AssetType:   cachedPrice.AssetType,
-					Price:       cachedPrice.Price,
-					Currency:    cachedPrice.Currency,
-					Source:      "stale-cache",
-					UpdatedAt:   cachedPrice.CachedAt,
-					IsStale:     true,
-					StaleReason: "API rate limit exceeded, using cached price",
-				}, nil
-			}
-
-			// 如果連舊快取都沒有，返回一個預設價格（避免整個功能失敗）
-			fmt.Printf("Warning: API rate limit for %s and no cache available, using fallback price\n", symbol)
-			currency := "TWD"
-			if assetType == models.AssetTypeUSStock {
-				currency = "USD"
-			}
-			return &models.Price{
-				Symbol:      symbol,
-				AssetType:   assetType,
-				Price:       0, // 價格設為 0，表示無法取得
-				Currency:    currency,
-				Source:      "unavailable",
-				UpdatedAt:   time.Now(),
-				IsStale:     true,
-				StaleReason: "API rate limit exceeded, no cached price available",
-			}, nil
+		// Redis 快取過期即被刪除，無法再取得舊的快取資料，改用資料庫中的最後已知價格
+		reason := "Price API failed"
+		if errors.Is(err, external.ErrRateLimited) {
+			reason = "API rate limit exceeded"
+		}
+		if stale := s.lastKnownPrice(symbol, assetType, reason); stale != nil {
+			fmt.Printf("Warning: failed to get price for %s, using last known price: %v\n", symbol, err)
+			return stale, nil
 		}
 
-		// 其他錯誤或沒有快取資料，返回錯誤
+		// 沒有最後已知價格，返回錯誤（由呼叫端以成本價估算，避免以 0 計算市值）
 		return nil, fmt.Errorf("failed to get price from fallback: %w", err)
 	}
 
-	// 3. 儲存到快取（根據資產類型使用不同的過期時間）
-	cacheData := models.PriceCache{
-		Symbol:    price.Symbol,
-		AssetType: price.AssetType,
-		Price:     price.Price,
-		Currency:  price.Currency,
-		CachedAt:  time.Now(),
+	// 來源鏈已退回最後已知價格時，不寫入快取，避免過期價格被當成最新價格
+	if price.IsStale {
+		return price, nil
 	}
 
-	expiration := s.getCacheExpiration(assetType)
-	if err := s.cache.Set(cacheKey, cacheData, expiration); err != nil {
-		// 快取失敗不影響返回結果，只記錄錯誤
-		fmt.Printf("Warning: failed to cache price for %s: %v\n", symbol, err)
-	}
+	// 3. 儲存到快取（根據資產類型使用不同的過期時間）
+	s.storeInCache(price)
 
 	// 更新 source 為 API
 	price.Source = "api"
 	return price, nil
 }
 
+// lastKnownPrice 取得最後已知價格並標記為過期（無資料時回傳 nil）
+func (s *cachedPriceService) lastKnownPrice(symbol string, assetType models.AssetType, reason string) *models.Price {
+	if s.lastKnownRepo == nil {
+		return nil
+	}
+
+	stored, err := s.lastKnownRepo.Get(symbol, assetType)
+	if err != nil {
+		fmt.Printf("Warning: failed to get last known price for %s: %v\n", symbol, err)
+		return nil
+	}
+	if stored == nil {
+		return nil
+	}
+
+	return newLastKnownStalePrice(stored, reason)
+}
+
 // GetPrices 批次取得多個標的價格
 // 採用部分成功策略：即使部分價格無法取得，仍然返回可用的價格
 func (s *cachedPriceService) GetPrices(symbols []string, assetTypes map[string]models.AssetType) (map[string]*models.Price, error) {
@@ -276,7 +304,38 @@ func (s *cachedPriceService) RefreshPrice(symbol string, assetType models.AssetT
 		return nil, fmt.Errorf("failed to refresh price: %w", err)
 	}
 
+	// 來源鏈已退回最後已知價格時，不寫入快取，避免過期價格被當成最新價格
+	if price.IsStale {
+		return price, nil
+	}
+
 	// 3. 儲存到快取（根據資產類型使用不同的過期時間）
+	s.storeInCache(price)
+
+	price.Source = "api"
+	return price, nil
+}
+
+// RefreshPrices 批次更新價格（直接向來源取得並寫回快取，供背景排程使用）
+func (s *cachedPriceService) RefreshPrices(symbols []string, assetTypes map[string]models.AssetType) (map[string]*models.Price, error) {
+	prices, err := s.fallback.GetPrices(symbols, assetTypes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to refresh prices: %w", err)
+	}
+
+	for _, price := range prices {
+		// 來源鏈已退回最後已知價格時，保留原本的快取
+		if price.IsStale {
+			continue
+		}
+		s.storeInCache(price)
+	}
+
+	return prices, nil
+}
+
+// storeInCache 將價格寫入快取（根據資產類型使用不同的過期時間）
+func (s *cachedPriceService) storeInCache(price *models.Price) {
 	cacheData := models.PriceCache{
 		Symbol:    price.Symbol,
 		AssetType: price.AssetType,
@@ -285,11 +344,10 @@ func (s *cachedPriceService) RefreshPrice(symbol string, assetType models.AssetT
 		CachedAt:  time.Now(),
 	}
 
-	expiration := s.getCacheExpiration(assetType)
+	cacheKey := s.getCacheKey(price.Symbol, price.AssetType)
+	expiration := s.getCacheExpiration(price.AssetType)
 	if err := s.cache.Set(cacheKey, cacheData, expiration); err != nil {
-		fmt.Printf("Warning: failed to cache refreshed price for %s: %v\n", symbol, err)
+		// 快取失敗不影響返回結果，只記錄錯誤
+		fmt.Printf("Warning: failed to cache price for %s: %v\n", price.Symbol, err)
 	}
-
-	price.Source = "api"
-	return price, nil
 }
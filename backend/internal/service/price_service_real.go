@@ -4,77 +4,53 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/chienchuanw/asset-manager/internal/external"
 	"github.com/chienchuanw/asset-manager/internal/models"
 )
 
-// realPriceService 真實價格服務（整合多個外部 API）
+// realPriceService 真實價格服務（依資產類型的來源順序向外部 API 取得價格）
 type realPriceService struct {
-	finmindClient       *external.FinMindClient
-	coingeckoClient     *external.CoinGeckoClient
-	alphaVantageClient  *external.AlphaVantageClient
+	registry PriceProviderRegistry
 }
 
-// NewRealPriceService 建立真實價格服務
+// NewRealPriceService 建立真實價格服務（使用內建來源與預設來源順序）
 func NewRealPriceService(finmindAPIKey, coingeckoAPIKey, alphaVantageAPIKey string) PriceService {
+	return NewProviderChainPriceService(NewDefaultPriceProviderRegistry(finmindAPIKey, coingeckoAPIKey, alphaVantageAPIKey, nil, nil))
+}
+
+// NewProviderChainPriceService 建立使用指定來源註冊表的價格服務
+func NewProviderChainPriceService(registry PriceProviderRegistry) PriceService {
 	return &realPriceService{
-		finmindClient:       external.NewFinMindClient(finmindAPIKey),
-		coingeckoClient:     external.NewCoinGeckoClient(coingeckoAPIKey),
-		alphaVantageClient:  external.NewAlphaVantageClient(alphaVantageAPIKey),
+		registry: registry,
 	}
 }
 
 // GetPrice 取得單一標的價格
 func (s *realPriceService) GetPrice(symbol string, assetType models.AssetType) (*models.Price, error) {
-	var price float64
-	var currency string
-	var err error
-
-	switch assetType {
-	case models.AssetTypeTWStock:
-		// 台股：使用 FinMind API
-		price, err = s.finmindClient.GetStockPrice(symbol)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get TW stock price: %w", err)
-		}
-		currency = "TWD"
-
-	case models.AssetTypeUSStock:
-		// 美股：使用 Alpha Vantage API
-		price, err = s.alphaVantageClient.GetStockPrice(symbol)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get US stock price: %w", err)
-		}
-		currency = "USD"
+	// 美元穩定幣視為準貨幣，固定以 1 USD 計價
+	if assetType == models.AssetTypeCrypto && models.IsStablecoin(symbol) {
+		return stablecoinPrice(symbol), nil
+	}
 
-	case models.AssetTypeCrypto:
-		// 加密貨幣：使用 CoinGecko API（使用 USD 作為基準貨幣）
-		price, err = s.coingeckoClient.GetCryptoPrice(symbol, "usd")
-		if err != nil {
-			return nil, fmt.Errorf("failed to get crypto price: %w", err)
-		}
-		currency = "USD"
+	prices, err := s.registry.FetchPrices([]string{symbol}, assetType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s price: %w", assetType, err)
+	}
 
-	default:
-		return nil, fmt.Errorf("unsupported asset type: %s", assetType)
+	price, exists := prices[symbol]
+	if !exists {
+		return nil, fmt.Errorf("failed to get %s price: no price for %s", assetType, symbol)
 	}
 
-	return &models.Price{
-		Symbol:    symbol,
-		AssetType: assetType,
-		Price:     price,
-		Currency:  currency,
-		Source:    "api",
-		UpdatedAt: time.Now(),
-	}, nil
+	return price, nil
 }
 
 // GetPrices 批次取得多個標的價格
 func (s *realPriceService) GetPrices(symbols []string, assetTypes map[string]models.AssetType) (map[string]*models.Price, error) {
-	// 按資產類型分組
-	twStocks := []string{}
-	usStocks := []string{}
-	cryptos := []string{}
+	// 按資產類型分組（保留第一次出現的順序）
+	grouped := make(map[models.AssetType][]string)
+	groupOrder := []models.AssetType{}
+
+	prices := make(map[string]*models.Price)
 
 	for _, symbol := range symbols {
 		assetType, exists := assetTypes[symbol]
@@ -82,73 +58,27 @@ func (s *realPriceService) GetPrices(symbols []string, assetTypes map[string]mod
 			return nil, fmt.Errorf("asset type not found for symbol: %s", symbol)
 		}
 
-		switch assetType {
-		case models.AssetTypeTWStock:
-			twStocks = append(twStocks, symbol)
-		case models.AssetTypeUSStock:
-			usStocks = append(usStocks, symbol)
-		case models.AssetTypeCrypto:
-			cryptos = append(cryptos, symbol)
+		// 美元穩定幣固定以 1 USD 計價，不需查詢 API
+		if assetType == models.AssetTypeCrypto && models.IsStablecoin(symbol) {
+			prices[symbol] = stablecoinPrice(symbol)
+			continue
 		}
-	}
-
-	prices := make(map[string]*models.Price)
 
-	// 批次取得台股價格
-	if len(twStocks) > 0 {
-		twPrices, err := s.finmindClient.GetMultipleStockPrices(twStocks)
-		if err != nil {
-			fmt.Printf("Warning: failed to get TW stock prices: %v\n", err)
-		} else {
-			for symbol, price := range twPrices {
-				prices[symbol] = &models.Price{
-					Symbol:    symbol,
-					AssetType: models.AssetTypeTWStock,
-					Price:     price,
-					Currency:  "TWD",
-					Source:    "api",
-					UpdatedAt: time.Now(),
-				}
-			}
+		if _, exists := grouped[assetType]; !exists {
+			groupOrder = append(groupOrder, assetType)
 		}
+		grouped[assetType] = append(grouped[assetType], symbol)
 	}
 
-	// 批次取得美股價格
-	// 注意：Alpha Vantage 免費版有速率限制（每分鐘 5 次），批次查詢會較慢
-	if len(usStocks) > 0 {
-		usPrices, err := s.alphaVantageClient.GetMultipleStockPrices(usStocks)
+	// 各資產類型依來源順序批次取得價格
+	for _, assetType := range groupOrder {
+		groupPrices, err := s.registry.FetchPrices(grouped[assetType], assetType)
 		if err != nil {
-			fmt.Printf("Warning: failed to get US stock prices: %v\n", err)
-		} else {
-			for symbol, price := range usPrices {
-				prices[symbol] = &models.Price{
-					Symbol:    symbol,
-					AssetType: models.AssetTypeUSStock,
-					Price:     price,
-					Currency:  "USD",
-					Source:    "api",
-					UpdatedAt: time.Now(),
-				}
-			}
+			fmt.Printf("Warning: failed to get %s prices: %v\n", assetType, err)
+			continue
 		}
-	}
-
-	// 批次取得加密貨幣價格
-	if len(cryptos) > 0 {
-		cryptoPrices, err := s.coingeckoClient.GetMultipleCryptoPrices(cryptos, "usd")
-		if err != nil {
-			fmt.Printf("Warning: failed to get crypto prices: %v\n", err)
-		} else {
-			for symbol, price := range cryptoPrices {
-				prices[symbol] = &models.Price{
-					Symbol:    symbol,
-					AssetType: models.AssetTypeCrypto,
-					Price:     price,
-					Currency:  "USD",
-					Source:    "api",
-					UpdatedAt: time.Now(),
-				}
-			}
+		for symbol, price := range groupPrices {
+			prices[symbol] = price
 		}
 	}
 
@@ -166,3 +96,19 @@ func (s *realPriceService) RefreshPrice(symbol string, assetType models.AssetTyp
 	return s.GetPrice(symbol, assetType)
 }
 
+// RefreshPrices 批次更新價格（每次都是從 API 取得最新價格，與 GetPrices 相同）
+func (s *realPriceService) RefreshPrices(symbols []string, assetTypes map[string]models.AssetType) (map[string]*models.Price, error) {
+	return s.GetPrices(symbols, assetTypes)
+}
+
+// stablecoinPrice 美元穩定幣的掛鉤價格（1 USD）
+func stablecoinPrice(symbol string) *models.Price {
+	return &models.Price{
+		Symbol:    symbol,
+		AssetType: models.AssetTypeCrypto,
+		Price:     1,
+		Currency:  "USD",
+		Source:    "peg",
+		UpdatedAt: time.Now(),
+	}
+}
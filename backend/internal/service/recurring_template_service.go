@@ -0,0 +1,340 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/chienchuanw/asset-manager/internal/models"
+	"github.com/chienchuanw/asset-manager/internal/repository"
+	"github.com/google/uuid"
+)
+
+// RecurringTemplateService 定期收支範本業務邏輯介面
+type RecurringTemplateService interface {
+	CreateTemplate(input *models.CreateRecurringTemplateInput) (*models.RecurringTemplate, error)
+	GetTemplate(id uuid.UUID) (*models.RecurringTemplate, error)
+	ListTemplates(filters repository.RecurringTemplateFilters) ([]*models.RecurringTemplate, error)
+	UpdateTemplate(id uuid.UUID, input *models.UpdateRecurringTemplateInput) (*models.RecurringTemplate, error)
+	DeleteTemplate(id uuid.UUID) error
+	GetOccurrences(id uuid.UUID, from, to time.Time) ([]*models.RecurringOccurrence, error)
+	AddAmountChange(id uuid.UUID, input *models.CreateRecurringAmountChangeInput) (*models.RecurringAmountChange, error)
+	DeleteAmountChange(id, changeID uuid.UUID) error
+}
+
+// recurringTemplateService 定期收支範本業務邏輯實作
+type recurringTemplateService struct {
+	repo         repository.RecurringTemplateRepository
+	categoryRepo repository.CategoryRepository
+}
+
+// NewRecurringTemplateService 建立新的定期收支範本 service
+func NewRecurringTemplateService(
+	repo repository.RecurringTemplateRepository,
+	categoryRepo repository.CategoryRepository,
+) RecurringTemplateService {
+	return &recurringTemplateService{
+		repo:         repo,
+		categoryRepo: categoryRepo,
+	}
+}
+
+// CreateTemplate 建立新的定期收支範本
+func (s *recurringTemplateService) CreateTemplate(input *models.CreateRecurringTemplateInput) (*models.RecurringTemplate, error) {
+	// 驗證範本名稱
+	if input.Name == "" {
+		return nil, fmt.Errorf("template name is required")
+	}
+
+	if len(input.Name) > 255 {
+		return nil, fmt.Errorf("template name must not exceed 255 characters")
+	}
+
+	// 驗證金額
+	if input.Amount <= 0 {
+		return nil, fmt.Errorf("amount must be greater than zero")
+	}
+
+	// 套用預設值
+	if input.Currency == "" {
+		input.Currency = models.CurrencyTWD
+	}
+	if input.Interval == 0 {
+		input.Interval = 1
+	}
+	if input.BusinessDayAdjustment == "" {
+		input.BusinessDayAdjustment = models.BusinessDayAdjustmentNone
+	}
+
+	if !input.Currency.Validate() {
+		return nil, fmt.Errorf("invalid currency: %s", input.Currency)
+	}
+
+	template := &models.RecurringTemplate{
+		Type:                  input.Type,
+		CategoryID:            input.CategoryID,
+		PaymentMethod:         input.PaymentMethod,
+		AccountID:             input.AccountID,
+		TargetType:            input.TargetType,
+		TargetID:              input.TargetID,
+		Frequency:             input.Frequency,
+		Interval:              input.Interval,
+		ByWeekdays:            input.ByWeekdays,
+		ByMonthDay:            input.ByMonthDay,
+		ByMonths:              input.ByMonths,
+		BusinessDayAdjustment: input.BusinessDayAdjustment,
+		StartDate:             input.StartDate,
+		EndDate:               input.EndDate,
+	}
+
+	if err := s.validateTemplate(template); err != nil {
+		return nil, err
+	}
+
+	// 呼叫 repository 建立範本
+	created, err := s.repo.Create(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create recurring template: %w", err)
+	}
+
+	return created, nil
+}
+
+// GetTemplate 取得單筆定期收支範本（含金額調整記錄）
+func (s *recurringTemplateService) GetTemplate(id uuid.UUID) (*models.RecurringTemplate, error) {
+	template, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := s.repo.GetAmountChanges(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get amount changes: %w", err)
+	}
+	template.AmountChanges = changes
+
+	return template, nil
+}
+
+// ListTemplates 取得定期收支範本列表
+func (s *recurringTemplateService) ListTemplates(filters repository.RecurringTemplateFilters) ([]*models.RecurringTemplate, error) {
+	// 驗證篩選條件
+	if filters.Status != nil && !filters.Status.Validate() {
+		return nil, fmt.Errorf("invalid recurring template status filter: %s", *filters.Status)
+	}
+
+	if filters.Type != nil && !filters.Type.Validate() {
+		return nil, fmt.Errorf("invalid cash flow type filter: %s", *filters.Type)
+	}
+
+	return s.repo.List(filters)
+}
+
+// UpdateTemplate 更新定期收支範本
+// 排程與付款設定可部分更新，因此以更新後的完整範本重新驗證
+func (s *recurringTemplateService) UpdateTemplate(id uuid.UUID, input *models.UpdateRecurringTemplateInput) (*models.RecurringTemplate, error) {
+	// 驗證範本是否存在
+	existing, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("recurring template not found: %w", err)
+	}
+
+	// 驗證範本名稱
+	if input.Name != nil {
+		if *input.Name == "" {
+			return nil, fmt.Errorf("template name cannot be empty")
+		}
+		if len(*input.Name) > 255 {
+			return nil, fmt.Errorf("template name must not exceed 255 characters")
+		}
+	}
+
+	// 驗證金額
+	if input.Amount != nil && *input.Amount <= 0 {
+		return nil, fmt.Errorf("amount must be greater than zero")
+	}
+
+	// 驗證狀態
+	if input.Status != nil && !input.Status.Validate() {
+		return nil, fmt.Errorf("invalid recurring template status: %s", *input.Status)
+	}
+
+	merged := *existing
+	if input.CategoryID != nil {
+		merged.CategoryID = *input.CategoryID
+	}
+	if input.PaymentMethod != nil {
+		merged.PaymentMethod = *input.PaymentMethod
+	}
+	if input.AccountID != nil {
+		merged.AccountID = input.AccountID
+	}
+	if input.TargetType != nil {
+		merged.TargetType = input.TargetType
+	}
+	if input.TargetID != nil {
+		merged.TargetID = input.TargetID
+	}
+	if input.Frequency != nil {
+		merged.Frequency = *input.Frequency
+	}
+	if input.Interval != nil {
+		merged.Interval = *input.Interval
+	}
+	if input.ByWeekdays != nil {
+		merged.ByWeekdays = *input.ByWeekdays
+	}
+	if input.ByMonthDay != nil {
+		merged.ByMonthDay = input.ByMonthDay
+	}
+	if input.ByMonths != nil {
+		merged.ByMonths = *input.ByMonths
+	}
+	if input.BusinessDayAdjustment != nil {
+		merged.BusinessDayAdjustment = *input.BusinessDayAdjustment
+	}
+	if input.EndDate != nil {
+		merged.EndDate = input.EndDate
+	}
+
+	if err := s.validateTemplate(&merged); err != nil {
+		return nil, err
+	}
+
+	// 呼叫 repository 更新範本
+	template, err := s.repo.Update(id, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update recurring template: %w", err)
+	}
+
+	return template, nil
+}
+
+// DeleteTemplate 刪除定期收支範本
+func (s *recurringTemplateService) DeleteTemplate(id uuid.UUID) error {
+	// 驗證範本是否存在
+	_, err := s.repo.GetByID(id)
+	if err != nil {
+		return fmt.Errorf("recurring template not found: %w", err)
+	}
+
+	// 呼叫 repository 刪除範本
+	if err := s.repo.Delete(id); err != nil {
+		return fmt.Errorf("failed to delete recurring template: %w", err)
+	}
+
+	return nil
+}
+
+// GetOccurrences 預覽指定期間內的發生日期與金額
+func (s *recurringTemplateService) GetOccurrences(id uuid.UUID, from, to time.Time) ([]*models.RecurringOccurrence, error) {
+	if to.Before(from) {
+		return nil, fmt.Errorf("end date must be on or after start date")
+	}
+
+	template, err := s.GetTemplate(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return template.Occurrences(from, to), nil
+}
+
+// AddAmountChange 新增金額調整（例如調薪）
+func (s *recurringTemplateService) AddAmountChange(id uuid.UUID, input *models.CreateRecurringAmountChangeInput) (*models.RecurringAmountChange, error) {
+	// 驗證範本是否存在
+	template, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("recurring template not found: %w", err)
+	}
+
+	// 驗證金額
+	if input.Amount <= 0 {
+		return nil, fmt.Errorf("amount must be greater than zero")
+	}
+
+	// 驗證生效日期
+	if input.EffectiveDate.IsZero() {
+		return nil, fmt.Errorf("effective date is required")
+	}
+
+	if input.EffectiveDate.Before(template.StartDate) {
+		return nil, fmt.Errorf("effective date cannot be before template start date")
+	}
+
+	change, err := s.repo.AddAmountChange(id, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add amount change: %w", err)
+	}
+
+	return change, nil
+}
+
+// DeleteAmountChange 刪除金額調整
+func (s *recurringTemplateService) DeleteAmountChange(id, changeID uuid.UUID) error {
+	if err := s.repo.DeleteAmountChange(id, changeID); err != nil {
+		return fmt.Errorf("failed to delete amount change: %w", err)
+	}
+
+	return nil
+}
+
+// validateTemplate 驗證範本的類型、分類、付款方式與排程規則
+func (s *recurringTemplateService) validateTemplate(template *models.RecurringTemplate) error {
+	// 驗證現金流類型
+	if !template.Type.Validate() {
+		return fmt.Errorf("invalid cash flow type: %s", template.Type)
+	}
+
+	// 驗證分類是否存在且類型匹配
+	category, err := s.categoryRepo.GetByID(template.CategoryID)
+	if err != nil {
+		return fmt.Errorf("invalid category: %w", err)
+	}
+
+	if category.Type != template.Type {
+		return fmt.Errorf("category type (%s) does not match cash flow type (%s)", category.Type, template.Type)
+	}
+
+	// 驗證付款方式
+	if !template.PaymentMethod.Validate() {
+		return fmt.Errorf("invalid payment method: %s", template.PaymentMethod)
+	}
+
+	// 驗證帳戶 ID（當付款方式需要帳戶時）
+	if template.PaymentMethod.RequiresAccountID() && template.AccountID == nil {
+		return fmt.Errorf("account ID is required for payment method: %s", template.PaymentMethod)
+	}
+
+	// 轉帳只能透過銀行帳戶
+	isTransfer := template.Type == models.CashFlowTypeTransferIn || template.Type == models.CashFlowTypeTransferOut
+	if isTransfer && template.PaymentMethod != models.PaymentMethodBankAccount {
+		return fmt.Errorf("only bank account is allowed for transfer transactions")
+	}
+
+	// 驗證轉帳目標（僅 transfer_out）
+	if template.TargetType != nil {
+		if template.Type != models.CashFlowTypeTransferOut {
+			return fmt.Errorf("target is only allowed for transfer_out")
+		}
+
+		switch *template.TargetType {
+		case models.SourceTypeCash:
+			if template.TargetID != nil {
+				return fmt.Errorf("target_id must be null when target_type is cash")
+			}
+		case models.SourceTypeBankAccount, models.SourceTypeCreditCard:
+			if template.TargetID == nil {
+				return fmt.Errorf("target_id is required when target_type is not cash")
+			}
+		default:
+			return fmt.Errorf("invalid target type: %s", *template.TargetType)
+		}
+	}
+
+	// 驗證排程規則
+	if template.StartDate.IsZero() {
+		return fmt.Errorf("start date is required")
+	}
+
+	return template.ValidateSchedule()
+}
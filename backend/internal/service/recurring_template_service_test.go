@@ -0,0 +1,230 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chienchuanw/asset-manager/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecurringTemplateService_CreateTemplate(t *testing.T) {
+	mockRepo := new(MockRecurringTemplateRepository)
+	mockCategoryRepo := new(MockCategoryRepository)
+	service := NewRecurringTemplateService(mockRepo, mockCategoryRepo)
+
+	categoryID := uuid.New()
+	accountID := uuid.New()
+	lastDay := models.LastDayOfMonth
+	category := &models.CashFlowCategory{
+		ID:   categoryID,
+		Name: "薪資",
+		Type: models.CashFlowTypeIncome,
+	}
+
+	input := &models.CreateRecurringTemplateInput{
+		Name:                  "薪資",
+		Type:                  models.CashFlowTypeIncome,
+		CategoryID:            categoryID,
+		Amount:                60000,
+		PaymentMethod:         models.PaymentMethodBankAccount,
+		AccountID:             &accountID,
+		Frequency:             models.RecurrenceMonthly,
+		ByMonthDay:            &lastDay,
+		BusinessDayAdjustment: models.BusinessDayAdjustmentPrevious,
+		StartDate:             time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	expected := &models.RecurringTemplate{ID: uuid.New(), Name: input.Name}
+
+	// 設定 mock 期望
+	mockCategoryRepo.On("GetByID", categoryID).Return(category, nil)
+	mockRepo.On("Create", mock.MatchedBy(func(in *models.CreateRecurringTemplateInput) bool {
+		// 未指定時套用預設幣別與間隔
+		return in.Currency == models.CurrencyTWD && in.Interval == 1
+	})).Return(expected, nil)
+
+	// 執行測試
+	result, err := service.CreateTemplate(input)
+
+	// 驗證結果
+	assert.NoError(t, err)
+	assert.Equal(t, expected.ID, result.ID)
+	mockCategoryRepo.AssertExpectations(t)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestRecurringTemplateService_CreateTemplate_InvalidInput(t *testing.T) {
+	incomeCategoryID := uuid.New()
+	transferCategoryID := uuid.New()
+	accountID := uuid.New()
+
+	valid := func() *models.CreateRecurringTemplateInput {
+		return &models.CreateRecurringTemplateInput{
+			Name:          "房租收入",
+			Type:          models.CashFlowTypeIncome,
+			CategoryID:    incomeCategoryID,
+			Amount:        15000,
+			PaymentMethod: models.PaymentMethodCash,
+			Frequency:     models.RecurrenceMonthly,
+			StartDate:     time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC),
+		}
+	}
+
+	tests := []struct {
+		name        string
+		modify      func(input *models.CreateRecurringTemplateInput)
+		expectedErr string
+	}{
+		{
+			name:        "空白名稱",
+			modify:      func(input *models.CreateRecurringTemplateInput) { input.Name = "" },
+			expectedErr: "template name is required",
+		},
+		{
+			name:        "金額為零",
+			modify:      func(input *models.CreateRecurringTemplateInput) { input.Amount = 0 },
+			expectedErr: "amount must be greater than zero",
+		},
+		{
+			name:        "分類類型不符",
+			modify:      func(input *models.CreateRecurringTemplateInput) { input.Type = models.CashFlowTypeExpense },
+			expectedErr: "does not match cash flow type",
+		},
+		{
+			name: "轉帳使用現金",
+			modify: func(input *models.CreateRecurringTemplateInput) {
+				input.Type = models.CashFlowTypeTransferOut
+				input.CategoryID = transferCategoryID
+			},
+			expectedErr: "only bank account is allowed for transfer transactions",
+		},
+		{
+			name: "銀行帳戶缺少帳戶 ID",
+			modify: func(input *models.CreateRecurringTemplateInput) {
+				input.PaymentMethod = models.PaymentMethodBankAccount
+			},
+			expectedErr: "account ID is required",
+		},
+		{
+			name: "收入不可設定轉帳目標",
+			modify: func(input *models.CreateRecurringTemplateInput) {
+				targetType := models.SourceTypeBankAccount
+				input.PaymentMethod = models.PaymentMethodBankAccount
+				input.AccountID = &accountID
+				input.TargetType = &targetType
+				input.TargetID = &accountID
+			},
+			expectedErr: "target is only allowed for transfer_out",
+		},
+		{
+			name:        "無效頻率",
+			modify:      func(input *models.CreateRecurringTemplateInput) { input.Frequency = "hourly" },
+			expectedErr: "invalid frequency",
+		},
+		{
+			name:        "無效月份",
+			modify:      func(input *models.CreateRecurringTemplateInput) { input.ByMonths = []int{0} },
+			expectedErr: "by_months must be between 1 and 12",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockRecurringTemplateRepository)
+			mockCategoryRepo := new(MockCategoryRepository)
+			service := NewRecurringTemplateService(mockRepo, mockCategoryRepo)
+
+			mockCategoryRepo.On("GetByID", incomeCategoryID).Return(&models.CashFlowCategory{ID: incomeCategoryID, Type: models.CashFlowTypeIncome}, nil).Maybe()
+			mockCategoryRepo.On("GetByID", transferCategoryID).Return(&models.CashFlowCategory{ID: transferCategoryID, Type: models.CashFlowTypeTransferOut}, nil).Maybe()
+
+			input := valid()
+			tt.modify(input)
+
+			result, err := service.CreateTemplate(input)
+
+			assert.Error(t, err)
+			assert.Nil(t, result)
+			assert.Contains(t, err.Error(), tt.expectedErr)
+			mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+		})
+	}
+}
+
+func TestRecurringTemplateService_UpdateTemplate_ValidatesMergedSchedule(t *testing.T) {
+	mockRepo := new(MockRecurringTemplateRepository)
+	mockCategoryRepo := new(MockCategoryRepository)
+	service := NewRecurringTemplateService(mockRepo, mockCategoryRepo)
+
+	templateID := uuid.New()
+	categoryID := uuid.New()
+	existing := &models.RecurringTemplate{
+		ID:                    templateID,
+		Name:                  "保險費",
+		Type:                  models.CashFlowTypeExpense,
+		CategoryID:            categoryID,
+		Amount:                12000,
+		PaymentMethod:         models.PaymentMethodCash,
+		Frequency:             models.RecurrenceMonthly,
+		Interval:              6,
+		BusinessDayAdjustment: models.BusinessDayAdjustmentNone,
+		StartDate:             time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC),
+	}
+
+	// 設定 mock 期望
+	mockRepo.On("GetByID", templateID).Return(existing, nil)
+	mockCategoryRepo.On("GetByID", categoryID).Return(&models.CashFlowCategory{ID: categoryID, Type: models.CashFlowTypeExpense}, nil)
+
+	// 每月排程不可指定星期
+	weekdays := []int{1}
+	result, err := service.UpdateTemplate(templateID, &models.UpdateRecurringTemplateInput{ByWeekdays: &weekdays})
+
+	// 驗證結果
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "by_weekdays is only supported")
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestRecurringTemplateService_GetOccurrences(t *testing.T) {
+	mockRepo := new(MockRecurringTemplateRepository)
+	mockCategoryRepo := new(MockCategoryRepository)
+	service := NewRecurringTemplateService(mockRepo, mockCategoryRepo)
+
+	templateID := uuid.New()
+	template := &models.RecurringTemplate{
+		ID:                    templateID,
+		Name:                  "薪資",
+		Type:                  models.CashFlowTypeIncome,
+		Amount:                50000,
+		Currency:              models.CurrencyTWD,
+		Frequency:             models.RecurrenceMonthly,
+		Interval:              1,
+		BusinessDayAdjustment: models.BusinessDayAdjustmentNone,
+		StartDate:             time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC),
+	}
+	changes := []*models.RecurringAmountChange{
+		{TemplateID: templateID, EffectiveDate: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), Amount: 53000},
+	}
+
+	// 設定 mock 期望
+	mockRepo.On("GetByID", templateID).Return(template, nil)
+	mockRepo.On("GetAmountChanges", templateID).Return(changes, nil)
+
+	// 執行測試
+	occurrences, err := service.GetOccurrences(templateID,
+		time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC),
+	)
+
+	// 驗證結果
+	require.NoError(t, err)
+	require.Len(t, occurrences, 3)
+	assert.Equal(t, 50000.0, occurrences[0].Amount)
+	assert.Equal(t, 50000.0, occurrences[1].Amount)
+	assert.Equal(t, 53000.0, occurrences[2].Amount)
+	mockRepo.AssertExpectations(t)
+}
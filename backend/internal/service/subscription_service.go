@@ -2,6 +2,7 @@ package service
 
 import (
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/chienchuanw/asset-manager/internal/models"
@@ -19,22 +20,37 @@ type SubscriptionService interface {
 	CancelSubscription(id uuid.UUID, endDate time.Time) (*models.Subscription, error)
 	GetDueBillings(date time.Time) ([]*models.Subscription, error)
 	GetExpiringSoon(days int) ([]*models.Subscription, error)
+	AddPriceChange(id uuid.UUID, input *models.CreateSubscriptionPriceChangeInput) (*models.SubscriptionPriceChange, error)
+	GetPriceHistory(id uuid.UUID) ([]*models.SubscriptionPriceChange, error)
+	AddUsageNote(id uuid.UUID, input *models.CreateSubscriptionUsageNoteInput) (*models.SubscriptionUsageNote, error)
+	ListUsageNotes(id uuid.UUID) ([]*models.SubscriptionUsageNote, error)
+	GetCostAnalytics(months, unusedDays int) (*models.SubscriptionCostAnalytics, error)
 }
 
+const (
+	// defaultAnalyticsMonths 費用趨勢預設的月數
+	defaultAnalyticsMonths = 12
+	// defaultUnusedDays 預設超過多少天沒有使用記錄視為未使用
+	defaultUnusedDays = 90
+)
+
 // subscriptionService 訂閱業務邏輯實作
 type subscriptionService struct {
-	repo         repository.SubscriptionRepository
-	categoryRepo repository.CategoryRepository
+	repo                repository.SubscriptionRepository
+	categoryRepo        repository.CategoryRepository
+	exchangeRateService ExchangeRateService
 }
 
 // NewSubscriptionService 建立新的訂閱 service
 func NewSubscriptionService(
 	repo repository.SubscriptionRepository,
 	categoryRepo repository.CategoryRepository,
+	exchangeRateService ExchangeRateService,
 ) SubscriptionService {
 	return &subscriptionService{
-		repo:         repo,
-		categoryRepo: categoryRepo,
+		repo:                repo,
+		categoryRepo:        categoryRepo,
+		exchangeRateService: exchangeRateService,
 	}
 }
 
@@ -98,9 +114,20 @@ func (s *subscriptionService) CreateSubscription(input *models.CreateSubscriptio
 	return subscription, nil
 }
 
-// GetSubscription 取得單筆訂閱
+// GetSubscription 取得單筆訂閱（含價格調整記錄）
 func (s *subscriptionService) GetSubscription(id uuid.UUID) (*models.Subscription, error) {
-	return s.repo.GetByID(id)
+	subscription, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := s.repo.GetPriceChanges(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get price changes: %w", err)
+	}
+	subscription.PriceChanges = changes
+
+	return subscription, nil
 }
 
 // ListSubscriptions 取得訂閱列表
@@ -176,6 +203,26 @@ func (s *subscriptionService) UpdateSubscription(id uuid.UUID, input *models.Upd
 		}
 	}
 
+	// 金額變更時記錄今日生效的價格調整，保留歷史價格供扣款與分析使用；
+	// 價格記錄與訂閱更新在同一個交易中寫入，避免更新失敗時留下孤立的價格記錄
+	if input.Amount != nil && *input.Amount != existing.Amount {
+		today := time.Now().Truncate(24 * time.Hour)
+		changes, err := s.priceChangesToRecord(existing, &models.CreateSubscriptionPriceChangeInput{
+			EffectiveDate: today,
+			Amount:        *input.Amount,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		subscription, _, err := s.repo.UpdateWithPriceChanges(id, input, changes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update subscription: %w", err)
+		}
+
+		return subscription, nil
+	}
+
 	// 呼叫 repository 更新訂閱
 	subscription, err := s.repo.Update(id, input)
 	if err != nil {
@@ -249,3 +296,285 @@ func (s *subscriptionService) GetExpiringSoon(days int) ([]*models.Subscription,
 	return s.repo.GetExpiringSoon(days)
 }
 
+// AddPriceChange 新增價格調整（例如漲價），生效日不晚於今天時同步更新目前金額
+func (s *subscriptionService) AddPriceChange(id uuid.UUID, input *models.CreateSubscriptionPriceChangeInput) (*models.SubscriptionPriceChange, error) {
+	// 驗證訂閱是否存在
+	subscription, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("subscription not found: %w", err)
+	}
+
+	// 驗證金額
+	if input.Amount <= 0 {
+		return nil, fmt.Errorf("amount must be greater than zero")
+	}
+
+	// 驗證生效日期
+	if input.EffectiveDate.IsZero() {
+		return nil, fmt.Errorf("effective date is required")
+	}
+
+	if input.EffectiveDate.Before(subscription.StartDate) {
+		return nil, fmt.Errorf("effective date cannot be before subscription start date")
+	}
+
+	changes, err := s.priceChangesToRecord(subscription, input)
+	if err != nil {
+		return nil, err
+	}
+
+	// 已生效的價格調整同步為目前金額（與價格記錄在同一個交易中寫入）
+	var update *models.UpdateSubscriptionInput
+	if !input.EffectiveDate.After(time.Now()) && input.Amount != subscription.Amount {
+		amount := input.Amount
+		update = &models.UpdateSubscriptionInput{Amount: &amount}
+	}
+
+	_, saved, err := s.repo.UpdateWithPriceChanges(id, update, changes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add price change: %w", err)
+	}
+
+	return saved[len(saved)-1], nil
+}
+
+// priceChangesToRecord 產生需要寫入的價格調整記錄
+// 第一次調整時先補上開始日的原始價格，確保調整前的扣款仍以原價計算
+func (s *subscriptionService) priceChangesToRecord(subscription *models.Subscription, input *models.CreateSubscriptionPriceChangeInput) ([]*models.CreateSubscriptionPriceChangeInput, error) {
+	existing, err := s.repo.GetPriceChanges(subscription.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get price changes: %w", err)
+	}
+
+	changes := []*models.CreateSubscriptionPriceChangeInput{}
+	if len(existing) == 0 && input.EffectiveDate.After(subscription.StartDate) {
+		changes = append(changes, &models.CreateSubscriptionPriceChangeInput{
+			EffectiveDate: subscription.StartDate,
+			Amount:        subscription.Amount,
+		})
+	}
+
+	return append(changes, input), nil
+}
+
+// GetPriceHistory 取得訂閱的價格調整記錄
+func (s *subscriptionService) GetPriceHistory(id uuid.UUID) ([]*models.SubscriptionPriceChange, error) {
+	// 驗證訂閱是否存在
+	if _, err := s.repo.GetByID(id); err != nil {
+		return nil, fmt.Errorf("subscription not found: %w", err)
+	}
+
+	return s.repo.GetPriceChanges(id)
+}
+
+// AddUsageNote 新增使用記錄
+func (s *subscriptionService) AddUsageNote(id uuid.UUID, input *models.CreateSubscriptionUsageNoteInput) (*models.SubscriptionUsageNote, error) {
+	// 驗證訂閱是否存在
+	if _, err := s.repo.GetByID(id); err != nil {
+		return nil, fmt.Errorf("subscription not found: %w", err)
+	}
+
+	// 驗證使用日期
+	if input.UsedOn.IsZero() {
+		return nil, fmt.Errorf("used on date is required")
+	}
+
+	note, err := s.repo.AddUsageNote(id, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add usage note: %w", err)
+	}
+
+	return note, nil
+}
+
+// ListUsageNotes 取得訂閱的使用記錄
+func (s *subscriptionService) ListUsageNotes(id uuid.UUID) ([]*models.SubscriptionUsageNote, error) {
+	// 驗證訂閱是否存在
+	if _, err := s.repo.GetByID(id); err != nil {
+		return nil, fmt.Errorf("subscription not found: %w", err)
+	}
+
+	return s.repo.GetUsageNotes(id)
+}
+
+// GetCostAnalytics 取得訂閱年化費用分析
+// months: 費用趨勢的月數（含本月）
+// unusedDays: 超過多少天沒有使用記錄視為未使用
+func (s *subscriptionService) GetCostAnalytics(months, unusedDays int) (*models.SubscriptionCostAnalytics, error) {
+	if months == 0 {
+		months = defaultAnalyticsMonths
+	}
+	if unusedDays == 0 {
+		unusedDays = defaultUnusedDays
+	}
+
+	if months < 1 || months > 60 {
+		return nil, fmt.Errorf("months must be between 1 and 60")
+	}
+
+	if unusedDays < 1 {
+		return nil, fmt.Errorf("unused days must be greater than zero")
+	}
+
+	subscriptions, err := s.repo.List(repository.SubscriptionFilters{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list subscriptions: %w", err)
+	}
+
+	// 載入價格調整記錄
+	for _, subscription := range subscriptions {
+		changes, err := s.repo.GetPriceChanges(subscription.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get price changes: %w", err)
+		}
+		subscription.PriceChanges = changes
+	}
+
+	lastUsed, err := s.repo.GetLastUsageDates()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get last usage dates: %w", err)
+	}
+
+	today := time.Now().Truncate(24 * time.Hour)
+	analytics := &models.SubscriptionCostAnalytics{
+		AsOf:                today,
+		ByCategory:          []*models.SubscriptionCategoryCost{},
+		ByCurrency:          []*models.SubscriptionCurrencyCost{},
+		MonthlyTrend:        []*models.SubscriptionMonthlyCost{},
+		UnusedSubscriptions: []*models.UnusedSubscription{},
+	}
+
+	categoryCosts := make(map[uuid.UUID]*models.SubscriptionCategoryCost)
+	currencyCosts := make(map[models.Currency]*models.SubscriptionCurrencyCost)
+	unusedSince := today.AddDate(0, 0, -unusedDays)
+
+	for _, subscription := range subscriptions {
+		if !subscription.IsActiveOn(today) {
+			continue
+		}
+
+		annualCost := subscription.AnnualizedCostOn(today)
+		annualCostTWD, err := s.convertToTWD(annualCost, subscription.Currency, today)
+		if err != nil {
+			return nil, err
+		}
+
+		analytics.ActiveCount++
+		analytics.TotalAnnualCost += annualCostTWD
+
+		categoryCost, ok := categoryCosts[subscription.CategoryID]
+		if !ok {
+			categoryCost = &models.SubscriptionCategoryCost{CategoryID: subscription.CategoryID}
+			if subscription.Category != nil {
+				categoryCost.CategoryName = subscription.Category.Name
+			}
+			categoryCosts[subscription.CategoryID] = categoryCost
+			analytics.ByCategory = append(analytics.ByCategory, categoryCost)
+		}
+		categoryCost.Count++
+		categoryCost.AnnualCost += annualCostTWD
+
+		currencyCost, ok := currencyCosts[subscription.Currency]
+		if !ok {
+			currencyCost = &models.SubscriptionCurrencyCost{Currency: subscription.Currency}
+			currencyCosts[subscription.Currency] = currencyCost
+			analytics.ByCurrency = append(analytics.ByCurrency, currencyCost)
+		}
+		currencyCost.Count++
+		currencyCost.AnnualCost += annualCost
+		currencyCost.AnnualCostTWD += annualCostTWD
+
+		// 從未記錄使用或最近一次使用早於門檻
+		usedOn, ok := lastUsed[subscription.ID]
+		if !ok || usedOn.Before(unusedSince) {
+			unused := &models.UnusedSubscription{
+				SubscriptionID: subscription.ID,
+				Name:           subscription.Name,
+				AnnualCost:     annualCostTWD,
+			}
+			if ok {
+				usedOnCopy := usedOn
+				unused.LastUsedOn = &usedOnCopy
+			}
+			analytics.UnusedSubscriptions = append(analytics.UnusedSubscriptions, unused)
+		}
+	}
+
+	analytics.TotalMonthlyCost = analytics.TotalAnnualCost / 12
+
+	sort.SliceStable(analytics.ByCategory, func(i, j int) bool {
+		return analytics.ByCategory[i].AnnualCost > analytics.ByCategory[j].AnnualCost
+	})
+	sort.SliceStable(analytics.ByCurrency, func(i, j int) bool {
+		return analytics.ByCurrency[i].Currency < analytics.ByCurrency[j].Currency
+	})
+	sort.SliceStable(analytics.UnusedSubscriptions, func(i, j int) bool {
+		return analytics.UnusedSubscriptions[i].AnnualCost > analytics.UnusedSubscriptions[j].AnnualCost
+	})
+
+	trend, err := s.monthlyTrend(subscriptions, today, months)
+	if err != nil {
+		return nil, err
+	}
+	analytics.MonthlyTrend = trend
+
+	return analytics, nil
+}
+
+// monthlyTrend 計算每月月底（本月以今天為準）的平均每月訂閱費用與月增減
+func (s *subscriptionService) monthlyTrend(subscriptions []*models.Subscription, today time.Time, months int) ([]*models.SubscriptionMonthlyCost, error) {
+	trend := make([]*models.SubscriptionMonthlyCost, 0, months)
+	firstOfMonth := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, today.Location())
+
+	for i := months - 1; i >= 0; i-- {
+		monthStart := firstOfMonth.AddDate(0, -i, 0)
+		asOf := monthStart.AddDate(0, 1, -1)
+		if asOf.After(today) {
+			asOf = today
+		}
+
+		point := &models.SubscriptionMonthlyCost{Month: monthStart.Format("2006-01")}
+		for _, subscription := range subscriptions {
+			if !subscription.IsActiveOn(asOf) {
+				continue
+			}
+
+			monthlyCost, err := s.convertToTWD(subscription.AnnualizedCostOn(asOf)/12, subscription.Currency, asOf)
+			if err != nil {
+				return nil, err
+			}
+			point.MonthlyCost += monthlyCost
+			point.Count++
+		}
+
+		if len(trend) > 0 {
+			previous := trend[len(trend)-1]
+			point.Change = point.MonthlyCost - previous.MonthlyCost
+			if previous.MonthlyCost != 0 {
+				point.ChangePct = point.Change / previous.MonthlyCost * 100
+			}
+		}
+
+		trend = append(trend, point)
+	}
+
+	return trend, nil
+}
+
+// convertToTWD 將金額換算為 TWD
+func (s *subscriptionService) convertToTWD(amount float64, currency models.Currency, date time.Time) (float64, error) {
+	if currency == models.CurrencyTWD || currency == "" {
+		return amount, nil
+	}
+
+	if s.exchangeRateService == nil {
+		return 0, fmt.Errorf("exchange rate service is not configured for currency: %s", currency)
+	}
+
+	converted, err := s.exchangeRateService.ConvertToTWD(amount, currency, date)
+	if err != nil {
+		return 0, fmt.Errorf("failed to convert %s to TWD: %w", currency, err)
+	}
+
+	return converted, nil
+}
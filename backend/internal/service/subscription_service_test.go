@@ -1,6 +1,7 @@
 package service
 
 import (
+	"errors"
 	"testing"
 	"time"
 
@@ -9,6 +10,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // MockSubscriptionRepository 訂閱 repository 的 mock
@@ -69,11 +71,64 @@ func (m *MockSubscriptionRepository) GetExpiringSoon(days int) ([]*models.Subscr
 	return args.Get(0).([]*models.Subscription), args.Error(1)
 }
 
+func (m *MockSubscriptionRepository) AddPriceChange(subscriptionID uuid.UUID, input *models.CreateSubscriptionPriceChangeInput) (*models.SubscriptionPriceChange, error) {
+	args := m.Called(subscriptionID, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SubscriptionPriceChange), args.Error(1)
+}
+
+func (m *MockSubscriptionRepository) UpdateWithPriceChanges(id uuid.UUID, input *models.UpdateSubscriptionInput, changes []*models.CreateSubscriptionPriceChangeInput) (*models.Subscription, []*models.SubscriptionPriceChange, error) {
+	args := m.Called(id, input, changes)
+	var subscription *models.Subscription
+	if args.Get(0) != nil {
+		subscription = args.Get(0).(*models.Subscription)
+	}
+	var saved []*models.SubscriptionPriceChange
+	if args.Get(1) != nil {
+		saved = args.Get(1).([]*models.SubscriptionPriceChange)
+	}
+	return subscription, saved, args.Error(2)
+}
+
+func (m *MockSubscriptionRepository) GetPriceChanges(subscriptionID uuid.UUID) ([]*models.SubscriptionPriceChange, error) {
+	args := m.Called(subscriptionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.SubscriptionPriceChange), args.Error(1)
+}
+
+func (m *MockSubscriptionRepository) AddUsageNote(subscriptionID uuid.UUID, input *models.CreateSubscriptionUsageNoteInput) (*models.SubscriptionUsageNote, error) {
+	args := m.Called(subscriptionID, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SubscriptionUsageNote), args.Error(1)
+}
+
+func (m *MockSubscriptionRepository) GetUsageNotes(subscriptionID uuid.UUID) ([]*models.SubscriptionUsageNote, error) {
+	args := m.Called(subscriptionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.SubscriptionUsageNote), args.Error(1)
+}
+
+func (m *MockSubscriptionRepository) GetLastUsageDates() (map[uuid.UUID]time.Time, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[uuid.UUID]time.Time), args.Error(1)
+}
+
 // TestSubscriptionService_CreateSubscription 測試建立訂閱
 func TestSubscriptionService_CreateSubscription(t *testing.T) {
 	mockRepo := new(MockSubscriptionRepository)
 	mockCategoryRepo := new(MockCategoryRepository)
-	service := NewSubscriptionService(mockRepo, mockCategoryRepo)
+	service := NewSubscriptionService(mockRepo, mockCategoryRepo, nil)
 
 	categoryID := uuid.New()
 	category := &models.CashFlowCategory{
@@ -129,7 +184,7 @@ func TestSubscriptionService_CreateSubscription(t *testing.T) {
 func TestSubscriptionService_CreateSubscription_InvalidInput(t *testing.T) {
 	mockRepo := new(MockSubscriptionRepository)
 	mockCategoryRepo := new(MockCategoryRepository)
-	service := NewSubscriptionService(mockRepo, mockCategoryRepo)
+	service := NewSubscriptionService(mockRepo, mockCategoryRepo, nil)
 
 	tests := []struct {
 		name        string
@@ -213,7 +268,7 @@ func TestSubscriptionService_CreateSubscription_InvalidInput(t *testing.T) {
 func TestSubscriptionService_GetSubscription(t *testing.T) {
 	mockRepo := new(MockSubscriptionRepository)
 	mockCategoryRepo := new(MockCategoryRepository)
-	service := NewSubscriptionService(mockRepo, mockCategoryRepo)
+	service := NewSubscriptionService(mockRepo, mockCategoryRepo, nil)
 
 	subscriptionID := uuid.New()
 	expectedSubscription := &models.Subscription{
@@ -223,13 +278,248 @@ func TestSubscriptionService_GetSubscription(t *testing.T) {
 		Status: models.SubscriptionStatusActive,
 	}
 
+	priceChanges := []*models.SubscriptionPriceChange{
+		{SubscriptionID: subscriptionID, EffectiveDate: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Amount: 390},
+	}
+
 	mockRepo.On("GetByID", subscriptionID).Return(expectedSubscription, nil)
+	mockRepo.On("GetPriceChanges", subscriptionID).Return(priceChanges, nil)
 
 	result, err := service.GetSubscription(subscriptionID)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
 	assert.Equal(t, expectedSubscription.ID, result.ID)
+	assert.Len(t, result.PriceChanges, 1)
+	mockRepo.AssertExpectations(t)
+}
+
+// TestSubscriptionService_UpdateSubscription_RecordsPriceChange 測試調整金額時保留原價並記錄新價格
+func TestSubscriptionService_UpdateSubscription_RecordsPriceChange(t *testing.T) {
+	mockRepo := new(MockSubscriptionRepository)
+	mockCategoryRepo := new(MockCategoryRepository)
+	service := NewSubscriptionService(mockRepo, mockCategoryRepo, nil)
+
+	subscriptionID := uuid.New()
+	startDate := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	existing := &models.Subscription{
+		ID:        subscriptionID,
+		Name:      "Netflix",
+		Amount:    390,
+		StartDate: startDate,
+		Status:    models.SubscriptionStatusActive,
+	}
+	newAmount := 420.0
+
+	// 設定 mock 期望
+	mockRepo.On("GetByID", subscriptionID).Return(existing, nil)
+	mockRepo.On("GetPriceChanges", subscriptionID).Return([]*models.SubscriptionPriceChange{}, nil)
+	mockRepo.On("UpdateWithPriceChanges", subscriptionID, mock.AnythingOfType("*models.UpdateSubscriptionInput"), mock.MatchedBy(func(changes []*models.CreateSubscriptionPriceChangeInput) bool {
+		return len(changes) == 2 &&
+			changes[0].EffectiveDate.Equal(startDate) && changes[0].Amount == 390 &&
+			changes[1].EffectiveDate.After(startDate) && changes[1].Amount == newAmount
+	})).Return(&models.Subscription{ID: subscriptionID, Amount: newAmount}, []*models.SubscriptionPriceChange{{Amount: 390}, {Amount: newAmount}}, nil)
+
+	// 執行測試
+	result, err := service.UpdateSubscription(subscriptionID, &models.UpdateSubscriptionInput{Amount: &newAmount})
+
+	// 驗證結果
+	assert.NoError(t, err)
+	assert.Equal(t, newAmount, result.Amount)
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "AddPriceChange", mock.Anything, mock.Anything)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+// TestSubscriptionService_UpdateSubscription_PriceChangeFailure 測試寫入失敗時回傳錯誤（價格記錄與訂閱更新一併回滾）
+func TestSubscriptionService_UpdateSubscription_PriceChangeFailure(t *testing.T) {
+	mockRepo := new(MockSubscriptionRepository)
+	mockCategoryRepo := new(MockCategoryRepository)
+	service := NewSubscriptionService(mockRepo, mockCategoryRepo, nil)
+
+	subscriptionID := uuid.New()
+	existing := &models.Subscription{
+		ID:        subscriptionID,
+		Name:      "Netflix",
+		Amount:    390,
+		StartDate: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		Status:    models.SubscriptionStatusActive,
+	}
+	newAmount := 420.0
+
+	// 設定 mock 期望
+	mockRepo.On("GetByID", subscriptionID).Return(existing, nil)
+	mockRepo.On("GetPriceChanges", subscriptionID).Return([]*models.SubscriptionPriceChange{}, nil)
+	mockRepo.On("UpdateWithPriceChanges", subscriptionID, mock.AnythingOfType("*models.UpdateSubscriptionInput"), mock.Anything).Return(nil, nil, errors.New("database error"))
+
+	// 執行測試
+	result, err := service.UpdateSubscription(subscriptionID, &models.UpdateSubscriptionInput{Amount: &newAmount})
+
+	// 驗證結果
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "failed to update subscription")
+	mockRepo.AssertNotCalled(t, "AddPriceChange", mock.Anything, mock.Anything)
+}
+
+// TestSubscriptionService_AddPriceChange_FutureDate 測試未來生效的價格調整不會變更目前金額
+func TestSubscriptionService_AddPriceChange_FutureDate(t *testing.T) {
+	mockRepo := new(MockSubscriptionRepository)
+	mockCategoryRepo := new(MockCategoryRepository)
+	service := NewSubscriptionService(mockRepo, mockCategoryRepo, nil)
+
+	subscriptionID := uuid.New()
+	startDate := time.Now().AddDate(-1, 0, 0).Truncate(24 * time.Hour)
+	existing := &models.Subscription{
+		ID:        subscriptionID,
+		Name:      "Spotify",
+		Amount:    149,
+		StartDate: startDate,
+		Status:    models.SubscriptionStatusActive,
+	}
+	existingChanges := []*models.SubscriptionPriceChange{
+		{SubscriptionID: subscriptionID, EffectiveDate: startDate, Amount: 149},
+	}
+	input := &models.CreateSubscriptionPriceChangeInput{
+		EffectiveDate: time.Now().AddDate(0, 1, 0).Truncate(24 * time.Hour),
+		Amount:        169,
+	}
+
+	// 設定 mock 期望
+	mockRepo.On("GetByID", subscriptionID).Return(existing, nil)
+	mockRepo.On("GetPriceChanges", subscriptionID).Return(existingChanges, nil)
+	mockRepo.On("UpdateWithPriceChanges", subscriptionID, (*models.UpdateSubscriptionInput)(nil), []*models.CreateSubscriptionPriceChangeInput{input}).Return(nil, []*models.SubscriptionPriceChange{{Amount: 169}}, nil).Once()
+
+	// 執行測試
+	change, err := service.AddPriceChange(subscriptionID, input)
+
+	// 驗證結果
+	assert.NoError(t, err)
+	assert.Equal(t, 169.0, change.Amount)
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+// TestSubscriptionService_AddPriceChange_BeforeStartDate 測試生效日不可早於訂閱開始日
+func TestSubscriptionService_AddPriceChange_BeforeStartDate(t *testing.T) {
+	mockRepo := new(MockSubscriptionRepository)
+	mockCategoryRepo := new(MockCategoryRepository)
+	service := NewSubscriptionService(mockRepo, mockCategoryRepo, nil)
+
+	subscriptionID := uuid.New()
+	existing := &models.Subscription{
+		ID:        subscriptionID,
+		Amount:    149,
+		StartDate: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	mockRepo.On("GetByID", subscriptionID).Return(existing, nil)
+
+	change, err := service.AddPriceChange(subscriptionID, &models.CreateSubscriptionPriceChangeInput{
+		EffectiveDate: time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC),
+		Amount:        169,
+	})
+
+	assert.Error(t, err)
+	assert.Nil(t, change)
+	assert.Contains(t, err.Error(), "before subscription start date")
+	mockRepo.AssertNotCalled(t, "UpdateWithPriceChanges", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestSubscriptionService_GetCostAnalytics 測試年化費用分析、月增減與未使用訂閱
+func TestSubscriptionService_GetCostAnalytics(t *testing.T) {
+	mockRepo := new(MockSubscriptionRepository)
+	mockCategoryRepo := new(MockCategoryRepository)
+	mockExchangeRateService := new(MockExchangeRateService)
+	service := NewSubscriptionService(mockRepo, mockCategoryRepo, mockExchangeRateService)
+
+	today := time.Now().Truncate(24 * time.Hour)
+	thisMonth := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, today.Location())
+	streamingID := uuid.New()
+	softwareID := uuid.New()
+
+	// 串流訂閱：本月起由 300 調漲為 390
+	netflix := &models.Subscription{
+		ID:           uuid.New(),
+		Name:         "Netflix",
+		Amount:       390,
+		Currency:     models.CurrencyTWD,
+		BillingCycle: models.BillingCycleMonthly,
+		CategoryID:   streamingID,
+		StartDate:    thisMonth.AddDate(-1, 0, 0),
+		Status:       models.SubscriptionStatusActive,
+		Category:     &models.CashFlowCategory{ID: streamingID, Name: "串流"},
+	}
+	// 軟體訂閱：年繳 120 美金
+	github := &models.Subscription{
+		ID:           uuid.New(),
+		Name:         "GitHub",
+		Amount:       120,
+		Currency:     models.CurrencyUSD,
+		BillingCycle: models.BillingCycleYearly,
+		CategoryID:   softwareID,
+		StartDate:    thisMonth.AddDate(-1, 0, 0),
+		Status:       models.SubscriptionStatusActive,
+		Category:     &models.CashFlowCategory{ID: softwareID, Name: "軟體"},
+	}
+	// 已取消的訂閱不計入目前費用
+	endDate := thisMonth.AddDate(0, -3, 0)
+	cancelled := &models.Subscription{
+		ID:           uuid.New(),
+		Name:         "Disney+",
+		Amount:       270,
+		Currency:     models.CurrencyTWD,
+		BillingCycle: models.BillingCycleMonthly,
+		CategoryID:   streamingID,
+		StartDate:    thisMonth.AddDate(-1, 0, 0),
+		EndDate:      &endDate,
+		Status:       models.SubscriptionStatusCancelled,
+	}
+
+	// 設定 mock 期望
+	mockRepo.On("List", repository.SubscriptionFilters{}).Return([]*models.Subscription{netflix, github, cancelled}, nil)
+	mockRepo.On("GetPriceChanges", netflix.ID).Return([]*models.SubscriptionPriceChange{
+		{SubscriptionID: netflix.ID, EffectiveDate: netflix.StartDate, Amount: 300},
+		{SubscriptionID: netflix.ID, EffectiveDate: thisMonth, Amount: 390},
+	}, nil)
+	mockRepo.On("GetPriceChanges", github.ID).Return([]*models.SubscriptionPriceChange{}, nil)
+	mockRepo.On("GetPriceChanges", cancelled.ID).Return([]*models.SubscriptionPriceChange{}, nil)
+	mockRepo.On("GetLastUsageDates").Return(map[uuid.UUID]time.Time{
+		netflix.ID: today.AddDate(0, 0, -3),
+		github.ID:  today.AddDate(0, 0, -200),
+	}, nil)
+	mockExchangeRateService.On("ConvertToTWD", 120.0, models.CurrencyUSD, mock.AnythingOfType("time.Time")).Return(3600.0, nil)
+	mockExchangeRateService.On("ConvertToTWD", 10.0, models.CurrencyUSD, mock.AnythingOfType("time.Time")).Return(300.0, nil)
+
+	// 執行測試
+	analytics, err := service.GetCostAnalytics(2, 90)
+
+	// 驗證結果
+	require.NoError(t, err)
+	assert.Equal(t, 2, analytics.ActiveCount)
+	assert.InDelta(t, 390*12+120*30, analytics.TotalAnnualCost, 0.001)
+	assert.InDelta(t, (390*12+120*30)/12.0, analytics.TotalMonthlyCost, 0.001)
+
+	require.Len(t, analytics.ByCategory, 2)
+	assert.Equal(t, "串流", analytics.ByCategory[0].CategoryName)
+	assert.InDelta(t, 390*12, analytics.ByCategory[0].AnnualCost, 0.001)
+
+	require.Len(t, analytics.ByCurrency, 2)
+	assert.Equal(t, models.CurrencyTWD, analytics.ByCurrency[0].Currency)
+	assert.Equal(t, models.CurrencyUSD, analytics.ByCurrency[1].Currency)
+	assert.InDelta(t, 120, analytics.ByCurrency[1].AnnualCost, 0.001)
+	assert.InDelta(t, 3600, analytics.ByCurrency[1].AnnualCostTWD, 0.001)
+
+	require.Len(t, analytics.MonthlyTrend, 2)
+	assert.Equal(t, thisMonth.Format("2006-01"), analytics.MonthlyTrend[1].Month)
+	assert.InDelta(t, 300+10*30, analytics.MonthlyTrend[0].MonthlyCost, 0.001)
+	assert.InDelta(t, 390+10*30, analytics.MonthlyTrend[1].MonthlyCost, 0.001)
+	assert.InDelta(t, 90, analytics.MonthlyTrend[1].Change, 0.001)
+	assert.InDelta(t, 15, analytics.MonthlyTrend[1].ChangePct, 0.001)
+
+	require.Len(t, analytics.UnusedSubscriptions, 1)
+	assert.Equal(t, github.ID, analytics.UnusedSubscriptions[0].SubscriptionID)
+	require.NotNil(t, analytics.UnusedSubscriptions[0].LastUsedOn)
 	mockRepo.AssertExpectations(t)
 }
 
@@ -237,7 +527,7 @@ func TestSubscriptionService_GetSubscription(t *testing.T) {
 func TestSubscriptionService_CancelSubscription(t *testing.T) {
 	mockRepo := new(MockSubscriptionRepository)
 	mockCategoryRepo := new(MockCategoryRepository)
-	service := NewSubscriptionService(mockRepo, mockCategoryRepo)
+	service := NewSubscriptionService(mockRepo, mockCategoryRepo, nil)
 
 	subscriptionID := uuid.New()
 	endDate := time.Now()
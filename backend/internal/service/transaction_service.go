@@ -3,6 +3,8 @@ package service
 import (
 	"database/sql"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/chienchuanw/asset-manager/internal/models"
 	"github.com/chienchuanw/asset-manager/internal/repository"
@@ -13,6 +15,7 @@ import (
 type TransactionService interface {
 	CreateTransaction(input *models.CreateTransactionInput) (*models.Transaction, error)
 	CreateTransactionsBatch(inputs []*models.CreateTransactionInput) ([]*models.Transaction, error)
+	CreateSwap(input *models.CreateSwapInput) (*models.Swap, error)
 	GetTransaction(id uuid.UUID) (*models.Transaction, error)
 	ListTransactions(filters repository.TransactionFilters) ([]*models.Transaction, error)
 	UpdateTransaction(id uuid.UUID, input *models.UpdateTransactionInput) (*models.Transaction, error)
@@ -25,6 +28,12 @@ type transactionService struct {
 	realizedProfitRepo repository.RealizedProfitRepository
 	fifoCalculator     FIFOCalculator
 	exchangeRateService ExchangeRateService
+	brokerageService   BrokerageAccountService
+	optionContractRepo repository.OptionContractRepository
+
+	instrumentRepo repository.InstrumentRepository // 可為 nil（不驗證交易標的）
+
+	feeProfileService FeeProfileService // 可為 nil（僅以法定費率補上台股手續費與交易稅）
 }
 
 // NewTransactionService 建立新的交易記錄 service
@@ -33,17 +42,52 @@ func NewTransactionService(
 	realizedProfitRepo repository.RealizedProfitRepository,
 	fifoCalculator FIFOCalculator,
 	exchangeRateService ExchangeRateService,
+	brokerageService BrokerageAccountService,
+	optionContractRepo repository.OptionContractRepository,
+	instrumentRepo repository.InstrumentRepository,
+	feeProfileService FeeProfileService,
 ) TransactionService {
 	return &transactionService{
 		repo:               repo,
 		realizedProfitRepo: realizedProfitRepo,
 		fifoCalculator:     fifoCalculator,
 		exchangeRateService: exchangeRateService,
+		brokerageService:   brokerageService,
+		optionContractRepo: optionContractRepo,
+		instrumentRepo:     instrumentRepo,
+		feeProfileService:  feeProfileService,
 	}
 }
 
 // CreateTransaction 建立新的交易記錄
 func (s *transactionService) CreateTransaction(input *models.CreateTransactionInput) (*models.Transaction, error) {
+	warnings, err := s.prepareTransactionInput(input)
+	if err != nil {
+		return nil, err
+	}
+
+	// 賣出、回補與選擇權到期/履約需要在資料庫事務中同時建立交易和已實現損益；
+	// 關聯券商帳戶的交易則需在同一事務中產生交割現金異動
+	var transaction *models.Transaction
+	if input.TransactionType.IsClosing() || (input.BrokerageAccountID != nil && s.brokerageService != nil) {
+		transaction, err = s.createTransactionAtomically(input)
+	} else {
+		// 非賣出交易（買入/股息/手續費），不需要事務
+		transaction, err = s.createNonSellTransaction(input)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(warnings) > 0 {
+		transaction.Warnings = warnings
+	}
+	return transaction, nil
+}
+
+// prepareTransactionInput 驗證交易輸入，並依商品主檔與手續費設定補上標的名稱、手續費與交易稅
+// 回傳與手續費設定不符的警告
+func (s *transactionService) prepareTransactionInput(input *models.CreateTransactionInput) ([]*models.Warning, error) {
 	// 驗證資產類型
 	if !input.AssetType.Validate() {
 		return nil, fmt.Errorf("invalid asset type: %s", input.AssetType)
@@ -78,13 +122,27 @@ func (s *transactionService) CreateTransaction(input *models.CreateTransactionIn
 		return nil, fmt.Errorf("invalid currency: %s", input.Currency)
 	}
 
-	// 賣出交易需要在資料庫事務中同時建立交易和已實現損益
-	if input.TransactionType == models.TransactionTypeSell {
-		return s.createSellTransactionAtomically(input)
+	// 驗證加密貨幣保管帳戶與轉移欄位
+	if err := validateCustodyFields(input); err != nil {
+		return nil, err
+	}
+
+	// 驗證交易標的存在於商品主檔，並依手續費設定補上未填寫的手續費與交易稅
+	instrument, err := s.resolveInstrument(input.AssetType, &input.Symbol, &input.Name)
+	if err != nil {
+		return nil, err
+	}
+	warnings, err := s.applyTradingCosts(input, instrument)
+	if err != nil {
+		return nil, err
+	}
+
+	// 驗證放空、回補與選擇權欄位
+	if err := s.validateShortAndOptionFields(input); err != nil {
+		return nil, err
 	}
 
-	// 非賣出交易（買入/股息/手續費），不需要事務
-	return s.createNonSellTransaction(input)
+	return warnings, nil
 }
 
 // CreateTransactionsBatch 批次建立交易記錄（全有或全無）
@@ -132,6 +190,105 @@ func (s *transactionService) CreateTransactionsBatch(inputs []*models.CreateTran
 	return transactions, nil
 }
 
+// CreateSwap 建立加密貨幣互換（例如 BTC→ETH、ETH→USDT）
+// 在同一個資料庫事務中建立處分端的賣出（以成交市值計算 FIFO 已實現損益）與取得端的買入（以成交市值作為成本）
+func (s *transactionService) CreateSwap(input *models.CreateSwapInput) (*models.Swap, error) {
+	if input.FromSymbol == input.ToSymbol {
+		return nil, fmt.Errorf("cannot swap %s for itself", input.FromSymbol)
+	}
+
+	if input.FromQuantity <= 0 || input.ToQuantity <= 0 {
+		return nil, fmt.Errorf("swap quantities must be greater than zero")
+	}
+
+	if !input.Currency.Validate() {
+		return nil, fmt.Errorf("invalid currency: %s", input.Currency)
+	}
+
+	if input.Fee != nil && *input.Fee < 0 {
+		return nil, fmt.Errorf("fee must be non-negative")
+	}
+
+	value, err := input.MarketValue()
+	if err != nil {
+		return nil, err
+	}
+
+	swapID := uuid.New()
+	disposalInput := &models.CreateTransactionInput{
+		Date:             input.Date,
+		AssetType:        models.AssetTypeCrypto,
+		Symbol:           input.FromSymbol,
+		Name:             input.FromName,
+		TransactionType:  models.TransactionTypeSell,
+		Quantity:         input.FromQuantity,
+		Price:            value / input.FromQuantity,
+		Amount:           value,
+		Fee:              input.Fee,
+		Currency:         input.Currency,
+		Note:             input.Note,
+		CustodyAccountID: input.CustodyAccountID,
+		SwapID:           &swapID,
+	}
+	acquisitionInput := &models.CreateTransactionInput{
+		Date:             input.Date,
+		AssetType:        models.AssetTypeCrypto,
+		Symbol:           input.ToSymbol,
+		Name:             input.ToName,
+		TransactionType:  models.TransactionTypeBuy,
+		Quantity:         input.ToQuantity,
+		Price:            value / input.ToQuantity,
+		Amount:           value,
+		Currency:         input.Currency,
+		Note:             input.Note,
+		CustodyAccountID: input.CustodyAccountID,
+		SwapID:           &swapID,
+	}
+
+	// 互換兩端與一般交易相同地驗證保管帳戶、商品主檔並補上交易成本
+	disposalWarnings, err := s.prepareTransactionInput(disposalInput)
+	if err != nil {
+		return nil, fmt.Errorf("invalid disposal of %s: %w", input.FromSymbol, err)
+	}
+	acquisitionWarnings, err := s.prepareTransactionInput(acquisitionInput)
+	if err != nil {
+		return nil, fmt.Errorf("invalid acquisition of %s: %w", input.ToSymbol, err)
+	}
+
+	dbTx, err := s.repo.DB().Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer dbTx.Rollback()
+
+	disposal, err := s.createTransactionTx(dbTx, disposalInput)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create disposal of %s: %w", input.FromSymbol, err)
+	}
+
+	acquisition, err := s.createTransactionTx(dbTx, acquisitionInput)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create acquisition of %s: %w", input.ToSymbol, err)
+	}
+
+	if err := dbTx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	if len(disposalWarnings) > 0 {
+		disposal.Warnings = disposalWarnings
+	}
+	if len(acquisitionWarnings) > 0 {
+		acquisition.Warnings = acquisitionWarnings
+	}
+
+	return &models.Swap{
+		SwapID:      swapID,
+		Disposal:    disposal,
+		Acquisition: acquisition,
+	}, nil
+}
+
 // GetTransaction 取得單筆交易記錄
 func (s *transactionService) GetTransaction(id uuid.UUID) (*models.Transaction, error) {
 	return s.repo.GetByID(id)
@@ -177,14 +334,90 @@ func (s *transactionService) UpdateTransaction(id uuid.UUID, input *models.Updat
 		return nil, fmt.Errorf("fee must be non-negative")
 	}
 
-	return s.repo.Update(id, input)
+	// 變更標的或資產類型時，驗證更新後的標的存在於商品主檔
+	if s.instrumentRepo != nil && (input.Symbol != nil || input.AssetType != nil) {
+		existing, err := s.repo.GetByID(id)
+		if err != nil {
+			return nil, err
+		}
+
+		symbol, assetType := existing.Symbol, existing.AssetType
+		if input.Symbol != nil {
+			symbol = *input.Symbol
+		}
+		if input.AssetType != nil {
+			assetType = *input.AssetType
+		}
+
+		if _, err := s.resolveInstrument(assetType, &symbol, nil); err != nil {
+			return nil, err
+		}
+		input.Symbol = &symbol
+	}
+
+	transaction, err := s.repo.Update(id, input)
+	if err != nil {
+		return nil, err
+	}
+
+	// 依更新後的內容重新產生交割現金異動
+	if s.brokerageService != nil {
+		if err := s.brokerageService.ResettleTransaction(transaction); err != nil {
+			return nil, fmt.Errorf("failed to resettle transaction: %w", err)
+		}
+	}
+
+	return transaction, nil
 }
 
 // DeleteTransaction 刪除交易記錄
 func (s *transactionService) DeleteTransaction(id uuid.UUID) error {
+	// 先撤銷交割現金異動，回復銀行帳戶或券商現金餘額
+	if s.brokerageService != nil {
+		if err := s.brokerageService.ReverseTransaction(id); err != nil {
+			return fmt.Errorf("failed to reverse settlement: %w", err)
+		}
+	}
+
 	return s.repo.Delete(id)
 }
 
+// resolveInstrument 驗證交易標的存在於商品主檔（該資產類型尚未匯入時略過），統一代碼大小寫並補上未填寫的名稱
+// 回傳對應的商品（未匯入時為 nil）
+func (s *transactionService) resolveInstrument(assetType models.AssetType, symbol *string, name *string) (*models.Instrument, error) {
+	instrument, err := lookupInstrument(s.instrumentRepo, *symbol, assetType)
+	if err != nil {
+		return nil, err
+	}
+
+	if instrument != nil {
+		*symbol = instrument.Symbol
+		if name != nil && strings.TrimSpace(*name) == "" {
+			*name = instrument.DisplayName()
+		}
+	}
+
+	if name != nil && strings.TrimSpace(*name) == "" {
+		return nil, fmt.Errorf("name is required for %s", *symbol)
+	}
+	return instrument, nil
+}
+
+// applyTradingCosts 依手續費設定補上未填寫的手續費與交易稅，回傳與設定不符的警告
+// 未設定手續費設定 service 時，僅以法定費率補上商品主檔中台股的手續費與交易稅
+func (s *transactionService) applyTradingCosts(input *models.CreateTransactionInput, instrument *models.Instrument) ([]*models.Warning, error) {
+	if s.feeProfileService == nil {
+		applyTWTradingCostDefaults(input, instrument)
+		return nil, nil
+	}
+
+	warnings, err := s.feeProfileService.ApplyTradingCosts(input, instrument)
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply fee profile: %w", err)
+	}
+	return warnings, nil
+}
+
 // createNonSellTransaction 建立非賣出交易（買入/股息/手續費）
 func (s *transactionService) createNonSellTransaction(input *models.CreateTransactionInput) (*models.Transaction, error) {
 	if input.Currency == models.CurrencyUSD {
@@ -210,16 +443,30 @@ func (s *transactionService) createNonSellTransaction(input *models.CreateTransa
 	return s.repo.Create(input)
 }
 
-// createSellTransactionAtomically 在資料庫事務中建立賣出交易和已實現損益
-func (s *transactionService) createSellTransactionAtomically(input *models.CreateTransactionInput) (*models.Transaction, error) {
+// createTransactionAtomically 在資料庫事務中建立交易，並一併建立已實現損益（賣出）與交割現金異動（券商帳戶）
+func (s *transactionService) createTransactionAtomically(input *models.CreateTransactionInput) (*models.Transaction, error) {
 	dbTx, err := s.repo.DB().Begin()
 	if err != nil {
 		return nil, fmt.Errorf("failed to begin transaction: %w", err)
 	}
 	defer dbTx.Rollback()
 
-	// 在事務中建立交易記錄
+	transaction, err := s.createTransactionTx(dbTx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := dbTx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return transaction, nil
+}
+
+// createTransactionTx 在指定的資料庫事務中建立交易記錄、已實現損益（賣出）與交割現金異動（券商帳戶）
+func (s *transactionService) createTransactionTx(dbTx *sql.Tx, input *models.CreateTransactionInput) (*models.Transaction, error) {
 	var transaction *models.Transaction
+	var err error
 	if input.Currency == models.CurrencyUSD {
 		rate, err := s.exchangeRateService.GetRate(models.CurrencyUSD, models.CurrencyTWD, input.Date)
 		if err != nil {
@@ -236,7 +483,7 @@ func (s *transactionService) createSellTransactionAtomically(input *models.Creat
 			return nil, err
 		}
 
-		fmt.Printf("Created USD sell transaction with exchange rate %.4f (ID: %d)\n", rate, exchangeRate.ID)
+		fmt.Printf("Created USD %s transaction with exchange rate %.4f (ID: %d)\n", transaction.TransactionType, rate, exchangeRate.ID)
 	} else {
 		transaction, err = s.repo.CreateTx(dbTx, input)
 		if err != nil {
@@ -245,12 +492,30 @@ func (s *transactionService) createSellTransactionAtomically(input *models.Creat
 	}
 
 	// 在同一事務中建立已實現損益
-	if err := s.createRealizedProfitTx(dbTx, transaction); err != nil {
-		return nil, fmt.Errorf("failed to create realized profit: %w", err)
+	switch transaction.TransactionType {
+	case models.TransactionTypeSell:
+		if err := s.createRealizedProfitTx(dbTx, transaction); err != nil {
+			return nil, fmt.Errorf("failed to create realized profit: %w", err)
+		}
+	case models.TransactionTypeCover, models.TransactionTypeExpiration, models.TransactionTypeAssignment:
+		closing, err := s.createClosingRealizedProfitTx(dbTx, transaction)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create realized profit: %w", err)
+		}
+
+		// 履約時一併以履約價建立標的股票交易
+		if transaction.TransactionType == models.TransactionTypeAssignment {
+			if _, err := s.createAssignmentLegTx(dbTx, transaction, closing.IsShort); err != nil {
+				return nil, fmt.Errorf("failed to create assignment of underlying: %w", err)
+			}
+		}
 	}
 
-	if err := dbTx.Commit(); err != nil {
-		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	// 在同一事務中產生交割現金異動
+	if s.brokerageService != nil {
+		if err := s.brokerageService.SettleTransactionTx(dbTx, transaction); err != nil {
+			return nil, fmt.Errorf("failed to settle transaction: %w", err)
+		}
 	}
 
 	return transaction, nil
@@ -305,3 +570,185 @@ func (s *transactionService) createRealizedProfitTx(dbTx *sql.Tx, sellTransactio
 	return nil
 }
 
+// createClosingRealizedProfitTx 在指定的資料庫事務中建立回補、選擇權到期或履約的已實現損益記錄
+// 多頭部位以了結金額扣除 FIFO 成本；空頭部位以 FIFO 放空所得扣除回補金額與手續費，金額皆換算為 TWD
+func (s *transactionService) createClosingRealizedProfitTx(dbTx *sql.Tx, closingTransaction *models.Transaction) (*ClosingBasis, error) {
+	filters := repository.TransactionFilters{
+		Symbol: &closingTransaction.Symbol,
+	}
+	allTransactions, err := s.repo.GetAll(filters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions for symbol %s: %w", closingTransaction.Symbol, err)
+	}
+
+	closing, err := s.fifoCalculator.CalculateClosingBasis(closingTransaction.Symbol, closingTransaction, allTransactions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate closing basis: %w", err)
+	}
+
+	fee := 0.0
+	if closingTransaction.Fee != nil {
+		fee = *closingTransaction.Fee
+	}
+
+	amountTWD, err := s.convertToTWD(closingTransaction.Amount, closingTransaction.Currency, closingTransaction.Date)
+	if err != nil {
+		return nil, err
+	}
+	feeTWD, err := s.convertToTWD(fee, closingTransaction.Currency, closingTransaction.Date)
+	if err != nil {
+		return nil, err
+	}
+
+	input := &models.CreateRealizedProfitInput{
+		TransactionID: closingTransaction.ID.String(),
+		Symbol:        closingTransaction.Symbol,
+		AssetType:     closingTransaction.AssetType,
+		SellDate:      closingTransaction.Date,
+		Quantity:      closingTransaction.Quantity,
+		SellPrice:     closingTransaction.Price,
+		SellAmount:    amountTWD,
+		SellFee:       feeTWD,
+		CostBasis:     closing.Basis,
+		Currency:      string(models.CurrencyTWD),
+	}
+	if closing.IsShort {
+		// 空頭部位：放空所得視為出售金額，回補支出（含手續費）視為成本
+		input.SellAmount = closing.Basis
+		input.SellFee = 0
+		input.CostBasis = amountTWD + feeTWD
+	}
+
+	if _, err := s.realizedProfitRepo.CreateTx(dbTx, input); err != nil {
+		return nil, fmt.Errorf("failed to create realized profit record: %w", err)
+	}
+
+	return closing, nil
+}
+
+// createAssignmentLegTx 在指定的資料庫事務中以履約價建立選擇權履約的標的股票交易
+func (s *transactionService) createAssignmentLegTx(dbTx *sql.Tx, assignment *models.Transaction, shortPosition bool) (*models.Transaction, error) {
+	if s.optionContractRepo == nil {
+		return nil, fmt.Errorf("option contract repository is not configured")
+	}
+
+	contract, err := s.optionContractRepo.GetBySymbol(assignment.Symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	quantity := assignment.Quantity * contract.Multiplier
+	note := fmt.Sprintf("選擇權履約：%s", assignment.Symbol)
+	legInput := &models.CreateTransactionInput{
+		Date:               assignment.Date,
+		AssetType:          models.AssetTypeUSStock,
+		Symbol:             contract.UnderlyingSymbol,
+		Name:               contract.UnderlyingSymbol,
+		TransactionType:    contract.AssignmentTransactionType(shortPosition),
+		Quantity:           quantity,
+		Price:              contract.StrikePrice,
+		Amount:             quantity * contract.StrikePrice,
+		Currency:           assignment.Currency,
+		Note:               &note,
+		BrokerageAccountID: assignment.BrokerageAccountID,
+	}
+
+	return s.createTransactionTx(dbTx, legInput)
+}
+
+// convertToTWD 將金額換算為 TWD（USD 以交易日匯率換算）
+func (s *transactionService) convertToTWD(amount float64, currency models.Currency, date time.Time) (float64, error) {
+	if currency != models.CurrencyUSD || amount == 0 {
+		return amount, nil
+	}
+
+	converted, err := s.exchangeRateService.ConvertToTWD(amount, currency, date)
+	if err != nil {
+		return 0, fmt.Errorf("failed to convert %s to TWD: %w", currency, err)
+	}
+	return converted, nil
+}
+
+// validateShortAndOptionFields 驗證放空、回補與選擇權相關欄位
+func (s *transactionService) validateShortAndOptionFields(input *models.CreateTransactionInput) error {
+	switch input.TransactionType {
+	case models.TransactionTypeShort, models.TransactionTypeCover:
+		switch input.AssetType {
+		case models.AssetTypeTWStock, models.AssetTypeUSStock, models.AssetTypeUSOption:
+		default:
+			return fmt.Errorf("%s transactions are only allowed for stocks and options", input.TransactionType)
+		}
+		if input.Quantity <= 0 {
+			return fmt.Errorf("%s quantity must be greater than zero", input.TransactionType)
+		}
+	case models.TransactionTypeExpiration, models.TransactionTypeAssignment:
+		if input.AssetType != models.AssetTypeUSOption {
+			return fmt.Errorf("%s transactions are only allowed for options", input.TransactionType)
+		}
+		if input.Quantity <= 0 {
+			return fmt.Errorf("%s quantity must be greater than zero", input.TransactionType)
+		}
+	}
+
+	if input.AssetType != models.AssetTypeUSOption {
+		return nil
+	}
+
+	if input.Currency != models.CurrencyUSD {
+		return fmt.Errorf("option transactions must be in USD")
+	}
+
+	// 選擇權需先建立合約規格（履約價、到期日、乘數）
+	if s.optionContractRepo == nil {
+		return fmt.Errorf("option contract repository is not configured")
+	}
+	contract, err := s.optionContractRepo.GetBySymbol(input.Symbol)
+	if err != nil {
+		return err
+	}
+
+	if input.TransactionType == models.TransactionTypeExpiration && input.Date.Before(contract.ExpiryDate) {
+		return fmt.Errorf("option %s cannot expire before %s", input.Symbol, contract.ExpiryDate.Format("2006-01-02"))
+	}
+
+	return nil
+}
+
+// validateCustodyFields 驗證加密貨幣保管帳戶、內部轉移與質押/空投收入的欄位
+func validateCustodyFields(input *models.CreateTransactionInput) error {
+	if input.TransactionType.IsCryptoOnly() && input.AssetType != models.AssetTypeCrypto {
+		return fmt.Errorf("%s transactions are only allowed for crypto", input.TransactionType)
+	}
+
+	if (input.CustodyAccountID != nil || input.ToCustodyAccountID != nil) && input.AssetType != models.AssetTypeCrypto {
+		return fmt.Errorf("custody accounts are only allowed for crypto")
+	}
+
+	if input.TransactionType != models.TransactionTypeTransfer {
+		if input.ToCustodyAccountID != nil || input.NetworkFee != nil {
+			return fmt.Errorf("to_custody_account_id and network_fee are only allowed for transfer transactions")
+		}
+	}
+
+	switch input.TransactionType {
+	case models.TransactionTypeTransfer:
+		if input.CustodyAccountID == nil || input.ToCustodyAccountID == nil {
+			return fmt.Errorf("source and destination custody accounts are required for transfer transactions")
+		}
+		if *input.CustodyAccountID == *input.ToCustodyAccountID {
+			return fmt.Errorf("source and destination custody accounts must be different")
+		}
+		if input.Quantity <= 0 {
+			return fmt.Errorf("transfer quantity must be greater than zero")
+		}
+		if input.NetworkFee != nil && (*input.NetworkFee < 0 || *input.NetworkFee >= input.Quantity) {
+			return fmt.Errorf("network fee must be non-negative and less than the transfer quantity")
+		}
+	case models.TransactionTypeStaking, models.TransactionTypeAirdrop:
+		if input.Quantity <= 0 {
+			return fmt.Errorf("%s quantity must be greater than zero", input.TransactionType)
+		}
+	}
+
+	return nil
+}
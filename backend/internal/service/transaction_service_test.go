@@ -158,6 +158,14 @@ func (m *MockFIFOCalculator) CalculateCostBasis(symbol string, sellTransaction *
 	return args.Get(0).(float64), args.Error(1)
 }
 
+func (m *MockFIFOCalculator) CalculateClosingBasis(symbol string, closingTransaction *models.Transaction, allTransactions []*models.Transaction) (*ClosingBasis, error) {
+	args := m.Called(symbol, closingTransaction, allTransactions)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ClosingBasis), args.Error(1)
+}
+
 // TestCreateTransaction_Success 測試成功建立買入交易記錄
 func TestCreateTransaction_Success(t *testing.T) {
 	// Arrange
@@ -165,7 +173,7 @@ func TestCreateTransaction_Success(t *testing.T) {
 	mockRealizedProfitRepo := new(MockRealizedProfitRepository)
 	mockFIFOCalc := new(MockFIFOCalculator)
 	mockExchangeRateService := new(MockExchangeRateService)
-	service := NewTransactionService(mockRepo, mockRealizedProfitRepo, mockFIFOCalc, mockExchangeRateService)
+	service := NewTransactionService(mockRepo, mockRealizedProfitRepo, mockFIFOCalc, mockExchangeRateService, nil, nil, nil, nil)
 
 	fee := 28.0
 	input := &models.CreateTransactionInput{
@@ -220,7 +228,7 @@ func TestCreateTransaction_InvalidAssetType(t *testing.T) {
 	mockRealizedProfitRepo := new(MockRealizedProfitRepository)
 	mockFIFOCalc := new(MockFIFOCalculator)
 	mockExchangeRateService := new(MockExchangeRateService)
-	service := NewTransactionService(mockRepo, mockRealizedProfitRepo, mockFIFOCalc, mockExchangeRateService)
+	service := NewTransactionService(mockRepo, mockRealizedProfitRepo, mockFIFOCalc, mockExchangeRateService, nil, nil, nil, nil)
 
 	input := &models.CreateTransactionInput{
 		Date:            time.Date(2025, 10, 22, 0, 0, 0, 0, time.UTC),
@@ -250,7 +258,7 @@ func TestCreateTransaction_InvalidTransactionType(t *testing.T) {
 	mockRealizedProfitRepo := new(MockRealizedProfitRepository)
 	mockFIFOCalc := new(MockFIFOCalculator)
 	mockExchangeRateService := new(MockExchangeRateService)
-	service := NewTransactionService(mockRepo, mockRealizedProfitRepo, mockFIFOCalc, mockExchangeRateService)
+	service := NewTransactionService(mockRepo, mockRealizedProfitRepo, mockFIFOCalc, mockExchangeRateService, nil, nil, nil, nil)
 
 	input := &models.CreateTransactionInput{
 		Date:            time.Date(2025, 10, 22, 0, 0, 0, 0, time.UTC),
@@ -280,7 +288,7 @@ func TestCreateTransaction_NegativeQuantity(t *testing.T) {
 	mockRealizedProfitRepo := new(MockRealizedProfitRepository)
 	mockFIFOCalc := new(MockFIFOCalculator)
 	mockExchangeRateService := new(MockExchangeRateService)
-	service := NewTransactionService(mockRepo, mockRealizedProfitRepo, mockFIFOCalc, mockExchangeRateService)
+	service := NewTransactionService(mockRepo, mockRealizedProfitRepo, mockFIFOCalc, mockExchangeRateService, nil, nil, nil, nil)
 
 	input := &models.CreateTransactionInput{
 		Date:            time.Date(2025, 10, 22, 0, 0, 0, 0, time.UTC),
@@ -310,7 +318,7 @@ func TestGetTransaction_Success(t *testing.T) {
 	mockRealizedProfitRepo := new(MockRealizedProfitRepository)
 	mockFIFOCalc := new(MockFIFOCalculator)
 	mockExchangeRateService := new(MockExchangeRateService)
-	service := NewTransactionService(mockRepo, mockRealizedProfitRepo, mockFIFOCalc, mockExchangeRateService)
+	service := NewTransactionService(mockRepo, mockRealizedProfitRepo, mockFIFOCalc, mockExchangeRateService, nil, nil, nil, nil)
 
 	transactionID := uuid.New()
 	expectedTransaction := &models.Transaction{
@@ -344,7 +352,7 @@ func TestGetTransaction_NotFound(t *testing.T) {
 	mockRealizedProfitRepo := new(MockRealizedProfitRepository)
 	mockFIFOCalc := new(MockFIFOCalculator)
 	mockExchangeRateService := new(MockExchangeRateService)
-	service := NewTransactionService(mockRepo, mockRealizedProfitRepo, mockFIFOCalc, mockExchangeRateService)
+	service := NewTransactionService(mockRepo, mockRealizedProfitRepo, mockFIFOCalc, mockExchangeRateService, nil, nil, nil, nil)
 
 	transactionID := uuid.New()
 	mockRepo.On("GetByID", transactionID).Return(nil, fmt.Errorf("transaction not found"))
@@ -365,7 +373,7 @@ func TestListTransactions_Success(t *testing.T) {
 	mockRealizedProfitRepo := new(MockRealizedProfitRepository)
 	mockFIFOCalc := new(MockFIFOCalculator)
 	mockExchangeRateService := new(MockExchangeRateService)
-	service := NewTransactionService(mockRepo, mockRealizedProfitRepo, mockFIFOCalc, mockExchangeRateService)
+	service := NewTransactionService(mockRepo, mockRealizedProfitRepo, mockFIFOCalc, mockExchangeRateService, nil, nil, nil, nil)
 
 	filters := repository.TransactionFilters{}
 	expectedTransactions := []*models.Transaction{
@@ -402,7 +410,7 @@ func TestDeleteTransaction_Success(t *testing.T) {
 	mockRealizedProfitRepo := new(MockRealizedProfitRepository)
 	mockFIFOCalc := new(MockFIFOCalculator)
 	mockExchangeRateService := new(MockExchangeRateService)
-	service := NewTransactionService(mockRepo, mockRealizedProfitRepo, mockFIFOCalc, mockExchangeRateService)
+	service := NewTransactionService(mockRepo, mockRealizedProfitRepo, mockFIFOCalc, mockExchangeRateService, nil, nil, nil, nil)
 
 	transactionID := uuid.New()
 	mockRepo.On("Delete", transactionID).Return(nil)
@@ -426,7 +434,7 @@ func TestCreateTransaction_SellWithRealizedProfit(t *testing.T) {
 	mockRealizedProfitRepo := new(MockRealizedProfitRepository)
 	mockFIFOCalc := new(MockFIFOCalculator)
 	mockExchangeRateService := new(MockExchangeRateService)
-	service := NewTransactionService(mockRepo, mockRealizedProfitRepo, mockFIFOCalc, mockExchangeRateService)
+	service := NewTransactionService(mockRepo, mockRealizedProfitRepo, mockFIFOCalc, mockExchangeRateService, nil, nil, nil, nil)
 
 	fee := 28.0
 	sellInput := &models.CreateTransactionInput{
@@ -522,7 +530,7 @@ func TestCreateTransaction_SellRollbackOnRealizedProfitError(t *testing.T) {
 	mockRealizedProfitRepo := new(MockRealizedProfitRepository)
 	mockFIFOCalc := new(MockFIFOCalculator)
 	mockExchangeRateService := new(MockExchangeRateService)
-	service := NewTransactionService(mockRepo, mockRealizedProfitRepo, mockFIFOCalc, mockExchangeRateService)
+	service := NewTransactionService(mockRepo, mockRealizedProfitRepo, mockFIFOCalc, mockExchangeRateService, nil, nil, nil, nil)
 
 	fee := 28.0
 	sellInput := &models.CreateTransactionInput{
@@ -601,7 +609,7 @@ func TestCreateTransaction_BuyDoesNotUseDBTransaction(t *testing.T) {
 	mockRealizedProfitRepo := new(MockRealizedProfitRepository)
 	mockFIFOCalc := new(MockFIFOCalculator)
 	mockExchangeRateService := new(MockExchangeRateService)
-	service := NewTransactionService(mockRepo, mockRealizedProfitRepo, mockFIFOCalc, mockExchangeRateService)
+	service := NewTransactionService(mockRepo, mockRealizedProfitRepo, mockFIFOCalc, mockExchangeRateService, nil, nil, nil, nil)
 
 	fee := 28.0
 	input := &models.CreateTransactionInput{
@@ -653,7 +661,7 @@ func TestCreateTransaction_USD_Success(t *testing.T) {
 	mockRealizedProfitRepo := new(MockRealizedProfitRepository)
 	mockFIFOCalc := new(MockFIFOCalculator)
 	mockExchangeRateService := new(MockExchangeRateService)
-	service := NewTransactionService(mockRepo, mockRealizedProfitRepo, mockFIFOCalc, mockExchangeRateService)
+	service := NewTransactionService(mockRepo, mockRealizedProfitRepo, mockFIFOCalc, mockExchangeRateService, nil, nil, nil, nil)
 
 	fee := 5.0
 	transactionDate := time.Date(2025, 10, 22, 0, 0, 0, 0, time.UTC)
@@ -725,7 +733,7 @@ func TestCreateTransaction_USD_ExchangeRateError(t *testing.T) {
 	mockRealizedProfitRepo := new(MockRealizedProfitRepository)
 	mockFIFOCalc := new(MockFIFOCalculator)
 	mockExchangeRateService := new(MockExchangeRateService)
-	service := NewTransactionService(mockRepo, mockRealizedProfitRepo, mockFIFOCalc, mockExchangeRateService)
+	service := NewTransactionService(mockRepo, mockRealizedProfitRepo, mockFIFOCalc, mockExchangeRateService, nil, nil, nil, nil)
 
 	transactionDate := time.Date(2025, 10, 22, 0, 0, 0, 0, time.UTC)
 	input := &models.CreateTransactionInput{
@@ -755,3 +763,228 @@ func TestCreateTransaction_USD_ExchangeRateError(t *testing.T) {
 	mockRepo.AssertNotCalled(t, "CreateWithExchangeRate")
 }
 
+
+// TestCreateSwap_StablecoinValue 測試以穩定幣計價的互換：處分端計算已實現損益，取得端以成交市值為成本
+func TestCreateSwap_StablecoinValue(t *testing.T) {
+	// Arrange
+	db, dbMock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mockRepo := new(MockTransactionRepository)
+	mockRealizedProfitRepo := new(MockRealizedProfitRepository)
+	mockFIFOCalc := new(MockFIFOCalculator)
+	mockExchangeRateService := new(MockExchangeRateService)
+	service := NewTransactionService(mockRepo, mockRealizedProfitRepo, mockFIFOCalc, mockExchangeRateService, nil, nil, nil, nil)
+
+	swapDate := time.Date(2025, 10, 24, 0, 0, 0, 0, time.UTC)
+	input := &models.CreateSwapInput{
+		Date:         swapDate,
+		FromSymbol:   "ETH",
+		FromName:     "Ethereum",
+		FromQuantity: 1,
+		ToSymbol:     "USDT",
+		ToName:       "Tether",
+		ToQuantity:   3000,
+		Currency:     models.CurrencyUSD,
+		Fee:          ptrFloat64(3),
+	}
+
+	disposal := &models.Transaction{ID: uuid.New(), Date: swapDate, Symbol: "ETH", TransactionType: models.TransactionTypeSell, Quantity: 1, Amount: 3000, Fee: ptrFloat64(3), Currency: models.CurrencyUSD}
+	acquisition := &models.Transaction{ID: uuid.New(), Date: swapDate, Symbol: "USDT", TransactionType: models.TransactionTypeBuy, Quantity: 3000, Amount: 3000, Currency: models.CurrencyUSD}
+	previousTransactions := []*models.Transaction{
+		{Date: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Symbol: "ETH", TransactionType: models.TransactionTypeBuy, Quantity: 1, Amount: 2000, Currency: models.CurrencyUSD},
+	}
+
+	dbMock.ExpectBegin()
+	dbMock.ExpectCommit()
+	mockRepo.On("DB").Return(db)
+	mockExchangeRateService.On("GetRate", models.CurrencyUSD, models.CurrencyTWD, swapDate).Return(31.0, nil)
+	mockExchangeRateService.On("GetRateRecord", models.CurrencyUSD, models.CurrencyTWD, swapDate).Return(&models.ExchangeRate{ID: 7, Rate: 31.0}, nil)
+
+	// 兩筆交易共用同一個 swap_id，價格由成交市值推算
+	var disposalSwapID uuid.UUID
+	mockRepo.On("CreateWithExchangeRateTx", mock.AnythingOfType("*sql.Tx"), mock.MatchedBy(func(in *models.CreateTransactionInput) bool {
+		if in.TransactionType != models.TransactionTypeSell {
+			return false
+		}
+		disposalSwapID = *in.SwapID
+		return in.Symbol == "ETH" && in.AssetType == models.AssetTypeCrypto && in.Amount == 3000 && in.Price == 3000 && *in.Fee == 3
+	}), 7).Return(disposal, nil)
+	mockRepo.On("CreateWithExchangeRateTx", mock.AnythingOfType("*sql.Tx"), mock.MatchedBy(func(in *models.CreateTransactionInput) bool {
+		return in.TransactionType == models.TransactionTypeBuy && in.Symbol == "USDT" &&
+			in.Amount == 3000 && in.Price == 1 && in.Fee == nil && *in.SwapID == disposalSwapID
+	}), 7).Return(acquisition, nil)
+
+	ethSymbol := "ETH"
+	mockRepo.On("GetAll", repository.TransactionFilters{Symbol: &ethSymbol}).Return(previousTransactions, nil)
+	mockFIFOCalc.On("CalculateCostBasis", "ETH", disposal, previousTransactions).Return(62000.0, nil)
+	mockRealizedProfitRepo.On("CreateTx", mock.AnythingOfType("*sql.Tx"), mock.MatchedBy(func(in *models.CreateRealizedProfitInput) bool {
+		return in.Symbol == "ETH" && in.SellAmount == 3000 && in.SellFee == 3 && in.CostBasis == 62000
+	})).Return(&models.RealizedProfit{}, nil)
+
+	// Act
+	result, err := service.CreateSwap(input)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, disposalSwapID, result.SwapID)
+	assert.Equal(t, disposal.ID, result.Disposal.ID)
+	assert.Equal(t, acquisition.ID, result.Acquisition.ID)
+	mockRepo.AssertExpectations(t)
+	mockRealizedProfitRepo.AssertExpectations(t)
+	assert.NoError(t, dbMock.ExpectationsWereMet())
+}
+
+// TestCreateSwap_RequiresValueWithoutStablecoin 測試非穩定幣互換必須提供成交市值
+func TestCreateSwap_RequiresValueWithoutStablecoin(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockTransactionRepository)
+	service := NewTransactionService(mockRepo, nil, nil, nil, nil, nil, nil, nil)
+
+	// Act
+	result, err := service.CreateSwap(&models.CreateSwapInput{
+		Date:         time.Date(2025, 10, 24, 0, 0, 0, 0, time.UTC),
+		FromSymbol:   "ETH",
+		FromQuantity: 1,
+		ToSymbol:     "SOL",
+		ToQuantity:   20,
+		Currency:     models.CurrencyUSD,
+	})
+
+	// Assert
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "value is required")
+	mockRepo.AssertNotCalled(t, "DB")
+}
+
+// TestCreateSwap_ValidatesLegsAgainstInstruments 測試互換兩端與一般交易相同地經過商品主檔驗證，未知幣種不會開啟資料庫事務
+func TestCreateSwap_ValidatesLegsAgainstInstruments(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockTransactionRepository)
+	instrumentRepo := &fakeInstrumentRepository{instruments: []*models.Instrument{
+		{Symbol: "ETH", AssetType: models.AssetTypeCrypto, NameEn: "Ethereum"},
+	}}
+	service := NewTransactionService(mockRepo, nil, nil, nil, nil, nil, instrumentRepo, nil)
+
+	// Act
+	result, err := service.CreateSwap(&models.CreateSwapInput{
+		Date:         time.Date(2025, 10, 24, 0, 0, 0, 0, time.UTC),
+		FromSymbol:   "eth",
+		FromQuantity: 1,
+		ToSymbol:     "NOTACOIN",
+		ToName:       "Not A Coin",
+		ToQuantity:   3000,
+		Value:        ptrFloat64(3000),
+		Currency:     models.CurrencyUSD,
+	})
+
+	// Assert
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "invalid acquisition of NOTACOIN")
+	assert.Contains(t, err.Error(), "unknown crypto symbol")
+	mockRepo.AssertNotCalled(t, "DB")
+}
+
+// TestCreateTransaction_CoveredCallAssignment 測試賣出買權被指派：權利金實現為已實現損益，並以履約價賣出標的股票
+func TestCreateTransaction_CoveredCallAssignment(t *testing.T) {
+	// Arrange
+	db, dbMock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mockRepo := new(MockTransactionRepository)
+	mockRealizedProfitRepo := new(MockRealizedProfitRepository)
+	mockFIFOCalc := new(MockFIFOCalculator)
+	mockExchangeRateService := new(MockExchangeRateService)
+	mockOptionRepo := new(MockOptionContractRepository)
+	service := NewTransactionService(mockRepo, mockRealizedProfitRepo, mockFIFOCalc, mockExchangeRateService, nil, mockOptionRepo, nil, nil)
+
+	optionSymbol := "AAPL251219C00200000"
+	assignDate := time.Date(2025, 12, 19, 0, 0, 0, 0, time.UTC)
+	contract := &models.OptionContract{
+		Symbol:           optionSymbol,
+		UnderlyingSymbol: "AAPL",
+		OptionType:       models.OptionTypeCall,
+		StrikePrice:      200,
+		ExpiryDate:       assignDate,
+		Multiplier:       100,
+	}
+	input := &models.CreateTransactionInput{
+		Date:            assignDate,
+		AssetType:       models.AssetTypeUSOption,
+		Symbol:          optionSymbol,
+		Name:            "AAPL 200 Call",
+		TransactionType: models.TransactionTypeAssignment,
+		Quantity:        1,
+		Currency:        models.CurrencyUSD,
+	}
+	assignment := &models.Transaction{ID: uuid.New(), Date: assignDate, AssetType: models.AssetTypeUSOption, Symbol: optionSymbol, TransactionType: models.TransactionTypeAssignment, Quantity: 1, Currency: models.CurrencyUSD}
+	stockSale := &models.Transaction{ID: uuid.New(), Date: assignDate, AssetType: models.AssetTypeUSStock, Symbol: "AAPL", TransactionType: models.TransactionTypeSell, Quantity: 100, Price: 200, Amount: 20000, Currency: models.CurrencyUSD}
+	optionHistory := []*models.Transaction{{Symbol: optionSymbol, TransactionType: models.TransactionTypeShort}}
+	stockHistory := []*models.Transaction{{Symbol: "AAPL", TransactionType: models.TransactionTypeBuy}}
+
+	dbMock.ExpectBegin()
+	dbMock.ExpectCommit()
+	mockRepo.On("DB").Return(db)
+	mockOptionRepo.On("GetBySymbol", optionSymbol).Return(contract, nil)
+	mockExchangeRateService.On("GetRate", models.CurrencyUSD, models.CurrencyTWD, assignDate).Return(31.0, nil)
+	mockExchangeRateService.On("GetRateRecord", models.CurrencyUSD, models.CurrencyTWD, assignDate).Return(&models.ExchangeRate{ID: 7, Rate: 31.0}, nil)
+
+	// 選擇權部位以零價值了結，權利金所得全數實現
+	mockRepo.On("CreateWithExchangeRateTx", mock.AnythingOfType("*sql.Tx"), input, 7).Return(assignment, nil)
+	mockRepo.On("GetAll", repository.TransactionFilters{Symbol: &optionSymbol}).Return(optionHistory, nil)
+	mockFIFOCalc.On("CalculateClosingBasis", optionSymbol, assignment, optionHistory).Return(&ClosingBasis{IsShort: true, Basis: 15500}, nil)
+	mockRealizedProfitRepo.On("CreateTx", mock.AnythingOfType("*sql.Tx"), mock.MatchedBy(func(in *models.CreateRealizedProfitInput) bool {
+		return in.Symbol == optionSymbol && in.SellAmount == 15500 && in.CostBasis == 0 && in.Currency == "TWD"
+	})).Return(&models.RealizedProfit{}, nil)
+
+	// 以履約價賣出 100 股標的股票
+	mockRepo.On("CreateWithExchangeRateTx", mock.AnythingOfType("*sql.Tx"), mock.MatchedBy(func(in *models.CreateTransactionInput) bool {
+		return in.Symbol == "AAPL" && in.AssetType == models.AssetTypeUSStock && in.TransactionType == models.TransactionTypeSell &&
+			in.Quantity == 100 && in.Price == 200 && in.Amount == 20000
+	}), 7).Return(stockSale, nil)
+	stockSymbol := "AAPL"
+	mockRepo.On("GetAll", repository.TransactionFilters{Symbol: &stockSymbol}).Return(stockHistory, nil)
+	mockFIFOCalc.On("CalculateCostBasis", "AAPL", stockSale, stockHistory).Return(465000.0, nil)
+	mockRealizedProfitRepo.On("CreateTx", mock.AnythingOfType("*sql.Tx"), mock.MatchedBy(func(in *models.CreateRealizedProfitInput) bool {
+		return in.Symbol == "AAPL" && in.CostBasis == 465000
+	})).Return(&models.RealizedProfit{}, nil)
+
+	// Act
+	result, err := service.CreateTransaction(input)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, assignment.ID, result.ID)
+	mockRepo.AssertExpectations(t)
+	mockRealizedProfitRepo.AssertExpectations(t)
+	mockFIFOCalc.AssertExpectations(t)
+	assert.NoError(t, dbMock.ExpectationsWereMet())
+}
+
+// TestCreateTransaction_OptionEventRequiresOption 測試到期與履約事件僅適用於選擇權
+func TestCreateTransaction_OptionEventRequiresOption(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockTransactionRepository)
+	service := NewTransactionService(mockRepo, nil, nil, nil, nil, nil, nil, nil)
+
+	// Act
+	result, err := service.CreateTransaction(&models.CreateTransactionInput{
+		Date:            time.Date(2025, 12, 19, 0, 0, 0, 0, time.UTC),
+		AssetType:       models.AssetTypeUSStock,
+		Symbol:          "AAPL",
+		Name:            "Apple Inc.",
+		TransactionType: models.TransactionTypeExpiration,
+		Quantity:        1,
+		Currency:        models.CurrencyUSD,
+	})
+
+	// Assert
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "only allowed for options")
+	mockRepo.AssertNotCalled(t, "DB")
+}
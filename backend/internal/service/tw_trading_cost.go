@@ -0,0 +1,60 @@
+package service
+
+import (
+	"time"
+
+	"github.com/chienchuanw/asset-manager/internal/models"
+)
+
+const (
+	// twBrokerFeeRate 台股券商手續費法定上限（千分之 1.425）
+	twBrokerFeeRate = 0.001425
+
+	// twMinBrokerFee 台股券商最低手續費（新台幣 20 元）
+	twMinBrokerFee = 20
+
+	// twStockTaxRate 股票證券交易稅率（千分之 3，含興櫃）
+	twStockTaxRate = 0.003
+
+	// twExchangeTradedTaxRate ETF、ETN 證券交易稅率（千分之 1）
+	twExchangeTradedTaxRate = 0.001
+)
+
+// twBondETFTaxExemptUntil 債券 ETF 停徵證券交易稅的截止日（證券交易稅條例第 2 條之 2，停徵至 2026-12-31）
+var twBondETFTaxExemptUntil = time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC)
+
+// twTransactionTaxRate 取得台股賣出時的證券交易稅率
+func twTransactionTaxRate(category models.InstrumentCategory, date time.Time) float64 {
+	switch category {
+	case models.InstrumentCategoryBondETF:
+		if !date.After(twBondETFTaxExemptUntil) {
+			return 0
+		}
+		return twExchangeTradedTaxRate
+	case models.InstrumentCategoryETF, models.InstrumentCategoryLeveragedETF, models.InstrumentCategoryETN:
+		return twExchangeTradedTaxRate
+	}
+	return twStockTaxRate
+}
+
+// statutoryTWFeeProfile 台股法定費率（手續費千分之 1.425、最低 20 元、無折扣，交易稅依商品類別）
+func statutoryTWFeeProfile() *models.FeeProfile {
+	return &models.FeeProfile{
+		Name:         "法定費率",
+		AssetType:    models.AssetTypeTWStock,
+		FeeRate:      twBrokerFeeRate,
+		FeeDiscount:  1,
+		MinFee:       twMinBrokerFee,
+		SellTaxRates: map[models.InstrumentCategory]float64{},
+	}
+}
+
+// applyTWTradingCostDefaults 依商品主檔的類別以法定費率補上台股交易未填寫的手續費與證券交易稅
+// 僅適用於商品主檔中的台股且以新台幣計價；證券交易稅僅於賣出（含放空）時課徵
+func applyTWTradingCostDefaults(input *models.CreateTransactionInput, instrument *models.Instrument) {
+	if instrument == nil || input.AssetType != models.AssetTypeTWStock || input.Currency != models.CurrencyTWD {
+		return
+	}
+
+	applyFeeProfile(input, statutoryTWFeeProfile(), tradingCostCategory(input, instrument), false)
+}
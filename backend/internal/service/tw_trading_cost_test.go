@@ -0,0 +1,85 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chienchuanw/asset-manager/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTWTransactionTaxRate 測試各台股商品類別的證券交易稅率
+func TestTWTransactionTaxRate(t *testing.T) {
+	date := time.Date(2025, 10, 22, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, 0.003, twTransactionTaxRate(models.InstrumentCategoryStock, date))
+	assert.Equal(t, 0.001, twTransactionTaxRate(models.InstrumentCategoryETF, date))
+	assert.Equal(t, 0.001, twTransactionTaxRate(models.InstrumentCategoryLeveragedETF, date))
+	assert.Equal(t, 0.001, twTransactionTaxRate(models.InstrumentCategoryETN, date))
+	assert.Equal(t, 0.0, twTransactionTaxRate(models.InstrumentCategoryBondETF, date))
+
+	// 債券 ETF 停徵期滿後恢復課徵千分之 1
+	assert.Equal(t, 0.001, twTransactionTaxRate(models.InstrumentCategoryBondETF, time.Date(2027, 1, 4, 0, 0, 0, 0, time.UTC)))
+}
+
+// TestApplyTWTradingCostDefaults 測試依商品類別補上台股預設手續費與證券交易稅
+func TestApplyTWTradingCostDefaults(t *testing.T) {
+	newInput := func(transactionType models.TransactionType, amount float64) *models.CreateTransactionInput {
+		return &models.CreateTransactionInput{
+			Date:            time.Date(2025, 10, 22, 0, 0, 0, 0, time.UTC),
+			AssetType:       models.AssetTypeTWStock,
+			Symbol:          "2330",
+			TransactionType: transactionType,
+			Amount:          amount,
+			Currency:        models.CurrencyTWD,
+		}
+	}
+	stock := &models.Instrument{Symbol: "2330", AssetType: models.AssetTypeTWStock, Category: models.InstrumentCategoryStock}
+	etf := &models.Instrument{Symbol: "0050", AssetType: models.AssetTypeTWStock, Category: models.InstrumentCategoryETF}
+
+	t.Run("股票賣出課徵千分之 3", func(t *testing.T) {
+		input := newInput(models.TransactionTypeSell, 1000000)
+		applyTWTradingCostDefaults(input, stock)
+
+		require.NotNil(t, input.Fee)
+		require.NotNil(t, input.Tax)
+		assert.Equal(t, 1425.0, *input.Fee)
+		assert.Equal(t, 3000.0, *input.Tax)
+	})
+
+	t.Run("ETF 賣出課徵千分之 1", func(t *testing.T) {
+		input := newInput(models.TransactionTypeSell, 1000000)
+		applyTWTradingCostDefaults(input, etf)
+
+		require.NotNil(t, input.Tax)
+		assert.Equal(t, 1000.0, *input.Tax)
+	})
+
+	t.Run("買入不課稅且手續費最低 20 元", func(t *testing.T) {
+		input := newInput(models.TransactionTypeBuy, 5000)
+		applyTWTradingCostDefaults(input, stock)
+
+		require.NotNil(t, input.Fee)
+		assert.Equal(t, 20.0, *input.Fee)
+		assert.Nil(t, input.Tax)
+	})
+
+	t.Run("已填寫的手續費與交易稅不覆寫", func(t *testing.T) {
+		fee, tax := 600.0, 2999.0
+		input := newInput(models.TransactionTypeSell, 1000000)
+		input.Fee, input.Tax = &fee, &tax
+		applyTWTradingCostDefaults(input, stock)
+
+		assert.Equal(t, 600.0, *input.Fee)
+		assert.Equal(t, 2999.0, *input.Tax)
+	})
+
+	t.Run("不在商品主檔時不補值", func(t *testing.T) {
+		input := newInput(models.TransactionTypeSell, 1000000)
+		applyTWTradingCostDefaults(input, nil)
+
+		assert.Nil(t, input.Fee)
+		assert.Nil(t, input.Tax)
+	})
+}
@@ -0,0 +1,8 @@
+-- 刪除觸發器
+DROP TRIGGER IF EXISTS update_loans_updated_at ON loans;
+
+-- 刪除資料表
+DROP TABLE IF EXISTS loan_payments;
+DROP TABLE IF EXISTS loan_prepayments;
+DROP TABLE IF EXISTS loan_rate_changes;
+DROP TABLE IF EXISTS loans;
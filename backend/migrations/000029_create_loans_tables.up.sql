@@ -0,0 +1,86 @@
+-- 建立貸款表
+CREATE TABLE IF NOT EXISTS loans (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(255) NOT NULL,
+    loan_type VARCHAR(20) NOT NULL DEFAULT 'personal' CHECK (loan_type IN ('mortgage', 'car', 'personal', 'other')),
+    principal DECIMAL(20, 2) NOT NULL CHECK (principal > 0),
+    currency VARCHAR(3) NOT NULL DEFAULT 'TWD' CHECK (currency = 'TWD'),
+    annual_rate DECIMAL(7, 4) NOT NULL DEFAULT 0 CHECK (annual_rate >= 0),
+    term_months INT NOT NULL CHECK (term_months > 0),
+    payment_day INT NOT NULL CHECK (payment_day BETWEEN 1 AND 31),
+    paid_count INT NOT NULL DEFAULT 0 CHECK (paid_count >= 0),
+    start_date DATE NOT NULL,
+    category_id UUID NOT NULL REFERENCES cash_flow_categories(id) ON DELETE RESTRICT,
+    interest_category_id UUID REFERENCES cash_flow_categories(id) ON DELETE RESTRICT,
+    payment_method VARCHAR(20) NOT NULL DEFAULT 'bank_account'
+        CHECK (payment_method IN ('cash', 'bank_account', 'credit_card')),
+    account_id UUID,
+    status VARCHAR(20) NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'paid_off', 'cancelled')),
+    note TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT check_loan_paid_count_not_exceed_term CHECK (paid_count <= term_months)
+);
+
+-- 建立利率調整表（浮動利率）
+CREATE TABLE IF NOT EXISTS loan_rate_changes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    loan_id UUID NOT NULL REFERENCES loans(id) ON DELETE CASCADE,
+    effective_date DATE NOT NULL,
+    annual_rate DECIMAL(7, 4) NOT NULL CHECK (annual_rate >= 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(loan_id, effective_date)
+);
+
+-- 建立提前還款表
+CREATE TABLE IF NOT EXISTS loan_prepayments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    loan_id UUID NOT NULL REFERENCES loans(id) ON DELETE CASCADE,
+    date DATE NOT NULL,
+    amount DECIMAL(20, 2) NOT NULL CHECK (amount > 0),
+    note TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- 建立貸款還款紀錄表（由扣款排程產生）
+CREATE TABLE IF NOT EXISTS loan_payments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    loan_id UUID NOT NULL REFERENCES loans(id) ON DELETE CASCADE,
+    period INT NOT NULL CHECK (period > 0),
+    payment_date DATE NOT NULL,
+    principal_amount DECIMAL(20, 2) NOT NULL CHECK (principal_amount >= 0),
+    interest_amount DECIMAL(20, 2) NOT NULL CHECK (interest_amount >= 0),
+    principal_cash_flow_id UUID REFERENCES cash_flows(id) ON DELETE SET NULL,
+    interest_cash_flow_id UUID REFERENCES cash_flows(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(loan_id, period)
+);
+
+-- 建立索引以提升查詢效能
+CREATE INDEX idx_loans_status ON loans(status);
+CREATE INDEX idx_loans_payment_day ON loans(payment_day);
+CREATE INDEX idx_loan_rate_changes_loan_id ON loan_rate_changes(loan_id, effective_date);
+CREATE INDEX idx_loan_prepayments_loan_id ON loan_prepayments(loan_id, date);
+CREATE INDEX idx_loan_payments_loan_id ON loan_payments(loan_id, period);
+
+-- 建立更新時間的觸發器
+CREATE TRIGGER update_loans_updated_at
+    BEFORE UPDATE ON loans
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- 註解說明
+COMMENT ON TABLE loans IS '貸款表（房貸、車貸、信貸）';
+COMMENT ON COLUMN loans.loan_type IS '貸款類型（mortgage: 房貸, car: 車貸, personal: 信貸, other: 其他）';
+COMMENT ON COLUMN loans.principal IS '貸款本金';
+COMMENT ON COLUMN loans.annual_rate IS '初始年利率（%），之後的調整記錄在 loan_rate_changes';
+COMMENT ON COLUMN loans.term_months IS '貸款期數（月）';
+COMMENT ON COLUMN loans.payment_day IS '每月還款日（1-31）';
+COMMENT ON COLUMN loans.paid_count IS '已還期數';
+COMMENT ON COLUMN loans.start_date IS '撥款日期，第一期於次月還款日扣款';
+COMMENT ON COLUMN loans.category_id IS '本金還款的現金流分類 ID';
+COMMENT ON COLUMN loans.interest_category_id IS '利息的現金流分類 ID（未設定時使用 category_id）';
+COMMENT ON COLUMN loans.status IS '狀態（active: 還款中, paid_off: 已清償, cancelled: 已取消）';
+COMMENT ON TABLE loan_rate_changes IS '貸款利率調整記錄';
+COMMENT ON TABLE loan_prepayments IS '貸款提前還本記錄';
+COMMENT ON TABLE loan_payments IS '貸款每期還款記錄（本金與利息拆分）';
@@ -0,0 +1,13 @@
+-- 還原資產快照類型限制
+DELETE FROM asset_snapshots WHERE asset_type = 'manual';
+ALTER TABLE asset_snapshots DROP CONSTRAINT IF EXISTS asset_snapshots_asset_type_check;
+ALTER TABLE asset_snapshots ADD CONSTRAINT asset_snapshots_asset_type_check
+    CHECK (asset_type IN ('tw-stock', 'us-stock', 'crypto', 'total'));
+COMMENT ON COLUMN asset_snapshots.asset_type IS '資產類型 (tw-stock, us-stock, crypto, total)';
+
+-- 刪除觸發器
+DROP TRIGGER IF EXISTS update_manual_assets_updated_at ON manual_assets;
+
+-- 刪除資料表
+DROP TABLE IF EXISTS manual_asset_valuations;
+DROP TABLE IF EXISTS manual_assets;
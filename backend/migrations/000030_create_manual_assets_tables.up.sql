@@ -0,0 +1,62 @@
+-- 建立手動估值資產表（不動產、車輛、保單價值準備金、未上市股權等）
+CREATE TABLE IF NOT EXISTS manual_assets (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(255) NOT NULL,
+    category VARCHAR(30) NOT NULL DEFAULT 'other'
+        CHECK (category IN ('real_estate', 'vehicle', 'insurance', 'private_equity', 'collectible', 'other')),
+    currency VARCHAR(3) NOT NULL DEFAULT 'TWD' CHECK (currency IN ('TWD', 'USD')),
+    acquisition_date DATE NOT NULL,
+    acquisition_cost DECIMAL(20, 2) NOT NULL CHECK (acquisition_cost >= 0),
+    schedule_type VARCHAR(20) NOT NULL DEFAULT 'none'
+        CHECK (schedule_type IN ('none', 'straight_line', 'compound')),
+    annual_rate DECIMAL(7, 4),
+    useful_life_months INT CHECK (useful_life_months IS NULL OR useful_life_months > 0),
+    salvage_value DECIMAL(20, 2) CHECK (salvage_value IS NULL OR salvage_value >= 0),
+    status VARCHAR(20) NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'disposed')),
+    note TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT check_manual_asset_straight_line
+        CHECK (schedule_type <> 'straight_line' OR useful_life_months IS NOT NULL),
+    CONSTRAINT check_manual_asset_compound
+        CHECK (schedule_type <> 'compound' OR annual_rate IS NOT NULL)
+);
+
+-- 建立手動估值記錄表
+CREATE TABLE IF NOT EXISTS manual_asset_valuations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    asset_id UUID NOT NULL REFERENCES manual_assets(id) ON DELETE CASCADE,
+    valuation_date DATE NOT NULL,
+    value DECIMAL(20, 2) NOT NULL CHECK (value >= 0),
+    note TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(asset_id, valuation_date)
+);
+
+-- 建立索引以提升查詢效能
+CREATE INDEX idx_manual_assets_status ON manual_assets(status);
+CREATE INDEX idx_manual_assets_category ON manual_assets(category);
+CREATE INDEX idx_manual_asset_valuations_asset_id ON manual_asset_valuations(asset_id, valuation_date);
+
+-- 建立更新時間的觸發器
+CREATE TRIGGER update_manual_assets_updated_at
+    BEFORE UPDATE ON manual_assets
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- 資產快照新增手動估值資產類型
+ALTER TABLE asset_snapshots DROP CONSTRAINT IF EXISTS asset_snapshots_asset_type_check;
+ALTER TABLE asset_snapshots ADD CONSTRAINT asset_snapshots_asset_type_check
+    CHECK (asset_type IN ('tw-stock', 'us-stock', 'crypto', 'manual', 'total'));
+COMMENT ON COLUMN asset_snapshots.asset_type IS '資產類型 (tw-stock, us-stock, crypto, manual, total)';
+
+-- 註解說明
+COMMENT ON TABLE manual_assets IS '手動估值資產表（無市場報價的資產）';
+COMMENT ON COLUMN manual_assets.category IS '資產分類（real_estate: 不動產, vehicle: 車輛, insurance: 保單價值, private_equity: 未上市股權, collectible: 收藏品, other: 其他）';
+COMMENT ON COLUMN manual_assets.acquisition_cost IS '取得成本（原幣），無估值記錄時作為基準價值';
+COMMENT ON COLUMN manual_assets.schedule_type IS '估值調整方式（none: 僅使用手動估值, straight_line: 直線折舊, compound: 年複合增減值）';
+COMMENT ON COLUMN manual_assets.annual_rate IS '年增減值率（%），負值代表折舊，compound 使用';
+COMMENT ON COLUMN manual_assets.useful_life_months IS '耐用月數（自取得日起），straight_line 使用';
+COMMENT ON COLUMN manual_assets.salvage_value IS '殘值，straight_line 使用（預設 0）';
+COMMENT ON COLUMN manual_assets.status IS '狀態（active: 持有中, disposed: 已處分）';
+COMMENT ON TABLE manual_asset_valuations IS '手動估值記錄，最新一筆估值為後續折舊/增值的基準';
@@ -0,0 +1,7 @@
+-- 刪除資料表
+DROP TABLE IF EXISTS bank_account_reconciliations;
+
+-- 移除對帳調整分類（仍被使用時保留）
+DELETE FROM cash_flow_categories
+WHERE name = '帳戶調整' AND type IN ('income', 'expense')
+  AND NOT EXISTS (SELECT 1 FROM cash_flows WHERE cash_flows.category_id = cash_flow_categories.id);
@@ -0,0 +1,28 @@
+-- 建立銀行帳戶對帳記錄表
+CREATE TABLE IF NOT EXISTS bank_account_reconciliations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    bank_account_id UUID NOT NULL REFERENCES bank_accounts(id) ON DELETE CASCADE,
+    statement_date DATE NOT NULL,
+    statement_balance DECIMAL(20, 2) NOT NULL,
+    ledger_balance DECIMAL(20, 2) NOT NULL,
+    difference DECIMAL(20, 2) NOT NULL,
+    adjustment_cash_flow_id UUID REFERENCES cash_flows(id) ON DELETE SET NULL,
+    note TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- 建立索引以提升查詢效能
+CREATE INDEX idx_bank_account_reconciliations_account_id ON bank_account_reconciliations(bank_account_id, statement_date);
+
+-- 新增對帳調整用的系統分類
+INSERT INTO cash_flow_categories (name, type, is_system)
+VALUES ('帳戶調整', 'income', true), ('帳戶調整', 'expense', true)
+ON CONFLICT (name, type) DO NOTHING;
+
+-- 註解說明
+COMMENT ON TABLE bank_account_reconciliations IS '銀行帳戶對帳記錄（與對帳單餘額比對）';
+COMMENT ON COLUMN bank_account_reconciliations.statement_date IS '對帳單日期';
+COMMENT ON COLUMN bank_account_reconciliations.statement_balance IS '對帳單上的餘額';
+COMMENT ON COLUMN bank_account_reconciliations.ledger_balance IS '系統帳上於對帳單日期的餘額（調整前）';
+COMMENT ON COLUMN bank_account_reconciliations.difference IS '差額 = 對帳單餘額 - 帳上餘額';
+COMMENT ON COLUMN bank_account_reconciliations.adjustment_cash_flow_id IS '差額調整所建立的現金流記錄 ID（無差額時為空）';
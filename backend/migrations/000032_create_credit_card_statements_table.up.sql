@@ -0,0 +1,40 @@
+-- 建立信用卡帳單表（每個結帳週期一筆）
+CREATE TABLE IF NOT EXISTS credit_card_statements (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    credit_card_id UUID NOT NULL REFERENCES credit_cards(id) ON DELETE CASCADE,
+    period_start DATE NOT NULL,
+    closing_date DATE NOT NULL,
+    due_date DATE NOT NULL,
+    previous_balance DECIMAL(20, 2) NOT NULL DEFAULT 0,
+    new_charges DECIMAL(20, 2) NOT NULL DEFAULT 0,
+    payments_and_credits DECIMAL(20, 2) NOT NULL DEFAULT 0,
+    closing_balance DECIMAL(20, 2) NOT NULL DEFAULT 0,
+    minimum_payment DECIMAL(20, 2) NOT NULL DEFAULT 0,
+    paid_amount DECIMAL(20, 2) NOT NULL DEFAULT 0,
+    status VARCHAR(20) NOT NULL DEFAULT 'unpaid' CHECK (status IN ('unpaid', 'partially_paid', 'paid')),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(credit_card_id, closing_date)
+);
+
+-- 建立索引以提升查詢效能
+CREATE INDEX idx_credit_card_statements_card_id ON credit_card_statements(credit_card_id, closing_date DESC);
+CREATE INDEX idx_credit_card_statements_status ON credit_card_statements(status);
+
+-- 建立更新時間的觸發器
+CREATE TRIGGER update_credit_card_statements_updated_at
+    BEFORE UPDATE ON credit_card_statements
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- 註解說明
+COMMENT ON TABLE credit_card_statements IS '信用卡帳單（依帳單日切分的結帳週期）';
+COMMENT ON COLUMN credit_card_statements.period_start IS '週期起日（上期結帳日隔天）';
+COMMENT ON COLUMN credit_card_statements.closing_date IS '結帳日';
+COMMENT ON COLUMN credit_card_statements.due_date IS '繳款截止日';
+COMMENT ON COLUMN credit_card_statements.new_charges IS '本期新增消費';
+COMMENT ON COLUMN credit_card_statements.payments_and_credits IS '本期繳款與退款';
+COMMENT ON COLUMN credit_card_statements.closing_balance IS '本期應繳總額';
+COMMENT ON COLUMN credit_card_statements.minimum_payment IS '最低應繳金額';
+COMMENT ON COLUMN credit_card_statements.paid_amount IS '結帳日後至下次結帳日前的繳款金額';
+COMMENT ON COLUMN credit_card_statements.status IS '繳款狀態（unpaid: 未繳, partially_paid: 部分繳款, paid: 已繳清）';
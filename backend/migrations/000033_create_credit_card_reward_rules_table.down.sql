@@ -0,0 +1,5 @@
+-- 刪除觸發器
+DROP TRIGGER IF EXISTS update_credit_card_reward_rules_updated_at ON credit_card_reward_rules;
+
+-- 刪除資料表
+DROP TABLE IF EXISTS credit_card_reward_rules;
@@ -0,0 +1,30 @@
+-- 建立信用卡回饋規則表
+CREATE TABLE IF NOT EXISTS credit_card_reward_rules (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    credit_card_id UUID NOT NULL REFERENCES credit_cards(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    category_id UUID REFERENCES cash_flow_categories(id) ON DELETE CASCADE,
+    merchant_keyword VARCHAR(255),
+    rate DECIMAL(6, 3) NOT NULL CHECK (rate >= 0 AND rate <= 100),
+    cycle_cap DECIMAL(20, 2) CHECK (cycle_cap IS NULL OR cycle_cap > 0),
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- 建立索引以提升查詢效能
+CREATE INDEX idx_credit_card_reward_rules_card_id ON credit_card_reward_rules(credit_card_id);
+CREATE INDEX idx_credit_card_reward_rules_category_id ON credit_card_reward_rules(category_id);
+
+-- 建立更新時間的觸發器
+CREATE TRIGGER update_credit_card_reward_rules_updated_at
+    BEFORE UPDATE ON credit_card_reward_rules
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- 註解說明
+COMMENT ON TABLE credit_card_reward_rules IS '信用卡回饋規則（一般、指定分類與指定商家加碼）';
+COMMENT ON COLUMN credit_card_reward_rules.category_id IS '適用的支出分類（NULL 表示不限分類）';
+COMMENT ON COLUMN credit_card_reward_rules.merchant_keyword IS '加碼商家關鍵字（比對交易描述，NULL 表示不限商家）';
+COMMENT ON COLUMN credit_card_reward_rules.rate IS '回饋比例（%）';
+COMMENT ON COLUMN credit_card_reward_rules.cycle_cap IS '每期回饋上限（NULL 表示無上限）';
@@ -0,0 +1,6 @@
+-- 刪除觸發器
+DROP TRIGGER IF EXISTS update_billing_runs_updated_at ON billing_runs;
+
+-- 刪除資料表
+DROP TABLE IF EXISTS billing_runs;
+DROP TABLE IF EXISTS billing_records;
@@ -0,0 +1,41 @@
+-- 建立扣款記錄表（每個訂閱、分期或貸款每期只會扣款一次）
+CREATE TABLE IF NOT EXISTS billing_records (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    source_type VARCHAR(20) NOT NULL CHECK (source_type IN ('subscription', 'installment', 'loan')),
+    source_id UUID NOT NULL,
+    period VARCHAR(7) NOT NULL,
+    billing_date DATE NOT NULL,
+    amount DECIMAL(20, 2) NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(source_type, source_id, period)
+);
+
+-- 建立每日扣款執行記錄表
+CREATE TABLE IF NOT EXISTS billing_runs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    billing_date DATE NOT NULL UNIQUE,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('success', 'failed')),
+    processed_count INTEGER NOT NULL DEFAULT 0,
+    skipped_count INTEGER NOT NULL DEFAULT 0,
+    failed_count INTEGER NOT NULL DEFAULT 0,
+    total_amount DECIMAL(20, 2) NOT NULL DEFAULT 0,
+    error_message TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- 建立索引以提升查詢效能
+CREATE INDEX idx_billing_records_source ON billing_records(source_type, source_id);
+CREATE INDEX idx_billing_runs_status_date ON billing_runs(status, billing_date DESC);
+
+-- 建立更新時間的觸發器
+CREATE TRIGGER update_billing_runs_updated_at
+    BEFORE UPDATE ON billing_runs
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- 註解說明
+COMMENT ON TABLE billing_records IS '扣款記錄（以來源與期別為唯一鍵，避免重複扣款）';
+COMMENT ON COLUMN billing_records.source_type IS '來源類型（subscription: 訂閱, installment: 分期, loan: 貸款）';
+COMMENT ON COLUMN billing_records.period IS '扣款期別（YYYY-MM）';
+COMMENT ON TABLE billing_runs IS '每日扣款執行記錄（用於補跑停機期間漏掉的扣款日）';
@@ -0,0 +1,14 @@
+-- 還原扣款記錄的來源類型與期別長度
+DELETE FROM billing_records WHERE source_type = 'recurring';
+ALTER TABLE billing_records DROP CONSTRAINT IF EXISTS billing_records_source_type_check;
+ALTER TABLE billing_records ADD CONSTRAINT billing_records_source_type_check
+    CHECK (source_type IN ('subscription', 'installment', 'loan'));
+ALTER TABLE billing_records ALTER COLUMN period TYPE VARCHAR(7);
+COMMENT ON COLUMN billing_records.period IS '扣款期別（YYYY-MM）';
+
+-- 刪除觸發器
+DROP TRIGGER IF EXISTS update_recurring_templates_updated_at ON recurring_templates;
+
+-- 刪除資料表
+DROP TABLE IF EXISTS recurring_template_amount_changes;
+DROP TABLE IF EXISTS recurring_templates;
@@ -0,0 +1,64 @@
+-- 建立定期收支範本表（薪資、租金收入、保險費、定期轉帳等週期性現金流）
+CREATE TABLE IF NOT EXISTS recurring_templates (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(255) NOT NULL,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('income', 'expense', 'transfer_in', 'transfer_out')),
+    category_id UUID NOT NULL REFERENCES cash_flow_categories(id) ON DELETE RESTRICT,
+    amount DECIMAL(20, 2) NOT NULL CHECK (amount > 0),
+    currency VARCHAR(3) NOT NULL DEFAULT 'TWD' CHECK (currency IN ('TWD', 'USD')),
+    payment_method VARCHAR(20) NOT NULL DEFAULT 'cash' CHECK (payment_method IN ('cash', 'bank_account', 'credit_card')),
+    account_id UUID,
+    target_type VARCHAR(20) CHECK (target_type IN ('bank_account', 'credit_card', 'cash')),
+    target_id UUID,
+    frequency VARCHAR(20) NOT NULL CHECK (frequency IN ('daily', 'weekly', 'monthly', 'yearly')),
+    interval_count INTEGER NOT NULL DEFAULT 1 CHECK (interval_count > 0),
+    by_weekdays INTEGER[] NOT NULL DEFAULT '{}',
+    by_month_day INTEGER CHECK (by_month_day = -1 OR (by_month_day >= 1 AND by_month_day <= 31)),
+    by_months INTEGER[] NOT NULL DEFAULT '{}',
+    business_day_adjustment VARCHAR(20) NOT NULL DEFAULT 'none' CHECK (business_day_adjustment IN ('none', 'previous', 'next')),
+    start_date DATE NOT NULL,
+    end_date DATE,
+    status VARCHAR(20) NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'paused', 'ended')),
+    note TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT check_recurring_template_dates CHECK (end_date IS NULL OR end_date >= start_date)
+);
+
+-- 建立定期收支金額調整表（例如調薪、租金調漲）
+CREATE TABLE IF NOT EXISTS recurring_template_amount_changes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    template_id UUID NOT NULL REFERENCES recurring_templates(id) ON DELETE CASCADE,
+    effective_date DATE NOT NULL,
+    amount DECIMAL(20, 2) NOT NULL CHECK (amount > 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(template_id, effective_date)
+);
+
+-- 扣款記錄支援定期收支（以發生日 YYYY-MM-DD 為期別，同一範本同一天只會產生一次）
+ALTER TABLE billing_records DROP CONSTRAINT IF EXISTS billing_records_source_type_check;
+ALTER TABLE billing_records ADD CONSTRAINT billing_records_source_type_check
+    CHECK (source_type IN ('subscription', 'installment', 'loan', 'recurring'));
+ALTER TABLE billing_records ALTER COLUMN period TYPE VARCHAR(10);
+
+-- 建立索引以提升查詢效能
+CREATE INDEX idx_recurring_templates_status ON recurring_templates(status);
+CREATE INDEX idx_recurring_templates_category_id ON recurring_templates(category_id);
+CREATE INDEX idx_recurring_template_amount_changes_template_id ON recurring_template_amount_changes(template_id, effective_date);
+
+-- 建立更新時間的觸發器
+CREATE TRIGGER update_recurring_templates_updated_at
+    BEFORE UPDATE ON recurring_templates
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- 註解說明
+COMMENT ON TABLE recurring_templates IS '定期收支範本（由每日扣款排程依排程規則產生現金流）';
+COMMENT ON COLUMN recurring_templates.frequency IS '重複頻率（daily: 每日, weekly: 每週, monthly: 每月, yearly: 每年）';
+COMMENT ON COLUMN recurring_templates.interval_count IS '間隔（例如 frequency=weekly、interval_count=2 表示每兩週）';
+COMMENT ON COLUMN recurring_templates.by_weekdays IS '指定星期（0: 週日 ~ 6: 週六），空陣列表示依開始日';
+COMMENT ON COLUMN recurring_templates.by_month_day IS '指定每月日期（-1 表示月底），NULL 表示依開始日';
+COMMENT ON COLUMN recurring_templates.by_months IS '指定月份（1 ~ 12），空陣列表示不限';
+COMMENT ON COLUMN recurring_templates.business_day_adjustment IS '遇週末調整（none: 不調整, previous: 提前至前一個營業日, next: 延後至下一個營業日）';
+COMMENT ON TABLE recurring_template_amount_changes IS '定期收支金額調整記錄（自生效日起套用新金額）';
+COMMENT ON COLUMN billing_records.period IS '扣款期別（YYYY-MM；定期收支為 YYYY-MM-DD）';
@@ -0,0 +1,3 @@
+-- 刪除資料表
+DROP TABLE IF EXISTS subscription_usage_notes;
+DROP TABLE IF EXISTS subscription_price_changes;
@@ -0,0 +1,29 @@
+-- 建立訂閱價格調整表（保留歷史價格，扣款時依扣款日適用的價格計算）
+CREATE TABLE IF NOT EXISTS subscription_price_changes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    subscription_id UUID NOT NULL REFERENCES subscriptions(id) ON DELETE CASCADE,
+    effective_date DATE NOT NULL,
+    amount DECIMAL(20, 2) NOT NULL CHECK (amount > 0),
+    note TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(subscription_id, effective_date)
+);
+
+-- 建立訂閱使用記錄表（用於找出長期未使用的訂閱）
+CREATE TABLE IF NOT EXISTS subscription_usage_notes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    subscription_id UUID NOT NULL REFERENCES subscriptions(id) ON DELETE CASCADE,
+    used_on DATE NOT NULL,
+    note TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- 建立索引以提升查詢效能
+CREATE INDEX idx_subscription_price_changes_subscription_id ON subscription_price_changes(subscription_id, effective_date);
+CREATE INDEX idx_subscription_usage_notes_subscription_id ON subscription_usage_notes(subscription_id, used_on DESC);
+
+-- 註解說明
+COMMENT ON TABLE subscription_price_changes IS '訂閱價格調整記錄（自生效日起套用新價格）';
+COMMENT ON COLUMN subscription_price_changes.effective_date IS '生效日期';
+COMMENT ON TABLE subscription_usage_notes IS '訂閱使用記錄';
+COMMENT ON COLUMN subscription_usage_notes.used_on IS '使用日期';
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS installment_payments;
+DROP TABLE IF EXISTS installment_prepayments;
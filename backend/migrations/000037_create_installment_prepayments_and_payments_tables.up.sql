@@ -0,0 +1,35 @@
+-- 建立分期提前還款記錄表（部分提前還本或提前清償）
+CREATE TABLE IF NOT EXISTS installment_prepayments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    installment_id UUID NOT NULL REFERENCES installments(id) ON DELETE CASCADE,
+    date DATE NOT NULL,
+    amount DECIMAL(20, 2) NOT NULL CHECK (amount > 0),
+    after_period INT NOT NULL CHECK (after_period >= 0),
+    is_payoff BOOLEAN NOT NULL DEFAULT FALSE,
+    cash_flow_id UUID REFERENCES cash_flows(id) ON DELETE SET NULL,
+    note TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- 建立分期每期扣款記錄表（由扣款排程產生）
+CREATE TABLE IF NOT EXISTS installment_payments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    installment_id UUID NOT NULL REFERENCES installments(id) ON DELETE CASCADE,
+    period INT NOT NULL CHECK (period > 0),
+    payment_date DATE NOT NULL,
+    principal_amount DECIMAL(20, 2) NOT NULL CHECK (principal_amount >= 0),
+    interest_amount DECIMAL(20, 2) NOT NULL CHECK (interest_amount >= 0),
+    cash_flow_id UUID REFERENCES cash_flows(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(installment_id, period)
+);
+
+-- 建立索引以提升查詢效能
+CREATE INDEX idx_installment_prepayments_installment_id ON installment_prepayments(installment_id, after_period);
+CREATE INDEX idx_installment_payments_installment_id ON installment_payments(installment_id, period);
+
+-- 註解說明
+COMMENT ON TABLE installment_prepayments IS '分期提前還款記錄';
+COMMENT ON COLUMN installment_prepayments.after_period IS '記錄時的已付期數，提前還款於該期扣款後套用';
+COMMENT ON COLUMN installment_prepayments.is_payoff IS '是否為提前清償';
+COMMENT ON TABLE installment_payments IS '分期每期扣款記錄（本金與利息拆分）';
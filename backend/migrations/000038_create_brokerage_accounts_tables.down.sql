@@ -0,0 +1,7 @@
+-- 刪除觸發器
+DROP TRIGGER IF EXISTS update_brokerage_accounts_updated_at ON brokerage_accounts;
+
+-- 刪除資料表與欄位
+DROP TABLE IF EXISTS brokerage_cash_movements;
+ALTER TABLE transactions DROP COLUMN IF EXISTS brokerage_account_id;
+DROP TABLE IF EXISTS brokerage_accounts;
@@ -0,0 +1,48 @@
+-- 建立券商帳戶表
+CREATE TABLE IF NOT EXISTS brokerage_accounts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(255) NOT NULL,
+    broker VARCHAR(255) NOT NULL,
+    currency VARCHAR(3) NOT NULL DEFAULT 'TWD' CHECK (currency IN ('TWD', 'USD')),
+    cash_balance DECIMAL(20, 2) NOT NULL DEFAULT 0,
+    settlement_account_id UUID REFERENCES bank_accounts(id) ON DELETE SET NULL,
+    note TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- 交易記錄關聯券商帳戶
+ALTER TABLE transactions
+ADD COLUMN brokerage_account_id UUID REFERENCES brokerage_accounts(id) ON DELETE SET NULL;
+
+-- 建立券商交割現金異動表（由交易產生）
+CREATE TABLE IF NOT EXISTS brokerage_cash_movements (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    brokerage_account_id UUID NOT NULL REFERENCES brokerage_accounts(id) ON DELETE CASCADE,
+    transaction_id UUID NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('buy', 'sell', 'dividend', 'fee')),
+    amount DECIMAL(20, 2) NOT NULL,
+    trade_date DATE NOT NULL,
+    settlement_date DATE NOT NULL,
+    bank_account_id UUID REFERENCES bank_accounts(id) ON DELETE SET NULL,
+    cash_flow_id UUID REFERENCES cash_flows(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- 建立索引以提升查詢效能
+CREATE INDEX idx_transactions_brokerage_account_id ON transactions(brokerage_account_id);
+CREATE INDEX idx_brokerage_cash_movements_account_id ON brokerage_cash_movements(brokerage_account_id, settlement_date DESC);
+CREATE INDEX idx_brokerage_cash_movements_transaction_id ON brokerage_cash_movements(transaction_id);
+
+-- 建立更新時間的觸發器
+CREATE TRIGGER update_brokerage_accounts_updated_at
+    BEFORE UPDATE ON brokerage_accounts
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- 註解說明
+COMMENT ON TABLE brokerage_accounts IS '券商帳戶（持有證券與交割現金）';
+COMMENT ON COLUMN brokerage_accounts.cash_balance IS '券商端現金餘額（未設定交割帳戶時，交易現金由此收付）';
+COMMENT ON COLUMN brokerage_accounts.settlement_account_id IS '交割銀行帳戶';
+COMMENT ON TABLE brokerage_cash_movements IS '券商交割現金異動（正數為流入、負數為流出）';
+COMMENT ON COLUMN brokerage_cash_movements.settlement_date IS '交割日（台股 T+2、美股 T+1）';
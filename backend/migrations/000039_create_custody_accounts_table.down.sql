@@ -0,0 +1,15 @@
+-- 回滾：移除轉移、質押、空投交易後恢復原始的交易類型約束
+DELETE FROM transactions WHERE transaction_type IN ('transfer', 'staking', 'airdrop');
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_transaction_type_check;
+ALTER TABLE transactions ADD CONSTRAINT transactions_transaction_type_check
+    CHECK (transaction_type IN ('buy', 'sell', 'dividend', 'fee'));
+
+-- 刪除觸發器
+DROP TRIGGER IF EXISTS update_custody_accounts_updated_at ON custody_accounts;
+
+-- 刪除欄位與資料表
+ALTER TABLE transactions
+DROP COLUMN IF EXISTS network_fee,
+DROP COLUMN IF EXISTS to_custody_account_id,
+DROP COLUMN IF EXISTS custody_account_id;
+DROP TABLE IF EXISTS custody_accounts;
@@ -0,0 +1,37 @@
+-- 建立加密貨幣保管帳戶表（交易所、錢包、質押）
+CREATE TABLE IF NOT EXISTS custody_accounts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(255) NOT NULL,
+    custody_type VARCHAR(20) NOT NULL CHECK (custody_type IN ('exchange', 'wallet', 'staking')),
+    provider VARCHAR(255),
+    note TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- 交易記錄關聯保管帳戶；轉移交易另記錄轉入帳戶與鏈上網路手續費（以幣數計）
+ALTER TABLE transactions
+ADD COLUMN custody_account_id UUID REFERENCES custody_accounts(id) ON DELETE SET NULL,
+ADD COLUMN to_custody_account_id UUID REFERENCES custody_accounts(id) ON DELETE SET NULL,
+ADD COLUMN network_fee DECIMAL(20, 8) CHECK (network_fee >= 0);
+
+-- 新增內部轉移與質押、空投收入的交易類型
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_transaction_type_check;
+ALTER TABLE transactions ADD CONSTRAINT transactions_transaction_type_check
+    CHECK (transaction_type IN ('buy', 'sell', 'dividend', 'fee', 'transfer', 'staking', 'airdrop'));
+
+-- 建立索引以提升查詢效能
+CREATE INDEX idx_transactions_custody_account_id ON transactions(custody_account_id);
+CREATE INDEX idx_transactions_to_custody_account_id ON transactions(to_custody_account_id);
+
+-- 建立更新時間的觸發器
+CREATE TRIGGER update_custody_accounts_updated_at
+    BEFORE UPDATE ON custody_accounts
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- 註解說明
+COMMENT ON TABLE custody_accounts IS '加密貨幣保管帳戶（交易所、錢包、質押）';
+COMMENT ON COLUMN transactions.custody_account_id IS '保管帳戶（轉移交易為轉出帳戶）';
+COMMENT ON COLUMN transactions.to_custody_account_id IS '轉移交易的轉入帳戶';
+COMMENT ON COLUMN transactions.network_fee IS '轉移交易的鏈上網路手續費（以幣數計，自轉出數量扣除）';
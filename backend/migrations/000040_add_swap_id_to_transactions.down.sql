@@ -0,0 +1,3 @@
+-- 刪除索引與欄位
+DROP INDEX IF EXISTS idx_transactions_swap_id;
+ALTER TABLE transactions DROP COLUMN IF EXISTS swap_id;
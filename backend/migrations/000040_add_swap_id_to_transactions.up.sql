@@ -0,0 +1,9 @@
+-- 新增 swap_id 欄位，關聯加密貨幣互換（swap）的處分與取得兩筆交易
+ALTER TABLE transactions
+ADD COLUMN swap_id UUID;
+
+-- 建立索引以提升查詢效能
+CREATE INDEX idx_transactions_swap_id ON transactions(swap_id) WHERE swap_id IS NOT NULL;
+
+-- 註解說明
+COMMENT ON COLUMN transactions.swap_id IS '加密貨幣互換識別碼（同一次互換的賣出與買入交易共用）';
@@ -0,0 +1,23 @@
+-- 回滾：移除放空、回補、選擇權相關交易後恢復原始約束
+DELETE FROM brokerage_cash_movements WHERE type IN ('short', 'cover');
+DELETE FROM realized_profits WHERE asset_type = 'us-option';
+DELETE FROM transactions WHERE transaction_type IN ('short', 'cover', 'expiration', 'assignment') OR asset_type = 'us-option';
+
+ALTER TABLE brokerage_cash_movements DROP CONSTRAINT IF EXISTS brokerage_cash_movements_type_check;
+ALTER TABLE brokerage_cash_movements ADD CONSTRAINT brokerage_cash_movements_type_check
+    CHECK (type IN ('buy', 'sell', 'dividend', 'fee'));
+
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_transaction_type_check;
+ALTER TABLE transactions ADD CONSTRAINT transactions_transaction_type_check
+    CHECK (transaction_type IN ('buy', 'sell', 'dividend', 'fee', 'transfer', 'staking', 'airdrop'));
+
+ALTER TABLE realized_profits DROP CONSTRAINT IF EXISTS realized_profits_asset_type_check;
+ALTER TABLE realized_profits ADD CONSTRAINT realized_profits_asset_type_check
+    CHECK (asset_type IN ('cash', 'tw-stock', 'us-stock', 'crypto'));
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_asset_type_check;
+ALTER TABLE transactions ADD CONSTRAINT transactions_asset_type_check
+    CHECK (asset_type IN ('cash', 'tw-stock', 'us-stock', 'crypto'));
+
+-- 刪除觸發器與資料表
+DROP TRIGGER IF EXISTS update_option_contracts_updated_at ON option_contracts;
+DROP TABLE IF EXISTS option_contracts;
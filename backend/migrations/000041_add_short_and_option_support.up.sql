@@ -0,0 +1,45 @@
+-- 建立選擇權合約表（以合約代碼對應交易記錄的 symbol）
+CREATE TABLE IF NOT EXISTS option_contracts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    symbol VARCHAR(50) NOT NULL UNIQUE,
+    underlying_symbol VARCHAR(20) NOT NULL,
+    option_type VARCHAR(4) NOT NULL CHECK (option_type IN ('call', 'put')),
+    strike_price DECIMAL(20, 4) NOT NULL CHECK (strike_price > 0),
+    expiry_date DATE NOT NULL,
+    multiplier DECIMAL(10, 2) NOT NULL DEFAULT 100 CHECK (multiplier > 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- 新增美股選擇權資產類型
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_asset_type_check;
+ALTER TABLE transactions ADD CONSTRAINT transactions_asset_type_check
+    CHECK (asset_type IN ('cash', 'tw-stock', 'us-stock', 'crypto', 'us-option'));
+ALTER TABLE realized_profits DROP CONSTRAINT IF EXISTS realized_profits_asset_type_check;
+ALTER TABLE realized_profits ADD CONSTRAINT realized_profits_asset_type_check
+    CHECK (asset_type IN ('cash', 'tw-stock', 'us-stock', 'crypto', 'us-option'));
+
+-- 新增放空、回補與選擇權到期、履約的交易類型
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_transaction_type_check;
+ALTER TABLE transactions ADD CONSTRAINT transactions_transaction_type_check
+    CHECK (transaction_type IN ('buy', 'sell', 'dividend', 'fee', 'transfer', 'staking', 'airdrop',
+                                'short', 'cover', 'expiration', 'assignment'));
+
+-- 放空與回補會產生交割現金異動
+ALTER TABLE brokerage_cash_movements DROP CONSTRAINT IF EXISTS brokerage_cash_movements_type_check;
+ALTER TABLE brokerage_cash_movements ADD CONSTRAINT brokerage_cash_movements_type_check
+    CHECK (type IN ('buy', 'sell', 'dividend', 'fee', 'short', 'cover'));
+
+-- 建立索引以提升查詢效能
+CREATE INDEX idx_option_contracts_underlying_symbol ON option_contracts(underlying_symbol);
+
+-- 建立更新時間的觸發器
+CREATE TRIGGER update_option_contracts_updated_at
+    BEFORE UPDATE ON option_contracts
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- 註解說明
+COMMENT ON TABLE option_contracts IS '選擇權合約規格（履約價、到期日、乘數）';
+COMMENT ON COLUMN option_contracts.symbol IS '合約代碼，對應交易記錄的 symbol';
+COMMENT ON COLUMN option_contracts.multiplier IS '每口合約代表的標的股數';
@@ -0,0 +1,2 @@
+-- 刪除最後已知價格表
+DROP TABLE IF EXISTS last_known_prices;
@@ -0,0 +1,11 @@
+-- 建立最後已知價格表（每次成功取得價格時寫入，所有價格來源皆失敗時作為備援）
+CREATE TABLE IF NOT EXISTS last_known_prices (
+    symbol VARCHAR(50) NOT NULL,
+    asset_type VARCHAR(20) NOT NULL,
+    price DECIMAL(20, 8) NOT NULL CHECK (price > 0),
+    currency VARCHAR(3) NOT NULL,
+    source VARCHAR(50) NOT NULL,
+    fetched_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (symbol, asset_type)
+);
@@ -0,0 +1,2 @@
+-- 刪除商品主檔
+DROP TABLE IF EXISTS instruments;
@@ -0,0 +1,29 @@
+-- 建立商品主檔（由證交所、櫃買中心、美股代碼清單與 CoinGecko 幣種清單匯入）
+CREATE TABLE IF NOT EXISTS instruments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    symbol VARCHAR(50) NOT NULL,
+    exchange VARCHAR(20) NOT NULL,
+    name_zh_tw VARCHAR(255) NOT NULL DEFAULT '',
+    name_en VARCHAR(255) NOT NULL DEFAULT '',
+    currency VARCHAR(3) NOT NULL,
+    asset_type VARCHAR(20) NOT NULL CHECK (asset_type IN ('tw-stock', 'us-stock', 'crypto')),
+    lot_size DECIMAL(20, 8) NOT NULL DEFAULT 1 CHECK (lot_size > 0),
+    provider_ids JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (asset_type, symbol)
+);
+
+-- 建立索引以提升查詢效能
+CREATE INDEX idx_instruments_upper_symbol ON instruments(asset_type, UPPER(symbol));
+
+-- 建立更新時間的觸發器
+CREATE TRIGGER update_instruments_updated_at
+    BEFORE UPDATE ON instruments
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- 註解說明
+COMMENT ON TABLE instruments IS '商品主檔（代碼搜尋與交易標的驗證）';
+COMMENT ON COLUMN instruments.lot_size IS '每交易單位數量（台股一張 1000 股）';
+COMMENT ON COLUMN instruments.provider_ids IS '各價格來源的代碼，例如 {"coingecko": "bitcoin"}';
@@ -0,0 +1,2 @@
+-- 移除台股商品類別
+ALTER TABLE instruments DROP COLUMN IF EXISTS category;
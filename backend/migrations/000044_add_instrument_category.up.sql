@@ -0,0 +1,18 @@
+-- 新增台股商品類別（股票、ETF、債券 ETF、槓桿／反向 ETF、ETN），用於計算預設證券交易稅
+ALTER TABLE instruments
+    ADD COLUMN category VARCHAR(20) NOT NULL DEFAULT ''
+    CHECK (category IN ('', 'stock', 'etf', 'bond_etf', 'leveraged_etf', 'etn'));
+
+-- 依代號編碼規則回填已匯入的台股商品
+UPDATE instruments SET category = CASE
+    WHEN symbol LIKE '00%' AND LENGTH(symbol) >= 4 AND UPPER(symbol) LIKE '%B' THEN 'bond_etf'
+    WHEN symbol LIKE '00%' AND LENGTH(symbol) >= 4 AND (UPPER(symbol) LIKE '%L' OR UPPER(symbol) LIKE '%R') THEN 'leveraged_etf'
+    WHEN symbol LIKE '00%' AND LENGTH(symbol) >= 4 THEN 'etf'
+    WHEN symbol LIKE '02%' AND LENGTH(symbol) = 6 THEN 'etn'
+    ELSE 'stock'
+END
+WHERE asset_type = 'tw-stock';
+
+-- 註解說明
+COMMENT ON COLUMN instruments.exchange IS '交易所（台股為 TWSE 上市、TPEx 上櫃、ESB 興櫃）';
+COMMENT ON COLUMN instruments.category IS '台股商品類別：stock, etf, bond_etf, leveraged_etf, etn（其他資產類型為空字串）';
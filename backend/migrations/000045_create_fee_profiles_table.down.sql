@@ -0,0 +1,2 @@
+-- 刪除券商手續費與交易稅設定表
+DROP TABLE IF EXISTS fee_profiles;
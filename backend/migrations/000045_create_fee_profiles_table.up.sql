@@ -0,0 +1,34 @@
+-- 建立券商手續費與交易稅設定表
+CREATE TABLE IF NOT EXISTS fee_profiles (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(255) NOT NULL,
+    asset_type VARCHAR(20) NOT NULL CHECK (asset_type IN ('tw-stock', 'us-stock')),
+    brokerage_account_id UUID REFERENCES brokerage_accounts(id) ON DELETE CASCADE,
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    fee_rate DECIMAL(10, 8) NOT NULL DEFAULT 0 CHECK (fee_rate >= 0),
+    fee_discount DECIMAL(6, 4) NOT NULL DEFAULT 1 CHECK (fee_discount > 0 AND fee_discount <= 1),
+    per_share_fee DECIMAL(20, 8) NOT NULL DEFAULT 0 CHECK (per_share_fee >= 0),
+    min_fee DECIMAL(20, 8) NOT NULL DEFAULT 0 CHECK (min_fee >= 0),
+    max_fee DECIMAL(20, 8) CHECK (max_fee >= 0),
+    sell_tax_rates JSONB NOT NULL DEFAULT '{}',
+    note TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- 每個資產類型只能有一個預設設定，每個券商帳戶的每個資產類型只能有一個設定
+CREATE UNIQUE INDEX idx_fee_profiles_default ON fee_profiles(asset_type) WHERE is_default;
+CREATE UNIQUE INDEX idx_fee_profiles_brokerage_account ON fee_profiles(brokerage_account_id, asset_type) WHERE brokerage_account_id IS NOT NULL;
+
+-- 建立更新時間的觸發器
+CREATE TRIGGER update_fee_profiles_updated_at
+    BEFORE UPDATE ON fee_profiles
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- 註解說明
+COMMENT ON TABLE fee_profiles IS '券商手續費與交易稅設定（建立交易時自動計算未填寫的手續費與交易稅）';
+COMMENT ON COLUMN fee_profiles.brokerage_account_id IS '僅套用於指定券商帳戶（NULL 為通用設定）';
+COMMENT ON COLUMN fee_profiles.is_default IS '未指定券商帳戶或帳戶無設定時使用的預設設定';
+COMMENT ON COLUMN fee_profiles.fee_discount IS '手續費折扣（0.28 表示 2.8 折）';
+COMMENT ON COLUMN fee_profiles.sell_tax_rates IS '賣出時各商品類別的交易稅（規費）率，例如 {"stock": 0.003, "etf": 0.001}';
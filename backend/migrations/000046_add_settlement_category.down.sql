@@ -0,0 +1,4 @@
+-- 移除證券交割分類（仍被使用時保留）
+DELETE FROM cash_flow_categories
+WHERE name = '證券交割' AND type IN ('transfer_in', 'transfer_out')
+  AND NOT EXISTS (SELECT 1 FROM cash_flows WHERE cash_flows.category_id = cash_flow_categories.id);
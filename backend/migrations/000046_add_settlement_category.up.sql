@@ -0,0 +1,4 @@
+-- 新增券商交割現金流使用的系統分類
+INSERT INTO cash_flow_categories (name, type, is_system)
+VALUES ('證券交割', 'transfer_in', true), ('證券交割', 'transfer_out', true)
+ON CONFLICT (name, type) DO NOTHING;